@@ -2,12 +2,14 @@ package cmd
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	piperhttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/piperutils"
 	"io"
 	netHttp "net/http"
 	"os"
+	"path/filepath"
 	"strings"
 	"text/template"
 	"time"
@@ -23,6 +25,7 @@ import (
 	gitConfig "github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
 	"github.com/go-git/go-git/v5/plumbing/transport/http"
 	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
 )
@@ -32,9 +35,11 @@ type gitRepository interface {
 	CreateTag(string, plumbing.Hash, *git.CreateTagOptions) (*plumbing.Reference, error)
 	CreateRemote(*gitConfig.RemoteConfig) (*git.Remote, error)
 	DeleteRemote(string) error
+	Log(*git.LogOptions) (object.CommitIter, error)
 	Push(*git.PushOptions) error
 	Remote(string) (*git.Remote, error)
 	ResolveRevision(plumbing.Revision) (*plumbing.Hash, error)
+	Tags() (storer.ReferenceIter, error)
 	Worktree() (*git.Worktree, error)
 }
 
@@ -56,6 +61,7 @@ type artifactPrepareVersionUtils interface {
 
 	Glob(pattern string) (matches []string, err error)
 	FileExists(filename string) (bool, error)
+	FileRead(path string) ([]byte, error)
 	Copy(src, dest string) (int64, error)
 	MkdirAll(path string, perm os.FileMode) error
 }
@@ -103,13 +109,15 @@ func runArtifactPrepareVersion(config *artifactPrepareVersionOptions, telemetryD
 
 	// Options for artifact
 	artifactOpts := versioning.Options{
-		GlobalSettingsFile:  config.GlobalSettingsFile,
-		M2Path:              config.M2Path,
-		ProjectSettingsFile: config.ProjectSettingsFile,
-		VersionField:        config.CustomVersionField,
-		VersionSection:      config.CustomVersionSection,
-		VersioningScheme:    config.CustomVersioningScheme,
-		VersionSource:       config.DockerVersionSource,
+		GlobalSettingsFile:   config.GlobalSettingsFile,
+		M2Path:               config.M2Path,
+		ProjectSettingsFile:  config.ProjectSettingsFile,
+		VersionField:         config.CustomVersionField,
+		VersionSection:       config.CustomVersionSection,
+		VersioningScheme:     config.CustomVersioningScheme,
+		VersionSource:        config.DockerVersionSource,
+		HelmUpdateAppVersion: config.HelmUpdateAppVersion,
+		KustomizeImageName:   config.KustomizeImageName,
 	}
 
 	var err error
@@ -128,12 +136,34 @@ func runArtifactPrepareVersion(config *artifactPrepareVersionOptions, telemetryD
 		versioningType, _, config.IncludeCommitID = templateCompatibility(config.VersioningTemplate)
 	}
 
+	artifacts := []*versionedArtifact{{artifact: artifact, buildTool: config.BuildTool, filePath: config.FilePath, versioningType: versioningType}}
+	additionalArtifacts, err := getAdditionalArtifacts(config, versioningType, artifactOpts, utils)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return err
+	}
+	artifacts = append(artifacts, additionalArtifacts...)
+
 	version, err := artifact.GetVersion()
 	if err != nil {
 		log.SetErrorCategory(log.ErrorConfiguration)
 		return errors.Wrap(err, "failed to retrieve version")
 	}
 	log.Entry().Infof("Version before automatic versioning: %v", version)
+	artifacts[0].version = version
+	for _, a := range artifacts[1:] {
+		a.version, err = a.artifact.GetVersion()
+		if err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return errors.Wrapf(err, "failed to retrieve version of '%v'", a.filePath)
+		}
+		log.Entry().Infof("Version of '%v' before automatic versioning: %v", a.filePath, a.version)
+	}
+
+	signer, err := getVersionSigner(config, utils)
+	if err != nil {
+		return err
+	}
 
 	gitCommit, gitCommitMessage, err := getGitCommitID(repository)
 	if err != nil {
@@ -142,66 +172,312 @@ func runArtifactPrepareVersion(config *artifactPrepareVersionOptions, telemetryD
 	}
 	gitCommitID := gitCommit.String()
 
-	newVersion := version
+	now := time.Now()
+	versionChanged := false
+	for _, a := range artifacts {
+		a.newVersion, err = calculateArtifactVersion(config, a, gitCommitID, repository, now)
+		if err != nil {
+			return err
+		}
+		versionChanged = versionChanged || a.newVersion != a.version
+	}
+	newVersion := artifacts[0].newVersion
 
-	if versioningType == "cloud" || versioningType == "cloud_noTag" {
-		versioningTempl, err := versioningTemplate(artifact.VersioningScheme())
+	// the changed versions of all artifacts are written, whether they are committed is defined by the versioningType of the step
+	if versioningType == "cloud" || versioningType == "cloud_noTag" || versionChanged {
+		appVersion := ""
+		if config.HelmUpdateAppVersion {
+			appVersion = newVersion
+		}
+		worktree, err := updateVersions(artifacts, appVersion, repository, getWorktree, gitCommit)
 		if err != nil {
-			log.SetErrorCategory(log.ErrorConfiguration)
-			return errors.Wrapf(err, "failed to get versioning template for scheme '%v'", artifact.VersioningScheme())
+			return err
 		}
 
-		now := time.Now()
+		//ToDo: what about closure in current Groovy step. Discard the possibility or provide extension mechanism?
+
+		// a semantic version is only released in case the version of the artifact defined via buildTool changed
+		semanticRelease := versioningType == "semantic" && newVersion != version
+		if versioningType == "cloud" || (semanticRelease && config.Push) {
+			// commit changes and push to repository (including new version tag)
+			gitCommitID, err = pushChanges(config, newVersion, repository, worktree, now, signer)
+			if err != nil {
+				return errors.Wrapf(err, "failed to push changes for version '%v'", newVersion)
+			}
+		} else if semanticRelease {
+			commit, _, err := commitAndTag(config, newVersion, repository, worktree, now, signer)
+			gitCommitID = commit.String()
+			if err != nil {
+				return errors.Wrapf(err, "failed to tag version '%v'", newVersion)
+			}
+		}
+	}
 
-		newVersion, err = calculateNewVersion(versioningTempl, version, gitCommitID, config.IncludeCommitID, config.ShortCommitID, config.UnixTimestamp, now)
+	log.Entry().Infof("New version: '%v'", newVersion)
+
+	commonPipelineEnvironment.git.commitID = gitCommitID
+	commonPipelineEnvironment.artifactVersion = newVersion
+	commonPipelineEnvironment.originalArtifactVersion = version
+	commonPipelineEnvironment.git.commitMessage = gitCommitMessage
+	commonPipelineEnvironment.custom.artifactVersions = []map[string]interface{}{}
+	for _, a := range artifacts {
+		commonPipelineEnvironment.custom.artifactVersions = append(commonPipelineEnvironment.custom.artifactVersions, map[string]interface{}{
+			"buildTool":       a.buildTool,
+			"filePath":        a.filePath,
+			"version":         a.newVersion,
+			"originalVersion": a.version,
+		})
+	}
+
+	return nil
+}
+
+// versionedArtifact describes one of the artifacts which are versioned together
+type versionedArtifact struct {
+	artifact       versioning.Artifact
+	buildTool      string
+	filePath       string
+	versioningType string
+	version        string
+	newVersion     string
+}
+
+// artifactSettings contains the settings of an artifact maintained via parameter artifacts
+type artifactSettings struct {
+	BuildTool              string `json:"buildTool"`
+	FilePath               string `json:"filePath"`
+	VersioningType         string `json:"versioningType"`
+	CustomVersionField     string `json:"customVersionField"`
+	CustomVersionSection   string `json:"customVersionSection"`
+	CustomVersioningScheme string `json:"customVersioningScheme"`
+	DockerVersionSource    string `json:"dockerVersionSource"`
+	KustomizeImageName     string `json:"kustomizeImageName"`
+}
+
+var getVersioningArtifact = versioning.GetArtifact
+
+// getAdditionalArtifacts returns the artifacts which are versioned besides the one defined via buildTool and filePath.
+// They are either maintained explicitly or discovered in the subdirectories of the project.
+// Artifacts without a versioningType of their own follow the versioningType of the step.
+func getAdditionalArtifacts(config *artifactPrepareVersionOptions, versioningType string, artifactOpts versioning.Options, utils artifactPrepareVersionUtils) ([]*versionedArtifact, error) {
+	settings := []artifactSettings{}
+	if len(config.Artifacts) > 0 {
+		content, err := json.Marshal(config.Artifacts)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read artifacts")
+		}
+		if err := json.Unmarshal(content, &settings); err != nil {
+			return nil, errors.Wrap(err, "failed to read artifacts")
+		}
+	} else if config.DiscoverArtifacts {
+		descriptors, err := versioning.DiscoverDescriptors(utils.Glob)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to discover artifacts")
+		}
+		for _, descriptor := range descriptors {
+			if descriptor.BuildTool == config.BuildTool && (filepath.Clean(descriptor.FilePath) == filepath.Clean(config.FilePath) || len(config.FilePath) == 0 && !strings.Contains(descriptor.FilePath, "/")) {
+				// artifact defined via buildTool and filePath
+				continue
+			}
+			log.Entry().Infof("Discovered artifact '%v' (%v)", descriptor.FilePath, descriptor.BuildTool)
+			settings = append(settings, artifactSettings{BuildTool: descriptor.BuildTool, FilePath: descriptor.FilePath})
+		}
+	}
+
+	artifacts := []*versionedArtifact{}
+	for _, s := range settings {
+		if len(s.BuildTool) == 0 {
+			return nil, fmt.Errorf("buildTool missing for artifact '%v'", s.FilePath)
+		}
+		opts := artifactOpts
+		opts.VersionField = s.CustomVersionField
+		opts.VersionSection = s.CustomVersionSection
+		opts.VersioningScheme = s.CustomVersioningScheme
+		opts.VersionSource = s.DockerVersionSource
+		opts.KustomizeImageName = s.KustomizeImageName
+		// the appVersion of additional charts follows the version of the artifact defined via buildTool
+		opts.HelmUpdateAppVersion = false
+		artifact, err := getVersioningArtifact(s.BuildTool, s.FilePath, &opts, utils)
 		if err != nil {
-			return errors.Wrap(err, "failed to calculate new version")
+			return nil, errors.Wrapf(err, "failed to retrieve artifact '%v'", s.FilePath)
+		}
+		artifactVersioningType := s.VersioningType
+		if len(artifactVersioningType) == 0 {
+			artifactVersioningType = versioningType
 		}
+		artifacts = append(artifacts, &versionedArtifact{artifact: artifact, buildTool: s.BuildTool, filePath: s.FilePath, versioningType: artifactVersioningType})
+	}
+	return artifacts, nil
+}
 
-		worktree, err := getWorktree(repository)
+// calculateArtifactVersion calculates the new version of an artifact according to its versioning type
+func calculateArtifactVersion(config *artifactPrepareVersionOptions, a *versionedArtifact, gitCommitID string, repository gitRepository, now time.Time) (string, error) {
+	switch a.versioningType {
+	case "cloud", "cloud_noTag":
+		versioningTempl, err := versioningTemplate(a.artifact.VersioningScheme())
 		if err != nil {
 			log.SetErrorCategory(log.ErrorConfiguration)
-			return errors.Wrap(err, "failed to retrieve git worktree")
+			return "", errors.Wrapf(err, "failed to get versioning template for scheme '%v'", a.artifact.VersioningScheme())
 		}
 
-		// opening repository does not seem to consider already existing files properly
-		// behavior in case we do not run initializeWorktree:
-		//   git.Add(".") will add the complete workspace instead of only changed files
-		err = initializeWorktree(gitCommit, worktree)
+		newVersion, err := calculateNewVersion(versioningTempl, a.version, gitCommitID, config.IncludeCommitID, config.ShortCommitID, config.UnixTimestamp, now)
 		if err != nil {
-			return err
+			return "", errors.Wrap(err, "failed to calculate new version")
 		}
+		return newVersion, nil
+	case "semantic":
+		newVersion, err := calculateSemanticVersion(a.version, config.TagPrefix, repository)
+		if err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return "", errors.Wrap(err, "failed to calculate semantic version")
+		}
+		return newVersion, nil
+	}
+	return a.version, nil
+}
 
+// appVersionArtifact is implemented by artifacts which contain the version of another artifact (e.g. the appVersion of a Helm chart)
+type appVersionArtifact interface {
+	GetAppVersion() (string, error)
+	SetAppVersion(string) error
+}
+
+// updateVersions prepares the worktree for committing the new versions and writes the new versions into the build descriptors.
+// In case an appVersion is provided, it is written into the additional artifacts supporting it.
+func updateVersions(artifacts []*versionedArtifact, appVersion string, repository gitRepository, getWorktree func(gitRepository) (gitWorktree, error), gitCommit plumbing.Hash) (gitWorktree, error) {
+	worktree, err := getWorktree(repository)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return nil, errors.Wrap(err, "failed to retrieve git worktree")
+	}
+
+	// opening repository does not seem to consider already existing files properly
+	// behavior in case we do not run initializeWorktree:
+	//   git.Add(".") will add the complete workspace instead of only changed files
+	err = initializeWorktree(gitCommit, worktree)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, a := range artifacts {
 		// only update version in build descriptor if required in order to save prossing time (e.g. maven case)
-		if newVersion != version {
-			err = artifact.SetVersion(newVersion)
+		if a.newVersion != a.version {
+			err = a.artifact.SetVersion(a.newVersion)
 			if err != nil {
 				log.SetErrorCategory(log.ErrorConfiguration)
-				return errors.Wrap(err, "failed to write version")
+				return nil, errors.Wrap(err, "failed to write version")
 			}
 		}
+	}
 
-		//ToDo: what about closure in current Groovy step. Discard the possibility or provide extension mechanism?
-
-		if versioningType == "cloud" {
-			// commit changes and push to repository (including new version tag)
-			gitCommitID, err = pushChanges(config, newVersion, repository, worktree, now)
-			if err != nil {
-				return errors.Wrapf(err, "failed to push changes for version '%v'", newVersion)
+	if len(appVersion) > 0 {
+		for _, a := range artifacts[1:] {
+			appVersionArtifact, ok := a.artifact.(appVersionArtifact)
+			if !ok {
+				continue
+			}
+			if current, err := appVersionArtifact.GetAppVersion(); err == nil && current == appVersion {
+				continue
+			}
+			log.Entry().Infof("Updating appVersion of '%v' to '%v'", a.filePath, appVersion)
+			if err := appVersionArtifact.SetAppVersion(appVersion); err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return nil, errors.Wrapf(err, "failed to write appVersion of '%v'", a.filePath)
 			}
 		}
 	}
+	return worktree, nil
+}
 
-	log.Entry().Infof("New version: '%v'", newVersion)
+// getVersionTagCommits returns the version of the latest version tag as well as the messages of all commits since this tag.
+// In case no version tag exists, the messages of all commits are returned.
+func getVersionTagCommits(repository gitRepository, tagPrefix string) (string, []string, error) {
+	tags, err := repository.Tags()
+	if err != nil {
+		return "", nil, errors.Wrap(err, "failed to retrieve tags")
+	}
+	latestTag, latestVersion, err := versioning.LatestVersionTag(tags, tagPrefix)
+	if err != nil {
+		return "", nil, err
+	}
 
-	commonPipelineEnvironment.git.commitID = gitCommitID
-	commonPipelineEnvironment.artifactVersion = newVersion
-	commonPipelineEnvironment.originalArtifactVersion = version
-	commonPipelineEnvironment.git.commitMessage = gitCommitMessage
+	head, err := repository.ResolveRevision(plumbing.Revision("HEAD"))
+	if err != nil {
+		return "", nil, errors.Wrap(err, "failed to retrieve HEAD")
+	}
+	// commits which are part of the latest version are not considered
+	released := map[plumbing.Hash]bool{}
+	if len(latestTag) > 0 {
+		log.Entry().Infof("Latest version tag: '%v'", latestTag)
+		tagCommit, err := repository.ResolveRevision(plumbing.Revision(latestTag))
+		if err != nil {
+			return "", nil, errors.Wrapf(err, "failed to resolve tag '%v'", latestTag)
+		}
+		err = forEachCommit(repository, *tagCommit, func(c *object.Commit) {
+			released[c.Hash] = true
+		})
+		if err != nil {
+			return "", nil, err
+		}
+	}
+
+	messages := []string{}
+	err = forEachCommit(repository, *head, func(c *object.Commit) {
+		if !released[c.Hash] {
+			messages = append(messages, c.Message)
+		}
+	})
+	if err != nil {
+		return "", nil, err
+	}
 
+	if len(latestTag) == 0 {
+		return "", messages, nil
+	}
+	return latestVersion.String(), messages, nil
+}
+
+func forEachCommit(repository gitRepository, from plumbing.Hash, handle func(*object.Commit)) error {
+	commits, err := repository.Log(&git.LogOptions{From: from})
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve commits")
+	}
+	err = commits.ForEach(func(c *object.Commit) error {
+		handle(c)
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve commits")
+	}
 	return nil
 }
 
+// calculateSemanticVersion increments the version of the latest version tag according to the Conventional Commits since that tag.
+// In case no version tag exists yet, the current version is incremented based on all commits.
+func calculateSemanticVersion(currentVersion, tagPrefix string, repository gitRepository) (string, error) {
+	tagVersion, messages, err := getVersionTagCommits(repository, tagPrefix)
+	if err != nil {
+		return "", err
+	}
+
+	baseVersion := tagVersion
+	if len(baseVersion) == 0 {
+		log.Entry().Infof("No version tag with prefix '%v' found, using version '%v' as base", tagPrefix, currentVersion)
+		baseVersion = currentVersion
+	}
+	version, err := versioning.ParseSemanticVersion(baseVersion)
+	if err != nil {
+		return "", err
+	}
+
+	increment := versioning.ConventionalCommitsIncrement(messages)
+	log.Entry().Infof("%v commit(s) since version '%v' require a %v increment", len(messages), baseVersion, increment)
+	if increment == versioning.IncrementNone {
+		return currentVersion, nil
+	}
+	return version.Increment(increment).String(), nil
+}
+
 func openGit() (gitRepository, error) {
 	workdir, _ := os.Getwd()
 	return gitUtils.PlainOpen(workdir)
@@ -232,6 +508,9 @@ func versioningTemplate(scheme string) (string, error) {
 	case "maven":
 		// according to https://www.mojohaus.org/versions-maven-plugin/version-rules.html
 		return "{{.Version}}{{if .Timestamp}}-{{.Timestamp}}{{if .CommitID}}_{{.CommitID}}{{end}}{{end}}", nil
+	case "rubygems":
+		// according to https://guides.rubygems.org/patterns/#prerelease-gems only letters, digits and periods are allowed
+		return "{{.Version}}{{if .Timestamp}}.{{.Timestamp}}{{if .CommitID}}.{{.CommitID}}{{end}}{{end}}", nil
 	case "pep440":
 		// according to https://www.python.org/dev/peps/pep-0440/
 		return "{{.Version}}{{if .Timestamp}}.{{.Timestamp}}{{if .CommitID}}+{{.CommitID}}{{end}}{{end}}", nil
@@ -292,19 +571,10 @@ func initializeWorktree(gitCommit plumbing.Hash, worktree gitWorktree) error {
 	return nil
 }
 
-func pushChanges(config *artifactPrepareVersionOptions, newVersion string, repository gitRepository, worktree gitWorktree, t time.Time) (string, error) {
-
-	var commitID string
-
-	commit, err := addAndCommit(config, worktree, newVersion, t)
-	if err != nil {
-		return commit.String(), err
-	}
-
-	commitID = commit.String()
+func pushChanges(config *artifactPrepareVersionOptions, newVersion string, repository gitRepository, worktree gitWorktree, t time.Time, signer gitUtils.Signer) (string, error) {
 
-	tag := fmt.Sprintf("%v%v", config.TagPrefix, newVersion)
-	_, err = repository.CreateTag(tag, commit, nil)
+	commit, tag, err := commitAndTag(config, newVersion, repository, worktree, t, signer)
+	commitID := commit.String()
 	if err != nil {
 		return commitID, err
 	}
@@ -397,6 +667,76 @@ func pushChanges(config *artifactPrepareVersionOptions, newVersion string, repos
 	return commitID, nil
 }
 
+// commitAndTag commits the changes for the new version and creates the version tag in the local repository.
+// In case a signer is provided, the commit and an annotated tag are signed.
+func commitAndTag(config *artifactPrepareVersionOptions, newVersion string, repository gitRepository, worktree gitWorktree, t time.Time, signer gitUtils.Signer) (plumbing.Hash, string, error) {
+	commit, err := addAndCommit(config, worktree, newVersion, t)
+	if err != nil {
+		return commit, "", err
+	}
+
+	tag := fmt.Sprintf("%v%v", config.TagPrefix, newVersion)
+	if signer == nil {
+		_, err = repository.CreateTag(tag, commit, nil)
+		return commit, tag, err
+	}
+
+	commit, err = signVersionCommit(repository, commit, signer)
+	if err != nil {
+		return commit, "", errors.Wrap(err, "failed to sign commit of new version")
+	}
+	_, err = createSignedVersionTag(repository, tag, commit, object.Signature{Name: config.CommitUserName, When: t}, fmt.Sprintf("version %v", newVersion), signer)
+	if err != nil {
+		return commit, tag, errors.Wrap(err, "failed to create signed tag")
+	}
+	return commit, tag, nil
+}
+
+// signVersionCommit replaces the version commit with a signed one
+var signVersionCommit = func(repository gitRepository, commit plumbing.Hash, signer gitUtils.Signer) (plumbing.Hash, error) {
+	repo, ok := repository.(*git.Repository)
+	if !ok {
+		return commit, fmt.Errorf("commit signing not available")
+	}
+	return gitUtils.SignCommit(repo, commit, signer)
+}
+
+// createSignedVersionTag creates a signed annotated version tag
+var createSignedVersionTag = func(repository gitRepository, tag string, commit plumbing.Hash, tagger object.Signature, message string, signer gitUtils.Signer) (*plumbing.Reference, error) {
+	repo, ok := repository.(*git.Repository)
+	if !ok {
+		return nil, fmt.Errorf("tag signing not available")
+	}
+	return gitUtils.CreateSignedTag(repo, tag, commit, tagger, message, signer)
+}
+
+// getVersionSigner returns the signer for the version commit and tag in case a signing key is configured
+func getVersionSigner(config *artifactPrepareVersionOptions, utils artifactPrepareVersionUtils) (gitUtils.Signer, error) {
+	if len(config.SigningKey) == 0 {
+		return nil, nil
+	}
+	key, err := utils.FileRead(config.SigningKey)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return nil, errors.Wrapf(err, "failed to read signing key '%v'", config.SigningKey)
+	}
+
+	var signer gitUtils.Signer
+	switch config.SigningFormat {
+	case "ssh":
+		signer, err = gitUtils.NewSSHSigner(string(key), config.SigningKeyPassphrase)
+	case "gpg", "":
+		signer, err = gitUtils.NewGPGSigner(string(key), config.SigningKeyPassphrase)
+	default:
+		err = fmt.Errorf("signing format '%v' not supported", config.SigningFormat)
+	}
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return nil, errors.Wrap(err, "failed to create signer")
+	}
+	return signer, nil
+}
+
 func addAndCommit(config *artifactPrepareVersionOptions, worktree gitWorktree, newVersion string, t time.Time) (plumbing.Hash, error) {
 	//maybe more options are required: https://github.com/go-git/go-git/blob/master/_examples/commit/main.go
 	commit, err := worktree.Commit(fmt.Sprintf("update version %v", newVersion), &git.CommitOptions{All: true, Author: &object.Signature{Name: config.CommitUserName, When: t}})
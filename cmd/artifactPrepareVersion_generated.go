@@ -16,24 +16,32 @@ import (
 )
 
 type artifactPrepareVersionOptions struct {
-	BuildTool              string `json:"buildTool,omitempty"`
-	CommitUserName         string `json:"commitUserName,omitempty"`
-	CustomVersionField     string `json:"customVersionField,omitempty"`
-	CustomVersionSection   string `json:"customVersionSection,omitempty"`
-	CustomVersioningScheme string `json:"customVersioningScheme,omitempty"`
-	DockerVersionSource    string `json:"dockerVersionSource,omitempty"`
-	FilePath               string `json:"filePath,omitempty"`
-	GlobalSettingsFile     string `json:"globalSettingsFile,omitempty"`
-	IncludeCommitID        bool   `json:"includeCommitId,omitempty"`
-	M2Path                 string `json:"m2Path,omitempty"`
-	Password               string `json:"password,omitempty"`
-	ProjectSettingsFile    string `json:"projectSettingsFile,omitempty"`
-	ShortCommitID          bool   `json:"shortCommitId,omitempty"`
-	TagPrefix              string `json:"tagPrefix,omitempty"`
-	UnixTimestamp          bool   `json:"unixTimestamp,omitempty"`
-	Username               string `json:"username,omitempty"`
-	VersioningTemplate     string `json:"versioningTemplate,omitempty"`
-	VersioningType         string `json:"versioningType,omitempty"`
+	Artifacts              []map[string]interface{} `json:"artifacts,omitempty"`
+	BuildTool              string                   `json:"buildTool,omitempty"`
+	CommitUserName         string                   `json:"commitUserName,omitempty"`
+	CustomVersionField     string                   `json:"customVersionField,omitempty"`
+	CustomVersionSection   string                   `json:"customVersionSection,omitempty"`
+	CustomVersioningScheme string                   `json:"customVersioningScheme,omitempty"`
+	DiscoverArtifacts      bool                     `json:"discoverArtifacts,omitempty"`
+	DockerVersionSource    string                   `json:"dockerVersionSource,omitempty"`
+	FilePath               string                   `json:"filePath,omitempty"`
+	GlobalSettingsFile     string                   `json:"globalSettingsFile,omitempty"`
+	HelmUpdateAppVersion   bool                     `json:"helmUpdateAppVersion,omitempty"`
+	IncludeCommitID        bool                     `json:"includeCommitId,omitempty"`
+	KustomizeImageName     string                   `json:"kustomizeImageName,omitempty"`
+	M2Path                 string                   `json:"m2Path,omitempty"`
+	Password               string                   `json:"password,omitempty"`
+	Push                   bool                     `json:"push,omitempty"`
+	ProjectSettingsFile    string                   `json:"projectSettingsFile,omitempty"`
+	ShortCommitID          bool                     `json:"shortCommitId,omitempty"`
+	SigningFormat          string                   `json:"signingFormat,omitempty"`
+	SigningKey             string                   `json:"signingKey,omitempty"`
+	SigningKeyPassphrase   string                   `json:"signingKeyPassphrase,omitempty"`
+	TagPrefix              string                   `json:"tagPrefix,omitempty"`
+	UnixTimestamp          bool                     `json:"unixTimestamp,omitempty"`
+	Username               string                   `json:"username,omitempty"`
+	VersioningTemplate     string                   `json:"versioningTemplate,omitempty"`
+	VersioningType         string                   `json:"versioningType,omitempty"`
 }
 
 type artifactPrepareVersionCommonPipelineEnvironment struct {
@@ -43,6 +51,9 @@ type artifactPrepareVersionCommonPipelineEnvironment struct {
 		commitID      string
 		commitMessage string
 	}
+	custom struct {
+		artifactVersions []map[string]interface{}
+	}
 }
 
 func (p *artifactPrepareVersionCommonPipelineEnvironment) persist(path, resourceName string) {
@@ -55,6 +66,7 @@ func (p *artifactPrepareVersionCommonPipelineEnvironment) persist(path, resource
 		{category: "", name: "originalArtifactVersion", value: p.originalArtifactVersion},
 		{category: "git", name: "commitId", value: p.git.commitID},
 		{category: "git", name: "commitMessage", value: p.git.commitMessage},
+		{category: "custom", name: "artifactVersions", value: p.custom.artifactVersions},
 	}
 
 	errCount := 0
@@ -120,6 +132,78 @@ Unlike for the _Continuous Deloyment_ pattern described above, in this case ther
 
 Configuration of this pattern is done via ` + "`" + `versioningType: library` + "`" + `.
 
+### 3. Semantic versioning based on Conventional Commits
+
+The next ` + "`" + `<major>.<minor>.<patch>` + "`" + ` version is calculated from the commit messages since the latest version tag (` + "`" + `<tagPrefix><major>.<minor>.<patch>` + "`" + `).
+The commit messages are classified according to [Conventional Commits](https://www.conventionalcommits.org):
+
+* ` + "`" + `fix: ...` + "`" + ` increments the patch version
+* ` + "`" + `feat: ...` + "`" + ` increments the minor version
+* ` + "`" + `BREAKING CHANGE: ...` + "`" + ` in the commit message or a ` + "`" + `!` + "`" + ` after the type (e.g. ` + "`" + `feat!: ...` + "`" + `) increments the major version
+
+The new version is written into the build descriptor, committed and tagged. With ` + "`" + `push: true` + "`" + ` (default) the tag is pushed to the remote repository.
+In case no version tag exists yet, the version from the build descriptor is used as base and all commits are taken into account.
+In case no commit requires a new version, the version from the build descriptor is kept and no tag is created.
+
+Configuration of this pattern is done via ` + "`" + `versioningType: semantic` + "`" + `, typically together with ` + "`" + `tagPrefix: v` + "`" + `.
+
+### Repositories containing multiple artifacts
+
+In case a repository contains several artifacts (e.g. a Maven backend, an npm UI and a Helm chart), all of them can be versioned within one run.
+Besides the artifact defined via ` + "`" + `buildTool` + "`" + ` and ` + "`" + `filePath` + "`" + `, additional artifacts are either listed via ` + "`" + `artifacts` + "`" + ` or discovered automatically via ` + "`" + `discoverArtifacts: true` + "`" + `.
+
+Each entry of ` + "`" + `artifacts` + "`" + ` requires ` + "`" + `buildTool` + "`" + ` and ` + "`" + `filePath` + "`" + ` and may contain ` + "`" + `versioningType` + "`" + `, ` + "`" + `customVersionField` + "`" + `, ` + "`" + `customVersionSection` + "`" + `, ` + "`" + `customVersioningScheme` + "`" + `, ` + "`" + `dockerVersionSource` + "`" + ` and ` + "`" + `kustomizeImageName` + "`" + `:
+
+` + "`" + `` + "`" + `` + "`" + `yaml
+steps:
+  artifactPrepareVersion:
+    buildTool: maven
+    filePath: backend/pom.xml
+    artifacts:
+      - buildTool: npm
+        filePath: ui/package.json
+      - buildTool: helm
+        filePath: chart/Chart.yaml
+        versioningType: library
+` + "`" + `` + "`" + `` + "`" + `
+
+The new versions of all artifacts are written in one commit and the tag as well as ` + "`" + `artifactVersion` + "`" + ` in the commonPipelineEnvironment refer to the version of the artifact defined via ` + "`" + `buildTool` + "`" + `.
+Artifacts without a ` + "`" + `versioningType` + "`" + ` of their own follow the step's ` + "`" + `versioningType` + "`" + ` (including a ` + "`" + `versioningType` + "`" + ` derived from ` + "`" + `versioningTemplate` + "`" + `), ` + "`" + `library` + "`" + ` keeps the version of an artifact.
+Every changed version is written into its build descriptor, whereas committing, tagging and pushing is defined by the step's ` + "`" + `versioningType` + "`" + ` only: with ` + "`" + `versioningType: library` + "`" + ` or ` + "`" + `cloud_noTag` + "`" + ` changed versions of artifacts are only written into the workspace, with ` + "`" + `versioningType: semantic` + "`" + ` a commit and tag are only created in case the version of the artifact defined via ` + "`" + `buildTool` + "`" + ` changed.
+The versions of all artifacts are available in the commonPipelineEnvironment as ` + "`" + `custom/artifactVersions` + "`" + `.
+
+### Signed commits and tags
+
+In case the repository requires signed commits, the version commit and tag can be signed by providing a ` + "`" + `signingKey` + "`" + ` (path to an armored GPG private key or, with ` + "`" + `signingFormat: ssh` + "`" + `, an OpenSSH private key) and optionally its ` + "`" + `signingKeyPassphrase` + "`" + `.
+With Vault the key is read from the secret file ` + "`" + `git-signing-key` + "`" + ` and the passphrase from the field ` + "`" + `signingKeyPassphrase` + "`" + ` of the secret ` + "`" + `gitSigningKey` + "`" + `.
+The version tag is then created as signed annotated tag.
+
+### Helm charts and Kustomize
+
+With ` + "`" + `buildTool: helm` + "`" + ` the ` + "`" + `version` + "`" + ` of the ` + "`" + `Chart.yaml` + "`" + ` is updated. Formatting and comments of the file are kept.
+Setting ` + "`" + `helmUpdateAppVersion: true` + "`" + ` keeps the ` + "`" + `appVersion` + "`" + ` of the chart in sync: a chart listed in ` + "`" + `artifacts` + "`" + ` gets the version of the application artifact as ` + "`" + `appVersion` + "`" + `, e.g.
+
+` + "`" + `` + "`" + `` + "`" + `yaml
+steps:
+  artifactPrepareVersion:
+    buildTool: maven
+    helmUpdateAppVersion: true
+    artifacts:
+      - buildTool: helm
+        filePath: chart/Chart.yaml
+` + "`" + `` + "`" + `` + "`" + `
+
+With ` + "`" + `buildTool: kustomize` + "`" + ` the ` + "`" + `newTag` + "`" + ` of an image in the ` + "`" + `images` + "`" + ` section of the ` + "`" + `kustomization.yaml` + "`" + ` is updated. In case the kustomization contains multiple images, the relevant one is defined via ` + "`" + `kustomizeImageName` + "`" + `.
+
+### Rust, Ruby, .NET and PHP
+
+* ` + "`" + `buildTool: cargo` + "`" + ` updates the ` + "`" + `version` + "`" + ` in the ` + "`" + `[package]` + "`" + ` (or ` + "`" + `[workspace.package]` + "`" + `) section of the ` + "`" + `Cargo.toml` + "`" + `.
+* ` + "`" + `buildTool: gem` + "`" + ` updates the ` + "`" + `version` + "`" + ` of the ` + "`" + `*.gemspec` + "`" + `. In case the gemspec refers to a constant (e.g. ` + "`" + `spec.version = MyGem::VERSION` + "`" + `), the ` + "`" + `VERSION` + "`" + ` in the ` + "`" + `lib/**/version.rb` + "`" + ` of the gem is updated. The ` + "`" + `version.rb` + "`" + ` can also be defined directly as ` + "`" + `filePath` + "`" + `.
+* ` + "`" + `buildTool: dotnet` + "`" + ` updates the ` + "`" + `<Version>` + "`" + ` (or ` + "`" + `<VersionPrefix>` + "`" + `) property of a ` + "`" + `*.csproj` + "`" + ` or a ` + "`" + `Directory.Build.props` + "`" + `.
+* ` + "`" + `buildTool: composer` + "`" + ` updates the ` + "`" + `version` + "`" + ` of the ` + "`" + `composer.json` + "`" + `. Please note that the ` + "`" + `version` + "`" + ` needs to be maintained in the file.
+
+With ` + "`" + `versioningType: cloud` + "`" + ` Ruby gems get a version like ` + "`" + `1.2.3.20210301120000.<commitId>` + "`" + ` since RubyGems does not allow ` + "`" + `-` + "`" + ` and ` + "`" + `+` + "`" + ` in versions.
+
 ### Support of additional build tools
 
 Besides the ` + "`" + `buildTools` + "`" + ` provided out of the box (like ` + "`" + `maven` + "`" + `, ` + "`" + `mta` + "`" + `, ` + "`" + `npm` + "`" + `, ...) it is possible to set ` + "`" + `buildTool: custom` + "`" + `.
@@ -160,6 +244,8 @@ Define ` + "`" + `buildTool: custom` + "`" + `, ` + "`" + `filePath: <path to yo
 				return err
 			}
 			log.RegisterSecret(stepConfig.Password)
+			log.RegisterSecret(stepConfig.SigningKey)
+			log.RegisterSecret(stepConfig.SigningKeyPassphrase)
 			log.RegisterSecret(stepConfig.Username)
 
 			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
@@ -193,24 +279,32 @@ Define ` + "`" + `buildTool: custom` + "`" + `, ` + "`" + `filePath: <path to yo
 }
 
 func addArtifactPrepareVersionFlags(cmd *cobra.Command, stepConfig *artifactPrepareVersionOptions) {
-	cmd.Flags().StringVar(&stepConfig.BuildTool, "buildTool", os.Getenv("PIPER_buildTool"), "Defines the tool which is used for building the artifact. Supports `custom`, `dub`, `golang`, `maven`, `mta`, `npm`, `pip`, `sbt`.")
+
+	cmd.Flags().StringVar(&stepConfig.BuildTool, "buildTool", os.Getenv("PIPER_buildTool"), "Defines the tool which is used for building the artifact. Supports `cargo`, `composer`, `custom`, `docker`, `dotnet`, `dub`, `gem`, `golang`, `helm`, `kustomize`, `maven`, `mta`, `npm`, `pip`, `sbt`.")
 	cmd.Flags().StringVar(&stepConfig.CommitUserName, "commitUserName", `Project Piper`, "Defines the user name which appears in version control for the versioning update (in case `versioningType: cloud`).")
 	cmd.Flags().StringVar(&stepConfig.CustomVersionField, "customVersionField", os.Getenv("PIPER_customVersionField"), "For `buildTool: custom`: Defines the field which contains the version in the descriptor file.")
 	cmd.Flags().StringVar(&stepConfig.CustomVersionSection, "customVersionSection", os.Getenv("PIPER_customVersionSection"), "For `buildTool: custom`: Defines the section for version retrieval in vase a *.ini/*.cfg file is used.")
 	cmd.Flags().StringVar(&stepConfig.CustomVersioningScheme, "customVersioningScheme", os.Getenv("PIPER_customVersioningScheme"), "For `buildTool: custom`: Defines the versioning scheme to be used (possible options `pep440`, `maven`, `semver2`).")
+	cmd.Flags().BoolVar(&stepConfig.DiscoverArtifacts, "discoverArtifacts", false, "If set to `true` and no `artifacts` are provided, build descriptors (`pom.xml`, `package.json`, `mta.yaml`, `setup.py`, `dub.json`, `go.mod`, `Chart.yaml`, `Cargo.toml`, `composer.json`) in all subdirectories are versioned together with the artifact defined via `buildTool`. Maven modules and Helm subcharts (below `charts/`) are versioned together with their parent descriptor and are therefore not considered, nested descriptors of other build tools (e.g. npm workspaces or Go modules) are versioned as artifacts of their own.")
 	cmd.Flags().StringVar(&stepConfig.DockerVersionSource, "dockerVersionSource", os.Getenv("PIPER_dockerVersionSource"), "For `buildTool: docker`: Defines the source of the version. Can be `FROM`, any supported _buildTool_ or an environment variable name.")
-	cmd.Flags().StringVar(&stepConfig.FilePath, "filePath", os.Getenv("PIPER_filePath"), "Defines a custom path to the descriptor file. Build tool specific defaults are used (e.g. `maven: pom.xml`, `npm: package.json`, `mta: mta.yaml`).")
+	cmd.Flags().StringVar(&stepConfig.FilePath, "filePath", os.Getenv("PIPER_filePath"), "Defines a custom path to the descriptor file. Build tool specific defaults are used (e.g. `maven: pom.xml`, `npm: package.json`, `mta: mta.yaml`, `helm: Chart.yaml`, `kustomize: kustomization.yaml`, `cargo: Cargo.toml`). For `dotnet` the `Directory.Build.props` or the only `*.csproj` and for `gem` the only `*.gemspec` in the current directory is used.")
 	cmd.Flags().StringVar(&stepConfig.GlobalSettingsFile, "globalSettingsFile", os.Getenv("PIPER_globalSettingsFile"), "Maven only - Path to the mvn settings file that should be used as global settings file.")
+	cmd.Flags().BoolVar(&stepConfig.HelmUpdateAppVersion, "helmUpdateAppVersion", false, "For `buildTool: helm`: Defines if the `appVersion` of the chart is updated as well. For the chart defined via `buildTool` the `appVersion` is set to the new chart version, for charts listed in `artifacts` it is set to the new version of the artifact defined via `buildTool`.")
 	cmd.Flags().BoolVar(&stepConfig.IncludeCommitID, "includeCommitId", true, "Defines if the automatically generated version (`versioningType: cloud`) should include the commit id hash.")
+	cmd.Flags().StringVar(&stepConfig.KustomizeImageName, "kustomizeImageName", os.Getenv("PIPER_kustomizeImageName"), "For `buildTool: kustomize`: Defines the name of the image in the `images` section of the kustomization whose `newTag` carries the version. Can be omitted in case the kustomization contains only one image.")
 	cmd.Flags().StringVar(&stepConfig.M2Path, "m2Path", os.Getenv("PIPER_m2Path"), "Maven only - Path to the location of the local repository that should be used.")
 	cmd.Flags().StringVar(&stepConfig.Password, "password", os.Getenv("PIPER_password"), "Password/token for git authentication.")
+	cmd.Flags().BoolVar(&stepConfig.Push, "push", true, "Defines if the commit and the tag of the new version are pushed to the remote repository (only `versioningType: semantic`).")
 	cmd.Flags().StringVar(&stepConfig.ProjectSettingsFile, "projectSettingsFile", os.Getenv("PIPER_projectSettingsFile"), "Maven only - Path to the mvn settings file that should be used as project settings file.")
 	cmd.Flags().BoolVar(&stepConfig.ShortCommitID, "shortCommitId", false, "Defines if a short version of the commitId should be used. GitHub format is used (first 7 characters).")
-	cmd.Flags().StringVar(&stepConfig.TagPrefix, "tagPrefix", `build_`, "Defines the prefix which is used for the git tag which is written during the versioning run (only `versioningType: cloud`).")
+	cmd.Flags().StringVar(&stepConfig.SigningFormat, "signingFormat", `gpg`, "Defines the format of the `signingKey`: `gpg` for an armored GPG private key or `ssh` for an OpenSSH private key.")
+	cmd.Flags().StringVar(&stepConfig.SigningKey, "signingKey", os.Getenv("PIPER_signingKey"), "Defines the path to the file containing the private key used to sign the version commit and tag. In case a key is provided, the version tag is created as signed annotated tag.")
+	cmd.Flags().StringVar(&stepConfig.SigningKeyPassphrase, "signingKeyPassphrase", os.Getenv("PIPER_signingKeyPassphrase"), "Defines the passphrase of the `signingKey`.")
+	cmd.Flags().StringVar(&stepConfig.TagPrefix, "tagPrefix", `build_`, "Defines the prefix which is used for the git tag which is written during the versioning run (only `versioningType: cloud` and `versioningType: semantic`).")
 	cmd.Flags().BoolVar(&stepConfig.UnixTimestamp, "unixTimestamp", false, "Defines if the Unix timestamp number should be used as build number instead of the standard date format.")
 	cmd.Flags().StringVar(&stepConfig.Username, "username", os.Getenv("PIPER_username"), "User name for git authentication")
 	cmd.Flags().StringVar(&stepConfig.VersioningTemplate, "versioningTemplate", os.Getenv("PIPER_versioningTemplate"), "DEPRECATED: Defines the template for the automatic version which will be created")
-	cmd.Flags().StringVar(&stepConfig.VersioningType, "versioningType", `cloud`, "Defines the type of versioning (`cloud`: fully automatic, `cloud_noTag`: automatic but no tag created, `library`: manual, i.e. the pipeline will pick up the version from the build descriptor, but not generate a new version)")
+	cmd.Flags().StringVar(&stepConfig.VersioningType, "versioningType", `cloud`, "Defines the type of versioning (`cloud`: fully automatic, `cloud_noTag`: automatic but no tag created, `library`: manual, i.e. the pipeline will pick up the version from the build descriptor, but not generate a new version, `semantic`: next version calculated from Conventional Commits)")
 
 	cmd.MarkFlagRequired("buildTool")
 }
@@ -226,6 +320,14 @@ func artifactPrepareVersionMetadata() config.StepData {
 		Spec: config.StepSpec{
 			Inputs: config.StepInputs{
 				Parameters: []config.StepParameters{
+					{
+						Name:        "artifacts",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]map[string]interface{}",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
 					{
 						Name:        "buildTool",
 						ResourceRef: []config.ResourceReference{},
@@ -266,6 +368,14 @@ func artifactPrepareVersionMetadata() config.StepData {
 						Mandatory:   false,
 						Aliases:     []config.Alias{},
 					},
+					{
+						Name:        "discoverArtifacts",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
 					{
 						Name:        "dockerVersionSource",
 						ResourceRef: []config.ResourceReference{},
@@ -290,6 +400,14 @@ func artifactPrepareVersionMetadata() config.StepData {
 						Mandatory:   false,
 						Aliases:     []config.Alias{{Name: "maven/globalSettingsFile"}},
 					},
+					{
+						Name:        "helmUpdateAppVersion",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
 					{
 						Name:        "includeCommitId",
 						ResourceRef: []config.ResourceReference{},
@@ -298,6 +416,14 @@ func artifactPrepareVersionMetadata() config.StepData {
 						Mandatory:   false,
 						Aliases:     []config.Alias{},
 					},
+					{
+						Name:        "kustomizeImageName",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
 					{
 						Name:        "m2Path",
 						ResourceRef: []config.ResourceReference{},
@@ -326,6 +452,14 @@ func artifactPrepareVersionMetadata() config.StepData {
 						Mandatory: false,
 						Aliases:   []config.Alias{},
 					},
+					{
+						Name:        "push",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
 					{
 						Name:        "projectSettingsFile",
 						ResourceRef: []config.ResourceReference{},
@@ -342,6 +476,52 @@ func artifactPrepareVersionMetadata() config.StepData {
 						Mandatory:   false,
 						Aliases:     []config.Alias{},
 					},
+					{
+						Name:        "signingFormat",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name: "signingKey",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "signingKeyCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:  "",
+								Paths: []string{"$(vaultPath)/git-signing-key", "$(vaultBasePath)/$(vaultPipelineName)/git-signing-key", "$(vaultBasePath)/GROUP-SECRETS/git-signing-key"},
+								Type:  "vaultSecretFile",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+					},
+					{
+						Name: "signingKeyPassphrase",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "signingKeyPassphraseCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:  "",
+								Paths: []string{"$(vaultPath)/gitSigningKey", "$(vaultBasePath)/$(vaultPipelineName)/gitSigningKey", "$(vaultBasePath)/GROUP-SECRETS/gitSigningKey"},
+								Type:  "vaultSecret",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+					},
 					{
 						Name:        "tagPrefix",
 						ResourceRef: []config.ResourceReference{},
@@ -409,6 +589,7 @@ func artifactPrepareVersionMetadata() config.StepData {
 							{"Name": "originalArtifactVersion"},
 							{"Name": "git/commitId"},
 							{"Name": "git/commitMessage"},
+							{"Name": "custom/artifactVersions"},
 						},
 					},
 				},
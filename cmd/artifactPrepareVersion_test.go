@@ -1,21 +1,31 @@
 package cmd
 
 import (
+	"bytes"
 	"fmt"
+	"io"
+	netHttp "net/http"
 	"testing"
 	"time"
 
+	gitUtils "github.com/SAP/jenkins-library/pkg/git"
+	"github.com/SAP/jenkins-library/pkg/mock"
 	"github.com/SAP/jenkins-library/pkg/versioning"
 
 	"github.com/SAP/jenkins-library/pkg/telemetry"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/go-git/go-billy/v5/memfs"
 	"github.com/go-git/go-git/v5"
 	gitConfig "github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
 	"github.com/go-git/go-git/v5/plumbing/transport/http"
 	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/armor"
 )
 
 type artifactVersioningMock struct {
@@ -71,6 +81,28 @@ type gitRepositoryMock struct {
 	worktree            *git.Worktree
 	worktreeError       string
 	commitObjectHash    string
+	tags                []*plumbing.Reference
+	tagsError           string
+	tagRevisions        map[string]plumbing.Hash
+	logs                map[plumbing.Hash][]*object.Commit
+}
+
+// withVersionHistory adds a version tag (if provided) and the commits since this tag to the repository mock
+func (r *gitRepositoryMock) withVersionHistory(tag string, messages ...string) *gitRepositoryMock {
+	commits := []*object.Commit{}
+	for _, message := range messages {
+		commits = append(commits, &object.Commit{Hash: plumbing.ComputeHash(plumbing.CommitObject, []byte(message)), Message: message})
+	}
+	r.logs = map[plumbing.Hash][]*object.Commit{}
+	if len(tag) > 0 {
+		tagCommit := &object.Commit{Hash: plumbing.ComputeHash(plumbing.CommitObject, []byte(tag)), Message: "update version"}
+		r.tags = append(r.tags, plumbing.NewHashReference(plumbing.NewTagReferenceName(tag), tagCommit.Hash))
+		r.tagRevisions = map[string]plumbing.Hash{tag: tagCommit.Hash}
+		r.logs[tagCommit.Hash] = []*object.Commit{tagCommit}
+		commits = append(commits, tagCommit)
+	}
+	r.logs[r.revisionHash] = commits
+	return r
 }
 
 func (r *gitRepositoryMock) CommitObject(hash plumbing.Hash) (*object.Commit, error) {
@@ -121,7 +153,14 @@ func (r *gitRepositoryMock) Remote(name string) (*git.Remote, error) {
 	return r.remote, nil
 }
 
+func (r *gitRepositoryMock) Log(o *git.LogOptions) (object.CommitIter, error) {
+	return &commitIterMock{commits: r.logs[o.From]}, nil
+}
+
 func (r *gitRepositoryMock) ResolveRevision(rev plumbing.Revision) (*plumbing.Hash, error) {
+	if hash, ok := r.tagRevisions[rev.String()]; ok {
+		return &hash, nil
+	}
 	if len(r.revisionError) > 0 {
 		return nil, fmt.Errorf(r.revisionError)
 	}
@@ -129,6 +168,13 @@ func (r *gitRepositoryMock) ResolveRevision(rev plumbing.Revision) (*plumbing.Ha
 	return &r.revisionHash, nil
 }
 
+func (r *gitRepositoryMock) Tags() (storer.ReferenceIter, error) {
+	if len(r.tagsError) > 0 {
+		return nil, fmt.Errorf(r.tagsError)
+	}
+	return storer.NewReferenceSliceIter(r.tags), nil
+}
+
 func (r *gitRepositoryMock) Worktree() (*git.Worktree, error) {
 	if len(r.worktreeError) > 0 {
 		return nil, fmt.Errorf(r.worktreeError)
@@ -136,6 +182,33 @@ func (r *gitRepositoryMock) Worktree() (*git.Worktree, error) {
 	return r.worktree, nil
 }
 
+type commitIterMock struct {
+	commits []*object.Commit
+	index   int
+}
+
+func (i *commitIterMock) Next() (*object.Commit, error) {
+	if i.index >= len(i.commits) {
+		return nil, io.EOF
+	}
+	i.index++
+	return i.commits[i.index-1], nil
+}
+
+func (i *commitIterMock) ForEach(cb func(*object.Commit) error) error {
+	for _, c := range i.commits {
+		if err := cb(c); err != nil {
+			if err == storer.ErrStop {
+				return nil
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func (i *commitIterMock) Close() {}
+
 type gitWorktreeMock struct {
 	checkoutError string
 	checkoutOpts  *git.CheckoutOptions
@@ -161,6 +234,15 @@ func (w *gitWorktreeMock) Commit(msg string, opts *git.CommitOptions) (plumbing.
 	return w.commitHash, nil
 }
 
+type artifactPrepareVersionMockUtils struct {
+	*mock.ExecMockRunner
+	*mock.FilesMock
+}
+
+func (a *artifactPrepareVersionMockUtils) DownloadFile(url, filename string, header netHttp.Header, cookies []*netHttp.Cookie) error {
+	return fmt.Errorf("not implemented")
+}
+
 func TestRunArtifactPrepareVersion(t *testing.T) {
 
 	t.Run("success case - cloud", func(t *testing.T) {
@@ -412,6 +494,478 @@ func TestRunArtifactPrepareVersion(t *testing.T) {
 		err := runArtifactPrepareVersion(&config, &telemetry.CustomData{}, nil, &versioningMock, nil, &repo, func(r gitRepository) (gitWorktree, error) { return &worktree, nil })
 		assert.Contains(t, fmt.Sprint(err), "failed to push changes for version '1.2.3")
 	})
+
+	t.Run("success case - semantic", func(t *testing.T) {
+		config := artifactPrepareVersionOptions{
+			BuildTool:      "maven",
+			Password:       "****",
+			Push:           true,
+			TagPrefix:      "v",
+			Username:       "testUser",
+			VersioningType: "semantic",
+		}
+		cpe := artifactPrepareVersionCommonPipelineEnvironment{}
+		versioningMock := artifactVersioningMock{
+			originalVersion:  "1.2.3",
+			versioningScheme: "maven",
+		}
+		worktree := gitWorktreeMock{
+			commitHash: plumbing.ComputeHash(plumbing.CommitObject, []byte{2, 3, 4}),
+		}
+		conf := gitConfig.RemoteConfig{Name: "origin", URLs: []string{"https://my.test.server"}}
+		repo := gitRepositoryMock{
+			revisionHash: plumbing.ComputeHash(plumbing.CommitObject, []byte{1, 2, 3}),
+			remote:       git.NewRemote(nil, &conf),
+		}
+		repo.withVersionHistory("v1.2.3", "fix: bug", "feat: feature")
+
+		err := runArtifactPrepareVersion(&config, &telemetry.CustomData{}, &cpe, &versioningMock, nil, &repo, func(r gitRepository) (gitWorktree, error) { return &worktree, nil })
+
+		assert.NoError(t, err)
+		assert.Equal(t, "1.3.0", versioningMock.newVersion)
+		assert.Equal(t, "update version 1.3.0", worktree.commitMsg)
+		assert.Equal(t, "v1.3.0", repo.tag)
+		assert.True(t, repo.pushCalled)
+		assert.Equal(t, "1.3.0", cpe.artifactVersion)
+		assert.Equal(t, "1.2.3", cpe.originalArtifactVersion)
+		assert.Equal(t, worktree.commitHash.String(), cpe.git.commitID)
+	})
+
+	t.Run("success case - semantic without push", func(t *testing.T) {
+		config := artifactPrepareVersionOptions{
+			BuildTool:      "maven",
+			TagPrefix:      "v",
+			VersioningType: "semantic",
+		}
+		cpe := artifactPrepareVersionCommonPipelineEnvironment{}
+		versioningMock := artifactVersioningMock{
+			originalVersion:  "1.2.3-SNAPSHOT",
+			versioningScheme: "maven",
+		}
+		worktree := gitWorktreeMock{
+			commitHash: plumbing.ComputeHash(plumbing.CommitObject, []byte{2, 3, 4}),
+		}
+		repo := gitRepositoryMock{
+			revisionHash: plumbing.ComputeHash(plumbing.CommitObject, []byte{1, 2, 3}),
+		}
+		repo.withVersionHistory("", "feat!: breaking feature")
+
+		err := runArtifactPrepareVersion(&config, &telemetry.CustomData{}, &cpe, &versioningMock, nil, &repo, func(r gitRepository) (gitWorktree, error) { return &worktree, nil })
+
+		assert.NoError(t, err)
+		assert.Equal(t, "2.0.0", versioningMock.newVersion)
+		assert.Equal(t, "v2.0.0", repo.tag)
+		assert.Equal(t, worktree.commitHash, repo.tagHash)
+		assert.False(t, repo.pushCalled)
+		assert.Equal(t, worktree.commitHash.String(), cpe.git.commitID)
+	})
+
+	t.Run("success case - semantic without relevant commits", func(t *testing.T) {
+		config := artifactPrepareVersionOptions{
+			BuildTool:      "maven",
+			Push:           true,
+			VersioningType: "semantic",
+		}
+		cpe := artifactPrepareVersionCommonPipelineEnvironment{}
+		versioningMock := artifactVersioningMock{
+			originalVersion:  "1.2.3",
+			versioningScheme: "maven",
+		}
+		repo := gitRepositoryMock{
+			revisionHash: plumbing.ComputeHash(plumbing.CommitObject, []byte{1, 2, 3}),
+		}
+		repo.withVersionHistory("1.2.3", "docs: update readme")
+
+		err := runArtifactPrepareVersion(&config, &telemetry.CustomData{}, &cpe, &versioningMock, nil, &repo, func(r gitRepository) (gitWorktree, error) { return &gitWorktreeMock{}, nil })
+
+		assert.NoError(t, err)
+		assert.Empty(t, versioningMock.newVersion)
+		assert.Empty(t, repo.tag)
+		assert.False(t, repo.pushCalled)
+		assert.Equal(t, "1.2.3", cpe.artifactVersion)
+		assert.Equal(t, repo.revisionHash.String(), cpe.git.commitID)
+	})
+
+	t.Run("error - semantic version", func(t *testing.T) {
+		config := artifactPrepareVersionOptions{
+			BuildTool:      "maven",
+			VersioningType: "semantic",
+		}
+		versioningMock := artifactVersioningMock{
+			originalVersion:  "1.2",
+			versioningScheme: "maven",
+		}
+
+		repo := (&gitRepositoryMock{}).withVersionHistory("", "fix: bug")
+
+		err := runArtifactPrepareVersion(&config, &telemetry.CustomData{}, nil, &versioningMock, nil, repo, func(r gitRepository) (gitWorktree, error) { return &gitWorktreeMock{}, nil })
+
+		assert.EqualError(t, err, "failed to calculate semantic version: version '1.2' is not a semantic version")
+	})
+}
+
+type appVersionArtifactMock struct {
+	*artifactVersioningMock
+	appVersion string
+}
+
+func (a *appVersionArtifactMock) GetAppVersion() (string, error) {
+	return a.appVersion, nil
+}
+
+func (a *appVersionArtifactMock) SetAppVersion(version string) error {
+	a.appVersion = version
+	return nil
+}
+
+func TestRunArtifactPrepareVersionMultipleArtifacts(t *testing.T) {
+	mockArtifacts := func(artifacts map[string]*artifactVersioningMock) func() {
+		getVersioningArtifact = func(buildTool, buildDescriptorFilePath string, opts *versioning.Options, utils versioning.Utils) (versioning.Artifact, error) {
+			if artifact, ok := artifacts[buildDescriptorFilePath]; ok {
+				return artifact, nil
+			}
+			return nil, fmt.Errorf("build tool '%v' not supported", buildTool)
+		}
+		return func() { getVersioningArtifact = versioning.GetArtifact }
+	}
+	newRepo := func() *gitRepositoryMock {
+		conf := gitConfig.RemoteConfig{Name: "origin", URLs: []string{"https://my.test.server"}}
+		return &gitRepositoryMock{
+			revisionHash: plumbing.ComputeHash(plumbing.CommitObject, []byte{1, 2, 3}),
+			remote:       git.NewRemote(nil, &conf),
+		}
+	}
+
+	t.Run("success case - cloud with artifact list", func(t *testing.T) {
+		ui := &artifactVersioningMock{originalVersion: "0.1.0", versioningScheme: "semver2"}
+		chart := &artifactVersioningMock{originalVersion: "2.0.0", versioningScheme: "semver2"}
+		defer mockArtifacts(map[string]*artifactVersioningMock{"ui/package.json": ui, "chart/Chart.yaml": chart})()
+
+		config := artifactPrepareVersionOptions{
+			BuildTool:      "maven",
+			FilePath:       "backend/pom.xml",
+			Password:       "****",
+			TagPrefix:      "v",
+			Username:       "testUser",
+			VersioningType: "cloud",
+			Artifacts: []map[string]interface{}{
+				{"buildTool": "npm", "filePath": "ui/package.json"},
+				{"buildTool": "custom", "filePath": "chart/Chart.yaml", "customVersionField": "version", "versioningType": "library"},
+			},
+		}
+		cpe := artifactPrepareVersionCommonPipelineEnvironment{}
+		backend := artifactVersioningMock{originalVersion: "1.2.3", versioningScheme: "maven"}
+		worktree := gitWorktreeMock{commitHash: plumbing.ComputeHash(plumbing.CommitObject, []byte{2, 3, 4})}
+		repo := newRepo()
+
+		err := runArtifactPrepareVersion(&config, &telemetry.CustomData{}, &cpe, &backend, nil, repo, func(r gitRepository) (gitWorktree, error) { return &worktree, nil })
+
+		if assert.NoError(t, err) {
+			assert.Regexp(t, `^1\.2\.3-\d{14}$`, backend.newVersion)
+			assert.Regexp(t, `^0\.1\.0-\d{14}$`, ui.newVersion)
+			assert.Empty(t, chart.newVersion, "version of library artifact must not be changed")
+			assert.Equal(t, "v"+backend.newVersion, repo.tag)
+			assert.True(t, repo.pushCalled)
+			assert.Equal(t, backend.newVersion, cpe.artifactVersion)
+			assert.Equal(t, worktree.commitHash.String(), cpe.git.commitID)
+			assert.Equal(t, []map[string]interface{}{
+				{"buildTool": "maven", "filePath": "backend/pom.xml", "version": backend.newVersion, "originalVersion": "1.2.3"},
+				{"buildTool": "npm", "filePath": "ui/package.json", "version": ui.newVersion, "originalVersion": "0.1.0"},
+				{"buildTool": "custom", "filePath": "chart/Chart.yaml", "version": "2.0.0", "originalVersion": "2.0.0"},
+			}, cpe.custom.artifactVersions)
+		}
+	})
+
+	t.Run("success case - semantic with discovered artifacts", func(t *testing.T) {
+		ui := &artifactVersioningMock{originalVersion: "1.2.3", versioningScheme: "semver2"}
+		defer mockArtifacts(map[string]*artifactVersioningMock{"ui/package.json": ui})()
+
+		utils := &artifactPrepareVersionMockUtils{ExecMockRunner: &mock.ExecMockRunner{}, FilesMock: &mock.FilesMock{}}
+		utils.AddFile("pom.xml", []byte{})
+		utils.AddFile("module/pom.xml", []byte{})
+		utils.AddFile("ui/package.json", []byte{})
+
+		config := artifactPrepareVersionOptions{
+			BuildTool:         "maven",
+			DiscoverArtifacts: true,
+			TagPrefix:         "v",
+			VersioningType:    "semantic",
+		}
+		cpe := artifactPrepareVersionCommonPipelineEnvironment{}
+		backend := artifactVersioningMock{originalVersion: "1.2.3", versioningScheme: "maven"}
+		worktree := gitWorktreeMock{commitHash: plumbing.ComputeHash(plumbing.CommitObject, []byte{2, 3, 4})}
+		repo := newRepo().withVersionHistory("v1.2.3", "fix: bug")
+
+		err := runArtifactPrepareVersion(&config, &telemetry.CustomData{}, &cpe, &backend, utils, repo, func(r gitRepository) (gitWorktree, error) { return &worktree, nil })
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, "1.2.4", backend.newVersion)
+			assert.Equal(t, "1.2.4", ui.newVersion)
+			assert.Equal(t, "v1.2.4", repo.tag)
+			assert.Equal(t, "update version 1.2.4", worktree.commitMsg)
+			assert.Len(t, cpe.custom.artifactVersions, 2)
+		}
+	})
+
+	t.Run("success case - appVersion of helm chart", func(t *testing.T) {
+		chart := &appVersionArtifactMock{artifactVersioningMock: &artifactVersioningMock{originalVersion: "2.0.0", versioningScheme: "semver2"}, appVersion: "1.2.3"}
+		getVersioningArtifact = func(buildTool, buildDescriptorFilePath string, opts *versioning.Options, utils versioning.Utils) (versioning.Artifact, error) {
+			assert.False(t, opts.HelmUpdateAppVersion)
+			return chart, nil
+		}
+		defer func() { getVersioningArtifact = versioning.GetArtifact }()
+
+		config := artifactPrepareVersionOptions{
+			BuildTool:            "maven",
+			HelmUpdateAppVersion: true,
+			VersioningType:       "cloud_noTag",
+			Artifacts:            []map[string]interface{}{{"buildTool": "helm", "filePath": "chart/Chart.yaml", "versioningType": "library"}},
+		}
+		cpe := artifactPrepareVersionCommonPipelineEnvironment{}
+		backend := artifactVersioningMock{originalVersion: "1.2.3", versioningScheme: "maven"}
+		worktree := gitWorktreeMock{}
+
+		err := runArtifactPrepareVersion(&config, &telemetry.CustomData{}, &cpe, &backend, nil, newRepo(), func(r gitRepository) (gitWorktree, error) { return &worktree, nil })
+
+		if assert.NoError(t, err) {
+			assert.Empty(t, chart.newVersion)
+			assert.Equal(t, backend.newVersion, chart.appVersion)
+		}
+	})
+
+	t.Run("success case - library with cloud artifact", func(t *testing.T) {
+		ui := &artifactVersioningMock{originalVersion: "0.1.0", versioningScheme: "semver2"}
+		defer mockArtifacts(map[string]*artifactVersioningMock{"ui/package.json": ui})()
+
+		config := artifactPrepareVersionOptions{
+			BuildTool:      "maven",
+			VersioningType: "library",
+			Artifacts:      []map[string]interface{}{{"buildTool": "npm", "filePath": "ui/package.json", "versioningType": "cloud_noTag"}},
+		}
+		cpe := artifactPrepareVersionCommonPipelineEnvironment{}
+		backend := artifactVersioningMock{originalVersion: "1.2.3", versioningScheme: "maven"}
+		worktree := gitWorktreeMock{}
+		repo := newRepo()
+
+		err := runArtifactPrepareVersion(&config, &telemetry.CustomData{}, &cpe, &backend, nil, repo, func(r gitRepository) (gitWorktree, error) { return &worktree, nil })
+
+		if assert.NoError(t, err) {
+			assert.Empty(t, backend.newVersion)
+			assert.Regexp(t, `^0\.1\.0-\d{14}$`, ui.newVersion, "changed version of artifact must be written")
+			assert.Empty(t, worktree.commitMsg)
+			assert.Empty(t, repo.tag)
+			assert.False(t, repo.pushCalled)
+			assert.Equal(t, "1.2.3", cpe.artifactVersion)
+		}
+	})
+
+	t.Run("success case - versioning template applies to artifacts", func(t *testing.T) {
+		ui := &artifactVersioningMock{originalVersion: "0.1.0", versioningScheme: "semver2"}
+		defer mockArtifacts(map[string]*artifactVersioningMock{"ui/package.json": ui})()
+
+		config := artifactPrepareVersionOptions{
+			BuildTool:          "maven",
+			Password:           "****",
+			Username:           "testUser",
+			VersioningType:     "library",
+			VersioningTemplate: "${version}-${timestamp}",
+			Artifacts:          []map[string]interface{}{{"buildTool": "npm", "filePath": "ui/package.json"}},
+		}
+		cpe := artifactPrepareVersionCommonPipelineEnvironment{}
+		backend := artifactVersioningMock{originalVersion: "1.2.3", versioningScheme: "maven"}
+		worktree := gitWorktreeMock{commitHash: plumbing.ComputeHash(plumbing.CommitObject, []byte{2, 3, 4})}
+
+		err := runArtifactPrepareVersion(&config, &telemetry.CustomData{}, &cpe, &backend, nil, newRepo(), func(r gitRepository) (gitWorktree, error) { return &worktree, nil })
+
+		if assert.NoError(t, err) {
+			assert.Regexp(t, `^1\.2\.3-\d{14}$`, backend.newVersion)
+			assert.Regexp(t, `^0\.1\.0-\d{14}$`, ui.newVersion)
+		}
+	})
+
+	t.Run("error - missing build tool", func(t *testing.T) {
+		config := artifactPrepareVersionOptions{
+			BuildTool:      "maven",
+			VersioningType: "cloud",
+			Artifacts:      []map[string]interface{}{{"filePath": "ui/package.json"}},
+		}
+		backend := artifactVersioningMock{originalVersion: "1.2.3", versioningScheme: "maven"}
+
+		err := runArtifactPrepareVersion(&config, &telemetry.CustomData{}, nil, &backend, nil, newRepo(), nil)
+
+		assert.EqualError(t, err, "buildTool missing for artifact 'ui/package.json'")
+	})
+
+	t.Run("error - version of additional artifact", func(t *testing.T) {
+		defer mockArtifacts(map[string]*artifactVersioningMock{"ui/package.json": {getVersionError: "invalid json"}})()
+		config := artifactPrepareVersionOptions{
+			BuildTool:      "maven",
+			VersioningType: "cloud",
+			Artifacts:      []map[string]interface{}{{"buildTool": "npm", "filePath": "ui/package.json"}},
+		}
+		backend := artifactVersioningMock{originalVersion: "1.2.3", versioningScheme: "maven"}
+
+		err := runArtifactPrepareVersion(&config, &telemetry.CustomData{}, nil, &backend, nil, newRepo(), nil)
+
+		assert.EqualError(t, err, "failed to retrieve version of 'ui/package.json': invalid json")
+	})
+}
+
+func TestGetVersionTagCommits(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	commit := func(message string) plumbing.Hash {
+		hash, err := worktree.Commit(message, &git.CommitOptions{Author: &object.Signature{Name: "me", Email: "me@example.org"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return hash
+	}
+
+	t.Run("no version tag", func(t *testing.T) {
+		commit("feat: initial")
+
+		version, messages, err := getVersionTagCommits(repo, "v")
+
+		assert.NoError(t, err)
+		assert.Empty(t, version)
+		assert.Equal(t, []string{"feat: initial"}, messages)
+	})
+
+	t.Run("commits since latest version tag", func(t *testing.T) {
+		first := commit("fix: first")
+		_, err := repo.CreateTag("v1.9.0", first, nil)
+		assert.NoError(t, err)
+		second := commit("feat: second")
+		_, err = repo.CreateTag("v1.10.0", second, &git.CreateTagOptions{Tagger: &object.Signature{Name: "me", Email: "me@example.org"}, Message: "v1.10.0"})
+		assert.NoError(t, err)
+		_, err = repo.CreateTag("build_2.0.0", second, nil)
+		assert.NoError(t, err)
+		commit("fix: third")
+		commit("docs: fourth")
+
+		version, messages, err := getVersionTagCommits(repo, "v")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "1.10.0", version)
+		assert.ElementsMatch(t, []string{"fix: third", "docs: fourth"}, messages)
+	})
+
+	t.Run("repository mock", func(t *testing.T) {
+		repo := (&gitRepositoryMock{}).withVersionHistory("v2.0.0", "fix: first", "feat: second")
+		repo.tags = append(repo.tags, plumbing.NewHashReference(plumbing.NewTagReferenceName("v1.0.0"), plumbing.ZeroHash))
+
+		version, messages, err := getVersionTagCommits(repo, "v")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "2.0.0", version)
+		assert.Equal(t, []string{"fix: first", "feat: second"}, messages)
+	})
+
+	t.Run("error - tags", func(t *testing.T) {
+		_, _, err := getVersionTagCommits(&gitRepositoryMock{tagsError: "storage error"}, "v")
+
+		assert.EqualError(t, err, "failed to retrieve tags: storage error")
+	})
+}
+
+func TestRunArtifactPrepareVersionSigning(t *testing.T) {
+	entity, err := openpgp.NewEntity("Project Piper", "", "piper@example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var privateKey, publicKey bytes.Buffer
+	w, _ := armor.Encode(&privateKey, openpgp.PrivateKeyType, nil)
+	entity.SerializePrivate(w, nil)
+	w.Close()
+	w, _ = armor.Encode(&publicKey, openpgp.PublicKeyType, nil)
+	entity.Serialize(w)
+	w.Close()
+
+	t.Run("success case - gpg signed commit and tag", func(t *testing.T) {
+		repo, err := git.Init(memory.NewStorage(), memfs.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+		worktree, _ := repo.Worktree()
+		if _, err := worktree.Commit("feat: signing", &git.CommitOptions{Author: &object.Signature{Name: "me", Email: "me@example.org"}}); err != nil {
+			t.Fatal(err)
+		}
+
+		utils := &artifactPrepareVersionMockUtils{ExecMockRunner: &mock.ExecMockRunner{}, FilesMock: &mock.FilesMock{}}
+		utils.AddFile("signing.key", privateKey.Bytes())
+		config := artifactPrepareVersionOptions{
+			BuildTool:      "maven",
+			CommitUserName: "Project Piper",
+			Push:           false,
+			SigningFormat:  "gpg",
+			SigningKey:     "signing.key",
+			TagPrefix:      "v",
+			VersioningType: "semantic",
+		}
+		cpe := artifactPrepareVersionCommonPipelineEnvironment{}
+		artifact := artifactVersioningMock{originalVersion: "1.2.3", versioningScheme: "maven"}
+
+		err = runArtifactPrepareVersion(&config, &telemetry.CustomData{}, &cpe, &artifact, utils, repo, getGitWorktree)
+
+		if assert.NoError(t, err) {
+			head, _ := repo.Head()
+			assert.Equal(t, head.Hash().String(), cpe.git.commitID)
+			commit, err := repo.CommitObject(head.Hash())
+			assert.NoError(t, err)
+			assert.Equal(t, "update version 1.3.0", commit.Message)
+			_, err = commit.Verify(publicKey.String())
+			assert.NoError(t, err, "commit signature verification failed")
+
+			tagRef, err := repo.Tag("v1.3.0")
+			assert.NoError(t, err)
+			tag, err := repo.TagObject(tagRef.Hash())
+			if assert.NoError(t, err, "annotated tag expected") {
+				assert.Equal(t, head.Hash(), tag.Target)
+				_, err = tag.Verify(publicKey.String())
+				assert.NoError(t, err, "tag signature verification failed")
+			}
+		}
+	})
+
+	t.Run("error - signing key not available", func(t *testing.T) {
+		utils := &artifactPrepareVersionMockUtils{ExecMockRunner: &mock.ExecMockRunner{}, FilesMock: &mock.FilesMock{}}
+		config := artifactPrepareVersionOptions{BuildTool: "maven", SigningKey: "signing.key", VersioningType: "semantic"}
+		artifact := artifactVersioningMock{originalVersion: "1.2.3", versioningScheme: "maven"}
+
+		err := runArtifactPrepareVersion(&config, &telemetry.CustomData{}, nil, &artifact, utils, &gitRepositoryMock{}, nil)
+
+		assert.EqualError(t, err, "failed to read signing key 'signing.key': could not read 'signing.key'")
+	})
+
+	t.Run("error - signing format", func(t *testing.T) {
+		utils := &artifactPrepareVersionMockUtils{ExecMockRunner: &mock.ExecMockRunner{}, FilesMock: &mock.FilesMock{}}
+		utils.AddFile("signing.key", privateKey.Bytes())
+		config := artifactPrepareVersionOptions{SigningFormat: "x509", SigningKey: "signing.key"}
+
+		_, err := getVersionSigner(&config, utils)
+
+		assert.EqualError(t, err, "failed to create signer: signing format 'x509' not supported")
+	})
+
+	t.Run("error - signing not available", func(t *testing.T) {
+		signer, err := gitUtils.NewGPGSigner(privateKey.String(), "")
+		assert.NoError(t, err)
+		config := artifactPrepareVersionOptions{TagPrefix: "v"}
+		repo := gitRepositoryMock{}
+		worktree := gitWorktreeMock{commitHash: plumbing.ComputeHash(plumbing.CommitObject, []byte{1, 2, 3})}
+
+		_, _, err = commitAndTag(&config, "1.2.3", &repo, &worktree, time.Now(), signer)
+
+		assert.EqualError(t, err, "failed to sign commit of new version: commit signing not available")
+		assert.Empty(t, repo.tag)
+	})
 }
 
 func TestVersioningTemplate(t *testing.T) {
@@ -423,6 +977,7 @@ func TestVersioningTemplate(t *testing.T) {
 		{scheme: "maven", expected: "{{.Version}}{{if .Timestamp}}-{{.Timestamp}}{{if .CommitID}}_{{.CommitID}}{{end}}{{end}}"},
 		{scheme: "semver2", expected: "{{.Version}}{{if .Timestamp}}-{{.Timestamp}}{{if .CommitID}}+{{.CommitID}}{{end}}{{end}}"},
 		{scheme: "pep440", expected: "{{.Version}}{{if .Timestamp}}.{{.Timestamp}}{{if .CommitID}}+{{.CommitID}}{{end}}{{end}}"},
+		{scheme: "rubygems", expected: "{{.Version}}{{if .Timestamp}}.{{.Timestamp}}{{if .CommitID}}.{{.CommitID}}{{end}}{{end}}"},
 		{scheme: "notSupported", expected: "", expectedErr: "versioning scheme 'notSupported' not supported"},
 	}
 
@@ -483,7 +1038,7 @@ func TestPushChanges(t *testing.T) {
 		repo := gitRepositoryMock{remote: remote}
 		worktree := gitWorktreeMock{commitHash: plumbing.ComputeHash(plumbing.CommitObject, []byte{1, 2, 3})}
 
-		commitID, err := pushChanges(&config, newVersion, &repo, &worktree, testTime)
+		commitID, err := pushChanges(&config, newVersion, &repo, &worktree, testTime, nil)
 		assert.NoError(t, err)
 		assert.Equal(t, "428ecf70bc22df0ba3dcf194b5ce53e769abab07", commitID)
 		assert.Equal(t, "update version 1.2.3", worktree.commitMsg)
@@ -500,7 +1055,7 @@ func TestPushChanges(t *testing.T) {
 
 		originalSSHAgentAuth := sshAgentAuth
 		sshAgentAuth = func(u string) (*ssh.PublicKeysCallback, error) { return &ssh.PublicKeysCallback{}, nil }
-		commitID, err := pushChanges(&config, newVersion, &repo, &worktree, testTime)
+		commitID, err := pushChanges(&config, newVersion, &repo, &worktree, testTime, nil)
 		sshAgentAuth = originalSSHAgentAuth
 
 		assert.NoError(t, err)
@@ -522,7 +1077,7 @@ func TestPushChanges(t *testing.T) {
 
 		originalSSHAgentAuth := sshAgentAuth
 		sshAgentAuth = func(u string) (*ssh.PublicKeysCallback, error) { return &ssh.PublicKeysCallback{}, nil }
-		commitID, err := pushChanges(&config, newVersion, &repo, &worktree, testTime)
+		commitID, err := pushChanges(&config, newVersion, &repo, &worktree, testTime, nil)
 		sshAgentAuth = originalSSHAgentAuth
 
 		assert.NoError(t, err)
@@ -535,7 +1090,7 @@ func TestPushChanges(t *testing.T) {
 		repo := gitRepositoryMock{}
 		worktree := gitWorktreeMock{commitError: "commit error", commitHash: plumbing.ComputeHash(plumbing.CommitObject, []byte{1, 2, 3})}
 
-		commitID, err := pushChanges(&config, newVersion, &repo, &worktree, testTime)
+		commitID, err := pushChanges(&config, newVersion, &repo, &worktree, testTime, nil)
 		assert.Equal(t, "0000000000000000000000000000000000000000", commitID)
 		assert.EqualError(t, err, "failed to commit new version: commit error")
 	})
@@ -545,7 +1100,7 @@ func TestPushChanges(t *testing.T) {
 		repo := gitRepositoryMock{tagError: "tag error"}
 		worktree := gitWorktreeMock{commitHash: plumbing.ComputeHash(plumbing.CommitObject, []byte{1, 2, 3})}
 
-		commitID, err := pushChanges(&config, newVersion, &repo, &worktree, testTime)
+		commitID, err := pushChanges(&config, newVersion, &repo, &worktree, testTime, nil)
 		assert.Equal(t, "428ecf70bc22df0ba3dcf194b5ce53e769abab07", commitID)
 		assert.EqualError(t, err, "tag error")
 	})
@@ -555,7 +1110,7 @@ func TestPushChanges(t *testing.T) {
 		repo := gitRepositoryMock{}
 		worktree := gitWorktreeMock{commitHash: plumbing.ComputeHash(plumbing.CommitObject, []byte{1, 2, 3})}
 
-		commitID, err := pushChanges(&config, newVersion, &repo, &worktree, testTime)
+		commitID, err := pushChanges(&config, newVersion, &repo, &worktree, testTime, nil)
 		assert.Equal(t, "428ecf70bc22df0ba3dcf194b5ce53e769abab07", commitID)
 		assert.EqualError(t, err, "no remote url maintained")
 	})
@@ -584,7 +1139,7 @@ func TestPushChanges(t *testing.T) {
 
 		for _, test := range tt {
 			sshAgentAuth = test.sshAgentAuth
-			commitID, err := pushChanges(&config, newVersion, &test.repo, &worktree, testTime)
+			commitID, err := pushChanges(&config, newVersion, &test.repo, &worktree, testTime, nil)
 			sshAgentAuth = originalSSHAgentAuth
 
 			assert.Equal(t, "428ecf70bc22df0ba3dcf194b5ce53e769abab07", commitID)
@@ -597,7 +1152,7 @@ func TestPushChanges(t *testing.T) {
 		repo := gitRepositoryMock{remote: remote, pushError: "push error"}
 		worktree := gitWorktreeMock{commitHash: plumbing.ComputeHash(plumbing.CommitObject, []byte{1, 2, 3})}
 
-		commitID, err := pushChanges(&config, newVersion, &repo, &worktree, testTime)
+		commitID, err := pushChanges(&config, newVersion, &repo, &worktree, testTime, nil)
 		assert.Equal(t, "428ecf70bc22df0ba3dcf194b5ce53e769abab07", commitID)
 		assert.EqualError(t, err, "push error")
 	})
@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/changelog"
+	"github.com/SAP/jenkins-library/pkg/git"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/google/go-github/v32/github"
+	"github.com/pkg/errors"
+
+	piperGithub "github.com/SAP/jenkins-library/pkg/github"
+)
+
+// changelogGit provides the git history the changelog is created from
+type changelogGit interface {
+	Commits(from, to string) ([]changelog.Commit, error)
+	LatestTag(tagPrefix, currentVersion string) (*changelog.Tag, error)
+}
+
+type changelogCreateUtils interface {
+	changelogGit
+
+	FileExists(filename string) (bool, error)
+	FileRead(path string) ([]byte, error)
+	FileWrite(path string, content []byte, perm os.FileMode) error
+}
+
+// changelogGitRepository reads the git history of the repository located at path
+type changelogGitRepository struct {
+	path string
+}
+
+func (c *changelogGitRepository) Commits(from, to string) ([]changelog.Commit, error) {
+	repo, err := git.PlainOpen(c.path)
+	if err != nil {
+		return nil, err
+	}
+	return changelog.Commits(repo, from, to)
+}
+
+func (c *changelogGitRepository) LatestTag(tagPrefix, currentVersion string) (*changelog.Tag, error) {
+	repo, err := git.PlainOpen(c.path)
+	if err != nil {
+		return nil, err
+	}
+	return changelog.LatestTag(repo, tagPrefix, currentVersion)
+}
+
+type changelogCreateUtilsBundle struct {
+	*piperutils.Files
+	*changelogGitRepository
+}
+
+func newChangelogCreateUtils() changelogCreateUtils {
+	return &changelogCreateUtilsBundle{
+		Files:                  &piperutils.Files{},
+		changelogGitRepository: &changelogGitRepository{path: "."},
+	}
+}
+
+// changelogGitHub describes the GitHub repository pull requests and issues are retrieved from
+type changelogGitHub struct {
+	owner         string
+	repository    string
+	serverURL     string
+	excludeLabels []string
+	pullRequests  changelog.PullRequestClient
+	issues        changelog.IssueClient
+}
+
+func changelogCreate(config changelogCreateOptions, telemetryData *telemetry.CustomData) {
+	utils := newChangelogCreateUtils()
+
+	ctx := context.Background()
+	gitHub := changelogGitHub{
+		owner:         config.Owner,
+		repository:    config.Repository,
+		serverURL:     config.ServerURL,
+		excludeLabels: config.ExcludeLabels,
+	}
+	if len(config.Token) > 0 {
+		var client *github.Client
+		var err error
+		ctx, client, err = piperGithub.NewClient(config.Token, config.APIURL, "")
+		if err != nil {
+			log.Entry().WithError(err).Fatal("Failed to get GitHub client")
+		}
+		gitHub.pullRequests = client.PullRequests
+		gitHub.issues = client.Issues
+	}
+
+	err := runChangelogCreate(ctx, &config, utils, gitHub)
+	if err != nil {
+		log.Entry().WithError(err).Fatal("step execution failed")
+	}
+}
+
+func runChangelogCreate(ctx context.Context, config *changelogCreateOptions, utils changelogCreateUtils, gitHub changelogGitHub) error {
+	previous, err := utils.LatestTag(config.TagPrefix, config.Version)
+	if err != nil {
+		return errors.Wrap(err, "failed to determine previous version")
+	}
+	from := ""
+	if previous != nil {
+		log.Entry().Infof("Creating changelog for changes since '%v'", previous.Name)
+		from = previous.Name
+	} else {
+		log.Entry().Info("No previous version found, creating changelog for all changes")
+		previous = &changelog.Tag{}
+	}
+
+	commits, err := utils.Commits(from, config.CommitID)
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve commits")
+	}
+
+	entries, err := buildChangelog(ctx, config.Version, previous.Date, commits, gitHub, config.IncludeOtherChanges)
+	if err != nil {
+		return err
+	}
+	if len(previous.Name) > 0 && len(gitHub.owner) > 0 && len(gitHub.repository) > 0 {
+		entries.CompareURL = fmt.Sprintf("%v/%v/%v/compare/%v...%v", strings.TrimSuffix(gitHub.serverURL, "/"), gitHub.owner, gitHub.repository, previous.Name, config.TagPrefix+config.Version)
+	}
+
+	entry, err := entries.Render(config.Template)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return errors.Wrap(err, "failed to render changelog")
+	}
+
+	content := ""
+	if exists, _ := utils.FileExists(config.FilePath); exists {
+		existing, err := utils.FileRead(config.FilePath)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read changelog '%v'", config.FilePath)
+		}
+		content = string(existing)
+	}
+	content, updated := changelog.UpdateFile(content, entry, config.Version)
+	if !updated {
+		log.Entry().Infof("Changelog '%v' already contains version '%v'", config.FilePath, config.Version)
+		return nil
+	}
+	if err := utils.FileWrite(config.FilePath, []byte(content), 0644); err != nil {
+		return errors.Wrapf(err, "failed to write changelog '%v'", config.FilePath)
+	}
+	log.Entry().Infof("Changelog '%v' updated for version '%v'", config.FilePath, config.Version)
+	return nil
+}
+
+// buildChangelog combines the commits with the pull requests and issues of the GitHub repository (if available)
+func buildChangelog(ctx context.Context, version string, since time.Time, commits []changelog.Commit, gitHub changelogGitHub, includeOtherChanges bool) (changelog.Changelog, error) {
+	options := changelog.Options{IncludeOtherChanges: includeOtherChanges}
+	pullRequests, issues := []changelog.Item{}, []changelog.Item{}
+
+	if len(gitHub.owner) > 0 && len(gitHub.repository) > 0 {
+		options.CommitURL = fmt.Sprintf("%v/%v/%v/commit/", strings.TrimSuffix(gitHub.serverURL, "/"), gitHub.owner, gitHub.repository)
+
+		var err error
+		if gitHub.pullRequests != nil {
+			pullRequests, err = changelog.MergedPullRequests(ctx, gitHub.pullRequests, gitHub.owner, gitHub.repository, since, gitHub.excludeLabels)
+			if err != nil {
+				log.SetErrorCategory(log.ErrorService)
+				return changelog.Changelog{}, err
+			}
+		}
+		if gitHub.issues != nil {
+			issues, err = changelog.ClosedIssues(ctx, gitHub.issues, gitHub.owner, gitHub.repository, since, gitHub.excludeLabels)
+			if err != nil {
+				log.SetErrorCategory(log.ErrorService)
+				return changelog.Changelog{}, err
+			}
+		}
+	}
+	log.Entry().Debugf("Changelog based on %v commits, %v pull requests and %v issues", len(commits), len(pullRequests), len(issues))
+
+	return changelog.Build(version, time.Now(), commits, pullRequests, issues, options), nil
+}
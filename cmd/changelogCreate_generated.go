@@ -0,0 +1,267 @@
+// Code generated by piper's step-generator. DO NOT EDIT.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/spf13/cobra"
+)
+
+type changelogCreateOptions struct {
+	Version             string   `json:"version,omitempty"`
+	FilePath            string   `json:"filePath,omitempty"`
+	Template            string   `json:"template,omitempty"`
+	TagPrefix           string   `json:"tagPrefix,omitempty"`
+	CommitID            string   `json:"commitId,omitempty"`
+	IncludeOtherChanges bool     `json:"includeOtherChanges,omitempty"`
+	ExcludeLabels       []string `json:"excludeLabels,omitempty"`
+	Owner               string   `json:"owner,omitempty"`
+	Repository          string   `json:"repository,omitempty"`
+	APIURL              string   `json:"apiUrl,omitempty"`
+	ServerURL           string   `json:"serverUrl,omitempty"`
+	Token               string   `json:"token,omitempty"`
+}
+
+// ChangelogCreateCommand Creates a changelog entry from the git history as well as GitHub pull requests and issues.
+func ChangelogCreateCommand() *cobra.Command {
+	const STEP_NAME = "changelogCreate"
+
+	metadata := changelogCreateMetadata()
+	var stepConfig changelogCreateOptions
+	var startTime time.Time
+
+	var createChangelogCreateCmd = &cobra.Command{
+		Use:   STEP_NAME,
+		Short: "Creates a changelog entry from the git history as well as GitHub pull requests and issues.",
+		Long: `This step creates a changelog entry for a version and adds it to a changelog file (e.g. ` + "`" + `CHANGELOG.md` + "`" + `).
+
+The entry contains all changes since the previous version, i.e. since the git tag with the highest semantic version (using the ` + "`" + `tagPrefix` + "`" + `):
+
+* Commits following the [Conventional Commits](https://www.conventionalcommits.org) format are grouped by their type (` + "`" + `feat` + "`" + `, ` + "`" + `fix` + "`" + `, ` + "`" + `perf` + "`" + `).
+  Breaking changes (e.g. ` + "`" + `feat!: ...` + "`" + ` or a ` + "`" + `BREAKING CHANGE:` + "`" + ` footer) are listed separately.
+  Commits of other types like ` + "`" + `chore` + "`" + ` or ` + "`" + `docs` + "`" + ` are not listed.
+* In case a GitHub token is available, merged pull requests are listed instead of their commits. They are grouped by their labels (e.g. ` + "`" + `bug` + "`" + `, ` + "`" + `enhancement` + "`" + `) or by the Conventional Commits type of their title.
+  In addition closed issues are listed.
+
+The entry is rendered as Markdown using a [Go template](https://golang.org/pkg/text/template/) which can be adapted via ` + "`" + `template` + "`" + `.
+The following fields are available in the template:
+
+* ` + "`" + `.Version` + "`" + ` - the version
+* ` + "`" + `.Date` + "`" + ` - the date of the changelog creation (` + "`" + `YYYY-MM-DD` + "`" + `)
+* ` + "`" + `.CompareURL` + "`" + ` - the link to the comparison with the previous version on GitHub
+* ` + "`" + `.Sections` + "`" + ` - list of sections with the fields ` + "`" + `.Title` + "`" + ` and ` + "`" + `.Entries` + "`" + `. Each entry provides ` + "`" + `.Text` + "`" + `, ` + "`" + `.Reference` + "`" + ` and ` + "`" + `.URL` + "`" + `.
+
+In case the changelog file already contains an entry for the version, it remains unchanged.
+The changelog file is not committed, this can for example be done via ` + "`" + `artifactPrepareVersion` + "`" + `.`,
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			startTime = time.Now()
+			log.SetStepName(STEP_NAME)
+			log.SetVerbose(GeneralConfig.Verbose)
+
+			path, _ := os.Getwd()
+			fatalHook := &log.FatalHook{CorrelationID: GeneralConfig.CorrelationID, Path: path}
+			log.RegisterHook(fatalHook)
+
+			err := PrepareConfig(cmd, &metadata, STEP_NAME, &stepConfig, config.OpenPiperFile)
+			if err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+			log.RegisterSecret(stepConfig.Token)
+
+			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
+				sentryHook := log.NewSentryHook(GeneralConfig.HookConfig.SentryConfig.Dsn, GeneralConfig.CorrelationID)
+				log.RegisterHook(&sentryHook)
+			}
+
+			return nil
+		},
+		Run: func(_ *cobra.Command, _ []string) {
+			telemetryData := telemetry.CustomData{}
+			telemetryData.ErrorCode = "1"
+			handler := func() {
+				config.RemoveVaultSecretFiles()
+				telemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
+				telemetryData.ErrorCategory = log.GetErrorCategory().String()
+				telemetry.Send(&telemetryData)
+			}
+			log.DeferExitHandler(handler)
+			defer handler()
+			telemetry.Initialize(GeneralConfig.NoTelemetry, STEP_NAME)
+			changelogCreate(stepConfig, &telemetryData)
+			telemetryData.ErrorCode = "0"
+			log.Entry().Info("SUCCESS")
+		},
+	}
+
+	addChangelogCreateFlags(createChangelogCreateCmd, &stepConfig)
+	return createChangelogCreateCmd
+}
+
+func addChangelogCreateFlags(cmd *cobra.Command, stepConfig *changelogCreateOptions) {
+	cmd.Flags().StringVar(&stepConfig.Version, "version", os.Getenv("PIPER_version"), "The version the changelog entry is created for.")
+	cmd.Flags().StringVar(&stepConfig.FilePath, "filePath", `CHANGELOG.md`, "Path to the changelog file which is updated.")
+	cmd.Flags().StringVar(&stepConfig.Template, "template", os.Getenv("PIPER_template"), "Go template used for rendering the changelog entry. In case it is not provided, a default Markdown layout is used.")
+	cmd.Flags().StringVar(&stepConfig.TagPrefix, "tagPrefix", os.Getenv("PIPER_tagPrefix"), "Prefix of the git tags of previous versions, e.g. `v` or `build_`.")
+	cmd.Flags().StringVar(&stepConfig.CommitID, "commitId", `HEAD`, "The git commit (or any other revision) up to which changes are considered.")
+	cmd.Flags().BoolVar(&stepConfig.IncludeOtherChanges, "includeOtherChanges", true, "If set to `true`, pull requests and commits which do not belong to any group (e.g. commits not following the Conventional Commits format) are listed as 'Other Changes'.")
+	cmd.Flags().StringSliceVar(&stepConfig.ExcludeLabels, "excludeLabels", []string{}, "Pull requests and issues with one of these labels are not listed.")
+	cmd.Flags().StringVar(&stepConfig.Owner, "owner", os.Getenv("PIPER_owner"), "Name of the GitHub organization.")
+	cmd.Flags().StringVar(&stepConfig.Repository, "repository", os.Getenv("PIPER_repository"), "Name of the GitHub repository.")
+	cmd.Flags().StringVar(&stepConfig.APIURL, "apiUrl", `https://api.github.com`, "Set the GitHub API url.")
+	cmd.Flags().StringVar(&stepConfig.ServerURL, "serverUrl", `https://github.com`, "GitHub server url for end-user access.")
+	cmd.Flags().StringVar(&stepConfig.Token, "token", os.Getenv("PIPER_token"), "GitHub personal access token as per https://help.github.com/en/github/authenticating-to-github/creating-a-personal-access-token-for-the-command-line. Pull requests and issues are only considered in case the token is provided.")
+
+	cmd.MarkFlagRequired("version")
+}
+
+// retrieve step metadata
+func changelogCreateMetadata() config.StepData {
+	var theMetaData = config.StepData{
+		Metadata: config.StepMetadata{
+			Name:        "changelogCreate",
+			Aliases:     []config.Alias{},
+			Description: "Creates a changelog entry from the git history as well as GitHub pull requests and issues.",
+		},
+		Spec: config.StepSpec{
+			Inputs: config.StepInputs{
+				Parameters: []config.StepParameters{
+					{
+						Name: "version",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "artifactVersion",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: true,
+						Aliases:   []config.Alias{},
+					},
+					{
+						Name:        "filePath",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "template",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "tagPrefix",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name: "commitId",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "git/commitId",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+					},
+					{
+						Name:        "includeOtherChanges",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "excludeLabels",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name: "owner",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "github/owner",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{{Name: "githubOrg"}},
+					},
+					{
+						Name: "repository",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "github/repository",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{{Name: "githubRepo"}},
+					},
+					{
+						Name:        "apiUrl",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{{Name: "githubApiUrl"}},
+					},
+					{
+						Name:        "serverUrl",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{{Name: "githubServerUrl"}},
+					},
+					{
+						Name: "token",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "githubTokenCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:  "",
+								Paths: []string{"$(vaultPath)/github", "$(vaultBasePath)/$(vaultPipelineName)/github", "$(vaultBasePath)/GROUP-SECRETS/github"},
+								Type:  "vaultSecret",
+							},
+						},
+						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{{Name: "githubToken"}, {Name: "access_token"}},
+					},
+				},
+			},
+		},
+	}
+	return theMetaData
+}
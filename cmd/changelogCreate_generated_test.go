@@ -0,0 +1,17 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChangelogCreateCommand(t *testing.T) {
+	t.Parallel()
+
+	testCmd := ChangelogCreateCommand()
+
+	// only high level testing performed - details are tested in step generation procedure
+	assert.Equal(t, "changelogCreate", testCmd.Use, "command name incorrect")
+
+}
@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/changelog"
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/google/go-github/v32/github"
+	"github.com/stretchr/testify/assert"
+)
+
+type changelogGitMock struct {
+	commits    []changelog.Commit
+	commitsErr error
+	from       string
+	to         string
+	tag        *changelog.Tag
+	tagErr     error
+}
+
+func (c *changelogGitMock) Commits(from, to string) ([]changelog.Commit, error) {
+	c.from = from
+	c.to = to
+	return c.commits, c.commitsErr
+}
+
+func (c *changelogGitMock) LatestTag(tagPrefix, currentVersion string) (*changelog.Tag, error) {
+	return c.tag, c.tagErr
+}
+
+type changelogCreateMockUtils struct {
+	*mock.FilesMock
+	*changelogGitMock
+}
+
+func newChangelogCreateTestsUtils() changelogCreateMockUtils {
+	utils := changelogCreateMockUtils{
+		FilesMock: &mock.FilesMock{},
+		changelogGitMock: &changelogGitMock{commits: []changelog.Commit{
+			changelog.ParseCommit("1111111111", "feat: add flag"),
+			changelog.ParseCommit("2222222222", "fix: crash on start (#12)"),
+			changelog.ParseCommit("3333333333", "chore: update dependencies"),
+		}},
+	}
+	return utils
+}
+
+type changelogPullRequestMock struct {
+	pullRequests []*github.PullRequest
+	err          error
+}
+
+func (c *changelogPullRequestMock) List(ctx context.Context, owner string, repo string, opts *github.PullRequestListOptions) ([]*github.PullRequest, *github.Response, error) {
+	return c.pullRequests, nil, c.err
+}
+
+func TestRunChangelogCreate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("new changelog from git history", func(t *testing.T) {
+		t.Parallel()
+		config := changelogCreateOptions{Version: "1.0.0", FilePath: "CHANGELOG.md", CommitID: "HEAD"}
+		utils := newChangelogCreateTestsUtils()
+
+		err := runChangelogCreate(context.Background(), &config, utils, changelogGitHub{})
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, "", utils.from)
+			assert.Equal(t, "HEAD", utils.to)
+			content, err := utils.FileRead("CHANGELOG.md")
+			assert.NoError(t, err)
+			assert.Regexp(t, `^# Changelog\n\n## 1\.0\.0 \(\d{4}-\d{2}-\d{2}\)\n\n### Features\n\n\* add flag\n\n### Bug Fixes\n\n\* crash on start \(#12\)\n$`, string(content))
+		}
+	})
+
+	t.Run("existing changelog with GitHub data", func(t *testing.T) {
+		t.Parallel()
+		config := changelogCreateOptions{Version: "1.1.0", FilePath: "CHANGELOG.md", CommitID: "abcdef", TagPrefix: "v", Template: "## {{.Version}}\n" + changelog.SectionsTemplate + "\n{{.CompareURL}}"}
+		utils := newChangelogCreateTestsUtils()
+		utils.tag = &changelog.Tag{Name: "v1.0.0", Date: time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)}
+		utils.AddFile("CHANGELOG.md", []byte("# Changelog\n\n## 1.0.0\n\n* initial\n"))
+		mergedAt := time.Date(2021, 3, 2, 0, 0, 0, 0, time.UTC)
+		gitHub := changelogGitHub{
+			owner:      "o",
+			repository: "r",
+			serverURL:  "https://github.com/",
+			pullRequests: &changelogPullRequestMock{pullRequests: []*github.PullRequest{
+				{Number: github.Int(12), Title: github.String("Fix crash on start"), HTMLURL: github.String("https://github.com/o/r/pull/12"), UpdatedAt: &mergedAt, MergedAt: &mergedAt, Labels: []*github.Label{{Name: github.String("bug")}}},
+			}},
+			issues: &ghICMock{issues: []*github.Issue{
+				{Number: github.Int(10), Title: github.String("Crash on start"), HTMLURL: github.String("https://github.com/o/r/issues/10"), ClosedAt: &mergedAt},
+			}},
+		}
+
+		err := runChangelogCreate(context.Background(), &config, utils, gitHub)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, "v1.0.0", utils.from)
+			content, err := utils.FileRead("CHANGELOG.md")
+			assert.NoError(t, err)
+			assert.Equal(t, `# Changelog
+
+## 1.1.0
+
+### Features
+
+* add flag ([1111111](https://github.com/o/r/commit/1111111111))
+
+### Bug Fixes
+
+* Fix crash on start ([#12](https://github.com/o/r/pull/12))
+
+### Closed Issues
+
+* Crash on start ([#10](https://github.com/o/r/issues/10))
+
+https://github.com/o/r/compare/v1.0.0...v1.1.0
+
+## 1.0.0
+
+* initial
+`, string(content))
+		}
+	})
+
+	t.Run("version already contained", func(t *testing.T) {
+		t.Parallel()
+		config := changelogCreateOptions{Version: "1.0.0", FilePath: "CHANGELOG.md"}
+		utils := newChangelogCreateTestsUtils()
+		utils.AddFile("CHANGELOG.md", []byte("# Changelog\n\n## 1.0.0\n\n* initial\n"))
+
+		err := runChangelogCreate(context.Background(), &config, utils, changelogGitHub{})
+
+		if assert.NoError(t, err) {
+			content, _ := utils.FileRead("CHANGELOG.md")
+			assert.Equal(t, "# Changelog\n\n## 1.0.0\n\n* initial\n", string(content))
+		}
+	})
+
+	t.Run("error retrieving tags", func(t *testing.T) {
+		t.Parallel()
+		config := changelogCreateOptions{Version: "1.0.0", FilePath: "CHANGELOG.md"}
+		utils := newChangelogCreateTestsUtils()
+		utils.tagErr = fmt.Errorf("repository does not exist")
+
+		err := runChangelogCreate(context.Background(), &config, utils, changelogGitHub{})
+
+		assert.EqualError(t, err, "failed to determine previous version: repository does not exist")
+	})
+
+	t.Run("error retrieving pull requests", func(t *testing.T) {
+		t.Parallel()
+		config := changelogCreateOptions{Version: "1.0.0", FilePath: "CHANGELOG.md"}
+		utils := newChangelogCreateTestsUtils()
+		gitHub := changelogGitHub{owner: "o", repository: "r", pullRequests: &changelogPullRequestMock{err: fmt.Errorf("unauthorized")}}
+
+		err := runChangelogCreate(context.Background(), &config, utils, gitHub)
+
+		assert.EqualError(t, err, "failed to retrieve pull requests of o/r: unauthorized")
+		assert.False(t, utils.HasWrittenFile("CHANGELOG.md"))
+	})
+
+	t.Run("invalid template", func(t *testing.T) {
+		t.Parallel()
+		config := changelogCreateOptions{Version: "1.0.0", FilePath: "CHANGELOG.md", Template: "{{.Unknown"}
+		utils := newChangelogCreateTestsUtils()
+
+		err := runChangelogCreate(context.Background(), &config, utils, changelogGitHub{})
+
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "failed to render changelog")
+		}
+	})
+}
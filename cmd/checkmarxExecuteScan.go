@@ -20,6 +20,7 @@ import (
 	piperHttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/reporting"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
 	"github.com/bmatcuk/doublestar"
 	"github.com/pkg/errors"
@@ -235,6 +236,11 @@ func verifyCxProjectCompliance(config checkmarxExecuteScanOptions, sys checkmarx
 		insecure = enforceThresholds(config, results)
 	}
 
+	scanReport := createCxScanReport(results, insecure)
+	if err := reporting.WriteJSONReport(scanReport, workspaceFiles{workspace: workspace}); err != nil {
+		log.Entry().WithError(err).Warning("failed to write scan report")
+	}
+
 	if insecure {
 		if config.VulnerabilityThresholdResult == "FAILURE" {
 			log.SetErrorCategory(log.ErrorCompliance)
@@ -247,6 +253,42 @@ func verifyCxProjectCompliance(config checkmarxExecuteScanOptions, sys checkmarx
 	return nil
 }
 
+// workspaceFiles writes files relative to the workspace
+type workspaceFiles struct {
+	piperutils.Files
+	workspace string
+}
+
+func (w workspaceFiles) FileWrite(path string, content []byte, perm os.FileMode) error {
+	return w.Files.FileWrite(filepath.Join(w.workspace, path), content, perm)
+}
+
+func (w workspaceFiles) MkdirAll(path string, perm os.FileMode) error {
+	return w.Files.MkdirAll(filepath.Join(w.workspace, path), perm)
+}
+
+func createCxScanReport(results map[string]interface{}, insecure bool) reporting.ScanReport {
+	scanReport := reporting.ScanReport{
+		StepName: "checkmarxExecuteScan",
+		Title:    "Checkmarx SAST Report",
+		Subheaders: []reporting.Subheader{
+			{Description: "Project name", Details: fmt.Sprint(results["ProjectName"])},
+		},
+		FurtherInfo:    fmt.Sprint(results["DeepLink"]),
+		ReportTime:     time.Now(),
+		SuccessfulScan: !insecure,
+	}
+	for _, severity := range []string{"High", "Medium", "Low"} {
+		if counts, ok := results[severity].(map[string]int); ok {
+			scanReport.Overview = append(scanReport.Overview, reporting.OverviewRow{Description: fmt.Sprintf("%v issues (not false positive)", severity), Details: fmt.Sprint(counts["NotFalsePositive"])})
+		}
+	}
+	if findings, ok := results["Findings"].([]reporting.Finding); ok {
+		scanReport.Findings = findings
+	}
+	return scanReport
+}
+
 func createReportName(workspace, reportFileNameTemplate string) string {
 	regExpFileName := regexp.MustCompile(`[^\w\d]`)
 	timeStamp, _ := time.Now().Local().MarshalText()
@@ -550,6 +592,7 @@ func getDetailedResults(sys checkmarx.System, reportFileName string, scanID int)
 		resultMap["Medium"] = map[string]int{}
 		resultMap["Low"] = map[string]int{}
 		resultMap["Information"] = map[string]int{}
+		findings := []reporting.Finding{}
 		for _, query := range xmlResult.Queries {
 			for _, result := range query.Results {
 				key := result.Severity
@@ -585,9 +628,13 @@ func getDetailedResults(sys checkmarx.System, reportFileName string, scanID int)
 
 				if result.FalsePositive != "True" {
 					submap["NotFalsePositive"]++
+					if auditState != "NotExploitable" && len(result.FileName) > 0 {
+						findings = append(findings, reporting.Finding{Rule: query.Name, Severity: result.Severity, File: result.FileName, Line: result.Line, Link: result.DeepLink})
+					}
 				}
 			}
 		}
+		resultMap["Findings"] = findings
 	}
 	return resultMap, nil
 }
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/SAP/jenkins-library/pkg/checkmarx"
+	"github.com/SAP/jenkins-library/pkg/reporting"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -314,6 +315,7 @@ func TestGetDetailedResults(t *testing.T) {
 		assert.Equal(t, 2, result["High"].(map[string]int)["NotFalsePositive"], "Number of High NotFalsePositive issues incorrect")
 		assert.Equal(t, 1, result["Medium"].(map[string]int)["Issues"], "Number of Medium issues incorrect")
 		assert.Equal(t, 0, result["Medium"].(map[string]int)["NotFalsePositive"], "Number of Medium NotFalsePositive issues incorrect")
+		assert.Equal(t, []reporting.Finding{{Rule: "SQL_Injection", Severity: "High", File: "bookstore/Login.cs", Line: 179, Link: "http://WIN2K12-TEMP/CxWebClient/ViewerMain.aspx?scanid=1000005&projectid=2&pathid=2"}}, result["Findings"])
 	})
 }
 
@@ -335,6 +337,8 @@ func TestRunScan(t *testing.T) {
 	assert.Equal(t, true, sys.isPublic, "isPublic has wrong value")
 	assert.Equal(t, true, sys.forceScan, "forceScan has wrong value")
 	assert.Equal(t, true, sys.scanProjectCalled, "ScanProject was not invoked")
+	scanReports, _ := filepath.Glob(filepath.Join(workspace, reporting.StepReportDirectory, "checkmarxExecuteScan_*.json"))
+	assert.Len(t, scanReports, 1, "scan report was not written")
 }
 
 func TestSetPresetForProjectWithIDProvided(t *testing.T) {
@@ -0,0 +1,200 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/command"
+	piperDocker "github.com/SAP/jenkins-library/pkg/docker"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/sbom"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+const rpmQueryFormat = `%{NAME}\t%{EPOCH}\t%{VERSION}-%{RELEASE}\t%{ARCH}\t%{LICENSE}\n`
+
+type containerScanImageUtils interface {
+	command.ExecRunner
+
+	FileRead(path string) ([]byte, error)
+	FileWrite(path string, content []byte, perm os.FileMode) error
+	MkdirAll(path string, perm os.FileMode) error
+	TempDir(dir, pattern string) (string, error)
+	RemoveAll(path string) error
+	ImageFileSystem(tarFilePath string) (io.ReadCloser, string, error)
+}
+
+type containerScanImageUtilsBundle struct {
+	*command.Command
+	*piperutils.Files
+}
+
+func (c *containerScanImageUtilsBundle) ImageFileSystem(tarFilePath string) (io.ReadCloser, string, error) {
+	return piperDocker.ImageFileSystem(tarFilePath)
+}
+
+func newContainerScanImageUtils() containerScanImageUtils {
+	utils := containerScanImageUtilsBundle{
+		Command: &command.Command{},
+		Files:   &piperutils.Files{},
+	}
+	// Reroute command output to logging framework
+	utils.Stdout(log.Writer())
+	utils.Stderr(log.Writer())
+	return &utils
+}
+
+func containerScanImage(config containerScanImageOptions, telemetryData *telemetry.CustomData) {
+	utils := newContainerScanImageUtils()
+
+	// Error situations should be bubbled up until they reach the line below which will then stop execution
+	// through the log.Entry().Fatal() call leading to an os.Exit(1) in the end.
+	reports, err := runContainerScanImage(&config, utils)
+	piperutils.PersistReportsAndLinks("containerScanImage", "", reports, nil)
+	if err != nil {
+		log.Entry().WithError(err).Fatal("step execution failed")
+	}
+}
+
+func runContainerScanImage(config *containerScanImageOptions, utils containerScanImageUtils) ([]piperutils.Path, error) {
+	tarFilePath := config.FilePath
+	if len(tarFilePath) == 0 {
+		if len(config.ContainerImage) == 0 {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return nil, fmt.Errorf("image has not been set, please configure filePath or containerImage parameter")
+		}
+		tarFilePath = filenameFromContainer("", config.ContainerImage)
+	}
+	imageName := config.ContainerImage
+	if len(imageName) == 0 {
+		imageName = filepath.Base(tarFilePath)
+	}
+
+	log.Entry().Infof("Inspecting image '%v' ...", imageName)
+	filesystem, digest, err := utils.ImageFileSystem(tarFilePath)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return nil, err
+	}
+	defer filesystem.Close()
+	inventory, err := sbom.Catalog(filesystem)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to inspect image '%v'", imageName)
+	}
+	if inventory.RpmDatabase != nil {
+		if err := queryRpmDatabase(inventory, utils); err != nil {
+			return nil, err
+		}
+	}
+	log.Entry().Infof("Found %v packages in image '%v'", len(inventory.Packages), imageName)
+
+	document := sbom.SBOM{
+		ImageName:   imageName,
+		ImageDigest: digest,
+		OS:          inventory.OS,
+		Packages:    inventory.Packages,
+		Created:     time.Now(),
+		UUID:        uuid.New().String(),
+	}
+	if err := utils.MkdirAll(config.SbomDirectory, 0755); err != nil {
+		return nil, errors.Wrapf(err, "failed to create directory '%v'", config.SbomDirectory)
+	}
+	reports := []piperutils.Path{}
+	for _, format := range config.SbomFormats {
+		var content []byte
+		var fileName, reportName string
+		switch format {
+		case "cyclonedx":
+			content, err = document.CycloneDX()
+			fileName, reportName = "sbom.cdx.json", "CycloneDX SBOM"
+		case "spdx":
+			content, err = document.SPDX()
+			fileName, reportName = "sbom.spdx.json", "SPDX SBOM"
+		default:
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return reports, fmt.Errorf("SBOM format '%v' is not supported", format)
+		}
+		if err != nil {
+			return reports, errors.Wrapf(err, "failed to create %v", reportName)
+		}
+		sbomPath := filepath.Join(config.SbomDirectory, fileName)
+		if err := utils.FileWrite(sbomPath, content, 0644); err != nil {
+			return reports, errors.Wrapf(err, "failed to write %v", sbomPath)
+		}
+		reports = append(reports, piperutils.Path{Name: reportName, Target: sbomPath, Mandatory: true})
+	}
+
+	if len(config.VulnerabilityDatabase) == 0 {
+		return reports, nil
+	}
+	return scanImageVulnerabilities(config, inventory.Packages, reports, utils)
+}
+
+// queryRpmDatabase adds the rpm packages to the inventory by querying the rpm database of the image
+func queryRpmDatabase(inventory *sbom.Inventory, utils containerScanImageUtils) error {
+	dbPath, err := utils.TempDir("", "rpmdb")
+	if err != nil {
+		return errors.Wrap(err, "failed to create directory for rpm database")
+	}
+	defer utils.RemoveAll(dbPath)
+	for name, content := range inventory.RpmDatabase {
+		if err := utils.FileWrite(filepath.Join(dbPath, name), content, 0644); err != nil {
+			return errors.Wrap(err, "failed to extract rpm database")
+		}
+	}
+
+	var queryResult bytes.Buffer
+	utils.Stdout(&queryResult)
+	err = utils.RunExecutable("rpm", "--dbpath", dbPath, "--query", "--all", "--queryformat", rpmQueryFormat)
+	utils.Stdout(log.Writer())
+	if err != nil {
+		return errors.Wrap(err, "failed to query rpm database, the rpm command line tool is required for images based on rpm")
+	}
+	return inventory.AddRpmPackages(queryResult.String())
+}
+
+func scanImageVulnerabilities(config *containerScanImageOptions, packages []sbom.Package, reports []piperutils.Path, utils containerScanImageUtils) ([]piperutils.Path, error) {
+	content, err := utils.FileRead(config.VulnerabilityDatabase)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return reports, errors.Wrapf(err, "failed to read vulnerability database '%v'", config.VulnerabilityDatabase)
+	}
+	database, err := sbom.ReadVulnerabilityDatabase(content)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return reports, err
+	}
+
+	findings := database.Match(packages)
+	reportPath := filepath.Join(config.SbomDirectory, "vulnerabilities.json")
+	report, err := json.MarshalIndent(findings, "", "  ")
+	if err != nil {
+		return reports, errors.Wrap(err, "failed to create vulnerability report")
+	}
+	if err := utils.FileWrite(reportPath, report, 0644); err != nil {
+		return reports, errors.Wrapf(err, "failed to write %v", reportPath)
+	}
+	reports = append(reports, piperutils.Path{Name: "Vulnerability Report", Target: reportPath})
+
+	violations := 0
+	for _, finding := range findings {
+		log.Entry().Warnf("%v (%v) in %v (%v)", finding.ID, finding.Severity, finding.PURL, finding.Location)
+		if config.FailOnSeverity != "none" && sbom.SeverityRank(finding.Severity) >= sbom.SeverityRank(config.FailOnSeverity) {
+			violations++
+		}
+	}
+	log.Entry().Infof("Found %v vulnerabilities", len(findings))
+	if violations > 0 {
+		log.SetErrorCategory(log.ErrorCompliance)
+		return reports, fmt.Errorf("%v vulnerabilities with severity '%v' or higher found", violations, config.FailOnSeverity)
+	}
+	return reports, nil
+}
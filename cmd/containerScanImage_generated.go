@@ -0,0 +1,199 @@
+// Code generated by piper's step-generator. DO NOT EDIT.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/spf13/cobra"
+)
+
+type containerScanImageOptions struct {
+	ContainerImage        string   `json:"containerImage,omitempty"`
+	FailOnSeverity        string   `json:"failOnSeverity,omitempty"`
+	FilePath              string   `json:"filePath,omitempty"`
+	SbomDirectory         string   `json:"sbomDirectory,omitempty"`
+	SbomFormats           []string `json:"sbomFormats,omitempty"`
+	VulnerabilityDatabase string   `json:"vulnerabilityDatabase,omitempty"`
+}
+
+// ContainerScanImageCommand Creates a software bill of materials (SBOM) of a container image and scans it for known vulnerabilities.
+func ContainerScanImageCommand() *cobra.Command {
+	const STEP_NAME = "containerScanImage"
+
+	metadata := containerScanImageMetadata()
+	var stepConfig containerScanImageOptions
+	var startTime time.Time
+
+	var createContainerScanImageCmd = &cobra.Command{
+		Use:   STEP_NAME,
+		Short: "Creates a software bill of materials (SBOM) of a container image and scans it for known vulnerabilities.",
+		Long: `This step inspects a container image saved as tar file, e.g. by the step ` + "`" + `containerSaveImage` + "`" + ` or via the ` + "`" + `--tarPath` + "`" + ` option of Kaniko, and creates a software bill of materials (SBOM) of the image.
+
+The following packages are detected in the file system of the image:
+
+* OS packages installed via dpkg (Debian, Ubuntu, distroless), apk (Alpine) and rpm (Red Hat, SUSE).
+* npm packages (` + "`" + `node_modules/**/package.json` + "`" + `).
+* Python packages (` + "`" + `*.dist-info/METADATA` + "`" + `, ` + "`" + `*.egg-info/PKG-INFO` + "`" + `).
+* Maven packages within jar, war and ear files (` + "`" + `META-INF/maven/**/pom.properties` + "`" + `), including nested archives, e.g. of Spring Boot applications.
+
+The rpm database is read via the ` + "`" + `rpm` + "`" + ` command line tool, thus it needs to be available for images based on rpm. The container of the step (Red Hat Universal Base Image) provides it.
+
+The SBOM is written in the formats [CycloneDX](https://cyclonedx.org/) (` + "`" + `sbom.cdx.json` + "`" + `) and [SPDX](https://spdx.dev/) (` + "`" + `sbom.spdx.json` + "`" + `) to ` + "`" + `sbomDirectory` + "`" + `.
+
+### Vulnerability scan
+
+In case a ` + "`" + `vulnerabilityDatabase` + "`" + ` is provided, the packages are matched against the vulnerabilities listed in it and the findings are written to ` + "`" + `vulnerabilities.json` + "`" + `.
+The step fails in case a vulnerability with the severity ` + "`" + `failOnSeverity` + "`" + ` or higher is found.
+
+The vulnerability database is a JSON file like the following. A vulnerability either lists the affected ` + "`" + `versions` + "`" + ` explicitly or affects all versions from ` + "`" + `introducedVersion` + "`" + ` up to ` + "`" + `fixedVersion` + "`" + `.
+Versions of npm, Go, Cargo and Composer packages are compared as [semantic versions](https://semver.org) (pre-releases sort before the release), all other versions like by dpkg.
+` + "`" + `type` + "`" + `, ` + "`" + `namespace` + "`" + ` and ` + "`" + `name` + "`" + ` correspond to the [package URL](https://github.com/package-url/purl-spec) of the affected package, the ` + "`" + `namespace` + "`" + ` is optional.
+
+` + "`" + `` + "`" + `` + "`" + `json
+{
+  "vulnerabilities": [
+    {
+      "id": "CVE-2021-44228",
+      "severity": "critical",
+      "type": "maven",
+      "namespace": "org.apache.logging.log4j",
+      "name": "log4j-core",
+      "introducedVersion": "2.0-beta9",
+      "fixedVersion": "2.15.0"
+    }
+  ]
+}
+` + "`" + `` + "`" + `` + "`" + ``,
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			startTime = time.Now()
+			log.SetStepName(STEP_NAME)
+			log.SetVerbose(GeneralConfig.Verbose)
+
+			path, _ := os.Getwd()
+			fatalHook := &log.FatalHook{CorrelationID: GeneralConfig.CorrelationID, Path: path}
+			log.RegisterHook(fatalHook)
+
+			err := PrepareConfig(cmd, &metadata, STEP_NAME, &stepConfig, config.OpenPiperFile)
+			if err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+
+			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
+				sentryHook := log.NewSentryHook(GeneralConfig.HookConfig.SentryConfig.Dsn, GeneralConfig.CorrelationID)
+				log.RegisterHook(&sentryHook)
+			}
+
+			return nil
+		},
+		Run: func(_ *cobra.Command, _ []string) {
+			telemetryData := telemetry.CustomData{}
+			telemetryData.ErrorCode = "1"
+			handler := func() {
+				config.RemoveVaultSecretFiles()
+				telemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
+				telemetryData.ErrorCategory = log.GetErrorCategory().String()
+				telemetry.Send(&telemetryData)
+			}
+			log.DeferExitHandler(handler)
+			defer handler()
+			telemetry.Initialize(GeneralConfig.NoTelemetry, STEP_NAME)
+			containerScanImage(stepConfig, &telemetryData)
+			telemetryData.ErrorCode = "0"
+			log.Entry().Info("SUCCESS")
+		},
+	}
+
+	addContainerScanImageFlags(createContainerScanImageCmd, &stepConfig)
+	return createContainerScanImageCmd
+}
+
+func addContainerScanImageFlags(cmd *cobra.Command, stepConfig *containerScanImageOptions) {
+	cmd.Flags().StringVar(&stepConfig.ContainerImage, "containerImage", os.Getenv("PIPER_containerImage"), "Name of the container image, which is used within the SBOM. In case `filePath` is not set, the image is read from the tar file written by `containerSaveImage` for this image.")
+	cmd.Flags().StringVar(&stepConfig.FailOnSeverity, "failOnSeverity", `high`, "The step fails in case a vulnerability with this severity or higher is found. `none` only reports the vulnerabilities.")
+	cmd.Flags().StringVar(&stepConfig.FilePath, "filePath", os.Getenv("PIPER_filePath"), "Path of the tar file containing the container image.")
+	cmd.Flags().StringVar(&stepConfig.SbomDirectory, "sbomDirectory", `.pipeline/sbom`, "Directory the SBOM and the vulnerability report are written to.")
+	cmd.Flags().StringSliceVar(&stepConfig.SbomFormats, "sbomFormats", []string{`cyclonedx`, `spdx`}, "Formats of the SBOM.")
+	cmd.Flags().StringVar(&stepConfig.VulnerabilityDatabase, "vulnerabilityDatabase", os.Getenv("PIPER_vulnerabilityDatabase"), "Path of the vulnerability database (JSON) the packages are matched against. No vulnerability scan is executed in case it is not set.")
+
+}
+
+// retrieve step metadata
+func containerScanImageMetadata() config.StepData {
+	var theMetaData = config.StepData{
+		Metadata: config.StepMetadata{
+			Name:        "containerScanImage",
+			Aliases:     []config.Alias{},
+			Description: "Creates a software bill of materials (SBOM) of a container image and scans it for known vulnerabilities.",
+		},
+		Spec: config.StepSpec{
+			Inputs: config.StepInputs{
+				Parameters: []config.StepParameters{
+					{
+						Name: "containerImage",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "container/imageNameTag",
+							},
+						},
+						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{{Name: "dockerImage"}, {Name: "scanImage"}},
+					},
+					{
+						Name:        "failOnSeverity",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "filePath",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "sbomDirectory",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "sbomFormats",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "vulnerabilityDatabase",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+				},
+			},
+			Containers: []config.Container{
+				{Name: "rpm", Image: "registry.access.redhat.com/ubi9/ubi-minimal:9.0.0"},
+			},
+		},
+	}
+	return theMetaData
+}
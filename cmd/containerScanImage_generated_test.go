@@ -0,0 +1,17 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContainerScanImageCommand(t *testing.T) {
+	t.Parallel()
+
+	testCmd := ContainerScanImageCommand()
+
+	// only high level testing performed - details are tested in step generation procedure
+	assert.Equal(t, "containerScanImage", testCmd.Use, "command name incorrect")
+
+}
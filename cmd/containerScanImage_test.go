@@ -0,0 +1,223 @@
+package cmd
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/stretchr/testify/assert"
+)
+
+type containerScanImageMockUtils struct {
+	*mock.ExecMockRunner
+	*mock.FilesMock
+	imageFiles   map[string]string
+	imagePath    string
+	removedPaths []string
+}
+
+func (c *containerScanImageMockUtils) TempDir(dir, pattern string) (string, error) {
+	return "/tmp/" + pattern, nil
+}
+
+func (c *containerScanImageMockUtils) RemoveAll(path string) error {
+	c.removedPaths = append(c.removedPaths, path)
+	return nil
+}
+
+func (c *containerScanImageMockUtils) ImageFileSystem(tarFilePath string) (io.ReadCloser, string, error) {
+	c.imagePath = tarFilePath
+	if c.imageFiles == nil {
+		return nil, "", fmt.Errorf("failed to read image from %v", tarFilePath)
+	}
+	var buffer bytes.Buffer
+	writer := tar.NewWriter(&buffer)
+	for name, content := range c.imageFiles {
+		writer.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg})
+		writer.Write([]byte(content))
+	}
+	writer.Close()
+	return ioutil.NopCloser(&buffer), "sha256:abc", nil
+}
+
+func newContainerScanImageTestsUtils() *containerScanImageMockUtils {
+	utils := containerScanImageMockUtils{
+		ExecMockRunner: &mock.ExecMockRunner{},
+		FilesMock:      &mock.FilesMock{},
+		imageFiles: map[string]string{
+			"etc/os-release":                       "ID=debian\nVERSION_ID=10\n",
+			"var/lib/dpkg/status":                  "Package: openssl\nStatus: install ok installed\nVersion: 1.1.1d-0+deb10u5\n",
+			"app/node_modules/lodash/package.json": `{"name": "lodash", "version": "4.17.20", "license": "MIT"}`,
+		},
+	}
+	utils.AddFile("vulnerabilities.json", []byte(`{"vulnerabilities": [
+		{"id": "CVE-2021-3449", "severity": "medium", "type": "deb", "name": "openssl", "fixedVersion": "1.1.1d-0+deb10u6"},
+		{"id": "CVE-2021-23337", "severity": "high", "type": "npm", "name": "lodash", "fixedVersion": "4.17.21"}
+	]}`))
+	return &utils
+}
+
+func TestRunContainerScanImage(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success - SBOM", func(t *testing.T) {
+		t.Parallel()
+		config := containerScanImageOptions{
+			ContainerImage: "my.registry/my-service:1.2.3",
+			SbomDirectory:  ".pipeline/sbom",
+			SbomFormats:    []string{"cyclonedx", "spdx"},
+		}
+		utils := newContainerScanImageTestsUtils()
+
+		reports, err := runContainerScanImage(&config, utils)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, "my_registry_my-service_1_2_3.tar", utils.imagePath)
+			assert.Equal(t, []piperutils.Path{
+				{Name: "CycloneDX SBOM", Target: ".pipeline/sbom/sbom.cdx.json", Mandatory: true},
+				{Name: "SPDX SBOM", Target: ".pipeline/sbom/sbom.spdx.json", Mandatory: true},
+			}, reports)
+			content, err := utils.FileRead(".pipeline/sbom/sbom.cdx.json")
+			if assert.NoError(t, err) {
+				bom := struct {
+					Components []struct {
+						PURL string `json:"purl"`
+					} `json:"components"`
+				}{}
+				assert.NoError(t, json.Unmarshal(content, &bom))
+				if assert.Len(t, bom.Components, 2) {
+					assert.Equal(t, "pkg:deb/debian/openssl@1.1.1d-0%2Bdeb10u5", bom.Components[0].PURL)
+					assert.Equal(t, "pkg:npm/lodash@4.17.20", bom.Components[1].PURL)
+				}
+			}
+			assert.True(t, utils.HasFile(".pipeline/sbom/sbom.spdx.json"))
+			assert.False(t, utils.HasFile(".pipeline/sbom/vulnerabilities.json"))
+			assert.Empty(t, utils.Calls)
+		}
+	})
+
+	t.Run("success - rpm database", func(t *testing.T) {
+		t.Parallel()
+		config := containerScanImageOptions{
+			FilePath:      "image.tar",
+			SbomDirectory: "sbom",
+			SbomFormats:   []string{"cyclonedx"},
+		}
+		utils := newContainerScanImageTestsUtils()
+		utils.imageFiles = map[string]string{"etc/os-release": "ID=rhel\n", "var/lib/rpm/Packages": "db"}
+		utils.StdoutReturn = map[string]string{"rpm .*": "openssl-libs\t1\t1.1.1g-15.el8_3\tx86_64\tOpenSSL\n"}
+
+		_, err := runContainerScanImage(&config, utils)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, "image.tar", utils.imagePath)
+			assert.Equal(t, []mock.ExecCall{{Exec: "rpm", Params: []string{"--dbpath", "/tmp/rpmdb", "--query", "--all", "--queryformat", rpmQueryFormat}}}, utils.Calls)
+			assert.True(t, utils.HasWrittenFile("/tmp/rpmdb/Packages"))
+			assert.Equal(t, []string{"/tmp/rpmdb"}, utils.removedPaths)
+			content, _ := utils.FileRead("sbom/sbom.cdx.json")
+			assert.Contains(t, string(content), `"purl": "pkg:rpm/rhel/openssl-libs@1%3A1.1.1g-15.el8_3?arch=x86_64"`)
+		}
+	})
+
+	t.Run("success - vulnerabilities below threshold", func(t *testing.T) {
+		t.Parallel()
+		config := containerScanImageOptions{
+			ContainerImage:        "my-service:1.2.3",
+			FailOnSeverity:        "critical",
+			SbomDirectory:         "sbom",
+			VulnerabilityDatabase: "vulnerabilities.json",
+		}
+		utils := newContainerScanImageTestsUtils()
+
+		reports, err := runContainerScanImage(&config, utils)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, []piperutils.Path{{Name: "Vulnerability Report", Target: "sbom/vulnerabilities.json"}}, reports)
+			content, err := utils.FileRead("sbom/vulnerabilities.json")
+			if assert.NoError(t, err) {
+				assert.Contains(t, string(content), `"id": "CVE-2021-3449"`)
+				assert.Contains(t, string(content), `"id": "CVE-2021-23337"`)
+			}
+		}
+	})
+
+	t.Run("error - vulnerabilities above threshold", func(t *testing.T) {
+		t.Parallel()
+		config := containerScanImageOptions{
+			ContainerImage:        "my-service:1.2.3",
+			FailOnSeverity:        "medium",
+			SbomDirectory:         "sbom",
+			VulnerabilityDatabase: "vulnerabilities.json",
+		}
+		utils := newContainerScanImageTestsUtils()
+
+		reports, err := runContainerScanImage(&config, utils)
+
+		assert.EqualError(t, err, "2 vulnerabilities with severity 'medium' or higher found")
+		assert.Len(t, reports, 1)
+	})
+
+	t.Run("success - report only", func(t *testing.T) {
+		t.Parallel()
+		config := containerScanImageOptions{
+			ContainerImage:        "my-service:1.2.3",
+			FailOnSeverity:        "none",
+			SbomDirectory:         "sbom",
+			VulnerabilityDatabase: "vulnerabilities.json",
+		}
+
+		_, err := runContainerScanImage(&config, newContainerScanImageTestsUtils())
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("error - no image", func(t *testing.T) {
+		t.Parallel()
+		config := containerScanImageOptions{}
+
+		_, err := runContainerScanImage(&config, newContainerScanImageTestsUtils())
+
+		assert.EqualError(t, err, "image has not been set, please configure filePath or containerImage parameter")
+	})
+
+	t.Run("error - invalid image", func(t *testing.T) {
+		t.Parallel()
+		config := containerScanImageOptions{FilePath: "image.tar"}
+		utils := newContainerScanImageTestsUtils()
+		utils.imageFiles = nil
+
+		_, err := runContainerScanImage(&config, utils)
+
+		assert.EqualError(t, err, "failed to read image from image.tar")
+	})
+
+	t.Run("error - rpm query", func(t *testing.T) {
+		t.Parallel()
+		config := containerScanImageOptions{FilePath: "image.tar"}
+		utils := newContainerScanImageTestsUtils()
+		utils.imageFiles = map[string]string{"var/lib/rpm/rpmdb.sqlite": "db"}
+		utils.ShouldFailOnCommand = map[string]error{"rpm .*": fmt.Errorf("command not found")}
+
+		_, err := runContainerScanImage(&config, utils)
+
+		assert.EqualError(t, err, "failed to query rpm database, the rpm command line tool is required for images based on rpm: command not found")
+	})
+
+	t.Run("error - invalid vulnerability database", func(t *testing.T) {
+		t.Parallel()
+		config := containerScanImageOptions{
+			FilePath:              "image.tar",
+			VulnerabilityDatabase: "missing.json",
+		}
+
+		_, err := runContainerScanImage(&config, newContainerScanImageTestsUtils())
+
+		assert.Contains(t, fmt.Sprint(err), "failed to read vulnerability database 'missing.json'")
+	})
+}
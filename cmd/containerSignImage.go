@@ -0,0 +1,351 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/command"
+	piperDocker "github.com/SAP/jenkins-library/pkg/docker"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/pkg/errors"
+)
+
+const (
+	provenanceBuilderID   = "https://github.com/SAP/jenkins-library"
+	provenanceBuildType   = "https://github.com/SAP/jenkins-library/container@v1"
+	attestationDirectory  = ".pipeline/attestation"
+	provenancePredicate   = "provenance.json"
+	provenanceCosignType  = "slsaprovenance"
+	inTotoPayloadType     = "application/vnd.in-toto+json"
+	cosignPasswordEnvName = "COSIGN_PASSWORD"
+)
+
+type containerSignImageUtils interface {
+	command.ExecRunner
+
+	FileExists(filename string) (bool, error)
+	FileRead(path string) ([]byte, error)
+	FileWrite(path string, content []byte, perm os.FileMode) error
+	Copy(src, dest string) (int64, error)
+	MkdirAll(path string, perm os.FileMode) error
+	TempDir(dir, pattern string) (string, error)
+	RemoveAll(path string) error
+}
+
+type containerSignImageUtilsBundle struct {
+	*command.Command
+	*piperutils.Files
+}
+
+func newContainerSignImageUtils() containerSignImageUtils {
+	utils := containerSignImageUtilsBundle{
+		Command: &command.Command{},
+		Files:   &piperutils.Files{},
+	}
+	// Reroute command output to logging framework
+	utils.Stdout(log.Writer())
+	utils.Stderr(log.Writer())
+	return &utils
+}
+
+// slsaProvenance is the predicate of a SLSA build provenance (https://slsa.dev/provenance/v0.2)
+type slsaProvenance struct {
+	Builder    slsaBuilder    `json:"builder"`
+	BuildType  string         `json:"buildType"`
+	Invocation slsaInvocation `json:"invocation"`
+	Metadata   slsaMetadata   `json:"metadata"`
+	Materials  []slsaMaterial `json:"materials,omitempty"`
+}
+
+type slsaBuilder struct {
+	ID string `json:"id"`
+}
+
+type slsaInvocation struct {
+	ConfigSource slsaMaterial      `json:"configSource"`
+	Parameters   map[string]string `json:"parameters,omitempty"`
+}
+
+type slsaMetadata struct {
+	BuildInvocationID string `json:"buildInvocationId,omitempty"`
+	BuildFinishedOn   string `json:"buildFinishedOn"`
+}
+
+type slsaMaterial struct {
+	URI        string            `json:"uri,omitempty"`
+	Digest     map[string]string `json:"digest,omitempty"`
+	EntryPoint string            `json:"entryPoint,omitempty"`
+}
+
+// inTotoStatement is the payload of the attestations returned by 'cosign verify-attestation'
+type inTotoStatement struct {
+	PredicateType string         `json:"predicateType"`
+	Predicate     slsaProvenance `json:"predicate"`
+}
+
+func containerSignImage(config containerSignImageOptions, telemetryData *telemetry.CustomData) {
+	utils := newContainerSignImageUtils()
+	if len(config.BuildURL) == 0 {
+		config.BuildURL = os.Getenv("BUILD_URL")
+	}
+
+	// Error situations should be bubbled up until they reach the line below which will then stop execution
+	// through the log.Entry().Fatal() call leading to an os.Exit(1) in the end.
+	reports, err := runContainerSignImage(&config, getProjectConfigFile(GeneralConfig.CustomConfig), utils)
+	piperutils.PersistReportsAndLinks("containerSignImage", "", reports, nil)
+	if err != nil {
+		log.Entry().WithError(err).Fatal("step execution failed")
+	}
+}
+
+func runContainerSignImage(config *containerSignImageOptions, configFile string, utils containerSignImageUtils) ([]piperutils.Path, error) {
+	images, err := containerImageReferences(config)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return nil, err
+	}
+
+	if len(config.DockerConfigJSON) > 0 {
+		dockerConfigDir, err := utils.TempDir("", "docker")
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create directory for Docker config.json")
+		}
+		defer utils.RemoveAll(dockerConfigDir)
+		if _, err := utils.Copy(config.DockerConfigJSON, filepath.Join(dockerConfigDir, "config.json")); err != nil {
+			return nil, errors.Wrapf(err, "failed to copy file '%v'", config.DockerConfigJSON)
+		}
+		utils.AppendEnv([]string{fmt.Sprintf("DOCKER_CONFIG=%v", dockerConfigDir)})
+	}
+
+	switch config.Mode {
+	case "sign":
+		return signContainerImages(config, configFile, images, utils)
+	case "verify":
+		return nil, verifyContainerImages(config, images, utils)
+	}
+	log.SetErrorCategory(log.ErrorConfiguration)
+	return nil, fmt.Errorf("mode '%v' is not supported", config.Mode)
+}
+
+// containerImageReferences returns the references of the images including the registry, images are referenced by digest if available
+func containerImageReferences(config *containerSignImageOptions) ([]string, error) {
+	if len(config.ContainerImageNameTags) == 0 {
+		return nil, fmt.Errorf("no images have been set, please configure containerImageNameTags parameter")
+	}
+	registry, err := piperDocker.ContainerRegistryFromURL(config.ContainerRegistryURL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read registry url %v", config.ContainerRegistryURL)
+	}
+	images := []string{}
+	for i, imageNameTag := range config.ContainerImageNameTags {
+		if i < len(config.ContainerImageDigests) && len(config.ContainerImageDigests[i]) > 0 {
+			name, _ := splitImageNameTag(imageNameTag)
+			images = append(images, fmt.Sprintf("%v/%v@%v", registry, name, config.ContainerImageDigests[i]))
+			continue
+		}
+		if !config.AllowTagReferences {
+			return nil, fmt.Errorf("no digest available for image '%v', please configure containerImageDigests parameter or allow references by tag via allowTagReferences parameter", imageNameTag)
+		}
+		log.Entry().Warnf("No digest available for image '%v', the image is referenced by tag", imageNameTag)
+		images = append(images, fmt.Sprintf("%v/%v", registry, imageNameTag))
+	}
+	return images, nil
+}
+
+func cosignSbomType(sbomFormat string) string {
+	if sbomFormat == "spdx" {
+		return "spdxjson"
+	}
+	return sbomFormat
+}
+
+func signContainerImages(config *containerSignImageOptions, configFile string, images []string, utils containerSignImageUtils) ([]piperutils.Path, error) {
+	if len(config.SigningKey) == 0 {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return nil, fmt.Errorf("signing key has not been set, please configure signingKey parameter")
+	}
+	// cosign reads the password of the key from the environment, an empty password is required for keys without password
+	utils.AppendEnv([]string{fmt.Sprintf("%v=%v", cosignPasswordEnvName, config.SigningKeyPassword)})
+
+	reports := []piperutils.Path{}
+	provenancePath := filepath.Join(attestationDirectory, provenancePredicate)
+	if config.AttestProvenance {
+		provenance, err := buildProvenance(config, configFile)
+		if err != nil {
+			return nil, err
+		}
+		if err := utils.MkdirAll(attestationDirectory, 0755); err != nil {
+			return nil, errors.Wrapf(err, "failed to create directory '%v'", attestationDirectory)
+		}
+		if err := utils.FileWrite(provenancePath, provenance, 0644); err != nil {
+			return nil, errors.Wrapf(err, "failed to write %v", provenancePath)
+		}
+		reports = append(reports, piperutils.Path{Name: "Build Provenance", Target: provenancePath})
+	}
+	if config.AttestSbom {
+		if exists, _ := utils.FileExists(config.SbomPath); !exists {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return reports, fmt.Errorf("SBOM '%v' does not exist, please create it via containerScanImage or configure sbomPath parameter", config.SbomPath)
+		}
+	}
+
+	for _, image := range images {
+		log.Entry().Infof("Signing image '%v' ...", image)
+		signParams := []string{"sign", "--key", config.SigningKey}
+		if len(config.CommitID) > 0 {
+			signParams = append(signParams, "-a", fmt.Sprintf("commitId=%v", config.CommitID))
+		}
+		if err := utils.RunExecutable("cosign", append(signParams, image)...); err != nil {
+			return reports, errors.Wrapf(err, "signing of image '%v' failed", image)
+		}
+		if config.AttestProvenance {
+			if err := utils.RunExecutable("cosign", "attest", "--key", config.SigningKey, "--type", provenanceCosignType, "--predicate", provenancePath, image); err != nil {
+				return reports, errors.Wrapf(err, "attaching build provenance to image '%v' failed", image)
+			}
+		}
+		if config.AttestSbom {
+			if err := utils.RunExecutable("cosign", "attest", "--key", config.SigningKey, "--type", cosignSbomType(config.SbomFormat), "--predicate", config.SbomPath, image); err != nil {
+				return reports, errors.Wrapf(err, "attaching SBOM to image '%v' failed", image)
+			}
+		}
+	}
+	return reports, nil
+}
+
+func buildProvenance(config *containerSignImageOptions, configFile string) ([]byte, error) {
+	configDigest, err := stepConfigDigest(config)
+	if err != nil {
+		return nil, err
+	}
+	provenance := slsaProvenance{
+		Builder:   slsaBuilder{ID: provenanceBuilderID},
+		BuildType: provenanceBuildType,
+		Invocation: slsaInvocation{
+			Parameters: map[string]string{"stepConfigurationDigest": "sha256:" + configDigest},
+		},
+		Metadata: slsaMetadata{
+			BuildInvocationID: config.BuildURL,
+			BuildFinishedOn:   time.Now().UTC().Format(time.RFC3339),
+		},
+		Materials: []slsaMaterial{},
+	}
+	if len(config.CommitID) > 0 {
+		source := slsaMaterial{URI: config.RepositoryURL, Digest: map[string]string{"sha1": config.CommitID}}
+		provenance.Materials = append(provenance.Materials, source)
+		source.EntryPoint = configFile
+		provenance.Invocation.ConfigSource = source
+	}
+	content, err := json.MarshalIndent(provenance, "", "  ")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create build provenance")
+	}
+	return content, nil
+}
+
+// stepConfigDigest returns the SHA-256 digest of the effective step configuration, i.e. including defaults and values of the commonPipelineEnvironment.
+// Secrets are excluded since they must not be derivable from the provenance and their paths differ from run to run.
+func stepConfigDigest(config *containerSignImageOptions) (string, error) {
+	effectiveConfig := *config
+	effectiveConfig.DockerConfigJSON = ""
+	effectiveConfig.SigningKey = ""
+	effectiveConfig.SigningKeyPassword = ""
+	content, err := json.Marshal(effectiveConfig)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to serialize step configuration")
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(content)), nil
+}
+
+func verifyContainerImages(config *containerSignImageOptions, images []string, utils containerSignImageUtils) error {
+	if len(config.PublicKey) == 0 {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return fmt.Errorf("public key has not been set, please configure publicKey parameter")
+	}
+
+	for _, image := range images {
+		log.Entry().Infof("Verifying image '%v' ...", image)
+		if err := utils.RunExecutable("cosign", "verify", "--key", config.PublicKey, image); err != nil {
+			log.SetErrorCategory(log.ErrorCompliance)
+			return errors.Wrapf(err, "verification of signature of image '%v' failed", image)
+		}
+		if config.AttestProvenance {
+			attestations, err := verifyAttestations(config.PublicKey, provenanceCosignType, image, utils)
+			if err != nil {
+				return err
+			}
+			if len(config.CommitID) > 0 && !provenanceReferencesCommit(attestations, config.CommitID) {
+				log.SetErrorCategory(log.ErrorCompliance)
+				return fmt.Errorf("build provenance of image '%v' does not reference commit '%v'", image, config.CommitID)
+			}
+		}
+		if config.AttestSbom {
+			if _, err := verifyAttestations(config.PublicKey, cosignSbomType(config.SbomFormat), image, utils); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// verifyAttestations verifies the attestations of the given type and returns their statements
+func verifyAttestations(publicKey, attestationType, image string, utils containerSignImageUtils) ([]inTotoStatement, error) {
+	var output bytes.Buffer
+	utils.Stdout(&output)
+	err := utils.RunExecutable("cosign", "verify-attestation", "--key", publicKey, "--type", attestationType, image)
+	utils.Stdout(log.Writer())
+	if err != nil {
+		log.SetErrorCategory(log.ErrorCompliance)
+		return nil, errors.Wrapf(err, "verification of %v attestation of image '%v' failed", attestationType, image)
+	}
+
+	// cosign prints one DSSE envelope per line
+	statements := []inTotoStatement{}
+	scanner := bufio.NewScanner(&output)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		envelope := struct {
+			PayloadType string `json:"payloadType"`
+			Payload     string `json:"payload"`
+		}{}
+		if err := json.Unmarshal(scanner.Bytes(), &envelope); err != nil || envelope.PayloadType != inTotoPayloadType {
+			continue
+		}
+		payload, err := base64.StdEncoding.DecodeString(envelope.Payload)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to decode attestation of image '%v'", image)
+		}
+		statement := inTotoStatement{}
+		if err := json.Unmarshal(payload, &statement); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse attestation of image '%v'", image)
+		}
+		statements = append(statements, statement)
+	}
+	return statements, nil
+}
+
+func provenanceReferencesCommit(statements []inTotoStatement, commitID string) bool {
+	for _, statement := range statements {
+		if !strings.HasPrefix(statement.PredicateType, "https://slsa.dev/provenance/") {
+			continue
+		}
+		if statement.Predicate.Invocation.ConfigSource.Digest["sha1"] == commitID {
+			return true
+		}
+		for _, material := range statement.Predicate.Materials {
+			if material.Digest["sha1"] == commitID {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,329 @@
+// Code generated by piper's step-generator. DO NOT EDIT.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/spf13/cobra"
+)
+
+type containerSignImageOptions struct {
+	AllowTagReferences     bool     `json:"allowTagReferences,omitempty"`
+	AttestProvenance       bool     `json:"attestProvenance,omitempty"`
+	AttestSbom             bool     `json:"attestSbom,omitempty"`
+	BuildURL               string   `json:"buildUrl,omitempty"`
+	CommitID               string   `json:"commitId,omitempty"`
+	ContainerImageDigests  []string `json:"containerImageDigests,omitempty"`
+	ContainerImageNameTags []string `json:"containerImageNameTags,omitempty"`
+	ContainerRegistryURL   string   `json:"containerRegistryUrl,omitempty"`
+	DockerConfigJSON       string   `json:"dockerConfigJSON,omitempty"`
+	Mode                   string   `json:"mode,omitempty"`
+	PublicKey              string   `json:"publicKey,omitempty"`
+	RepositoryURL          string   `json:"repositoryUrl,omitempty"`
+	SbomFormat             string   `json:"sbomFormat,omitempty"`
+	SbomPath               string   `json:"sbomPath,omitempty"`
+	SigningKey             string   `json:"signingKey,omitempty"`
+	SigningKeyPassword     string   `json:"signingKeyPassword,omitempty"`
+}
+
+// ContainerSignImageCommand Signs container images and attaches attestations or verifies them using cosign.
+func ContainerSignImageCommand() *cobra.Command {
+	const STEP_NAME = "containerSignImage"
+
+	metadata := containerSignImageMetadata()
+	var stepConfig containerSignImageOptions
+	var startTime time.Time
+
+	var createContainerSignImageCmd = &cobra.Command{
+		Use:   STEP_NAME,
+		Short: "Signs container images and attaches attestations or verifies them using cosign.",
+		Long: `This step signs container images and attaches attestations using [cosign](https://github.com/sigstore/cosign), thus signatures and attestations are compatible with the sigstore tooling.
+The images are referenced by digest, by default the images built and pushed by ` + "`" + `kanikoExecute` + "`" + ` are used (see ` + "`" + `containerImageNameTags` + "`" + ` and ` + "`" + `containerImageDigests` + "`" + `).
+Images without digest are only referenced by tag in case ` + "`" + `allowTagReferences` + "`" + ` is active, since a tag can be moved to another image.
+
+### Mode ` + "`" + `sign` + "`" + `
+
+1. The image digests are signed with the private key ` + "`" + `signingKey` + "`" + `, which can be provided as Jenkins 'Secret file' credentials or read from Vault.
+1. A [SLSA](https://slsa.dev/) build provenance is attached as attestation in case ` + "`" + `attestProvenance` + "`" + ` is active. It contains the git commit (` + "`" + `commitId` + "`" + `), the repository (` + "`" + `repositoryUrl` + "`" + `), the URL of the pipeline run (` + "`" + `buildUrl` + "`" + `) and the digest of the effective step configuration (secrets excluded).
+1. The SBOM ` + "`" + `sbomPath` + "`" + `, e.g. created by ` + "`" + `containerScanImage` + "`" + `, is attached as attestation in case ` + "`" + `attestSbom` + "`" + ` is active.
+
+### Mode ` + "`" + `verify` + "`" + `
+
+The signatures and the attestations of the images are verified with the public key ` + "`" + `publicKey` + "`" + `, e.g. before the images are deployed via ` + "`" + `kubernetesDeploy` + "`" + `.
+In case ` + "`" + `commitId` + "`" + ` is available, the build provenance has to reference the commit, which ensures that the images have been built from the sources which are deployed.`,
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			startTime = time.Now()
+			log.SetStepName(STEP_NAME)
+			log.SetVerbose(GeneralConfig.Verbose)
+
+			path, _ := os.Getwd()
+			fatalHook := &log.FatalHook{CorrelationID: GeneralConfig.CorrelationID, Path: path}
+			log.RegisterHook(fatalHook)
+
+			err := PrepareConfig(cmd, &metadata, STEP_NAME, &stepConfig, config.OpenPiperFile)
+			if err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+			log.RegisterSecret(stepConfig.DockerConfigJSON)
+			log.RegisterSecret(stepConfig.SigningKey)
+			log.RegisterSecret(stepConfig.SigningKeyPassword)
+
+			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
+				sentryHook := log.NewSentryHook(GeneralConfig.HookConfig.SentryConfig.Dsn, GeneralConfig.CorrelationID)
+				log.RegisterHook(&sentryHook)
+			}
+
+			return nil
+		},
+		Run: func(_ *cobra.Command, _ []string) {
+			telemetryData := telemetry.CustomData{}
+			telemetryData.ErrorCode = "1"
+			handler := func() {
+				config.RemoveVaultSecretFiles()
+				telemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
+				telemetryData.ErrorCategory = log.GetErrorCategory().String()
+				telemetry.Send(&telemetryData)
+			}
+			log.DeferExitHandler(handler)
+			defer handler()
+			telemetry.Initialize(GeneralConfig.NoTelemetry, STEP_NAME)
+			containerSignImage(stepConfig, &telemetryData)
+			telemetryData.ErrorCode = "0"
+			log.Entry().Info("SUCCESS")
+		},
+	}
+
+	addContainerSignImageFlags(createContainerSignImageCmd, &stepConfig)
+	return createContainerSignImageCmd
+}
+
+func addContainerSignImageFlags(cmd *cobra.Command, stepConfig *containerSignImageOptions) {
+	cmd.Flags().BoolVar(&stepConfig.AllowTagReferences, "allowTagReferences", false, "Allows to reference images without digest by their tag. This is not recommended since a tag can be moved to another image after it has been signed or verified.")
+	cmd.Flags().BoolVar(&stepConfig.AttestProvenance, "attestProvenance", true, "Defines whether a build provenance is attached to the images as attestation (mode `sign`) respectively whether it is verified (mode `verify`).")
+	cmd.Flags().BoolVar(&stepConfig.AttestSbom, "attestSbom", false, "Defines whether the SBOM `sbomPath` is attached to the images as attestation (mode `sign`) respectively whether it is verified (mode `verify`).")
+	cmd.Flags().StringVar(&stepConfig.BuildURL, "buildUrl", os.Getenv("PIPER_buildUrl"), "URL of the pipeline run used in the build provenance. Defaults to the environment variable `BUILD_URL`.")
+	cmd.Flags().StringVar(&stepConfig.CommitID, "commitId", os.Getenv("PIPER_commitId"), "The git commit id the images are built from.")
+	cmd.Flags().StringSliceVar(&stepConfig.ContainerImageDigests, "containerImageDigests", []string{}, "Digests of the images, in the same order as `containerImageNameTags`. A digest is required for every image unless `allowTagReferences` is active.")
+	cmd.Flags().StringSliceVar(&stepConfig.ContainerImageNameTags, "containerImageNameTags", []string{}, "Names and tags of the images, e.g. `my-service:1.2.3`.")
+	cmd.Flags().StringVar(&stepConfig.ContainerRegistryURL, "containerRegistryUrl", os.Getenv("PIPER_containerRegistryUrl"), "The URL of the container registry the images are located in.")
+	cmd.Flags().StringVar(&stepConfig.DockerConfigJSON, "dockerConfigJSON", os.Getenv("PIPER_dockerConfigJSON"), "Path to the file `.docker/config.json` containing the credentials for the container registry.")
+	cmd.Flags().StringVar(&stepConfig.Mode, "mode", `sign`, "Defines whether the images are signed or verified.")
+	cmd.Flags().StringVar(&stepConfig.PublicKey, "publicKey", os.Getenv("PIPER_publicKey"), "Mode `verify` only: path to the cosign public key.")
+	cmd.Flags().StringVar(&stepConfig.RepositoryURL, "repositoryUrl", os.Getenv("PIPER_repositoryUrl"), "URL of the git repository used in the build provenance.")
+	cmd.Flags().StringVar(&stepConfig.SbomFormat, "sbomFormat", `cyclonedx`, "Format of the SBOM.")
+	cmd.Flags().StringVar(&stepConfig.SbomPath, "sbomPath", `.pipeline/sbom/sbom.cdx.json`, "Mode `sign` only: path to the SBOM attached as attestation.")
+	cmd.Flags().StringVar(&stepConfig.SigningKey, "signingKey", os.Getenv("PIPER_signingKey"), "Mode `sign` only: path to the cosign private key.")
+	cmd.Flags().StringVar(&stepConfig.SigningKeyPassword, "signingKeyPassword", os.Getenv("PIPER_signingKeyPassword"), "Mode `sign` only: password of the cosign private key.")
+
+	cmd.MarkFlagRequired("containerImageNameTags")
+	cmd.MarkFlagRequired("containerRegistryUrl")
+}
+
+// retrieve step metadata
+func containerSignImageMetadata() config.StepData {
+	var theMetaData = config.StepData{
+		Metadata: config.StepMetadata{
+			Name:        "containerSignImage",
+			Aliases:     []config.Alias{},
+			Description: "Signs container images and attaches attestations or verifies them using cosign.",
+		},
+		Spec: config.StepSpec{
+			Inputs: config.StepInputs{
+				Parameters: []config.StepParameters{
+					{
+						Name:        "allowTagReferences",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "attestProvenance",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "attestSbom",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "buildUrl",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name: "commitId",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "git/commitId",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+					},
+					{
+						Name: "containerImageDigests",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "container/imageDigests",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "[]string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+					},
+					{
+						Name: "containerImageNameTags",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "container/imageNameTags",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "[]string",
+						Mandatory: true,
+						Aliases:   []config.Alias{},
+					},
+					{
+						Name: "containerRegistryUrl",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "container/registryUrl",
+							},
+						},
+						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: true,
+						Aliases:   []config.Alias{{Name: "dockerRegistryUrl"}},
+					},
+					{
+						Name: "dockerConfigJSON",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "dockerConfigJsonCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:  "",
+								Paths: []string{"$(vaultPath)/docker-config", "$(vaultBasePath)/$(vaultPipelineName)/docker-config", "$(vaultBasePath)/GROUP-SECRETS/docker-config"},
+								Type:  "vaultSecretFile",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+					},
+					{
+						Name:        "mode",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "publicKey",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "repositoryUrl",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "sbomFormat",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "sbomPath",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name: "signingKey",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "signingKeyCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:  "",
+								Paths: []string{"$(vaultPath)/cosign-private-key", "$(vaultBasePath)/$(vaultPipelineName)/cosign-private-key", "$(vaultBasePath)/GROUP-SECRETS/cosign-private-key"},
+								Type:  "vaultSecretFile",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+					},
+					{
+						Name: "signingKeyPassword",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "signingKeyPasswordCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:  "",
+								Paths: []string{"$(vaultPath)/cosign", "$(vaultBasePath)/$(vaultPipelineName)/cosign", "$(vaultBasePath)/GROUP-SECRETS/cosign"},
+								Type:  "vaultSecret",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+					},
+				},
+			},
+			Containers: []config.Container{
+				{Name: "cosign", Image: "gcr.io/projectsigstore/cosign:v1.13.1-dev", Options: []config.Option{{Name: "-u", Value: "0"}, {Name: "--entrypoint", Value: "''"}}},
+			},
+		},
+	}
+	return theMetaData
+}
@@ -0,0 +1,284 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/stretchr/testify/assert"
+)
+
+type containerSignImageMockUtils struct {
+	*mock.ExecMockRunner
+	*mock.FilesMock
+	removedPaths []string
+}
+
+func (c *containerSignImageMockUtils) TempDir(dir, pattern string) (string, error) {
+	return "/tmp/" + pattern, nil
+}
+
+func (c *containerSignImageMockUtils) RemoveAll(path string) error {
+	c.removedPaths = append(c.removedPaths, path)
+	return nil
+}
+
+func newContainerSignImageTestsUtils() *containerSignImageMockUtils {
+	utils := containerSignImageMockUtils{
+		ExecMockRunner: &mock.ExecMockRunner{},
+		FilesMock:      &mock.FilesMock{},
+	}
+	utils.AddFile(".pipeline/config.yml", []byte("general:\n  buildTool: docker\n"))
+	return &utils
+}
+
+func cosignAttestation(t *testing.T, commitID string) string {
+	statement := map[string]interface{}{
+		"_type":         "https://in-toto.io/Statement/v0.1",
+		"predicateType": "https://slsa.dev/provenance/v0.2",
+		"predicate": map[string]interface{}{
+			"materials": []map[string]interface{}{{"uri": "https://github.com/SAP/app", "digest": map[string]string{"sha1": commitID}}},
+		},
+	}
+	payload, err := json.Marshal(statement)
+	assert.NoError(t, err)
+	envelope, err := json.Marshal(map[string]string{"payloadType": "application/vnd.in-toto+json", "payload": base64.StdEncoding.EncodeToString(payload)})
+	assert.NoError(t, err)
+	return string(envelope) + "\n"
+}
+
+func TestRunContainerSignImage(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success - sign", func(t *testing.T) {
+		t.Parallel()
+		config := containerSignImageOptions{
+			AttestProvenance:       true,
+			AttestSbom:             true,
+			BuildURL:               "https://jenkins.example.org/job/app/1/",
+			CommitID:               "a1b2c3",
+			ContainerImageDigests:  []string{"sha256:111", "sha256:222"},
+			ContainerImageNameTags: []string{"my-service:1.2.3", "my-worker:1.2.3"},
+			ContainerRegistryURL:   "https://my.registry.com:50000",
+			DockerConfigJSON:       "docker/config.json",
+			Mode:                   "sign",
+			RepositoryURL:          "https://github.com/SAP/app",
+			SbomFormat:             "spdx",
+			SbomPath:               "sbom.spdx.json",
+			SigningKey:             "cosign.key",
+			SigningKeyPassword:     "secret",
+		}
+		utils := newContainerSignImageTestsUtils()
+		utils.AddFile("docker/config.json", []byte(`{"auths":{}}`))
+		utils.AddFile("sbom.spdx.json", []byte(`{}`))
+
+		reports, err := runContainerSignImage(&config, ".pipeline/config.yml", utils)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, []string{"DOCKER_CONFIG=/tmp/docker", "COSIGN_PASSWORD=secret"}, utils.Env)
+			assert.True(t, utils.HasCopiedFile("docker/config.json", "/tmp/docker/config.json"))
+			assert.Equal(t, []string{"/tmp/docker"}, utils.removedPaths)
+			assert.Equal(t, []piperutils.Path{{Name: "Build Provenance", Target: ".pipeline/attestation/provenance.json"}}, reports)
+			assert.Equal(t, []mock.ExecCall{
+				{Exec: "cosign", Params: []string{"sign", "--key", "cosign.key", "-a", "commitId=a1b2c3", "my.registry.com:50000/my-service@sha256:111"}},
+				{Exec: "cosign", Params: []string{"attest", "--key", "cosign.key", "--type", "slsaprovenance", "--predicate", ".pipeline/attestation/provenance.json", "my.registry.com:50000/my-service@sha256:111"}},
+				{Exec: "cosign", Params: []string{"attest", "--key", "cosign.key", "--type", "spdxjson", "--predicate", "sbom.spdx.json", "my.registry.com:50000/my-service@sha256:111"}},
+				{Exec: "cosign", Params: []string{"sign", "--key", "cosign.key", "-a", "commitId=a1b2c3", "my.registry.com:50000/my-worker@sha256:222"}},
+				{Exec: "cosign", Params: []string{"attest", "--key", "cosign.key", "--type", "slsaprovenance", "--predicate", ".pipeline/attestation/provenance.json", "my.registry.com:50000/my-worker@sha256:222"}},
+				{Exec: "cosign", Params: []string{"attest", "--key", "cosign.key", "--type", "spdxjson", "--predicate", "sbom.spdx.json", "my.registry.com:50000/my-worker@sha256:222"}},
+			}, utils.Calls)
+
+			content, err := utils.FileRead(".pipeline/attestation/provenance.json")
+			if assert.NoError(t, err) {
+				provenance := slsaProvenance{}
+				assert.NoError(t, json.Unmarshal(content, &provenance))
+				assert.Equal(t, "https://github.com/SAP/jenkins-library", provenance.Builder.ID)
+				assert.Equal(t, "https://jenkins.example.org/job/app/1/", provenance.Metadata.BuildInvocationID)
+				assert.Equal(t, slsaMaterial{URI: "https://github.com/SAP/app", Digest: map[string]string{"sha1": "a1b2c3"}, EntryPoint: ".pipeline/config.yml"}, provenance.Invocation.ConfigSource)
+				effectiveConfig := config
+				effectiveConfig.DockerConfigJSON, effectiveConfig.SigningKey, effectiveConfig.SigningKeyPassword = "", "", ""
+				effectiveConfigJSON, _ := json.Marshal(effectiveConfig)
+				assert.Equal(t, map[string]string{"stepConfigurationDigest": fmt.Sprintf("sha256:%x", sha256.Sum256(effectiveConfigJSON))}, provenance.Invocation.Parameters)
+				assert.NotContains(t, string(effectiveConfigJSON), "secret")
+				assert.Equal(t, []slsaMaterial{{URI: "https://github.com/SAP/app", Digest: map[string]string{"sha1": "a1b2c3"}}}, provenance.Materials)
+			}
+		}
+	})
+
+	t.Run("success - sign by tag without attestations", func(t *testing.T) {
+		t.Parallel()
+		config := containerSignImageOptions{
+			AllowTagReferences:     true,
+			ContainerImageNameTags: []string{"my-service:1.2.3"},
+			ContainerRegistryURL:   "https://my.registry.com",
+			Mode:                   "sign",
+			SigningKey:             "cosign.key",
+		}
+		utils := newContainerSignImageTestsUtils()
+
+		reports, err := runContainerSignImage(&config, ".pipeline/config.yml", utils)
+
+		if assert.NoError(t, err) {
+			assert.Empty(t, reports)
+			assert.Equal(t, []string{"COSIGN_PASSWORD="}, utils.Env)
+			assert.Equal(t, []mock.ExecCall{{Exec: "cosign", Params: []string{"sign", "--key", "cosign.key", "my.registry.com/my-service:1.2.3"}}}, utils.Calls)
+		}
+	})
+
+	t.Run("success - verify", func(t *testing.T) {
+		t.Parallel()
+		config := containerSignImageOptions{
+			AttestProvenance:       true,
+			AttestSbom:             true,
+			CommitID:               "a1b2c3",
+			ContainerImageDigests:  []string{"sha256:111"},
+			ContainerImageNameTags: []string{"my-service:1.2.3"},
+			ContainerRegistryURL:   "https://my.registry.com",
+			Mode:                   "verify",
+			PublicKey:              "cosign.pub",
+			SbomFormat:             "cyclonedx",
+		}
+		utils := newContainerSignImageTestsUtils()
+		utils.StdoutReturn = map[string]string{"cosign verify-attestation .* slsaprovenance .*": cosignAttestation(t, "000000") + cosignAttestation(t, "a1b2c3")}
+
+		_, err := runContainerSignImage(&config, ".pipeline/config.yml", utils)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, []mock.ExecCall{
+				{Exec: "cosign", Params: []string{"verify", "--key", "cosign.pub", "my.registry.com/my-service@sha256:111"}},
+				{Exec: "cosign", Params: []string{"verify-attestation", "--key", "cosign.pub", "--type", "slsaprovenance", "my.registry.com/my-service@sha256:111"}},
+				{Exec: "cosign", Params: []string{"verify-attestation", "--key", "cosign.pub", "--type", "cyclonedx", "my.registry.com/my-service@sha256:111"}},
+			}, utils.Calls)
+		}
+	})
+
+	t.Run("error - verify provenance of other commit", func(t *testing.T) {
+		t.Parallel()
+		config := containerSignImageOptions{
+			AttestProvenance:       true,
+			CommitID:               "a1b2c3",
+			ContainerImageDigests:  []string{"sha256:111"},
+			ContainerImageNameTags: []string{"my-service:1.2.3"},
+			ContainerRegistryURL:   "https://my.registry.com",
+			Mode:                   "verify",
+			PublicKey:              "cosign.pub",
+		}
+		utils := newContainerSignImageTestsUtils()
+		utils.StdoutReturn = map[string]string{"cosign verify-attestation .*": cosignAttestation(t, "000000")}
+
+		_, err := runContainerSignImage(&config, ".pipeline/config.yml", utils)
+
+		assert.EqualError(t, err, "build provenance of image 'my.registry.com/my-service@sha256:111' does not reference commit 'a1b2c3'")
+	})
+
+	t.Run("error - verify signature", func(t *testing.T) {
+		t.Parallel()
+		config := containerSignImageOptions{
+			ContainerImageDigests:  []string{"sha256:111"},
+			ContainerImageNameTags: []string{"my-service:1.2.3"},
+			ContainerRegistryURL:   "https://my.registry.com",
+			Mode:                   "verify",
+			PublicKey:              "cosign.pub",
+		}
+		utils := newContainerSignImageTestsUtils()
+		utils.ShouldFailOnCommand = map[string]error{"cosign verify .*": fmt.Errorf("no matching signatures")}
+
+		_, err := runContainerSignImage(&config, ".pipeline/config.yml", utils)
+
+		assert.EqualError(t, err, "verification of signature of image 'my.registry.com/my-service@sha256:111' failed: no matching signatures")
+	})
+
+	t.Run("error - missing public key", func(t *testing.T) {
+		t.Parallel()
+		config := containerSignImageOptions{
+			ContainerImageDigests:  []string{"sha256:111"},
+			ContainerImageNameTags: []string{"my-service:1.2.3"},
+			ContainerRegistryURL:   "https://my.registry.com",
+			Mode:                   "verify",
+		}
+
+		_, err := runContainerSignImage(&config, ".pipeline/config.yml", newContainerSignImageTestsUtils())
+
+		assert.EqualError(t, err, "public key has not been set, please configure publicKey parameter")
+	})
+
+	t.Run("error - missing signing key", func(t *testing.T) {
+		t.Parallel()
+		config := containerSignImageOptions{
+			ContainerImageDigests:  []string{"sha256:111"},
+			ContainerImageNameTags: []string{"my-service:1.2.3"},
+			ContainerRegistryURL:   "https://my.registry.com",
+			Mode:                   "sign",
+		}
+
+		_, err := runContainerSignImage(&config, ".pipeline/config.yml", newContainerSignImageTestsUtils())
+
+		assert.EqualError(t, err, "signing key has not been set, please configure signingKey parameter")
+	})
+
+	t.Run("error - missing SBOM", func(t *testing.T) {
+		t.Parallel()
+		config := containerSignImageOptions{
+			AttestSbom:             true,
+			ContainerImageDigests:  []string{"sha256:111"},
+			ContainerImageNameTags: []string{"my-service:1.2.3"},
+			ContainerRegistryURL:   "https://my.registry.com",
+			Mode:                   "sign",
+			SbomPath:               ".pipeline/sbom/sbom.cdx.json",
+			SigningKey:             "cosign.key",
+		}
+		utils := newContainerSignImageTestsUtils()
+
+		_, err := runContainerSignImage(&config, ".pipeline/config.yml", utils)
+
+		assert.EqualError(t, err, "SBOM '.pipeline/sbom/sbom.cdx.json' does not exist, please create it via containerScanImage or configure sbomPath parameter")
+		assert.Empty(t, utils.Calls)
+	})
+
+	t.Run("error - missing digest", func(t *testing.T) {
+		t.Parallel()
+		config := containerSignImageOptions{
+			ContainerImageDigests:  []string{"sha256:111"},
+			ContainerImageNameTags: []string{"my-service:1.2.3", "my-worker:1.2.3"},
+			ContainerRegistryURL:   "https://my.registry.com",
+			Mode:                   "sign",
+			SigningKey:             "cosign.key",
+		}
+		utils := newContainerSignImageTestsUtils()
+
+		_, err := runContainerSignImage(&config, ".pipeline/config.yml", utils)
+
+		assert.EqualError(t, err, "no digest available for image 'my-worker:1.2.3', please configure containerImageDigests parameter or allow references by tag via allowTagReferences parameter")
+		assert.Empty(t, utils.Calls)
+	})
+
+	t.Run("error - no images", func(t *testing.T) {
+		t.Parallel()
+		config := containerSignImageOptions{ContainerRegistryURL: "https://my.registry.com", Mode: "sign"}
+
+		_, err := runContainerSignImage(&config, ".pipeline/config.yml", newContainerSignImageTestsUtils())
+
+		assert.EqualError(t, err, "no images have been set, please configure containerImageNameTags parameter")
+	})
+
+	t.Run("error - sign", func(t *testing.T) {
+		t.Parallel()
+		config := containerSignImageOptions{
+			ContainerImageDigests:  []string{"sha256:111"},
+			ContainerImageNameTags: []string{"my-service:1.2.3"},
+			ContainerRegistryURL:   "https://my.registry.com",
+			Mode:                   "sign",
+			SigningKey:             "cosign.key",
+		}
+		utils := newContainerSignImageTestsUtils()
+		utils.ShouldFailOnCommand = map[string]error{"cosign sign .*": fmt.Errorf("decrypt failed")}
+
+		_, err := runContainerSignImage(&config, ".pipeline/config.yml", utils)
+
+		assert.EqualError(t, err, "signing of image 'my.registry.com/my-service@sha256:111' failed: decrypt failed")
+	})
+}
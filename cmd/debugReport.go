@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+
+	"github.com/SAP/jenkins-library/pkg/command"
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/debugreport"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/pkg/errors"
+)
+
+const debugReportLogLines = 100
+
+// debugLogCollector keeps the latest log lines for the debug report
+var debugLogCollector = &log.CollectorHook{MaxLines: debugReportLogLines}
+
+type debugReportUtils interface {
+	command.ExecRunner
+
+	Glob(pattern string) (matches []string, err error)
+	FileRead(path string) ([]byte, error)
+	FileWrite(path string, content []byte, perm os.FileMode) error
+	DirExists(path string) (bool, error)
+}
+
+type debugReportUtilsBundle struct {
+	*command.Command
+	*piperutils.Files
+}
+
+func newDebugReportUtils() debugReportUtils {
+	return &debugReportUtilsBundle{
+		Command: &command.Command{},
+		Files:   &piperutils.Files{},
+	}
+}
+
+// debugReportStep contains the step information for the debug report, it is set as soon as the step configuration is resolved
+var debugReportStep struct {
+	name   string
+	config map[string]interface{}
+}
+
+// prepareDebugReport keeps the step name and the redacted step configuration for the debug report
+func prepareDebugReport(stepName string, metadata *config.StepData, stepConfig map[string]interface{}) {
+	secretKeys := []string{}
+	for _, param := range metadata.Spec.Inputs.Parameters {
+		if param.Secret {
+			secretKeys = append(secretKeys, param.Name)
+		}
+	}
+	debugReportStep.name = stepName
+	debugReportStep.config = debugreport.RedactConfig(stepConfig, secretKeys)
+}
+
+// registerDebugReport makes sure that a debug report archive is written in case the step fails.
+// It is only called from Execute() so that tests which replace the exit function do not write archives.
+func registerDebugReport() {
+	// exit handlers are only executed for fatal errors
+	log.DeferExitHandler(func() {
+		if GeneralConfig.NoDebugReport || len(debugReportStep.name) == 0 {
+			return
+		}
+		archive, err := writeDebugReport(debugReportStep.name, debugReportStep.config, newDebugReportUtils(), exec.LookPath)
+		if err != nil {
+			log.Entry().WithError(err).Warn("Failed to create debug report")
+			return
+		}
+		log.Entry().Infof("Debug report written to '%s'", archive)
+	})
+}
+
+func writeDebugReport(stepName string, redactedConfig map[string]interface{}, utils debugReportUtils, lookPath func(string) (string, error)) (string, error) {
+	report := debugreport.NewReport(stepName, GitTag, GitCommit)
+	report.Config = redactedConfig
+	report.LogLines = debugLogCollector.Lines()
+	report.CollectToolVersions(utils, lookPath, debugreport.DefaultTools)
+
+	if err := report.CollectErrorDetails(utils, "."); err != nil {
+		log.Entry().WithError(err).Warn("Error details are not part of the debug report")
+	}
+	if err := report.CollectCommonPipelineEnvironment(utils, GeneralConfig.EnvRootPath); err != nil {
+		log.Entry().WithError(err).Warn("The commonPipelineEnvironment is not part of the debug report")
+	}
+
+	var archive bytes.Buffer
+	if err := report.WriteArchive(&archive); err != nil {
+		return "", err
+	}
+	if err := utils.FileWrite(report.ArchiveName(), archive.Bytes(), 0644); err != nil {
+		return "", errors.Wrapf(err, "failed to write debug report '%s'", report.ArchiveName())
+	}
+	return report.ArchiveName(), nil
+}
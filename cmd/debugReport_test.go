@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+type debugReportMockUtils struct {
+	*mock.ExecMockRunner
+	*mock.FilesMock
+}
+
+func TestWriteDebugReport(t *testing.T) {
+	utils := debugReportMockUtils{
+		ExecMockRunner: &mock.ExecMockRunner{StdoutReturn: map[string]string{"git --version": "git version 2.30.0"}},
+		FilesMock:      &mock.FilesMock{},
+	}
+	utils.AddFile("testStep_errorDetails.json", []byte(`{"message":"failed"}`))
+	lookPath := func(file string) (string, error) {
+		if file == "git" {
+			return "/usr/bin/git", nil
+		}
+		return "", fmt.Errorf("not found")
+	}
+
+	archive, err := writeDebugReport("testStep", map[string]interface{}{"password": "****"}, utils, lookPath)
+
+	if assert.NoError(t, err) {
+		assert.Regexp(t, `^testStep_debugReport_.*\.tar\.gz$`, archive)
+		assert.True(t, utils.HasWrittenFile(archive))
+		if assert.Len(t, utils.Calls, 1) {
+			assert.Equal(t, "git", utils.Calls[0].Exec)
+		}
+	}
+}
+
+func TestPrepareDebugReport(t *testing.T) {
+	stepBak := debugReportStep
+	defer func() { debugReportStep = stepBak }()
+	metadata := config.StepData{Spec: config.StepSpec{Inputs: config.StepInputs{Parameters: []config.StepParameters{
+		{Name: "user"},
+		{Name: "apiKey", Secret: true},
+	}}}}
+
+	prepareDebugReport("testStep", &metadata, map[string]interface{}{"user": "me", "apiKey": "key"})
+
+	assert.Equal(t, "testStep", debugReportStep.name)
+	assert.Equal(t, map[string]interface{}{"user": "me", "apiKey": "****"}, debugReportStep.config)
+}
@@ -10,6 +10,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"sort"
 	"strings"
 	"time"
 
@@ -27,6 +28,7 @@ import (
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/maven"
 	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/reporting"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
 	"github.com/SAP/jenkins-library/pkg/versioning"
 
@@ -81,6 +83,12 @@ func fortifyExecuteScan(config fortifyExecuteScanOptions, telemetryData *telemet
 	influx.step_data.fields.fortify = false
 	reports, err := runFortifyScan(config, sys, utils, telemetryData, influx, auditStatus)
 	piperutils.PersistReportsAndLinks("fortifyExecuteScan", config.ModulePath, reports, nil)
+	if len(auditStatus) > 0 {
+		scanReport := createFortifyScanReport(influx, auditStatus)
+		if err := reporting.WriteJSONReport(scanReport, &piperutils.Files{}); err != nil {
+			log.Entry().WithError(err).Warning("failed to write scan report")
+		}
+	}
 	if err != nil {
 		log.Entry().WithError(err).Fatal("Fortify scan and check failed")
 	}
@@ -89,6 +97,34 @@ func fortifyExecuteScan(config fortifyExecuteScanOptions, telemetryData *telemet
 	log.SetErrorCategory(log.ErrorUndefined)
 }
 
+// createFortifyScanReport creates the scan report based on the audit status of the issue groups.
+// It does not contain findings since the issues are only retrieved grouped by their audit status and not with their location in the source code.
+func createFortifyScanReport(influx *fortifyExecuteScanInflux, auditStatus map[string]string) reporting.ScanReport {
+	scanReport := reporting.ScanReport{
+		StepName: "fortifyExecuteScan",
+		Title:    "Fortify SAST Report",
+		Subheaders: []reporting.Subheader{
+			{Description: "Fortify project name", Details: influx.fortify_data.fields.projectName},
+			{Description: "Fortify project version", Details: influx.fortify_data.fields.projectVersion},
+		},
+		Overview: []reporting.OverviewRow{
+			{Description: "Violations", Details: fmt.Sprint(influx.fortify_data.fields.violations)},
+		},
+		ReportTime:     time.Now(),
+		SuccessfulScan: influx.fortify_data.fields.violations == 0,
+	}
+	groups := []string{}
+	for group := range auditStatus {
+		groups = append(groups, group)
+	}
+	// sorting keeps the report stable across pipeline runs
+	sort.Strings(groups)
+	for _, group := range groups {
+		scanReport.Overview = append(scanReport.Overview, reporting.OverviewRow{Description: group, Details: auditStatus[group]})
+	}
+	return scanReport
+}
+
 func determineArtifact(config fortifyExecuteScanOptions, utils fortifyUtils) (versioning.Artifact, error) {
 	versioningOptions := versioning.Options{
 		M2Path:              config.M2Path,
@@ -18,6 +18,7 @@ import (
 
 	"github.com/SAP/jenkins-library/pkg/fortify"
 	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/reporting"
 	"github.com/SAP/jenkins-library/pkg/versioning"
 
 	"github.com/google/go-github/v32/github"
@@ -701,6 +702,25 @@ func TestCalculateTimeDifferenceToLastUpload(t *testing.T) {
 	assert.Equal(t, true, diffSeconds < 1)
 }
 
+func TestCreateFortifyScanReport(t *testing.T) {
+	influx := fortifyExecuteScanInflux{}
+	influx.fortify_data.fields.projectName = "project"
+	influx.fortify_data.fields.projectVersion = "1"
+	influx.fortify_data.fields.violations = 2
+	auditStatus := map[string]string{"Suspicious": "1", "Exploitable": "1", "Corporate Security Requirements": "4 total : 2 audited"}
+
+	scanReport := createFortifyScanReport(&influx, auditStatus)
+
+	assert.Equal(t, "fortifyExecuteScan", scanReport.StepName)
+	assert.False(t, scanReport.SuccessfulScan)
+	assert.Equal(t, []reporting.OverviewRow{
+		{Description: "Violations", Details: "2"},
+		{Description: "Corporate Security Requirements", Details: "4 total : 2 audited"},
+		{Description: "Exploitable", Details: "1"},
+		{Description: "Suspicious", Details: "1"},
+	}, scanReport.Overview)
+}
+
 func TestExecuteTemplatedCommand(t *testing.T) {
 	utils := newFortifyTestUtilsBundle()
 	template := []string{"{{.Executable}}", "-c", "{{.Param}}"}
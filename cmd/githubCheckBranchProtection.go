@@ -3,9 +3,14 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"os"
+	"regexp"
 	"strings"
+	"time"
 
 	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/reporting"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
 	"github.com/google/go-github/v32/github"
 
@@ -14,54 +19,281 @@ import (
 	piperGithub "github.com/SAP/jenkins-library/pkg/github"
 )
 
+const githubPolicyReportPath = "piper_github_policy_report.html"
+
 type gitHubBranchProtectionRepositoriesService interface {
+	Get(ctx context.Context, owner, repo string) (*github.Repository, *github.Response, error)
 	GetBranchProtection(ctx context.Context, owner, repo, branch string) (*github.Protection, *github.Response, error)
+	GetSignaturesProtectedBranch(ctx context.Context, owner, repo, branch string) (*github.SignaturesProtectedBranch, *github.Response, error)
+	GetVulnerabilityAlerts(ctx context.Context, owner, repository string) (bool, *github.Response, error)
+}
+
+type githubCheckBranchProtectionUtils interface {
+	FileWrite(path string, content []byte, perm os.FileMode) error
+	MkdirAll(path string, perm os.FileMode) error
+	SecretScanningEnabled(owner, repository string) (bool, error)
+}
+
+type githubCheckBranchProtectionUtilsBundle struct {
+	*piperutils.Files
+	ctx    context.Context
+	client *github.Client
+}
+
+func (g *githubCheckBranchProtectionUtilsBundle) SecretScanningEnabled(owner, repository string) (bool, error) {
+	return piperGithub.SecretScanningEnabled(g.ctx, g.client, owner, repository)
 }
 
 func githubCheckBranchProtection(config githubCheckBranchProtectionOptions, telemetryData *telemetry.CustomData) {
-	ctx, client, err := piperGithub.NewClient(config.Token, config.APIURL, "")
+	appOptions := piperGithub.AppOptions{
+		AppID:          config.GithubAppID,
+		InstallationID: config.GithubAppInstallationID,
+		PrivateKeyFile: config.GithubAppPrivateKey,
+		Owner:          config.Owner,
+		Repository:     config.Repository,
+	}
+	ctx, client, err := piperGithub.NewClientWithAuth(config.Token, appOptions, config.APIURL, "")
 	if err != nil {
 		log.Entry().WithError(err).Fatal("Failed to get GitHub client")
 	}
 
-	err = runGithubCheckBranchProtection(ctx, &config, telemetryData, client.Repositories)
+	utils := &githubCheckBranchProtectionUtilsBundle{Files: &piperutils.Files{}, ctx: ctx, client: client}
+	err = runGithubCheckBranchProtection(ctx, &config, telemetryData, client.Repositories, utils)
+	piperutils.PersistReportsAndLinks("githubCheckBranchProtection", "", []piperutils.Path{{Name: "GitHub Repository Policy Report", Target: githubPolicyReportPath}}, nil)
 	if err != nil {
 		log.Entry().WithError(err).Fatal("GitHub branch protection check failed")
 	}
 }
 
-func runGithubCheckBranchProtection(ctx context.Context, config *githubCheckBranchProtectionOptions, telemetryData *telemetry.CustomData, ghRepositoriesService gitHubBranchProtectionRepositoriesService) error {
+// policyRule is the result of evaluating a single rule of the repository policy
+type policyRule struct {
+	description string
+	expected    string
+	actual      string
+	// violation describes why the rule is not fulfilled, it is empty for compliant rules
+	violation string
+}
+
+type policyEvaluation struct {
+	rules []policyRule
+}
+
+func (p *policyEvaluation) check(description, expected, actual string, compliant bool, violation string) {
+	rule := policyRule{description: description, expected: expected, actual: actual}
+	if !compliant {
+		rule.violation = violation
+	}
+	p.rules = append(p.rules, rule)
+}
+
+func (p *policyEvaluation) violations() []string {
+	violations := []string{}
+	for _, rule := range p.rules {
+		if len(rule.violation) > 0 {
+			violations = append(violations, rule.violation)
+		}
+	}
+	return violations
+}
+
+func runGithubCheckBranchProtection(ctx context.Context, config *githubCheckBranchProtectionOptions, telemetryData *telemetry.CustomData, ghRepositoriesService gitHubBranchProtectionRepositoriesService, utils githubCheckBranchProtectionUtils) error {
 	ghProtection, _, err := ghRepositoriesService.GetBranchProtection(ctx, config.Owner, config.Repository, config.Branch)
 	if err != nil {
 		return errors.Wrap(err, "failed to read branch protection information")
 	}
 
+	policy := policyEvaluation{}
+
 	// validate required status checks
+	foundContexts := []string{}
+	if requiredStatusChecks := ghProtection.GetRequiredStatusChecks(); requiredStatusChecks != nil {
+		foundContexts = requiredStatusChecks.Contexts
+	}
 	for _, check := range config.RequiredChecks {
 		var found bool
-		foundContexts := []string{}
-		if requiredStatusChecks := ghProtection.GetRequiredStatusChecks(); requiredStatusChecks != nil {
-			foundContexts = requiredStatusChecks.Contexts
-		}
 		for _, context := range foundContexts {
-			if check == context {
+			if statusCheckMatches(check, context) {
 				found = true
 			}
 		}
-		if !found {
-			return fmt.Errorf("required status check '%v' not found among '%v' in branch protection configuration", check, strings.Join(foundContexts, ","))
-		}
+		policy.check(fmt.Sprintf("Required status check '%v'", check), "required", strings.Join(foundContexts, ", "), found,
+			fmt.Sprintf("required status check '%v' not found among '%v' in branch protection configuration", check, strings.Join(foundContexts, ",")))
 	}
 
 	// validate that admins are enforced in checks
-	if config.RequireEnforceAdmins && !ghProtection.GetEnforceAdmins().Enabled {
-		return fmt.Errorf("admins are not enforced in branch protection configuration")
+	if config.RequireEnforceAdmins {
+		enforced := ghProtection.GetEnforceAdmins() != nil && ghProtection.GetEnforceAdmins().Enabled
+		policy.check("Include administrators", enabledText(true), enabledText(enforced), enforced, "admins are not enforced in branch protection configuration")
 	}
 
+	reviews := ghProtection.GetRequiredPullRequestReviews()
+	if reviews == nil {
+		reviews = &github.PullRequestReviewsEnforcement{}
+	}
 	// validate number of mandatory reviewers
-	if config.RequiredApprovingReviewCount > 0 && ghProtection.GetRequiredPullRequestReviews().RequiredApprovingReviewCount < config.RequiredApprovingReviewCount {
-		return fmt.Errorf("not enough mandatory reviewers in branch protection configuration, expected at least %v, got %v", config.RequiredApprovingReviewCount, ghProtection.GetRequiredPullRequestReviews().RequiredApprovingReviewCount)
+	if config.RequiredApprovingReviewCount > 0 {
+		policy.check("Required approving reviews", fmt.Sprintf("at least %v", config.RequiredApprovingReviewCount), fmt.Sprint(reviews.RequiredApprovingReviewCount),
+			reviews.RequiredApprovingReviewCount >= config.RequiredApprovingReviewCount,
+			fmt.Sprintf("not enough mandatory reviewers in branch protection configuration, expected at least %v, got %v", config.RequiredApprovingReviewCount, reviews.RequiredApprovingReviewCount))
+	}
+	if config.RequireCodeOwnerReviews {
+		policy.check("Require review from code owners", enabledText(true), enabledText(reviews.RequireCodeOwnerReviews), reviews.RequireCodeOwnerReviews,
+			"code owner reviews are not required in branch protection configuration")
+	}
+	if config.RequireDismissStaleReviews {
+		policy.check("Dismiss stale reviews", enabledText(true), enabledText(reviews.DismissStaleReviews), reviews.DismissStaleReviews,
+			"stale reviews are not dismissed in branch protection configuration")
+	}
+
+	if config.RequireLinearHistory {
+		linear := ghProtection.GetRequireLinearHistory() != nil && ghProtection.GetRequireLinearHistory().Enabled
+		policy.check("Require linear history", enabledText(true), enabledText(linear), linear, "linear history is not required in branch protection configuration")
+	}
+
+	if config.RequireSignedCommits {
+		signatures, _, err := ghRepositoriesService.GetSignaturesProtectedBranch(ctx, config.Owner, config.Repository, config.Branch)
+		if err != nil {
+			return errors.Wrap(err, "failed to read commit signature protection information")
+		}
+		policy.check("Require signed commits", enabledText(true), enabledText(signatures.GetEnabled()), signatures.GetEnabled(), "signed commits are not required in branch protection configuration")
+	}
+
+	if config.RequirePushRestrictions || len(config.AllowedPushers) > 0 {
+		restrictions := ghProtection.Restrictions
+		policy.check("Restrict who can push", enabledText(true), enabledText(restrictions != nil), restrictions != nil, "pushes are not restricted in branch protection configuration")
+		if restrictions != nil && len(config.AllowedPushers) > 0 {
+			pushers := []string{}
+			for _, user := range restrictions.Users {
+				pushers = append(pushers, user.GetLogin())
+			}
+			for _, team := range restrictions.Teams {
+				pushers = append(pushers, team.GetSlug())
+			}
+			for _, app := range restrictions.Apps {
+				pushers = append(pushers, app.GetSlug())
+			}
+			unexpected := []string{}
+			for _, pusher := range pushers {
+				if !piperutils.ContainsString(config.AllowedPushers, pusher) {
+					unexpected = append(unexpected, pusher)
+				}
+			}
+			policy.check("Allowed pushers", strings.Join(config.AllowedPushers, ", "), strings.Join(pushers, ", "), len(unexpected) == 0,
+				fmt.Sprintf("'%v' may push to the branch but are not among the allowed pushers", strings.Join(unexpected, ",")))
+		}
+	}
+
+	if len(config.AllowedMergeMethods) > 0 {
+		for _, method := range config.AllowedMergeMethods {
+			if !piperutils.ContainsString([]string{"merge", "squash", "rebase"}, method) {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return fmt.Errorf("merge method '%v' is not supported, use one of merge, squash or rebase", method)
+			}
+		}
+		repository, _, err := ghRepositoriesService.Get(ctx, config.Owner, config.Repository)
+		if err != nil {
+			return errors.Wrap(err, "failed to read repository information")
+		}
+		enabled := []string{}
+		if repository.GetAllowMergeCommit() {
+			enabled = append(enabled, "merge")
+		}
+		if repository.GetAllowSquashMerge() {
+			enabled = append(enabled, "squash")
+		}
+		if repository.GetAllowRebaseMerge() {
+			enabled = append(enabled, "rebase")
+		}
+		notAllowed := []string{}
+		for _, method := range enabled {
+			if !piperutils.ContainsString(config.AllowedMergeMethods, method) {
+				notAllowed = append(notAllowed, method)
+			}
+		}
+		policy.check("Allowed merge methods", strings.Join(config.AllowedMergeMethods, ", "), strings.Join(enabled, ", "), len(notAllowed) == 0,
+			fmt.Sprintf("merge methods '%v' are enabled but not allowed", strings.Join(notAllowed, ",")))
 	}
 
+	if config.RequireDependabotAlerts {
+		alerts, _, err := ghRepositoriesService.GetVulnerabilityAlerts(ctx, config.Owner, config.Repository)
+		if err != nil {
+			return errors.Wrap(err, "failed to read Dependabot alerts setting")
+		}
+		policy.check("Dependabot alerts", enabledText(true), enabledText(alerts), alerts, "Dependabot alerts are not enabled for the repository")
+	}
+
+	if config.RequireSecretScanning {
+		secretScanning, err := utils.SecretScanningEnabled(config.Owner, config.Repository)
+		if err != nil {
+			return err
+		}
+		policy.check("Secret scanning", enabledText(true), enabledText(secretScanning), secretScanning, "secret scanning is not enabled for the repository")
+	}
+
+	if err := writePolicyReport(config, policy, utils); err != nil {
+		return err
+	}
+
+	if violations := policy.violations(); len(violations) > 0 {
+		log.SetErrorCategory(log.ErrorCompliance)
+		return fmt.Errorf("repository policy is violated: %v", strings.Join(violations, "; "))
+	}
 	return nil
 }
+
+func statusCheckMatches(pattern, context string) bool {
+	if !strings.Contains(pattern, "*") {
+		return pattern == context
+	}
+	expression := "^" + strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*") + "$"
+	matches, _ := regexp.MatchString(expression, context)
+	return matches
+}
+
+func enabledText(enabled bool) string {
+	if enabled {
+		return "enabled"
+	}
+	return "disabled"
+}
+
+func writePolicyReport(config *githubCheckBranchProtectionOptions, policy policyEvaluation, utils githubCheckBranchProtectionUtils) error {
+	violations := policy.violations()
+	scanReport := reporting.ScanReport{
+		StepName: "githubCheckBranchProtection",
+		Title:    "GitHub Repository Policy Report",
+		Subheaders: []reporting.Subheader{
+			{Description: "Repository", Details: fmt.Sprintf("%v/%v", config.Owner, config.Repository)},
+			{Description: "Branch", Details: config.Branch},
+		},
+		Overview: []reporting.OverviewRow{
+			{Description: "Evaluated rules", Details: fmt.Sprint(len(policy.rules))},
+			{Description: "Violations", Details: fmt.Sprint(len(violations))},
+		},
+		ReportTime:     time.Now(),
+		SuccessfulScan: len(violations) == 0,
+		DetailTable: reporting.ScanDetailTable{
+			Headers:       []string{"Rule", "Expected", "Actual", "Status"},
+			NoRowsMessage: "No rules configured",
+		},
+	}
+	for _, rule := range policy.rules {
+		row := reporting.ScanRow{}
+		row.AddColumn(rule.description, 0)
+		row.AddColumn(rule.expected, 0)
+		row.AddColumn(rule.actual, 0)
+		if len(rule.violation) > 0 {
+			row.AddColumn("violated", reporting.Red)
+		} else {
+			row.AddColumn("compliant", reporting.Green)
+		}
+		scanReport.DetailTable.Rows = append(scanReport.DetailTable.Rows, row)
+	}
+
+	// ignore templating errors since template is in our hands
+	htmlReport, _ := scanReport.ToHTML()
+	if err := utils.FileWrite(githubPolicyReportPath, htmlReport, 0666); err != nil {
+		return errors.Wrap(err, "failed to write policy report")
+	}
+	return reporting.WriteJSONReport(scanReport, utils)
+}
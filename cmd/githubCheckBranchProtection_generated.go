@@ -14,13 +14,25 @@ import (
 )
 
 type githubCheckBranchProtectionOptions struct {
+	AllowedMergeMethods          []string `json:"allowedMergeMethods,omitempty"`
+	AllowedPushers               []string `json:"allowedPushers,omitempty"`
 	APIURL                       string   `json:"apiUrl,omitempty"`
 	Branch                       string   `json:"branch,omitempty"`
+	GithubAppID                  int      `json:"githubAppId,omitempty"`
+	GithubAppInstallationID      int      `json:"githubAppInstallationId,omitempty"`
+	GithubAppPrivateKey          string   `json:"githubAppPrivateKey,omitempty"`
 	Owner                        string   `json:"owner,omitempty"`
 	Repository                   string   `json:"repository,omitempty"`
+	RequireCodeOwnerReviews      bool     `json:"requireCodeOwnerReviews,omitempty"`
+	RequiredApprovingReviewCount int      `json:"requiredApprovingReviewCount,omitempty"`
 	RequiredChecks               []string `json:"requiredChecks,omitempty"`
+	RequireDependabotAlerts      bool     `json:"requireDependabotAlerts,omitempty"`
+	RequireDismissStaleReviews   bool     `json:"requireDismissStaleReviews,omitempty"`
 	RequireEnforceAdmins         bool     `json:"requireEnforceAdmins,omitempty"`
-	RequiredApprovingReviewCount int      `json:"requiredApprovingReviewCount,omitempty"`
+	RequireLinearHistory         bool     `json:"requireLinearHistory,omitempty"`
+	RequirePushRestrictions      bool     `json:"requirePushRestrictions,omitempty"`
+	RequireSecretScanning        bool     `json:"requireSecretScanning,omitempty"`
+	RequireSignedCommits         bool     `json:"requireSignedCommits,omitempty"`
 	Token                        string   `json:"token,omitempty"`
 }
 
@@ -37,7 +49,12 @@ func GithubCheckBranchProtectionCommand() *cobra.Command {
 		Short: "Check branch protection of a GitHub branch",
 		Long: `This step allows you to check if certain branch protection rules are fulfilled.
 
-It can for example be used to verify if certain status checks are mandatory. This can be helpful to decide if a certain check needs to be performed again after merging a pull request.`,
+It can for example be used to verify if certain status checks are mandatory. This can be helpful to decide if a certain check needs to be performed again after merging a pull request.
+
+Beyond branch protection the step verifies repository settings like the allowed merge methods, secret scanning and Dependabot alerts.
+Thus the parameters of the step form a declarative policy for a repository, which is evaluated as a whole.
+All rules are listed with their expected and actual values in a compliance report (` + "`" + `piper_github_policy_report.html` + "`" + ` plus a JSON report for ` + "`" + `pipelineCreateScanSummary` + "`" + `), which can serve as evidence for audits.
+The step fails in case any rule is violated.`,
 		PreRunE: func(cmd *cobra.Command, _ []string) error {
 			startTime = time.Now()
 			log.SetStepName(STEP_NAME)
@@ -52,6 +69,7 @@ It can for example be used to verify if certain status checks are mandatory. Thi
 				log.SetErrorCategory(log.ErrorConfiguration)
 				return err
 			}
+			log.RegisterSecret(stepConfig.GithubAppPrivateKey)
 			log.RegisterSecret(stepConfig.Token)
 
 			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
@@ -84,20 +102,31 @@ It can for example be used to verify if certain status checks are mandatory. Thi
 }
 
 func addGithubCheckBranchProtectionFlags(cmd *cobra.Command, stepConfig *githubCheckBranchProtectionOptions) {
+	cmd.Flags().StringSliceVar(&stepConfig.AllowedMergeMethods, "allowedMergeMethods", []string{}, "Merge methods which may be enabled in the repository settings, one of `merge`, `squash` or `rebase`. Enabled methods not contained in the list are violations.")
+	cmd.Flags().StringSliceVar(&stepConfig.AllowedPushers, "allowedPushers", []string{}, "Users, teams (slug) or apps (slug) which may be allowed to push to the branch. Implies that pushes need to be restricted.")
 	cmd.Flags().StringVar(&stepConfig.APIURL, "apiUrl", `https://api.github.com`, "Set the GitHub API url.")
 	cmd.Flags().StringVar(&stepConfig.Branch, "branch", `master`, "The name of the branch for which the protection settings should be checked.")
+	cmd.Flags().IntVar(&stepConfig.GithubAppID, "githubAppId", 0, "ID of the GitHub App used to authenticate to GitHub instead of a personal access token. The step authenticates as installation of the app, see [authenticating with GitHub Apps](https://docs.github.com/en/developers/apps/authenticating-with-github-apps).")
+	cmd.Flags().IntVar(&stepConfig.GithubAppInstallationID, "githubAppInstallationId", 0, "ID of the installation of the GitHub App. If not provided, the installation for `owner` and `repository` is looked up.")
+	cmd.Flags().StringVar(&stepConfig.GithubAppPrivateKey, "githubAppPrivateKey", os.Getenv("PIPER_githubAppPrivateKey"), "Path to the file containing the PEM encoded private key of the GitHub App.")
 	cmd.Flags().StringVar(&stepConfig.Owner, "owner", os.Getenv("PIPER_owner"), "Name of the GitHub organization.")
 	cmd.Flags().StringVar(&stepConfig.Repository, "repository", os.Getenv("PIPER_repository"), "Name of the GitHub repository.")
-	cmd.Flags().StringSliceVar(&stepConfig.RequiredChecks, "requiredChecks", []string{}, "List of checks which have to be set to 'required' in the GitHub repository configuration.")
-	cmd.Flags().BoolVar(&stepConfig.RequireEnforceAdmins, "requireEnforceAdmins", false, "Check if 'Include Administrators' option is set in the GitHub repository configuration.")
+	cmd.Flags().BoolVar(&stepConfig.RequireCodeOwnerReviews, "requireCodeOwnerReviews", false, "Check if 'Require review from Code Owners' option is set in the branch protection configuration.")
 	cmd.Flags().IntVar(&stepConfig.RequiredApprovingReviewCount, "requiredApprovingReviewCount", 0, "Check if 'Require pull request reviews before merging' option is set with at least the defined number of reviewers in the GitHub repository configuration.")
-	cmd.Flags().StringVar(&stepConfig.Token, "token", os.Getenv("PIPER_token"), "GitHub personal access token as per https://help.github.com/en/github/authenticating-to-github/creating-a-personal-access-token-for-the-command-line.")
+	cmd.Flags().StringSliceVar(&stepConfig.RequiredChecks, "requiredChecks", []string{}, "List of checks which have to be set to 'required' in the GitHub repository configuration. Names may contain `*` as wildcard, e.g. `continuous-integration/*`.")
+	cmd.Flags().BoolVar(&stepConfig.RequireDependabotAlerts, "requireDependabotAlerts", false, "Check if Dependabot alerts (vulnerability alerts) are enabled for the repository.")
+	cmd.Flags().BoolVar(&stepConfig.RequireDismissStaleReviews, "requireDismissStaleReviews", false, "Check if 'Dismiss stale pull request approvals when new commits are pushed' option is set in the branch protection configuration.")
+	cmd.Flags().BoolVar(&stepConfig.RequireEnforceAdmins, "requireEnforceAdmins", false, "Check if 'Include Administrators' option is set in the GitHub repository configuration.")
+	cmd.Flags().BoolVar(&stepConfig.RequireLinearHistory, "requireLinearHistory", false, "Check if 'Require linear history' option is set in the branch protection configuration.")
+	cmd.Flags().BoolVar(&stepConfig.RequirePushRestrictions, "requirePushRestrictions", false, "Check if 'Restrict who can push to matching branches' option is set in the branch protection configuration.")
+	cmd.Flags().BoolVar(&stepConfig.RequireSecretScanning, "requireSecretScanning", false, "Check if secret scanning is enabled for the repository.")
+	cmd.Flags().BoolVar(&stepConfig.RequireSignedCommits, "requireSignedCommits", false, "Check if 'Require signed commits' option is set in the branch protection configuration.")
+	cmd.Flags().StringVar(&stepConfig.Token, "token", os.Getenv("PIPER_token"), "GitHub personal access token as per https://help.github.com/en/github/authenticating-to-github/creating-a-personal-access-token-for-the-command-line. Not required in case a GitHub App is used for authentication (see `githubAppId`).")
 
 	cmd.MarkFlagRequired("apiUrl")
 	cmd.MarkFlagRequired("branch")
 	cmd.MarkFlagRequired("owner")
 	cmd.MarkFlagRequired("repository")
-	cmd.MarkFlagRequired("token")
 }
 
 // retrieve step metadata
@@ -111,6 +140,22 @@ func githubCheckBranchProtectionMetadata() config.StepData {
 		Spec: config.StepSpec{
 			Inputs: config.StepInputs{
 				Parameters: []config.StepParameters{
+					{
+						Name:        "allowedMergeMethods",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "allowedPushers",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
 					{
 						Name:        "apiUrl",
 						ResourceRef: []config.ResourceReference{},
@@ -127,6 +172,41 @@ func githubCheckBranchProtectionMetadata() config.StepData {
 						Mandatory:   true,
 						Aliases:     []config.Alias{},
 					},
+					{
+						Name:        "githubAppId",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "githubAppInstallationId",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name: "githubAppPrivateKey",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "githubAppPrivateKeyCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:  "",
+								Paths: []string{"$(vaultPath)/github-app-private-key", "$(vaultBasePath)/$(vaultPipelineName)/github-app-private-key", "$(vaultBasePath)/GROUP-SECRETS/github-app-private-key"},
+								Type:  "vaultSecretFile",
+							},
+						},
+						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+					},
 					{
 						Name: "owner",
 						ResourceRef: []config.ResourceReference{
@@ -153,6 +233,22 @@ func githubCheckBranchProtectionMetadata() config.StepData {
 						Mandatory: true,
 						Aliases:   []config.Alias{{Name: "githubRepo"}},
 					},
+					{
+						Name:        "requireCodeOwnerReviews",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "requiredApprovingReviewCount",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
 					{
 						Name:        "requiredChecks",
 						ResourceRef: []config.ResourceReference{},
@@ -161,6 +257,22 @@ func githubCheckBranchProtectionMetadata() config.StepData {
 						Mandatory:   false,
 						Aliases:     []config.Alias{},
 					},
+					{
+						Name:        "requireDependabotAlerts",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "requireDismissStaleReviews",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
 					{
 						Name:        "requireEnforceAdmins",
 						ResourceRef: []config.ResourceReference{},
@@ -170,10 +282,34 @@ func githubCheckBranchProtectionMetadata() config.StepData {
 						Aliases:     []config.Alias{},
 					},
 					{
-						Name:        "requiredApprovingReviewCount",
+						Name:        "requireLinearHistory",
 						ResourceRef: []config.ResourceReference{},
 						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
-						Type:        "int",
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "requirePushRestrictions",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "requireSecretScanning",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "requireSignedCommits",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
 						Mandatory:   false,
 						Aliases:     []config.Alias{},
 					},
@@ -193,7 +329,7 @@ func githubCheckBranchProtectionMetadata() config.StepData {
 						},
 						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
 						Type:      "string",
-						Mandatory: true,
+						Mandatory: false,
 						Aliases:   []config.Alias{{Name: "githubToken"}, {Name: "access_token"}},
 					},
 				},
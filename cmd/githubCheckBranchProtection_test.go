@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/SAP/jenkins-library/pkg/mock"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
 
 	"github.com/google/go-github/v32/github"
@@ -12,11 +13,19 @@ import (
 )
 
 type ghCheckBranchRepoService struct {
-	protection   github.Protection
-	serviceError error
-	owner        string
-	repo         string
-	branch       string
+	protection    github.Protection
+	repository    github.Repository
+	signatures    bool
+	alerts        bool
+	serviceError  error
+	settingsError error
+	owner         string
+	repo          string
+	branch        string
+}
+
+func (g *ghCheckBranchRepoService) Get(ctx context.Context, owner, repo string) (*github.Repository, *github.Response, error) {
+	return &g.repository, nil, g.settingsError
 }
 
 func (g *ghCheckBranchRepoService) GetBranchProtection(ctx context.Context, owner, repo, branch string) (*github.Protection, *github.Response, error) {
@@ -27,6 +36,27 @@ func (g *ghCheckBranchRepoService) GetBranchProtection(ctx context.Context, owne
 	return &g.protection, nil, g.serviceError
 }
 
+func (g *ghCheckBranchRepoService) GetSignaturesProtectedBranch(ctx context.Context, owner, repo, branch string) (*github.SignaturesProtectedBranch, *github.Response, error) {
+	return &github.SignaturesProtectedBranch{Enabled: &g.signatures}, nil, g.settingsError
+}
+
+func (g *ghCheckBranchRepoService) GetVulnerabilityAlerts(ctx context.Context, owner, repository string) (bool, *github.Response, error) {
+	return g.alerts, nil, g.settingsError
+}
+
+type ghCheckBranchProtectionUtilsMock struct {
+	*mock.FilesMock
+	secretScanning bool
+}
+
+func (g *ghCheckBranchProtectionUtilsMock) SecretScanningEnabled(owner, repository string) (bool, error) {
+	return g.secretScanning, nil
+}
+
+func newGhCheckBranchProtectionUtilsMock() *ghCheckBranchProtectionUtilsMock {
+	return &ghCheckBranchProtectionUtilsMock{FilesMock: &mock.FilesMock{}}
+}
+
 func TestRunGithubCheckBranchProtection(t *testing.T) {
 	ctx := context.Background()
 	telemetryData := telemetry.CustomData{}
@@ -34,7 +64,7 @@ func TestRunGithubCheckBranchProtection(t *testing.T) {
 	t.Run("no checks active", func(t *testing.T) {
 		config := githubCheckBranchProtectionOptions{Branch: "testBranch", Owner: "testOrg", Repository: "testRepo"}
 		ghRepo := ghCheckBranchRepoService{}
-		err := runGithubCheckBranchProtection(ctx, &config, &telemetryData, &ghRepo)
+		err := runGithubCheckBranchProtection(ctx, &config, &telemetryData, &ghRepo, newGhCheckBranchProtectionUtilsMock())
 		assert.NoError(t, err)
 		assert.Equal(t, config.Branch, ghRepo.branch)
 		assert.Equal(t, config.Owner, ghRepo.owner)
@@ -44,7 +74,7 @@ func TestRunGithubCheckBranchProtection(t *testing.T) {
 	t.Run("error calling GitHub", func(t *testing.T) {
 		config := githubCheckBranchProtectionOptions{Branch: "testBranch", Owner: "testOrg", Repository: "testRepo"}
 		ghRepo := ghCheckBranchRepoService{serviceError: fmt.Errorf("gh test error")}
-		err := runGithubCheckBranchProtection(ctx, &config, &telemetryData, &ghRepo)
+		err := runGithubCheckBranchProtection(ctx, &config, &telemetryData, &ghRepo, newGhCheckBranchProtectionUtilsMock())
 		assert.EqualError(t, err, "failed to read branch protection information: gh test error")
 	})
 
@@ -62,11 +92,13 @@ func TestRunGithubCheckBranchProtection(t *testing.T) {
 			EnforceAdmins:              &github.AdminEnforcement{Enabled: true},
 			RequiredPullRequestReviews: &github.PullRequestReviewsEnforcement{RequiredApprovingReviewCount: 1},
 		}}
-		err := runGithubCheckBranchProtection(ctx, &config, &telemetryData, &ghRepo)
+		utils := newGhCheckBranchProtectionUtilsMock()
+		err := runGithubCheckBranchProtection(ctx, &config, &telemetryData, &ghRepo, utils)
 		assert.NoError(t, err)
 		assert.Equal(t, config.Branch, ghRepo.branch)
 		assert.Equal(t, config.Owner, ghRepo.owner)
 		assert.Equal(t, config.Repository, ghRepo.repo)
+		assert.True(t, utils.HasWrittenFile(githubPolicyReportPath))
 	})
 
 	t.Run("no status checks", func(t *testing.T) {
@@ -74,7 +106,7 @@ func TestRunGithubCheckBranchProtection(t *testing.T) {
 			RequiredChecks: []string{"check1", "check2"},
 		}
 		ghRepo := ghCheckBranchRepoService{protection: github.Protection{}}
-		err := runGithubCheckBranchProtection(ctx, &config, &telemetryData, &ghRepo)
+		err := runGithubCheckBranchProtection(ctx, &config, &telemetryData, &ghRepo, newGhCheckBranchProtectionUtilsMock())
 		assert.Contains(t, fmt.Sprint(err), "required status check 'check1' not found")
 	})
 
@@ -85,10 +117,21 @@ func TestRunGithubCheckBranchProtection(t *testing.T) {
 		ghRepo := ghCheckBranchRepoService{protection: github.Protection{
 			RequiredStatusChecks: &github.RequiredStatusChecks{Contexts: []string{"check0", "check1"}},
 		}}
-		err := runGithubCheckBranchProtection(ctx, &config, &telemetryData, &ghRepo)
+		err := runGithubCheckBranchProtection(ctx, &config, &telemetryData, &ghRepo, newGhCheckBranchProtectionUtilsMock())
 		assert.Contains(t, fmt.Sprint(err), "required status check 'check2' not found")
 	})
 
+	t.Run("status check pattern", func(t *testing.T) {
+		config := githubCheckBranchProtectionOptions{
+			RequiredChecks: []string{"continuous-integration/*", "security/*"},
+		}
+		ghRepo := ghCheckBranchRepoService{protection: github.Protection{
+			RequiredStatusChecks: &github.RequiredStatusChecks{Contexts: []string{"continuous-integration/jenkins/pr-merge"}},
+		}}
+		err := runGithubCheckBranchProtection(ctx, &config, &telemetryData, &ghRepo, newGhCheckBranchProtectionUtilsMock())
+		assert.EqualError(t, err, "repository policy is violated: required status check 'security/*' not found among 'continuous-integration/jenkins/pr-merge' in branch protection configuration")
+	})
+
 	t.Run("admin enforcement inactive", func(t *testing.T) {
 		config := githubCheckBranchProtectionOptions{
 			RequireEnforceAdmins: true,
@@ -96,7 +139,7 @@ func TestRunGithubCheckBranchProtection(t *testing.T) {
 		ghRepo := ghCheckBranchRepoService{protection: github.Protection{
 			EnforceAdmins: &github.AdminEnforcement{Enabled: false},
 		}}
-		err := runGithubCheckBranchProtection(ctx, &config, &telemetryData, &ghRepo)
+		err := runGithubCheckBranchProtection(ctx, &config, &telemetryData, &ghRepo, newGhCheckBranchProtectionUtilsMock())
 		assert.Contains(t, fmt.Sprint(err), "admins are not enforced")
 	})
 
@@ -107,8 +150,100 @@ func TestRunGithubCheckBranchProtection(t *testing.T) {
 		ghRepo := ghCheckBranchRepoService{protection: github.Protection{
 			RequiredPullRequestReviews: &github.PullRequestReviewsEnforcement{RequiredApprovingReviewCount: 1},
 		}}
-		err := runGithubCheckBranchProtection(ctx, &config, &telemetryData, &ghRepo)
+		err := runGithubCheckBranchProtection(ctx, &config, &telemetryData, &ghRepo, newGhCheckBranchProtectionUtilsMock())
 		assert.Contains(t, fmt.Sprint(err), "not enough mandatory reviewers")
 	})
 
+	t.Run("full policy compliant", func(t *testing.T) {
+		config := githubCheckBranchProtectionOptions{
+			Owner:                      "testOrg",
+			Repository:                 "testRepo",
+			Branch:                     "master",
+			RequireCodeOwnerReviews:    true,
+			RequireDismissStaleReviews: true,
+			RequireLinearHistory:       true,
+			RequireSignedCommits:       true,
+			RequirePushRestrictions:    true,
+			AllowedPushers:             []string{"admin", "release-team", "piper-bot"},
+			AllowedMergeMethods:        []string{"squash", "rebase"},
+			RequireDependabotAlerts:    true,
+			RequireSecretScanning:      true,
+		}
+		ghRepo := ghCheckBranchRepoService{
+			protection: github.Protection{
+				RequiredPullRequestReviews: &github.PullRequestReviewsEnforcement{RequireCodeOwnerReviews: true, DismissStaleReviews: true},
+				RequireLinearHistory:       &github.RequireLinearHistory{Enabled: true},
+				Restrictions: &github.BranchRestrictions{
+					Users: []*github.User{{Login: github.String("admin")}},
+					Teams: []*github.Team{{Slug: github.String("release-team")}},
+					Apps:  []*github.App{{Slug: github.String("piper-bot")}},
+				},
+			},
+			repository: github.Repository{AllowSquashMerge: github.Bool(true), AllowMergeCommit: github.Bool(false)},
+			signatures: true,
+			alerts:     true,
+		}
+		utils := newGhCheckBranchProtectionUtilsMock()
+		utils.secretScanning = true
+
+		err := runGithubCheckBranchProtection(ctx, &config, &telemetryData, &ghRepo, utils)
+
+		if assert.NoError(t, err) {
+			reports, _ := utils.Glob(".pipeline/stepReports/githubCheckBranchProtection_*.json")
+			if assert.Len(t, reports, 1) {
+				content, _ := utils.FileRead(reports[0])
+				assert.Contains(t, string(content), `"successfulScan":true`)
+				assert.Contains(t, string(content), `{"description":"Evaluated rules","details":"9"}`)
+			}
+		}
+	})
+
+	t.Run("full policy violated", func(t *testing.T) {
+		config := githubCheckBranchProtectionOptions{
+			RequireCodeOwnerReviews:    true,
+			RequireDismissStaleReviews: true,
+			RequireLinearHistory:       true,
+			RequireSignedCommits:       true,
+			AllowedPushers:             []string{"admin"},
+			AllowedMergeMethods:        []string{"squash"},
+			RequireDependabotAlerts:    true,
+			RequireSecretScanning:      true,
+		}
+		ghRepo := ghCheckBranchRepoService{
+			protection: github.Protection{
+				Restrictions: &github.BranchRestrictions{Users: []*github.User{{Login: github.String("admin")}, {Login: github.String("jdoe")}}},
+			},
+			repository: github.Repository{AllowSquashMerge: github.Bool(true), AllowMergeCommit: github.Bool(true)},
+		}
+		utils := newGhCheckBranchProtectionUtilsMock()
+
+		err := runGithubCheckBranchProtection(ctx, &config, &telemetryData, &ghRepo, utils)
+
+		assert.EqualError(t, err, "repository policy is violated: code owner reviews are not required in branch protection configuration; "+
+			"stale reviews are not dismissed in branch protection configuration; linear history is not required in branch protection configuration; "+
+			"signed commits are not required in branch protection configuration; 'jdoe' may push to the branch but are not among the allowed pushers; "+
+			"merge methods 'merge' are enabled but not allowed; Dependabot alerts are not enabled for the repository; secret scanning is not enabled for the repository")
+		assert.True(t, utils.HasWrittenFile(githubPolicyReportPath))
+	})
+
+	t.Run("pushes not restricted", func(t *testing.T) {
+		config := githubCheckBranchProtectionOptions{AllowedPushers: []string{"admin"}}
+		ghRepo := ghCheckBranchRepoService{}
+		err := runGithubCheckBranchProtection(ctx, &config, &telemetryData, &ghRepo, newGhCheckBranchProtectionUtilsMock())
+		assert.EqualError(t, err, "repository policy is violated: pushes are not restricted in branch protection configuration")
+	})
+
+	t.Run("unsupported merge method", func(t *testing.T) {
+		config := githubCheckBranchProtectionOptions{AllowedMergeMethods: []string{"fast-forward"}}
+		ghRepo := ghCheckBranchRepoService{}
+		err := runGithubCheckBranchProtection(ctx, &config, &telemetryData, &ghRepo, newGhCheckBranchProtectionUtilsMock())
+		assert.EqualError(t, err, "merge method 'fast-forward' is not supported, use one of merge, squash or rebase")
+	})
+
+	t.Run("error reading repository settings", func(t *testing.T) {
+		config := githubCheckBranchProtectionOptions{RequireDependabotAlerts: true}
+		ghRepo := ghCheckBranchRepoService{settingsError: fmt.Errorf("forbidden")}
+		err := runGithubCheckBranchProtection(ctx, &config, &telemetryData, &ghRepo, newGhCheckBranchProtectionUtilsMock())
+		assert.EqualError(t, err, "failed to read Dependabot alerts setting: forbidden")
+	})
 }
@@ -16,7 +16,14 @@ type githubIssueCommentService interface {
 }
 
 func githubCommentIssue(config githubCommentIssueOptions, telemetryData *telemetry.CustomData) {
-	ctx, client, err := piperGithub.NewClient(config.Token, config.APIURL, "")
+	appOptions := piperGithub.AppOptions{
+		AppID:          config.GithubAppID,
+		InstallationID: config.GithubAppInstallationID,
+		PrivateKeyFile: config.GithubAppPrivateKey,
+		Owner:          config.Owner,
+		Repository:     config.Repository,
+	}
+	ctx, client, err := piperGithub.NewClientWithAuth(config.Token, appOptions, config.APIURL, "")
 	if err != nil {
 		log.Entry().WithError(err).Fatal("Failed to get GitHub client")
 	}
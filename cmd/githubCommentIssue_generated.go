@@ -14,12 +14,15 @@ import (
 )
 
 type githubCommentIssueOptions struct {
-	APIURL     string `json:"apiUrl,omitempty"`
-	Body       string `json:"body,omitempty"`
-	Number     int    `json:"number,omitempty"`
-	Owner      string `json:"owner,omitempty"`
-	Repository string `json:"repository,omitempty"`
-	Token      string `json:"token,omitempty"`
+	APIURL                  string `json:"apiUrl,omitempty"`
+	Body                    string `json:"body,omitempty"`
+	GithubAppID             int    `json:"githubAppId,omitempty"`
+	GithubAppInstallationID int    `json:"githubAppInstallationId,omitempty"`
+	GithubAppPrivateKey     string `json:"githubAppPrivateKey,omitempty"`
+	Number                  int    `json:"number,omitempty"`
+	Owner                   string `json:"owner,omitempty"`
+	Repository              string `json:"repository,omitempty"`
+	Token                   string `json:"token,omitempty"`
 }
 
 // GithubCommentIssueCommand Comment on GitHub issues and pull requests.
@@ -51,6 +54,7 @@ This comes in very handy when you want to make developers aware of certain thing
 				log.SetErrorCategory(log.ErrorConfiguration)
 				return err
 			}
+			log.RegisterSecret(stepConfig.GithubAppPrivateKey)
 			log.RegisterSecret(stepConfig.Token)
 
 			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
@@ -85,17 +89,19 @@ This comes in very handy when you want to make developers aware of certain thing
 func addGithubCommentIssueFlags(cmd *cobra.Command, stepConfig *githubCommentIssueOptions) {
 	cmd.Flags().StringVar(&stepConfig.APIURL, "apiUrl", `https://api.github.com`, "Set the GitHub API url.")
 	cmd.Flags().StringVar(&stepConfig.Body, "body", os.Getenv("PIPER_body"), "Defines the content of the comment, e.g. using markdown syntax.")
+	cmd.Flags().IntVar(&stepConfig.GithubAppID, "githubAppId", 0, "ID of the GitHub App used to authenticate to GitHub instead of a personal access token. The step authenticates as installation of the app, see [authenticating with GitHub Apps](https://docs.github.com/en/developers/apps/authenticating-with-github-apps).")
+	cmd.Flags().IntVar(&stepConfig.GithubAppInstallationID, "githubAppInstallationId", 0, "ID of the installation of the GitHub App. If not provided, the installation for `owner` and `repository` is looked up.")
+	cmd.Flags().StringVar(&stepConfig.GithubAppPrivateKey, "githubAppPrivateKey", os.Getenv("PIPER_githubAppPrivateKey"), "Path to the file containing the PEM encoded private key of the GitHub App.")
 	cmd.Flags().IntVar(&stepConfig.Number, "number", 0, "Defines the number of the GitHub issue/pull request.")
 	cmd.Flags().StringVar(&stepConfig.Owner, "owner", os.Getenv("PIPER_owner"), "Name of the GitHub organization.")
 	cmd.Flags().StringVar(&stepConfig.Repository, "repository", os.Getenv("PIPER_repository"), "Name of the GitHub repository.")
-	cmd.Flags().StringVar(&stepConfig.Token, "token", os.Getenv("PIPER_token"), "GitHub personal access token as per https://help.github.com/en/github/authenticating-to-github/creating-a-personal-access-token-for-the-command-line.")
+	cmd.Flags().StringVar(&stepConfig.Token, "token", os.Getenv("PIPER_token"), "GitHub personal access token as per https://help.github.com/en/github/authenticating-to-github/creating-a-personal-access-token-for-the-command-line. Not required in case a GitHub App is used for authentication (see `githubAppId`).")
 
 	cmd.MarkFlagRequired("apiUrl")
 	cmd.MarkFlagRequired("body")
 	cmd.MarkFlagRequired("number")
 	cmd.MarkFlagRequired("owner")
 	cmd.MarkFlagRequired("repository")
-	cmd.MarkFlagRequired("token")
 }
 
 // retrieve step metadata
@@ -125,6 +131,41 @@ func githubCommentIssueMetadata() config.StepData {
 						Mandatory:   true,
 						Aliases:     []config.Alias{},
 					},
+					{
+						Name:        "githubAppId",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "githubAppInstallationId",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name: "githubAppPrivateKey",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "githubAppPrivateKeyCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:  "",
+								Paths: []string{"$(vaultPath)/github-app-private-key", "$(vaultBasePath)/$(vaultPipelineName)/github-app-private-key", "$(vaultBasePath)/GROUP-SECRETS/github-app-private-key"},
+								Type:  "vaultSecretFile",
+							},
+						},
+						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+					},
 					{
 						Name:        "number",
 						ResourceRef: []config.ResourceReference{},
@@ -175,7 +216,7 @@ func githubCommentIssueMetadata() config.StepData {
 						},
 						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
 						Type:      "string",
-						Mandatory: true,
+						Mandatory: false,
 						Aliases:   []config.Alias{{Name: "githubToken"}, {Name: "access_token"}},
 					},
 				},
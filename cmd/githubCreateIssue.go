@@ -18,7 +18,14 @@ type githubCreateIssueService interface {
 }
 
 func githubCreateIssue(config githubCreateIssueOptions, telemetryData *telemetry.CustomData) {
-	ctx, client, err := piperGithub.NewClient(config.Token, config.APIURL, "")
+	appOptions := piperGithub.AppOptions{
+		AppID:          config.GithubAppID,
+		InstallationID: config.GithubAppInstallationID,
+		PrivateKeyFile: config.GithubAppPrivateKey,
+		Owner:          config.Owner,
+		Repository:     config.Repository,
+	}
+	ctx, client, err := piperGithub.NewClientWithAuth(config.Token, appOptions, config.APIURL, "")
 	if err != nil {
 		log.Entry().WithError(err).Fatal("Failed to get GitHub client")
 	}
@@ -14,13 +14,16 @@ import (
 )
 
 type githubCreateIssueOptions struct {
-	APIURL       string `json:"apiUrl,omitempty"`
-	Body         string `json:"body,omitempty"`
-	BodyFilePath string `json:"bodyFilePath,omitempty"`
-	Owner        string `json:"owner,omitempty"`
-	Repository   string `json:"repository,omitempty"`
-	Title        string `json:"title,omitempty"`
-	Token        string `json:"token,omitempty"`
+	APIURL                  string `json:"apiUrl,omitempty"`
+	Body                    string `json:"body,omitempty"`
+	BodyFilePath            string `json:"bodyFilePath,omitempty"`
+	GithubAppID             int    `json:"githubAppId,omitempty"`
+	GithubAppInstallationID int    `json:"githubAppInstallationId,omitempty"`
+	GithubAppPrivateKey     string `json:"githubAppPrivateKey,omitempty"`
+	Owner                   string `json:"owner,omitempty"`
+	Repository              string `json:"repository,omitempty"`
+	Title                   string `json:"title,omitempty"`
+	Token                   string `json:"token,omitempty"`
 }
 
 // GithubCreateIssueCommand Create a new GitHub issue.
@@ -51,6 +54,7 @@ You will be able to use this step for example for regular jobs to report into yo
 				log.SetErrorCategory(log.ErrorConfiguration)
 				return err
 			}
+			log.RegisterSecret(stepConfig.GithubAppPrivateKey)
 			log.RegisterSecret(stepConfig.Token)
 
 			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
@@ -86,16 +90,18 @@ func addGithubCreateIssueFlags(cmd *cobra.Command, stepConfig *githubCreateIssue
 	cmd.Flags().StringVar(&stepConfig.APIURL, "apiUrl", `https://api.github.com`, "Set the GitHub API url.")
 	cmd.Flags().StringVar(&stepConfig.Body, "body", os.Getenv("PIPER_body"), "Defines the content of the issue, e.g. using markdown syntax.")
 	cmd.Flags().StringVar(&stepConfig.BodyFilePath, "bodyFilePath", os.Getenv("PIPER_bodyFilePath"), "Defines the path to a file containing the markdown content for the issue. This can be used instead of [`body`](#body)")
+	cmd.Flags().IntVar(&stepConfig.GithubAppID, "githubAppId", 0, "ID of the GitHub App used to authenticate to GitHub instead of a personal access token. The step authenticates as installation of the app, see [authenticating with GitHub Apps](https://docs.github.com/en/developers/apps/authenticating-with-github-apps).")
+	cmd.Flags().IntVar(&stepConfig.GithubAppInstallationID, "githubAppInstallationId", 0, "ID of the installation of the GitHub App. If not provided, the installation for `owner` and `repository` is looked up.")
+	cmd.Flags().StringVar(&stepConfig.GithubAppPrivateKey, "githubAppPrivateKey", os.Getenv("PIPER_githubAppPrivateKey"), "Path to the file containing the PEM encoded private key of the GitHub App.")
 	cmd.Flags().StringVar(&stepConfig.Owner, "owner", os.Getenv("PIPER_owner"), "Name of the GitHub organization.")
 	cmd.Flags().StringVar(&stepConfig.Repository, "repository", os.Getenv("PIPER_repository"), "Name of the GitHub repository.")
 	cmd.Flags().StringVar(&stepConfig.Title, "title", os.Getenv("PIPER_title"), "Defines the title for the Issue.")
-	cmd.Flags().StringVar(&stepConfig.Token, "token", os.Getenv("PIPER_token"), "GitHub personal access token as per https://help.github.com/en/github/authenticating-to-github/creating-a-personal-access-token-for-the-command-line.")
+	cmd.Flags().StringVar(&stepConfig.Token, "token", os.Getenv("PIPER_token"), "GitHub personal access token as per https://help.github.com/en/github/authenticating-to-github/creating-a-personal-access-token-for-the-command-line. Not required in case a GitHub App is used for authentication (see `githubAppId`).")
 
 	cmd.MarkFlagRequired("apiUrl")
 	cmd.MarkFlagRequired("owner")
 	cmd.MarkFlagRequired("repository")
 	cmd.MarkFlagRequired("title")
-	cmd.MarkFlagRequired("token")
 }
 
 // retrieve step metadata
@@ -133,6 +139,41 @@ func githubCreateIssueMetadata() config.StepData {
 						Mandatory:   false,
 						Aliases:     []config.Alias{},
 					},
+					{
+						Name:        "githubAppId",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "githubAppInstallationId",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name: "githubAppPrivateKey",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "githubAppPrivateKeyCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:  "",
+								Paths: []string{"$(vaultPath)/github-app-private-key", "$(vaultBasePath)/$(vaultPipelineName)/github-app-private-key", "$(vaultBasePath)/GROUP-SECRETS/github-app-private-key"},
+								Type:  "vaultSecretFile",
+							},
+						},
+						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+					},
 					{
 						Name: "owner",
 						ResourceRef: []config.ResourceReference{
@@ -183,7 +224,7 @@ func githubCreateIssueMetadata() config.StepData {
 						},
 						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
 						Type:      "string",
-						Mandatory: true,
+						Mandatory: false,
 						Aliases:   []config.Alias{{Name: "githubToken"}, {Name: "access_token"}},
 					},
 				},
@@ -20,7 +20,14 @@ type githubIssueService interface {
 }
 
 func githubCreatePullRequest(config githubCreatePullRequestOptions, telemetryData *telemetry.CustomData) {
-	ctx, client, err := piperGithub.NewClient(config.Token, config.APIURL, "")
+	appOptions := piperGithub.AppOptions{
+		AppID:          config.GithubAppID,
+		InstallationID: config.GithubAppInstallationID,
+		PrivateKeyFile: config.GithubAppPrivateKey,
+		Owner:          config.Owner,
+		Repository:     config.Repository,
+	}
+	ctx, client, err := piperGithub.NewClientWithAuth(config.Token, appOptions, config.APIURL, "")
 	if err != nil {
 		log.Entry().WithError(err).Fatal("Failed to get GitHub client")
 	}
@@ -14,17 +14,20 @@ import (
 )
 
 type githubCreatePullRequestOptions struct {
-	Assignees  []string `json:"assignees,omitempty"`
-	Base       string   `json:"base,omitempty"`
-	Body       string   `json:"body,omitempty"`
-	APIURL     string   `json:"apiUrl,omitempty"`
-	Head       string   `json:"head,omitempty"`
-	Owner      string   `json:"owner,omitempty"`
-	Repository string   `json:"repository,omitempty"`
-	ServerURL  string   `json:"serverUrl,omitempty"`
-	Title      string   `json:"title,omitempty"`
-	Token      string   `json:"token,omitempty"`
-	Labels     []string `json:"labels,omitempty"`
+	Assignees               []string `json:"assignees,omitempty"`
+	Base                    string   `json:"base,omitempty"`
+	Body                    string   `json:"body,omitempty"`
+	APIURL                  string   `json:"apiUrl,omitempty"`
+	GithubAppID             int      `json:"githubAppId,omitempty"`
+	GithubAppInstallationID int      `json:"githubAppInstallationId,omitempty"`
+	GithubAppPrivateKey     string   `json:"githubAppPrivateKey,omitempty"`
+	Head                    string   `json:"head,omitempty"`
+	Owner                   string   `json:"owner,omitempty"`
+	Repository              string   `json:"repository,omitempty"`
+	ServerURL               string   `json:"serverUrl,omitempty"`
+	Title                   string   `json:"title,omitempty"`
+	Token                   string   `json:"token,omitempty"`
+	Labels                  []string `json:"labels,omitempty"`
 }
 
 // GithubCreatePullRequestCommand Create a pull request on GitHub
@@ -55,6 +58,7 @@ It can for example be used for GitOps scenarios or for scenarios where you want
 				log.SetErrorCategory(log.ErrorConfiguration)
 				return err
 			}
+			log.RegisterSecret(stepConfig.GithubAppPrivateKey)
 			log.RegisterSecret(stepConfig.Token)
 
 			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
@@ -91,12 +95,15 @@ func addGithubCreatePullRequestFlags(cmd *cobra.Command, stepConfig *githubCreat
 	cmd.Flags().StringVar(&stepConfig.Base, "base", os.Getenv("PIPER_base"), "The name of the branch you want the changes pulled into.")
 	cmd.Flags().StringVar(&stepConfig.Body, "body", os.Getenv("PIPER_body"), "The description text of the pull request in markdown format.")
 	cmd.Flags().StringVar(&stepConfig.APIURL, "apiUrl", `https://api.github.com`, "Set the GitHub API url.")
+	cmd.Flags().IntVar(&stepConfig.GithubAppID, "githubAppId", 0, "ID of the GitHub App used to authenticate to GitHub instead of a personal access token. The step authenticates as installation of the app, see [authenticating with GitHub Apps](https://docs.github.com/en/developers/apps/authenticating-with-github-apps).")
+	cmd.Flags().IntVar(&stepConfig.GithubAppInstallationID, "githubAppInstallationId", 0, "ID of the installation of the GitHub App. If not provided, the installation for `owner` and `repository` is looked up.")
+	cmd.Flags().StringVar(&stepConfig.GithubAppPrivateKey, "githubAppPrivateKey", os.Getenv("PIPER_githubAppPrivateKey"), "Path to the file containing the PEM encoded private key of the GitHub App.")
 	cmd.Flags().StringVar(&stepConfig.Head, "head", os.Getenv("PIPER_head"), "The name of the branch where your changes are implemented.")
 	cmd.Flags().StringVar(&stepConfig.Owner, "owner", os.Getenv("PIPER_owner"), "Name of the GitHub organization.")
 	cmd.Flags().StringVar(&stepConfig.Repository, "repository", os.Getenv("PIPER_repository"), "Name of the GitHub repository.")
 	cmd.Flags().StringVar(&stepConfig.ServerURL, "serverUrl", `https://github.com`, "GitHub server url for end-user access.")
 	cmd.Flags().StringVar(&stepConfig.Title, "title", os.Getenv("PIPER_title"), "Title of the pull request.")
-	cmd.Flags().StringVar(&stepConfig.Token, "token", os.Getenv("PIPER_token"), "GitHub personal access token as per https://help.github.com/en/github/authenticating-to-github/creating-a-personal-access-token-for-the-command-line")
+	cmd.Flags().StringVar(&stepConfig.Token, "token", os.Getenv("PIPER_token"), "GitHub personal access token as per https://help.github.com/en/github/authenticating-to-github/creating-a-personal-access-token-for-the-command-line. Not required in case a GitHub App is used for authentication (see `githubAppId`).")
 	cmd.Flags().StringSliceVar(&stepConfig.Labels, "labels", []string{}, "Labels to be added to the pull request.")
 
 	cmd.MarkFlagRequired("base")
@@ -107,7 +114,6 @@ func addGithubCreatePullRequestFlags(cmd *cobra.Command, stepConfig *githubCreat
 	cmd.MarkFlagRequired("repository")
 	cmd.MarkFlagRequired("serverUrl")
 	cmd.MarkFlagRequired("title")
-	cmd.MarkFlagRequired("token")
 }
 
 // retrieve step metadata
@@ -153,6 +159,41 @@ func githubCreatePullRequestMetadata() config.StepData {
 						Mandatory:   true,
 						Aliases:     []config.Alias{{Name: "githubApiUrl"}},
 					},
+					{
+						Name:        "githubAppId",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "githubAppInstallationId",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name: "githubAppPrivateKey",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "githubAppPrivateKeyCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:  "",
+								Paths: []string{"$(vaultPath)/github-app-private-key", "$(vaultBasePath)/$(vaultPipelineName)/github-app-private-key", "$(vaultBasePath)/GROUP-SECRETS/github-app-private-key"},
+								Type:  "vaultSecretFile",
+							},
+						},
+						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+					},
 					{
 						Name:        "head",
 						ResourceRef: []config.ResourceReference{},
@@ -219,7 +260,7 @@ func githubCreatePullRequestMetadata() config.StepData {
 						},
 						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
 						Type:      "string",
-						Mandatory: true,
+						Mandatory: false,
 						Aliases:   []config.Alias{{Name: "githubToken"}, {Name: "access_token"}},
 					},
 					{
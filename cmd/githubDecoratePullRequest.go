@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/git"
+	piperGithub "github.com/SAP/jenkins-library/pkg/github"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/reporting"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/pkg/errors"
+)
+
+type githubDecoratePullRequestUtils interface {
+	FileRead(path string) ([]byte, error)
+	Glob(pattern string) (matches []string, err error)
+	ChangedLines(base, head string) (map[string][]int, error)
+}
+
+type githubDecoratePullRequestUtilsBundle struct {
+	*piperutils.Files
+}
+
+func (g *githubDecoratePullRequestUtilsBundle) ChangedLines(base, head string) (map[string][]int, error) {
+	repo, err := git.PlainOpen(".")
+	if err != nil {
+		return nil, err
+	}
+	return git.ChangedLines(repo, base, head)
+}
+
+func newGithubDecoratePullRequestUtils() githubDecoratePullRequestUtils {
+	return &githubDecoratePullRequestUtilsBundle{
+		Files: &piperutils.Files{},
+	}
+}
+
+func githubDecoratePullRequest(config githubDecoratePullRequestOptions, telemetryData *telemetry.CustomData) {
+	if config.PullRequestNumber == 0 {
+		log.Entry().Info("no pull request number available, skipping pull request decoration")
+		return
+	}
+
+	appOptions := piperGithub.AppOptions{
+		AppID:          config.GithubAppID,
+		InstallationID: config.GithubAppInstallationID,
+		PrivateKeyFile: config.GithubAppPrivateKey,
+		Owner:          config.Owner,
+		Repository:     config.Repository,
+	}
+	ctx, client, err := piperGithub.NewClientWithAuth(config.Token, appOptions, config.APIURL, "")
+	if err != nil {
+		log.Entry().WithError(err).Fatal("Failed to get GitHub client")
+	}
+
+	decorate := func(decoration piperGithub.PullRequestDecoration) error {
+		return piperGithub.DecoratePullRequest(ctx, client, decoration)
+	}
+	if err := runGithubDecoratePullRequest(&config, newGithubDecoratePullRequestUtils(), decorate); err != nil {
+		log.Entry().WithError(err).Fatal("Failed to decorate GitHub pull request")
+	}
+}
+
+func runGithubDecoratePullRequest(config *githubDecoratePullRequestOptions, utils githubDecoratePullRequestUtils, decorate func(piperGithub.PullRequestDecoration) error) error {
+	scanReports, err := readScanReports(utils)
+	if err != nil {
+		return err
+	}
+	if len(scanReports) == 0 {
+		log.Entry().Info("no scan reports found, skipping pull request decoration")
+		return nil
+	}
+
+	changedLines, err := utils.ChangedLines(config.BaseRevision, config.CommitID)
+	inlineComments := err == nil
+	if err != nil {
+		// e.g. the revision of the target branch is not available in a shallow clone
+		log.Entry().WithError(err).Warn("failed to determine the lines changed by the pull request, only the summary comment is added")
+	}
+	changed := map[string]bool{}
+	for file, lines := range changedLines {
+		for _, line := range lines {
+			changed[fmt.Sprintf("%v:%v", file, line)] = true
+		}
+	}
+
+	decoration := piperGithub.PullRequestDecoration{
+		Owner:      config.Owner,
+		Repository: config.Repository,
+		Number:     config.PullRequestNumber,
+		CommitID:   config.CommitID,
+	}
+	summary := []string{"## Scan results"}
+	for _, scanReport := range scanReports {
+		mdReport, _ := scanReport.ToMarkdown()
+		summary = append(summary, string(mdReport))
+
+		inline := 0
+		for _, finding := range scanReport.Findings {
+			file := strings.TrimPrefix(filepath.ToSlash(finding.File), "./")
+			if !changed[fmt.Sprintf("%v:%v", file, finding.Line)] {
+				continue
+			}
+			inline++
+			decoration.Comments = append(decoration.Comments, piperGithub.ReviewComment{Path: file, Line: finding.Line, Body: findingComment(scanReport.StepName, finding)})
+		}
+		if len(scanReport.Findings) > 0 && inlineComments {
+			summary = append(summary, fmt.Sprintf("%v of %v findings are located in lines changed by this pull request and have been commented inline.", inline, len(scanReport.Findings)))
+		}
+	}
+	decoration.Summary = strings.Join(summary, "\n\n")
+
+	if err := decorate(decoration); err != nil {
+		return errors.Wrapf(err, "failed to decorate pull request #%v", config.PullRequestNumber)
+	}
+	return nil
+}
+
+func readScanReports(utils githubDecoratePullRequestUtils) ([]reporting.ScanReport, error) {
+	reports, _ := utils.Glob(reporting.StepReportDirectory + "/*.json")
+	scanReports := []reporting.ScanReport{}
+	for _, report := range reports {
+		reportContent, err := utils.FileRead(report)
+		if err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return nil, errors.Wrapf(err, "failed to read report %v", report)
+		}
+		scanReport := reporting.ScanReport{}
+		if err = json.Unmarshal(reportContent, &scanReport); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse report %v", report)
+		}
+		scanReports = append(scanReports, scanReport)
+	}
+	return scanReports, nil
+}
+
+func findingComment(stepName string, finding reporting.Finding) string {
+	comment := fmt.Sprintf("**%v**: %v", finding.Severity, finding.Rule)
+	if len(finding.Message) > 0 {
+		comment += "\n\n" + finding.Message
+	}
+	if len(finding.Link) > 0 {
+		comment += fmt.Sprintf("\n\n[Details](%v)", finding.Link)
+	}
+	return comment + fmt.Sprintf("\n\n_reported by %v_", stepName)
+}
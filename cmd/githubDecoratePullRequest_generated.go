@@ -0,0 +1,253 @@
+// Code generated by piper's step-generator. DO NOT EDIT.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/spf13/cobra"
+)
+
+type githubDecoratePullRequestOptions struct {
+	APIURL                  string `json:"apiUrl,omitempty"`
+	BaseRevision            string `json:"baseRevision,omitempty"`
+	CommitID                string `json:"commitId,omitempty"`
+	GithubAppID             int    `json:"githubAppId,omitempty"`
+	GithubAppInstallationID int    `json:"githubAppInstallationId,omitempty"`
+	GithubAppPrivateKey     string `json:"githubAppPrivateKey,omitempty"`
+	Owner                   string `json:"owner,omitempty"`
+	PullRequestNumber       int    `json:"pullRequestNumber,omitempty"`
+	Repository              string `json:"repository,omitempty"`
+	Token                   string `json:"token,omitempty"`
+}
+
+// GithubDecoratePullRequestCommand Decorates a GitHub pull request with the findings of the scan steps.
+func GithubDecoratePullRequestCommand() *cobra.Command {
+	const STEP_NAME = "githubDecoratePullRequest"
+
+	metadata := githubDecoratePullRequestMetadata()
+	var stepConfig githubDecoratePullRequestOptions
+	var startTime time.Time
+
+	var createGithubDecoratePullRequestCmd = &cobra.Command{
+		Use:   STEP_NAME,
+		Short: "Decorates a GitHub pull request with the findings of the scan steps.",
+		Long: `This step feeds the results of the scan steps back into a pull request.
+
+It reads the scan reports (` + "`" + `reporting.ScanReport` + "`" + `) written by the scan steps, e.g. ` + "`" + `checkmarxExecuteScan` + "`" + `, ` + "`" + `sonarExecuteScan` + "`" + `, ` + "`" + `fortifyExecuteScan` + "`" + `,
+` + "`" + `whitesourceExecuteScan` + "`" + ` or ` + "`" + `protecodeExecuteScan` + "`" + `, and
+
+* adds a summary comment to the pull request. The comment is updated by subsequent runs instead of adding a new one.
+* adds review comments on the lines of findings which are located in lines changed by the pull request.
+  The changed lines are determined from the git repository in the workspace by comparing ` + "`" + `commitId` + "`" + ` with ` + "`" + `baseRevision` + "`" + `.
+  Comments which already exist on the same line are not added again.
+  In case the changed lines cannot be determined (e.g. ` + "`" + `baseRevision` + "`" + ` is not available in the workspace), only the summary comment is added.
+
+Review comments require the location of the findings in the source code. They are provided by ` + "`" + `checkmarxExecuteScan` + "`" + ` and, for pull requests, by ` + "`" + `sonarExecuteScan` + "`" + `.
+The report of ` + "`" + `fortifyExecuteScan` + "`" + ` only contains the audit status and is therefore only part of the summary comment.
+
+In case the step does not run for a pull request (no ` + "`" + `pullRequestNumber` + "`" + ` available), it does nothing.`,
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			startTime = time.Now()
+			log.SetStepName(STEP_NAME)
+			log.SetVerbose(GeneralConfig.Verbose)
+
+			path, _ := os.Getwd()
+			fatalHook := &log.FatalHook{CorrelationID: GeneralConfig.CorrelationID, Path: path}
+			log.RegisterHook(fatalHook)
+
+			err := PrepareConfig(cmd, &metadata, STEP_NAME, &stepConfig, config.OpenPiperFile)
+			if err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+			log.RegisterSecret(stepConfig.GithubAppPrivateKey)
+			log.RegisterSecret(stepConfig.Token)
+
+			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
+				sentryHook := log.NewSentryHook(GeneralConfig.HookConfig.SentryConfig.Dsn, GeneralConfig.CorrelationID)
+				log.RegisterHook(&sentryHook)
+			}
+
+			return nil
+		},
+		Run: func(_ *cobra.Command, _ []string) {
+			telemetryData := telemetry.CustomData{}
+			telemetryData.ErrorCode = "1"
+			handler := func() {
+				config.RemoveVaultSecretFiles()
+				telemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
+				telemetryData.ErrorCategory = log.GetErrorCategory().String()
+				telemetry.Send(&telemetryData)
+			}
+			log.DeferExitHandler(handler)
+			defer handler()
+			telemetry.Initialize(GeneralConfig.NoTelemetry, STEP_NAME)
+			githubDecoratePullRequest(stepConfig, &telemetryData)
+			telemetryData.ErrorCode = "0"
+			log.Entry().Info("SUCCESS")
+		},
+	}
+
+	addGithubDecoratePullRequestFlags(createGithubDecoratePullRequestCmd, &stepConfig)
+	return createGithubDecoratePullRequestCmd
+}
+
+func addGithubDecoratePullRequestFlags(cmd *cobra.Command, stepConfig *githubDecoratePullRequestOptions) {
+	cmd.Flags().StringVar(&stepConfig.APIURL, "apiUrl", `https://api.github.com`, "Set the GitHub API url.")
+	cmd.Flags().StringVar(&stepConfig.BaseRevision, "baseRevision", `origin/master`, "Git revision of the target branch of the pull request, e.g. `origin/master`. Only lines changed since the head commit diverged from this revision receive review comments.")
+	cmd.Flags().StringVar(&stepConfig.CommitID, "commitId", os.Getenv("PIPER_commitId"), "The head commit of the pull request which the review comments refer to.")
+	cmd.Flags().IntVar(&stepConfig.GithubAppID, "githubAppId", 0, "ID of the GitHub App used to authenticate to GitHub instead of a personal access token. The step authenticates as installation of the app, see [authenticating with GitHub Apps](https://docs.github.com/en/developers/apps/authenticating-with-github-apps).")
+	cmd.Flags().IntVar(&stepConfig.GithubAppInstallationID, "githubAppInstallationId", 0, "ID of the installation of the GitHub App. If not provided, the installation for `owner` and `repository` is looked up.")
+	cmd.Flags().StringVar(&stepConfig.GithubAppPrivateKey, "githubAppPrivateKey", os.Getenv("PIPER_githubAppPrivateKey"), "Path to the file containing the PEM encoded private key of the GitHub App.")
+	cmd.Flags().StringVar(&stepConfig.Owner, "owner", os.Getenv("PIPER_owner"), "Name of the GitHub organization.")
+	cmd.Flags().IntVar(&stepConfig.PullRequestNumber, "pullRequestNumber", 0, "Number of the pull request which is decorated. In case it is not provided, the step does nothing.")
+	cmd.Flags().StringVar(&stepConfig.Repository, "repository", os.Getenv("PIPER_repository"), "Name of the GitHub repository.")
+	cmd.Flags().StringVar(&stepConfig.Token, "token", os.Getenv("PIPER_token"), "GitHub personal access token as per https://help.github.com/en/github/authenticating-to-github/creating-a-personal-access-token-for-the-command-line. Not required in case a GitHub App is used for authentication (see `githubAppId`).")
+
+	cmd.MarkFlagRequired("apiUrl")
+	cmd.MarkFlagRequired("baseRevision")
+	cmd.MarkFlagRequired("commitId")
+	cmd.MarkFlagRequired("owner")
+	cmd.MarkFlagRequired("repository")
+}
+
+// retrieve step metadata
+func githubDecoratePullRequestMetadata() config.StepData {
+	var theMetaData = config.StepData{
+		Metadata: config.StepMetadata{
+			Name:        "githubDecoratePullRequest",
+			Aliases:     []config.Alias{},
+			Description: "Decorates a GitHub pull request with the findings of the scan steps.",
+		},
+		Spec: config.StepSpec{
+			Inputs: config.StepInputs{
+				Parameters: []config.StepParameters{
+					{
+						Name:        "apiUrl",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   true,
+						Aliases:     []config.Alias{{Name: "githubApiUrl"}},
+					},
+					{
+						Name:        "baseRevision",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   true,
+						Aliases:     []config.Alias{{Name: "changeTarget"}},
+					},
+					{
+						Name: "commitId",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "git/commitId",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: true,
+						Aliases:   []config.Alias{},
+					},
+					{
+						Name:        "githubAppId",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "githubAppInstallationId",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name: "githubAppPrivateKey",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "githubAppPrivateKeyCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:  "",
+								Paths: []string{"$(vaultPath)/github-app-private-key", "$(vaultBasePath)/$(vaultPipelineName)/github-app-private-key", "$(vaultBasePath)/GROUP-SECRETS/github-app-private-key"},
+								Type:  "vaultSecretFile",
+							},
+						},
+						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+					},
+					{
+						Name: "owner",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "github/owner",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: true,
+						Aliases:   []config.Alias{{Name: "githubOrg"}},
+					},
+					{
+						Name:        "pullRequestNumber",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{{Name: "changeId"}},
+					},
+					{
+						Name: "repository",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "github/repository",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: true,
+						Aliases:   []config.Alias{{Name: "githubRepo"}},
+					},
+					{
+						Name: "token",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "githubTokenCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:  "",
+								Paths: []string{"$(vaultPath)/github", "$(vaultBasePath)/$(vaultPipelineName)/github", "$(vaultBasePath)/GROUP-SECRETS/github"},
+								Type:  "vaultSecret",
+							},
+						},
+						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{{Name: "githubToken"}, {Name: "access_token"}},
+					},
+				},
+			},
+		},
+	}
+	return theMetaData
+}
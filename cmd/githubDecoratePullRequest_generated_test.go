@@ -0,0 +1,17 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGithubDecoratePullRequestCommand(t *testing.T) {
+	t.Parallel()
+
+	testCmd := GithubDecoratePullRequestCommand()
+
+	// only high level testing performed - details are tested in step generation procedure
+	assert.Equal(t, "githubDecoratePullRequest", testCmd.Use, "command name incorrect")
+
+}
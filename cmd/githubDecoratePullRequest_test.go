@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"fmt"
+	"testing"
+
+	piperGithub "github.com/SAP/jenkins-library/pkg/github"
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+type githubDecoratePullRequestMockUtils struct {
+	*mock.FilesMock
+	changedLines map[string][]int
+	base, head   string
+}
+
+func (g *githubDecoratePullRequestMockUtils) ChangedLines(base, head string) (map[string][]int, error) {
+	g.base, g.head = base, head
+	if g.changedLines == nil {
+		return nil, fmt.Errorf("unknown revision")
+	}
+	return g.changedLines, nil
+}
+
+func newGithubDecoratePullRequestTestsUtils() *githubDecoratePullRequestMockUtils {
+	return &githubDecoratePullRequestMockUtils{FilesMock: &mock.FilesMock{}}
+}
+
+const decorateScanReport = `{"stepName": "checkmarxExecuteScan", "title": "Checkmarx SAST Report", "successfulScan": false, "findings": [
+	{"rule": "SQL_Injection", "severity": "High", "file": "src/main.go", "line": 3, "link": "https://cx.example.org/1"},
+	{"rule": "Weak_Hash", "severity": "Medium", "message": "use sha256", "file": "./src/main.go", "line": 5},
+	{"rule": "Weak_Hash", "severity": "Medium", "file": "src/other.go", "line": 5}]}`
+
+func TestRunGithubDecoratePullRequest(t *testing.T) {
+	t.Parallel()
+
+	config := githubDecoratePullRequestOptions{
+		Owner:             "SAP",
+		Repository:        "jenkins-library",
+		PullRequestNumber: 7,
+		CommitID:          "abc123",
+		BaseRevision:      "origin/master",
+	}
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		utils := newGithubDecoratePullRequestTestsUtils()
+		utils.AddFile(".pipeline/stepReports/checkmarxExecuteScan_20201019120000.json", []byte(decorateScanReport))
+		utils.changedLines = map[string][]int{"src/main.go": {3, 4, 5}}
+		var decoration piperGithub.PullRequestDecoration
+
+		err := runGithubDecoratePullRequest(&config, utils, func(d piperGithub.PullRequestDecoration) error {
+			decoration = d
+			return nil
+		})
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, "origin/master", utils.base)
+			assert.Equal(t, "abc123", utils.head)
+			assert.Equal(t, 7, decoration.Number)
+			assert.Equal(t, "abc123", decoration.CommitID)
+			assert.Contains(t, decoration.Summary, "Checkmarx SAST Report")
+			assert.Contains(t, decoration.Summary, "2 of 3 findings are located in lines changed by this pull request")
+			assert.Equal(t, []piperGithub.ReviewComment{
+				{Path: "src/main.go", Line: 3, Body: "**High**: SQL_Injection\n\n[Details](https://cx.example.org/1)\n\n_reported by checkmarxExecuteScan_"},
+				{Path: "src/main.go", Line: 5, Body: "**Medium**: Weak_Hash\n\nuse sha256\n\n_reported by checkmarxExecuteScan_"},
+			}, decoration.Comments)
+		}
+	})
+
+	t.Run("no reports", func(t *testing.T) {
+		t.Parallel()
+		utils := newGithubDecoratePullRequestTestsUtils()
+
+		err := runGithubDecoratePullRequest(&config, utils, func(d piperGithub.PullRequestDecoration) error {
+			t.Fail()
+			return nil
+		})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("error - invalid report", func(t *testing.T) {
+		t.Parallel()
+		utils := newGithubDecoratePullRequestTestsUtils()
+		utils.AddFile(".pipeline/stepReports/step.json", []byte(`{`))
+
+		err := runGithubDecoratePullRequest(&config, utils, nil)
+
+		assert.Contains(t, err.Error(), "failed to parse report .pipeline/stepReports/step.json")
+	})
+
+	t.Run("changed lines not available", func(t *testing.T) {
+		t.Parallel()
+		utils := newGithubDecoratePullRequestTestsUtils()
+		utils.AddFile(".pipeline/stepReports/step.json", []byte(decorateScanReport))
+		var decoration piperGithub.PullRequestDecoration
+
+		err := runGithubDecoratePullRequest(&config, utils, func(d piperGithub.PullRequestDecoration) error {
+			decoration = d
+			return nil
+		})
+
+		if assert.NoError(t, err) {
+			assert.Contains(t, decoration.Summary, "Checkmarx SAST Report")
+			assert.NotContains(t, decoration.Summary, "commented inline")
+			assert.Empty(t, decoration.Comments)
+		}
+	})
+
+	t.Run("error - decoration", func(t *testing.T) {
+		t.Parallel()
+		utils := newGithubDecoratePullRequestTestsUtils()
+		utils.AddFile(".pipeline/stepReports/step.json", []byte(decorateScanReport))
+		utils.changedLines = map[string][]int{}
+
+		err := runGithubDecoratePullRequest(&config, utils, func(d piperGithub.PullRequestDecoration) error {
+			return fmt.Errorf("forbidden")
+		})
+
+		assert.EqualError(t, err, "failed to decorate pull request #7: forbidden")
+	})
+}
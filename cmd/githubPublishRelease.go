@@ -2,14 +2,22 @@ package cmd
 
 import (
 	"context"
+	"crypto/sha256"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"mime"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
 
+	"github.com/SAP/jenkins-library/pkg/changelog"
 	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/bmatcuk/doublestar"
 	"github.com/google/go-github/v32/github"
 	"github.com/pkg/errors"
 
@@ -19,7 +27,9 @@ import (
 type githubRepoClient interface {
 	CreateRelease(ctx context.Context, owner string, repo string, release *github.RepositoryRelease) (*github.RepositoryRelease, *github.Response, error)
 	DeleteReleaseAsset(ctx context.Context, owner string, repo string, id int64) (*github.Response, error)
+	EditRelease(ctx context.Context, owner string, repo string, id int64, release *github.RepositoryRelease) (*github.RepositoryRelease, *github.Response, error)
 	GetLatestRelease(ctx context.Context, owner string, repo string) (*github.RepositoryRelease, *github.Response, error)
+	ListReleases(ctx context.Context, owner string, repo string, opts *github.ListOptions) ([]*github.RepositoryRelease, *github.Response, error)
 	ListReleaseAssets(ctx context.Context, owner string, repo string, id int64, opt *github.ListOptions) ([]*github.ReleaseAsset, *github.Response, error)
 	UploadReleaseAsset(ctx context.Context, owner string, repo string, id int64, opt *github.UploadOptions, file *os.File) (*github.ReleaseAsset, *github.Response, error)
 }
@@ -28,19 +38,29 @@ type githubIssueClient interface {
 	ListByRepo(ctx context.Context, owner string, repo string, opt *github.IssueListByRepoOptions) ([]*github.Issue, *github.Response, error)
 }
 
+// githubReleaseChangelogGit provides the commits for the changelog of the release
+var githubReleaseChangelogGit changelogGit = &changelogGitRepository{path: "."}
+
 func githubPublishRelease(config githubPublishReleaseOptions, telemetryData *telemetry.CustomData) {
-	ctx, client, err := piperGithub.NewClient(config.Token, config.APIURL, config.UploadURL)
+	appOptions := piperGithub.AppOptions{
+		AppID:          config.GithubAppID,
+		InstallationID: config.GithubAppInstallationID,
+		PrivateKeyFile: config.GithubAppPrivateKey,
+		Owner:          config.Owner,
+		Repository:     config.Repository,
+	}
+	ctx, client, err := piperGithub.NewClientWithAuth(config.Token, appOptions, config.APIURL, config.UploadURL)
 	if err != nil {
 		log.Entry().WithError(err).Fatal("Failed to get GitHub client.")
 	}
 
-	err = runGithubPublishRelease(ctx, &config, client.Repositories, client.Issues)
+	err = runGithubPublishRelease(ctx, &config, client.Repositories, client.Issues, client.PullRequests)
 	if err != nil {
 		log.Entry().WithError(err).Fatal("Failed to publish GitHub release.")
 	}
 }
 
-func runGithubPublishRelease(ctx context.Context, config *githubPublishReleaseOptions, ghRepoClient githubRepoClient, ghIssueClient githubIssueClient) error {
+func runGithubPublishRelease(ctx context.Context, config *githubPublishReleaseOptions, ghRepoClient githubRepoClient, ghIssueClient githubIssueClient, ghPullRequestClient changelog.PullRequestClient) error {
 
 	var publishedAt github.Timestamp
 
@@ -58,8 +78,19 @@ func runGithubPublishRelease(ctx context.Context, config *githubPublishReleaseOp
 	log.Entry().Debugf("Previous GitHub release published: '%v'", publishedAt)
 
 	//updating assets only supported on latest release
-	if len(config.AssetPath) > 0 && config.Version == "latest" {
-		return uploadReleaseAsset(ctx, lastRelease.GetID(), config, ghRepoClient)
+	if hasReleaseAssets(config) && config.Version == "latest" {
+		return uploadReleaseAssets(ctx, lastRelease.GetID(), config, ghRepoClient)
+	}
+
+	preRelease, err := isPreRelease(config)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return err
+	}
+
+	existingRelease, err := getReleaseByTag(ctx, config, ghRepoClient)
+	if err != nil {
+		return err
 	}
 
 	releaseBody := ""
@@ -68,6 +99,10 @@ func runGithubPublishRelease(ctx context.Context, config *githubPublishReleaseOp
 		releaseBody += config.ReleaseBodyHeader + "\n"
 	}
 
+	if config.AddChangelog {
+		releaseBody += getChangelogText(ctx, lastRelease, config, ghIssueClient, ghPullRequestClient)
+	}
+
 	if config.AddClosedIssues {
 		releaseBody += getClosedIssuesText(ctx, publishedAt, config, ghIssueClient)
 	}
@@ -81,22 +116,74 @@ func runGithubPublishRelease(ctx context.Context, config *githubPublishReleaseOp
 		TargetCommitish: &config.Commitish,
 		Name:            &config.Version,
 		Body:            &releaseBody,
-		Prerelease:      &config.PreRelease,
+		Prerelease:      &preRelease,
+		Draft:           &config.Draft,
 	}
 
-	createdRelease, _, err := ghRepoClient.CreateRelease(ctx, config.Owner, config.Repository, &release)
-	if err != nil {
-		return errors.Wrapf(err, "Creation of release '%v' failed", *release.TagName)
+	var publishedRelease *github.RepositoryRelease
+	if existingRelease != nil {
+		if !existingRelease.GetDraft() {
+			//keep the body of an already published release, the delta to the last release would refer to the release itself
+			release.Body = nil
+		}
+		publishedRelease, _, err = ghRepoClient.EditRelease(ctx, config.Owner, config.Repository, existingRelease.GetID(), &release)
+		if err != nil {
+			return errors.Wrapf(err, "Update of release '%v' failed", *release.TagName)
+		}
+		log.Entry().Infof("Release %v updated on %v/%v", publishedRelease.GetTagName(), config.Owner, config.Repository)
+	} else {
+		publishedRelease, _, err = ghRepoClient.CreateRelease(ctx, config.Owner, config.Repository, &release)
+		if err != nil {
+			return errors.Wrapf(err, "Creation of release '%v' failed", *release.TagName)
+		}
+		log.Entry().Infof("Release %v created on %v/%v", publishedRelease.GetTagName(), config.Owner, config.Repository)
 	}
-	log.Entry().Infof("Release %v created on %v/%v", *createdRelease.TagName, config.Owner, config.Repository)
 
-	if len(config.AssetPath) > 0 {
-		return uploadReleaseAsset(ctx, createdRelease.GetID(), config, ghRepoClient)
+	if hasReleaseAssets(config) {
+		return uploadReleaseAssets(ctx, publishedRelease.GetID(), config, ghRepoClient)
 	}
 
 	return nil
 }
 
+// isPreRelease returns true in case the release is configured as Pre-release or the version matches the preReleasePattern
+func isPreRelease(config *githubPublishReleaseOptions) (bool, error) {
+	if config.PreRelease || len(config.PreReleasePattern) == 0 {
+		return config.PreRelease, nil
+	}
+	pattern, err := regexp.Compile(config.PreReleasePattern)
+	if err != nil {
+		return false, errors.Wrapf(err, "invalid preReleasePattern '%v'", config.PreReleasePattern)
+	}
+	if pattern.MatchString(config.Version) {
+		log.Entry().Infof("Version '%v' is published as Pre-release", config.Version)
+		return true, nil
+	}
+	return false, nil
+}
+
+// getReleaseByTag looks up an existing release for the version.
+// Drafts are not tagged yet and thus cannot be retrieved via the tag directly, which is why all releases are searched.
+func getReleaseByTag(ctx context.Context, config *githubPublishReleaseOptions, ghRepoClient githubRepoClient) (*github.RepositoryRelease, error) {
+	options := github.ListOptions{PerPage: 100}
+	for {
+		releases, resp, err := ghRepoClient.ListReleases(ctx, config.Owner, config.Repository, &options)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to get list of releases (%v/%v)", config.Owner, config.Repository)
+		}
+		for _, release := range releases {
+			if release.GetTagName() == config.Version {
+				log.Entry().Infof("Release %v already exists (draft: %v)", config.Version, release.GetDraft())
+				return release, nil
+			}
+		}
+		if resp == nil || resp.NextPage == 0 {
+			return nil, nil
+		}
+		options.Page = resp.NextPage
+	}
+}
+
 func getClosedIssuesText(ctx context.Context, publishedAt github.Timestamp, config *githubPublishReleaseOptions, ghIssueClient githubIssueClient) string {
 	closedIssuesText := ""
 
@@ -136,6 +223,34 @@ func getClosedIssuesText(ctx context.Context, publishedAt github.Timestamp, conf
 	return closedIssuesText
 }
 
+func getChangelogText(ctx context.Context, lastRelease *github.RepositoryRelease, config *githubPublishReleaseOptions, ghIssueClient githubIssueClient, ghPullRequestClient changelog.PullRequestClient) string {
+	commits, err := githubReleaseChangelogGit.Commits(lastRelease.GetTagName(), config.Commitish)
+	if err != nil {
+		log.Entry().WithError(err).Warning("Failed to retrieve commits, changelog only contains pull-requests and issues.")
+		commits = []changelog.Commit{}
+	}
+
+	gitHub := changelogGitHub{
+		owner:         config.Owner,
+		repository:    config.Repository,
+		serverURL:     config.ServerURL,
+		excludeLabels: config.ExcludeLabels,
+		pullRequests:  ghPullRequestClient,
+		issues:        ghIssueClient,
+	}
+	entries, err := buildChangelog(ctx, config.Version, lastRelease.GetPublishedAt().Time, commits, gitHub, true)
+	if err != nil {
+		log.Entry().WithError(err).Error("Failed to create changelog.")
+		return ""
+	}
+	changelogText, err := entries.Render(changelog.SectionsTemplate)
+	if err != nil {
+		log.Entry().WithError(err).Error("Failed to render changelog.")
+		return ""
+	}
+	return changelogText
+}
+
 func getReleaseDeltaText(config *githubPublishReleaseOptions, lastRelease *github.RepositoryRelease) string {
 	releaseDeltaText := ""
 
@@ -154,50 +269,180 @@ func getReleaseDeltaText(config *githubPublishReleaseOptions, lastRelease *githu
 	return releaseDeltaText
 }
 
-func uploadReleaseAsset(ctx context.Context, releaseID int64, config *githubPublishReleaseOptions, ghRepoClient githubRepoClient) error {
+func hasReleaseAssets(config *githubPublishReleaseOptions) bool {
+	return len(config.AssetPath) > 0 || len(config.AssetPaths) > 0
+}
 
-	assets, _, err := ghRepoClient.ListReleaseAssets(ctx, config.Owner, config.Repository, releaseID, &github.ListOptions{})
+func uploadReleaseAssets(ctx context.Context, releaseID int64, config *githubPublishReleaseOptions, ghRepoClient githubRepoClient) error {
+	assetPaths, err := releaseAssetPaths(config)
 	if err != nil {
-		return errors.Wrap(err, "Failed to get list of release assets.")
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return err
 	}
-	var assetID int64
-	for _, a := range assets {
-		if a.GetName() == filepath.Base(config.AssetPath) {
-			assetID = a.GetID()
-			break
+
+	existingAssets, err := listReleaseAssets(ctx, releaseID, config, ghRepoClient)
+	if err != nil {
+		return err
+	}
+
+	uploadPaths := append([]string{}, assetPaths...)
+
+	if config.CreateChecksums {
+		checksumDir, err := ioutil.TempDir("", "release-checksums")
+		if err != nil {
+			return errors.Wrap(err, "Failed to create directory for checksum file")
+		}
+		defer os.RemoveAll(checksumDir)
+
+		checksumPath := filepath.Join(checksumDir, config.ChecksumFileName)
+		if err := writeChecksumFile(checksumPath, assetPaths); err != nil {
+			return err
+		}
+		uploadPaths = append(uploadPaths, checksumPath)
+	}
+
+	if config.UploadSignatures {
+		for _, path := range assetPaths {
+			for _, signaturePath := range []string{path + ".sig", path + ".asc"} {
+				if fileExists(signaturePath) && !piperutils.ContainsString(uploadPaths, signaturePath) {
+					uploadPaths = append(uploadPaths, signaturePath)
+				}
+			}
+		}
+	}
+
+	names := map[string]string{}
+	for _, path := range uploadPaths {
+		name := filepath.Base(path)
+		if other, ok := names[name]; ok {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return fmt.Errorf("Release assets '%v' and '%v' have the same name", other, path)
+		}
+		names[name] = path
+	}
+
+	for _, path := range uploadPaths {
+		if err := uploadReleaseAsset(ctx, releaseID, path, existingAssets, config, ghRepoClient); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// releaseAssetPaths resolves assetPath and the glob patterns of assetPaths to the list of files to be uploaded
+func releaseAssetPaths(config *githubPublishReleaseOptions) ([]string, error) {
+	paths := []string{}
+	if len(config.AssetPath) > 0 {
+		paths = append(paths, config.AssetPath)
+	}
+	for _, pattern := range config.AssetPaths {
+		matches, err := doublestar.Glob(pattern)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Invalid asset pattern '%v'", pattern)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("No release asset found for pattern '%v'", pattern)
+		}
+		sort.Strings(matches)
+		for _, match := range matches {
+			if info, err := os.Stat(match); err == nil && info.IsDir() {
+				continue
+			}
+			if !piperutils.ContainsString(paths, match) {
+				paths = append(paths, match)
+			}
+		}
+	}
+	return paths, nil
+}
+
+func listReleaseAssets(ctx context.Context, releaseID int64, config *githubPublishReleaseOptions, ghRepoClient githubRepoClient) ([]*github.ReleaseAsset, error) {
+	assets := []*github.ReleaseAsset{}
+	options := github.ListOptions{}
+	for {
+		page, resp, err := ghRepoClient.ListReleaseAssets(ctx, config.Owner, config.Repository, releaseID, &options)
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to get list of release assets.")
 		}
+		assets = append(assets, page...)
+		if resp == nil || resp.NextPage == 0 {
+			return assets, nil
+		}
+		options.Page = resp.NextPage
 	}
-	if assetID != 0 {
-		//asset needs to be deleted first since API does not allow for replacement
-		_, err := ghRepoClient.DeleteReleaseAsset(ctx, config.Owner, config.Repository, assetID)
+}
+
+// writeChecksumFile writes the SHA-256 checksums of the assets in the format of sha256sum
+func writeChecksumFile(checksumPath string, assetPaths []string) error {
+	sortedPaths := append([]string{}, assetPaths...)
+	sort.Slice(sortedPaths, func(i, j int) bool { return filepath.Base(sortedPaths[i]) < filepath.Base(sortedPaths[j]) })
+
+	lines := []string{}
+	for _, path := range sortedPaths {
+		checksum, err := sha256Checksum(path)
 		if err != nil {
-			return errors.Wrap(err, "Failed to delete release asset.")
+			return err
+		}
+		lines = append(lines, fmt.Sprintf("%v  %v", checksum, filepath.Base(path)))
+	}
+
+	content := strings.Join(lines, "\n") + "\n"
+	if err := ioutil.WriteFile(checksumPath, []byte(content), 0644); err != nil {
+		return errors.Wrapf(err, "Failed to write checksum file '%v'", checksumPath)
+	}
+	return nil
+}
+
+func sha256Checksum(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", errors.Wrapf(err, "Failed to load release asset '%v'", path)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", errors.Wrapf(err, "Failed to calculate checksum of release asset '%v'", path)
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil)), nil
+}
+
+func uploadReleaseAsset(ctx context.Context, releaseID int64, assetPath string, existingAssets []*github.ReleaseAsset, config *githubPublishReleaseOptions, ghRepoClient githubRepoClient) error {
+	name := filepath.Base(assetPath)
+	log.Entry().Debugf("Using file name '%v'", name)
+
+	for _, a := range existingAssets {
+		if a.GetName() == name {
+			//asset needs to be deleted first since API does not allow for replacement
+			_, err := ghRepoClient.DeleteReleaseAsset(ctx, config.Owner, config.Repository, a.GetID())
+			if err != nil {
+				return errors.Wrap(err, "Failed to delete release asset.")
+			}
+			log.Entry().Infof("Replacing existing release asset '%v'.", name)
+			break
 		}
 	}
 
-	mediaType := mime.TypeByExtension(filepath.Ext(config.AssetPath))
+	mediaType := mime.TypeByExtension(filepath.Ext(assetPath))
 	if mediaType == "" {
 		mediaType = "application/octet-stream"
 	}
 	log.Entry().Debugf("Using mediaType '%v'", mediaType)
 
-	name := filepath.Base(config.AssetPath)
-	log.Entry().Debugf("Using file name '%v'", name)
-
 	opts := github.UploadOptions{
 		Name:      name,
 		MediaType: mediaType,
 	}
-	file, err := os.Open(config.AssetPath)
-	defer file.Close()
+	file, err := os.Open(assetPath)
 	if err != nil {
-		return errors.Wrapf(err, "Failed to load release asset '%v'", config.AssetPath)
+		return errors.Wrapf(err, "Failed to load release asset '%v'", assetPath)
 	}
+	defer file.Close()
 
-	log.Entry().Info("Starting to upload release asset.")
+	log.Entry().Infof("Starting to upload release asset '%v'.", name)
 	asset, _, err := ghRepoClient.UploadReleaseAsset(ctx, config.Owner, config.Repository, releaseID, &opts, file)
 	if err != nil {
-		return errors.Wrap(err, "Failed to upload release asset.")
+		return errors.Wrapf(err, "Failed to upload release asset '%v'", name)
 	}
 	log.Entry().Infof("Done uploading asset '%v'.", asset.GetURL())
 
@@ -14,21 +14,31 @@ import (
 )
 
 type githubPublishReleaseOptions struct {
-	AddClosedIssues       bool     `json:"addClosedIssues,omitempty"`
-	AddDeltaToLastRelease bool     `json:"addDeltaToLastRelease,omitempty"`
-	APIURL                string   `json:"apiUrl,omitempty"`
-	AssetPath             string   `json:"assetPath,omitempty"`
-	Commitish             string   `json:"commitish,omitempty"`
-	ExcludeLabels         []string `json:"excludeLabels,omitempty"`
-	Labels                []string `json:"labels,omitempty"`
-	Owner                 string   `json:"owner,omitempty"`
-	PreRelease            bool     `json:"preRelease,omitempty"`
-	ReleaseBodyHeader     string   `json:"releaseBodyHeader,omitempty"`
-	Repository            string   `json:"repository,omitempty"`
-	ServerURL             string   `json:"serverUrl,omitempty"`
-	Token                 string   `json:"token,omitempty"`
-	UploadURL             string   `json:"uploadUrl,omitempty"`
-	Version               string   `json:"version,omitempty"`
+	AddChangelog            bool     `json:"addChangelog,omitempty"`
+	AddClosedIssues         bool     `json:"addClosedIssues,omitempty"`
+	AddDeltaToLastRelease   bool     `json:"addDeltaToLastRelease,omitempty"`
+	APIURL                  string   `json:"apiUrl,omitempty"`
+	AssetPath               string   `json:"assetPath,omitempty"`
+	AssetPaths              []string `json:"assetPaths,omitempty"`
+	ChecksumFileName        string   `json:"checksumFileName,omitempty"`
+	Commitish               string   `json:"commitish,omitempty"`
+	CreateChecksums         bool     `json:"createChecksums,omitempty"`
+	Draft                   bool     `json:"draft,omitempty"`
+	ExcludeLabels           []string `json:"excludeLabels,omitempty"`
+	GithubAppID             int      `json:"githubAppId,omitempty"`
+	GithubAppInstallationID int      `json:"githubAppInstallationId,omitempty"`
+	GithubAppPrivateKey     string   `json:"githubAppPrivateKey,omitempty"`
+	Labels                  []string `json:"labels,omitempty"`
+	Owner                   string   `json:"owner,omitempty"`
+	PreRelease              bool     `json:"preRelease,omitempty"`
+	PreReleasePattern       string   `json:"preReleasePattern,omitempty"`
+	ReleaseBodyHeader       string   `json:"releaseBodyHeader,omitempty"`
+	Repository              string   `json:"repository,omitempty"`
+	ServerURL               string   `json:"serverUrl,omitempty"`
+	Token                   string   `json:"token,omitempty"`
+	UploadSignatures        bool     `json:"uploadSignatures,omitempty"`
+	UploadURL               string   `json:"uploadUrl,omitempty"`
+	Version                 string   `json:"version,omitempty"`
 }
 
 // GithubPublishReleaseCommand Publish a release in GitHub
@@ -48,10 +58,17 @@ The release can be filled with text plus additional information like:
 * Closed pull request since last release
 * Closed issues since last release
 * Link to delta information showing all commits since last release
+* Changelog grouping commits, merged pull requests and closed issues since last release (see step ` + "`" + `changelogCreate` + "`" + `)
 
 The result looks like
 
-![Example release](../images/githubRelease.png)`,
+![Example release](../images/githubRelease.png)
+
+Release assets can be provided via ` + "`" + `assetPath` + "`" + ` and ` + "`" + `assetPaths` + "`" + `, the latter supporting glob patterns, e.g. for uploading several platform binaries.
+Optionally a SHA-256 checksum file is created for all assets and detached signatures (` + "`" + `<asset>.sig` + "`" + ` or ` + "`" + `<asset>.asc` + "`" + `) are uploaded along with the assets.
+
+Re-running the step for the same version updates the existing release and replaces assets with the same name.
+This also allows a two-staged release: Create the release as draft first (` + "`" + `draft: true` + "`" + `) and promote it later by running the step again with ` + "`" + `draft: false` + "`" + `.`,
 		PreRunE: func(cmd *cobra.Command, _ []string) error {
 			startTime = time.Now()
 			log.SetStepName(STEP_NAME)
@@ -66,6 +83,7 @@ The result looks like
 				log.SetErrorCategory(log.ErrorConfiguration)
 				return err
 			}
+			log.RegisterSecret(stepConfig.GithubAppPrivateKey)
 			log.RegisterSecret(stepConfig.Token)
 
 			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
@@ -98,19 +116,29 @@ The result looks like
 }
 
 func addGithubPublishReleaseFlags(cmd *cobra.Command, stepConfig *githubPublishReleaseOptions) {
+	cmd.Flags().BoolVar(&stepConfig.AddChangelog, "addChangelog", false, "If set to `true`, a changelog will be added below the `releaseBodyHeader`. It contains the commits of the local git repository, merged pull-requests and closed issues since the last release grouped by their type (see step `changelogCreate`).")
 	cmd.Flags().BoolVar(&stepConfig.AddClosedIssues, "addClosedIssues", false, "If set to `true`, closed issues and merged pull-requests since the last release will added below the `releaseBodyHeader`")
 	cmd.Flags().BoolVar(&stepConfig.AddDeltaToLastRelease, "addDeltaToLastRelease", false, "If set to `true`, a link will be added to the release information that brings up all commits since the last release.")
 	cmd.Flags().StringVar(&stepConfig.APIURL, "apiUrl", `https://api.github.com`, "Set the GitHub API url.")
 	cmd.Flags().StringVar(&stepConfig.AssetPath, "assetPath", os.Getenv("PIPER_assetPath"), "Path to a release asset which should be uploaded to the list of release assets.")
+	cmd.Flags().StringSliceVar(&stepConfig.AssetPaths, "assetPaths", []string{}, "List of paths to release assets which should be uploaded to the list of release assets. Glob patterns like `dist/*` are supported.")
+	cmd.Flags().StringVar(&stepConfig.ChecksumFileName, "checksumFileName", `sha256sums.txt`, "Name of the checksum file which is created in case `createChecksums` is active.")
 	cmd.Flags().StringVar(&stepConfig.Commitish, "commitish", `master`, "Target git commitish for the release")
+	cmd.Flags().BoolVar(&stepConfig.CreateChecksums, "createChecksums", false, "If set to `true` a file containing the SHA-256 checksums of all release assets is created and uploaded as release asset.")
+	cmd.Flags().BoolVar(&stepConfig.Draft, "draft", false, "If set to `true` the release is created as draft. Running the step again with `false` publishes an existing draft of the same version.")
 	cmd.Flags().StringSliceVar(&stepConfig.ExcludeLabels, "excludeLabels", []string{}, "Allows to exclude issues with dedicated list of labels.")
+	cmd.Flags().IntVar(&stepConfig.GithubAppID, "githubAppId", 0, "ID of the GitHub App used to authenticate to GitHub instead of a personal access token. The step authenticates as installation of the app, see [authenticating with GitHub Apps](https://docs.github.com/en/developers/apps/authenticating-with-github-apps).")
+	cmd.Flags().IntVar(&stepConfig.GithubAppInstallationID, "githubAppInstallationId", 0, "ID of the installation of the GitHub App. If not provided, the installation for `owner` and `repository` is looked up.")
+	cmd.Flags().StringVar(&stepConfig.GithubAppPrivateKey, "githubAppPrivateKey", os.Getenv("PIPER_githubAppPrivateKey"), "Path to the file containing the PEM encoded private key of the GitHub App.")
 	cmd.Flags().StringSliceVar(&stepConfig.Labels, "labels", []string{}, "Labels to include in issue search.")
 	cmd.Flags().StringVar(&stepConfig.Owner, "owner", os.Getenv("PIPER_owner"), "Name of the GitHub organization.")
 	cmd.Flags().BoolVar(&stepConfig.PreRelease, "preRelease", false, "If set to `true` the release will be marked as Pre-release.")
+	cmd.Flags().StringVar(&stepConfig.PreReleasePattern, "preReleasePattern", `(?i)-(alpha|beta|rc|pre|preview)`, "Regular expression which marks a release as Pre-release in case it matches the version, e.g. for `1.0.0-rc.1`. Set it to an empty value in order to disable the detection.")
 	cmd.Flags().StringVar(&stepConfig.ReleaseBodyHeader, "releaseBodyHeader", os.Getenv("PIPER_releaseBodyHeader"), "Content which will appear for the release.")
 	cmd.Flags().StringVar(&stepConfig.Repository, "repository", os.Getenv("PIPER_repository"), "Name of the GitHub repository.")
 	cmd.Flags().StringVar(&stepConfig.ServerURL, "serverUrl", `https://github.com`, "GitHub server url for end-user access.")
-	cmd.Flags().StringVar(&stepConfig.Token, "token", os.Getenv("PIPER_token"), "GitHub personal access token as per https://help.github.com/en/github/authenticating-to-github/creating-a-personal-access-token-for-the-command-line")
+	cmd.Flags().StringVar(&stepConfig.Token, "token", os.Getenv("PIPER_token"), "GitHub personal access token as per https://help.github.com/en/github/authenticating-to-github/creating-a-personal-access-token-for-the-command-line. Not required in case a GitHub App is used for authentication (see `githubAppId`).")
+	cmd.Flags().BoolVar(&stepConfig.UploadSignatures, "uploadSignatures", false, "If set to `true` detached signatures of the assets (`<asset>.sig` or `<asset>.asc`) are uploaded as well in case they exist.")
 	cmd.Flags().StringVar(&stepConfig.UploadURL, "uploadUrl", `https://uploads.github.com`, "Set the GitHub API url.")
 	cmd.Flags().StringVar(&stepConfig.Version, "version", os.Getenv("PIPER_version"), "Define the version number which will be written as tag as well as release name.")
 
@@ -118,7 +146,6 @@ func addGithubPublishReleaseFlags(cmd *cobra.Command, stepConfig *githubPublishR
 	cmd.MarkFlagRequired("owner")
 	cmd.MarkFlagRequired("repository")
 	cmd.MarkFlagRequired("serverUrl")
-	cmd.MarkFlagRequired("token")
 	cmd.MarkFlagRequired("uploadUrl")
 	cmd.MarkFlagRequired("version")
 }
@@ -134,6 +161,14 @@ func githubPublishReleaseMetadata() config.StepData {
 		Spec: config.StepSpec{
 			Inputs: config.StepInputs{
 				Parameters: []config.StepParameters{
+					{
+						Name:        "addChangelog",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
 					{
 						Name:        "addClosedIssues",
 						ResourceRef: []config.ResourceReference{},
@@ -166,6 +201,22 @@ func githubPublishReleaseMetadata() config.StepData {
 						Mandatory:   false,
 						Aliases:     []config.Alias{},
 					},
+					{
+						Name:        "assetPaths",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "checksumFileName",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
 					{
 						Name:        "commitish",
 						ResourceRef: []config.ResourceReference{},
@@ -174,6 +225,22 @@ func githubPublishReleaseMetadata() config.StepData {
 						Mandatory:   false,
 						Aliases:     []config.Alias{},
 					},
+					{
+						Name:        "createChecksums",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "draft",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
 					{
 						Name:        "excludeLabels",
 						ResourceRef: []config.ResourceReference{},
@@ -182,6 +249,41 @@ func githubPublishReleaseMetadata() config.StepData {
 						Mandatory:   false,
 						Aliases:     []config.Alias{},
 					},
+					{
+						Name:        "githubAppId",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "githubAppInstallationId",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name: "githubAppPrivateKey",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "githubAppPrivateKeyCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:  "",
+								Paths: []string{"$(vaultPath)/github-app-private-key", "$(vaultBasePath)/$(vaultPipelineName)/github-app-private-key", "$(vaultBasePath)/GROUP-SECRETS/github-app-private-key"},
+								Type:  "vaultSecretFile",
+							},
+						},
+						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+					},
 					{
 						Name:        "labels",
 						ResourceRef: []config.ResourceReference{},
@@ -211,6 +313,14 @@ func githubPublishReleaseMetadata() config.StepData {
 						Mandatory:   false,
 						Aliases:     []config.Alias{},
 					},
+					{
+						Name:        "preReleasePattern",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
 					{
 						Name:        "releaseBodyHeader",
 						ResourceRef: []config.ResourceReference{},
@@ -256,9 +366,17 @@ func githubPublishReleaseMetadata() config.StepData {
 						},
 						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
 						Type:      "string",
-						Mandatory: true,
+						Mandatory: false,
 						Aliases:   []config.Alias{{Name: "githubToken"}, {Name: "access_token"}},
 					},
+					{
+						Name:        "uploadSignatures",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
 					{
 						Name:        "uploadUrl",
 						ResourceRef: []config.ResourceReference{},
@@ -3,19 +3,25 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"os"
 	"path/filepath"
 	"testing"
 	"time"
 
+	"github.com/SAP/jenkins-library/pkg/changelog"
 	"github.com/google/go-github/v32/github"
 	"github.com/stretchr/testify/assert"
 )
 
 type ghRCMock struct {
 	createErr         error
+	editErr           error
+	editID            int64
 	latestRelease     *github.RepositoryRelease
+	releases          []*github.RepositoryRelease
+	listReleasesErr   error
 	release           *github.RepositoryRelease
 	delErr            error
 	delID             int64
@@ -34,6 +40,7 @@ type ghRCMock struct {
 	uploadOpts        *github.UploadOptions
 	uploadOwner       string
 	uploadRepo        string
+	uploaded          map[string]string
 }
 
 func (g *ghRCMock) CreateRelease(ctx context.Context, owner string, repo string, release *github.RepositoryRelease) (*github.RepositoryRelease, *github.Response, error) {
@@ -48,6 +55,12 @@ func (g *ghRCMock) DeleteReleaseAsset(ctx context.Context, owner string, repo st
 	return nil, g.delErr
 }
 
+func (g *ghRCMock) EditRelease(ctx context.Context, owner string, repo string, id int64, release *github.RepositoryRelease) (*github.RepositoryRelease, *github.Response, error) {
+	g.editID = id
+	g.release = release
+	return &github.RepositoryRelease{ID: &id, TagName: release.TagName}, nil, g.editErr
+}
+
 func (g *ghRCMock) GetLatestRelease(ctx context.Context, owner string, repo string) (*github.RepositoryRelease, *github.Response, error) {
 	hc := http.Response{StatusCode: 200}
 	if g.latestStatusCode != 0 {
@@ -62,6 +75,10 @@ func (g *ghRCMock) GetLatestRelease(ctx context.Context, owner string, repo stri
 	return g.latestRelease, &ghResp, g.latestErr
 }
 
+func (g *ghRCMock) ListReleases(ctx context.Context, owner string, repo string, opts *github.ListOptions) ([]*github.RepositoryRelease, *github.Response, error) {
+	return g.releases, nil, g.listReleasesErr
+}
+
 func (g *ghRCMock) ListReleaseAssets(ctx context.Context, owner string, repo string, id int64, opt *github.ListOptions) ([]*github.ReleaseAsset, *github.Response, error) {
 	g.listID = id
 	g.listOwner = owner
@@ -75,6 +92,11 @@ func (g *ghRCMock) UploadReleaseAsset(ctx context.Context, owner string, repo st
 	g.uploadOwner = owner
 	g.uploadRepo = repo
 	g.uploadOpts = opt
+	if g.uploaded == nil {
+		g.uploaded = map[string]string{}
+	}
+	content, _ := ioutil.ReadAll(file)
+	g.uploaded[opt.Name] = string(content)
 	return nil, nil, nil
 }
 
@@ -114,7 +136,7 @@ func TestRunGithubPublishRelease(t *testing.T) {
 			ReleaseBodyHeader:     "Header",
 			Version:               "1.0",
 		}
-		err := runGithubPublishRelease(ctx, &myGithubPublishReleaseOptions, &ghRepoClient, &ghIssueClient)
+		err := runGithubPublishRelease(ctx, &myGithubPublishReleaseOptions, &ghRepoClient, &ghIssueClient, nil)
 		assert.NoError(t, err, "Error occurred but none expected.")
 
 		assert.Equal(t, "Header\n", ghRepoClient.release.GetBody())
@@ -155,7 +177,7 @@ func TestRunGithubPublishRelease(t *testing.T) {
 			ReleaseBodyHeader:     "Header",
 			Version:               "1.1",
 		}
-		err := runGithubPublishRelease(ctx, &myGithubPublishReleaseOptions, &ghRepoClient, &ghIssueClient)
+		err := runGithubPublishRelease(ctx, &myGithubPublishReleaseOptions, &ghRepoClient, &ghIssueClient, nil)
 
 		assert.NoError(t, err, "Error occurred but none expected.")
 
@@ -167,6 +189,42 @@ func TestRunGithubPublishRelease(t *testing.T) {
 		assert.Equal(t, lastPublishedAt.Time, ghIssueClient.lastPublished)
 	})
 
+	t.Run("Success - with changelog", func(t *testing.T) {
+		lastTag := "1.0"
+		lastPublishedAt := github.Timestamp{Time: time.Date(2019, 01, 01, 0, 0, 0, 0, time.UTC)}
+		ghRepoClient := ghRCMock{
+			latestRelease: &github.RepositoryRelease{
+				TagName:     &lastTag,
+				PublishedAt: &lastPublishedAt,
+			},
+		}
+		ghIssueClient := ghICMock{}
+		mergedAt := time.Date(2019, 01, 02, 0, 0, 0, 0, time.UTC)
+		ghPullRequestClient := changelogPullRequestMock{pullRequests: []*github.PullRequest{
+			{Number: github.Int(3), Title: github.String("feat: new option"), HTMLURL: github.String("https://github.com/TEST/test/pull/3"), UpdatedAt: &mergedAt, MergedAt: &mergedAt},
+		}}
+		gitMock := &changelogGitMock{commits: []changelog.Commit{changelog.ParseCommit("1111111111", "fix: crash")}}
+		defer func(original changelogGit) { githubReleaseChangelogGit = original }(githubReleaseChangelogGit)
+		githubReleaseChangelogGit = gitMock
+
+		myGithubPublishReleaseOptions := githubPublishReleaseOptions{
+			AddChangelog:      true,
+			Commitish:         "master",
+			Owner:             "TEST",
+			Repository:        "test",
+			ServerURL:         "https://github.com",
+			ReleaseBodyHeader: "Header",
+			Version:           "1.1",
+		}
+		err := runGithubPublishRelease(ctx, &myGithubPublishReleaseOptions, &ghRepoClient, &ghIssueClient, &ghPullRequestClient)
+
+		assert.NoError(t, err, "Error occurred but none expected.")
+		assert.Equal(t, "1.0", gitMock.from)
+		assert.Equal(t, "master", gitMock.to)
+		assert.Equal(t, "Header\n\n### Features\n\n* feat: new option ([#3](https://github.com/TEST/test/pull/3))\n\n### Bug Fixes\n\n* crash ([1111111](https://github.com/TEST/test/commit/1111111111))\n", ghRepoClient.release.GetBody())
+		assert.Equal(t, lastPublishedAt.Time, ghIssueClient.lastPublished)
+	})
+
 	t.Run("Success - update asset", func(t *testing.T) {
 		var releaseID int64 = 1
 		ghIssueClient := ghICMock{}
@@ -181,7 +239,7 @@ func TestRunGithubPublishRelease(t *testing.T) {
 			Version:   "latest",
 		}
 
-		err := runGithubPublishRelease(ctx, &myGithubPublishReleaseOptions, &ghRepoClient, &ghIssueClient)
+		err := runGithubPublishRelease(ctx, &myGithubPublishReleaseOptions, &ghRepoClient, &ghIssueClient, nil)
 
 		assert.NoError(t, err, "Error occurred but none expected.")
 
@@ -191,6 +249,118 @@ func TestRunGithubPublishRelease(t *testing.T) {
 		assert.Equal(t, releaseID, ghRepoClient.uploadID)
 	})
 
+	t.Run("Success - pre-release detected from version", func(t *testing.T) {
+		ghRepoClient := ghRCMock{}
+		myGithubPublishReleaseOptions := githubPublishReleaseOptions{
+			PreReleasePattern: "(?i)-(alpha|beta|rc|pre|preview)",
+			Version:           "1.1.0-RC.1",
+		}
+		err := runGithubPublishRelease(ctx, &myGithubPublishReleaseOptions, &ghRepoClient, &ghICMock{}, nil)
+
+		assert.NoError(t, err, "Error occurred but none expected.")
+		assert.Equal(t, true, ghRepoClient.release.GetPrerelease())
+	})
+
+	t.Run("Success - no pre-release for timestamp version", func(t *testing.T) {
+		ghRepoClient := ghRCMock{}
+		myGithubPublishReleaseOptions := githubPublishReleaseOptions{
+			PreReleasePattern: "(?i)-(alpha|beta|rc|pre|preview)",
+			Version:           "1.1.0-20200131120000+c2a1b3d",
+		}
+		err := runGithubPublishRelease(ctx, &myGithubPublishReleaseOptions, &ghRepoClient, &ghICMock{}, nil)
+
+		assert.NoError(t, err, "Error occurred but none expected.")
+		assert.Equal(t, false, ghRepoClient.release.GetPrerelease())
+	})
+
+	t.Run("Success - draft", func(t *testing.T) {
+		ghRepoClient := ghRCMock{}
+		myGithubPublishReleaseOptions := githubPublishReleaseOptions{
+			Draft:   true,
+			Version: "1.1",
+		}
+		err := runGithubPublishRelease(ctx, &myGithubPublishReleaseOptions, &ghRepoClient, &ghICMock{}, nil)
+
+		assert.NoError(t, err, "Error occurred but none expected.")
+		assert.Equal(t, true, ghRepoClient.release.GetDraft())
+		assert.Equal(t, int64(0), ghRepoClient.editID)
+	})
+
+	t.Run("Success - promote draft", func(t *testing.T) {
+		var releaseID int64 = 3
+		ghRepoClient := ghRCMock{
+			releases: []*github.RepositoryRelease{
+				{ID: github.Int64(2), TagName: github.String("1.0")},
+				{ID: &releaseID, TagName: github.String("1.1"), Draft: github.Bool(true)},
+			},
+		}
+		myGithubPublishReleaseOptions := githubPublishReleaseOptions{
+			AssetPath:         filepath.Join("testdata", "TestRunGithubPublishRelease", "Success_-_update_asset_test.txt"),
+			ReleaseBodyHeader: "Header",
+			Version:           "1.1",
+		}
+		err := runGithubPublishRelease(ctx, &myGithubPublishReleaseOptions, &ghRepoClient, &ghICMock{}, nil)
+
+		assert.NoError(t, err, "Error occurred but none expected.")
+		assert.Equal(t, releaseID, ghRepoClient.editID)
+		assert.Equal(t, false, ghRepoClient.release.GetDraft())
+		assert.Equal(t, "Header\n", ghRepoClient.release.GetBody())
+		assert.Equal(t, releaseID, ghRepoClient.uploadID)
+	})
+
+	t.Run("Success - re-run for published release", func(t *testing.T) {
+		var releaseID int64 = 3
+		ghRepoClient := ghRCMock{
+			releases: []*github.RepositoryRelease{{ID: &releaseID, TagName: github.String("1.1")}},
+		}
+		myGithubPublishReleaseOptions := githubPublishReleaseOptions{
+			ReleaseBodyHeader: "Header",
+			Version:           "1.1",
+		}
+		err := runGithubPublishRelease(ctx, &myGithubPublishReleaseOptions, &ghRepoClient, &ghICMock{}, nil)
+
+		assert.NoError(t, err, "Error occurred but none expected.")
+		assert.Equal(t, releaseID, ghRepoClient.editID)
+		assert.Nil(t, ghRepoClient.release.Body)
+	})
+
+	t.Run("Error - invalid preReleasePattern", func(t *testing.T) {
+		myGithubPublishReleaseOptions := githubPublishReleaseOptions{
+			PreReleasePattern: "-(rc",
+			Version:           "1.1",
+		}
+		err := runGithubPublishRelease(ctx, &myGithubPublishReleaseOptions, &ghRCMock{}, &ghICMock{}, nil)
+
+		assert.Contains(t, fmt.Sprint(err), "invalid preReleasePattern '-(rc'")
+	})
+
+	t.Run("Error - list releases", func(t *testing.T) {
+		ghRepoClient := ghRCMock{
+			listReleasesErr: fmt.Errorf("List releases error"),
+		}
+		myGithubPublishReleaseOptions := githubPublishReleaseOptions{
+			Owner:      "TEST",
+			Repository: "test",
+			Version:    "1.1",
+		}
+		err := runGithubPublishRelease(ctx, &myGithubPublishReleaseOptions, &ghRepoClient, &ghICMock{}, nil)
+
+		assert.Equal(t, "Failed to get list of releases (TEST/test): List releases error", fmt.Sprint(err))
+	})
+
+	t.Run("Error - update release", func(t *testing.T) {
+		ghRepoClient := ghRCMock{
+			editErr:  fmt.Errorf("Edit release error"),
+			releases: []*github.RepositoryRelease{{ID: github.Int64(3), TagName: github.String("1.1")}},
+		}
+		myGithubPublishReleaseOptions := githubPublishReleaseOptions{
+			Version: "1.1",
+		}
+		err := runGithubPublishRelease(ctx, &myGithubPublishReleaseOptions, &ghRepoClient, &ghICMock{}, nil)
+
+		assert.Equal(t, "Update of release '1.1' failed: Edit release error", fmt.Sprint(err))
+	})
+
 	t.Run("Error - get release", func(t *testing.T) {
 		ghIssueClient := ghICMock{}
 		ghRepoClient := ghRCMock{
@@ -200,7 +370,7 @@ func TestRunGithubPublishRelease(t *testing.T) {
 			Owner:      "TEST",
 			Repository: "test",
 		}
-		err := runGithubPublishRelease(ctx, &myGithubPublishReleaseOptions, &ghRepoClient, &ghIssueClient)
+		err := runGithubPublishRelease(ctx, &myGithubPublishReleaseOptions, &ghRepoClient, &ghIssueClient, nil)
 
 		assert.Equal(t, "Error occurred when retrieving latest GitHub release (TEST/test): Latest release error", fmt.Sprint(err))
 	})
@@ -214,7 +384,7 @@ func TestRunGithubPublishRelease(t *testing.T) {
 			Owner:      "",
 			Repository: "test",
 		}
-		err := runGithubPublishRelease(ctx, &myGithubPublishReleaseOptions, &ghRepoClient, &ghIssueClient)
+		err := runGithubPublishRelease(ctx, &myGithubPublishReleaseOptions, &ghRepoClient, &ghIssueClient, nil)
 
 		assert.Equal(t, "Error occurred when retrieving latest GitHub release (/test): Latest release error, no response", fmt.Sprint(err))
 	})
@@ -227,7 +397,7 @@ func TestRunGithubPublishRelease(t *testing.T) {
 		myGithubPublishReleaseOptions := githubPublishReleaseOptions{
 			Version: "1.0",
 		}
-		err := runGithubPublishRelease(ctx, &myGithubPublishReleaseOptions, &ghRepoClient, &ghIssueClient)
+		err := runGithubPublishRelease(ctx, &myGithubPublishReleaseOptions, &ghRepoClient, &ghIssueClient, nil)
 
 		assert.Equal(t, "Creation of release '1.0' failed: Create release error", fmt.Sprint(err))
 	})
@@ -325,7 +495,7 @@ func TestUploadReleaseAsset(t *testing.T) {
 			AssetPath:  filepath.Join("testdata", t.Name()+"_test.txt"),
 		}
 
-		err := uploadReleaseAsset(ctx, releaseID, &myGithubPublishReleaseOptions, &ghRepoClient)
+		err := uploadReleaseAssets(ctx, releaseID, &myGithubPublishReleaseOptions, &ghRepoClient)
 
 		assert.NoError(t, err, "Error occurred but none expected.")
 
@@ -362,13 +532,67 @@ func TestUploadReleaseAsset(t *testing.T) {
 			AssetPath:  filepath.Join("testdata", t.Name()+"_test.txt"),
 		}
 
-		err := uploadReleaseAsset(ctx, releaseID, &myGithubPublishReleaseOptions, &ghRepoClient)
+		err := uploadReleaseAssets(ctx, releaseID, &myGithubPublishReleaseOptions, &ghRepoClient)
 
 		assert.NoError(t, err, "Error occurred but none expected.")
 
 		assert.Equal(t, int64(0), ghRepoClient.delID, "Relase ID should not be populated")
 	})
 
+	t.Run("Success - multiple assets with checksums and signatures", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "")
+		if err != nil {
+			t.Fatal("Failed to create temporary directory")
+		}
+		defer os.RemoveAll(dir)
+		ioutil.WriteFile(filepath.Join(dir, "tool-linux.tar.gz"), []byte("linux"), 0644)
+		ioutil.WriteFile(filepath.Join(dir, "tool-darwin.tar.gz"), []byte("darwin"), 0644)
+		ioutil.WriteFile(filepath.Join(dir, "tool-linux.tar.gz.sig"), []byte("signature"), 0644)
+
+		var releaseID int64 = 1
+		ghRepoClient := ghRCMock{}
+		myGithubPublishReleaseOptions := githubPublishReleaseOptions{
+			AssetPaths:       []string{filepath.Join(dir, "*.tar.gz")},
+			ChecksumFileName: "sha256sums.txt",
+			CreateChecksums:  true,
+			UploadSignatures: true,
+		}
+
+		err = uploadReleaseAssets(ctx, releaseID, &myGithubPublishReleaseOptions, &ghRepoClient)
+
+		if assert.NoError(t, err, "Error occurred but none expected.") {
+			assert.Equal(t, map[string]string{
+				"tool-darwin.tar.gz":    "darwin",
+				"tool-linux.tar.gz":     "linux",
+				"tool-linux.tar.gz.sig": "signature",
+				"sha256sums.txt":        "26ce1a1580f693873b6268fef54c5f0d0607f2896cad02ce2894c0c899a11575  tool-darwin.tar.gz\ncaf90169eefa5f807d577486b9f795ab86ae2983c5c20806cff959117e90af18  tool-linux.tar.gz\n",
+			}, ghRepoClient.uploaded)
+		}
+	})
+
+	t.Run("Error - no asset matches pattern", func(t *testing.T) {
+		myGithubPublishReleaseOptions := githubPublishReleaseOptions{
+			AssetPaths: []string{filepath.Join("testdata", "notFound", "*.zip")},
+		}
+
+		err := uploadReleaseAssets(ctx, 1, &myGithubPublishReleaseOptions, &ghRCMock{})
+
+		assert.Equal(t, fmt.Sprintf("No release asset found for pattern '%v'", filepath.Join("testdata", "notFound", "*.zip")), fmt.Sprint(err))
+	})
+
+	t.Run("Error - assets with same name", func(t *testing.T) {
+		myGithubPublishReleaseOptions := githubPublishReleaseOptions{
+			AssetPath:        filepath.Join("testdata", "TestUploadReleaseAsset", "Success_-_no_asset_test.txt"),
+			AssetPaths:       []string{filepath.Join("testdata", "TestRunGithubPublishRelease", "*"), filepath.Join("testdata", "TestUploadReleaseAsset", "*")},
+			ChecksumFileName: "Success_-_update_asset_test.txt",
+			CreateChecksums:  true,
+		}
+
+		err := uploadReleaseAssets(ctx, 1, &myGithubPublishReleaseOptions, &ghRCMock{})
+
+		assert.Contains(t, fmt.Sprint(err), "have the same name")
+	})
+
 	t.Run("Error - List Assets", func(t *testing.T) {
 		var releaseID int64 = 1
 		ghRepoClient := ghRCMock{
@@ -376,7 +600,7 @@ func TestUploadReleaseAsset(t *testing.T) {
 		}
 		myGithubPublishReleaseOptions := githubPublishReleaseOptions{}
 
-		err := uploadReleaseAsset(ctx, releaseID, &myGithubPublishReleaseOptions, &ghRepoClient)
+		err := uploadReleaseAssets(ctx, releaseID, &myGithubPublishReleaseOptions, &ghRepoClient)
 		assert.Equal(t, "Failed to get list of release assets.: List Asset Error", fmt.Sprint(err), "Wrong error received")
 	})
 }
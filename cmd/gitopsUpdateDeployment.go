@@ -2,29 +2,40 @@ package cmd
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"github.com/SAP/jenkins-library/pkg/command"
 	"github.com/SAP/jenkins-library/pkg/docker"
 	gitUtil "github.com/SAP/jenkins-library/pkg/git"
+	piperGithub "github.com/SAP/jenkins-library/pkg/github"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/piperutils"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
 	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
 	"io"
+	"math/rand"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
 const toolKubectl = "kubectl"
 const toolHelm = "helm"
+const toolKustomize = "kustomize"
+
+var gitopsRetryInterval = 5 * time.Second
 
 type iGitopsUpdateDeploymentGitUtils interface {
-	CommitSingleFile(filePath, commitMessage, author string) (plumbing.Hash, error)
-	PushChangesToRepository(username, password string) error
-	PlainClone(username, password, serverURL, directory string) error
+	CommitFiles(filePaths []string, commitMessage, author string) (plumbing.Hash, error)
+	PushChanges(auth gitUtil.AuthOptions) error
+	PushChangesToBranch(auth gitUtil.AuthOptions, branchName string) error
+	HasChanges(paths []string) (bool, error)
+	Clone(serverURL, directory string, options gitUtil.CloneOptions) error
 	ChangeBranch(branchName string) error
 }
 
@@ -32,12 +43,14 @@ type gitopsUpdateDeploymentFileUtils interface {
 	TempDir(dir, pattern string) (name string, err error)
 	RemoveAll(path string) error
 	FileWrite(path string, content []byte, perm os.FileMode) error
+	FileRead(path string) ([]byte, error)
 }
 
 type gitopsUpdateDeploymentExecRunner interface {
 	RunExecutable(executable string, params ...string) error
 	Stdout(out io.Writer)
 	Stderr(err io.Writer)
+	SetDir(dir string)
 }
 
 type gitopsUpdateDeploymentGitUtils struct {
@@ -45,19 +58,27 @@ type gitopsUpdateDeploymentGitUtils struct {
 	repository *git.Repository
 }
 
-func (g *gitopsUpdateDeploymentGitUtils) CommitSingleFile(filePath, commitMessage, author string) (plumbing.Hash, error) {
-	return gitUtil.CommitSingleFile(filePath, commitMessage, author, g.worktree)
+func (g *gitopsUpdateDeploymentGitUtils) CommitFiles(filePaths []string, commitMessage, author string) (plumbing.Hash, error) {
+	return gitUtil.CommitFiles(filePaths, commitMessage, author, g.worktree)
+}
+
+func (g *gitopsUpdateDeploymentGitUtils) PushChanges(auth gitUtil.AuthOptions) error {
+	return gitUtil.PushChanges(g.repository, auth)
+}
+
+func (g *gitopsUpdateDeploymentGitUtils) PushChangesToBranch(auth gitUtil.AuthOptions, branchName string) error {
+	return gitUtil.PushChangesToBranch(g.repository, branchName, auth)
 }
 
-func (g *gitopsUpdateDeploymentGitUtils) PushChangesToRepository(username, password string) error {
-	return gitUtil.PushChangesToRepository(username, password, g.repository)
+func (g *gitopsUpdateDeploymentGitUtils) HasChanges(paths []string) (bool, error) {
+	return gitUtil.HasChanges(g.worktree, paths)
 }
 
-func (g *gitopsUpdateDeploymentGitUtils) PlainClone(username, password, serverURL, directory string) error {
+func (g *gitopsUpdateDeploymentGitUtils) Clone(serverURL, directory string, options gitUtil.CloneOptions) error {
 	var err error
-	g.repository, err = gitUtil.PlainClone(username, password, serverURL, directory)
+	g.repository, err = gitUtil.Clone(serverURL, directory, options)
 	if err != nil {
-		return errors.Wrap(err, "plain clone failed")
+		return errors.Wrap(err, "clone failed")
 	}
 	g.worktree, err = g.repository.Worktree()
 	return errors.Wrap(err, "failed to retrieve worktree")
@@ -85,12 +106,40 @@ func gitopsUpdateDeployment(config gitopsUpdateDeploymentOptions, _ *telemetry.C
 	}
 }
 
+// gitopsImage defines a container image which is updated in the deployment descriptors
+type gitopsImage struct {
+	ContainerName         string `json:"containerName,omitempty"`
+	ContainerImageNameTag string `json:"containerImageNameTag,omitempty"`
+	ContainerRegistryURL  string `json:"containerRegistryUrl,omitempty"`
+}
+
 func runGitopsUpdateDeployment(config *gitopsUpdateDeploymentOptions, command gitopsUpdateDeploymentExecRunner, gitUtils iGitopsUpdateDeploymentGitUtils, fileUtils gitopsUpdateDeploymentFileUtils) error {
-	err := checkRequiredFieldsForDeployTool(config)
+	images, err := imagesToUpdate(config)
+	if err != nil {
+		return err
+	}
+
+	err = checkRequiredFieldsForDeployTool(config, images)
 	if err != nil {
 		return err
 	}
 
+	retryInterval := gitopsRetryInterval
+	for attempt := 1; ; attempt++ {
+		err = updateDeployment(config, images, command, gitUtils, fileUtils)
+		if attempt > config.MaxRetries || !gitUtil.IsPushRejected(err) {
+			return err
+		}
+		// randomize the interval to avoid that concurrent pipelines retry at the same time
+		delay := retryInterval + time.Duration(rand.Int63n(int64(retryInterval)+1))
+		log.Entry().WithError(err).Warnf("Changes were rejected since the branch was updated meanwhile, retrying in %v (%v/%v)", delay, attempt, config.MaxRetries)
+		time.Sleep(delay)
+		retryInterval *= 2
+	}
+}
+
+// updateDeployment clones the repository, applies the changes on top of the latest commit and pushes them
+func updateDeployment(config *gitopsUpdateDeploymentOptions, images []gitopsImage, command gitopsUpdateDeploymentExecRunner, gitUtils iGitopsUpdateDeploymentGitUtils, fileUtils gitopsUpdateDeploymentFileUtils) error {
 	temporaryFolder, err := fileUtils.TempDir(".", "temp-")
 	if err != nil {
 		return errors.Wrap(err, "failed to create temporary directory")
@@ -108,30 +157,64 @@ func runGitopsUpdateDeployment(config *gitopsUpdateDeploymentOptions, command gi
 		return errors.Wrap(err, "repository could not get prepared")
 	}
 
-	filePath := filepath.Join(temporaryFolder, config.FilePath)
-
-	var outputBytes []byte
-	if config.Tool == toolKubectl {
-		outputBytes, err = executeKubectl(config, command, outputBytes, filePath)
-		if err != nil {
-			return errors.Wrap(err, "error on kubectl execution")
+	var changedPaths []string
+	switch config.Tool {
+	case toolKubectl:
+		definedContainers := []string{}
+		for _, path := range deploymentFilePaths(config) {
+			filePath := filepath.Join(temporaryFolder, path)
+			outputBytes, containers, err := executeKubectl(images, command, filePath, fileUtils)
+			if err != nil {
+				return errors.Wrap(err, "error on kubectl execution")
+			}
+			definedContainers = append(definedContainers, containers...)
+			if outputBytes == nil {
+				log.Entry().Infof("Images in '%v' are already up to date", path)
+				continue
+			}
+			err = fileUtils.FileWrite(filePath, outputBytes, 0755)
+			if err != nil {
+				return errors.Wrap(err, "failed to write file")
+			}
+			changedPaths = append(changedPaths, path)
+		}
+		if err := checkContainersDefined(images, definedContainers); err != nil {
+			return err
 		}
-	} else if config.Tool == toolHelm {
-		outputBytes, err = runHelmCommand(command, config)
+	case toolHelm:
+		outputBytes, err := runHelmCommand(command, config, images[0])
 		if err != nil {
 			return errors.Wrap(err, "failed to apply helm command")
 		}
-	} else {
+		err = fileUtils.FileWrite(filepath.Join(temporaryFolder, config.FilePath), outputBytes, 0755)
+		if err != nil {
+			return errors.Wrap(err, "failed to write file")
+		}
+		changedPaths = append(changedPaths, config.FilePath)
+	case toolKustomize:
+		for _, path := range deploymentFilePaths(config) {
+			kustomizationDir := kustomizationDirectory(path)
+			err = runKustomizeCommand(command, images, filepath.Join(temporaryFolder, kustomizationDir))
+			if err != nil {
+				return errors.Wrap(err, "error on kustomize execution")
+			}
+			changedPaths = append(changedPaths, kustomizationDir)
+		}
+	default:
 		log.SetErrorCategory(log.ErrorConfiguration)
 		return errors.New("tool " + config.Tool + " is not supported")
 	}
 
-	err = fileUtils.FileWrite(filePath, outputBytes, 0755)
+	changed, err := gitUtils.HasChanges(changedPaths)
 	if err != nil {
-		return errors.Wrap(err, "failed to write file")
+		return errors.Wrap(err, "failed to check for changes")
+	}
+	if !changed {
+		log.Entry().Info("The deployment is already up to date, no changes committed")
+		return nil
 	}
 
-	commit, err := commitAndPushChanges(config, gitUtils)
+	commit, err := commitAndPushChanges(config, gitUtils, changedPaths, images)
 	if err != nil {
 		return errors.Wrap(err, "failed to commit and push changes")
 	}
@@ -141,19 +224,70 @@ func runGitopsUpdateDeployment(config *gitopsUpdateDeploymentOptions, command gi
 	return nil
 }
 
-func checkRequiredFieldsForDeployTool(config *gitopsUpdateDeploymentOptions) error {
+// imagesToUpdate returns the image defined via containerImageNameTag followed by the additionalImages
+func imagesToUpdate(config *gitopsUpdateDeploymentOptions) ([]gitopsImage, error) {
+	images := []gitopsImage{{
+		ContainerName:         config.ContainerName,
+		ContainerImageNameTag: config.ContainerImageNameTag,
+		ContainerRegistryURL:  config.ContainerRegistryURL,
+	}}
+	if len(config.AdditionalImages) == 0 {
+		return images, nil
+	}
+
+	additionalImages := []gitopsImage{}
+	content, err := json.Marshal(config.AdditionalImages)
+	if err == nil {
+		err = json.Unmarshal(content, &additionalImages)
+	}
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return nil, errors.Wrap(err, "failed to read additionalImages")
+	}
+	for i, image := range additionalImages {
+		if len(image.ContainerImageNameTag) == 0 {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return nil, errors.Errorf("containerImageNameTag missing for additional image %v", i+1)
+		}
+		if len(image.ContainerRegistryURL) == 0 {
+			additionalImages[i].ContainerRegistryURL = config.ContainerRegistryURL
+		}
+	}
+	return append(images, additionalImages...), nil
+}
+
+func deploymentFilePaths(config *gitopsUpdateDeploymentOptions) []string {
+	return append([]string{config.FilePath}, config.AdditionalFilePaths...)
+}
+
+// kustomizationDirectory returns the directory of the kustomization, the file path may point to the directory or to the kustomization file
+func kustomizationDirectory(filePath string) string {
+	switch filepath.Base(filePath) {
+	case "kustomization.yaml", "kustomization.yml", "Kustomization":
+		return filepath.Dir(filePath)
+	}
+	return filePath
+}
+
+func checkRequiredFieldsForDeployTool(config *gitopsUpdateDeploymentOptions, images []gitopsImage) error {
 	if config.Tool == toolHelm {
 		err := checkRequiredFieldsForHelm(config)
 		if err != nil {
 			return errors.Wrap(err, "missing required fields for helm")
 		}
+		if len(images) > 1 || len(config.AdditionalFilePaths) > 0 {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return errors.New("helm renders a single image into a single file, additionalImages and additionalFilePaths are not supported")
+		}
 		logNotRequiredButFilledFieldForHelm(config)
 	} else if config.Tool == toolKubectl {
-		err := checkRequiredFieldsForKubectl(config)
+		err := checkRequiredFieldsForKubectl(images)
 		if err != nil {
 			return errors.Wrap(err, "missing required fields for kubectl")
 		}
 		logNotRequiredButFilledFieldForKubectl(config)
+	} else if config.Tool == toolKustomize {
+		logNotRequiredButFilledFieldForKustomize(config)
 	}
 
 	return nil
@@ -174,10 +308,17 @@ func checkRequiredFieldsForHelm(config *gitopsUpdateDeploymentOptions) error {
 	return nil
 }
 
-func checkRequiredFieldsForKubectl(config *gitopsUpdateDeploymentOptions) error {
+func checkRequiredFieldsForKubectl(images []gitopsImage) error {
 	var missingParameters []string
-	if config.ContainerName == "" {
-		missingParameters = append(missingParameters, "containerName")
+	for i, image := range images {
+		if image.ContainerName != "" {
+			continue
+		}
+		if i == 0 {
+			missingParameters = append(missingParameters, "containerName")
+		} else {
+			missingParameters = append(missingParameters, fmt.Sprintf("additionalImages[%v].containerName", i-1))
+		}
 	}
 	if len(missingParameters) > 0 {
 		log.SetErrorCategory(log.ErrorConfiguration)
@@ -204,10 +345,57 @@ func logNotRequiredButFilledFieldForKubectl(config *gitopsUpdateDeploymentOption
 	}
 }
 
+func gitAuthOptions(config *gitopsUpdateDeploymentOptions) gitUtil.AuthOptions {
+	auth := gitUtil.AuthOptions{
+		Username:          config.Username,
+		Password:          config.Password,
+		SSHKnownHostsFile: config.SshKnownHostsFile,
+	}
+	if len(config.Username) == 0 {
+		// e.g. GitHub App installation tokens
+		auth.Token = config.Password
+	}
+	return auth
+}
+
+func logNotRequiredButFilledFieldForKustomize(config *gitopsUpdateDeploymentOptions) {
+	if config.ChartPath != "" {
+		log.Entry().Info("chartPath is not used for kustomize and can be removed")
+	}
+	if len(config.HelmValues) > 0 {
+		log.Entry().Info("helmValues is not used for kustomize and can be removed")
+	}
+	if len(config.DeploymentName) > 0 {
+		log.Entry().Info("deploymentName is not used for kustomize and can be removed")
+	}
+}
+
 func cloneRepositoryAndChangeBranch(config *gitopsUpdateDeploymentOptions, gitUtils iGitopsUpdateDeploymentGitUtils, temporaryFolder string) error {
-	err := gitUtils.PlainClone(config.Username, config.Password, config.ServerURL, temporaryFolder)
+	options := gitUtil.CloneOptions{
+		Auth:  gitAuthOptions(config),
+		Depth: config.CloneDepth,
+	}
+	if config.CloneDepth > 0 || config.SparseCheckout {
+		// only the history of the branch is fetched, therefore the branch has to exist already
+		options.Branch = config.BranchName
+		options.SingleBranch = true
+	}
+	if config.SparseCheckout {
+		for _, path := range deploymentFilePaths(config) {
+			if config.Tool == toolKustomize {
+				options.SparseCheckoutPaths = append(options.SparseCheckoutPaths, kustomizationDirectory(path))
+			} else {
+				options.SparseCheckoutPaths = append(options.SparseCheckoutPaths, filepath.Dir(path))
+			}
+		}
+	}
+
+	err := gitUtils.Clone(config.ServerURL, temporaryFolder, options)
 	if err != nil {
-		return errors.Wrap(err, "failed to plain clone repository")
+		return errors.Wrap(err, "failed to clone repository")
+	}
+	if options.SingleBranch {
+		return nil
 	}
 
 	err = gitUtils.ChangeBranch(config.BranchName)
@@ -217,24 +405,116 @@ func cloneRepositoryAndChangeBranch(config *gitopsUpdateDeploymentOptions, gitUt
 	return nil
 }
 
-func executeKubectl(config *gitopsUpdateDeploymentOptions, command gitopsUpdateDeploymentExecRunner, outputBytes []byte, filePath string) ([]byte, error) {
-	registryImage, err := buildRegistryPlusImage(config)
+// deploymentContainer defines a container of a Kubernetes deployment
+type deploymentContainer struct {
+	Name  string `json:"name" yaml:"name"`
+	Image string `json:"image,omitempty" yaml:"image,omitempty"`
+}
+
+// executeKubectl patches the images of the containers defined in the file and returns the names of these containers.
+// In case all images are up to date no output is returned.
+func executeKubectl(images []gitopsImage, command gitopsUpdateDeploymentExecRunner, filePath string, fileUtils gitopsUpdateDeploymentFileUtils) ([]byte, []string, error) {
+	existingContainers, err := deploymentContainers(filePath, fileUtils)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to apply kubectl command")
+		return nil, nil, errors.Wrap(err, "failed to read deployment")
 	}
-	patchString := "{\"spec\":{\"template\":{\"spec\":{\"containers\":[{\"name\":\"" + config.ContainerName + "\",\"image\":\"" + registryImage + "\"}]}}}}"
 
-	outputBytes, err = runKubeCtlCommand(command, patchString, filePath)
+	// a patch adds containers which do not exist yet, therefore only the containers defined in the file are patched
+	containers := []deploymentContainer{}
+	names := []string{}
+	upToDate := true
+	for _, image := range images {
+		existingImage, ok := existingContainers[image.ContainerName]
+		if !ok {
+			continue
+		}
+		registryImage, err := buildRegistryPlusImage(image)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "failed to apply kubectl command")
+		}
+		containers = append(containers, deploymentContainer{Name: image.ContainerName, Image: registryImage})
+		names = append(names, image.ContainerName)
+		upToDate = upToDate && existingImage == registryImage
+	}
+	if len(containers) == 0 {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return nil, nil, errors.Errorf("none of the containers %v is defined in '%v'", containerNames(images), filePath)
+	}
+	if upToDate {
+		return nil, names, nil
+	}
+
+	patch := map[string]interface{}{"spec": map[string]interface{}{"template": map[string]interface{}{"spec": map[string]interface{}{"containers": containers}}}}
+	patchString, err := json.Marshal(patch)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to apply kubectl command")
+		return nil, nil, errors.Wrap(err, "failed to create patch")
 	}
-	return outputBytes, nil
+
+	outputBytes, err := runKubeCtlCommand(command, string(patchString), filePath)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to apply kubectl command")
+	}
+	return outputBytes, names, nil
 }
 
-func buildRegistryPlusImage(config *gitopsUpdateDeploymentOptions) (string, error) {
-	registryURL := config.ContainerRegistryURL
+// checkContainersDefined ensures that each container is defined in at least one of the files, e.g. a misspelled containerName must not be ignored
+func checkContainersDefined(images []gitopsImage, definedContainers []string) error {
+	missing := []string{}
+	for _, name := range containerNames(images) {
+		if !piperutils.ContainsString(definedContainers, name) {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return errors.Errorf("the following containers are not defined in any of the deployment files: %v", missing)
+	}
+	return nil
+}
+
+func containerNames(images []gitopsImage) []string {
+	names := []string{}
+	for _, image := range images {
+		names = append(names, image.ContainerName)
+	}
+	return names
+}
+
+// deploymentContainers returns the images of the containers defined in the (multi-document) file by container name
+func deploymentContainers(filePath string, fileUtils gitopsUpdateDeploymentFileUtils) (map[string]string, error) {
+	content, err := fileUtils.FileRead(filePath)
+	if err != nil {
+		return nil, err
+	}
+	images := map[string]string{}
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	for {
+		deployment := struct {
+			Spec struct {
+				Template struct {
+					Spec struct {
+						Containers []deploymentContainer `yaml:"containers"`
+					} `yaml:"spec"`
+				} `yaml:"template"`
+			} `yaml:"spec"`
+		}{}
+		err := decoder.Decode(&deployment)
+		if err == io.EOF {
+			return images, nil
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse '%v'", filePath)
+		}
+		for _, container := range deployment.Spec.Template.Spec.Containers {
+			images[container.Name] = container.Image
+		}
+	}
+}
+
+func buildRegistryPlusImage(image gitopsImage) (string, error) {
+	registryURL := image.ContainerRegistryURL
 	if registryURL == "" {
-		return config.ContainerImageNameTag, nil
+		return image.ContainerImageNameTag, nil
 	}
 
 	url, err := docker.ContainerRegistryFromURL(registryURL)
@@ -244,7 +524,7 @@ func buildRegistryPlusImage(config *gitopsUpdateDeploymentOptions) (string, erro
 	if url != "" {
 		url = url + "/"
 	}
-	return url + config.ContainerImageNameTag, nil
+	return url + image.ContainerImageNameTag, nil
 }
 
 func runKubeCtlCommand(command gitopsUpdateDeploymentExecRunner, patchString string, filePath string) ([]byte, error) {
@@ -265,11 +545,11 @@ func runKubeCtlCommand(command gitopsUpdateDeploymentExecRunner, patchString str
 	return kubectlOutput.Bytes(), nil
 }
 
-func runHelmCommand(runner gitopsUpdateDeploymentExecRunner, config *gitopsUpdateDeploymentOptions) ([]byte, error) {
+func runHelmCommand(runner gitopsUpdateDeploymentExecRunner, config *gitopsUpdateDeploymentOptions, image gitopsImage) ([]byte, error) {
 	var helmOutput = bytes.Buffer{}
 	runner.Stdout(&helmOutput)
 
-	registryImage, imageTag, err := buildRegistryPlusImageAndTagSeparately(config)
+	registryImage, imageTag, err := buildRegistryPlusImageAndTagSeparately(image)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to extract registry URL, image name, and image tag")
 	}
@@ -294,8 +574,8 @@ func runHelmCommand(runner gitopsUpdateDeploymentExecRunner, config *gitopsUpdat
 
 // buildRegistryPlusImageAndTagSeparately combines the registry together with the image name. Handles the tag separately.
 // Tag is defined by everything on the right hand side of the colon sign. This looks weird for sha container versions but works for helm.
-func buildRegistryPlusImageAndTagSeparately(config *gitopsUpdateDeploymentOptions) (string, string, error) {
-	registryURL := config.ContainerRegistryURL
+func buildRegistryPlusImageAndTagSeparately(image gitopsImage) (string, string, error) {
+	registryURL := image.ContainerRegistryURL
 	url := ""
 	if registryURL != "" {
 		containerURL, err := docker.ContainerRegistryFromURL(registryURL)
@@ -308,7 +588,7 @@ func buildRegistryPlusImageAndTagSeparately(config *gitopsUpdateDeploymentOption
 		url = containerURL
 	}
 
-	imageNameTag := config.ContainerImageNameTag
+	imageNameTag := image.ContainerImageNameTag
 	var imageName, imageTag string
 	if strings.Contains(imageNameTag, ":") {
 		split := strings.Split(imageNameTag, ":")
@@ -330,19 +610,58 @@ func buildRegistryPlusImageAndTagSeparately(config *gitopsUpdateDeploymentOption
 
 }
 
-func commitAndPushChanges(config *gitopsUpdateDeploymentOptions, gitUtils iGitopsUpdateDeploymentGitUtils) (plumbing.Hash, error) {
+// runKustomizeCommand sets the images in the kustomization located in the directory
+func runKustomizeCommand(runner gitopsUpdateDeploymentExecRunner, images []gitopsImage, directory string) error {
+	runner.SetDir(directory)
+	defer runner.SetDir("")
+
+	for _, image := range images {
+		registryImage, imageTag, err := buildRegistryPlusImageAndTagSeparately(image)
+		if err != nil {
+			return errors.Wrap(err, "failed to extract registry URL, image name, and image tag")
+		}
+		// the name of the image as referenced in the manifests
+		name := image.ContainerName
+		if name == "" {
+			name = strings.Split(image.ContainerImageNameTag, ":")[0]
+		}
+		err = runner.RunExecutable(toolKustomize, "edit", "set", "image", fmt.Sprintf("%v=%v:%v", name, registryImage, imageTag))
+		if err != nil {
+			return errors.Wrap(err, "failed to execute kustomize command")
+		}
+	}
+	return nil
+}
+
+func commitAndPushChanges(config *gitopsUpdateDeploymentOptions, gitUtils iGitopsUpdateDeploymentGitUtils, filePaths []string, images []gitopsImage) (plumbing.Hash, error) {
 	commitMessage := config.CommitMessage
 
 	if commitMessage == "" {
-		commitMessage = defaultCommitMessage(config)
+		commitMessage = defaultCommitMessage(images)
 	}
 
-	commit, err := gitUtils.CommitSingleFile(config.FilePath, commitMessage, config.Username)
+	commit, err := gitUtils.CommitFiles(filePaths, commitMessage, config.Username)
 	if err != nil {
 		return [20]byte{}, errors.Wrap(err, "committing changes failed")
 	}
 
-	err = gitUtils.PushChangesToRepository(config.Username, config.Password)
+	if config.CreatePullRequest {
+		branchName := config.PullRequestBranchName
+		if branchName == "" {
+			branchName = "gitops/" + commit.String()[:8]
+		}
+		err = gitUtils.PushChangesToBranch(gitAuthOptions(config), branchName)
+		if err != nil {
+			return [20]byte{}, errors.Wrap(err, "pushing changes failed")
+		}
+		err = createPullRequestForChanges(config, branchName, commitMessage)
+		if err != nil {
+			return [20]byte{}, errors.Wrap(err, "creating pull request failed")
+		}
+		return commit, nil
+	}
+
+	err = gitUtils.PushChanges(gitAuthOptions(config))
 	if err != nil {
 		return [20]byte{}, errors.Wrap(err, "pushing changes failed")
 	}
@@ -350,8 +669,59 @@ func commitAndPushChanges(config *gitopsUpdateDeploymentOptions, gitUtils iGitop
 	return commit, nil
 }
 
-func defaultCommitMessage(config *gitopsUpdateDeploymentOptions) string {
-	image, tag, _ := buildRegistryPlusImageAndTagSeparately(config)
-	commitMessage := fmt.Sprintf("Updated %v to version %v", image, tag)
-	return commitMessage
+var gitopsCreatePullRequest = func(config *githubCreatePullRequestOptions) error {
+	ctx, client, err := piperGithub.NewClient(config.Token, config.APIURL, "")
+	if err != nil {
+		return errors.Wrap(err, "failed to get GitHub client")
+	}
+	return runGithubCreatePullRequest(ctx, config, client.PullRequests, client.Issues)
+}
+
+// createPullRequestForChanges creates a pull request from the branch containing the changes into branchName
+func createPullRequestForChanges(config *gitopsUpdateDeploymentOptions, head, commitMessage string) error {
+	owner, repository, err := githubRepositoryFromURL(config.ServerURL)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return err
+	}
+	token := config.GithubToken
+	if token == "" {
+		token = config.Password
+	}
+	labels := append([]string{}, config.PullRequestLabels...)
+	assignees := append([]string{}, config.PullRequestAssignees...)
+	return gitopsCreatePullRequest(&githubCreatePullRequestOptions{
+		Title:      strings.SplitN(commitMessage, "\n", 2)[0],
+		Body:       commitMessage,
+		Head:       head,
+		Base:       config.BranchName,
+		Owner:      owner,
+		Repository: repository,
+		APIURL:     config.APIURL,
+		Token:      token,
+		Labels:     labels,
+		Assignees:  assignees,
+	})
+}
+
+// githubRepositoryFromURL returns owner and name of the repository, e.g. for https://github.com/org/repo.git or git@github.com:org/repo.git
+func githubRepositoryFromURL(url string) (string, string, error) {
+	endpoint, err := transport.NewEndpoint(url)
+	if err != nil {
+		return "", "", errors.Wrapf(err, "invalid repository url '%v'", url)
+	}
+	parts := strings.Split(strings.TrimSuffix(strings.Trim(endpoint.Path, "/"), ".git"), "/")
+	if len(parts) < 2 || parts[len(parts)-2] == "" || parts[len(parts)-1] == "" {
+		return "", "", errors.Errorf("failed to retrieve owner and repository from url '%v'", url)
+	}
+	return parts[len(parts)-2], parts[len(parts)-1], nil
+}
+
+func defaultCommitMessage(images []gitopsImage) string {
+	updates := []string{}
+	for _, image := range images {
+		registryImage, tag, _ := buildRegistryPlusImageAndTagSeparately(image)
+		updates = append(updates, fmt.Sprintf("%v to version %v", registryImage, tag))
+	}
+	return "Updated " + strings.Join(updates, ", ")
 }
@@ -14,19 +14,31 @@ import (
 )
 
 type gitopsUpdateDeploymentOptions struct {
-	BranchName            string   `json:"branchName,omitempty"`
-	CommitMessage         string   `json:"commitMessage,omitempty"`
-	ServerURL             string   `json:"serverUrl,omitempty"`
-	Username              string   `json:"username,omitempty"`
-	Password              string   `json:"password,omitempty"`
-	FilePath              string   `json:"filePath,omitempty"`
-	ContainerName         string   `json:"containerName,omitempty"`
-	ContainerRegistryURL  string   `json:"containerRegistryUrl,omitempty"`
-	ContainerImageNameTag string   `json:"containerImageNameTag,omitempty"`
-	ChartPath             string   `json:"chartPath,omitempty"`
-	HelmValues            []string `json:"helmValues,omitempty"`
-	DeploymentName        string   `json:"deploymentName,omitempty"`
-	Tool                  string   `json:"tool,omitempty"`
+	BranchName            string                   `json:"branchName,omitempty"`
+	CommitMessage         string                   `json:"commitMessage,omitempty"`
+	ServerURL             string                   `json:"serverUrl,omitempty"`
+	Username              string                   `json:"username,omitempty"`
+	Password              string                   `json:"password,omitempty"`
+	SshKnownHostsFile     string                   `json:"sshKnownHostsFile,omitempty"`
+	CloneDepth            int                      `json:"cloneDepth,omitempty"`
+	SparseCheckout        bool                     `json:"sparseCheckout,omitempty"`
+	FilePath              string                   `json:"filePath,omitempty"`
+	AdditionalFilePaths   []string                 `json:"additionalFilePaths,omitempty"`
+	ContainerName         string                   `json:"containerName,omitempty"`
+	ContainerRegistryURL  string                   `json:"containerRegistryUrl,omitempty"`
+	ContainerImageNameTag string                   `json:"containerImageNameTag,omitempty"`
+	AdditionalImages      []map[string]interface{} `json:"additionalImages,omitempty"`
+	ChartPath             string                   `json:"chartPath,omitempty"`
+	HelmValues            []string                 `json:"helmValues,omitempty"`
+	DeploymentName        string                   `json:"deploymentName,omitempty"`
+	Tool                  string                   `json:"tool,omitempty"`
+	MaxRetries            int                      `json:"maxRetries,omitempty"`
+	CreatePullRequest     bool                     `json:"createPullRequest,omitempty"`
+	PullRequestBranchName string                   `json:"pullRequestBranchName,omitempty"`
+	PullRequestLabels     []string                 `json:"pullRequestLabels,omitempty"`
+	PullRequestAssignees  []string                 `json:"pullRequestAssignees,omitempty"`
+	APIURL                string                   `json:"apiUrl,omitempty"`
+	GithubToken           string                   `json:"githubToken,omitempty"`
 }
 
 // GitopsUpdateDeploymentCommand Updates Kubernetes Deployment Manifest in an Infrastructure Git Repository
@@ -44,9 +56,41 @@ func GitopsUpdateDeploymentCommand() *cobra.Command {
 
 It can for example be used for GitOps scenarios where the update of the manifests triggers an update of the corresponding deployment in Kubernetes.
 
-As of today, it supports the update of deployment yaml files via kubectl patch and update a whole helm template.
+As of today, it supports the update of deployment yaml files via kubectl patch, the update of Kustomize overlays via ` + "`" + `kustomize edit set image` + "`" + ` and update a whole helm template.
 For kubectl the container inside the yaml must be described within the following hierarchy: ` + "`" + `{"spec":{"template":{"spec":{"containers":[{...}]}}}}` + "`" + `
-For helm the whole template is generated into a file and uploaded into the repository.`,
+For kustomize ` + "`" + `filePath` + "`" + ` points to the directory of the kustomization (e.g. an overlay per environment).
+For helm the whole template is generated into a file and uploaded into the repository.
+
+### Multiple images and files
+
+Besides the image defined via ` + "`" + `containerImageNameTag` + "`" + `, further images can be updated in the same commit via ` + "`" + `additionalImages` + "`" + `.
+Each entry requires ` + "`" + `containerImageNameTag` + "`" + ` and may contain ` + "`" + `containerName` + "`" + ` and ` + "`" + `containerRegistryUrl` + "`" + `:
+
+` + "`" + `` + "`" + `` + "`" + `yaml
+steps:
+  gitopsUpdateDeployment:
+    tool: kustomize
+    filePath: overlays/dev
+    additionalFilePaths:
+      - overlays/staging
+    containerImageNameTag: backend:1.2.3
+    additionalImages:
+      - containerImageNameTag: frontend:1.2.3
+` + "`" + `` + "`" + `` + "`" + `
+
+With kubectl, only the containers defined in the respective file are updated, files may contain multiple YAML documents.
+The step fails in case a file does not define any of the containers or a container is not defined in any of the files, since this indicates a wrong ` + "`" + `containerName` + "`" + ` or ` + "`" + `filePath` + "`" + `.
+Please note that in contrast to former versions of this step a container which does not exist in the file is not added by the patch, but reported as error.
+
+### Concurrent updates
+
+In case the push is rejected since another pipeline updated the branch meanwhile, the repository is cloned again, the changes are applied on top of the latest commit and the push is retried (see ` + "`" + `maxRetries` + "`" + `).
+In case the images are already up to date no commit is created.
+
+### Pull requests
+
+With ` + "`" + `createPullRequest: true` + "`" + ` the changes are pushed to a separate branch and a GitHub pull request into ` + "`" + `branchName` + "`" + ` is opened instead of pushing to ` + "`" + `branchName` + "`" + ` directly.
+Owner and name of the repository are taken from ` + "`" + `serverUrl` + "`" + `.`,
 		PreRunE: func(cmd *cobra.Command, _ []string) error {
 			startTime = time.Now()
 			log.SetStepName(STEP_NAME)
@@ -63,6 +107,7 @@ For helm the whole template is generated into a file and uploaded into the repos
 			}
 			log.RegisterSecret(stepConfig.Username)
 			log.RegisterSecret(stepConfig.Password)
+			log.RegisterSecret(stepConfig.GithubToken)
 
 			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
 				sentryHook := log.NewSentryHook(GeneralConfig.HookConfig.SentryConfig.Dsn, GeneralConfig.CorrelationID)
@@ -99,19 +144,29 @@ func addGitopsUpdateDeploymentFlags(cmd *cobra.Command, stepConfig *gitopsUpdate
 	cmd.Flags().StringVar(&stepConfig.ServerURL, "serverUrl", `https://github.com`, "GitHub server url to the repository.")
 	cmd.Flags().StringVar(&stepConfig.Username, "username", os.Getenv("PIPER_username"), "User name for git authentication")
 	cmd.Flags().StringVar(&stepConfig.Password, "password", os.Getenv("PIPER_password"), "Password/token for git authentication.")
-	cmd.Flags().StringVar(&stepConfig.FilePath, "filePath", os.Getenv("PIPER_filePath"), "Relative path in the git repository to the deployment descriptor file that shall be updated")
+	cmd.Flags().StringVar(&stepConfig.SshKnownHostsFile, "sshKnownHostsFile", os.Getenv("PIPER_sshKnownHostsFile"), "Path to the known_hosts file used to verify the host key of the git server for ssh access.")
+	cmd.Flags().IntVar(&stepConfig.CloneDepth, "cloneDepth", 0, "Limits the fetched history of the repository to the given number of commits. `0` fetches the complete history.")
+	cmd.Flags().BoolVar(&stepConfig.SparseCheckout, "sparseCheckout", false, "Only checks out the directory containing `filePath` instead of the whole repository.")
+	cmd.Flags().StringVar(&stepConfig.FilePath, "filePath", os.Getenv("PIPER_filePath"), "Relative path in the git repository to the deployment descriptor file that shall be updated. For kustomize the directory containing the kustomization.")
+	cmd.Flags().StringSliceVar(&stepConfig.AdditionalFilePaths, "additionalFilePaths", []string{}, "Relative paths of further deployment descriptors (kubectl) or kustomizations (kustomize) which are updated in the same commit.")
 	cmd.Flags().StringVar(&stepConfig.ContainerName, "containerName", os.Getenv("PIPER_containerName"), "The name of the container to update")
 	cmd.Flags().StringVar(&stepConfig.ContainerRegistryURL, "containerRegistryUrl", os.Getenv("PIPER_containerRegistryUrl"), "http(s) url of the Container registry where the image is located")
 	cmd.Flags().StringVar(&stepConfig.ContainerImageNameTag, "containerImageNameTag", os.Getenv("PIPER_containerImageNameTag"), "Container image name with version tag to annotate in the deployment configuration.")
+
 	cmd.Flags().StringVar(&stepConfig.ChartPath, "chartPath", os.Getenv("PIPER_chartPath"), "Defines the chart path for deployments using helm.")
 	cmd.Flags().StringSliceVar(&stepConfig.HelmValues, "helmValues", []string{}, "List of helm values as YAML file reference or URL (as per helm parameter description for `-f` / `--values`)")
 	cmd.Flags().StringVar(&stepConfig.DeploymentName, "deploymentName", os.Getenv("PIPER_deploymentName"), "Defines the name of the deployment.")
 	cmd.Flags().StringVar(&stepConfig.Tool, "tool", `kubectl`, "Defines the tool which should be used to update the deployment description.")
+	cmd.Flags().IntVar(&stepConfig.MaxRetries, "maxRetries", 5, "Maximum number of retries in case the push is rejected since the branch was updated meanwhile.")
+	cmd.Flags().BoolVar(&stepConfig.CreatePullRequest, "createPullRequest", false, "Creates a pull request into `branchName` instead of pushing the changes directly.")
+	cmd.Flags().StringVar(&stepConfig.PullRequestBranchName, "pullRequestBranchName", os.Getenv("PIPER_pullRequestBranchName"), "Name of the branch containing the changes for the pull request. Defaults to `gitops/<commit>`.")
+	cmd.Flags().StringSliceVar(&stepConfig.PullRequestLabels, "pullRequestLabels", []string{}, "Labels to be added to the pull request.")
+	cmd.Flags().StringSliceVar(&stepConfig.PullRequestAssignees, "pullRequestAssignees", []string{}, "Login names of users to which the pull request should be assigned to.")
+	cmd.Flags().StringVar(&stepConfig.APIURL, "apiUrl", `https://api.github.com`, "Set the GitHub API url used to create pull requests.")
+	cmd.Flags().StringVar(&stepConfig.GithubToken, "githubToken", os.Getenv("PIPER_githubToken"), "GitHub token used to create pull requests. If not provided, the password is used.")
 
 	cmd.MarkFlagRequired("branchName")
 	cmd.MarkFlagRequired("serverUrl")
-	cmd.MarkFlagRequired("username")
-	cmd.MarkFlagRequired("password")
 	cmd.MarkFlagRequired("filePath")
 	cmd.MarkFlagRequired("containerRegistryUrl")
 	cmd.MarkFlagRequired("containerImageNameTag")
@@ -167,7 +222,7 @@ func gitopsUpdateDeploymentMetadata() config.StepData {
 						},
 						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
 						Type:      "string",
-						Mandatory: true,
+						Mandatory: false,
 						Aliases:   []config.Alias{},
 					},
 					{
@@ -181,9 +236,33 @@ func gitopsUpdateDeploymentMetadata() config.StepData {
 						},
 						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
 						Type:      "string",
-						Mandatory: true,
+						Mandatory: false,
 						Aliases:   []config.Alias{},
 					},
+					{
+						Name:        "sshKnownHostsFile",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "cloneDepth",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "sparseCheckout",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
 					{
 						Name:        "filePath",
 						ResourceRef: []config.ResourceReference{},
@@ -192,6 +271,14 @@ func gitopsUpdateDeploymentMetadata() config.StepData {
 						Mandatory:   true,
 						Aliases:     []config.Alias{},
 					},
+					{
+						Name:        "additionalFilePaths",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
 					{
 						Name:        "containerName",
 						ResourceRef: []config.ResourceReference{},
@@ -226,6 +313,14 @@ func gitopsUpdateDeploymentMetadata() config.StepData {
 						Mandatory: true,
 						Aliases:   []config.Alias{{Name: "image"}, {Name: "containerImage"}},
 					},
+					{
+						Name:        "additionalImages",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]map[string]interface{}",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
 					{
 						Name:        "chartPath",
 						ResourceRef: []config.ResourceReference{},
@@ -258,11 +353,79 @@ func gitopsUpdateDeploymentMetadata() config.StepData {
 						Mandatory:   true,
 						Aliases:     []config.Alias{},
 					},
+					{
+						Name:        "maxRetries",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "createPullRequest",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "pullRequestBranchName",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "pullRequestLabels",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "pullRequestAssignees",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "apiUrl",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{{Name: "githubApiUrl"}},
+					},
+					{
+						Name: "githubToken",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "githubTokenCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:  "",
+								Paths: []string{"$(vaultPath)/github", "$(vaultBasePath)/$(vaultPipelineName)/github", "$(vaultBasePath)/GROUP-SECRETS/github"},
+								Type:  "vaultSecret",
+							},
+						},
+						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{{Name: "access_token"}},
+					},
 				},
 			},
 			Containers: []config.Container{
 				{Image: "dtzar/helm-kubectl:3.3.4", WorkingDir: "/config", Options: []config.Option{{Name: "-u", Value: "0"}}, Conditions: []config.Condition{{ConditionRef: "strings-equal", Params: []config.Param{{Name: "tool", Value: "helm"}}}}},
 				{Image: "dtzar/helm-kubectl:2.12.1", WorkingDir: "/config", Options: []config.Option{{Name: "-u", Value: "0"}}, Conditions: []config.Condition{{ConditionRef: "strings-equal", Params: []config.Param{{Name: "tool", Value: "kubectl"}}}}},
+				{Image: "dtzar/helm-kubectl:3.4.1", WorkingDir: "/config", Options: []config.Option{{Name: "-u", Value: "0"}}, Conditions: []config.Condition{{ConditionRef: "strings-equal", Params: []config.Param{{Name: "tool", Value: "kustomize"}}}}},
 			},
 		},
 	}
@@ -2,22 +2,26 @@ package cmd
 
 import (
 	"errors"
+	"fmt"
+	gitUtil "github.com/SAP/jenkins-library/pkg/git"
 	"github.com/SAP/jenkins-library/pkg/piperutils"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
+	pkgErrors "github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"io"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestBuildRegistryPlusImage(t *testing.T) {
 	t.Parallel()
 	t.Run("build full image", func(t *testing.T) {
 		t.Parallel()
-		registryImage, err := buildRegistryPlusImage(&gitopsUpdateDeploymentOptions{
+		registryImage, err := buildRegistryPlusImage(gitopsImage{
 			ContainerRegistryURL:  "https://myregistry.com/registry/containers",
 			ContainerImageNameTag: "myFancyContainer:1337",
 		})
@@ -27,7 +31,7 @@ func TestBuildRegistryPlusImage(t *testing.T) {
 
 	t.Run("without registry", func(t *testing.T) {
 		t.Parallel()
-		registryImage, err := buildRegistryPlusImage(&gitopsUpdateDeploymentOptions{
+		registryImage, err := buildRegistryPlusImage(gitopsImage{
 			ContainerRegistryURL:  "",
 			ContainerImageNameTag: "myFancyContainer:1337",
 		})
@@ -36,7 +40,7 @@ func TestBuildRegistryPlusImage(t *testing.T) {
 	})
 	t.Run("without faulty URL", func(t *testing.T) {
 		t.Parallel()
-		_, err := buildRegistryPlusImage(&gitopsUpdateDeploymentOptions{
+		_, err := buildRegistryPlusImage(gitopsImage{
 			ContainerRegistryURL:  "//myregistry.com/registry/containers",
 			ContainerImageNameTag: "myFancyContainer:1337",
 		})
@@ -48,7 +52,7 @@ func TestBuildRegistryPlusImageWithoutTag(t *testing.T) {
 	t.Parallel()
 	t.Run("build full image", func(t *testing.T) {
 		t.Parallel()
-		registryImage, tag, err := buildRegistryPlusImageAndTagSeparately(&gitopsUpdateDeploymentOptions{
+		registryImage, tag, err := buildRegistryPlusImageAndTagSeparately(gitopsImage{
 			ContainerRegistryURL:  "https://myregistry.com/registry/containers",
 			ContainerImageNameTag: "myFancyContainer:1337",
 		})
@@ -59,7 +63,7 @@ func TestBuildRegistryPlusImageWithoutTag(t *testing.T) {
 
 	t.Run("without registry", func(t *testing.T) {
 		t.Parallel()
-		registryImage, tag, err := buildRegistryPlusImageAndTagSeparately(&gitopsUpdateDeploymentOptions{
+		registryImage, tag, err := buildRegistryPlusImageAndTagSeparately(gitopsImage{
 			ContainerRegistryURL:  "",
 			ContainerImageNameTag: "myFancyContainer:1337",
 		})
@@ -69,7 +73,7 @@ func TestBuildRegistryPlusImageWithoutTag(t *testing.T) {
 	})
 	t.Run("without faulty URL", func(t *testing.T) {
 		t.Parallel()
-		_, _, err := buildRegistryPlusImageAndTagSeparately(&gitopsUpdateDeploymentOptions{
+		_, _, err := buildRegistryPlusImageAndTagSeparately(gitopsImage{
 			ContainerRegistryURL:  "//myregistry.com/registry/containers",
 			ContainerImageNameTag: "myFancyContainer:1337",
 		})
@@ -220,12 +224,50 @@ func TestRunGitopsUpdateDeploymentWithKubectl(t *testing.T) {
 		assert.EqualError(t, err, "error on kubectl execution: failed to apply kubectl command: registry URL could not be extracted: invalid registry url")
 	})
 
+	t.Run("shallow sparse clone", func(t *testing.T) {
+		t.Parallel()
+		var configuration = *validConfiguration
+		configuration.CloneDepth = 1
+		configuration.SparseCheckout = true
+		gitUtilsMock := &gitUtilsMock{}
+
+		err := runGitopsUpdateDeployment(&configuration, &gitOpsExecRunnerMock{}, gitUtilsMock, &filesMock{})
+		assert.NoError(t, err)
+		assert.Equal(t, gitUtil.CloneOptions{
+			Auth:                gitUtil.AuthOptions{Username: "admin3", Password: "validAccessToken"},
+			Branch:              "main",
+			SingleBranch:        true,
+			Depth:               1,
+			SparseCheckoutPaths: []string{filepath.Join("dir1", "dir2")},
+		}, gitUtilsMock.cloneOptions)
+		assert.Equal(t, "", gitUtilsMock.changedBranch, "single branch clone must not change the branch")
+		assert.Equal(t, []string{"dir1/dir2/depl.yaml"}, gitUtilsMock.committedFiles)
+		assert.Equal(t, expectedYaml, gitUtilsMock.savedFile)
+	})
+
+	t.Run("token and ssh authentication", func(t *testing.T) {
+		t.Parallel()
+		var configuration = *validConfiguration
+		configuration.Username = ""
+		configuration.SshKnownHostsFile = ".ssh/known_hosts"
+		gitUtilsMock := &gitUtilsMock{}
+
+		err := runGitopsUpdateDeployment(&configuration, &gitOpsExecRunnerMock{}, gitUtilsMock, &filesMock{})
+		assert.NoError(t, err)
+		expectedAuth := gitUtil.AuthOptions{Password: "validAccessToken", Token: "validAccessToken", SSHKnownHostsFile: ".ssh/known_hosts"}
+		assert.Equal(t, expectedAuth, gitUtilsMock.cloneOptions.Auth)
+		assert.Equal(t, expectedAuth, gitUtilsMock.pushAuth)
+		assert.Equal(t, 0, gitUtilsMock.cloneOptions.Depth)
+		assert.False(t, gitUtilsMock.cloneOptions.SingleBranch)
+		assert.Equal(t, "main", gitUtilsMock.changedBranch)
+	})
+
 	t.Run("error on plain clone", func(t *testing.T) {
 		t.Parallel()
 		gitUtils := &gitUtilsMock{failOnClone: true}
 
 		err := runGitopsUpdateDeployment(validConfiguration, &gitOpsExecRunnerMock{}, gitUtils, &filesMock{})
-		assert.EqualError(t, err, "repository could not get prepared: failed to plain clone repository: error on clone")
+		assert.EqualError(t, err, "repository could not get prepared: failed to clone repository: error on clone")
 	})
 
 	t.Run("error on change branch", func(t *testing.T) {
@@ -302,6 +344,307 @@ func TestRunGitopsUpdateDeploymentWithInvalid(t *testing.T) {
 	})
 }
 
+func TestRunGitopsUpdateDeploymentWithMultipleImages(t *testing.T) {
+	var validConfiguration = &gitopsUpdateDeploymentOptions{
+		BranchName:            "main",
+		ServerURL:             "https://github.com",
+		Username:              "admin3",
+		Password:              "validAccessToken",
+		FilePath:              "dir1/dir2/depl.yaml",
+		ContainerName:         "myContainer",
+		ContainerRegistryURL:  "https://myregistry.com/registry/containers",
+		ContainerImageNameTag: "myFancyContainer:1337",
+		AdditionalImages: []map[string]interface{}{
+			{"containerName": "mySidecar", "containerImageNameTag": "mySidecar:42", "containerRegistryUrl": "https://otherregistry.com"},
+		},
+		Tool: "kubectl",
+	}
+
+	t.Parallel()
+	t.Run("kubectl updates the containers defined in each file", func(t *testing.T) {
+		t.Parallel()
+		var configuration = *validConfiguration
+		configuration.AdditionalFilePaths = []string{"dir1/sidecar.yaml"}
+		gitUtilsMock := &gitUtilsMock{additionalFiles: map[string]string{"dir1/sidecar.yaml": sidecarYaml}}
+		runnerMock := &gitOpsExecRunnerMock{}
+
+		err := runGitopsUpdateDeployment(&configuration, runnerMock, gitUtilsMock, &filesMock{})
+		if assert.NoError(t, err) {
+			if assert.Len(t, runnerMock.calls, 2) {
+				assert.Equal(t, `--patch={"spec":{"template":{"spec":{"containers":[{"name":"myContainer","image":"myregistry.com/myFancyContainer:1337"}]}}}}`, runnerMock.calls[0].params[3])
+				assert.Equal(t, `--patch={"spec":{"template":{"spec":{"containers":[{"name":"mySidecar","image":"otherregistry.com/mySidecar:42"}]}}}}`, runnerMock.calls[1].params[3])
+			}
+			assert.Equal(t, "Updated myregistry.com/myFancyContainer to version 1337, otherregistry.com/mySidecar to version 42", gitUtilsMock.commitMessage)
+			assert.Equal(t, []string{"dir1/dir2/depl.yaml", "dir1/sidecar.yaml"}, gitUtilsMock.committedFiles)
+		}
+	})
+
+	t.Run("kubectl with container not defined in any file", func(t *testing.T) {
+		t.Parallel()
+		gitUtilsMock := &gitUtilsMock{}
+
+		err := runGitopsUpdateDeployment(validConfiguration, &gitOpsExecRunnerMock{}, gitUtilsMock, &filesMock{})
+		assert.EqualError(t, err, "the following containers are not defined in any of the deployment files: [mySidecar]")
+		assert.Empty(t, gitUtilsMock.committedFiles)
+	})
+
+	t.Run("kubectl without matching container", func(t *testing.T) {
+		t.Parallel()
+		var configuration = *validConfiguration
+		configuration.ContainerName = "otherContainer"
+
+		err := runGitopsUpdateDeployment(&configuration, &gitOpsExecRunnerMock{}, &gitUtilsMock{}, &filesMock{})
+		assert.Contains(t, fmt.Sprint(err), "error on kubectl execution: none of the containers [otherContainer mySidecar] is defined in")
+	})
+
+	t.Run("kubectl missing container name", func(t *testing.T) {
+		t.Parallel()
+		var configuration = *validConfiguration
+		configuration.AdditionalImages = []map[string]interface{}{{"containerImageNameTag": "mySidecar:42"}}
+
+		err := runGitopsUpdateDeployment(&configuration, &gitOpsExecRunnerMock{}, &gitUtilsMock{}, &filesMock{})
+		assert.EqualError(t, err, "missing required fields for kubectl: the following parameters are necessary for kubectl: [additionalImages[0].containerName]")
+	})
+
+	t.Run("missing image", func(t *testing.T) {
+		t.Parallel()
+		var configuration = *validConfiguration
+		configuration.AdditionalImages = []map[string]interface{}{{"containerName": "mySidecar"}}
+
+		err := runGitopsUpdateDeployment(&configuration, &gitOpsExecRunnerMock{}, &gitUtilsMock{}, &filesMock{})
+		assert.EqualError(t, err, "containerImageNameTag missing for additional image 1")
+	})
+
+	t.Run("kustomize overlays", func(t *testing.T) {
+		t.Parallel()
+		var configuration = *validConfiguration
+		configuration.Tool = "kustomize"
+		configuration.FilePath = "overlays/dev"
+		configuration.AdditionalFilePaths = []string{"overlays/prod/kustomization.yaml"}
+		configuration.ContainerName = ""
+		gitUtilsMock := &gitUtilsMock{}
+		runnerMock := &gitOpsExecRunnerMock{}
+
+		err := runGitopsUpdateDeployment(&configuration, runnerMock, gitUtilsMock, &filesMock{})
+		if assert.NoError(t, err) {
+			assert.Equal(t, []string{"overlays/dev", "overlays/prod"}, gitUtilsMock.committedFiles)
+			if assert.Len(t, runnerMock.calls, 4) {
+				devDir := filepath.Join(gitUtilsMock.temporaryDirectory, "overlays/dev")
+				prodDir := filepath.Join(gitUtilsMock.temporaryDirectory, "overlays/prod")
+				assert.Equal(t, execMockCall{dir: devDir, executable: "kustomize", params: []string{"edit", "set", "image", "myFancyContainer=myregistry.com/myFancyContainer:1337"}}, runnerMock.calls[0])
+				assert.Equal(t, execMockCall{dir: devDir, executable: "kustomize", params: []string{"edit", "set", "image", "mySidecar=otherregistry.com/mySidecar:42"}}, runnerMock.calls[1])
+				assert.Equal(t, prodDir, runnerMock.calls[2].dir)
+				assert.Equal(t, prodDir, runnerMock.calls[3].dir)
+			}
+			assert.Equal(t, "", runnerMock.dir)
+		}
+	})
+
+	t.Run("kustomize sparse checkout", func(t *testing.T) {
+		t.Parallel()
+		var configuration = *validConfiguration
+		configuration.Tool = "kustomize"
+		configuration.FilePath = "overlays/dev/kustomization.yaml"
+		configuration.AdditionalFilePaths = []string{"overlays/prod"}
+		configuration.SparseCheckout = true
+		gitUtilsMock := &gitUtilsMock{}
+
+		err := runGitopsUpdateDeployment(&configuration, &gitOpsExecRunnerMock{}, gitUtilsMock, &filesMock{})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"overlays/dev", "overlays/prod"}, gitUtilsMock.cloneOptions.SparseCheckoutPaths)
+	})
+
+	t.Run("kustomize error", func(t *testing.T) {
+		t.Parallel()
+		var configuration = *validConfiguration
+		configuration.Tool = "kustomize"
+
+		err := runGitopsUpdateDeployment(&configuration, &gitOpsExecRunnerMock{failOnRunExecutable: true}, &gitUtilsMock{}, &filesMock{})
+		assert.EqualError(t, err, "error on kustomize execution: failed to execute kustomize command: error happened")
+	})
+
+	t.Run("helm does not support multiple images", func(t *testing.T) {
+		t.Parallel()
+		var configuration = *validConfiguration
+		configuration.Tool = "helm"
+		configuration.ChartPath = "./helm"
+		configuration.DeploymentName = "myFancyDeployment"
+
+		err := runGitopsUpdateDeployment(&configuration, &gitOpsExecRunnerMock{}, &gitUtilsMock{}, &filesMock{})
+		assert.EqualError(t, err, "helm renders a single image into a single file, additionalImages and additionalFilePaths are not supported")
+	})
+}
+
+func TestRunGitopsUpdateDeploymentWithConflicts(t *testing.T) {
+	var configuration = &gitopsUpdateDeploymentOptions{
+		BranchName:            "main",
+		ServerURL:             "https://github.com/org/gitops",
+		Username:              "admin3",
+		Password:              "validAccessToken",
+		FilePath:              "dir1/dir2/depl.yaml",
+		ContainerName:         "myContainer",
+		ContainerRegistryURL:  "https://myregistry.com/registry/containers",
+		ContainerImageNameTag: "myFancyContainer:1337",
+		Tool:                  "kubectl",
+		MaxRetries:            2,
+	}
+
+	defer func(original time.Duration) { gitopsRetryInterval = original }(gitopsRetryInterval)
+	gitopsRetryInterval = 0
+
+	t.Run("retry after rejected push", func(t *testing.T) {
+		gitUtilsMock := &gitUtilsMock{pushRejections: 2}
+
+		err := runGitopsUpdateDeployment(configuration, &gitOpsExecRunnerMock{}, gitUtilsMock, &filesMock{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, gitUtilsMock.cloneCount, "changes must be applied to a fresh clone")
+		assert.Equal(t, 3, gitUtilsMock.pushCount)
+	})
+
+	t.Run("retries exceeded", func(t *testing.T) {
+		gitUtilsMock := &gitUtilsMock{pushRejections: 3}
+
+		err := runGitopsUpdateDeployment(configuration, &gitOpsExecRunnerMock{}, gitUtilsMock, &filesMock{})
+
+		assert.EqualError(t, err, "failed to commit and push changes: pushing changes failed: failed to push commit: non-fast-forward update: refs/heads/main")
+		assert.Equal(t, 3, gitUtilsMock.pushCount)
+	})
+
+	t.Run("no retry for other errors", func(t *testing.T) {
+		gitUtilsMock := &gitUtilsMock{failOnPush: true}
+
+		err := runGitopsUpdateDeployment(configuration, &gitOpsExecRunnerMock{}, gitUtilsMock, &filesMock{})
+
+		assert.EqualError(t, err, "failed to commit and push changes: pushing changes failed: error on push")
+		assert.Equal(t, 1, gitUtilsMock.cloneCount)
+	})
+}
+
+func TestRunGitopsUpdateDeploymentUpToDate(t *testing.T) {
+	t.Parallel()
+	t.Run("kubectl image already set", func(t *testing.T) {
+		t.Parallel()
+		gitUtilsMock := &gitUtilsMock{}
+		runnerMock := &gitOpsExecRunnerMock{}
+		configuration := &gitopsUpdateDeploymentOptions{
+			BranchName:            "main",
+			FilePath:              "dir1/dir2/depl.yaml",
+			ContainerName:         "myContainer",
+			ContainerRegistryURL:  "https://myregistry.com",
+			ContainerImageNameTag: "myFancyContainer:1336",
+			Tool:                  "kubectl",
+		}
+
+		err := runGitopsUpdateDeployment(configuration, runnerMock, gitUtilsMock, &filesMock{})
+
+		assert.NoError(t, err)
+		assert.Empty(t, runnerMock.calls)
+		assert.Equal(t, "", gitUtilsMock.commitMessage)
+		assert.Equal(t, 0, gitUtilsMock.pushCount)
+	})
+
+	t.Run("kustomization unchanged", func(t *testing.T) {
+		t.Parallel()
+		gitUtilsMock := &gitUtilsMock{unchanged: true}
+		configuration := &gitopsUpdateDeploymentOptions{
+			BranchName:            "main",
+			FilePath:              "overlays/dev",
+			ContainerImageNameTag: "myFancyContainer:1337",
+			Tool:                  "kustomize",
+		}
+
+		err := runGitopsUpdateDeployment(configuration, &gitOpsExecRunnerMock{}, gitUtilsMock, &filesMock{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "", gitUtilsMock.commitMessage)
+		assert.Equal(t, 0, gitUtilsMock.pushCount)
+	})
+}
+
+func TestRunGitopsUpdateDeploymentWithPullRequest(t *testing.T) {
+	var configuration = &gitopsUpdateDeploymentOptions{
+		BranchName:            "main",
+		CommitMessage:         "Update myFancyContainer\n\nRelease 1337",
+		ServerURL:             "git@github.com:org/gitops.git",
+		Password:              "validAccessToken",
+		FilePath:              "dir1/dir2/depl.yaml",
+		ContainerName:         "myContainer",
+		ContainerImageNameTag: "myFancyContainer:1337",
+		Tool:                  "kubectl",
+		CreatePullRequest:     true,
+		PullRequestLabels:     []string{"production"},
+		APIURL:                "https://api.github.com",
+	}
+
+	var pullRequest *githubCreatePullRequestOptions
+	defer func(original func(*githubCreatePullRequestOptions) error) { gitopsCreatePullRequest = original }(gitopsCreatePullRequest)
+	gitopsCreatePullRequest = func(config *githubCreatePullRequestOptions) error {
+		pullRequest = config
+		return nil
+	}
+
+	t.Run("successful run", func(t *testing.T) {
+		gitUtilsMock := &gitUtilsMock{}
+
+		err := runGitopsUpdateDeployment(configuration, &gitOpsExecRunnerMock{}, gitUtilsMock, &filesMock{})
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, "gitops/7b000000", gitUtilsMock.pushedBranch)
+			assert.Equal(t, &githubCreatePullRequestOptions{
+				Title:      "Update myFancyContainer",
+				Body:       "Update myFancyContainer\n\nRelease 1337",
+				Head:       "gitops/7b000000",
+				Base:       "main",
+				Owner:      "org",
+				Repository: "gitops",
+				APIURL:     "https://api.github.com",
+				Token:      "validAccessToken",
+				Labels:     []string{"production"},
+				Assignees:  []string{},
+			}, pullRequest)
+		}
+	})
+
+	t.Run("custom branch and token", func(t *testing.T) {
+		var config = *configuration
+		config.PullRequestBranchName = "update-myFancyContainer"
+		config.GithubToken = "githubToken"
+		gitUtilsMock := &gitUtilsMock{}
+
+		err := runGitopsUpdateDeployment(&config, &gitOpsExecRunnerMock{}, gitUtilsMock, &filesMock{})
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, "update-myFancyContainer", gitUtilsMock.pushedBranch)
+			assert.Equal(t, "githubToken", pullRequest.Token)
+		}
+	})
+
+	t.Run("error on pull request", func(t *testing.T) {
+		gitopsCreatePullRequest = func(*githubCreatePullRequestOptions) error {
+			return errors.New("not authorized")
+		}
+
+		err := runGitopsUpdateDeployment(configuration, &gitOpsExecRunnerMock{}, &gitUtilsMock{}, &filesMock{})
+
+		assert.EqualError(t, err, "failed to commit and push changes: creating pull request failed: not authorized")
+	})
+}
+
+func TestGithubRepositoryFromURL(t *testing.T) {
+	t.Parallel()
+	for _, url := range []string{"https://github.com/org/repo", "https://github.com/org/repo.git", "git@github.com:org/repo.git", "ssh://git@github.com/org/repo.git"} {
+		owner, repository, err := githubRepositoryFromURL(url)
+		if assert.NoError(t, err, url) {
+			assert.Equal(t, "org", owner, url)
+			assert.Equal(t, "repo", repository, url)
+		}
+	}
+
+	_, _, err := githubRepositoryFromURL("https://github.com")
+	assert.EqualError(t, err, "failed to retrieve owner and repository from url 'https://github.com'")
+}
+
 func TestRunGitopsUpdateDeploymentWithHelm(t *testing.T) {
 	var validConfiguration = &gitopsUpdateDeploymentOptions{
 		BranchName:            "main",
@@ -472,7 +815,7 @@ func TestRunGitopsUpdateDeploymentWithHelm(t *testing.T) {
 		gitUtils := &gitUtilsMock{failOnClone: true}
 
 		err := runGitopsUpdateDeployment(validConfiguration, &gitOpsExecRunnerMock{}, gitUtils, &filesMock{})
-		assert.EqualError(t, err, "repository could not get prepared: failed to plain clone repository: error on clone")
+		assert.EqualError(t, err, "repository could not get prepared: failed to clone repository: error on clone")
 	})
 
 	t.Run("error on change branch", func(t *testing.T) {
@@ -530,6 +873,18 @@ type gitOpsExecRunnerMock struct {
 	params              []string
 	executable          string
 	failOnRunExecutable bool
+	dir                 string
+	calls               []execMockCall
+}
+
+type execMockCall struct {
+	dir        string
+	executable string
+	params     []string
+}
+
+func (e *gitOpsExecRunnerMock) SetDir(dir string) {
+	e.dir = dir
 }
 
 func (e *gitOpsExecRunnerMock) Stdout(out io.Writer) {
@@ -546,6 +901,10 @@ func (e *gitOpsExecRunnerMock) RunExecutable(executable string, params ...string
 	}
 	e.executable = executable
 	e.params = params
+	e.calls = append(e.calls, execMockCall{dir: e.dir, executable: executable, params: params})
+	if e.out == nil {
+		return nil
+	}
 	_, err := e.out.Write([]byte(expectedYaml))
 	return err
 }
@@ -564,6 +923,10 @@ func (f filesMock) FileWrite(path string, content []byte, perm os.FileMode) erro
 	return piperutils.Files{}.FileWrite(path, content, perm)
 }
 
+func (f filesMock) FileRead(path string) ([]byte, error) {
+	return piperutils.Files{}.FileRead(path)
+}
+
 func (f filesMock) TempDir(dir string, pattern string) (name string, err error) {
 	if f.failOnCreation {
 		return "", errors.New("error appeared")
@@ -588,6 +951,19 @@ type gitUtilsMock struct {
 	failOnChangeBranch bool
 	failOnCommit       bool
 	failOnPush         bool
+	cloneOptions       gitUtil.CloneOptions
+	pushAuth           gitUtil.AuthOptions
+	committedFiles     []string
+	pushedBranch       string
+	unchanged          bool
+	pushRejections     int
+	cloneCount         int
+	pushCount          int
+	additionalFiles    map[string]string
+}
+
+func (v *gitUtilsMock) HasChanges(paths []string) (bool, error) {
+	return len(paths) > 0 && !v.unchanged, nil
 }
 
 func (gitUtilsMock) GetWorktree() (*git.Worktree, error) {
@@ -602,12 +978,13 @@ func (v *gitUtilsMock) ChangeBranch(branchName string) error {
 	return nil
 }
 
-func (v *gitUtilsMock) CommitSingleFile(_ string, commitMessage string, _ string) (plumbing.Hash, error) {
+func (v *gitUtilsMock) CommitFiles(filePaths []string, commitMessage string, _ string) (plumbing.Hash, error) {
 	if v.failOnCommit {
 		return [20]byte{}, errors.New("error on commit")
 	}
 
 	v.commitMessage = commitMessage
+	v.committedFiles = filePaths
 
 	matches, _ := piperutils.Files{}.Glob(v.temporaryDirectory + "/dir1/dir2/depl.yaml")
 	if len(matches) < 1 {
@@ -618,18 +995,35 @@ func (v *gitUtilsMock) CommitSingleFile(_ string, commitMessage string, _ string
 	return [20]byte{123}, nil
 }
 
-func (v gitUtilsMock) PushChangesToRepository(string, string) error {
+func (v *gitUtilsMock) PushChanges(auth gitUtil.AuthOptions) error {
 	if v.failOnPush {
 		return errors.New("error on push")
 	}
+	v.pushCount++
+	if v.pushRejections > 0 {
+		v.pushRejections--
+		return pkgErrors.Wrap(fmt.Errorf("non-fast-forward update: refs/heads/main"), "failed to push commit")
+	}
+	v.pushAuth = auth
 	return nil
 }
 
-func (v *gitUtilsMock) PlainClone(_, _, _, directory string) error {
+func (v *gitUtilsMock) PushChangesToBranch(auth gitUtil.AuthOptions, branchName string) error {
+	if v.failOnPush {
+		return errors.New("error on push")
+	}
+	v.pushAuth = auth
+	v.pushedBranch = branchName
+	return nil
+}
+
+func (v *gitUtilsMock) Clone(_, directory string, options gitUtil.CloneOptions) error {
 	if v.failOnClone {
 		return errors.New("error on clone")
 	}
 	v.temporaryDirectory = directory
+	v.cloneOptions = options
+	v.cloneCount++
 	filePath := filepath.Join(directory, "dir1/dir2/depl.yaml")
 	err := piperutils.Files{}.MkdirAll(filepath.Join(directory, "dir1/dir2"), 0755)
 	if err != nil {
@@ -639,8 +1033,15 @@ func (v *gitUtilsMock) PlainClone(_, _, _, directory string) error {
 	if err != nil {
 		return err
 	}
+	for path, content := range v.additionalFiles {
+		err = piperutils.Files{}.FileWrite(filepath.Join(directory, path), []byte(content), 0755)
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
 var existingYaml = "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: myFancyApp\n  labels:\n    tier: application\nspec:\n  replicas: 4\n  selector:\n    matchLabels:\n      run: myContainer\n  template:\n    metadata:\n      labels:\n        run: myContainer\n    spec:\n      containers:\n      - image: myregistry.com/myFancyContainer:1336\n        name: myContainer"
+var sidecarYaml = "apiVersion: v1\nkind: Service\nmetadata:\n  name: mySidecar\n---\napiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: mySidecar\nspec:\n  template:\n    spec:\n      containers:\n      - image: otherregistry.com/mySidecar:41\n        name: mySidecar"
 var expectedYaml = "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: myFancyApp\n  labels:\n    tier: application\nspec:\n  replicas: 4\n  selector:\n    matchLabels:\n      run: myContainer\n  template:\n    metadata:\n      labels:\n        run: myContainer\n    spec:\n      containers:\n      - image: myregistry.com/myFancyContainer:1337\n        name: myContainer"
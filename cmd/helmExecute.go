@@ -0,0 +1,209 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/command"
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+type helmExecuteUtils interface {
+	command.ExecRunner
+	piperhttp.Uploader
+
+	Stdin(in io.Reader)
+	FileRead(path string) ([]byte, error)
+	MkdirAll(path string, perm os.FileMode) error
+}
+
+type helmExecuteUtilsBundle struct {
+	*command.Command
+	*piperutils.Files
+	*piperhttp.Client
+}
+
+func newHelmExecuteUtils() helmExecuteUtils {
+	utils := helmExecuteUtilsBundle{
+		Command: &command.Command{},
+		Files:   &piperutils.Files{},
+		Client:  &piperhttp.Client{},
+	}
+	// Reroute command output to logging framework
+	utils.Stdout(log.Writer())
+	utils.Stderr(log.Writer())
+	return &utils
+}
+
+// helmChart contains the fields of the Chart.yaml relevant for packaging
+type helmChart struct {
+	Name    string `yaml:"name"`
+	Version string `yaml:"version"`
+}
+
+func helmExecute(config helmExecuteOptions, telemetryData *telemetry.CustomData) {
+	utils := newHelmExecuteUtils()
+
+	// Error situations should be bubbled up until they reach the line below which will then stop execution
+	// through the log.Entry().Fatal() call leading to an os.Exit(1) in the end.
+	err := runHelmExecute(&config, utils)
+	if err != nil {
+		log.Entry().WithError(err).Fatal("step execution failed")
+	}
+}
+
+func runHelmExecute(config *helmExecuteOptions, utils helmExecuteUtils) error {
+	chart, err := readHelmChart(config.ChartPath, utils)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return err
+	}
+
+	if config.DependencyUpdate {
+		if err := utils.RunExecutable("helm", "dependency", "update", config.ChartPath); err != nil {
+			return errors.Wrapf(err, "failed to update dependencies of chart '%v'", chart.Name)
+		}
+	}
+
+	valuesParams := []string{}
+	for _, values := range config.HelmValues {
+		valuesParams = append(valuesParams, "--values", values)
+	}
+
+	log.Entry().Infof("Linting chart '%v' ...", chart.Name)
+	if err := utils.RunExecutable("helm", append([]string{"lint", config.ChartPath}, valuesParams...)...); err != nil {
+		log.SetErrorCategory(log.ErrorBuild)
+		return errors.Wrapf(err, "linting of chart '%v' failed", chart.Name)
+	}
+
+	if err := renderHelmChart(config, chart, valuesParams, utils); err != nil {
+		log.SetErrorCategory(log.ErrorBuild)
+		return err
+	}
+
+	version := chart.Version
+	packageParams := []string{"package", config.ChartPath, "--destination", config.PackageDirectory}
+	if len(config.ChartVersion) > 0 {
+		version = config.ChartVersion
+		packageParams = append(packageParams, "--version", config.ChartVersion, "--app-version", config.ChartVersion)
+	}
+	if err := utils.MkdirAll(config.PackageDirectory, 0755); err != nil {
+		return errors.Wrapf(err, "failed to create directory '%v'", config.PackageDirectory)
+	}
+	log.Entry().Infof("Packaging chart '%v' in version %v ...", chart.Name, version)
+	if err := utils.RunExecutable("helm", packageParams...); err != nil {
+		log.SetErrorCategory(log.ErrorBuild)
+		return errors.Wrapf(err, "packaging of chart '%v' failed", chart.Name)
+	}
+	chartPackage := filepath.Join(config.PackageDirectory, fmt.Sprintf("%v-%v.tgz", chart.Name, version))
+
+	if config.Publish {
+		return publishHelmChart(config, chartPackage, utils)
+	}
+	return nil
+}
+
+func readHelmChart(chartPath string, utils helmExecuteUtils) (helmChart, error) {
+	chart := helmChart{}
+	content, err := utils.FileRead(filepath.Join(chartPath, "Chart.yaml"))
+	if err != nil {
+		return chart, errors.Wrapf(err, "failed to read chart '%v'", chartPath)
+	}
+	if err := yaml.Unmarshal(content, &chart); err != nil {
+		return chart, errors.Wrapf(err, "failed to parse chart '%v'", chartPath)
+	}
+	if len(chart.Name) == 0 {
+		return chart, fmt.Errorf("chart '%v' does not define a name", chartPath)
+	}
+	return chart, nil
+}
+
+// renderHelmChart renders the chart which validates the values against the schema of the chart and checks that the result is valid YAML
+func renderHelmChart(config *helmExecuteOptions, chart helmChart, valuesParams []string, utils helmExecuteUtils) error {
+	var manifests bytes.Buffer
+	utils.Stdout(&manifests)
+	err := utils.RunExecutable("helm", append([]string{"template", chart.Name, config.ChartPath}, valuesParams...)...)
+	utils.Stdout(log.Writer())
+	if err != nil {
+		return errors.Wrapf(err, "rendering of chart '%v' failed", chart.Name)
+	}
+
+	decoder := yaml.NewDecoder(&manifests)
+	for {
+		var manifest interface{}
+		err := decoder.Decode(&manifest)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrapf(err, "chart '%v' renders invalid manifests", chart.Name)
+		}
+	}
+}
+
+func publishHelmChart(config *helmExecuteOptions, chartPackage string, utils helmExecuteUtils) error {
+	if len(config.TargetRepositoryURL) == 0 {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return fmt.Errorf("target repository has not been set, please configure targetRepositoryUrl parameter")
+	}
+	log.Entry().Infof("Publishing chart package '%v' to %v ...", chartPackage, config.TargetRepositoryURL)
+
+	switch config.TargetRepositoryType {
+	case "oci":
+		if !strings.HasPrefix(config.TargetRepositoryURL, "oci://") {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return fmt.Errorf("OCI registry url '%v' has to start with 'oci://'", config.TargetRepositoryURL)
+		}
+		if len(config.TargetRepositoryUser) > 0 {
+			registry := strings.Split(strings.TrimPrefix(config.TargetRepositoryURL, "oci://"), "/")[0]
+			// the password is passed via stdin in order not to expose it in the process list and the log
+			utils.Stdin(strings.NewReader(config.TargetRepositoryPassword))
+			err := utils.RunExecutable("helm", "registry", "login", registry, "--username", config.TargetRepositoryUser, "--password-stdin")
+			utils.Stdin(nil)
+			if err != nil {
+				return errors.Wrapf(err, "login to registry '%v' failed", registry)
+			}
+		}
+		if err := utils.RunExecutable("helm", "push", chartPackage, config.TargetRepositoryURL); err != nil {
+			return errors.Wrapf(err, "failed to push chart package '%v'", chartPackage)
+		}
+		return nil
+	case "chartmuseum":
+		return uploadHelmChart(config, strings.TrimSuffix(config.TargetRepositoryURL, "/")+"/api/charts", "chart", chartPackage, utils)
+	case "nexus":
+		if len(config.TargetRepositoryName) == 0 {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return fmt.Errorf("repository name has not been set, please configure targetRepositoryName parameter")
+		}
+		uploadURL := fmt.Sprintf("%v/service/rest/v1/components?repository=%v", strings.TrimSuffix(config.TargetRepositoryURL, "/"), url.QueryEscape(config.TargetRepositoryName))
+		return uploadHelmChart(config, uploadURL, "helm.asset", chartPackage, utils)
+	}
+	log.SetErrorCategory(log.ErrorConfiguration)
+	return fmt.Errorf("repository type '%v' is not supported", config.TargetRepositoryType)
+}
+
+func uploadHelmChart(config *helmExecuteOptions, uploadURL, fieldName, chartPackage string, utils helmExecuteUtils) error {
+	utils.SetOptions(piperhttp.ClientOptions{
+		Username: config.TargetRepositoryUser,
+		Password: config.TargetRepositoryPassword,
+	})
+	response, err := utils.UploadRequest(http.MethodPost, uploadURL, chartPackage, fieldName, nil, nil)
+	if response != nil && response.Body != nil {
+		response.Body.Close()
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to upload chart package '%v'", chartPackage)
+	}
+	return nil
+}
@@ -0,0 +1,242 @@
+// Code generated by piper's step-generator. DO NOT EDIT.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/spf13/cobra"
+)
+
+type helmExecuteOptions struct {
+	ChartPath                string   `json:"chartPath,omitempty"`
+	ChartVersion             string   `json:"chartVersion,omitempty"`
+	DependencyUpdate         bool     `json:"dependencyUpdate,omitempty"`
+	HelmValues               []string `json:"helmValues,omitempty"`
+	PackageDirectory         string   `json:"packageDirectory,omitempty"`
+	Publish                  bool     `json:"publish,omitempty"`
+	TargetRepositoryName     string   `json:"targetRepositoryName,omitempty"`
+	TargetRepositoryPassword string   `json:"targetRepositoryPassword,omitempty"`
+	TargetRepositoryType     string   `json:"targetRepositoryType,omitempty"`
+	TargetRepositoryURL      string   `json:"targetRepositoryUrl,omitempty"`
+	TargetRepositoryUser     string   `json:"targetRepositoryUser,omitempty"`
+}
+
+// HelmExecuteCommand Lints, packages and publishes a Helm chart.
+func HelmExecuteCommand() *cobra.Command {
+	const STEP_NAME = "helmExecute"
+
+	metadata := helmExecuteMetadata()
+	var stepConfig helmExecuteOptions
+	var startTime time.Time
+
+	var createHelmExecuteCmd = &cobra.Command{
+		Use:   STEP_NAME,
+		Short: "Lints, packages and publishes a Helm chart.",
+		Long: `This step builds a [Helm](https://helm.sh/) chart as artifact of the pipeline:
+
+1. The dependencies of the chart are updated in case ` + "`" + `dependencyUpdate` + "`" + ` is active.
+1. The chart is linted via ` + "`" + `helm lint` + "`" + `.
+1. The chart is rendered via ` + "`" + `helm template` + "`" + `. This validates the values against the schema of the chart (` + "`" + `values.schema.json` + "`" + `) and ensures that the rendered manifests are valid YAML.
+1. The chart is packaged via ` + "`" + `helm package` + "`" + ` using the version provided by the step ` + "`" + `artifactPrepareVersion` + "`" + ` as chart version and app version.
+1. The package is published to the repository ` + "`" + `targetRepositoryUrl` + "`" + ` in case ` + "`" + `publish` + "`" + ` is active.
+
+The following repository types are supported for publishing (see ` + "`" + `targetRepositoryType` + "`" + `):
+
+* ` + "`" + `oci` + "`" + `: OCI registry, e.g. ` + "`" + `oci://my.registry/charts` + "`" + `. The chart is pushed via ` + "`" + `helm push` + "`" + `.
+* ` + "`" + `chartmuseum` + "`" + `: [ChartMuseum](https://chartmuseum.com/), e.g. ` + "`" + `https://charts.example.org` + "`" + `. The package is uploaded via the ChartMuseum API.
+* ` + "`" + `nexus` + "`" + `: Helm hosted repository of the Nexus Repository Manager 3, e.g. ` + "`" + `https://nexus.example.org` + "`" + `. The package is uploaded via the components API into the repository ` + "`" + `targetRepositoryName` + "`" + `.
+
+Values files provided via ` + "`" + `helmValues` + "`" + ` are used for linting and rendering the chart.`,
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			startTime = time.Now()
+			log.SetStepName(STEP_NAME)
+			log.SetVerbose(GeneralConfig.Verbose)
+
+			path, _ := os.Getwd()
+			fatalHook := &log.FatalHook{CorrelationID: GeneralConfig.CorrelationID, Path: path}
+			log.RegisterHook(fatalHook)
+
+			err := PrepareConfig(cmd, &metadata, STEP_NAME, &stepConfig, config.OpenPiperFile)
+			if err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+			log.RegisterSecret(stepConfig.TargetRepositoryPassword)
+			log.RegisterSecret(stepConfig.TargetRepositoryUser)
+
+			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
+				sentryHook := log.NewSentryHook(GeneralConfig.HookConfig.SentryConfig.Dsn, GeneralConfig.CorrelationID)
+				log.RegisterHook(&sentryHook)
+			}
+
+			return nil
+		},
+		Run: func(_ *cobra.Command, _ []string) {
+			telemetryData := telemetry.CustomData{}
+			telemetryData.ErrorCode = "1"
+			handler := func() {
+				config.RemoveVaultSecretFiles()
+				telemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
+				telemetryData.ErrorCategory = log.GetErrorCategory().String()
+				telemetry.Send(&telemetryData)
+			}
+			log.DeferExitHandler(handler)
+			defer handler()
+			telemetry.Initialize(GeneralConfig.NoTelemetry, STEP_NAME)
+			helmExecute(stepConfig, &telemetryData)
+			telemetryData.ErrorCode = "0"
+			log.Entry().Info("SUCCESS")
+		},
+	}
+
+	addHelmExecuteFlags(createHelmExecuteCmd, &stepConfig)
+	return createHelmExecuteCmd
+}
+
+func addHelmExecuteFlags(cmd *cobra.Command, stepConfig *helmExecuteOptions) {
+	cmd.Flags().StringVar(&stepConfig.ChartPath, "chartPath", os.Getenv("PIPER_chartPath"), "Directory of the Helm chart.")
+	cmd.Flags().StringVar(&stepConfig.ChartVersion, "chartVersion", os.Getenv("PIPER_chartVersion"), "Version of the chart package. It is used as chart version as well as app version, since the chart deploys the image built with the same version.")
+	cmd.Flags().BoolVar(&stepConfig.DependencyUpdate, "dependencyUpdate", false, "Defines whether the dependencies of the chart are updated via `helm dependency update` before linting.")
+	cmd.Flags().StringSliceVar(&stepConfig.HelmValues, "helmValues", []string{}, "List of values files used for linting and rendering the chart.")
+	cmd.Flags().StringVar(&stepConfig.PackageDirectory, "packageDirectory", `.pipeline/helm`, "Directory the chart package is written to.")
+	cmd.Flags().BoolVar(&stepConfig.Publish, "publish", false, "Defines whether the chart package is published to `targetRepositoryUrl`.")
+	cmd.Flags().StringVar(&stepConfig.TargetRepositoryName, "targetRepositoryName", os.Getenv("PIPER_targetRepositoryName"), "Nexus only: name of the Helm hosted repository.")
+	cmd.Flags().StringVar(&stepConfig.TargetRepositoryPassword, "targetRepositoryPassword", os.Getenv("PIPER_targetRepositoryPassword"), "Password for the Helm repository or OCI registry.")
+	cmd.Flags().StringVar(&stepConfig.TargetRepositoryType, "targetRepositoryType", `oci`, "Type of the repository the chart is published to.")
+	cmd.Flags().StringVar(&stepConfig.TargetRepositoryURL, "targetRepositoryUrl", os.Getenv("PIPER_targetRepositoryUrl"), "URL of the Helm repository or OCI registry the chart is published to, e.g. `oci://my.registry/charts`.")
+	cmd.Flags().StringVar(&stepConfig.TargetRepositoryUser, "targetRepositoryUser", os.Getenv("PIPER_targetRepositoryUser"), "Username for the Helm repository or OCI registry.")
+
+	cmd.MarkFlagRequired("chartPath")
+}
+
+// retrieve step metadata
+func helmExecuteMetadata() config.StepData {
+	var theMetaData = config.StepData{
+		Metadata: config.StepMetadata{
+			Name:        "helmExecute",
+			Aliases:     []config.Alias{},
+			Description: "Lints, packages and publishes a Helm chart.",
+		},
+		Spec: config.StepSpec{
+			Inputs: config.StepInputs{
+				Parameters: []config.StepParameters{
+					{
+						Name:        "chartPath",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   true,
+						Aliases:     []config.Alias{{Name: "helmChartPath"}},
+					},
+					{
+						Name: "chartVersion",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "artifactVersion",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{{Name: "artifactVersion"}},
+					},
+					{
+						Name:        "dependencyUpdate",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "helmValues",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "packageDirectory",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "publish",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "targetRepositoryName",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name: "targetRepositoryPassword",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "targetRepositoryCredentialsId",
+								Param: "password",
+								Type:  "secret",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+					},
+					{
+						Name:        "targetRepositoryType",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "targetRepositoryUrl",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name: "targetRepositoryUser",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "targetRepositoryCredentialsId",
+								Param: "username",
+								Type:  "secret",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+					},
+				},
+			},
+			Containers: []config.Container{
+				{Image: "dtzar/helm-kubectl:3.8.2", WorkingDir: "/config", Options: []config.Option{{Name: "-u", Value: "0"}}},
+			},
+		},
+	}
+	return theMetaData
+}
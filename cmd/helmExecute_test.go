@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+type helmExecuteMockUtils struct {
+	*mock.ExecMockRunner
+	*mock.FilesMock
+	uploadErr     error
+	uploadMethod  string
+	uploadURL     string
+	uploadFile    string
+	uploadField   string
+	clientOptions piperhttp.ClientOptions
+}
+
+func (h *helmExecuteMockUtils) SendRequest(method, url string, body io.Reader, header http.Header, cookies []*http.Cookie) (*http.Response, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (h *helmExecuteMockUtils) SetOptions(options piperhttp.ClientOptions) {
+	h.clientOptions = options
+}
+
+func (h *helmExecuteMockUtils) UploadRequest(method, url, file, fieldName string, header http.Header, cookies []*http.Cookie) (*http.Response, error) {
+	h.uploadMethod, h.uploadURL, h.uploadFile, h.uploadField = method, url, file, fieldName
+	return &http.Response{StatusCode: http.StatusCreated}, h.uploadErr
+}
+
+func (h *helmExecuteMockUtils) UploadFile(url, file, fieldName string, header http.Header, cookies []*http.Cookie) (*http.Response, error) {
+	return h.UploadRequest(http.MethodPost, url, file, fieldName, header, cookies)
+}
+
+func (h *helmExecuteMockUtils) Upload(data piperhttp.UploadRequestData) (*http.Response, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func newHelmExecuteTestsUtils() *helmExecuteMockUtils {
+	utils := helmExecuteMockUtils{
+		ExecMockRunner: &mock.ExecMockRunner{},
+		FilesMock:      &mock.FilesMock{},
+	}
+	utils.AddFile("chart/Chart.yaml", []byte("apiVersion: v2\nname: app\nversion: 0.1.0\n"))
+	return &utils
+}
+
+func TestRunHelmExecute(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success - package", func(t *testing.T) {
+		t.Parallel()
+		config := helmExecuteOptions{
+			ChartPath:        "chart",
+			ChartVersion:     "1.2.3-20201019120000+abc",
+			DependencyUpdate: true,
+			HelmValues:       []string{"values-dev.yaml"},
+			PackageDirectory: ".pipeline/helm",
+		}
+		utils := newHelmExecuteTestsUtils()
+		utils.StdoutReturn = map[string]string{"helm template .*": "---\nkind: Service\n---\nkind: Deployment\n"}
+
+		err := runHelmExecute(&config, utils)
+
+		if assert.NoError(t, err) {
+			assert.True(t, utils.HasFile(".pipeline/helm"))
+			assert.Equal(t, []mock.ExecCall{
+				{Exec: "helm", Params: []string{"dependency", "update", "chart"}},
+				{Exec: "helm", Params: []string{"lint", "chart", "--values", "values-dev.yaml"}},
+				{Exec: "helm", Params: []string{"template", "app", "chart", "--values", "values-dev.yaml"}},
+				{Exec: "helm", Params: []string{"package", "chart", "--destination", ".pipeline/helm", "--version", "1.2.3-20201019120000+abc", "--app-version", "1.2.3-20201019120000+abc"}},
+			}, utils.Calls)
+			assert.Empty(t, utils.uploadURL)
+		}
+	})
+
+	t.Run("success - publish to OCI registry", func(t *testing.T) {
+		t.Parallel()
+		config := helmExecuteOptions{
+			ChartPath:                "chart",
+			PackageDirectory:         ".pipeline/helm",
+			Publish:                  true,
+			TargetRepositoryType:     "oci",
+			TargetRepositoryURL:      "oci://my.registry:5000/charts",
+			TargetRepositoryUser:     "user",
+			TargetRepositoryPassword: "password",
+		}
+		utils := newHelmExecuteTestsUtils()
+
+		err := runHelmExecute(&config, utils)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, []string{"package", "chart", "--destination", ".pipeline/helm"}, utils.Calls[2].Params)
+			assert.Equal(t, []string{"registry", "login", "my.registry:5000", "--username", "user", "--password-stdin"}, utils.Calls[3].Params)
+			assert.Equal(t, "password", utils.Calls[3].Stdin)
+			assert.Empty(t, utils.Calls[4].Stdin)
+			assert.Equal(t, []string{"push", ".pipeline/helm/app-0.1.0.tgz", "oci://my.registry:5000/charts"}, utils.Calls[4].Params)
+		}
+	})
+
+	t.Run("success - publish to ChartMuseum", func(t *testing.T) {
+		t.Parallel()
+		config := helmExecuteOptions{
+			ChartPath:                "chart",
+			ChartVersion:             "1.2.3",
+			PackageDirectory:         "dist",
+			Publish:                  true,
+			TargetRepositoryType:     "chartmuseum",
+			TargetRepositoryURL:      "https://charts.example.org/",
+			TargetRepositoryUser:     "user",
+			TargetRepositoryPassword: "password",
+		}
+		utils := newHelmExecuteTestsUtils()
+
+		err := runHelmExecute(&config, utils)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, http.MethodPost, utils.uploadMethod)
+			assert.Equal(t, "https://charts.example.org/api/charts", utils.uploadURL)
+			assert.Equal(t, "dist/app-1.2.3.tgz", utils.uploadFile)
+			assert.Equal(t, "chart", utils.uploadField)
+			assert.Equal(t, piperhttp.ClientOptions{Username: "user", Password: "password"}, utils.clientOptions)
+		}
+	})
+
+	t.Run("success - publish to Nexus", func(t *testing.T) {
+		t.Parallel()
+		config := helmExecuteOptions{
+			ChartPath:            "chart",
+			ChartVersion:         "1.2.3",
+			PackageDirectory:     "dist",
+			Publish:              true,
+			TargetRepositoryName: "helm hosted",
+			TargetRepositoryType: "nexus",
+			TargetRepositoryURL:  "https://nexus.example.org",
+		}
+		utils := newHelmExecuteTestsUtils()
+
+		err := runHelmExecute(&config, utils)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, "https://nexus.example.org/service/rest/v1/components?repository=helm+hosted", utils.uploadURL)
+			assert.Equal(t, "helm.asset", utils.uploadField)
+		}
+	})
+
+	t.Run("error - missing chart", func(t *testing.T) {
+		t.Parallel()
+		config := helmExecuteOptions{ChartPath: "other"}
+
+		err := runHelmExecute(&config, newHelmExecuteTestsUtils())
+
+		assert.Contains(t, fmt.Sprint(err), "failed to read chart 'other'")
+	})
+
+	t.Run("error - lint", func(t *testing.T) {
+		t.Parallel()
+		config := helmExecuteOptions{ChartPath: "chart"}
+		utils := newHelmExecuteTestsUtils()
+		utils.ShouldFailOnCommand = map[string]error{"helm lint chart": fmt.Errorf("chart invalid")}
+
+		err := runHelmExecute(&config, utils)
+
+		assert.EqualError(t, err, "linting of chart 'app' failed: chart invalid")
+	})
+
+	t.Run("error - invalid manifests", func(t *testing.T) {
+		t.Parallel()
+		config := helmExecuteOptions{ChartPath: "chart"}
+		utils := newHelmExecuteTestsUtils()
+		utils.StdoutReturn = map[string]string{"helm template .*": "kind: [Service"}
+
+		err := runHelmExecute(&config, utils)
+
+		assert.Contains(t, fmt.Sprint(err), "chart 'app' renders invalid manifests")
+	})
+
+	t.Run("error - OCI registry without scheme", func(t *testing.T) {
+		t.Parallel()
+		config := helmExecuteOptions{
+			ChartPath:            "chart",
+			Publish:              true,
+			TargetRepositoryType: "oci",
+			TargetRepositoryURL:  "my.registry/charts",
+		}
+
+		err := runHelmExecute(&config, newHelmExecuteTestsUtils())
+
+		assert.EqualError(t, err, "OCI registry url 'my.registry/charts' has to start with 'oci://'")
+	})
+
+	t.Run("error - upload", func(t *testing.T) {
+		t.Parallel()
+		config := helmExecuteOptions{
+			ChartPath:            "chart",
+			PackageDirectory:     "dist",
+			Publish:              true,
+			TargetRepositoryType: "chartmuseum",
+			TargetRepositoryURL:  "https://charts.example.org",
+		}
+		utils := newHelmExecuteTestsUtils()
+		utils.uploadErr = fmt.Errorf("409 Conflict")
+
+		err := runHelmExecute(&config, utils)
+
+		assert.EqualError(t, err, "failed to upload chart package 'dist/app-0.1.0.tgz': 409 Conflict")
+	})
+}
@@ -1,10 +1,12 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"os"
+	"path/filepath"
 	"strings"
 
 	piperhttp "github.com/SAP/jenkins-library/pkg/http"
@@ -66,29 +68,23 @@ func runKanikoExecute(config *kanikoExecuteOptions, telemetryData *telemetry.Cus
 		log.Entry().Info("skipping updation of certificates")
 	}
 
-	if !piperutils.ContainsString(config.BuildOptions, "--destination") {
-		dest := []string{"--no-push"}
-		if len(config.ContainerRegistryURL) > 0 && len(config.ContainerImageName) > 0 && len(config.ContainerImageTag) > 0 {
-			containerRegistry, err := docker.ContainerRegistryFromURL(config.ContainerRegistryURL)
-			if err != nil {
-				return errors.Wrapf(err, "failed to read registry url %v", config.ContainerRegistryURL)
-			}
-			containerImageTag := fmt.Sprintf("%v:%v", config.ContainerImageName, strings.ReplaceAll(config.ContainerImageTag, "+", "-"))
-			dest = []string{"--destination", fmt.Sprintf("%v/%v", containerRegistry, containerImageTag)}
-			commonPipelineEnvironment.container.registryURL = config.ContainerRegistryURL
-			commonPipelineEnvironment.container.imageNameTag = containerImageTag
-		} else if len(config.ContainerImage) > 0 {
-			containerRegistry, err := docker.ContainerRegistryFromImage(config.ContainerImage)
-			if err != nil {
-				return errors.Wrapf(err, "invalid registry part in image %v", config.ContainerImage)
-			}
-			// errors are already caught with previous call to docker.ContainerRegistryFromImage
-			containerImageNameTag, _ := docker.ContainerImageNameTagFromImage(config.ContainerImage)
-			dest = []string{"--destination", config.ContainerImage}
-			commonPipelineEnvironment.container.registryURL = fmt.Sprintf("https://%v", containerRegistry)
-			commonPipelineEnvironment.container.imageNameTag = containerImageNameTag
+	images, err := kanikoImages(config)
+	if err != nil {
+		return err
+	}
+
+	registry, repository, tags, err := kanikoRegistryAndTags(config)
+	if err != nil {
+		return err
+	}
+	// pushing is controlled via the build options in case they contain a destination
+	customDestination := piperutils.ContainsString(config.BuildOptions, "--destination")
+	push := !customDestination && len(registry) > 0
+	if push {
+		commonPipelineEnvironment.container.registryURL = config.ContainerRegistryURL
+		if len(config.ContainerRegistryURL) == 0 {
+			commonPipelineEnvironment.container.registryURL = fmt.Sprintf("https://%v", registry)
 		}
-		config.BuildOptions = append(config.BuildOptions, dest...)
 	}
 
 	dockerConfig := []byte(`{"auths":{}}`)
@@ -108,16 +104,175 @@ func runKanikoExecute(config *kanikoExecuteOptions, telemetryData *telemetry.Cus
 	if err != nil {
 		return errors.Wrap(err, "failed to get current working directory")
 	}
-	kanikoOpts := []string{"--dockerfile", config.DockerfilePath, "--context", cwd}
-	kanikoOpts = append(kanikoOpts, config.BuildOptions...)
 
-	err = execRunner.RunExecutable("/kaniko/executor", kanikoOpts...)
-	if err != nil {
-		return errors.Wrap(err, "execution of '/kaniko/executor' failed")
+	buildArgs := []string{}
+	if config.AddPipelineBuildArgs {
+		if artifactVersion := kanikoArtifactVersion(config); len(artifactVersion) > 0 {
+			buildArgs = append(buildArgs, "--build-arg", fmt.Sprintf("ARTIFACT_VERSION=%v", artifactVersion))
+		}
+		if len(config.CommitID) > 0 {
+			buildArgs = append(buildArgs, "--build-arg", fmt.Sprintf("GIT_COMMIT=%v", config.CommitID))
+		}
+	}
+
+	imageNameTags, imageDigests := []string{}, []string{}
+	for i, image := range images {
+		contextPath := cwd
+		if len(image.ContextPath) > 0 {
+			contextPath = filepath.Join(cwd, image.ContextPath)
+		}
+		kanikoOpts := []string{"--dockerfile", image.DockerfilePath, "--context", contextPath}
+		kanikoOpts = append(kanikoOpts, config.BuildOptions...)
+		if len(images) > 1 && !piperutils.ContainsString(config.BuildOptions, "--cleanup") {
+			// the file system of the container needs to be cleaned up before building the next image
+			kanikoOpts = append(kanikoOpts, "--cleanup")
+		}
+		kanikoOpts = append(kanikoOpts, buildArgs...)
+
+		digestFile := fmt.Sprintf("/kaniko/image-%v.digest", i)
+		if push {
+			for _, tag := range tags {
+				kanikoOpts = append(kanikoOpts, "--destination", fmt.Sprintf("%v%v:%v", repository, image.ContainerImageName, tag))
+			}
+			kanikoOpts = append(kanikoOpts, "--digest-file", digestFile)
+		} else if !customDestination {
+			kanikoOpts = append(kanikoOpts, "--no-push")
+		}
+
+		log.Entry().Infof("Building image '%v' ...", image.ContainerImageName)
+		err = execRunner.RunExecutable("/kaniko/executor", kanikoOpts...)
+		if err != nil {
+			return errors.Wrap(err, "execution of '/kaniko/executor' failed")
+		}
+
+		if push {
+			digest, err := fileUtils.FileRead(digestFile)
+			if err != nil {
+				return errors.Wrapf(err, "failed to read digest of image '%v'", image.ContainerImageName)
+			}
+			imageNameTags = append(imageNameTags, fmt.Sprintf("%v:%v", image.ContainerImageName, tags[0]))
+			imageDigests = append(imageDigests, strings.TrimSpace(string(digest)))
+		}
+	}
+
+	if push {
+		commonPipelineEnvironment.container.imageNameTag = imageNameTags[0]
+		commonPipelineEnvironment.container.imageNameTags = imageNameTags
+		commonPipelineEnvironment.container.imageDigest = imageDigests[0]
+		commonPipelineEnvironment.container.imageDigests = imageDigests
 	}
 	return nil
 }
 
+// kanikoImage defines an image which is built by kanikoExecute
+type kanikoImage struct {
+	ContainerImageName string `json:"containerImageName,omitempty"`
+	DockerfilePath     string `json:"dockerfilePath,omitempty"`
+	ContextPath        string `json:"contextPath,omitempty"`
+}
+
+// kanikoImages returns the image defined via containerImageName or containerImage followed by the additionalImages
+func kanikoImages(config *kanikoExecuteOptions) ([]kanikoImage, error) {
+	name := config.ContainerImageName
+	if len(config.ContainerRegistryURL) == 0 || len(name) == 0 || len(config.ContainerImageTag) == 0 {
+		if len(config.ContainerImage) > 0 {
+			// errors are caught with the call to docker.ContainerRegistryFromImage
+			containerImageNameTag, _ := docker.ContainerImageNameTagFromImage(config.ContainerImage)
+			name, _ = splitImageNameTag(containerImageNameTag)
+		}
+	}
+	images := []kanikoImage{{ContainerImageName: name, DockerfilePath: config.DockerfilePath}}
+	if len(config.AdditionalImages) == 0 {
+		return images, nil
+	}
+
+	additionalImages := []kanikoImage{}
+	content, err := json.Marshal(config.AdditionalImages)
+	if err == nil {
+		err = json.Unmarshal(content, &additionalImages)
+	}
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return nil, errors.Wrap(err, "failed to read additionalImages")
+	}
+	for i, image := range additionalImages {
+		if len(image.ContainerImageName) == 0 {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return nil, errors.Errorf("containerImageName missing for additional image %v", i+1)
+		}
+		if len(image.DockerfilePath) == 0 {
+			additionalImages[i].DockerfilePath = filepath.Join(image.ContextPath, "Dockerfile")
+		}
+	}
+	return append(images, additionalImages...), nil
+}
+
+// kanikoRegistryAndTags returns the registry the images are pushed to, the prefix of the image destinations and the tags of the images.
+// The registry is empty in case the images are not pushed.
+func kanikoRegistryAndTags(config *kanikoExecuteOptions) (string, string, []string, error) {
+	var registry, repository, tag string
+	if len(config.ContainerRegistryURL) > 0 && len(config.ContainerImageName) > 0 && len(config.ContainerImageTag) > 0 {
+		var err error
+		registry, err = docker.ContainerRegistryFromURL(config.ContainerRegistryURL)
+		if err != nil {
+			return "", "", nil, errors.Wrapf(err, "failed to read registry url %v", config.ContainerRegistryURL)
+		}
+		repository = registry + "/"
+		tag = config.ContainerImageTag
+	} else if len(config.ContainerImage) > 0 {
+		var err error
+		registry, err = docker.ContainerRegistryFromImage(config.ContainerImage)
+		if err != nil {
+			return "", "", nil, errors.Wrapf(err, "invalid registry part in image %v", config.ContainerImage)
+		}
+		containerImageNameTag, _ := docker.ContainerImageNameTagFromImage(config.ContainerImage)
+		// keep the registry implicit in case the image does not contain it, e.g. for images on Docker Hub
+		repository = strings.TrimSuffix(config.ContainerImage, containerImageNameTag)
+		_, tag = splitImageNameTag(containerImageNameTag)
+	} else {
+		return "", "", nil, nil
+	}
+
+	tags := []string{}
+	candidates := append([]string{tag}, config.AdditionalTags...)
+	if config.AddCommitIDTag && len(config.CommitID) > 0 {
+		candidates = append(candidates, config.CommitID)
+	}
+	for _, candidate := range candidates {
+		// '+' is not allowed in tags but part of versions containing build metadata
+		candidate = strings.ReplaceAll(candidate, "+", "-")
+		if len(candidate) > 0 && !piperutils.ContainsString(tags, candidate) {
+			tags = append(tags, candidate)
+		}
+	}
+	if len(tags) == 0 {
+		tags = []string{"latest"}
+	}
+	return registry, repository, tags, nil
+}
+
+// kanikoArtifactVersion returns the tag of the image defined via containerImageTag or containerImage, it is provided as build argument ARTIFACT_VERSION
+func kanikoArtifactVersion(config *kanikoExecuteOptions) string {
+	if len(config.ContainerRegistryURL) == 0 || len(config.ContainerImageName) == 0 || len(config.ContainerImageTag) == 0 {
+		if len(config.ContainerImage) > 0 {
+			containerImageNameTag, _ := docker.ContainerImageNameTagFromImage(config.ContainerImage)
+			if _, tag := splitImageNameTag(containerImageNameTag); len(tag) > 0 {
+				return tag
+			}
+		}
+	}
+	return config.ContainerImageTag
+}
+
+// splitImageNameTag splits an image like 'path/image:tag' into name and tag
+func splitImageNameTag(imageNameTag string) (string, string) {
+	separator := strings.LastIndex(imageNameTag, ":")
+	if separator < 0 || strings.Contains(imageNameTag[separator:], "/") {
+		return imageNameTag, ""
+	}
+	return imageNameTag[:separator], imageNameTag[separator+1:]
+}
+
 func certificateUpdate(certLinks []string, httpClient piperhttp.Sender, fileUtils piperutils.FileUtils) error {
 	caCertsFile := "/kaniko/ssl/certs/ca-certificates.crt"
 	caCerts, err := fileUtils.FileRead(caCertsFile)
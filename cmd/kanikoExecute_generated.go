@@ -16,22 +16,30 @@ import (
 )
 
 type kanikoExecuteOptions struct {
-	BuildOptions                []string `json:"buildOptions,omitempty"`
-	ContainerBuildOptions       string   `json:"containerBuildOptions,omitempty"`
-	ContainerImage              string   `json:"containerImage,omitempty"`
-	ContainerImageName          string   `json:"containerImageName,omitempty"`
-	ContainerImageTag           string   `json:"containerImageTag,omitempty"`
-	ContainerPreparationCommand string   `json:"containerPreparationCommand,omitempty"`
-	ContainerRegistryURL        string   `json:"containerRegistryUrl,omitempty"`
-	CustomTLSCertificateLinks   []string `json:"customTlsCertificateLinks,omitempty"`
-	DockerConfigJSON            string   `json:"dockerConfigJSON,omitempty"`
-	DockerfilePath              string   `json:"dockerfilePath,omitempty"`
+	AddCommitIDTag              bool                     `json:"addCommitIdTag,omitempty"`
+	AdditionalImages            []map[string]interface{} `json:"additionalImages,omitempty"`
+	AdditionalTags              []string                 `json:"additionalTags,omitempty"`
+	AddPipelineBuildArgs        bool                     `json:"addPipelineBuildArgs,omitempty"`
+	BuildOptions                []string                 `json:"buildOptions,omitempty"`
+	CommitID                    string                   `json:"commitId,omitempty"`
+	ContainerBuildOptions       string                   `json:"containerBuildOptions,omitempty"`
+	ContainerImage              string                   `json:"containerImage,omitempty"`
+	ContainerImageName          string                   `json:"containerImageName,omitempty"`
+	ContainerImageTag           string                   `json:"containerImageTag,omitempty"`
+	ContainerPreparationCommand string                   `json:"containerPreparationCommand,omitempty"`
+	ContainerRegistryURL        string                   `json:"containerRegistryUrl,omitempty"`
+	CustomTLSCertificateLinks   []string                 `json:"customTlsCertificateLinks,omitempty"`
+	DockerConfigJSON            string                   `json:"dockerConfigJSON,omitempty"`
+	DockerfilePath              string                   `json:"dockerfilePath,omitempty"`
 }
 
 type kanikoExecuteCommonPipelineEnvironment struct {
 	container struct {
-		registryURL  string
-		imageNameTag string
+		registryURL   string
+		imageNameTag  string
+		imageNameTags []string
+		imageDigest   string
+		imageDigests  []string
 	}
 }
 
@@ -43,6 +51,9 @@ func (p *kanikoExecuteCommonPipelineEnvironment) persist(path, resourceName stri
 	}{
 		{category: "container", name: "registryUrl", value: p.container.registryURL},
 		{category: "container", name: "imageNameTag", value: p.container.imageNameTag},
+		{category: "container", name: "imageNameTags", value: p.container.imageNameTags},
+		{category: "container", name: "imageDigest", value: p.container.imageDigest},
+		{category: "container", name: "imageDigests", value: p.container.imageDigests},
 	}
 
 	errCount := 0
@@ -70,7 +81,21 @@ func KanikoExecuteCommand() *cobra.Command {
 	var createKanikoExecuteCmd = &cobra.Command{
 		Use:   STEP_NAME,
 		Short: "Executes a [Kaniko](https://github.com/GoogleContainerTools/kaniko) build for creating a Docker container.",
-		Long:  `Executes a [Kaniko](https://github.com/GoogleContainerTools/kaniko) build for creating a Docker container.`,
+		Long: `Executes a [Kaniko](https://github.com/GoogleContainerTools/kaniko) build for creating a Docker container.
+
+### Multiple images
+
+Further images, e.g. of other services in the same repository, can be built in the same step via ` + "`" + `additionalImages` + "`" + `.
+They are pushed to the same registry with the same tag(s) as the image defined via ` + "`" + `containerRegistryUrl` + "`" + `, ` + "`" + `containerImageName` + "`" + ` and ` + "`" + `containerImageTag` + "`" + ` (or ` + "`" + `containerImage` + "`" + `).
+Since the images are built one after the other in the same container, Kaniko is called with ` + "`" + `--cleanup` + "`" + ` in this case.
+
+### Multiple tags
+
+Besides the version tag, images can be pushed with further tags like ` + "`" + `latest` + "`" + ` (see ` + "`" + `additionalTags` + "`" + `) and with the git commit id (see ` + "`" + `addCommitIdTag` + "`" + `).
+
+### Outputs
+
+The name and tag as well as the digest of all pushed images are written to the commonPipelineEnvironment (` + "`" + `container/imageNameTags` + "`" + ` and ` + "`" + `container/imageDigests` + "`" + `), the values of the first image also to ` + "`" + `container/imageNameTag` + "`" + ` and ` + "`" + `container/imageDigest` + "`" + `.`,
 		PreRunE: func(cmd *cobra.Command, _ []string) error {
 			startTime = time.Now()
 			log.SetStepName(STEP_NAME)
@@ -118,7 +143,12 @@ func KanikoExecuteCommand() *cobra.Command {
 }
 
 func addKanikoExecuteFlags(cmd *cobra.Command, stepConfig *kanikoExecuteOptions) {
+	cmd.Flags().BoolVar(&stepConfig.AddCommitIDTag, "addCommitIdTag", false, "Defines whether the images are additionally tagged with the git commit id (see `commitId`).")
+
+	cmd.Flags().StringSliceVar(&stepConfig.AdditionalTags, "additionalTags", []string{}, "List of further tags (e.g. `latest`) the images are pushed with.")
+	cmd.Flags().BoolVar(&stepConfig.AddPipelineBuildArgs, "addPipelineBuildArgs", false, "Defines whether the build arguments `ARTIFACT_VERSION` (the tag of the image, see `containerImageTag` and `containerImage`) and `GIT_COMMIT` (see `commitId`) are passed to the build.")
 	cmd.Flags().StringSliceVar(&stepConfig.BuildOptions, "buildOptions", []string{`--skip-tls-verify-pull`}, "Defines a list of build options for the [kaniko](https://github.com/GoogleContainerTools/kaniko) build.")
+	cmd.Flags().StringVar(&stepConfig.CommitID, "commitId", os.Getenv("PIPER_commitId"), "The git commit id used for the tag (see `addCommitIdTag`) and the build argument `GIT_COMMIT` (see `addPipelineBuildArgs`).")
 	cmd.Flags().StringVar(&stepConfig.ContainerBuildOptions, "containerBuildOptions", os.Getenv("PIPER_containerBuildOptions"), "Deprected, please use buildOptions. Defines the build options for the [kaniko](https://github.com/GoogleContainerTools/kaniko) build.")
 	cmd.Flags().StringVar(&stepConfig.ContainerImage, "containerImage", os.Getenv("PIPER_containerImage"), "Defines the full name of the Docker image to be created including registry, image name and tag like `my.docker.registry/path/myImageName:myTag`. If left empty, image will not be pushed.")
 	cmd.Flags().StringVar(&stepConfig.ContainerImageName, "containerImageName", os.Getenv("PIPER_containerImageName"), "Name of the container which will be built - will be used instead of parameter `containerImage`")
@@ -142,6 +172,38 @@ func kanikoExecuteMetadata() config.StepData {
 		Spec: config.StepSpec{
 			Inputs: config.StepInputs{
 				Parameters: []config.StepParameters{
+					{
+						Name:        "addCommitIdTag",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "additionalImages",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]map[string]interface{}",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "additionalTags",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "addPipelineBuildArgs",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
 					{
 						Name:        "buildOptions",
 						ResourceRef: []config.ResourceReference{},
@@ -150,6 +212,19 @@ func kanikoExecuteMetadata() config.StepData {
 						Mandatory:   false,
 						Aliases:     []config.Alias{},
 					},
+					{
+						Name: "commitId",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "git/commitId",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+					},
 					{
 						Name:        "containerBuildOptions",
 						ResourceRef: []config.ResourceReference{},
@@ -256,6 +331,9 @@ func kanikoExecuteMetadata() config.StepData {
 						Parameters: []map[string]interface{}{
 							{"Name": "container/registryUrl"},
 							{"Name": "container/imageNameTag"},
+							{"Name": "container/imageNameTags"},
+							{"Name": "container/imageDigest"},
+							{"Name": "container/imageDigests"},
 						},
 					},
 				},
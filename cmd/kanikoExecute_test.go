@@ -7,6 +7,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"os"
+	"path/filepath"
 	"testing"
 
 	piperhttp "github.com/SAP/jenkins-library/pkg/http"
@@ -118,7 +119,7 @@ func TestRunKanikoExecute(t *testing.T) {
 
 		assert.Equal(t, "/kaniko/executor", runner.Calls[1].Exec)
 		cwd, _ := os.Getwd()
-		assert.Equal(t, []string{"--dockerfile", "Dockerfile", "--context", cwd, "--skip-tls-verify-pull", "--destination", "myImage:tag"}, runner.Calls[1].Params)
+		assert.Equal(t, []string{"--dockerfile", "Dockerfile", "--context", cwd, "--skip-tls-verify-pull", "--destination", "myImage:tag", "--digest-file", "/kaniko/image-0.digest"}, runner.Calls[1].Params)
 
 	})
 
@@ -156,7 +157,7 @@ func TestRunKanikoExecute(t *testing.T) {
 
 		assert.Equal(t, "/kaniko/executor", runner.Calls[1].Exec)
 		cwd, _ := os.Getwd()
-		assert.Equal(t, []string{"--dockerfile", "Dockerfile", "--context", cwd, "--skip-tls-verify-pull", "--destination", "my.registry.com:50000/myImage:1.2.3-a-x"}, runner.Calls[1].Params)
+		assert.Equal(t, []string{"--dockerfile", "Dockerfile", "--context", cwd, "--skip-tls-verify-pull", "--destination", "my.registry.com:50000/myImage:1.2.3-a-x", "--digest-file", "/kaniko/image-0.digest"}, runner.Calls[1].Params)
 
 	})
 
@@ -212,6 +213,30 @@ func TestRunKanikoExecute(t *testing.T) {
 		assert.Equal(t, []string{"--dockerfile", "Dockerfile", "--context", cwd, "--skip-tls-verify-pull", "--no-push"}, runner.Calls[1].Params)
 	})
 
+	t.Run("success case - build args with containerImage", func(t *testing.T) {
+		config := &kanikoExecuteOptions{
+			AddPipelineBuildArgs: true,
+			CommitID:             "a1b2c3",
+			ContainerImage:       "my.registry.com:50000/myImage:1.2.3",
+			DockerfilePath:       "Dockerfile",
+		}
+
+		runner := &mock.ExecMockRunner{}
+
+		certClient := &kanikoMockClient{}
+		fileUtils := &kanikoFileMock{
+			fileReadContent:  map[string]string{"/kaniko/image-0.digest": "sha256:000"},
+			fileWriteContent: map[string]string{},
+		}
+		cpe := kanikoExecuteCommonPipelineEnvironment{}
+
+		err := runKanikoExecute(config, &telemetry.CustomData{}, &cpe, runner, certClient, fileUtils)
+
+		assert.NoError(t, err)
+		cwd, _ := os.Getwd()
+		assert.Equal(t, []string{"--dockerfile", "Dockerfile", "--context", cwd, "--build-arg", "ARTIFACT_VERSION=1.2.3", "--build-arg", "GIT_COMMIT=a1b2c3", "--destination", "my.registry.com:50000/myImage:1.2.3", "--digest-file", "/kaniko/image-0.digest"}, runner.Calls[1].Params)
+	})
+
 	t.Run("success case - backward compatibility", func(t *testing.T) {
 		config := &kanikoExecuteOptions{
 			ContainerBuildOptions:       "--skip-tls-verify-pull",
@@ -236,7 +261,75 @@ func TestRunKanikoExecute(t *testing.T) {
 
 		assert.NoError(t, err)
 		cwd, _ := os.Getwd()
-		assert.Equal(t, []string{"--dockerfile", "Dockerfile", "--context", cwd, "--skip-tls-verify-pull", "--destination", "myImage:tag"}, runner.Calls[1].Params)
+		assert.Equal(t, []string{"--dockerfile", "Dockerfile", "--context", cwd, "--skip-tls-verify-pull", "--destination", "myImage:tag", "--digest-file", "/kaniko/image-0.digest"}, runner.Calls[1].Params)
+	})
+
+	t.Run("success case - multiple images and tags", func(t *testing.T) {
+		config := &kanikoExecuteOptions{
+			AddCommitIDTag:       true,
+			AdditionalImages:     []map[string]interface{}{{"containerImageName": "myImage-worker", "contextPath": "worker"}, {"containerImageName": "myImage-ui", "contextPath": "ui", "dockerfilePath": "ui/Dockerfile.prod"}},
+			AdditionalTags:       []string{"latest", "1.2.3-a+x"},
+			AddPipelineBuildArgs: true,
+			CommitID:             "a1b2c3",
+			ContainerImageName:   "myImage",
+			ContainerImageTag:    "1.2.3-a+x",
+			ContainerRegistryURL: "https://my.registry.com:50000",
+			DockerfilePath:       "Dockerfile",
+		}
+
+		runner := &mock.ExecMockRunner{}
+
+		certClient := &kanikoMockClient{}
+		fileUtils := &kanikoFileMock{
+			fileReadContent: map[string]string{
+				"/kaniko/image-0.digest": "sha256:000\n",
+				"/kaniko/image-1.digest": "sha256:111",
+				"/kaniko/image-2.digest": "sha256:222",
+			},
+			fileWriteContent: map[string]string{},
+		}
+		cpe := kanikoExecuteCommonPipelineEnvironment{}
+
+		err := runKanikoExecute(config, &telemetry.CustomData{}, &cpe, runner, certClient, fileUtils)
+
+		assert.NoError(t, err)
+		cwd, _ := os.Getwd()
+		buildArgs := []string{"--cleanup", "--build-arg", "ARTIFACT_VERSION=1.2.3-a+x", "--build-arg", "GIT_COMMIT=a1b2c3"}
+		destinations := func(name, digestFile string) []string {
+			return []string{
+				"--destination", "my.registry.com:50000/" + name + ":1.2.3-a-x",
+				"--destination", "my.registry.com:50000/" + name + ":latest",
+				"--destination", "my.registry.com:50000/" + name + ":a1b2c3",
+				"--digest-file", digestFile,
+			}
+		}
+		if assert.Len(t, runner.Calls, 4) {
+			assert.Equal(t, append(append([]string{"--dockerfile", "Dockerfile", "--context", cwd}, buildArgs...), destinations("myImage", "/kaniko/image-0.digest")...), runner.Calls[1].Params)
+			assert.Equal(t, append(append([]string{"--dockerfile", "worker/Dockerfile", "--context", filepath.Join(cwd, "worker")}, buildArgs...), destinations("myImage-worker", "/kaniko/image-1.digest")...), runner.Calls[2].Params)
+			assert.Equal(t, append(append([]string{"--dockerfile", "ui/Dockerfile.prod", "--context", filepath.Join(cwd, "ui")}, buildArgs...), destinations("myImage-ui", "/kaniko/image-2.digest")...), runner.Calls[3].Params)
+		}
+		assert.Equal(t, "https://my.registry.com:50000", cpe.container.registryURL)
+		assert.Equal(t, "myImage:1.2.3-a-x", cpe.container.imageNameTag)
+		assert.Equal(t, []string{"myImage:1.2.3-a-x", "myImage-worker:1.2.3-a-x", "myImage-ui:1.2.3-a-x"}, cpe.container.imageNameTags)
+		assert.Equal(t, "sha256:000", cpe.container.imageDigest)
+		assert.Equal(t, []string{"sha256:000", "sha256:111", "sha256:222"}, cpe.container.imageDigests)
+	})
+
+	t.Run("error case - additional image without name", func(t *testing.T) {
+		config := &kanikoExecuteOptions{
+			AdditionalImages: []map[string]interface{}{{"contextPath": "worker"}},
+		}
+
+		runner := &mock.ExecMockRunner{}
+
+		certClient := &kanikoMockClient{}
+		fileUtils := &kanikoFileMock{
+			fileWriteContent: map[string]string{},
+		}
+
+		err := runKanikoExecute(config, &telemetry.CustomData{}, &commonPipelineEnvironment, runner, certClient, fileUtils)
+
+		assert.EqualError(t, err, "containerImageName missing for additional image 1")
 	})
 
 	t.Run("error case - Kaniko init failed", func(t *testing.T) {
@@ -6,13 +6,19 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"os"
+	"path/filepath"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/SAP/jenkins-library/pkg/command"
 	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
 )
 
 func kubernetesDeploy(config kubernetesDeployOptions, telemetryData *telemetry.CustomData) {
@@ -47,8 +53,8 @@ func kubernetesDeploy(config kubernetesDeployOptions, telemetryData *telemetry.C
 func runKubernetesDeploy(config kubernetesDeployOptions, command command.ExecRunner, stdout io.Writer) error {
 	if config.DeployTool == "helm" || config.DeployTool == "helm3" {
 		return runHelmDeploy(config, command, stdout)
-	} else if config.DeployTool == "kubectl" {
-		return runKubectlDeploy(config, command)
+	} else if config.DeployTool == "kubectl" || config.DeployTool == "kustomize" {
+		return runKubectlDeploy(config, command, stdout)
 	}
 	return fmt.Errorf("Failed to execute deployments")
 }
@@ -196,15 +202,31 @@ func runHelmDeploy(config kubernetesDeployOptions, command command.ExecRunner, s
 	if err := command.RunExecutable("helm", upgradeParams...); err != nil {
 		log.Entry().WithError(err).Fatal("Helm upgrade call failed")
 	}
+
+	if config.VerifyDeployment {
+		if err := verifyHelmDeployment(config, command, stdout); err != nil {
+			if config.RollbackOnFailure {
+				rollbackHelmDeployment(config, command)
+			}
+			log.SetErrorCategory(log.ErrorCustom)
+			return errors.Wrap(err, "deployment verification failed")
+		}
+	}
 	return nil
 }
 
-func runKubectlDeploy(config kubernetesDeployOptions, command command.ExecRunner) error {
+func runKubectlDeploy(config kubernetesDeployOptions, command command.ExecRunner, stdout io.Writer) error {
 	_, containerRegistry, err := splitRegistryURL(config.ContainerRegistryURL)
 	if err != nil {
 		log.Entry().WithError(err).Fatalf("Container registry url '%v' incorrect", config.ContainerRegistryURL)
 	}
 
+	// kubectl only prunes resources carrying the annotation written by client-side apply
+	if len(config.PruneSelector) > 0 && piperutils.ContainsString(config.AdditionalParameters, "--server-side") {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return fmt.Errorf("pruneSelector cannot be combined with server-side apply, since kubectl only prunes resources created via client-side apply")
+	}
+
 	kubeParams := []string{
 		"--insecure-skip-tls-verify=true",
 		fmt.Sprintf("--namespace=%v", config.Namespace),
@@ -250,14 +272,47 @@ func runKubectlDeploy(config kubernetesDeployOptions, command command.ExecRunner
 		}
 	}
 
+	var manifests []byte
+	if config.DeployTool == "kustomize" {
+		manifests, err = applyKustomization(config, command, stdout, kubeParams, containerRegistry)
+	} else if len(config.ManifestDirectory) > 0 {
+		manifests, err = applyManifestDirectory(config, command, kubeParams, containerRegistry)
+	} else {
+		manifests = applyAppTemplate(config, command, kubeParams, containerRegistry)
+	}
+	if err != nil {
+		return err
+	}
+
+	if config.VerifyDeployment {
+		workloads, err := deployedWorkloads(manifests)
+		if err != nil {
+			return err
+		}
+		if err := verifyDeployment(config, command, stdout, kubeParams, workloads); err != nil {
+			if config.RollbackOnFailure {
+				rollbackKubectlDeployment(command, kubeParams, workloads)
+			}
+			log.SetErrorCategory(log.ErrorCustom)
+			return errors.Wrap(err, "deployment verification failed")
+		}
+	}
+	return nil
+}
+
+// replaceImagePlaceholder updates the image name in the deployment yaml, expects placeholder like 'image: <image-name>'
+func replaceImagePlaceholder(manifest []byte, containerRegistry, image string) []byte {
+	re := regexp.MustCompile(`image:[ ]*<image-name>`)
+	return []byte(re.ReplaceAllString(string(manifest), fmt.Sprintf("image: %v/%v", containerRegistry, image)))
+}
+
+func applyAppTemplate(config kubernetesDeployOptions, command command.ExecRunner, kubeParams []string, containerRegistry string) []byte {
 	appTemplate, err := ioutil.ReadFile(config.AppTemplate)
 	if err != nil {
 		log.Entry().WithError(err).Fatalf("Error when reading appTemplate '%v'", config.AppTemplate)
 	}
 
-	// Update image name in deployment yaml, expects placeholder like 'image: <image-name>'
-	re := regexp.MustCompile(`image:[ ]*<image-name>`)
-	appTemplate = []byte(re.ReplaceAllString(string(appTemplate), fmt.Sprintf("image: %v/%v", containerRegistry, config.Image)))
+	appTemplate = replaceImagePlaceholder(appTemplate, containerRegistry, config.Image)
 
 	err = ioutil.WriteFile(config.AppTemplate, appTemplate, 0700)
 	if err != nil {
@@ -273,9 +328,392 @@ func runKubectlDeploy(config kubernetesDeployOptions, command command.ExecRunner
 		log.Entry().Debugf("Running kubectl with following parameters: %v", kubeApplyParams)
 		log.Entry().WithError(err).Fatal("Deployment with kubectl failed.")
 	}
+	return appTemplate
+}
+
+// applyKustomization sets the image in the kustomization, builds it and applies the result.
+// Server-side apply is used unless resources are pruned, since kubectl only prunes resources created via client-side apply.
+func applyKustomization(config kubernetesDeployOptions, command command.ExecRunner, stdout io.Writer, kubeParams []string, containerRegistry string) ([]byte, error) {
+	if len(config.KustomizationPath) == 0 {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return nil, fmt.Errorf("kustomization path has not been set, please configure kustomizationPath parameter")
+	}
+	containerImageName, containerImageTag, err := splitFullImageName(config.Image)
+	if err != nil {
+		log.Entry().WithError(err).Fatalf("Container image '%v' incorrect", config.Image)
+	}
+	// the name of the image as referenced in the manifests
+	name := config.ContainerName
+	if len(name) == 0 {
+		name = containerImageName
+	}
+	newImage := fmt.Sprintf("%v/%v", containerRegistry, containerImageName)
+	if len(containerImageTag) > 0 {
+		newImage += ":" + containerImageTag
+	}
+
+	var manifests bytes.Buffer
+	command.SetDir(config.KustomizationPath)
+	err = command.RunExecutable("kustomize", "edit", "set", "image", fmt.Sprintf("%v=%v", name, newImage))
+	if err == nil {
+		command.Stdout(&manifests)
+		err = command.RunExecutable("kustomize", "build", ".")
+		command.Stdout(stdout)
+	}
+	command.SetDir("")
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to build kustomization '%v'", config.KustomizationPath)
+	}
+
+	manifestDir, err := ioutil.TempDir("", "kustomization")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create directory for manifests")
+	}
+	defer os.RemoveAll(manifestDir)
+	manifestFile := filepath.Join(manifestDir, "manifests.yaml")
+	if err := ioutil.WriteFile(manifestFile, manifests.Bytes(), 0600); err != nil {
+		return nil, errors.Wrap(err, "failed to write manifests")
+	}
+
+	kubeApplyParams := append(append([]string{}, kubeParams...), "apply")
+	if len(config.PruneSelector) == 0 {
+		kubeApplyParams = append(kubeApplyParams, "--server-side")
+	}
+	kubeApplyParams = append(kubeApplyParams, "--filename", manifestFile)
+	kubeApplyParams = append(kubeApplyParams, pruneParams(config)...)
+	kubeApplyParams = append(kubeApplyParams, config.AdditionalParameters...)
+	log.Entry().Infof("Applying kustomization '%v' ...", config.KustomizationPath)
+	if err := command.RunExecutable("kubectl", kubeApplyParams...); err != nil {
+		log.Entry().Debugf("Running kubectl with following parameters: %v", kubeApplyParams)
+		return nil, errors.Wrap(err, "deployment with kubectl failed")
+	}
+	return manifests.Bytes(), nil
+}
+
+// applyManifestDirectory applies all manifests of the directory with namespaces and custom resource definitions first
+func applyManifestDirectory(config kubernetesDeployOptions, command command.ExecRunner, kubeParams []string, containerRegistry string) ([]byte, error) {
+	clusterManifests, allManifests, crds, err := orderedManifests(config.ManifestDirectory, containerRegistry, config.Image)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return nil, err
+	}
+
+	manifestDir, err := ioutil.TempDir("", "manifests")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create directory for manifests")
+	}
+	defer os.RemoveAll(manifestDir)
+
+	if len(clusterManifests) > 0 {
+		clusterFile := filepath.Join(manifestDir, "cluster.yaml")
+		if err := ioutil.WriteFile(clusterFile, clusterManifests, 0600); err != nil {
+			return nil, errors.Wrap(err, "failed to write manifests")
+		}
+		log.Entry().Info("Applying namespaces and custom resource definitions ...")
+		if err := command.RunExecutable("kubectl", append(append([]string{}, kubeParams...), "apply", "--filename", clusterFile)...); err != nil {
+			return nil, errors.Wrap(err, "deployment of namespaces and custom resource definitions failed")
+		}
+		for _, crd := range crds {
+			waitParams := append(append([]string{}, kubeParams...), "wait", "--for=condition=established", fmt.Sprintf("customresourcedefinition/%v", crd), fmt.Sprintf("--timeout=%vs", config.VerificationTimeoutSeconds))
+			if err := command.RunExecutable("kubectl", waitParams...); err != nil {
+				return nil, errors.Wrapf(err, "custom resource definition '%v' is not established", crd)
+			}
+		}
+	}
+
+	// all manifests are applied together in order to avoid pruning the namespaces and custom resource definitions
+	manifestFile := filepath.Join(manifestDir, "manifests.yaml")
+	if err := ioutil.WriteFile(manifestFile, allManifests, 0600); err != nil {
+		return nil, errors.Wrap(err, "failed to write manifests")
+	}
+	kubeApplyParams := append(append([]string{}, kubeParams...), "apply", "--filename", manifestFile)
+	kubeApplyParams = append(kubeApplyParams, pruneParams(config)...)
+	kubeApplyParams = append(kubeApplyParams, config.AdditionalParameters...)
+	log.Entry().Infof("Applying manifests of directory '%v' ...", config.ManifestDirectory)
+	if err := command.RunExecutable("kubectl", kubeApplyParams...); err != nil {
+		log.Entry().Debugf("Running kubectl with following parameters: %v", kubeApplyParams)
+		return nil, errors.Wrap(err, "deployment with kubectl failed")
+	}
+	return allManifests, nil
+}
+
+// pruneParams returns the kubectl parameters deleting the resources selected by the prune selector which are no longer part of the manifests.
+// Since the selector also filters the applied manifests, manifests without the labels are not applied at all.
+func pruneParams(config kubernetesDeployOptions) []string {
+	if len(config.PruneSelector) == 0 {
+		return []string{}
+	}
+	return []string{"--prune", fmt.Sprintf("--selector=%v", config.PruneSelector)}
+}
+
+// orderedManifests reads all manifests of the directory and returns the namespaces and custom resource definitions
+// as well as all manifests with namespaces and custom resource definitions first
+func orderedManifests(directory, containerRegistry, image string) (clusterManifests, allManifests []byte, crds []string, err error) {
+	files := []string{}
+	err = filepath.Walk(directory, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".yaml", ".yml", ".json":
+			if !info.IsDir() {
+				files = append(files, path)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, nil, errors.Wrapf(err, "failed to read manifest directory '%v'", directory)
+	}
+	if len(files) == 0 {
+		return nil, nil, nil, fmt.Errorf("no manifests found in directory '%v'", directory)
+	}
+
+	clusterDocuments := []string{}
+	otherDocuments := []string{}
+	documentSeparator := regexp.MustCompile(`(?m)^---\s*$`)
+	for _, file := range files {
+		content, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, nil, nil, errors.Wrapf(err, "failed to read manifest '%v'", file)
+		}
+		content = replaceImagePlaceholder(content, containerRegistry, image)
+		for _, document := range documentSeparator.Split(string(content), -1) {
+			var manifest struct {
+				Kind     string `yaml:"kind"`
+				Metadata struct {
+					Name string `yaml:"name"`
+				} `yaml:"metadata"`
+			}
+			if err := yaml.Unmarshal([]byte(document), &manifest); err != nil {
+				return nil, nil, nil, errors.Wrapf(err, "failed to parse manifest '%v'", file)
+			}
+			if len(manifest.Kind) == 0 {
+				continue
+			}
+			document = strings.TrimSpace(document) + "\n"
+			switch manifest.Kind {
+			case "Namespace", "CustomResourceDefinition":
+				clusterDocuments = append(clusterDocuments, document)
+				if manifest.Kind == "CustomResourceDefinition" {
+					crds = append(crds, manifest.Metadata.Name)
+				}
+			default:
+				otherDocuments = append(otherDocuments, document)
+			}
+		}
+	}
+
+	if len(clusterDocuments) > 0 {
+		clusterManifests = []byte(strings.Join(clusterDocuments, "---\n"))
+	}
+	allManifests = []byte(strings.Join(append(clusterDocuments, otherDocuments...), "---\n"))
+	return clusterManifests, allManifests, crds, nil
+}
+
+// deployedWorkload is a workload contained in the deployed manifests whose rollout can be verified
+type deployedWorkload struct {
+	Kind     string `yaml:"kind"`
+	Metadata struct {
+		Name string `yaml:"name"`
+	} `yaml:"metadata"`
+	Spec struct {
+		Selector struct {
+			MatchLabels map[string]string `yaml:"matchLabels"`
+		} `yaml:"selector"`
+	} `yaml:"spec"`
+}
+
+func (w deployedWorkload) resource() string {
+	return fmt.Sprintf("%v/%v", strings.ToLower(w.Kind), w.Metadata.Name)
+}
+
+func (w deployedWorkload) selector() string {
+	labels := []string{}
+	for key, value := range w.Spec.Selector.MatchLabels {
+		labels = append(labels, fmt.Sprintf("%v=%v", key, value))
+	}
+	sort.Strings(labels)
+	return strings.Join(labels, ",")
+}
+
+// deployedWorkloads returns the Deployments, StatefulSets and DaemonSets contained in the (multi-document) manifests
+func deployedWorkloads(manifests []byte) ([]deployedWorkload, error) {
+	workloads := []deployedWorkload{}
+	decoder := yaml.NewDecoder(bytes.NewReader(manifests))
+	for {
+		var workload deployedWorkload
+		err := decoder.Decode(&workload)
+		if err == io.EOF {
+			return workloads, nil
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse deployed manifests")
+		}
+		switch workload.Kind {
+		case "Deployment", "StatefulSet", "DaemonSet":
+			workloads = append(workloads, workload)
+		}
+	}
+}
+
+// podList contains the pod status information relevant for the deployment verification
+type podList struct {
+	Items []struct {
+		Metadata struct {
+			Name              string  `json:"name"`
+			DeletionTimestamp *string `json:"deletionTimestamp"`
+		} `json:"metadata"`
+		Status struct {
+			ContainerStatuses []struct {
+				Name         string `json:"name"`
+				Ready        bool   `json:"ready"`
+				RestartCount int    `json:"restartCount"`
+			} `json:"containerStatuses"`
+		} `json:"status"`
+	} `json:"items"`
+}
+
+func verifyHelmDeployment(config kubernetesDeployOptions, command command.ExecRunner, stdout io.Writer) error {
+	helmParams := []string{}
+	if config.DeployTool == "helm3" {
+		helmParams = append(helmParams, "--namespace", config.Namespace)
+	}
+	if len(config.KubeContext) > 0 {
+		helmParams = append(helmParams, "--kube-context", config.KubeContext)
+	}
+
+	var manifests bytes.Buffer
+	command.Stdout(&manifests)
+	err := command.RunExecutable("helm", append([]string{"get", "manifest", config.DeploymentName}, helmParams...)...)
+	command.Stdout(stdout)
+	if err != nil {
+		return errors.Wrapf(err, "failed to retrieve manifests of release '%v'", config.DeploymentName)
+	}
+	workloads, err := deployedWorkloads(manifests.Bytes())
+	if err != nil {
+		return err
+	}
+
+	kubeParams := []string{"--insecure-skip-tls-verify=true", fmt.Sprintf("--namespace=%v", config.Namespace)}
+	if len(config.KubeContext) > 0 {
+		kubeParams = append(kubeParams, fmt.Sprintf("--context=%v", config.KubeContext))
+	}
+	if err := verifyDeployment(config, command, stdout, kubeParams, workloads); err != nil {
+		return err
+	}
+
+	if config.RunHelmTests {
+		log.Entry().Infof("Running tests of release '%v' ...", config.DeploymentName)
+		if err := command.RunExecutable("helm", append([]string{"test", config.DeploymentName}, helmParams...)...); err != nil {
+			return errors.Wrapf(err, "tests of release '%v' failed", config.DeploymentName)
+		}
+	}
+	return nil
+}
+
+// verifyDeployment waits for the rollout of the workloads and checks the readiness and restarts of their pods
+func verifyDeployment(config kubernetesDeployOptions, command command.ExecRunner, stdout io.Writer, kubeParams []string, workloads []deployedWorkload) error {
+	for _, workload := range workloads {
+		log.Entry().Infof("Waiting for rollout of %v ...", workload.resource())
+		rolloutParams := append(append([]string{}, kubeParams...), "rollout", "status", workload.resource(), fmt.Sprintf("--timeout=%vs", config.VerificationTimeoutSeconds))
+		if err := command.RunExecutable("kubectl", rolloutParams...); err != nil {
+			reportUnhealthyPods(config, command, stdout, kubeParams, workload)
+			return errors.Wrapf(err, "rollout of %v did not complete", workload.resource())
+		}
+		if unhealthyPods := reportUnhealthyPods(config, command, stdout, kubeParams, workload); len(unhealthyPods) > 0 {
+			return fmt.Errorf("pods of %v are not healthy: %v", workload.resource(), strings.Join(unhealthyPods, ", "))
+		}
+	}
 	return nil
 }
 
+// reportUnhealthyPods returns the pods of the workload which are not ready or restarted too often and logs their events and logs
+func reportUnhealthyPods(config kubernetesDeployOptions, command command.ExecRunner, stdout io.Writer, kubeParams []string, workload deployedWorkload) []string {
+	selector := workload.selector()
+	if len(selector) == 0 {
+		log.Entry().Debugf("No label selector found for %v, skipping pod verification", workload.resource())
+		return nil
+	}
+
+	var podsJSON bytes.Buffer
+	command.Stdout(&podsJSON)
+	err := command.RunExecutable("kubectl", append(append([]string{}, kubeParams...), "get", "pods", fmt.Sprintf("--selector=%v", selector), "--output=json")...)
+	command.Stdout(stdout)
+	if err != nil {
+		log.Entry().WithError(err).Warningf("Failed to retrieve pods of %v", workload.resource())
+		return nil
+	}
+	var pods podList
+	if err := json.Unmarshal(podsJSON.Bytes(), &pods); err != nil {
+		log.Entry().WithError(err).Warningf("Failed to read pods of %v", workload.resource())
+		return nil
+	}
+
+	unhealthyPods := []string{}
+	for _, pod := range pods.Items {
+		if pod.Metadata.DeletionTimestamp != nil {
+			continue
+		}
+		problems := []string{}
+		restarted := false
+		for _, container := range pod.Status.ContainerStatuses {
+			if !container.Ready {
+				problems = append(problems, fmt.Sprintf("container '%v' is not ready", container.Name))
+			}
+			if container.RestartCount > config.MaxPodRestarts {
+				problems = append(problems, fmt.Sprintf("container '%v' restarted %v times", container.Name, container.RestartCount))
+			}
+			restarted = restarted || container.RestartCount > 0
+		}
+		if len(problems) == 0 {
+			continue
+		}
+		log.Entry().Errorf("Pod '%v' is not healthy: %v", pod.Metadata.Name, strings.Join(problems, ", "))
+		unhealthyPods = append(unhealthyPods, pod.Metadata.Name)
+
+		eventParams := append(append([]string{}, kubeParams...), "get", "events", fmt.Sprintf("--field-selector=involvedObject.name=%v", pod.Metadata.Name))
+		if err := command.RunExecutable("kubectl", eventParams...); err != nil {
+			log.Entry().WithError(err).Warningf("Failed to retrieve events of pod '%v'", pod.Metadata.Name)
+		}
+		logParams := append(append([]string{}, kubeParams...), "logs", pod.Metadata.Name, "--all-containers=true", "--tail=100")
+		if restarted {
+			// logs of the crashed container instance are more helpful than the ones of the current one
+			logParams = append(logParams, "--previous")
+		}
+		if err := command.RunExecutable("kubectl", logParams...); err != nil {
+			log.Entry().WithError(err).Warningf("Failed to retrieve logs of pod '%v'", pod.Metadata.Name)
+		}
+	}
+	return unhealthyPods
+}
+
+func rollbackHelmDeployment(config kubernetesDeployOptions, command command.ExecRunner) {
+	rollbackParams := []string{"rollback", config.DeploymentName}
+	if config.DeployTool == "helm" {
+		// revision 0 refers to the previous revision
+		rollbackParams = append(rollbackParams, "0", "--wait", "--timeout", strconv.Itoa(config.HelmDeployWaitSeconds))
+	} else {
+		rollbackParams = append(rollbackParams, "--namespace", config.Namespace, "--wait", "--timeout", fmt.Sprintf("%vs", config.HelmDeployWaitSeconds))
+	}
+	if len(config.KubeContext) > 0 {
+		rollbackParams = append(rollbackParams, "--kube-context", config.KubeContext)
+	}
+	log.Entry().Infof("Rolling back release '%v' ...", config.DeploymentName)
+	if err := command.RunExecutable("helm", rollbackParams...); err != nil {
+		log.Entry().WithError(err).Errorf("Rollback of release '%v' failed", config.DeploymentName)
+	}
+}
+
+func rollbackKubectlDeployment(command command.ExecRunner, kubeParams []string, workloads []deployedWorkload) {
+	for _, workload := range workloads {
+		log.Entry().Infof("Rolling back %v ...", workload.resource())
+		if err := command.RunExecutable("kubectl", append(append([]string{}, kubeParams...), "rollout", "undo", workload.resource())...); err != nil {
+			log.Entry().WithError(err).Errorf("Rollback of %v failed", workload.resource())
+		}
+	}
+}
+
 func splitRegistryURL(registryURL string) (protocol, registry string, err error) {
 	parts := strings.Split(registryURL, "://")
 	if len(parts) != 2 || len(parts[1]) == 0 {
@@ -18,6 +18,7 @@ type kubernetesDeployOptions struct {
 	APIServer                  string   `json:"apiServer,omitempty"`
 	AppTemplate                string   `json:"appTemplate,omitempty"`
 	ChartPath                  string   `json:"chartPath,omitempty"`
+	ContainerName              string   `json:"containerName,omitempty"`
 	ContainerRegistryPassword  string   `json:"containerRegistryPassword,omitempty"`
 	ContainerRegistryURL       string   `json:"containerRegistryUrl,omitempty"`
 	ContainerRegistryUser      string   `json:"containerRegistryUser,omitempty"`
@@ -34,8 +35,16 @@ type kubernetesDeployOptions struct {
 	KubeConfig                 string   `json:"kubeConfig,omitempty"`
 	KubeContext                string   `json:"kubeContext,omitempty"`
 	KubeToken                  string   `json:"kubeToken,omitempty"`
+	KustomizationPath          string   `json:"kustomizationPath,omitempty"`
+	ManifestDirectory          string   `json:"manifestDirectory,omitempty"`
+	MaxPodRestarts             int      `json:"maxPodRestarts,omitempty"`
 	Namespace                  string   `json:"namespace,omitempty"`
+	PruneSelector              string   `json:"pruneSelector,omitempty"`
+	RollbackOnFailure          bool     `json:"rollbackOnFailure,omitempty"`
+	RunHelmTests               bool     `json:"runHelmTests,omitempty"`
 	TillerNamespace            string   `json:"tillerNamespace,omitempty"`
+	VerificationTimeoutSeconds int      `json:"verificationTimeoutSeconds,omitempty"`
+	VerifyDeployment           bool     `json:"verifyDeployment,omitempty"`
 }
 
 // KubernetesDeployCommand Deployment to Kubernetes test or production namespace within the specified Kubernetes cluster.
@@ -56,6 +65,7 @@ func KubernetesDeployCommand() *cobra.Command {
 
     * [Helm](https://helm.sh/) command line tool and [Helm Charts](https://docs.helm.sh/developing_charts/#charts).
     * [kubectl](https://kubernetes.io/docs/reference/kubectl/overview/) and ` + "`" + `kubectl apply` + "`" + ` command.
+    * [Kustomize](https://kustomize.io/) overlays which are applied via ` + "`" + `kubectl apply` + "`" + `.
 
 ## Helm
 Following helm command will be executed by default:
@@ -66,7 +76,32 @@ helm upgrade <deploymentName> <chartPath> --install --force --namespace <namespa
 
 * ` + "`" + `yourRegistry` + "`" + ` will be retrieved from ` + "`" + `containerRegistryUrl` + "`" + `
 * ` + "`" + `yourImageName` + "`" + `, ` + "`" + `yourImageTag` + "`" + ` will be retrieved from ` + "`" + `image` + "`" + `
-* ` + "`" + `dockerSecret` + "`" + ` will be calculated with a call to ` + "`" + `kubectl create secret docker-registry regsecret --docker-server=<yourRegistry> --docker-username=<containerRegistryUser> --docker-password=<containerRegistryPassword> --dry-run=true --output=json'` + "`" + ``,
+* ` + "`" + `dockerSecret` + "`" + ` will be calculated with a call to ` + "`" + `kubectl create secret docker-registry regsecret --docker-server=<yourRegistry> --docker-username=<containerRegistryUser> --docker-password=<containerRegistryPassword> --dry-run=true --output=json'` + "`" + `
+
+## kubectl
+By default the file ` + "`" + `appTemplate` + "`" + ` is applied, after the placeholder ` + "`" + `image: <image-name>` + "`" + ` has been replaced with the image to be deployed.
+
+Alternatively all manifests (` + "`" + `*.yaml` + "`" + `, ` + "`" + `*.yml` + "`" + `, ` + "`" + `*.json` + "`" + `) of the directory ` + "`" + `manifestDirectory` + "`" + ` are applied.
+Namespaces and custom resource definitions are applied first, so that resources which depend on them can be created in the same deployment.
+
+## Kustomize
+The image of the kustomization in ` + "`" + `kustomizationPath` + "`" + ` (e.g. an overlay per environment) is set via ` + "`" + `kustomize edit set image` + "`" + `.
+Afterwards the kustomization is built and the result is applied via server-side apply.
+In case ` + "`" + `pruneSelector` + "`" + ` is set, client-side apply is used instead, since kubectl only prunes resources created via client-side apply.
+
+Using ` + "`" + `pruneSelector` + "`" + ` (kubectl and Kustomize), resources carrying the labels which are no longer part of the manifests are deleted.
+The selector is passed to ` + "`" + `kubectl apply` + "`" + ` and thus also filters the applied manifests:
+manifests not carrying the labels are skipped without notice, so make sure all manifests carry them.
+` + "`" + `pruneSelector` + "`" + ` cannot be combined with ` + "`" + `--server-side` + "`" + ` in ` + "`" + `additionalParameters` + "`" + `.
+
+## Deployment verification
+With ` + "`" + `verifyDeployment: true` + "`" + ` the step does not rely on the exit code of the deployment tool only. After the deployment it
+
+* waits for the rollout of all Deployments, StatefulSets and DaemonSets contained in the deployed manifests (` + "`" + `kubectl rollout status` + "`" + `),
+* checks that all pods of these workloads are ready and did not restart more often than ` + "`" + `maxPodRestarts` + "`" + `,
+* runs ` + "`" + `helm test` + "`" + ` for the release in case ` + "`" + `runHelmTests` + "`" + ` is active (Helm only).
+
+In case the verification fails, events and logs of the failing pods are written to the log and the deployment is rolled back via ` + "`" + `helm rollback` + "`" + ` or ` + "`" + `kubectl rollout undo` + "`" + ` respectively (see ` + "`" + `rollbackOnFailure` + "`" + `).`,
 		PreRunE: func(cmd *cobra.Command, _ []string) error {
 			startTime = time.Now()
 			log.SetStepName(STEP_NAME)
@@ -120,6 +155,7 @@ func addKubernetesDeployFlags(cmd *cobra.Command, stepConfig *kubernetesDeployOp
 	cmd.Flags().StringVar(&stepConfig.APIServer, "apiServer", os.Getenv("PIPER_apiServer"), "Defines the Url of the API Server of the Kubernetes cluster.")
 	cmd.Flags().StringVar(&stepConfig.AppTemplate, "appTemplate", os.Getenv("PIPER_appTemplate"), "Defines the filename for the kubernetes app template (e.g. k8s_apptemplate.yaml)")
 	cmd.Flags().StringVar(&stepConfig.ChartPath, "chartPath", os.Getenv("PIPER_chartPath"), "Defines the chart path for deployments using helm. It is a mandatory parameter when `deployTool:helm` or `deployTool:helm3`.")
+	cmd.Flags().StringVar(&stepConfig.ContainerName, "containerName", os.Getenv("PIPER_containerName"), "Kustomize only: name of the image as referenced in the manifests. It defaults to the image name of `image`.")
 	cmd.Flags().StringVar(&stepConfig.ContainerRegistryPassword, "containerRegistryPassword", os.Getenv("PIPER_containerRegistryPassword"), "Password for container registry access - typically provided by the CI/CD environment.")
 	cmd.Flags().StringVar(&stepConfig.ContainerRegistryURL, "containerRegistryUrl", os.Getenv("PIPER_containerRegistryUrl"), "http(s) url of the Container registry where the image to deploy is located.")
 	cmd.Flags().StringVar(&stepConfig.ContainerRegistryUser, "containerRegistryUser", os.Getenv("PIPER_containerRegistryUser"), "Username for container registry access - typically provided by the CI/CD environment.")
@@ -136,8 +172,16 @@ func addKubernetesDeployFlags(cmd *cobra.Command, stepConfig *kubernetesDeployOp
 	cmd.Flags().StringVar(&stepConfig.KubeConfig, "kubeConfig", os.Getenv("PIPER_kubeConfig"), "Defines the path to the \"kubeconfig\" file.")
 	cmd.Flags().StringVar(&stepConfig.KubeContext, "kubeContext", os.Getenv("PIPER_kubeContext"), "Defines the context to use from the \"kubeconfig\" file.")
 	cmd.Flags().StringVar(&stepConfig.KubeToken, "kubeToken", os.Getenv("PIPER_kubeToken"), "Contains the id_token used by kubectl for authentication. Consider using kubeConfig parameter instead.")
+	cmd.Flags().StringVar(&stepConfig.KustomizationPath, "kustomizationPath", os.Getenv("PIPER_kustomizationPath"), "Kustomize only: directory containing the kustomization which is deployed, e.g. an overlay.")
+	cmd.Flags().StringVar(&stepConfig.ManifestDirectory, "manifestDirectory", os.Getenv("PIPER_manifestDirectory"), "kubectl only: directory containing the manifests to be deployed. If set, it is used instead of `appTemplate`.")
+	cmd.Flags().IntVar(&stepConfig.MaxPodRestarts, "maxPodRestarts", 0, "Maximum number of container restarts which is accepted for the pods of the deployment during the verification (see `verifyDeployment`).")
 	cmd.Flags().StringVar(&stepConfig.Namespace, "namespace", `default`, "Defines the target Kubernetes namespace for the deployment.")
+	cmd.Flags().StringVar(&stepConfig.PruneSelector, "pruneSelector", os.Getenv("PIPER_pruneSelector"), "kubectl and Kustomize only: label selector (e.g. `app.kubernetes.io/part-of=myApp`) of resources which are deleted in case they are no longer part of the deployed manifests. Manifests not matching the selector are not applied.")
+	cmd.Flags().BoolVar(&stepConfig.RollbackOnFailure, "rollbackOnFailure", true, "Defines whether the deployment is rolled back in case the verification fails (see `verifyDeployment`).")
+	cmd.Flags().BoolVar(&stepConfig.RunHelmTests, "runHelmTests", false, "Helm only: run the tests of the chart via `helm test` as part of the verification (see `verifyDeployment`).")
 	cmd.Flags().StringVar(&stepConfig.TillerNamespace, "tillerNamespace", os.Getenv("PIPER_tillerNamespace"), "Defines optional tiller namespace for deployments using helm.")
+	cmd.Flags().IntVar(&stepConfig.VerificationTimeoutSeconds, "verificationTimeoutSeconds", 300, "Number of seconds to wait for the rollout of each workload during the verification (see `verifyDeployment`).")
+	cmd.Flags().BoolVar(&stepConfig.VerifyDeployment, "verifyDeployment", false, "Defines whether the deployment is verified after it has been applied. This covers the rollout status of the deployed workloads, the readiness and restart counts of their pods as well as optionally `helm test`.")
 
 	cmd.MarkFlagRequired("containerRegistryUrl")
 	cmd.MarkFlagRequired("deployTool")
@@ -190,6 +234,14 @@ func kubernetesDeployMetadata() config.StepData {
 						Mandatory:   false,
 						Aliases:     []config.Alias{{Name: "helmChartPath"}},
 					},
+					{
+						Name:        "containerName",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
 					{
 						Name: "containerRegistryPassword",
 						ResourceRef: []config.ResourceReference{
@@ -356,6 +408,30 @@ func kubernetesDeployMetadata() config.StepData {
 						Mandatory: false,
 						Aliases:   []config.Alias{},
 					},
+					{
+						Name:        "kustomizationPath",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "manifestDirectory",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "maxPodRestarts",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
 					{
 						Name:        "namespace",
 						ResourceRef: []config.ResourceReference{},
@@ -364,6 +440,30 @@ func kubernetesDeployMetadata() config.StepData {
 						Mandatory:   false,
 						Aliases:     []config.Alias{{Name: "helmDeploymentNamespace"}, {Name: "k8sDeploymentNamespace"}},
 					},
+					{
+						Name:        "pruneSelector",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "rollbackOnFailure",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "runHelmTests",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
 					{
 						Name:        "tillerNamespace",
 						ResourceRef: []config.ResourceReference{},
@@ -372,12 +472,29 @@ func kubernetesDeployMetadata() config.StepData {
 						Mandatory:   false,
 						Aliases:     []config.Alias{{Name: "helmTillerNamespace"}},
 					},
+					{
+						Name:        "verificationTimeoutSeconds",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "verifyDeployment",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
 				},
 			},
 			Containers: []config.Container{
 				{Image: "dtzar/helm-kubectl:3.1.2", WorkingDir: "/config", Options: []config.Option{{Name: "-u", Value: "0"}}, Conditions: []config.Condition{{ConditionRef: "strings-equal", Params: []config.Param{{Name: "deployTool", Value: "helm3"}}}}},
 				{Image: "dtzar/helm-kubectl:2.12.1", WorkingDir: "/config", Options: []config.Option{{Name: "-u", Value: "0"}}, Conditions: []config.Condition{{ConditionRef: "strings-equal", Params: []config.Param{{Name: "deployTool", Value: "helm"}}}}},
 				{Image: "dtzar/helm-kubectl:2.12.1", WorkingDir: "/config", Options: []config.Option{{Name: "-u", Value: "0"}}, Conditions: []config.Condition{{ConditionRef: "strings-equal", Params: []config.Param{{Name: "deployTool", Value: "kubectl"}}}}},
+				{Image: "dtzar/helm-kubectl:3.4.1", WorkingDir: "/config", Options: []config.Option{{Name: "-u", Value: "0"}}, Conditions: []config.Condition{{ConditionRef: "strings-equal", Params: []config.Param{{Name: "deployTool", Value: "kustomize"}}}}},
 			},
 		},
 	}
@@ -537,6 +537,331 @@ spec:
 	})
 }
 
+const verificationManifests = `---
+apiVersion: v1
+kind: Service
+metadata:
+  name: app
+spec:
+  selector:
+    app: app
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  selector:
+    matchLabels:
+      app: app
+      release: deploymentName
+`
+
+func TestKubernetesDeployVerification(t *testing.T) {
+	helmOpts := kubernetesDeployOptions{
+		ContainerRegistryURL:       "https://my.registry:55555",
+		ChartPath:                  "path/to/chart",
+		DeploymentName:             "deploymentName",
+		DeployTool:                 "helm3",
+		HelmDeployWaitSeconds:      400,
+		Image:                      "path/to/Image:latest",
+		Namespace:                  "deploymentNamespace",
+		RollbackOnFailure:          true,
+		VerificationTimeoutSeconds: 120,
+		VerifyDeployment:           true,
+	}
+
+	t.Run("test helm v3 - verification succeeds", func(t *testing.T) {
+		opts := helmOpts
+		opts.RunHelmTests = true
+		e := mock.ExecMockRunner{
+			StdoutReturn: map[string]string{
+				"helm get manifest deploymentName --namespace deploymentNamespace": verificationManifests,
+				"kubectl .* get pods .*": `{"items": [
+					{"metadata": {"name": "app-1"}, "status": {"containerStatuses": [{"name": "app", "ready": true, "restartCount": 0}]}},
+					{"metadata": {"name": "app-0", "deletionTimestamp": "2020-10-19T12:00:00Z"}, "status": {"containerStatuses": [{"name": "app", "ready": false}]}}]}`,
+			},
+		}
+		var stdout bytes.Buffer
+
+		err := runKubernetesDeploy(opts, &e, &stdout)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 5, len(e.Calls))
+		assert.Equal(t, []string{"--insecure-skip-tls-verify=true", "--namespace=deploymentNamespace", "rollout", "status", "deployment/app", "--timeout=120s"}, e.Calls[2].Params)
+		assert.Equal(t, []string{"--insecure-skip-tls-verify=true", "--namespace=deploymentNamespace", "get", "pods", "--selector=app=app,release=deploymentName", "--output=json"}, e.Calls[3].Params)
+		assert.Equal(t, "helm", e.Calls[4].Exec)
+		assert.Equal(t, []string{"test", "deploymentName", "--namespace", "deploymentNamespace"}, e.Calls[4].Params)
+	})
+
+	t.Run("test helm v3 - crash looping pods are rolled back", func(t *testing.T) {
+		e := mock.ExecMockRunner{
+			StdoutReturn: map[string]string{
+				"helm get manifest deploymentName --namespace deploymentNamespace": verificationManifests,
+				"kubectl .* get pods .*": `{"items": [{"metadata": {"name": "app-1"}, "status": {"containerStatuses": [{"name": "app", "ready": false, "restartCount": 3}]}}]}`,
+			},
+		}
+		var stdout bytes.Buffer
+
+		err := runKubernetesDeploy(helmOpts, &e, &stdout)
+
+		assert.EqualError(t, err, "deployment verification failed: pods of deployment/app are not healthy: app-1")
+		assert.Equal(t, 7, len(e.Calls))
+		assert.Equal(t, []string{"--insecure-skip-tls-verify=true", "--namespace=deploymentNamespace", "get", "events", "--field-selector=involvedObject.name=app-1"}, e.Calls[4].Params)
+		assert.Equal(t, []string{"--insecure-skip-tls-verify=true", "--namespace=deploymentNamespace", "logs", "app-1", "--all-containers=true", "--tail=100", "--previous"}, e.Calls[5].Params)
+		assert.Equal(t, "helm", e.Calls[6].Exec)
+		assert.Equal(t, []string{"rollback", "deploymentName", "--namespace", "deploymentNamespace", "--wait", "--timeout", "400s"}, e.Calls[6].Params)
+	})
+
+	t.Run("test helm v3 - failing helm tests without rollback", func(t *testing.T) {
+		opts := helmOpts
+		opts.RunHelmTests = true
+		opts.RollbackOnFailure = false
+		e := mock.ExecMockRunner{
+			ShouldFailOnCommand: map[string]error{"helm test .*": fmt.Errorf("test failed")},
+		}
+		var stdout bytes.Buffer
+
+		err := runKubernetesDeploy(opts, &e, &stdout)
+
+		assert.EqualError(t, err, "deployment verification failed: tests of release 'deploymentName' failed: test failed")
+		assert.Equal(t, "test", e.Calls[len(e.Calls)-1].Params[0])
+	})
+
+	t.Run("test kubectl - rollout fails", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "")
+		defer os.RemoveAll(dir) // clean up
+		assert.NoError(t, err, "Error when creating temp dir")
+
+		opts := kubernetesDeployOptions{
+			AppTemplate:                filepath.Join(dir, "test.yaml"),
+			ContainerRegistryURL:       "https://my.registry:55555",
+			DeployTool:                 "kubectl",
+			Image:                      "path/to/Image:latest",
+			KubeConfig:                 "This is my kubeconfig",
+			Namespace:                  "deploymentNamespace",
+			RollbackOnFailure:          true,
+			VerificationTimeoutSeconds: 120,
+			VerifyDeployment:           true,
+		}
+		ioutil.WriteFile(opts.AppTemplate, []byte(verificationManifests), 0755)
+
+		e := mock.ExecMockRunner{
+			ShouldFailOnCommand: map[string]error{"kubectl .* rollout status .*": fmt.Errorf("timed out")},
+			StdoutReturn:        map[string]string{"kubectl .* get pods .*": `{"items": []}`},
+		}
+		var stdout bytes.Buffer
+
+		err = runKubernetesDeploy(opts, &e, &stdout)
+
+		assert.EqualError(t, err, "deployment verification failed: rollout of deployment/app did not complete: timed out")
+		assert.Equal(t, []string{"--insecure-skip-tls-verify=true", "--namespace=deploymentNamespace", "rollout", "undo", "deployment/app"}, e.Calls[len(e.Calls)-1].Params)
+	})
+}
+
+func TestDeployedWorkloads(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		workloads, err := deployedWorkloads([]byte(verificationManifests + "---\nkind: StatefulSet\nmetadata:\n  name: db\n"))
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, 2, len(workloads))
+			assert.Equal(t, "deployment/app", workloads[0].resource())
+			assert.Equal(t, "app=app,release=deploymentName", workloads[0].selector())
+			assert.Equal(t, "statefulset/db", workloads[1].resource())
+			assert.Equal(t, "", workloads[1].selector())
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		_, err := deployedWorkloads([]byte("kind: [Deployment"))
+
+		assert.Contains(t, fmt.Sprint(err), "failed to parse deployed manifests")
+	})
+}
+
+func TestKubernetesDeployKustomizeAndManifestDirectory(t *testing.T) {
+	t.Run("test kustomize", func(t *testing.T) {
+		opts := kubernetesDeployOptions{
+			ContainerRegistryURL:       "https://my.registry:55555",
+			DeployTool:                 "kustomize",
+			Image:                      "path/to/Image:latest",
+			KubeConfig:                 "This is my kubeconfig",
+			KustomizationPath:          "overlays/dev",
+			Namespace:                  "deploymentNamespace",
+			PruneSelector:              "app.kubernetes.io/part-of=app",
+			VerificationTimeoutSeconds: 60,
+			VerifyDeployment:           true,
+		}
+		e := mock.ExecMockRunner{
+			StdoutReturn: map[string]string{"kustomize build .": verificationManifests},
+		}
+		var stdout bytes.Buffer
+
+		err := runKubernetesDeploy(opts, &e, &stdout)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, []string{"overlays/dev", ""}, e.Dir)
+			assert.Equal(t, mock.ExecCall{Exec: "kustomize", Params: []string{"edit", "set", "image", "path/to/Image=my.registry:55555/path/to/Image:latest"}}, e.Calls[0])
+			assert.Equal(t, mock.ExecCall{Exec: "kustomize", Params: []string{"build", "."}}, e.Calls[1])
+			assert.Equal(t, "kubectl", e.Calls[2].Exec)
+			// pruning requires client-side apply, since kubectl only prunes resources carrying the last-applied annotation
+			assert.Equal(t, []string{"--insecure-skip-tls-verify=true", "--namespace=deploymentNamespace", "apply", "--filename"}, e.Calls[2].Params[:4])
+			assert.Equal(t, []string{"--prune", "--selector=app.kubernetes.io/part-of=app"}, e.Calls[2].Params[5:])
+			// verification of the built manifests
+			assert.Equal(t, []string{"--insecure-skip-tls-verify=true", "--namespace=deploymentNamespace", "rollout", "status", "deployment/app", "--timeout=60s"}, e.Calls[3].Params)
+		}
+	})
+
+	t.Run("test kustomize - server-side apply without prune selector", func(t *testing.T) {
+		opts := kubernetesDeployOptions{
+			ContainerRegistryURL: "https://my.registry:55555",
+			DeployTool:           "kustomize",
+			Image:                "path/to/Image:latest",
+			KubeConfig:           "This is my kubeconfig",
+			KustomizationPath:    "overlays/dev",
+			Namespace:            "deploymentNamespace",
+		}
+		e := mock.ExecMockRunner{}
+		var stdout bytes.Buffer
+
+		err := runKubernetesDeploy(opts, &e, &stdout)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, []string{"--insecure-skip-tls-verify=true", "--namespace=deploymentNamespace", "apply", "--server-side", "--filename"}, e.Calls[2].Params[:5])
+			assert.Equal(t, 6, len(e.Calls[2].Params))
+		}
+	})
+
+	t.Run("test kustomize - prune selector with server-side apply fails", func(t *testing.T) {
+		opts := kubernetesDeployOptions{
+			AdditionalParameters: []string{"--server-side"},
+			ContainerRegistryURL: "https://my.registry:55555",
+			DeployTool:           "kustomize",
+			Image:                "path/to/Image:latest",
+			KubeConfig:           "This is my kubeconfig",
+			KustomizationPath:    "overlays/dev",
+			Namespace:            "deploymentNamespace",
+			PruneSelector:        "app.kubernetes.io/part-of=app",
+		}
+		e := mock.ExecMockRunner{}
+		var stdout bytes.Buffer
+
+		err := runKubernetesDeploy(opts, &e, &stdout)
+
+		assert.EqualError(t, err, "pruneSelector cannot be combined with server-side apply, since kubectl only prunes resources created via client-side apply")
+		assert.Equal(t, 0, len(e.Calls))
+	})
+
+	t.Run("test kustomize - custom container name", func(t *testing.T) {
+		opts := kubernetesDeployOptions{
+			ContainerName:        "app",
+			ContainerRegistryURL: "https://my.registry:55555",
+			DeployTool:           "kustomize",
+			Image:                "path/to/Image:latest",
+			KustomizationPath:    "overlays/dev",
+		}
+		e := mock.ExecMockRunner{}
+		var stdout bytes.Buffer
+
+		err := runKubernetesDeploy(opts, &e, &stdout)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, []string{"edit", "set", "image", "app=my.registry:55555/path/to/Image:latest"}, e.Calls[0].Params)
+		}
+	})
+
+	t.Run("test kustomize - fails without kustomization path", func(t *testing.T) {
+		opts := kubernetesDeployOptions{
+			ContainerRegistryURL: "https://my.registry:55555",
+			DeployTool:           "kustomize",
+			Image:                "path/to/Image:latest",
+		}
+		var stdout bytes.Buffer
+
+		err := runKubernetesDeploy(opts, &mock.ExecMockRunner{}, &stdout)
+
+		assert.EqualError(t, err, "kustomization path has not been set, please configure kustomizationPath parameter")
+	})
+
+	t.Run("test kustomize - build fails", func(t *testing.T) {
+		opts := kubernetesDeployOptions{
+			ContainerRegistryURL: "https://my.registry:55555",
+			DeployTool:           "kustomize",
+			Image:                "path/to/Image:latest",
+			KustomizationPath:    "overlays/dev",
+		}
+		e := mock.ExecMockRunner{
+			ShouldFailOnCommand: map[string]error{"kustomize build .": fmt.Errorf("missing resource")},
+		}
+		var stdout bytes.Buffer
+
+		err := runKubernetesDeploy(opts, &e, &stdout)
+
+		assert.EqualError(t, err, "failed to build kustomization 'overlays/dev': missing resource")
+		assert.Equal(t, []string{"overlays/dev", ""}, e.Dir)
+	})
+
+	t.Run("test kubectl - manifest directory", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "")
+		defer os.RemoveAll(dir) // clean up
+		assert.NoError(t, err, "Error when creating temp dir")
+		ioutil.WriteFile(filepath.Join(dir, "app.yaml"), []byte(verificationManifests), 0755)
+		ioutil.WriteFile(filepath.Join(dir, "crd.yaml"), []byte("apiVersion: apiextensions.k8s.io/v1\nkind: CustomResourceDefinition\nmetadata:\n  name: apps.example.org\n"), 0755)
+
+		opts := kubernetesDeployOptions{
+			ContainerRegistryURL:       "https://my.registry:55555",
+			DeployTool:                 "kubectl",
+			Image:                      "path/to/Image:latest",
+			KubeConfig:                 "This is my kubeconfig",
+			ManifestDirectory:          dir,
+			Namespace:                  "deploymentNamespace",
+			VerificationTimeoutSeconds: 60,
+		}
+		e := mock.ExecMockRunner{}
+		var stdout bytes.Buffer
+
+		err = runKubernetesDeploy(opts, &e, &stdout)
+
+		if assert.NoError(t, err) && assert.Equal(t, 3, len(e.Calls)) {
+			assert.Equal(t, []string{"--insecure-skip-tls-verify=true", "--namespace=deploymentNamespace", "apply", "--filename"}, e.Calls[0].Params[:4])
+			assert.Equal(t, []string{"--insecure-skip-tls-verify=true", "--namespace=deploymentNamespace", "wait", "--for=condition=established", "customresourcedefinition/apps.example.org", "--timeout=60s"}, e.Calls[1].Params)
+			assert.Equal(t, []string{"--insecure-skip-tls-verify=true", "--namespace=deploymentNamespace", "apply", "--filename"}, e.Calls[2].Params[:4])
+			assert.NotEqual(t, e.Calls[0].Params[4], e.Calls[2].Params[4])
+		}
+	})
+}
+
+func TestOrderedManifests(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "")
+		defer os.RemoveAll(dir) // clean up
+		assert.NoError(t, err, "Error when creating temp dir")
+		os.Mkdir(filepath.Join(dir, "base"), 0755)
+		ioutil.WriteFile(filepath.Join(dir, "base", "deployment.yaml"), []byte("kind: Deployment\nmetadata:\n  name: app\nspec:\n  template:\n    spec:\n      containers:\n      - image: <image-name>\n"), 0755)
+		ioutil.WriteFile(filepath.Join(dir, "namespace.json"), []byte(`{"kind": "Namespace", "metadata": {"name": "ns"}}`), 0755)
+		ioutil.WriteFile(filepath.Join(dir, "resources.yml"), []byte("---\nkind: CustomResourceDefinition\nmetadata:\n  name: apps.example.org\n---\nkind: App\nmetadata:\n  name: app\n---\n"), 0755)
+		ioutil.WriteFile(filepath.Join(dir, "README.md"), []byte("kind: Deployment"), 0755)
+
+		clusterManifests, allManifests, crds, err := orderedManifests(dir, "my.registry:55555", "path/to/Image:latest")
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, []string{"apps.example.org"}, crds)
+			assert.Equal(t, "{\"kind\": \"Namespace\", \"metadata\": {\"name\": \"ns\"}}\n---\nkind: CustomResourceDefinition\nmetadata:\n  name: apps.example.org\n", string(clusterManifests))
+			assert.Equal(t, string(clusterManifests)+"---\nkind: Deployment\nmetadata:\n  name: app\nspec:\n  template:\n    spec:\n      containers:\n      - image: my.registry:55555/path/to/Image:latest\n---\nkind: App\nmetadata:\n  name: app\n", string(allManifests))
+		}
+	})
+
+	t.Run("error - no manifests", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "")
+		defer os.RemoveAll(dir) // clean up
+		assert.NoError(t, err, "Error when creating temp dir")
+
+		_, _, _, err = orderedManifests(dir, "my.registry:55555", "path/to/Image:latest")
+
+		assert.EqualError(t, err, fmt.Sprintf("no manifests found in directory '%v'", dir))
+	})
+}
+
 func TestSplitRegistryURL(t *testing.T) {
 	tt := []struct {
 		in          string
@@ -21,6 +21,7 @@ func GetAllStepMetadata() map[string]config.StepData {
 		"abapEnvironmentCreateSystem":             abapEnvironmentCreateSystemMetadata(),
 		"abapEnvironmentPullGitRepo":              abapEnvironmentPullGitRepoMetadata(),
 		"abapEnvironmentRunATCCheck":              abapEnvironmentRunATCCheckMetadata(),
+		"changelogCreate":                         changelogCreateMetadata(),
 		"checkChangeInDevelopment":                checkChangeInDevelopmentMetadata(),
 		"checkmarxExecuteScan":                    checkmarxExecuteScanMetadata(),
 		"cloudFoundryCreateService":               cloudFoundryCreateServiceMetadata(),
@@ -30,6 +31,8 @@ func GetAllStepMetadata() map[string]config.StepData {
 		"cloudFoundryDeleteSpace":                 cloudFoundryDeleteSpaceMetadata(),
 		"cloudFoundryDeploy":                      cloudFoundryDeployMetadata(),
 		"containerExecuteStructureTests":          containerExecuteStructureTestsMetadata(),
+		"containerScanImage":                      containerScanImageMetadata(),
+		"containerSignImage":                      containerSignImageMetadata(),
 		"detectExecuteScan":                       detectExecuteScanMetadata(),
 		"fortifyExecuteScan":                      fortifyExecuteScanMetadata(),
 		"gctsCloneRepository":                     gctsCloneRepositoryMetadata(),
@@ -41,10 +44,12 @@ func GetAllStepMetadata() map[string]config.StepData {
 		"githubCommentIssue":                      githubCommentIssueMetadata(),
 		"githubCreateIssue":                       githubCreateIssueMetadata(),
 		"githubCreatePullRequest":                 githubCreatePullRequestMetadata(),
+		"githubDecoratePullRequest":               githubDecoratePullRequestMetadata(),
 		"githubPublishRelease":                    githubPublishReleaseMetadata(),
 		"githubSetCommitStatus":                   githubSetCommitStatusMetadata(),
 		"gitopsUpdateDeployment":                  gitopsUpdateDeploymentMetadata(),
 		"hadolintExecute":                         hadolintExecuteMetadata(),
+		"helmExecute":                             helmExecuteMetadata(),
 		"integrationArtifactDeploy":               integrationArtifactDeployMetadata(),
 		"integrationArtifactDownload":             integrationArtifactDownloadMetadata(),
 		"integrationArtifactGetMplStatus":         integrationArtifactGetMplStatusMetadata(),
@@ -61,14 +66,27 @@ func GetAllStepMetadata() map[string]config.StepData {
 		"mavenExecuteIntegration":                 mavenExecuteIntegrationMetadata(),
 		"mavenExecuteStaticCodeChecks":            mavenExecuteStaticCodeChecksMetadata(),
 		"mtaBuild":                                mtaBuildMetadata(),
+		"neoDeploy":                               neoDeployMetadata(),
 		"newmanExecute":                           newmanExecuteMetadata(),
 		"nexusUpload":                             nexusUploadMetadata(),
+		"notificationSend":                        notificationSendMetadata(),
 		"npmExecuteLint":                          npmExecuteLintMetadata(),
 		"npmExecuteScripts":                       npmExecuteScriptsMetadata(),
 		"pipelineCreateScanSummary":               pipelineCreateScanSummaryMetadata(),
 		"protecodeExecuteScan":                    protecodeExecuteScanMetadata(),
 		"containerSaveImage":                      containerSaveImageMetadata(),
+		"scmCheckBranchProtection":                scmCheckBranchProtectionMetadata(),
+		"scmCommentIssue":                         scmCommentIssueMetadata(),
+		"scmCreateIssue":                          scmCreateIssueMetadata(),
+		"scmCreatePullRequest":                    scmCreatePullRequestMetadata(),
+		"scmPublishRelease":                       scmPublishReleaseMetadata(),
+		"scmSetCommitStatus":                      scmSetCommitStatusMetadata(),
 		"sonarExecuteScan":                        sonarExecuteScanMetadata(),
+		"spinnakerTriggerPipeline":                spinnakerTriggerPipelineMetadata(),
+		"transportRequestCreateCTS":               transportRequestCreateCTSMetadata(),
+		"transportRequestCreateSOLMAN":            transportRequestCreateSOLMANMetadata(),
+		"transportRequestReleaseCTS":              transportRequestReleaseCTSMetadata(),
+		"transportRequestReleaseSOLMAN":           transportRequestReleaseSOLMANMetadata(),
 		"transportRequestUploadCTS":               transportRequestUploadCTSMetadata(),
 		"transportRequestUploadSOLMAN":            transportRequestUploadSOLMANMetadata(),
 		"uiVeri5ExecuteTests":                     uiVeri5ExecuteTestsMetadata(),
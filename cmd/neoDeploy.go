@@ -0,0 +1,372 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/command"
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/maven"
+	"github.com/SAP/jenkins-library/pkg/neo"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+const (
+	neoCredentialTypeUsernamePassword = "UsernamePassword"
+	neoCredentialTypeSecretFile       = "SecretFile"
+)
+
+type neoDeployUtils interface {
+	command.ExecRunner
+	piperhttp.Uploader
+
+	FileExists(filename string) (bool, error)
+	FileRead(path string) ([]byte, error)
+	MkdirAll(path string, perm os.FileMode) error
+	Glob(pattern string) (matches []string, err error)
+	Abs(path string) (string, error)
+}
+
+type neoDeployUtilsBundle struct {
+	*command.Command
+	*piperutils.Files
+	*piperhttp.Client
+}
+
+func newNeoDeployUtils() neoDeployUtils {
+	utils := neoDeployUtilsBundle{
+		Command: &command.Command{},
+		Files:   &piperutils.Files{},
+		Client:  &piperhttp.Client{},
+	}
+	// Reroute command output to logging framework
+	utils.Stdout(log.Writer())
+	utils.Stderr(log.Writer())
+	return &utils
+}
+
+func neoDeploy(config neoDeployOptions, telemetryData *telemetry.CustomData, commonPipelineEnvironment *neoDeployCommonPipelineEnvironment) {
+	utils := newNeoDeployUtils()
+
+	err := runNeoDeploy(&config, telemetryData, utils, commonPipelineEnvironment)
+	if err != nil {
+		log.Entry().WithError(err).Fatal("step execution failed")
+	}
+}
+
+func runNeoDeploy(config *neoDeployOptions, telemetryData *telemetry.CustomData, utils neoDeployUtils, commonPipelineEnvironment *neoDeployCommonPipelineEnvironment) error {
+	mode := neo.DeployMode(config.DeployMode)
+	if mode != neo.DeployModeMta && !mode.IsWarDeployment() {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return fmt.Errorf("deploy mode '%s' is not supported, use one of '%s', '%s', '%s'", config.DeployMode,
+			neo.DeployModeMta, neo.DeployModeWarParams, neo.DeployModeWarPropertiesFile)
+	}
+
+	if err := validateNeoDeployConfig(config, mode); err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return err
+	}
+
+	source, err := getNeoDeploySource(config, mode, utils)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return err
+	}
+
+	for _, file := range append([]string{source}, config.Extensions...) {
+		exists, err := utils.FileExists(file)
+		if err != nil {
+			return errors.Wrapf(err, "failed to check for file '%s'", file)
+		}
+		if !exists {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return fmt.Errorf("file '%s' does not exist", file)
+		}
+	}
+
+	if config.CredentialType == neoCredentialTypeSecretFile {
+		if err := deployNeoMtaWithOAuth(config, source, utils); err != nil {
+			return err
+		}
+		return invalidateNeoCache(config, mode, utils)
+	}
+
+	target := neo.Target{
+		Host:           config.Host,
+		Account:        config.Account,
+		Application:    config.Application,
+		Runtime:        config.Runtime,
+		RuntimeVersion: config.RuntimeVersion,
+		Size:           config.Size,
+		Environment:    config.Environment,
+		VMArguments:    config.VmArguments,
+		PropertiesFile: config.PropertiesFile,
+	}
+	linkTarget := target
+	if mode == neo.DeployModeWarPropertiesFile {
+		content, err := utils.FileRead(config.PropertiesFile)
+		if err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return errors.Wrapf(err, "failed to read properties file '%s'", config.PropertiesFile)
+		}
+		linkTarget, err = neo.ReadTargetFromProperties(content)
+		if err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return errors.Wrapf(err, "error in neo deployment configuration '%s'", config.PropertiesFile)
+		}
+	}
+
+	builder := &neo.CommandBuilder{
+		Mode:        mode,
+		Target:      target,
+		Credentials: neo.Credentials{User: config.Username, Password: config.Password},
+		Source:      source,
+		Extensions:  config.Extensions,
+	}
+
+	logFolder := filepath.Join("logs", "neo", uuid.New().String())
+	if err := utils.MkdirAll(logFolder, 0755); err != nil {
+		return errors.Wrapf(err, "failed to create log folder '%s'", logFolder)
+	}
+	absLogFolder, err := utils.Abs(logFolder)
+	if err != nil {
+		return errors.Wrapf(err, "failed to resolve log folder '%s'", logFolder)
+	}
+	utils.AppendEnv([]string{"neo_logging_location=" + absLogFolder})
+
+	log.Entry().Infof("Link to the cloud cockpit: %s", neo.CloudCockpitLink(mode, linkTarget))
+
+	var status neo.Status
+	if mode == neo.DeployModeMta {
+		status, err = deployNeoMta(builder, utils)
+	} else {
+		status, err = deployNeoWar(builder, neo.WarAction(config.WarAction), utils)
+	}
+	if err != nil {
+		log.SetErrorCategory(log.ErrorService)
+		printNeoLogs(logFolder, utils)
+		return err
+	}
+
+	if len(status.URLs) > 0 {
+		log.Entry().Infof("Application URL: %s", status.URLs[0])
+		commonPipelineEnvironment.custom.neoApplicationURL = status.URLs[0]
+	}
+	return invalidateNeoCache(config, mode, utils)
+}
+
+func validateNeoDeployConfig(config *neoDeployOptions, mode neo.DeployMode) error {
+	if config.CredentialType != neoCredentialTypeUsernamePassword && config.CredentialType != neoCredentialTypeSecretFile {
+		return fmt.Errorf("credential type '%s' is not supported, use one of '%s', '%s'", config.CredentialType, neoCredentialTypeUsernamePassword, neoCredentialTypeSecretFile)
+	}
+
+	if config.CredentialType == neoCredentialTypeSecretFile && mode != neo.DeployModeMta {
+		return fmt.Errorf("credential type '%s' is only supported for deploy mode '%s'", neoCredentialTypeSecretFile, neo.DeployModeMta)
+	}
+
+	if strings.HasPrefix(config.Password, "@") {
+		return fmt.Errorf("your password for the deployment to SAP BTP contains characters which are not supported by the neo tools. " +
+			"For example it is not allowed that the password starts with @. " +
+			"Please consult the documentation for the neo command line tool for more information: " +
+			"https://help.sap.com/viewer/65de2977205c403bbc107264b8eccf4b/Cloud/en-US/8900b22376f84c609ee9baf5bf67130a.html")
+	}
+
+	if mode != neo.DeployModeMta && len(config.Extensions) > 0 {
+		return fmt.Errorf("extensions (%v) found for deploy mode '%s'. Extensions are only supported for deploy mode '%s'", config.Extensions, mode, neo.DeployModeMta)
+	}
+
+	if mode.IsWarDeployment() && config.WarAction != string(neo.WarActionDeploy) && config.WarAction != string(neo.WarActionRollingUpdate) {
+		return fmt.Errorf("war action '%s' is not supported, use one of '%s', '%s'", config.WarAction, neo.WarActionDeploy, neo.WarActionRollingUpdate)
+	}
+
+	mandatory := map[string]string{}
+	switch mode {
+	case neo.DeployModeMta:
+		mandatory = map[string]string{"host": config.Host, "account": config.Account}
+	case neo.DeployModeWarParams:
+		mandatory = map[string]string{"host": config.Host, "account": config.Account, "application": config.Application,
+			"runtime": config.Runtime, "runtimeVersion": config.RuntimeVersion}
+	case neo.DeployModeWarPropertiesFile:
+		mandatory = map[string]string{"propertiesFile": config.PropertiesFile}
+	}
+	if config.CredentialType == neoCredentialTypeSecretFile {
+		mandatory["oauthCredentialsFile"] = config.OauthCredentialsFile
+	} else {
+		mandatory["username"] = config.Username
+		mandatory["password"] = config.Password
+	}
+	if config.InvalidateCache && mode == neo.DeployModeMta {
+		mandatory["oauthClientId"] = config.OauthClientID
+		mandatory["oauthClientSecret"] = config.OauthClientSecret
+	}
+	missing := []string{}
+	for _, name := range []string{"username", "password", "oauthCredentialsFile", "host", "account", "application", "runtime", "runtimeVersion", "propertiesFile", "oauthClientId", "oauthClientSecret"} {
+		if value, ok := mandatory[name]; ok && len(value) == 0 {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("the following parameters are mandatory for deploy mode '%s' but not available: %v", mode, missing)
+	}
+	return nil
+}
+
+func getNeoDeploySource(config *neoDeployOptions, mode neo.DeployMode, utils neoDeployUtils) (string, error) {
+	if len(config.Source) > 0 {
+		return config.Source, nil
+	}
+	if mode == neo.DeployModeMta {
+		// the archive of a previous build step is only considered for MTA deployments
+		if len(config.MtarFilePath) > 0 {
+			return config.MtarFilePath, nil
+		}
+		return "", fmt.Errorf("no source provided for deploy mode '%s'", mode)
+	}
+
+	pomFile := filepath.Join(config.MavenDeploymentModule, "pom.xml")
+	content, err := utils.FileRead(pomFile)
+	if err != nil {
+		return "", errors.Wrapf(err, "the configured mavenDeploymentModule (%s) does not contain a pom file", config.MavenDeploymentModule)
+	}
+	project, err := maven.ParsePOM(content)
+	if err != nil {
+		return "", err
+	}
+	packaging := project.Packaging
+	if len(packaging) == 0 {
+		packaging = "jar"
+	}
+	return filepath.Join(config.MavenDeploymentModule, "target", fmt.Sprintf("%s.%s", project.ArtifactID, packaging)), nil
+}
+
+func deployNeoMta(builder *neo.CommandBuilder, utils neoDeployUtils) (neo.Status, error) {
+	output, err := runNeo(utils, builder.DeployMtaArgs())
+	if err != nil {
+		return neo.Status{}, errors.Wrap(err, "the execution of the deploy command failed, see the log for details")
+	}
+	return neo.ParseOutput(output), nil
+}
+
+func deployNeoWar(builder *neo.CommandBuilder, action neo.WarAction, utils neoDeployUtils) (neo.Status, error) {
+	statusArgs, err := builder.StatusArgs()
+	if err != nil {
+		return neo.Status{}, err
+	}
+
+	if action == neo.WarActionRollingUpdate {
+		// the status call fails in case the application does not exist, which is fine here
+		output, _ := runNeo(utils, statusArgs)
+		if neo.ParseOutput(output).State != neo.StatusStarted {
+			log.Entry().Info("Rolling update not possible because application is not running. Falling back to standard deployment.")
+			action = neo.WarActionDeploy
+		}
+	}
+
+	if action == neo.WarActionRollingUpdate {
+		if _, err := runNeo(utils, builder.RollingUpdateArgs()); err != nil {
+			return neo.Status{}, errors.Wrap(err, "the execution of the rolling update command failed, see the log for details")
+		}
+	} else {
+		if _, err := runNeo(utils, builder.DeployArgs()); err != nil {
+			return neo.Status{}, errors.Wrap(err, "the execution of the deploy command failed, see the log for details")
+		}
+		if _, err := runNeo(utils, builder.RestartArgs()); err != nil {
+			return neo.Status{}, errors.Wrap(err, "the execution of the restart command failed, see the log for details")
+		}
+	}
+
+	output, err := runNeo(utils, statusArgs)
+	if err != nil {
+		return neo.Status{}, errors.Wrap(err, "failed to retrieve the application status")
+	}
+	status := neo.ParseOutput(output)
+	log.Entry().Infof("Application status: %s", status.State)
+	if status.State != neo.StatusStarted {
+		return status, fmt.Errorf("application is not running after deployment, status: '%s'", status.State)
+	}
+	return status, nil
+}
+
+func deployNeoMtaWithOAuth(config *neoDeployOptions, source string, utils neoDeployUtils) error {
+	content, err := utils.FileRead(config.OauthCredentialsFile)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return errors.Wrapf(err, "failed to read OAuth credentials file '%s'", config.OauthCredentialsFile)
+	}
+	credentials, err := neo.ReadOAuthCredentials(content)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return err
+	}
+	archive, err := utils.FileRead(source)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read MTA archive '%s'", source)
+	}
+
+	deployer := neo.MtaDeployer{Client: utils, Host: config.Host, Account: config.Account, PollInterval: 10 * time.Second}
+	if err := deployer.Deploy(credentials, source, bytes.NewReader(archive)); err != nil {
+		log.SetErrorCategory(log.ErrorService)
+		return err
+	}
+	return nil
+}
+
+func invalidateNeoCache(config *neoDeployOptions, mode neo.DeployMode, utils neoDeployUtils) error {
+	if !config.InvalidateCache {
+		return nil
+	}
+	if mode != neo.DeployModeMta {
+		log.Entry().Info("Invalidation of cache is ignored. It is performed only for html5 applications.")
+		return nil
+	}
+
+	log.Entry().Info("Triggering invalidation of cache for html5 applications")
+	portal := neo.Portal{Client: utils, Host: config.Host, Account: config.Account, Landscape: config.PortalLandscape}
+	if err := portal.InvalidateCache(config.OauthClientID, config.OauthClientSecret, config.SiteID); err != nil {
+		log.SetErrorCategory(log.ErrorService)
+		return err
+	}
+	return nil
+}
+
+// runNeo executes neo.sh, the output is forwarded to the original stdout and returned for further processing
+func runNeo(utils neoDeployUtils, args []string) (string, error) {
+	oldStdout := utils.GetStdout()
+	defer utils.Stdout(oldStdout)
+
+	var output bytes.Buffer
+	if oldStdout != nil {
+		utils.Stdout(io.MultiWriter(&output, oldStdout))
+	} else {
+		utils.Stdout(&output)
+	}
+
+	err := utils.RunExecutable("neo.sh", args...)
+	return output.String(), err
+}
+
+func printNeoLogs(logFolder string, utils neoDeployUtils) {
+	logFiles, err := utils.Glob(filepath.Join(logFolder, "*"))
+	if err != nil || len(logFiles) == 0 {
+		log.Entry().Warn("Unable to provide the neo.sh logs.")
+		return
+	}
+	log.Entry().Info("Error while deploying to SAP BTP. Here are the neo.sh logs:")
+	for _, logFile := range logFiles {
+		content, err := utils.FileRead(logFile)
+		if err != nil {
+			log.Entry().WithError(err).Warnf("Unable to read log file '%s'", logFile)
+			continue
+		}
+		log.Entry().Infof("%s:\n%s", logFile, string(content))
+	}
+}
@@ -0,0 +1,443 @@
+// Code generated by piper's step-generator. DO NOT EDIT.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperenv"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/spf13/cobra"
+)
+
+type neoDeployOptions struct {
+	CredentialType        string                 `json:"credentialType,omitempty"`
+	Username              string                 `json:"username,omitempty"`
+	Password              string                 `json:"password,omitempty"`
+	OauthCredentialsFile  string                 `json:"oauthCredentialsFile,omitempty"`
+	DeployMode            string                 `json:"deployMode,omitempty"`
+	WarAction             string                 `json:"warAction,omitempty"`
+	Source                string                 `json:"source,omitempty"`
+	MtarFilePath          string                 `json:"mtarFilePath,omitempty"`
+	MavenDeploymentModule string                 `json:"mavenDeploymentModule,omitempty"`
+	Extensions            []string               `json:"extensions,omitempty"`
+	Account               string                 `json:"account,omitempty"`
+	Host                  string                 `json:"host,omitempty"`
+	Application           string                 `json:"application,omitempty"`
+	Runtime               string                 `json:"runtime,omitempty"`
+	RuntimeVersion        string                 `json:"runtimeVersion,omitempty"`
+	Size                  string                 `json:"size,omitempty"`
+	Environment           map[string]interface{} `json:"environment,omitempty"`
+	VmArguments           string                 `json:"vmArguments,omitempty"`
+	PropertiesFile        string                 `json:"propertiesFile,omitempty"`
+	InvalidateCache       bool                   `json:"invalidateCache,omitempty"`
+	PortalLandscape       string                 `json:"portalLandscape,omitempty"`
+	SiteID                string                 `json:"siteId,omitempty"`
+	OauthClientID         string                 `json:"oauthClientId,omitempty"`
+	OauthClientSecret     string                 `json:"oauthClientSecret,omitempty"`
+}
+
+type neoDeployCommonPipelineEnvironment struct {
+	custom struct {
+		neoApplicationURL string
+	}
+}
+
+func (p *neoDeployCommonPipelineEnvironment) persist(path, resourceName string) {
+	content := []struct {
+		category string
+		name     string
+		value    interface{}
+	}{
+		{category: "custom", name: "neoApplicationUrl", value: p.custom.neoApplicationURL},
+	}
+
+	errCount := 0
+	for _, param := range content {
+		err := piperenv.SetResourceParameter(path, resourceName, filepath.Join(param.category, param.name), param.value)
+		if err != nil {
+			log.Entry().WithError(err).Error("Error persisting piper environment.")
+			errCount++
+		}
+	}
+	if errCount > 0 {
+		log.Entry().Fatal("failed to persist Piper environment")
+	}
+}
+
+// NeoDeployCommand Deploys an Application to SAP BTP Neo environment.
+func NeoDeployCommand() *cobra.Command {
+	const STEP_NAME = "neoDeploy"
+
+	metadata := neoDeployMetadata()
+	var stepConfig neoDeployOptions
+	var startTime time.Time
+	var commonPipelineEnvironment neoDeployCommonPipelineEnvironment
+
+	var createNeoDeployCmd = &cobra.Command{
+		Use:   STEP_NAME,
+		Short: "Deploys an Application to SAP BTP Neo environment.",
+		Long: `Deploys an Application to SAP BTP Neo environment using the SAP BTP Console Client (Neo Java Web SDK).
+
+Three deploy modes are supported:
+
+* ` + "`" + `mta` + "`" + ` - deploying an MTA archive, optionally with MTA extension descriptors,
+* ` + "`" + `warParams` + "`" + ` - deploying a WAR file, passing all the deployment parameters via the step configuration,
+* ` + "`" + `warPropertiesFile` + "`" + ` - deploying a WAR file, putting all the deployment parameters in a .properties file.
+
+For WAR files the application is either deployed and restarted (` + "`" + `warAction: deploy` + "`" + `) or updated without downtime (` + "`" + `warAction: rolling-update` + "`" + `).
+In case the application is not running a rolling update is not possible and a standard deployment is performed instead.
+
+With ` + "`" + `credentialType: SecretFile` + "`" + ` MTA archives are deployed with OAuth client credentials instead of user and password.`,
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			startTime = time.Now()
+			log.SetStepName(STEP_NAME)
+			log.SetVerbose(GeneralConfig.Verbose)
+
+			path, _ := os.Getwd()
+			fatalHook := &log.FatalHook{CorrelationID: GeneralConfig.CorrelationID, Path: path}
+			log.RegisterHook(fatalHook)
+
+			err := PrepareConfig(cmd, &metadata, STEP_NAME, &stepConfig, config.OpenPiperFile)
+			if err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+			log.RegisterSecret(stepConfig.Username)
+			log.RegisterSecret(stepConfig.Password)
+			log.RegisterSecret(stepConfig.OauthCredentialsFile)
+			log.RegisterSecret(stepConfig.OauthClientID)
+			log.RegisterSecret(stepConfig.OauthClientSecret)
+
+			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
+				sentryHook := log.NewSentryHook(GeneralConfig.HookConfig.SentryConfig.Dsn, GeneralConfig.CorrelationID)
+				log.RegisterHook(&sentryHook)
+			}
+
+			return nil
+		},
+		Run: func(_ *cobra.Command, _ []string) {
+			telemetryData := telemetry.CustomData{}
+			telemetryData.ErrorCode = "1"
+			handler := func() {
+				config.RemoveVaultSecretFiles()
+				commonPipelineEnvironment.persist(GeneralConfig.EnvRootPath, "commonPipelineEnvironment")
+				telemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
+				telemetryData.ErrorCategory = log.GetErrorCategory().String()
+				telemetry.Send(&telemetryData)
+			}
+			log.DeferExitHandler(handler)
+			defer handler()
+			telemetry.Initialize(GeneralConfig.NoTelemetry, STEP_NAME)
+			neoDeploy(stepConfig, &telemetryData, &commonPipelineEnvironment)
+			telemetryData.ErrorCode = "0"
+			log.Entry().Info("SUCCESS")
+		},
+	}
+
+	addNeoDeployFlags(createNeoDeployCmd, &stepConfig)
+	return createNeoDeployCmd
+}
+
+func addNeoDeployFlags(cmd *cobra.Command, stepConfig *neoDeployOptions) {
+	cmd.Flags().StringVar(&stepConfig.CredentialType, "credentialType", `UsernamePassword`, "Type of the credentials used for the deployment to SAP BTP. `SecretFile` is only supported for deploy mode `mta`.")
+	cmd.Flags().StringVar(&stepConfig.Username, "username", os.Getenv("PIPER_username"), "User for the deployment to SAP BTP. Mandatory for credential type `UsernamePassword`.")
+	cmd.Flags().StringVar(&stepConfig.Password, "password", os.Getenv("PIPER_password"), "Password for the deployment to SAP BTP. Mandatory for credential type `UsernamePassword`.")
+	cmd.Flags().StringVar(&stepConfig.OauthCredentialsFile, "oauthCredentialsFile", os.Getenv("PIPER_oauthCredentialsFile"), "Path to the JSON file containing `oauthClientId`, `oauthClientSecret` and `oauthServiceUrl` used for the deployment to SAP BTP. Mandatory for credential type `SecretFile`.")
+	cmd.Flags().StringVar(&stepConfig.DeployMode, "deployMode", `mta`, "The deployment mode which should be used.")
+	cmd.Flags().StringVar(&stepConfig.WarAction, "warAction", `deploy`, "Action mode when using WAR file mode. `rolling-update` performs an update of the application without downtime in one go.")
+	cmd.Flags().StringVar(&stepConfig.Source, "source", os.Getenv("PIPER_source"), "The path to the archive for deployment. If not provided, the `mtarFilePath` is used for deploy mode `mta` and the file `<mavenDeploymentModule>/target/<artifactId>.<packaging>` for the WAR deploy modes.")
+	cmd.Flags().StringVar(&stepConfig.MtarFilePath, "mtarFilePath", os.Getenv("PIPER_mtarFilePath"), "The path to the MTA archive created by a previous build step, e.g. `mtaBuild`. Only used for deploy mode `mta` if `source` is not provided.")
+	cmd.Flags().StringVar(&stepConfig.MavenDeploymentModule, "mavenDeploymentModule", `.`, "Path to the maven module which contains the deployment artifact.")
+	cmd.Flags().StringSliceVar(&stepConfig.Extensions, "extensions", []string{}, "Extension files. Provided to the neo command via parameter `--extensions` (`-e`). Only valid for deploy mode `mta`.")
+	cmd.Flags().StringVar(&stepConfig.Account, "account", os.Getenv("PIPER_account"), "The SAP BTP account to deploy to. Mandatory for the deploy modes `mta` and `warParams`.")
+	cmd.Flags().StringVar(&stepConfig.Host, "host", os.Getenv("PIPER_host"), "The SAP BTP host to deploy to. Mandatory for the deploy modes `mta` and `warParams`.")
+	cmd.Flags().StringVar(&stepConfig.Application, "application", os.Getenv("PIPER_application"), "Name of the application you want to manage, configure, or deploy. Mandatory for deploy mode `warParams`.")
+	cmd.Flags().StringVar(&stepConfig.Runtime, "runtime", os.Getenv("PIPER_runtime"), "Name of SAP BTP application runtime. Mandatory for deploy mode `warParams`.")
+	cmd.Flags().StringVar(&stepConfig.RuntimeVersion, "runtimeVersion", os.Getenv("PIPER_runtimeVersion"), "Version of SAP BTP application runtime. Mandatory for deploy mode `warParams`.")
+	cmd.Flags().StringVar(&stepConfig.Size, "size", `lite`, "Compute unit (VM) size. Acceptable values: lite, pro, prem, prem-plus.")
+
+	cmd.Flags().StringVar(&stepConfig.VmArguments, "vmArguments", os.Getenv("PIPER_vmArguments"), "String of VM arguments passed to the JVM.")
+	cmd.Flags().StringVar(&stepConfig.PropertiesFile, "propertiesFile", os.Getenv("PIPER_propertiesFile"), "The path to the .properties file in which all necessary deployment properties for the application are defined. Mandatory for deploy mode `warPropertiesFile`.")
+	cmd.Flags().BoolVar(&stepConfig.InvalidateCache, "invalidateCache", false, "Invalidates the cache of the SAP Fiori launchpad site after the deployment. Only performed for deploy mode `mta`, i.e. for HTML5 applications.")
+	cmd.Flags().StringVar(&stepConfig.PortalLandscape, "portalLandscape", `cloudnwcportal`, "Portal landscape region subscribed to in SAP BTP.")
+	cmd.Flags().StringVar(&stepConfig.SiteID, "siteId", os.Getenv("PIPER_siteId"), "Site ID of the SAP Fiori launchpad containing the SAP Fiori app. If not set, the cache of the default site, as defined in the portal service, is invalidated.")
+	cmd.Flags().StringVar(&stepConfig.OauthClientID, "oauthClientId", os.Getenv("PIPER_oauthClientId"), "OAuth client ID used for invalidating the cache of the SAP Fiori launchpad site. Mandatory for `invalidateCache`.")
+	cmd.Flags().StringVar(&stepConfig.OauthClientSecret, "oauthClientSecret", os.Getenv("PIPER_oauthClientSecret"), "OAuth client secret used for invalidating the cache of the SAP Fiori launchpad site. Mandatory for `invalidateCache`.")
+
+}
+
+// retrieve step metadata
+func neoDeployMetadata() config.StepData {
+	var theMetaData = config.StepData{
+		Metadata: config.StepMetadata{
+			Name:        "neoDeploy",
+			Aliases:     []config.Alias{},
+			Description: "Deploys an Application to SAP BTP Neo environment.",
+		},
+		Spec: config.StepSpec{
+			Inputs: config.StepInputs{
+				Parameters: []config.StepParameters{
+					{
+						Name:        "credentialType",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{{Name: "neo/credentialType"}},
+					},
+					{
+						Name: "username",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "neoCredentialsId",
+								Param: "username",
+								Type:  "secret",
+							},
+
+							{
+								Name:  "",
+								Paths: []string{"$(vaultPath)/neo", "$(vaultBasePath)/$(vaultPipelineName)/neo", "$(vaultBasePath)/GROUP-SECRETS/neo"},
+								Type:  "vaultSecret",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+					},
+					{
+						Name: "password",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "neoCredentialsId",
+								Param: "password",
+								Type:  "secret",
+							},
+
+							{
+								Name:  "",
+								Paths: []string{"$(vaultPath)/neo", "$(vaultBasePath)/$(vaultPipelineName)/neo", "$(vaultBasePath)/GROUP-SECRETS/neo"},
+								Type:  "vaultSecret",
+							},
+						},
+						Scope:     []string{"PARAMETERS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+					},
+					{
+						Name: "oauthCredentialsFile",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "neoCredentialsId",
+								Type: "secret",
+							},
+						},
+						Scope:     []string{"PARAMETERS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+					},
+					{
+						Name:        "deployMode",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "warAction",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "source",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name: "mtarFilePath",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "mtarFilePath",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+					},
+					{
+						Name:        "mavenDeploymentModule",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "extensions",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "account",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{{Name: "neo/account"}},
+					},
+					{
+						Name:        "host",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{{Name: "neo/host"}},
+					},
+					{
+						Name:        "application",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{{Name: "neo/application"}},
+					},
+					{
+						Name:        "runtime",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{{Name: "neo/runtime"}},
+					},
+					{
+						Name:        "runtimeVersion",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{{Name: "neo/runtimeVersion"}},
+					},
+					{
+						Name:        "size",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{{Name: "neo/size"}},
+					},
+					{
+						Name:        "environment",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "map[string]interface{}",
+						Mandatory:   false,
+						Aliases:     []config.Alias{{Name: "neo/environment"}},
+					},
+					{
+						Name:        "vmArguments",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{{Name: "neo/vmArguments"}},
+					},
+					{
+						Name:        "propertiesFile",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{{Name: "neo/propertiesFile"}},
+					},
+					{
+						Name:        "invalidateCache",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{{Name: "neo/invalidateCache"}},
+					},
+					{
+						Name:        "portalLandscape",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{{Name: "neo/portalLandscape"}},
+					},
+					{
+						Name:        "siteId",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{{Name: "neo/siteId"}},
+					},
+					{
+						Name: "oauthClientId",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "oauthCredentialId",
+								Param: "username",
+								Type:  "secret",
+							},
+						},
+						Scope:     []string{"PARAMETERS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+					},
+					{
+						Name: "oauthClientSecret",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "oauthCredentialId",
+								Param: "password",
+								Type:  "secret",
+							},
+						},
+						Scope:     []string{"PARAMETERS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+					},
+				},
+			},
+			Containers: []config.Container{
+				{Name: "neo", Image: "ppiper/neo-cli"},
+			},
+			Outputs: config.StepOutputs{
+				Resources: []config.StepResources{
+					{
+						Name: "commonPipelineEnvironment",
+						Type: "piperEnvironment",
+						Parameters: []map[string]interface{}{
+							{"Name": "custom/neoApplicationUrl"},
+						},
+					},
+				},
+			},
+		},
+	}
+	return theMetaData
+}
@@ -0,0 +1,17 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNeoDeployCommand(t *testing.T) {
+	t.Parallel()
+
+	testCmd := NeoDeployCommand()
+
+	// only high level testing performed - details are tested in step generation procedure
+	assert.Equal(t, "neoDeploy", testCmd.Use, "command name incorrect")
+
+}
@@ -0,0 +1,347 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+type neoDeployMockHTTPClient struct {
+	requests  []string
+	responses map[string]string
+}
+
+func (c *neoDeployMockHTTPClient) SendRequest(method, url string, body io.Reader, header http.Header, cookies []*http.Cookie) (*http.Response, error) {
+	c.requests = append(c.requests, method+" "+url)
+	response, ok := c.responses[method+" "+url]
+	if !ok {
+		return &http.Response{StatusCode: http.StatusNotFound, Body: ioutil.NopCloser(strings.NewReader(""))}, fmt.Errorf("request to %s returned with response 404 Not Found", url)
+	}
+	return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(response))}, nil
+}
+
+func (c *neoDeployMockHTTPClient) SetOptions(options piperhttp.ClientOptions) {}
+
+func (c *neoDeployMockHTTPClient) Upload(data piperhttp.UploadRequestData) (*http.Response, error) {
+	return c.SendRequest(data.Method, data.URL, data.FileContent, data.Header, data.Cookies)
+}
+
+func (c *neoDeployMockHTTPClient) UploadRequest(method, url, file, fieldName string, header http.Header, cookies []*http.Cookie) (*http.Response, error) {
+	return c.SendRequest(method, url, nil, header, cookies)
+}
+
+func (c *neoDeployMockHTTPClient) UploadFile(url, file, fieldName string, header http.Header, cookies []*http.Cookie) (*http.Response, error) {
+	return c.UploadRequest(http.MethodPost, url, file, fieldName, header, cookies)
+}
+
+type neoDeployMockUtils struct {
+	*mock.ExecMockRunner
+	*mock.FilesMock
+	*neoDeployMockHTTPClient
+}
+
+func newNeoDeployTestsUtils() neoDeployMockUtils {
+	utils := neoDeployMockUtils{
+		ExecMockRunner:          &mock.ExecMockRunner{},
+		FilesMock:               &mock.FilesMock{},
+		neoDeployMockHTTPClient: &neoDeployMockHTTPClient{responses: map[string]string{}},
+	}
+	return utils
+}
+
+const neoStatusStarted = `Status: STARTED
+
+URL: https://myappmyaccount.hana.example.org
+`
+
+func TestRunNeoDeploy(t *testing.T) {
+	t.Parallel()
+
+	warConfig := neoDeployOptions{
+		CredentialType:        "UsernamePassword",
+		Username:              "me",
+		Password:              "secret",
+		DeployMode:            "warParams",
+		WarAction:             "deploy",
+		MavenDeploymentModule: ".",
+		Host:                  "hana.example.org",
+		Account:               "myAccount",
+		Application:           "myApp",
+		Runtime:               "neo-javaee7-wp",
+		RuntimeVersion:        "2",
+		Size:                  "lite",
+	}
+
+	t.Run("mta", func(t *testing.T) {
+		t.Parallel()
+		config := neoDeployOptions{CredentialType: "UsernamePassword", Username: "me", Password: "secret", DeployMode: "mta", Source: "app.mtar", Host: "hana.example.org", Account: "myAccount"}
+		utils := newNeoDeployTestsUtils()
+		utils.AddFile("app.mtar", []byte("dummy"))
+		cpe := neoDeployCommonPipelineEnvironment{}
+
+		err := runNeoDeploy(&config, nil, utils, &cpe)
+
+		if assert.NoError(t, err) {
+			assert.Len(t, utils.Calls, 1)
+			assert.Equal(t, "neo.sh", utils.Calls[0].Exec)
+			assert.Equal(t, []string{"deploy-mta", "--synchronous", "--host", "hana.example.org", "--account", "myAccount",
+				"--user", "me", "--password", "secret", "--source", "app.mtar"}, utils.Calls[0].Params)
+			assert.Len(t, utils.Env, 1)
+			assert.Contains(t, utils.Env[0], "neo_logging_location=")
+		}
+	})
+
+	t.Run("war deploy with source from pom", func(t *testing.T) {
+		t.Parallel()
+		config := warConfig
+		utils := newNeoDeployTestsUtils()
+		utils.AddFile("pom.xml", []byte("<project><artifactId>myApp</artifactId><packaging>war</packaging></project>"))
+		utils.AddFile("target/myApp.war", []byte("dummy"))
+		utils.StdoutReturn = map[string]string{"neo.sh status.*": neoStatusStarted}
+		cpe := neoDeployCommonPipelineEnvironment{}
+
+		err := runNeoDeploy(&config, nil, utils, &cpe)
+
+		if assert.NoError(t, err) {
+			assert.Len(t, utils.Calls, 3)
+			assert.Equal(t, "deploy", utils.Calls[0].Params[0])
+			assert.Contains(t, utils.Calls[0].Params, "target/myApp.war")
+			assert.Equal(t, "restart", utils.Calls[1].Params[0])
+			assert.Equal(t, "status", utils.Calls[2].Params[0])
+			assert.Equal(t, "https://myappmyaccount.hana.example.org", cpe.custom.neoApplicationURL)
+		}
+	})
+
+	t.Run("mta with archive from build", func(t *testing.T) {
+		t.Parallel()
+		config := neoDeployOptions{CredentialType: "UsernamePassword", Username: "me", Password: "secret", DeployMode: "mta", MtarFilePath: "build.mtar", Host: "hana.example.org", Account: "myAccount"}
+		utils := newNeoDeployTestsUtils()
+		utils.AddFile("build.mtar", []byte("dummy"))
+
+		err := runNeoDeploy(&config, nil, utils, &neoDeployCommonPipelineEnvironment{})
+
+		if assert.NoError(t, err) {
+			assert.Contains(t, utils.Calls[0].Params, "build.mtar")
+		}
+	})
+
+	t.Run("war deploy ignores archive from build", func(t *testing.T) {
+		t.Parallel()
+		config := warConfig
+		config.MtarFilePath = "build.mtar"
+		utils := newNeoDeployTestsUtils()
+		utils.AddFile("build.mtar", []byte("dummy"))
+		utils.AddFile("pom.xml", []byte("<project><artifactId>myApp</artifactId><packaging>war</packaging></project>"))
+		utils.AddFile("target/myApp.war", []byte("dummy"))
+		utils.StdoutReturn = map[string]string{"neo.sh status.*": neoStatusStarted}
+
+		err := runNeoDeploy(&config, nil, utils, &neoDeployCommonPipelineEnvironment{})
+
+		if assert.NoError(t, err) {
+			assert.Contains(t, utils.Calls[0].Params, "target/myApp.war")
+			assert.NotContains(t, utils.Calls[0].Params, "build.mtar")
+		}
+	})
+
+	t.Run("war rolling update", func(t *testing.T) {
+		t.Parallel()
+		config := warConfig
+		config.WarAction = "rolling-update"
+		config.Source = "app.war"
+		utils := newNeoDeployTestsUtils()
+		utils.AddFile("app.war", []byte("dummy"))
+		utils.StdoutReturn = map[string]string{"neo.sh status.*": neoStatusStarted}
+		cpe := neoDeployCommonPipelineEnvironment{}
+
+		err := runNeoDeploy(&config, nil, utils, &cpe)
+
+		if assert.NoError(t, err) {
+			assert.Len(t, utils.Calls, 3)
+			assert.Equal(t, "status", utils.Calls[0].Params[0])
+			assert.Equal(t, "rolling-update", utils.Calls[1].Params[0])
+			assert.Equal(t, "status", utils.Calls[2].Params[0])
+		}
+	})
+
+	t.Run("war rolling update falls back to deploy", func(t *testing.T) {
+		t.Parallel()
+		config := warConfig
+		config.WarAction = "rolling-update"
+		config.Source = "app.war"
+		utils := newNeoDeployTestsUtils()
+		utils.AddFile("app.war", []byte("dummy"))
+		utils.StdoutReturn = map[string]string{"neo.sh status.*": "Status: STOPPED\n"}
+		cpe := neoDeployCommonPipelineEnvironment{}
+
+		err := runNeoDeploy(&config, nil, utils, &cpe)
+
+		assert.EqualError(t, err, "application is not running after deployment, status: 'STOPPED'")
+		if assert.Len(t, utils.Calls, 4) {
+			assert.Equal(t, "deploy", utils.Calls[1].Params[0])
+			assert.Equal(t, "restart", utils.Calls[2].Params[0])
+		}
+	})
+
+	t.Run("war properties file", func(t *testing.T) {
+		t.Parallel()
+		config := neoDeployOptions{CredentialType: "UsernamePassword", Username: "me", Password: "secret", DeployMode: "warPropertiesFile", WarAction: "deploy", Source: "app.war", PropertiesFile: "neo.properties"}
+		utils := newNeoDeployTestsUtils()
+		utils.AddFile("app.war", []byte("dummy"))
+		utils.AddFile("neo.properties", []byte("host=hana.example.org\naccount=myAccount\napplication=myApp\n"))
+		utils.StdoutReturn = map[string]string{"neo.sh status.*": neoStatusStarted}
+		cpe := neoDeployCommonPipelineEnvironment{}
+
+		err := runNeoDeploy(&config, nil, utils, &cpe)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, []string{"deploy", "neo.properties", "--user", "me", "--password", "secret", "--source", "app.war"}, utils.Calls[0].Params)
+		}
+	})
+
+	t.Run("deployment fails", func(t *testing.T) {
+		t.Parallel()
+		config := warConfig
+		config.Source = "app.war"
+		utils := newNeoDeployTestsUtils()
+		utils.AddFile("app.war", []byte("dummy"))
+		utils.ShouldFailOnCommand = map[string]error{"neo.sh deploy.*": fmt.Errorf("exit status 1")}
+		cpe := neoDeployCommonPipelineEnvironment{}
+
+		err := runNeoDeploy(&config, nil, utils, &cpe)
+
+		assert.EqualError(t, err, "the execution of the deploy command failed, see the log for details: exit status 1")
+		assert.Len(t, utils.Calls, 1)
+	})
+
+	t.Run("unsupported deploy mode", func(t *testing.T) {
+		t.Parallel()
+		config := neoDeployOptions{CredentialType: "UsernamePassword", DeployMode: "other"}
+
+		err := runNeoDeploy(&config, nil, newNeoDeployTestsUtils(), &neoDeployCommonPipelineEnvironment{})
+
+		assert.EqualError(t, err, "deploy mode 'other' is not supported, use one of 'mta', 'warParams', 'warPropertiesFile'")
+	})
+
+	t.Run("password with leading @", func(t *testing.T) {
+		t.Parallel()
+		config := warConfig
+		config.Password = "@secret"
+
+		err := runNeoDeploy(&config, nil, newNeoDeployTestsUtils(), &neoDeployCommonPipelineEnvironment{})
+
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "it is not allowed that the password starts with @")
+		}
+	})
+
+	t.Run("extensions for war deployment", func(t *testing.T) {
+		t.Parallel()
+		config := warConfig
+		config.Extensions = []string{"ext.mtaext"}
+
+		err := runNeoDeploy(&config, nil, newNeoDeployTestsUtils(), &neoDeployCommonPipelineEnvironment{})
+
+		assert.EqualError(t, err, "extensions ([ext.mtaext]) found for deploy mode 'warParams'. Extensions are only supported for deploy mode 'mta'")
+	})
+
+	t.Run("missing parameters", func(t *testing.T) {
+		t.Parallel()
+		config := neoDeployOptions{CredentialType: "UsernamePassword", Username: "me", Password: "secret", DeployMode: "warParams", WarAction: "deploy", Host: "hana.example.org"}
+
+		err := runNeoDeploy(&config, nil, newNeoDeployTestsUtils(), &neoDeployCommonPipelineEnvironment{})
+
+		assert.EqualError(t, err, "the following parameters are mandatory for deploy mode 'warParams' but not available: [account application runtime runtimeVersion]")
+	})
+
+	t.Run("source missing", func(t *testing.T) {
+		t.Parallel()
+		config := neoDeployOptions{CredentialType: "UsernamePassword", Username: "me", Password: "secret", DeployMode: "mta", Source: "app.mtar", Host: "hana.example.org", Account: "myAccount"}
+
+		err := runNeoDeploy(&config, nil, newNeoDeployTestsUtils(), &neoDeployCommonPipelineEnvironment{})
+
+		assert.EqualError(t, err, "file 'app.mtar' does not exist")
+	})
+
+	t.Run("mta with OAuth credentials", func(t *testing.T) {
+		t.Parallel()
+		config := neoDeployOptions{CredentialType: "SecretFile", OauthCredentialsFile: "credentials.json", DeployMode: "mta", Source: "app.mtar", Host: "hana.example.org", Account: "myAccount"}
+		utils := newNeoDeployTestsUtils()
+		utils.AddFile("app.mtar", []byte("dummy"))
+		utils.AddFile("credentials.json", []byte(`{"oauthClientId": "client", "oauthClientSecret": "secret", "oauthServiceUrl": "https://oauth.example.org"}`))
+		utils.responses["POST https://oauth.example.org/apitoken/v1?grant_type=client_credentials"] = `{"access_token": "token"}`
+		utils.responses["POST https://slservice.hana.example.org/slservice/v1/oauth/accounts/myAccount/mtars"] = `{"id": "123"}`
+		utils.responses["GET https://slservice.hana.example.org/slservice/v1/oauth/accounts/myAccount/mtars/123"] = `{"id": "123", "state": "DONE"}`
+
+		err := runNeoDeploy(&config, nil, utils, &neoDeployCommonPipelineEnvironment{})
+
+		if assert.NoError(t, err) {
+			assert.Empty(t, utils.Calls)
+			assert.Len(t, utils.requests, 3)
+		}
+	})
+
+	t.Run("OAuth credentials for war deployment", func(t *testing.T) {
+		t.Parallel()
+		config := warConfig
+		config.CredentialType = "SecretFile"
+		config.OauthCredentialsFile = "credentials.json"
+
+		err := runNeoDeploy(&config, nil, newNeoDeployTestsUtils(), &neoDeployCommonPipelineEnvironment{})
+
+		assert.EqualError(t, err, "credential type 'SecretFile' is only supported for deploy mode 'mta'")
+	})
+
+	t.Run("mta with cache invalidation", func(t *testing.T) {
+		t.Parallel()
+		config := neoDeployOptions{CredentialType: "UsernamePassword", Username: "me", Password: "secret", DeployMode: "mta", Source: "app.mtar", Host: "hana.example.org", Account: "myAccount",
+			InvalidateCache: true, PortalLandscape: "cloudnwcportal", SiteID: "mySite", OauthClientID: "client", OauthClientSecret: "secret"}
+		utils := newNeoDeployTestsUtils()
+		utils.AddFile("app.mtar", []byte("dummy"))
+		utils.responses["POST https://oauthasservices-myAccount.hana.example.org/oauth2/api/v1/token?grant_type=client_credentials&scope=write,read"] = `{"access_token": "token"}`
+		utils.responses["GET https://cloudnwcportal-myAccount.hana.example.org/fiori/api/v1/csrf"] = ""
+		utils.responses["POST https://cloudnwcportal-myAccount.hana.example.org/fiori/v1/operations/invalidateCache"] = ""
+
+		err := runNeoDeploy(&config, nil, utils, &neoDeployCommonPipelineEnvironment{})
+
+		if assert.NoError(t, err) {
+			assert.Len(t, utils.Calls, 1)
+			assert.Equal(t, []string{
+				"POST https://oauthasservices-myAccount.hana.example.org/oauth2/api/v1/token?grant_type=client_credentials&scope=write,read",
+				"GET https://cloudnwcportal-myAccount.hana.example.org/fiori/api/v1/csrf",
+				"POST https://cloudnwcportal-myAccount.hana.example.org/fiori/v1/operations/invalidateCache",
+			}, utils.requests)
+		}
+	})
+
+	t.Run("cache invalidation ignored for war deployment", func(t *testing.T) {
+		t.Parallel()
+		config := warConfig
+		config.Source = "app.war"
+		config.InvalidateCache = true
+		utils := newNeoDeployTestsUtils()
+		utils.AddFile("app.war", []byte("dummy"))
+		utils.StdoutReturn = map[string]string{"neo.sh status.*": neoStatusStarted}
+
+		err := runNeoDeploy(&config, nil, utils, &neoDeployCommonPipelineEnvironment{})
+
+		if assert.NoError(t, err) {
+			assert.Empty(t, utils.requests)
+		}
+	})
+
+	t.Run("cache invalidation without OAuth client", func(t *testing.T) {
+		t.Parallel()
+		config := neoDeployOptions{CredentialType: "UsernamePassword", Username: "me", Password: "secret", DeployMode: "mta", Source: "app.mtar", Host: "hana.example.org", Account: "myAccount", InvalidateCache: true}
+
+		err := runNeoDeploy(&config, nil, newNeoDeployTestsUtils(), &neoDeployCommonPipelineEnvironment{})
+
+		assert.EqualError(t, err, "the following parameters are mandatory for deploy mode 'mta' but not available: [oauthClientId oauthClientSecret]")
+	})
+}
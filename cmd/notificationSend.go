@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/git"
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/notification"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/pkg/errors"
+)
+
+type notificationSendUtils interface {
+	Glob(pattern string) (matches []string, err error)
+	FileRead(path string) ([]byte, error)
+
+	SendRequest(method, url string, body io.Reader, header http.Header, cookies []*http.Cookie) (*http.Response, error)
+	SendMail(server notification.MailServer, from string, recipients []string, message notification.Message) error
+	CommitAuthors(from, to string) ([]string, error)
+}
+
+type notificationSendUtilsBundle struct {
+	*piperutils.Files
+	*piperhttp.Client
+}
+
+func (n *notificationSendUtilsBundle) SendMail(server notification.MailServer, from string, recipients []string, message notification.Message) error {
+	return notification.NewMailSender(server).Send(from, recipients, message)
+}
+
+func (n *notificationSendUtilsBundle) CommitAuthors(from, to string) ([]string, error) {
+	repo, err := git.PlainOpen(".")
+	if err != nil {
+		return nil, err
+	}
+	return git.CommitAuthors(repo, from, to)
+}
+
+func newNotificationSendUtils() notificationSendUtils {
+	return &notificationSendUtilsBundle{
+		Files:  &piperutils.Files{},
+		Client: &piperhttp.Client{},
+	}
+}
+
+// notificationErrorDetails contains the relevant content of the errorDetails.json files written by log.FatalHook
+type notificationErrorDetails struct {
+	StepName string `json:"stepName"`
+	Message  string `json:"message"`
+	Error    string `json:"error"`
+	Category string `json:"category"`
+}
+
+type notificationContext struct {
+	BuildResult  string
+	ProjectName  string
+	BuildURL     string
+	CommitID     string
+	ErrorDetails []notificationErrorDetails
+}
+
+func notificationSend(config notificationSendOptions, telemetryData *telemetry.CustomData) {
+	utils := newNotificationSendUtils()
+
+	err := runNotificationSend(&config, utils)
+	if err != nil {
+		log.Entry().WithError(err).Fatal("step execution failed")
+	}
+}
+
+func runNotificationSend(config *notificationSendOptions, utils notificationSendUtils) error {
+	if config.BuildResult == notification.ResultAborted {
+		log.Entry().Info("Build has been aborted, no notification is sent.")
+		return nil
+	}
+
+	errorDetails, err := readNotificationErrorDetails(utils)
+	if err != nil {
+		return err
+	}
+	context := notificationContext{
+		BuildResult:  config.BuildResult,
+		ProjectName:  config.ProjectName,
+		BuildURL:     config.BuildURL,
+		CommitID:     config.GitCommitID,
+		ErrorDetails: errorDetails,
+	}
+
+	subject, err := piperutils.ExecuteTemplate(config.SubjectTemplate, context)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return errors.Wrap(err, "failed to render notification subject")
+	}
+	text, err := piperutils.ExecuteTemplate(config.MessageTemplate, context)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return errors.Wrap(err, "failed to render notification message")
+	}
+	message := notification.Message{Subject: strings.TrimSpace(subject), Text: strings.TrimSpace(text), Result: config.BuildResult}
+
+	sent := false
+	failures := []string{}
+	if len(config.SlackWebhookURL) > 0 {
+		sent = true
+		if err := notification.SendSlack(utils, config.SlackWebhookURL, message); err != nil {
+			failures = append(failures, err.Error())
+		} else {
+			log.Entry().Info("Notification sent to Slack.")
+		}
+	}
+	if len(config.TeamsWebhookURL) > 0 {
+		sent = true
+		if err := notification.SendTeams(utils, config.TeamsWebhookURL, message); err != nil {
+			failures = append(failures, err.Error())
+		} else {
+			log.Entry().Info("Notification sent to Microsoft Teams.")
+		}
+	}
+	if len(config.SmtpHost) > 0 {
+		sent = true
+		if err := sendNotificationMail(config, message, utils); err != nil {
+			failures = append(failures, err.Error())
+		}
+	}
+
+	if !sent {
+		log.Entry().Warn("Neither a webhook URL nor an SMTP host is configured, no notification is sent.")
+	}
+	if len(failures) > 0 {
+		log.SetErrorCategory(log.ErrorService)
+		return fmt.Errorf("failed to send notifications: %s", strings.Join(failures, "; "))
+	}
+	return nil
+}
+
+func sendNotificationMail(config *notificationSendOptions, message notification.Message, utils notificationSendUtils) error {
+	recipients := append([]string{}, config.NotificationRecipients...)
+	if config.NotifyCulprits {
+		if len(config.GitCommitID) == 0 {
+			log.Entry().Warn("No git commit id available, culprits cannot be notified.")
+		} else {
+			culprits, err := utils.CommitAuthors(config.GitBaseCommitID, config.GitCommitID)
+			if err != nil {
+				log.Entry().WithError(err).Warn("Culprits cannot be notified.")
+			}
+			for _, culprit := range culprits {
+				if !piperutils.ContainsString(recipients, culprit) {
+					recipients = append(recipients, culprit)
+				}
+			}
+		}
+	}
+	if len(recipients) == 0 {
+		log.Entry().Warn("No mail recipients found, no mail is sent.")
+		return nil
+	}
+
+	server := notification.MailServer{
+		Host:     config.SmtpHost,
+		Port:     config.SmtpPort,
+		Username: config.SmtpUsername,
+		Password: config.SmtpPassword,
+	}
+	if err := utils.SendMail(server, config.MailFrom, recipients, message); err != nil {
+		return err
+	}
+	log.Entry().Infof("Notification mail sent to %s.", strings.Join(recipients, ", "))
+	return nil
+}
+
+func readNotificationErrorDetails(utils notificationSendUtils) ([]notificationErrorDetails, error) {
+	files, err := utils.Glob("*errorDetails.json")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to search for error details")
+	}
+	sort.Strings(files)
+
+	details := []notificationErrorDetails{}
+	for _, file := range files {
+		content, err := utils.FileRead(file)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read error details '%s'", file)
+		}
+		var detail notificationErrorDetails
+		if err := json.Unmarshal(content, &detail); err != nil {
+			log.Entry().WithError(err).Warnf("Failed to parse error details '%s'", file)
+			continue
+		}
+		details = append(details, detail)
+	}
+	return details, nil
+}
@@ -0,0 +1,330 @@
+// Code generated by piper's step-generator. DO NOT EDIT.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/spf13/cobra"
+)
+
+type notificationSendOptions struct {
+	BuildResult            string   `json:"buildResult,omitempty"`
+	ProjectName            string   `json:"projectName,omitempty"`
+	BuildURL               string   `json:"buildUrl,omitempty"`
+	SubjectTemplate        string   `json:"subjectTemplate,omitempty"`
+	MessageTemplate        string   `json:"messageTemplate,omitempty"`
+	SlackWebhookURL        string   `json:"slackWebhookUrl,omitempty"`
+	TeamsWebhookURL        string   `json:"teamsWebhookUrl,omitempty"`
+	SmtpHost               string   `json:"smtpHost,omitempty"`
+	SmtpPort               int      `json:"smtpPort,omitempty"`
+	SmtpUsername           string   `json:"smtpUsername,omitempty"`
+	SmtpPassword           string   `json:"smtpPassword,omitempty"`
+	MailFrom               string   `json:"mailFrom,omitempty"`
+	NotificationRecipients []string `json:"notificationRecipients,omitempty"`
+	NotifyCulprits         bool     `json:"notifyCulprits,omitempty"`
+	GitCommitID            string   `json:"gitCommitId,omitempty"`
+	GitBaseCommitID        string   `json:"gitBaseCommitId,omitempty"`
+}
+
+// NotificationSendCommand Sends build notifications via Slack, Microsoft Teams and e-mail.
+func NotificationSendCommand() *cobra.Command {
+	const STEP_NAME = "notificationSend"
+
+	metadata := notificationSendMetadata()
+	var stepConfig notificationSendOptions
+	var startTime time.Time
+
+	var createNotificationSendCmd = &cobra.Command{
+		Use:   STEP_NAME,
+		Short: "Sends build notifications via Slack, Microsoft Teams and e-mail.",
+		Long: `Sends a notification about the build result to Slack and Microsoft Teams incoming webhooks and via e-mail (SMTP).
+A notification is sent to each channel which is configured, i.e. a webhook URL for Slack or Teams and an SMTP host for e-mail.
+
+Subject and message are rendered from [Go templates](https://golang.org/pkg/text/template/).
+The following fields are available in the templates:
+
+* ` + "`" + `.BuildResult` + "`" + ` - the build result, e.g. ` + "`" + `FAILURE` + "`" + `
+* ` + "`" + `.ProjectName` + "`" + ` - the name of the project
+* ` + "`" + `.BuildURL` + "`" + ` - the URL of the build
+* ` + "`" + `.CommitID` + "`" + ` - the git commit id of the build
+* ` + "`" + `.ErrorDetails` + "`" + ` - list of error details written by failing steps (` + "`" + `errorDetails.json` + "`" + `) with the fields ` + "`" + `.StepName` + "`" + `, ` + "`" + `.Message` + "`" + `, ` + "`" + `.Error` + "`" + ` and ` + "`" + `.Category` + "`" + `
+
+In case ` + "`" + `notifyCulprits` + "`" + ` is active, the authors of the commits since ` + "`" + `gitBaseCommitId` + "`" + ` (or only the author of ` + "`" + `gitCommitId` + "`" + ` if no base commit is provided)
+receive the e-mail in addition to the ` + "`" + `notificationRecipients` + "`" + `.`,
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			startTime = time.Now()
+			log.SetStepName(STEP_NAME)
+			log.SetVerbose(GeneralConfig.Verbose)
+
+			path, _ := os.Getwd()
+			fatalHook := &log.FatalHook{CorrelationID: GeneralConfig.CorrelationID, Path: path}
+			log.RegisterHook(fatalHook)
+
+			err := PrepareConfig(cmd, &metadata, STEP_NAME, &stepConfig, config.OpenPiperFile)
+			if err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+			log.RegisterSecret(stepConfig.SlackWebhookURL)
+			log.RegisterSecret(stepConfig.TeamsWebhookURL)
+			log.RegisterSecret(stepConfig.SmtpUsername)
+			log.RegisterSecret(stepConfig.SmtpPassword)
+
+			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
+				sentryHook := log.NewSentryHook(GeneralConfig.HookConfig.SentryConfig.Dsn, GeneralConfig.CorrelationID)
+				log.RegisterHook(&sentryHook)
+			}
+
+			return nil
+		},
+		Run: func(_ *cobra.Command, _ []string) {
+			telemetryData := telemetry.CustomData{}
+			telemetryData.ErrorCode = "1"
+			handler := func() {
+				config.RemoveVaultSecretFiles()
+				telemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
+				telemetryData.ErrorCategory = log.GetErrorCategory().String()
+				telemetry.Send(&telemetryData)
+			}
+			log.DeferExitHandler(handler)
+			defer handler()
+			telemetry.Initialize(GeneralConfig.NoTelemetry, STEP_NAME)
+			notificationSend(stepConfig, &telemetryData)
+			telemetryData.ErrorCode = "0"
+			log.Entry().Info("SUCCESS")
+		},
+	}
+
+	addNotificationSendFlags(createNotificationSendCmd, &stepConfig)
+	return createNotificationSendCmd
+}
+
+func addNotificationSendFlags(cmd *cobra.Command, stepConfig *notificationSendOptions) {
+	cmd.Flags().StringVar(&stepConfig.BuildResult, "buildResult", os.Getenv("PIPER_buildResult"), "The result of the build the notification is sent for.")
+	cmd.Flags().StringVar(&stepConfig.ProjectName, "projectName", os.Getenv("PIPER_projectName"), "The name of the project used in the notification.")
+	cmd.Flags().StringVar(&stepConfig.BuildURL, "buildUrl", os.Getenv("PIPER_buildUrl"), "The URL of the build used in the notification.")
+	cmd.Flags().StringVar(&stepConfig.SubjectTemplate, "subjectTemplate", `{{.BuildResult}}: Build {{.ProjectName}}`, "Go template for the subject of the notification.")
+	cmd.Flags().StringVar(&stepConfig.MessageTemplate, "messageTemplate", `Build {{.ProjectName}} finished with result {{.BuildResult}}.
+{{if .BuildURL}}Details: {{.BuildURL}}
+{{end}}{{range .ErrorDetails}}
+Step '{{.StepName}}' failed ({{.Category}}): {{.Message}}
+{{.Error}}
+{{end}}
+`, "Go template for the text of the notification.")
+	cmd.Flags().StringVar(&stepConfig.SlackWebhookURL, "slackWebhookUrl", os.Getenv("PIPER_slackWebhookUrl"), "URL of the Slack incoming webhook.")
+	cmd.Flags().StringVar(&stepConfig.TeamsWebhookURL, "teamsWebhookUrl", os.Getenv("PIPER_teamsWebhookUrl"), "URL of the Microsoft Teams incoming webhook.")
+	cmd.Flags().StringVar(&stepConfig.SmtpHost, "smtpHost", os.Getenv("PIPER_smtpHost"), "Host of the SMTP server used for sending e-mails.")
+	cmd.Flags().IntVar(&stepConfig.SmtpPort, "smtpPort", 25, "Port of the SMTP server used for sending e-mails.")
+	cmd.Flags().StringVar(&stepConfig.SmtpUsername, "smtpUsername", os.Getenv("PIPER_smtpUsername"), "User for the SMTP server.")
+	cmd.Flags().StringVar(&stepConfig.SmtpPassword, "smtpPassword", os.Getenv("PIPER_smtpPassword"), "Password for the SMTP server.")
+	cmd.Flags().StringVar(&stepConfig.MailFrom, "mailFrom", os.Getenv("PIPER_mailFrom"), "Sender address of the e-mails.")
+	cmd.Flags().StringSliceVar(&stepConfig.NotificationRecipients, "notificationRecipients", []string{}, "List of e-mail recipients that always get the notification.")
+	cmd.Flags().BoolVar(&stepConfig.NotifyCulprits, "notifyCulprits", false, "Notify the authors of the commits which are part of the build via e-mail.")
+	cmd.Flags().StringVar(&stepConfig.GitCommitID, "gitCommitId", os.Getenv("PIPER_gitCommitId"), "The git commit id of the build, used for the resolution of the culprits.")
+	cmd.Flags().StringVar(&stepConfig.GitBaseCommitID, "gitBaseCommitId", os.Getenv("PIPER_gitBaseCommitId"), "The git commit id of the last successful build. All authors of commits after this commit are notified in case `notifyCulprits` is active.")
+
+	cmd.MarkFlagRequired("buildResult")
+}
+
+// retrieve step metadata
+func notificationSendMetadata() config.StepData {
+	var theMetaData = config.StepData{
+		Metadata: config.StepMetadata{
+			Name:        "notificationSend",
+			Aliases:     []config.Alias{},
+			Description: "Sends build notifications via Slack, Microsoft Teams and e-mail.",
+		},
+		Spec: config.StepSpec{
+			Inputs: config.StepInputs{
+				Parameters: []config.StepParameters{
+					{
+						Name:        "buildResult",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   true,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "projectName",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "buildUrl",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "subjectTemplate",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "messageTemplate",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name: "slackWebhookUrl",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "slackCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:  "",
+								Paths: []string{"$(vaultPath)/slack", "$(vaultBasePath)/$(vaultPipelineName)/slack", "$(vaultBasePath)/GROUP-SECRETS/slack"},
+								Type:  "vaultSecret",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+					},
+					{
+						Name: "teamsWebhookUrl",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "teamsCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:  "",
+								Paths: []string{"$(vaultPath)/teams", "$(vaultBasePath)/$(vaultPipelineName)/teams", "$(vaultBasePath)/GROUP-SECRETS/teams"},
+								Type:  "vaultSecret",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+					},
+					{
+						Name:        "smtpHost",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "smtpPort",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name: "smtpUsername",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "smtpCredentialsId",
+								Param: "username",
+								Type:  "secret",
+							},
+
+							{
+								Name:  "",
+								Paths: []string{"$(vaultPath)/smtp", "$(vaultBasePath)/$(vaultPipelineName)/smtp", "$(vaultBasePath)/GROUP-SECRETS/smtp"},
+								Type:  "vaultSecret",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+					},
+					{
+						Name: "smtpPassword",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "smtpCredentialsId",
+								Param: "password",
+								Type:  "secret",
+							},
+
+							{
+								Name:  "",
+								Paths: []string{"$(vaultPath)/smtp", "$(vaultBasePath)/$(vaultPipelineName)/smtp", "$(vaultBasePath)/GROUP-SECRETS/smtp"},
+								Type:  "vaultSecret",
+							},
+						},
+						Scope:     []string{"PARAMETERS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+					},
+					{
+						Name:        "mailFrom",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "notificationRecipients",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "notifyCulprits",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name: "gitCommitId",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "git/commitId",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+					},
+					{
+						Name:        "gitBaseCommitId",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+				},
+			},
+		},
+	}
+	return theMetaData
+}
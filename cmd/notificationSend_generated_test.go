@@ -0,0 +1,17 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotificationSendCommand(t *testing.T) {
+	t.Parallel()
+
+	testCmd := NotificationSendCommand()
+
+	// only high level testing performed - details are tested in step generation procedure
+	assert.Equal(t, "notificationSend", testCmd.Use, "command name incorrect")
+
+}
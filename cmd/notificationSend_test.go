@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/SAP/jenkins-library/pkg/notification"
+	"github.com/stretchr/testify/assert"
+)
+
+type notificationSendMockUtils struct {
+	*mock.FilesMock
+	requests       map[string]string
+	requestError   error
+	mailServer     notification.MailServer
+	mailFrom       string
+	mailRecipients []string
+	mailMessage    notification.Message
+	mailError      error
+	authors        []string
+	authorsError   error
+	authorsFrom    string
+	authorsTo      string
+}
+
+func newNotificationSendTestsUtils() *notificationSendMockUtils {
+	return &notificationSendMockUtils{
+		FilesMock: &mock.FilesMock{},
+		requests:  map[string]string{},
+	}
+}
+
+func (n *notificationSendMockUtils) SendRequest(method, url string, body io.Reader, header http.Header, cookies []*http.Cookie) (*http.Response, error) {
+	content, _ := ioutil.ReadAll(body)
+	n.requests[url] = string(content)
+	if n.requestError != nil {
+		return nil, n.requestError
+	}
+	return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(bytes.NewReader([]byte("ok")))}, nil
+}
+
+func (n *notificationSendMockUtils) SendMail(server notification.MailServer, from string, recipients []string, message notification.Message) error {
+	n.mailServer = server
+	n.mailFrom = from
+	n.mailRecipients = recipients
+	n.mailMessage = message
+	return n.mailError
+}
+
+func (n *notificationSendMockUtils) CommitAuthors(from, to string) ([]string, error) {
+	n.authorsFrom = from
+	n.authorsTo = to
+	return n.authors, n.authorsError
+}
+
+func TestRunNotificationSend(t *testing.T) {
+	t.Parallel()
+
+	defaultConfig := func() notificationSendOptions {
+		return notificationSendOptions{
+			BuildResult:     "FAILURE",
+			ProjectName:     "myProject",
+			BuildURL:        "https://ci.example.org/job/myProject/1",
+			SubjectTemplate: "{{.BuildResult}}: Build {{.ProjectName}}",
+			MessageTemplate: "{{.BuildURL}}{{range .ErrorDetails}}\n{{.StepName}}: {{.Message}} - {{.Error}}{{end}}",
+			SmtpPort:        25,
+		}
+	}
+
+	t.Run("slack and teams", func(t *testing.T) {
+		t.Parallel()
+		config := defaultConfig()
+		config.SlackWebhookURL = "https://hooks.slack.com/services/x"
+		config.TeamsWebhookURL = "https://example.webhook.office.com/webhookb2/x"
+		utils := newNotificationSendTestsUtils()
+		utils.AddFile("npmExecute_errorDetails.json", []byte(`{"stepName":"npmExecute","message":"step execution failed","error":"npm install failed","category":"build"}`))
+
+		err := runNotificationSend(&config, utils)
+
+		if assert.NoError(t, err) {
+			assert.Contains(t, utils.requests[config.SlackWebhookURL], `"title":"FAILURE: Build myProject"`)
+			assert.Contains(t, utils.requests[config.SlackWebhookURL], `npmExecute: step execution failed - npm install failed`)
+			assert.Contains(t, utils.requests[config.TeamsWebhookURL], `"@type":"MessageCard"`)
+			assert.Contains(t, utils.requests[config.TeamsWebhookURL], `https://ci.example.org/job/myProject/1`)
+		}
+	})
+
+	t.Run("mail with culprits", func(t *testing.T) {
+		t.Parallel()
+		config := defaultConfig()
+		config.SmtpHost = "smtp.example.org"
+		config.SmtpUsername = "user"
+		config.SmtpPassword = "password"
+		config.MailFrom = "piper@example.org"
+		config.NotificationRecipients = []string{"team@example.org", "a@example.org"}
+		config.NotifyCulprits = true
+		config.GitCommitID = "abcdef"
+		config.GitBaseCommitID = "123456"
+		utils := newNotificationSendTestsUtils()
+		utils.authors = []string{"a@example.org", "b@example.org"}
+
+		err := runNotificationSend(&config, utils)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, "123456", utils.authorsFrom)
+			assert.Equal(t, "abcdef", utils.authorsTo)
+			assert.Equal(t, []string{"team@example.org", "a@example.org", "b@example.org"}, utils.mailRecipients)
+			assert.Equal(t, notification.MailServer{Host: "smtp.example.org", Port: 25, Username: "user", Password: "password"}, utils.mailServer)
+			assert.Equal(t, "piper@example.org", utils.mailFrom)
+			assert.Equal(t, "FAILURE: Build myProject", utils.mailMessage.Subject)
+			assert.Equal(t, "https://ci.example.org/job/myProject/1", utils.mailMessage.Text)
+		}
+	})
+
+	t.Run("culprits cannot be resolved", func(t *testing.T) {
+		t.Parallel()
+		config := defaultConfig()
+		config.SmtpHost = "smtp.example.org"
+		config.NotificationRecipients = []string{"team@example.org"}
+		config.NotifyCulprits = true
+		config.GitCommitID = "abcdef"
+		utils := newNotificationSendTestsUtils()
+		utils.authorsError = fmt.Errorf("repository does not exist")
+
+		err := runNotificationSend(&config, utils)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, []string{"team@example.org"}, utils.mailRecipients)
+		}
+	})
+
+	t.Run("no mail recipients", func(t *testing.T) {
+		t.Parallel()
+		config := defaultConfig()
+		config.SmtpHost = "smtp.example.org"
+		utils := newNotificationSendTestsUtils()
+
+		err := runNotificationSend(&config, utils)
+
+		assert.NoError(t, err)
+		assert.Empty(t, utils.mailRecipients)
+	})
+
+	t.Run("aborted build", func(t *testing.T) {
+		t.Parallel()
+		config := defaultConfig()
+		config.BuildResult = "ABORTED"
+		config.SlackWebhookURL = "https://hooks.slack.com/services/x"
+		utils := newNotificationSendTestsUtils()
+
+		err := runNotificationSend(&config, utils)
+
+		assert.NoError(t, err)
+		assert.Empty(t, utils.requests)
+	})
+
+	t.Run("sending fails", func(t *testing.T) {
+		t.Parallel()
+		config := defaultConfig()
+		config.SlackWebhookURL = "https://hooks.slack.com/services/x"
+		config.SmtpHost = "smtp.example.org"
+		config.NotificationRecipients = []string{"team@example.org"}
+		utils := newNotificationSendTestsUtils()
+		utils.requestError = fmt.Errorf("connection refused")
+		utils.mailError = fmt.Errorf("mail rejected")
+
+		err := runNotificationSend(&config, utils)
+
+		assert.EqualError(t, err, "failed to send notifications: failed to send Slack notification: connection refused; mail rejected")
+		assert.Equal(t, []string{"team@example.org"}, utils.mailRecipients)
+	})
+
+	t.Run("invalid template", func(t *testing.T) {
+		t.Parallel()
+		config := defaultConfig()
+		config.SubjectTemplate = "{{.Unknown"
+		utils := newNotificationSendTestsUtils()
+
+		err := runNotificationSend(&config, utils)
+
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "failed to render notification subject")
+		}
+	})
+}
@@ -26,6 +26,7 @@ type GeneralConfigOptions struct {
 	ParametersJSON       string
 	EnvRootPath          string
 	NoTelemetry          bool
+	NoDebugReport        bool
 	StageName            string
 	StepConfigJSON       string
 	StepMetadata         string //metadata to be considered, can be filePath or ENV containing JSON in format 'ENV:MY_ENV_VAR'
@@ -137,8 +138,28 @@ func Execute() {
 	rootCmd.AddCommand(AbapEnvironmentAssembleConfirmCommand())
 	rootCmd.AddCommand(IntegrationArtifactUploadCommand())
 	rootCmd.AddCommand(ContainerExecuteStructureTestsCommand())
+	rootCmd.AddCommand(TransportRequestCreateCTSCommand())
+	rootCmd.AddCommand(TransportRequestReleaseCTSCommand())
+	rootCmd.AddCommand(TransportRequestCreateSOLMANCommand())
+	rootCmd.AddCommand(TransportRequestReleaseSOLMANCommand())
+	rootCmd.AddCommand(SpinnakerTriggerPipelineCommand())
+	rootCmd.AddCommand(NeoDeployCommand())
+	rootCmd.AddCommand(NotificationSendCommand())
+	rootCmd.AddCommand(ChangelogCreateCommand())
+	rootCmd.AddCommand(HelmExecuteCommand())
+	rootCmd.AddCommand(ContainerScanImageCommand())
+	rootCmd.AddCommand(ContainerSignImageCommand())
+	rootCmd.AddCommand(GithubDecoratePullRequestCommand())
+	rootCmd.AddCommand(ScmSetCommitStatusCommand())
+	rootCmd.AddCommand(ScmPublishReleaseCommand())
+	rootCmd.AddCommand(ScmCreatePullRequestCommand())
+	rootCmd.AddCommand(ScmCreateIssueCommand())
+	rootCmd.AddCommand(ScmCommentIssueCommand())
+	rootCmd.AddCommand(ScmCheckBranchProtectionCommand())
 
 	addRootFlags(rootCmd)
+	log.RegisterHook(debugLogCollector)
+	registerDebugReport()
 	if err := rootCmd.Execute(); err != nil {
 		log.SetErrorCategory(log.ErrorConfiguration)
 		log.Entry().WithError(err).Fatal("configuration error")
@@ -156,6 +177,7 @@ func addRootFlags(rootCmd *cobra.Command) {
 	rootCmd.PersistentFlags().StringVar(&GeneralConfig.StageName, "stageName", "", "Name of the stage for which configuration should be included")
 	rootCmd.PersistentFlags().StringVar(&GeneralConfig.StepConfigJSON, "stepConfigJSON", os.Getenv("PIPER_stepConfigJSON"), "Step configuration in JSON format")
 	rootCmd.PersistentFlags().BoolVar(&GeneralConfig.NoTelemetry, "noTelemetry", false, "Disables telemetry reporting")
+	rootCmd.PersistentFlags().BoolVar(&GeneralConfig.NoDebugReport, "noDebugReport", false, "Disables the creation of a debug report archive in case of a step failure")
 	rootCmd.PersistentFlags().BoolVarP(&GeneralConfig.Verbose, "verbose", "v", false, "verbose output")
 	rootCmd.PersistentFlags().StringVar(&GeneralConfig.LogFormat, "logFormat", "default", "Log format to use. Options: default, timestamp, plain, full.")
 	rootCmd.PersistentFlags().StringVar(&GeneralConfig.VaultServerURL, "vaultServerUrl", "", "The vault server which should be used to fetch credentials")
@@ -306,6 +328,8 @@ func PrepareConfig(cmd *cobra.Command, metadata *config.StepData, stepName strin
 
 	retrieveHookConfig(stepConfig.HookConfig, &GeneralConfig.HookConfig)
 
+	prepareDebugReport(stepName, metadata, stepConfig.Config)
+
 	return nil
 }
 
@@ -20,6 +20,7 @@ import (
 	"github.com/SAP/jenkins-library/pkg/log"
 	StepResults "github.com/SAP/jenkins-library/pkg/piperutils"
 	"github.com/SAP/jenkins-library/pkg/protecode"
+	"github.com/SAP/jenkins-library/pkg/reporting"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
 )
 
@@ -208,7 +209,29 @@ func executeProtecodeScan(influx *protecodeExecuteScanInflux, client protecode.P
 	}
 	StepResults.PersistReportsAndLinks("protecodeExecuteScan", "", reports, links)
 
-	if config.FailOnSevereVulnerabilities && protecode.HasSevereVulnerabilities(result.Result, config.ExcludeCVEs) {
+	severe := protecode.HasSevereVulnerabilities(result.Result, config.ExcludeCVEs)
+	scanReport := reporting.ScanReport{
+		StepName: "protecodeExecuteScan",
+		Title:    "Protecode Vulnerability Report",
+		Subheaders: []reporting.Subheader{
+			{Description: "Protecode product", Details: fmt.Sprint(productID)},
+		},
+		Overview: []reporting.OverviewRow{
+			{Description: "Vulnerabilities", Details: fmt.Sprint(parsedResult["vulnerabilities"])},
+			{Description: "Major vulnerabilities", Details: fmt.Sprint(parsedResult["major_vulnerabilities"])},
+			{Description: "Minor vulnerabilities", Details: fmt.Sprint(parsedResult["minor_vulnerabilities"])},
+			{Description: "Triaged vulnerabilities", Details: fmt.Sprint(parsedResult["triaged_vulnerabilities"])},
+			{Description: "Excluded vulnerabilities", Details: fmt.Sprint(parsedResult["excluded_vulnerabilities"])},
+		},
+		FurtherInfo:    fmt.Sprintf(webReportPath, config.ServerURL, productID),
+		ReportTime:     time.Now(),
+		SuccessfulScan: !severe,
+	}
+	if err := reporting.WriteJSONReport(scanReport, workspaceFiles{workspace: reportPath}); err != nil {
+		log.Entry().Warningf("failed to write scan report: %v", err)
+	}
+
+	if config.FailOnSevereVulnerabilities && severe {
 		log.SetErrorCategory(log.ErrorCompliance)
 		return fmt.Errorf("the product is not compliant")
 	}
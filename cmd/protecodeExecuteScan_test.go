@@ -18,6 +18,7 @@ import (
 
 	pkgutil "github.com/GoogleContainerTools/container-diff/pkg/util"
 	"github.com/SAP/jenkins-library/pkg/protecode"
+	"github.com/SAP/jenkins-library/pkg/reporting"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -345,6 +346,8 @@ func TestExecuteProtecodeScan(t *testing.T) {
 		assert.Equal(t, 1, influxData.protecode_data.fields.excluded_vulnerabilities)
 		assert.Equal(t, 142, influxData.protecode_data.fields.major_vulnerabilities)
 		assert.Equal(t, 226, influxData.protecode_data.fields.vulnerabilities)
+		scanReports, _ := filepath.Glob(filepath.Join(reportPath, reporting.StepReportDirectory, "protecodeExecuteScan_*.json"))
+		assert.Len(t, scanReports, 1)
 	}
 }
 
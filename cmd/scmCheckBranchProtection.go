@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	piperGithub "github.com/SAP/jenkins-library/pkg/github"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/scm"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/pkg/errors"
+)
+
+type scmBranchProtectionReader interface {
+	GetBranchProtection(branch string) (scm.BranchProtection, error)
+}
+
+func scmCheckBranchProtection(config scmCheckBranchProtectionOptions, telemetryData *telemetry.CustomData) {
+	provider, err := scm.NewProvider(scm.Options{
+		Provider:   config.Provider,
+		APIURL:     config.APIURL,
+		Token:      config.Token,
+		Owner:      config.Owner,
+		Repository: config.Repository,
+		GitHubApp: piperGithub.AppOptions{
+			AppID:          config.GithubAppID,
+			InstallationID: config.GithubAppInstallationID,
+			PrivateKeyFile: config.GithubAppPrivateKey,
+		},
+	})
+	if err != nil {
+		log.Entry().WithError(err).Fatal("Failed to get SCM provider")
+	}
+	err = runScmCheckBranchProtection(&config, provider)
+	if err != nil {
+		log.Entry().WithError(err).Fatal("Branch protection check failed")
+	}
+}
+
+func runScmCheckBranchProtection(config *scmCheckBranchProtectionOptions, reader scmBranchProtectionReader) error {
+	protection, err := reader.GetBranchProtection(config.Branch)
+	if err != nil {
+		return errors.Wrap(err, "failed to read branch protection information")
+	}
+
+	// validate required status checks
+	for _, check := range config.RequiredChecks {
+		if !piperutils.ContainsString(protection.RequiredChecks, check) {
+			return fmt.Errorf("required status check '%v' not found among '%v' in branch protection configuration", check, strings.Join(protection.RequiredChecks, ","))
+		}
+	}
+
+	// validate that admins are enforced in checks
+	if config.RequireEnforceAdmins && !protection.EnforceAdmins {
+		return fmt.Errorf("admins are not enforced in branch protection configuration")
+	}
+
+	// validate number of mandatory reviewers
+	if config.RequiredApprovingReviewCount > 0 && protection.RequiredApprovingReviewCount < config.RequiredApprovingReviewCount {
+		return fmt.Errorf("not enough mandatory reviewers in branch protection configuration, expected at least %v, got %v", config.RequiredApprovingReviewCount, protection.RequiredApprovingReviewCount)
+	}
+
+	return nil
+}
@@ -0,0 +1,266 @@
+// Code generated by piper's step-generator. DO NOT EDIT.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/spf13/cobra"
+)
+
+type scmCheckBranchProtectionOptions struct {
+	APIURL                       string   `json:"apiUrl,omitempty"`
+	Branch                       string   `json:"branch,omitempty"`
+	GithubAppID                  int      `json:"githubAppId,omitempty"`
+	GithubAppInstallationID      int      `json:"githubAppInstallationId,omitempty"`
+	GithubAppPrivateKey          string   `json:"githubAppPrivateKey,omitempty"`
+	Owner                        string   `json:"owner,omitempty"`
+	Provider                     string   `json:"provider,omitempty"`
+	Repository                   string   `json:"repository,omitempty"`
+	RequiredApprovingReviewCount int      `json:"requiredApprovingReviewCount,omitempty"`
+	RequiredChecks               []string `json:"requiredChecks,omitempty"`
+	RequireEnforceAdmins         bool     `json:"requireEnforceAdmins,omitempty"`
+	Token                        string   `json:"token,omitempty"`
+}
+
+// ScmCheckBranchProtectionCommand Check branch protection of a GitHub, GitLab or Bitbucket branch.
+func ScmCheckBranchProtectionCommand() *cobra.Command {
+	const STEP_NAME = "scmCheckBranchProtection"
+
+	metadata := scmCheckBranchProtectionMetadata()
+	var stepConfig scmCheckBranchProtectionOptions
+	var startTime time.Time
+
+	var createScmCheckBranchProtectionCmd = &cobra.Command{
+		Use:   STEP_NAME,
+		Short: "Check branch protection of a GitHub, GitLab or Bitbucket branch.",
+		Long: `In order to ensure certain quality gates are enforced on a branch, this step checks the branch protection independent of the source code management system.
+
+The settings of the providers are mapped as follows:
+
+| Check | GitHub | GitLab | Bitbucket |
+| ----- | ------ | ------ | --------- |
+| ` + "`" + `requiredChecks` + "`" + ` | required status checks | ` + "`" + `pipeline` + "`" + ` in case pipelines must succeed | required builds |
+| ` + "`" + `requireEnforceAdmins` + "`" + ` | include administrators | nobody may push or force push | pull request only without exemptions |
+| ` + "`" + `requiredApprovingReviewCount` + "`" + ` | required approving reviews | approval rules | required approvers |
+
+The provider is selected via ` + "`" + `provider` + "`" + `:
+
+* ` + "`" + `github` + "`" + `: GitHub and GitHub Enterprise
+* ` + "`" + `gitlab` + "`" + `: GitLab, pull requests are created as merge requests
+* ` + "`" + `bitbucket` + "`" + `: Bitbucket Server`,
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			startTime = time.Now()
+			log.SetStepName(STEP_NAME)
+			log.SetVerbose(GeneralConfig.Verbose)
+
+			path, _ := os.Getwd()
+			fatalHook := &log.FatalHook{CorrelationID: GeneralConfig.CorrelationID, Path: path}
+			log.RegisterHook(fatalHook)
+
+			err := PrepareConfig(cmd, &metadata, STEP_NAME, &stepConfig, config.OpenPiperFile)
+			if err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+			log.RegisterSecret(stepConfig.GithubAppPrivateKey)
+			log.RegisterSecret(stepConfig.Token)
+
+			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
+				sentryHook := log.NewSentryHook(GeneralConfig.HookConfig.SentryConfig.Dsn, GeneralConfig.CorrelationID)
+				log.RegisterHook(&sentryHook)
+			}
+
+			return nil
+		},
+		Run: func(_ *cobra.Command, _ []string) {
+			telemetryData := telemetry.CustomData{}
+			telemetryData.ErrorCode = "1"
+			handler := func() {
+				config.RemoveVaultSecretFiles()
+				telemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
+				telemetryData.ErrorCategory = log.GetErrorCategory().String()
+				telemetry.Send(&telemetryData)
+			}
+			log.DeferExitHandler(handler)
+			defer handler()
+			telemetry.Initialize(GeneralConfig.NoTelemetry, STEP_NAME)
+			scmCheckBranchProtection(stepConfig, &telemetryData)
+			telemetryData.ErrorCode = "0"
+			log.Entry().Info("SUCCESS")
+		},
+	}
+
+	addScmCheckBranchProtectionFlags(createScmCheckBranchProtectionCmd, &stepConfig)
+	return createScmCheckBranchProtectionCmd
+}
+
+func addScmCheckBranchProtectionFlags(cmd *cobra.Command, stepConfig *scmCheckBranchProtectionOptions) {
+	cmd.Flags().StringVar(&stepConfig.APIURL, "apiUrl", os.Getenv("PIPER_apiUrl"), "URL of the REST API of the SCM provider. Defaults to `https://api.github.com` for GitHub and `https://gitlab.com/api/v4` for GitLab. For Bitbucket Server the URL of the server is required, e.g. `https://bitbucket.example.org`.")
+	cmd.Flags().StringVar(&stepConfig.Branch, "branch", `master`, "The name of the branch to check.")
+	cmd.Flags().IntVar(&stepConfig.GithubAppID, "githubAppId", 0, "GitHub only: ID of the GitHub App used to authenticate to GitHub instead of a token. The step authenticates as installation of the app, see [authenticating with GitHub Apps](https://docs.github.com/en/developers/apps/authenticating-with-github-apps).")
+	cmd.Flags().IntVar(&stepConfig.GithubAppInstallationID, "githubAppInstallationId", 0, "GitHub only: ID of the installation of the GitHub App. If not provided, the installation for `owner` and `repository` is looked up.")
+	cmd.Flags().StringVar(&stepConfig.GithubAppPrivateKey, "githubAppPrivateKey", os.Getenv("PIPER_githubAppPrivateKey"), "GitHub only: path to the file containing the PEM encoded private key of the GitHub App.")
+	cmd.Flags().StringVar(&stepConfig.Owner, "owner", os.Getenv("PIPER_owner"), "Name of the GitHub organization, the GitLab namespace (group including subgroups, or user) or the key of the Bitbucket project.")
+	cmd.Flags().StringVar(&stepConfig.Provider, "provider", `github`, "The source code management system hosting the repository.")
+	cmd.Flags().StringVar(&stepConfig.Repository, "repository", os.Getenv("PIPER_repository"), "Name of the repository.")
+	cmd.Flags().IntVar(&stepConfig.RequiredApprovingReviewCount, "requiredApprovingReviewCount", 0, "Check if the branch requires at least the given number of approvals before merging.")
+	cmd.Flags().StringSliceVar(&stepConfig.RequiredChecks, "requiredChecks", []string{}, "List of checks which have to be set to 'required' in the branch protection.")
+	cmd.Flags().BoolVar(&stepConfig.RequireEnforceAdmins, "requireEnforceAdmins", false, "Check if the branch protection also applies to administrators.")
+	cmd.Flags().StringVar(&stepConfig.Token, "token", os.Getenv("PIPER_token"), "Token to authenticate to the SCM provider, e.g. a GitHub or GitLab personal access token or a Bitbucket HTTP access token. Not required for GitHub in case a GitHub App is used for authentication (see `githubAppId`).")
+
+	cmd.MarkFlagRequired("branch")
+	cmd.MarkFlagRequired("owner")
+	cmd.MarkFlagRequired("repository")
+}
+
+// retrieve step metadata
+func scmCheckBranchProtectionMetadata() config.StepData {
+	var theMetaData = config.StepData{
+		Metadata: config.StepMetadata{
+			Name:        "scmCheckBranchProtection",
+			Aliases:     []config.Alias{},
+			Description: "Check branch protection of a GitHub, GitLab or Bitbucket branch.",
+		},
+		Spec: config.StepSpec{
+			Inputs: config.StepInputs{
+				Parameters: []config.StepParameters{
+					{
+						Name:        "apiUrl",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "branch",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   true,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "githubAppId",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "githubAppInstallationId",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name: "githubAppPrivateKey",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "githubAppPrivateKeyCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:  "",
+								Paths: []string{"$(vaultPath)/github-app-private-key", "$(vaultBasePath)/$(vaultPipelineName)/github-app-private-key", "$(vaultBasePath)/GROUP-SECRETS/github-app-private-key"},
+								Type:  "vaultSecretFile",
+							},
+						},
+						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+					},
+					{
+						Name: "owner",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "github/owner",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: true,
+						Aliases:   []config.Alias{{Name: "githubOrg"}},
+					},
+					{
+						Name:        "provider",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name: "repository",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "github/repository",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: true,
+						Aliases:   []config.Alias{{Name: "githubRepo"}},
+					},
+					{
+						Name:        "requiredApprovingReviewCount",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "requiredChecks",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "requireEnforceAdmins",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name: "token",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "scmTokenCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:  "",
+								Paths: []string{"$(vaultPath)/scm", "$(vaultBasePath)/$(vaultPipelineName)/scm", "$(vaultBasePath)/GROUP-SECRETS/scm"},
+								Type:  "vaultSecret",
+							},
+						},
+						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+					},
+				},
+			},
+		},
+	}
+	return theMetaData
+}
@@ -0,0 +1,17 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScmCheckBranchProtectionCommand(t *testing.T) {
+	t.Parallel()
+
+	testCmd := ScmCheckBranchProtectionCommand()
+
+	// only high level testing performed - details are tested in step generation procedure
+	assert.Equal(t, "scmCheckBranchProtection", testCmd.Use, "command name incorrect")
+
+}
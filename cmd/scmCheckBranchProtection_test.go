@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/scm"
+	"github.com/stretchr/testify/assert"
+)
+
+type scmBranchProtectionReaderMock struct {
+	branch     string
+	protection scm.BranchProtection
+	err        error
+}
+
+func (m *scmBranchProtectionReaderMock) GetBranchProtection(branch string) (scm.BranchProtection, error) {
+	m.branch = branch
+	return m.protection, m.err
+}
+
+func TestRunScmCheckBranchProtection(t *testing.T) {
+	t.Parallel()
+
+	protection := scm.BranchProtection{RequiredChecks: []string{"pipeline", "check2"}, EnforceAdmins: true, RequiredApprovingReviewCount: 1}
+
+	t.Run("all checks ok", func(t *testing.T) {
+		config := scmCheckBranchProtectionOptions{Branch: "main", RequiredChecks: []string{"pipeline"}, RequireEnforceAdmins: true, RequiredApprovingReviewCount: 1}
+		reader := scmBranchProtectionReaderMock{protection: protection}
+
+		err := runScmCheckBranchProtection(&config, &reader)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "main", reader.branch)
+	})
+
+	t.Run("error reading branch protection", func(t *testing.T) {
+		config := scmCheckBranchProtectionOptions{Branch: "main"}
+		reader := scmBranchProtectionReaderMock{err: fmt.Errorf("branch not protected")}
+
+		err := runScmCheckBranchProtection(&config, &reader)
+
+		assert.EqualError(t, err, "failed to read branch protection information: branch not protected")
+	})
+
+	t.Run("required check missing", func(t *testing.T) {
+		config := scmCheckBranchProtectionOptions{RequiredChecks: []string{"check3"}}
+
+		err := runScmCheckBranchProtection(&config, &scmBranchProtectionReaderMock{protection: protection})
+
+		assert.EqualError(t, err, "required status check 'check3' not found among 'pipeline,check2' in branch protection configuration")
+	})
+
+	t.Run("admins not enforced", func(t *testing.T) {
+		config := scmCheckBranchProtectionOptions{RequireEnforceAdmins: true}
+
+		err := runScmCheckBranchProtection(&config, &scmBranchProtectionReaderMock{})
+
+		assert.EqualError(t, err, "admins are not enforced in branch protection configuration")
+	})
+
+	t.Run("not enough reviewers", func(t *testing.T) {
+		config := scmCheckBranchProtectionOptions{RequiredApprovingReviewCount: 2}
+
+		err := runScmCheckBranchProtection(&config, &scmBranchProtectionReaderMock{protection: protection})
+
+		assert.EqualError(t, err, "not enough mandatory reviewers in branch protection configuration, expected at least 2, got 1")
+	})
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	piperGithub "github.com/SAP/jenkins-library/pkg/github"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/scm"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/pkg/errors"
+)
+
+type scmIssueCommenter interface {
+	Comment(number int, pullRequest bool, body string) error
+}
+
+func scmCommentIssue(config scmCommentIssueOptions, telemetryData *telemetry.CustomData) {
+	provider, err := scm.NewProvider(scm.Options{
+		Provider:   config.Provider,
+		APIURL:     config.APIURL,
+		Token:      config.Token,
+		Owner:      config.Owner,
+		Repository: config.Repository,
+		GitHubApp: piperGithub.AppOptions{
+			AppID:          config.GithubAppID,
+			InstallationID: config.GithubAppInstallationID,
+			PrivateKeyFile: config.GithubAppPrivateKey,
+		},
+	})
+	if err != nil {
+		log.Entry().WithError(err).Fatal("Failed to get SCM provider")
+	}
+	err = runScmCommentIssue(&config, provider)
+	if err != nil {
+		log.Entry().WithError(err).Fatal("Failed to comment on issue")
+	}
+}
+
+func runScmCommentIssue(config *scmCommentIssueOptions, commenter scmIssueCommenter) error {
+	err := commenter.Comment(config.Number, config.PullRequest, config.Body)
+	if err != nil {
+		return errors.Wrapf(err, "error occurred when creating comment on %v/%v", config.Owner, config.Repository)
+	}
+	log.Entry().Infof("Comment added to #%v of %v/%v", config.Number, config.Owner, config.Repository)
+	return nil
+}
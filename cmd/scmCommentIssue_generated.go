@@ -0,0 +1,252 @@
+// Code generated by piper's step-generator. DO NOT EDIT.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/spf13/cobra"
+)
+
+type scmCommentIssueOptions struct {
+	APIURL                  string `json:"apiUrl,omitempty"`
+	Body                    string `json:"body,omitempty"`
+	GithubAppID             int    `json:"githubAppId,omitempty"`
+	GithubAppInstallationID int    `json:"githubAppInstallationId,omitempty"`
+	GithubAppPrivateKey     string `json:"githubAppPrivateKey,omitempty"`
+	Number                  int    `json:"number,omitempty"`
+	Owner                   string `json:"owner,omitempty"`
+	Provider                string `json:"provider,omitempty"`
+	PullRequest             bool   `json:"pullRequest,omitempty"`
+	Repository              string `json:"repository,omitempty"`
+	Token                   string `json:"token,omitempty"`
+}
+
+// ScmCommentIssueCommand Comment on issues and pull requests on GitHub, GitLab or Bitbucket.
+func ScmCommentIssueCommand() *cobra.Command {
+	const STEP_NAME = "scmCommentIssue"
+
+	metadata := scmCommentIssueMetadata()
+	var stepConfig scmCommentIssueOptions
+	var startTime time.Time
+
+	var createScmCommentIssueCmd = &cobra.Command{
+		Use:   STEP_NAME,
+		Short: "Comment on issues and pull requests on GitHub, GitLab or Bitbucket.",
+		Long: `This step allows you to add comments to existing issues or pull requests independent of the source code management system.
+
+Since GitLab numbers issues and merge requests separately, set ` + "`" + `pullRequest` + "`" + ` in order to comment on a merge request.
+Bitbucket only supports comments on pull requests.
+
+The provider is selected via ` + "`" + `provider` + "`" + `:
+
+* ` + "`" + `github` + "`" + `: GitHub and GitHub Enterprise
+* ` + "`" + `gitlab` + "`" + `: GitLab, pull requests are created as merge requests
+* ` + "`" + `bitbucket` + "`" + `: Bitbucket Server`,
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			startTime = time.Now()
+			log.SetStepName(STEP_NAME)
+			log.SetVerbose(GeneralConfig.Verbose)
+
+			path, _ := os.Getwd()
+			fatalHook := &log.FatalHook{CorrelationID: GeneralConfig.CorrelationID, Path: path}
+			log.RegisterHook(fatalHook)
+
+			err := PrepareConfig(cmd, &metadata, STEP_NAME, &stepConfig, config.OpenPiperFile)
+			if err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+			log.RegisterSecret(stepConfig.GithubAppPrivateKey)
+			log.RegisterSecret(stepConfig.Token)
+
+			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
+				sentryHook := log.NewSentryHook(GeneralConfig.HookConfig.SentryConfig.Dsn, GeneralConfig.CorrelationID)
+				log.RegisterHook(&sentryHook)
+			}
+
+			return nil
+		},
+		Run: func(_ *cobra.Command, _ []string) {
+			telemetryData := telemetry.CustomData{}
+			telemetryData.ErrorCode = "1"
+			handler := func() {
+				config.RemoveVaultSecretFiles()
+				telemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
+				telemetryData.ErrorCategory = log.GetErrorCategory().String()
+				telemetry.Send(&telemetryData)
+			}
+			log.DeferExitHandler(handler)
+			defer handler()
+			telemetry.Initialize(GeneralConfig.NoTelemetry, STEP_NAME)
+			scmCommentIssue(stepConfig, &telemetryData)
+			telemetryData.ErrorCode = "0"
+			log.Entry().Info("SUCCESS")
+		},
+	}
+
+	addScmCommentIssueFlags(createScmCommentIssueCmd, &stepConfig)
+	return createScmCommentIssueCmd
+}
+
+func addScmCommentIssueFlags(cmd *cobra.Command, stepConfig *scmCommentIssueOptions) {
+	cmd.Flags().StringVar(&stepConfig.APIURL, "apiUrl", os.Getenv("PIPER_apiUrl"), "URL of the REST API of the SCM provider. Defaults to `https://api.github.com` for GitHub and `https://gitlab.com/api/v4` for GitLab. For Bitbucket Server the URL of the server is required, e.g. `https://bitbucket.example.org`.")
+	cmd.Flags().StringVar(&stepConfig.Body, "body", os.Getenv("PIPER_body"), "Defines the content of the comment, e.g. using markdown syntax.")
+	cmd.Flags().IntVar(&stepConfig.GithubAppID, "githubAppId", 0, "GitHub only: ID of the GitHub App used to authenticate to GitHub instead of a token. The step authenticates as installation of the app, see [authenticating with GitHub Apps](https://docs.github.com/en/developers/apps/authenticating-with-github-apps).")
+	cmd.Flags().IntVar(&stepConfig.GithubAppInstallationID, "githubAppInstallationId", 0, "GitHub only: ID of the installation of the GitHub App. If not provided, the installation for `owner` and `repository` is looked up.")
+	cmd.Flags().StringVar(&stepConfig.GithubAppPrivateKey, "githubAppPrivateKey", os.Getenv("PIPER_githubAppPrivateKey"), "GitHub only: path to the file containing the PEM encoded private key of the GitHub App.")
+	cmd.Flags().IntVar(&stepConfig.Number, "number", 0, "Defines the number of the issue/pull request.")
+	cmd.Flags().StringVar(&stepConfig.Owner, "owner", os.Getenv("PIPER_owner"), "Name of the GitHub organization, the GitLab namespace (group including subgroups, or user) or the key of the Bitbucket project.")
+	cmd.Flags().StringVar(&stepConfig.Provider, "provider", `github`, "The source code management system hosting the repository.")
+	cmd.Flags().BoolVar(&stepConfig.PullRequest, "pullRequest", false, "If set to `true` the comment is added to the pull request with the given number instead of the issue.")
+	cmd.Flags().StringVar(&stepConfig.Repository, "repository", os.Getenv("PIPER_repository"), "Name of the repository.")
+	cmd.Flags().StringVar(&stepConfig.Token, "token", os.Getenv("PIPER_token"), "Token to authenticate to the SCM provider, e.g. a GitHub or GitLab personal access token or a Bitbucket HTTP access token. Not required for GitHub in case a GitHub App is used for authentication (see `githubAppId`).")
+
+	cmd.MarkFlagRequired("body")
+	cmd.MarkFlagRequired("number")
+	cmd.MarkFlagRequired("owner")
+	cmd.MarkFlagRequired("repository")
+}
+
+// retrieve step metadata
+func scmCommentIssueMetadata() config.StepData {
+	var theMetaData = config.StepData{
+		Metadata: config.StepMetadata{
+			Name:        "scmCommentIssue",
+			Aliases:     []config.Alias{},
+			Description: "Comment on issues and pull requests on GitHub, GitLab or Bitbucket.",
+		},
+		Spec: config.StepSpec{
+			Inputs: config.StepInputs{
+				Parameters: []config.StepParameters{
+					{
+						Name:        "apiUrl",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "body",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   true,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "githubAppId",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "githubAppInstallationId",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name: "githubAppPrivateKey",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "githubAppPrivateKeyCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:  "",
+								Paths: []string{"$(vaultPath)/github-app-private-key", "$(vaultBasePath)/$(vaultPipelineName)/github-app-private-key", "$(vaultBasePath)/GROUP-SECRETS/github-app-private-key"},
+								Type:  "vaultSecretFile",
+							},
+						},
+						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+					},
+					{
+						Name:        "number",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   true,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name: "owner",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "github/owner",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: true,
+						Aliases:   []config.Alias{{Name: "githubOrg"}},
+					},
+					{
+						Name:        "provider",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "pullRequest",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name: "repository",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "github/repository",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: true,
+						Aliases:   []config.Alias{{Name: "githubRepo"}},
+					},
+					{
+						Name: "token",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "scmTokenCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:  "",
+								Paths: []string{"$(vaultPath)/scm", "$(vaultBasePath)/$(vaultPipelineName)/scm", "$(vaultBasePath)/GROUP-SECRETS/scm"},
+								Type:  "vaultSecret",
+							},
+						},
+						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+					},
+				},
+			},
+		},
+	}
+	return theMetaData
+}
@@ -0,0 +1,17 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScmCommentIssueCommand(t *testing.T) {
+	t.Parallel()
+
+	testCmd := ScmCommentIssueCommand()
+
+	// only high level testing performed - details are tested in step generation procedure
+	assert.Equal(t, "scmCommentIssue", testCmd.Use, "command name incorrect")
+
+}
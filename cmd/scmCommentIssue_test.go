@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type scmIssueCommenterMock struct {
+	number      int
+	pullRequest bool
+	body        string
+	err         error
+}
+
+func (m *scmIssueCommenterMock) Comment(number int, pullRequest bool, body string) error {
+	m.number = number
+	m.pullRequest = pullRequest
+	m.body = body
+	return m.err
+}
+
+func TestRunScmCommentIssue(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		config := scmCommentIssueOptions{Owner: "SAP", Repository: "jenkins-library", Number: 1, PullRequest: true, Body: "This is my test body"}
+		commenter := scmIssueCommenterMock{}
+
+		err := runScmCommentIssue(&config, &commenter)
+
+		assert.NoError(t, err)
+		assert.Equal(t, scmIssueCommenterMock{number: 1, pullRequest: true, body: "This is my test body"}, commenter)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		config := scmCommentIssueOptions{Owner: "SAP", Repository: "jenkins-library", Number: 1}
+		commenter := scmIssueCommenterMock{err: fmt.Errorf("issues is not supported by Bitbucket")}
+
+		err := runScmCommentIssue(&config, &commenter)
+
+		assert.EqualError(t, err, "error occurred when creating comment on SAP/jenkins-library: issues is not supported by Bitbucket")
+	})
+}
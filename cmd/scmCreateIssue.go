@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	piperGithub "github.com/SAP/jenkins-library/pkg/github"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/scm"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/pkg/errors"
+)
+
+type scmIssueCreator interface {
+	CreateIssue(issue scm.Issue) (scm.Reference, error)
+}
+
+func scmCreateIssue(config scmCreateIssueOptions, telemetryData *telemetry.CustomData) {
+	provider, err := scm.NewProvider(scm.Options{
+		Provider:   config.Provider,
+		APIURL:     config.APIURL,
+		Token:      config.Token,
+		Owner:      config.Owner,
+		Repository: config.Repository,
+		GitHubApp: piperGithub.AppOptions{
+			AppID:          config.GithubAppID,
+			InstallationID: config.GithubAppInstallationID,
+			PrivateKeyFile: config.GithubAppPrivateKey,
+		},
+	})
+	if err != nil {
+		log.Entry().WithError(err).Fatal("Failed to get SCM provider")
+	}
+	err = runScmCreateIssue(&config, provider, ioutil.ReadFile)
+	if err != nil {
+		log.Entry().WithError(err).Fatal("Failed to create issue")
+	}
+}
+
+func runScmCreateIssue(config *scmCreateIssueOptions, creator scmIssueCreator, readFile func(string) ([]byte, error)) error {
+	if len(config.Body)+len(config.BodyFilePath) == 0 {
+		return fmt.Errorf("either parameter `body` or parameter `bodyFilePath` is required")
+	}
+
+	issue := scm.Issue{Title: config.Title, Body: config.Body}
+	if len(config.Body) == 0 {
+		content, err := readFile(config.BodyFilePath)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read file '%v'", config.BodyFilePath)
+		}
+		issue.Body = string(content)
+	}
+
+	reference, err := creator.CreateIssue(issue)
+	if err != nil {
+		return errors.Wrapf(err, "error occurred when creating issue in %v/%v", config.Owner, config.Repository)
+	}
+	log.Entry().Infof("Issue #%v created: %v", reference.Number, reference.URL)
+	return nil
+}
@@ -0,0 +1,17 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScmCreateIssueCommand(t *testing.T) {
+	t.Parallel()
+
+	testCmd := ScmCreateIssueCommand()
+
+	// only high level testing performed - details are tested in step generation procedure
+	assert.Equal(t, "scmCreateIssue", testCmd.Use, "command name incorrect")
+
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/scm"
+	"github.com/stretchr/testify/assert"
+)
+
+type scmIssueCreatorMock struct {
+	issue scm.Issue
+	err   error
+}
+
+func (m *scmIssueCreatorMock) CreateIssue(issue scm.Issue) (scm.Reference, error) {
+	m.issue = issue
+	return scm.Reference{Number: 1, URL: "https://gitlab.com/SAP/jenkins-library/-/issues/1"}, m.err
+}
+
+func TestRunScmCreateIssue(t *testing.T) {
+	t.Parallel()
+
+	readFile := func(path string) ([]byte, error) {
+		if path == "issue.md" {
+			return []byte("Body from file"), nil
+		}
+		return nil, fmt.Errorf("file not found")
+	}
+
+	t.Run("body", func(t *testing.T) {
+		config := scmCreateIssueOptions{Owner: "SAP", Repository: "jenkins-library", Title: "Test issue", Body: "This is my test body"}
+		creator := scmIssueCreatorMock{}
+
+		err := runScmCreateIssue(&config, &creator, readFile)
+
+		assert.NoError(t, err)
+		assert.Equal(t, scm.Issue{Title: "Test issue", Body: "This is my test body"}, creator.issue)
+	})
+
+	t.Run("body from file", func(t *testing.T) {
+		config := scmCreateIssueOptions{Owner: "SAP", Repository: "jenkins-library", Title: "Test issue", BodyFilePath: "issue.md"}
+		creator := scmIssueCreatorMock{}
+
+		err := runScmCreateIssue(&config, &creator, readFile)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Body from file", creator.issue.Body)
+	})
+
+	t.Run("no body", func(t *testing.T) {
+		config := scmCreateIssueOptions{Title: "Test issue"}
+
+		err := runScmCreateIssue(&config, &scmIssueCreatorMock{}, readFile)
+
+		assert.EqualError(t, err, "either parameter `body` or parameter `bodyFilePath` is required")
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		config := scmCreateIssueOptions{Title: "Test issue", BodyFilePath: "missing.md"}
+
+		err := runScmCreateIssue(&config, &scmIssueCreatorMock{}, readFile)
+
+		assert.EqualError(t, err, "failed to read file 'missing.md': file not found")
+	})
+
+	t.Run("error", func(t *testing.T) {
+		config := scmCreateIssueOptions{Owner: "SAP", Repository: "jenkins-library", Title: "Test issue", Body: "body"}
+		creator := scmIssueCreatorMock{err: fmt.Errorf("issues is not supported by Bitbucket")}
+
+		err := runScmCreateIssue(&config, &creator, readFile)
+
+		assert.EqualError(t, err, "error occurred when creating issue in SAP/jenkins-library: issues is not supported by Bitbucket")
+	})
+}
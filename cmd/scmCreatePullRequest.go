@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	piperGithub "github.com/SAP/jenkins-library/pkg/github"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/scm"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/pkg/errors"
+)
+
+type scmPullRequestCreator interface {
+	CreatePullRequest(pullRequest scm.PullRequest) (scm.Reference, error)
+}
+
+func scmCreatePullRequest(config scmCreatePullRequestOptions, telemetryData *telemetry.CustomData) {
+	provider, err := scm.NewProvider(scm.Options{
+		Provider:   config.Provider,
+		APIURL:     config.APIURL,
+		Token:      config.Token,
+		Owner:      config.Owner,
+		Repository: config.Repository,
+		GitHubApp: piperGithub.AppOptions{
+			AppID:          config.GithubAppID,
+			InstallationID: config.GithubAppInstallationID,
+			PrivateKeyFile: config.GithubAppPrivateKey,
+		},
+	})
+	if err != nil {
+		log.Entry().WithError(err).Fatal("Failed to get SCM provider")
+	}
+	err = runScmCreatePullRequest(&config, provider)
+	if err != nil {
+		log.Entry().WithError(err).Fatal("Failed to create pull request")
+	}
+}
+
+func runScmCreatePullRequest(config *scmCreatePullRequestOptions, creator scmPullRequestCreator) error {
+	reference, err := creator.CreatePullRequest(scm.PullRequest{
+		Title:     config.Title,
+		Body:      config.Body,
+		Head:      config.Head,
+		Base:      config.Base,
+		Labels:    config.Labels,
+		Assignees: config.Assignees,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "error occurred when creating pull request in %v/%v", config.Owner, config.Repository)
+	}
+	log.Entry().Infof("Pull request #%v created: %v", reference.Number, reference.URL)
+	return nil
+}
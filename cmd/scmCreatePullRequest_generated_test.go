@@ -0,0 +1,17 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScmCreatePullRequestCommand(t *testing.T) {
+	t.Parallel()
+
+	testCmd := ScmCreatePullRequestCommand()
+
+	// only high level testing performed - details are tested in step generation procedure
+	assert.Equal(t, "scmCreatePullRequest", testCmd.Use, "command name incorrect")
+
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/scm"
+	"github.com/stretchr/testify/assert"
+)
+
+type scmPullRequestCreatorMock struct {
+	pullRequest scm.PullRequest
+	err         error
+}
+
+func (m *scmPullRequestCreatorMock) CreatePullRequest(pullRequest scm.PullRequest) (scm.Reference, error) {
+	m.pullRequest = pullRequest
+	return scm.Reference{Number: 1, URL: "https://gitlab.com/SAP/jenkins-library/-/merge_requests/1"}, m.err
+}
+
+func TestRunScmCreatePullRequest(t *testing.T) {
+	t.Parallel()
+
+	config := scmCreatePullRequestOptions{
+		Owner:      "SAP",
+		Repository: "jenkins-library",
+		Title:      "Test title",
+		Body:       "This is my test body",
+		Head:       "head/test",
+		Base:       "base/test",
+		Labels:     []string{"testLabel"},
+		Assignees:  []string{"testAssignee"},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		creator := scmPullRequestCreatorMock{}
+
+		err := runScmCreatePullRequest(&config, &creator)
+
+		assert.NoError(t, err)
+		assert.Equal(t, scm.PullRequest{Title: "Test title", Body: "This is my test body", Head: "head/test", Base: "base/test", Labels: []string{"testLabel"}, Assignees: []string{"testAssignee"}}, creator.pullRequest)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		creator := scmPullRequestCreatorMock{err: fmt.Errorf("failed to create merge request")}
+
+		err := runScmCreatePullRequest(&config, &creator)
+
+		assert.EqualError(t, err, "error occurred when creating pull request in SAP/jenkins-library: failed to create merge request")
+	})
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	piperGithub "github.com/SAP/jenkins-library/pkg/github"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/scm"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/pkg/errors"
+)
+
+type scmReleasePublisher interface {
+	PublishRelease(release scm.Release) (string, error)
+}
+
+func scmPublishRelease(config scmPublishReleaseOptions, telemetryData *telemetry.CustomData) {
+	provider, err := scm.NewProvider(scm.Options{
+		Provider:   config.Provider,
+		APIURL:     config.APIURL,
+		UploadURL:  config.UploadURL,
+		Token:      config.Token,
+		Owner:      config.Owner,
+		Repository: config.Repository,
+		GitHubApp: piperGithub.AppOptions{
+			AppID:          config.GithubAppID,
+			InstallationID: config.GithubAppInstallationID,
+			PrivateKeyFile: config.GithubAppPrivateKey,
+		},
+	})
+	if err != nil {
+		log.Entry().WithError(err).Fatal("Failed to get SCM provider")
+	}
+	err = runScmPublishRelease(&config, provider)
+	if err != nil {
+		log.Entry().WithError(err).Fatal("Failed to publish release")
+	}
+}
+
+func runScmPublishRelease(config *scmPublishReleaseOptions, publisher scmReleasePublisher) error {
+	releaseURL, err := publisher.PublishRelease(scm.Release{
+		TagName:    config.Version,
+		Name:       config.Version,
+		Body:       config.ReleaseBody,
+		Commitish:  config.Commitish,
+		PreRelease: config.PreRelease,
+		Assets:     config.AssetPaths,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to publish release '%v' in %v/%v", config.Version, config.Owner, config.Repository)
+	}
+	log.Entry().Infof("Release %v published: %v", config.Version, releaseURL)
+	return nil
+}
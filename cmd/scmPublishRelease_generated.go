@@ -0,0 +1,292 @@
+// Code generated by piper's step-generator. DO NOT EDIT.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/spf13/cobra"
+)
+
+type scmPublishReleaseOptions struct {
+	APIURL                  string   `json:"apiUrl,omitempty"`
+	AssetPaths              []string `json:"assetPaths,omitempty"`
+	Commitish               string   `json:"commitish,omitempty"`
+	GithubAppID             int      `json:"githubAppId,omitempty"`
+	GithubAppInstallationID int      `json:"githubAppInstallationId,omitempty"`
+	GithubAppPrivateKey     string   `json:"githubAppPrivateKey,omitempty"`
+	Owner                   string   `json:"owner,omitempty"`
+	PreRelease              bool     `json:"preRelease,omitempty"`
+	Provider                string   `json:"provider,omitempty"`
+	ReleaseBody             string   `json:"releaseBody,omitempty"`
+	Repository              string   `json:"repository,omitempty"`
+	Token                   string   `json:"token,omitempty"`
+	UploadURL               string   `json:"uploadUrl,omitempty"`
+	Version                 string   `json:"version,omitempty"`
+}
+
+// ScmPublishReleaseCommand Publish a release on GitHub, GitLab or Bitbucket.
+func ScmPublishReleaseCommand() *cobra.Command {
+	const STEP_NAME = "scmPublishRelease"
+
+	metadata := scmPublishReleaseMetadata()
+	var stepConfig scmPublishReleaseOptions
+	var startTime time.Time
+
+	var createScmPublishReleaseCmd = &cobra.Command{
+		Use:   STEP_NAME,
+		Short: "Publish a release on GitHub, GitLab or Bitbucket.",
+		Long: `This step creates a release for a tag in your repository independent of the source code management system.
+
+* On GitHub and GitLab a release is created, the tag is created from ` + "`" + `commitish` + "`" + ` in case it does not exist yet. Assets are attached to the release.
+* Bitbucket does not know releases, thus an annotated tag is created which contains the release name and body in its message. Assets are not supported.
+* Pre-releases are only supported by GitHub.
+
+The provider is selected via ` + "`" + `provider` + "`" + `:
+
+* ` + "`" + `github` + "`" + `: GitHub and GitHub Enterprise
+* ` + "`" + `gitlab` + "`" + `: GitLab, pull requests are created as merge requests
+* ` + "`" + `bitbucket` + "`" + `: Bitbucket Server`,
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			startTime = time.Now()
+			log.SetStepName(STEP_NAME)
+			log.SetVerbose(GeneralConfig.Verbose)
+
+			path, _ := os.Getwd()
+			fatalHook := &log.FatalHook{CorrelationID: GeneralConfig.CorrelationID, Path: path}
+			log.RegisterHook(fatalHook)
+
+			err := PrepareConfig(cmd, &metadata, STEP_NAME, &stepConfig, config.OpenPiperFile)
+			if err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+			log.RegisterSecret(stepConfig.GithubAppPrivateKey)
+			log.RegisterSecret(stepConfig.Token)
+
+			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
+				sentryHook := log.NewSentryHook(GeneralConfig.HookConfig.SentryConfig.Dsn, GeneralConfig.CorrelationID)
+				log.RegisterHook(&sentryHook)
+			}
+
+			return nil
+		},
+		Run: func(_ *cobra.Command, _ []string) {
+			telemetryData := telemetry.CustomData{}
+			telemetryData.ErrorCode = "1"
+			handler := func() {
+				config.RemoveVaultSecretFiles()
+				telemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
+				telemetryData.ErrorCategory = log.GetErrorCategory().String()
+				telemetry.Send(&telemetryData)
+			}
+			log.DeferExitHandler(handler)
+			defer handler()
+			telemetry.Initialize(GeneralConfig.NoTelemetry, STEP_NAME)
+			scmPublishRelease(stepConfig, &telemetryData)
+			telemetryData.ErrorCode = "0"
+			log.Entry().Info("SUCCESS")
+		},
+	}
+
+	addScmPublishReleaseFlags(createScmPublishReleaseCmd, &stepConfig)
+	return createScmPublishReleaseCmd
+}
+
+func addScmPublishReleaseFlags(cmd *cobra.Command, stepConfig *scmPublishReleaseOptions) {
+	cmd.Flags().StringVar(&stepConfig.APIURL, "apiUrl", os.Getenv("PIPER_apiUrl"), "URL of the REST API of the SCM provider. Defaults to `https://api.github.com` for GitHub and `https://gitlab.com/api/v4` for GitLab. For Bitbucket Server the URL of the server is required, e.g. `https://bitbucket.example.org`.")
+	cmd.Flags().StringSliceVar(&stepConfig.AssetPaths, "assetPaths", []string{}, "Paths to files which are attached to the release.")
+	cmd.Flags().StringVar(&stepConfig.Commitish, "commitish", `master`, "Target git commitish for the release, used in case the tag does not exist yet.")
+	cmd.Flags().IntVar(&stepConfig.GithubAppID, "githubAppId", 0, "GitHub only: ID of the GitHub App used to authenticate to GitHub instead of a token. The step authenticates as installation of the app, see [authenticating with GitHub Apps](https://docs.github.com/en/developers/apps/authenticating-with-github-apps).")
+	cmd.Flags().IntVar(&stepConfig.GithubAppInstallationID, "githubAppInstallationId", 0, "GitHub only: ID of the installation of the GitHub App. If not provided, the installation for `owner` and `repository` is looked up.")
+	cmd.Flags().StringVar(&stepConfig.GithubAppPrivateKey, "githubAppPrivateKey", os.Getenv("PIPER_githubAppPrivateKey"), "GitHub only: path to the file containing the PEM encoded private key of the GitHub App.")
+	cmd.Flags().StringVar(&stepConfig.Owner, "owner", os.Getenv("PIPER_owner"), "Name of the GitHub organization, the GitLab namespace (group including subgroups, or user) or the key of the Bitbucket project.")
+	cmd.Flags().BoolVar(&stepConfig.PreRelease, "preRelease", false, "If set to `true` the release will be marked as pre-release (GitHub only).")
+	cmd.Flags().StringVar(&stepConfig.Provider, "provider", `github`, "The source code management system hosting the repository.")
+	cmd.Flags().StringVar(&stepConfig.ReleaseBody, "releaseBody", os.Getenv("PIPER_releaseBody"), "Content which will appear for the release in markdown format.")
+	cmd.Flags().StringVar(&stepConfig.Repository, "repository", os.Getenv("PIPER_repository"), "Name of the repository.")
+	cmd.Flags().StringVar(&stepConfig.Token, "token", os.Getenv("PIPER_token"), "Token to authenticate to the SCM provider, e.g. a GitHub or GitLab personal access token or a Bitbucket HTTP access token. Not required for GitHub in case a GitHub App is used for authentication (see `githubAppId`).")
+	cmd.Flags().StringVar(&stepConfig.UploadURL, "uploadUrl", `https://uploads.github.com`, "Set the GitHub upload URL used for release assets.")
+	cmd.Flags().StringVar(&stepConfig.Version, "version", os.Getenv("PIPER_version"), "Define the version number which will be written as tag as well as release name.")
+
+	cmd.MarkFlagRequired("owner")
+	cmd.MarkFlagRequired("repository")
+	cmd.MarkFlagRequired("version")
+}
+
+// retrieve step metadata
+func scmPublishReleaseMetadata() config.StepData {
+	var theMetaData = config.StepData{
+		Metadata: config.StepMetadata{
+			Name:        "scmPublishRelease",
+			Aliases:     []config.Alias{},
+			Description: "Publish a release on GitHub, GitLab or Bitbucket.",
+		},
+		Spec: config.StepSpec{
+			Inputs: config.StepInputs{
+				Parameters: []config.StepParameters{
+					{
+						Name:        "apiUrl",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "assetPaths",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name: "commitish",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "git/commitId",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+					},
+					{
+						Name:        "githubAppId",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "githubAppInstallationId",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name: "githubAppPrivateKey",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "githubAppPrivateKeyCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:  "",
+								Paths: []string{"$(vaultPath)/github-app-private-key", "$(vaultBasePath)/$(vaultPipelineName)/github-app-private-key", "$(vaultBasePath)/GROUP-SECRETS/github-app-private-key"},
+								Type:  "vaultSecretFile",
+							},
+						},
+						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+					},
+					{
+						Name: "owner",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "github/owner",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: true,
+						Aliases:   []config.Alias{{Name: "githubOrg"}},
+					},
+					{
+						Name:        "preRelease",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "provider",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "releaseBody",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name: "repository",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "github/repository",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: true,
+						Aliases:   []config.Alias{{Name: "githubRepo"}},
+					},
+					{
+						Name: "token",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "scmTokenCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:  "",
+								Paths: []string{"$(vaultPath)/scm", "$(vaultBasePath)/$(vaultPipelineName)/scm", "$(vaultBasePath)/GROUP-SECRETS/scm"},
+								Type:  "vaultSecret",
+							},
+						},
+						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+					},
+					{
+						Name:        "uploadUrl",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{{Name: "githubUploadUrl"}},
+					},
+					{
+						Name: "version",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "artifactVersion",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: true,
+						Aliases:   []config.Alias{},
+					},
+				},
+			},
+		},
+	}
+	return theMetaData
+}
@@ -0,0 +1,17 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScmPublishReleaseCommand(t *testing.T) {
+	t.Parallel()
+
+	testCmd := ScmPublishReleaseCommand()
+
+	// only high level testing performed - details are tested in step generation procedure
+	assert.Equal(t, "scmPublishRelease", testCmd.Use, "command name incorrect")
+
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/scm"
+	"github.com/stretchr/testify/assert"
+)
+
+type scmReleasePublisherMock struct {
+	release scm.Release
+	err     error
+}
+
+func (m *scmReleasePublisherMock) PublishRelease(release scm.Release) (string, error) {
+	m.release = release
+	return "https://gitlab.com/SAP/jenkins-library/-/releases/1.0.0", m.err
+}
+
+func TestRunScmPublishRelease(t *testing.T) {
+	t.Parallel()
+
+	config := scmPublishReleaseOptions{
+		Owner:       "SAP",
+		Repository:  "jenkins-library",
+		Version:     "1.0.0",
+		Commitish:   "cd8ee54",
+		ReleaseBody: "Release notes",
+		PreRelease:  true,
+		AssetPaths:  []string{"app.zip"},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		publisher := scmReleasePublisherMock{}
+
+		err := runScmPublishRelease(&config, &publisher)
+
+		assert.NoError(t, err)
+		assert.Equal(t, scm.Release{TagName: "1.0.0", Name: "1.0.0", Body: "Release notes", Commitish: "cd8ee54", PreRelease: true, Assets: []string{"app.zip"}}, publisher.release)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		publisher := scmReleasePublisherMock{err: fmt.Errorf("release assets is not supported by Bitbucket")}
+
+		err := runScmPublishRelease(&config, &publisher)
+
+		assert.EqualError(t, err, "failed to publish release '1.0.0' in SAP/jenkins-library: release assets is not supported by Bitbucket")
+	})
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	piperGithub "github.com/SAP/jenkins-library/pkg/github"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/scm"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+)
+
+type scmCommitStatusSetter interface {
+	SetCommitStatus(status scm.CommitStatus) error
+}
+
+func scmSetCommitStatus(config scmSetCommitStatusOptions, telemetryData *telemetry.CustomData) {
+	provider, err := scm.NewProvider(scm.Options{
+		Provider:   config.Provider,
+		APIURL:     config.APIURL,
+		Token:      config.Token,
+		Owner:      config.Owner,
+		Repository: config.Repository,
+		GitHubApp: piperGithub.AppOptions{
+			AppID:          config.GithubAppID,
+			InstallationID: config.GithubAppInstallationID,
+			PrivateKeyFile: config.GithubAppPrivateKey,
+		},
+	})
+	if err != nil {
+		log.Entry().WithError(err).Fatal("Failed to get SCM provider")
+	}
+	err = runScmSetCommitStatus(&config, provider)
+	if err != nil {
+		log.Entry().WithError(err).Fatal("Commit status update failed")
+	}
+}
+
+func runScmSetCommitStatus(config *scmSetCommitStatusOptions, setter scmCommitStatusSetter) error {
+	return setter.SetCommitStatus(scm.CommitStatus{
+		CommitID:    config.CommitID,
+		Context:     config.Context,
+		Description: config.Description,
+		State:       config.Status,
+		TargetURL:   config.TargetURL,
+	})
+}
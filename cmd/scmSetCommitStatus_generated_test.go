@@ -0,0 +1,17 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScmSetCommitStatusCommand(t *testing.T) {
+	t.Parallel()
+
+	testCmd := ScmSetCommitStatusCommand()
+
+	// only high level testing performed - details are tested in step generation procedure
+	assert.Equal(t, "scmSetCommitStatus", testCmd.Use, "command name incorrect")
+
+}
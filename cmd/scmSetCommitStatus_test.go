@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/scm"
+	"github.com/stretchr/testify/assert"
+)
+
+type scmCommitStatusSetterMock struct {
+	status scm.CommitStatus
+	err    error
+}
+
+func (m *scmCommitStatusSetterMock) SetCommitStatus(status scm.CommitStatus) error {
+	m.status = status
+	return m.err
+}
+
+func TestRunScmSetCommitStatus(t *testing.T) {
+	t.Parallel()
+
+	config := scmSetCommitStatusOptions{
+		CommitID:    "testSha",
+		Context:     "test /context",
+		Description: "testDescription",
+		Status:      "success",
+		TargetURL:   "https://test.url",
+	}
+
+	t.Run("success", func(t *testing.T) {
+		setter := scmCommitStatusSetterMock{}
+
+		err := runScmSetCommitStatus(&config, &setter)
+
+		assert.NoError(t, err)
+		assert.Equal(t, scm.CommitStatus{CommitID: "testSha", Context: "test /context", Description: "testDescription", State: "success", TargetURL: "https://test.url"}, setter.status)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		setter := scmCommitStatusSetterMock{err: fmt.Errorf("failed to set status 'success' on commit 'testSha'")}
+
+		err := runScmSetCommitStatus(&config, &setter)
+
+		assert.EqualError(t, err, "failed to set status 'success' on commit 'testSha'")
+	})
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"fmt"
 	"io/ioutil"
 	"os"
 	"os/exec"
@@ -19,6 +20,7 @@ import (
 	FileUtils "github.com/SAP/jenkins-library/pkg/piperutils"
 	SliceUtils "github.com/SAP/jenkins-library/pkg/piperutils"
 	StepResults "github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/reporting"
 	SonarUtils "github.com/SAP/jenkins-library/pkg/sonar"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
 )
@@ -240,7 +242,52 @@ func runSonar(config sonarExecuteScanOptions, client piperhttp.Downloader, runne
 	if err != nil {
 		return err
 	}
-	return nil
+	scanReport := reporting.ScanReport{
+		StepName: "sonarExecuteScan",
+		Title:    "SonarQube Report",
+		Overview: []reporting.OverviewRow{
+			{Description: "Blocker issues", Details: fmt.Sprint(influx.sonarqube_data.fields.blocker_issues)},
+			{Description: "Critical issues", Details: fmt.Sprint(influx.sonarqube_data.fields.critical_issues)},
+			{Description: "Major issues", Details: fmt.Sprint(influx.sonarqube_data.fields.major_issues)},
+			{Description: "Minor issues", Details: fmt.Sprint(influx.sonarqube_data.fields.minor_issues)},
+			{Description: "Info issues", Details: fmt.Sprint(influx.sonarqube_data.fields.info_issues)},
+		},
+		FurtherInfo: taskReport.DashboardURL,
+		ReportTime:  time.Now(),
+		// the scan itself does not evaluate a quality gate, blocker and critical issues are considered as failure
+		SuccessfulScan: influx.sonarqube_data.fields.blocker_issues == 0 && influx.sonarqube_data.fields.critical_issues == 0,
+	}
+	if len(config.ChangeID) > 0 {
+		// locate the issues in the source code for decorating the pull request
+		scanReport.Findings = sonarFindings(issueService, taskReport)
+	}
+	return reporting.WriteJSONReport(scanReport, workspaceFiles{workspace: sonar.workingDir})
+}
+
+// sonarFindings returns the unresolved issues which can be located in the source code.
+// The findings are optional, therefore a failure to retrieve them only results in a warning.
+func sonarFindings(issueService *SonarUtils.IssueService, taskReport SonarUtils.TaskReportData) []reporting.Finding {
+	issues, err := issueService.GetUnresolvedIssues()
+	if err != nil {
+		log.Entry().WithError(err).Warn("failed to retrieve the issues for decorating the pull request")
+		return nil
+	}
+	findings := []reporting.Finding{}
+	for _, issue := range issues {
+		separator := strings.Index(issue.Component, ":")
+		if separator < 0 || issue.Line == 0 {
+			continue
+		}
+		findings = append(findings, reporting.Finding{
+			Rule:     issue.Rule,
+			Severity: issue.Severity,
+			Message:  issue.Message,
+			File:     issue.Component[separator+1:],
+			Line:     issue.Line,
+			Link:     fmt.Sprintf("%v/project/issues?id=%v&open=%v", strings.TrimSuffix(taskReport.ServerURL, "/"), taskReport.ProjectKey, issue.Key),
+		})
+	}
+	return findings
 }
 
 // isInOptions returns true, if the given property is already provided in config.Options.
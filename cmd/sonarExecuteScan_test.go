@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -18,10 +19,11 @@ import (
 	piperHttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/mock"
 	FileUtils "github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/reporting"
 	SonarUtils "github.com/SAP/jenkins-library/pkg/sonar"
 )
 
-//TODO: extract to mock package
+// TODO: extract to mock package
 type mockDownloader struct {
 	shouldFail    bool
 	requestedURL  []string
@@ -158,6 +160,100 @@ func TestRunSonar(t *testing.T) {
 		assert.Contains(t, sonar.environment, "SONAR_SCANNER_OPTS=-Djavax.net.ssl.trustStore="+filepath.Join(getWorkingDir(), ".certificates", "cacerts")+" -Djavax.net.ssl.trustStorePassword=changeit")
 		assert.FileExists(t, filepath.Join(sonar.workingDir, "sonarExecuteScan_reports.json"))
 		assert.FileExists(t, filepath.Join(sonar.workingDir, "sonarExecuteScan_links.json"))
+		scanReports, _ := filepath.Glob(filepath.Join(sonar.workingDir, reporting.StepReportDirectory, "sonarExecuteScan_*.json"))
+		assert.Len(t, scanReports, 1)
+	})
+	t.Run("pull request", func(t *testing.T) {
+		// init
+		tmpFolder, err := ioutil.TempDir(".", "test-sonar-")
+		require.NoError(t, err)
+		defer os.RemoveAll(tmpFolder)
+		createTaskReportFile(t, tmpFolder)
+
+		sonar = sonarSettings{
+			workingDir:  tmpFolder,
+			binary:      "sonar-scanner",
+			environment: []string{},
+			options:     []string{},
+		}
+		options := sonarExecuteScanOptions{
+			Token:               "secret-ABC",
+			ChangeID:            "42",
+			PullRequestProvider: "GitHub",
+		}
+		fileUtilsExists = mockFileUtilsExists(true)
+		httpmock.RegisterResponder(http.MethodGet, sonarServerURL+"/api/"+SonarUtils.EndpointIssuesSearch+"", func(req *http.Request) (*http.Response, error) {
+			if req.URL.Query().Get("ps") != "500" {
+				return httpmock.NewStringResponse(http.StatusOK, `{ "total": 1 }`), nil
+			}
+			return httpmock.NewStringResponse(http.StatusOK, `{ "total": 2, "issues": [
+				{ "key": "AX1", "rule": "go:S1234", "severity": "MAJOR", "component": "piper-test:cmd/main.go", "line": 12, "message": "fix me" },
+				{ "key": "AX2", "rule": "go:S5678", "severity": "MINOR", "component": "piper-test" }
+			]}`), nil
+		})
+		defer func() {
+			fileUtilsExists = FileUtils.FileExists
+			httpmock.RegisterResponder(http.MethodGet, sonarServerURL+"/api/"+SonarUtils.EndpointIssuesSearch+"", httpmock.NewStringResponder(http.StatusOK, `{ "total": 0 }`))
+		}()
+		// test
+		err = runSonar(options, &mockDownloadClient, &mockRunner, apiClient, &sonarExecuteScanInflux{})
+		// assert
+		assert.NoError(t, err)
+		scanReports, _ := filepath.Glob(filepath.Join(sonar.workingDir, reporting.StepReportDirectory, "sonarExecuteScan_*.json"))
+		require.Len(t, scanReports, 1)
+		content, err := ioutil.ReadFile(scanReports[0])
+		require.NoError(t, err)
+		var scanReport reporting.ScanReport
+		require.NoError(t, json.Unmarshal(content, &scanReport))
+		assert.False(t, scanReport.SuccessfulScan)
+		assert.Equal(t, []reporting.Finding{
+			{Rule: "go:S1234", Severity: "MAJOR", Message: "fix me", File: "cmd/main.go", Line: 12, Link: sonarServerURL + "/project/issues?id=piper-test&open=AX1"},
+		}, scanReport.Findings)
+	})
+	t.Run("pull request - issues not available", func(t *testing.T) {
+		// init
+		tmpFolder, err := ioutil.TempDir(".", "test-sonar-")
+		require.NoError(t, err)
+		defer os.RemoveAll(tmpFolder)
+		createTaskReportFile(t, tmpFolder)
+
+		sonar = sonarSettings{
+			workingDir:  tmpFolder,
+			binary:      "sonar-scanner",
+			environment: []string{},
+			options:     []string{},
+		}
+		options := sonarExecuteScanOptions{
+			Token:               "secret-ABC",
+			ChangeID:            "42",
+			PullRequestProvider: "GitHub",
+		}
+		fileUtilsExists = mockFileUtilsExists(true)
+		httpmock.RegisterResponder(http.MethodGet, sonarServerURL+"/api/"+SonarUtils.EndpointIssuesSearch+"", func(req *http.Request) (*http.Response, error) {
+			if req.URL.Query().Get("ps") != "500" {
+				return httpmock.NewStringResponse(http.StatusOK, `{ "total": 0 }`), nil
+			}
+			response := httpmock.NewStringResponse(http.StatusInternalServerError, `{}`)
+			// the error handling of the http client requires the request
+			response.Request = req
+			return response, nil
+		})
+		defer func() {
+			fileUtilsExists = FileUtils.FileExists
+			httpmock.RegisterResponder(http.MethodGet, sonarServerURL+"/api/"+SonarUtils.EndpointIssuesSearch+"", httpmock.NewStringResponder(http.StatusOK, `{ "total": 0 }`))
+		}()
+		// test
+		err = runSonar(options, &mockDownloadClient, &mockRunner, apiClient, &sonarExecuteScanInflux{})
+		// assert
+		assert.NoError(t, err)
+		scanReports, _ := filepath.Glob(filepath.Join(sonar.workingDir, reporting.StepReportDirectory, "sonarExecuteScan_*.json"))
+		require.Len(t, scanReports, 1)
+		content, err := ioutil.ReadFile(scanReports[0])
+		require.NoError(t, err)
+		var scanReport reporting.ScanReport
+		require.NoError(t, json.Unmarshal(content, &scanReport))
+		assert.True(t, scanReport.SuccessfulScan)
+		assert.Empty(t, scanReport.Findings)
 	})
 	t.Run("with custom options", func(t *testing.T) {
 		// init
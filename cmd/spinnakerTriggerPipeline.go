@@ -0,0 +1,201 @@
+package cmd
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/spinnaker"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/pkg/errors"
+)
+
+const spinnakerReportFile = "spinnakerExecution.json"
+
+// spinnakerParameterReference matches the references to configuration values and environment variables in pipeline parameters, e.g. ${env.BUILD_NUMBER}
+var spinnakerParameterReference = regexp.MustCompile(`\$\{\s*(config|env)\.([\w.]+)\s*\}`)
+
+type spinnakerTriggerPipelineUtils interface {
+	FileRead(path string) ([]byte, error)
+	FileWrite(path string, content []byte, perm os.FileMode) error
+}
+
+type spinnakerClient interface {
+	TriggerPipeline(application, pipelineNameOrID string, parameters map[string]interface{}) (string, error)
+	WaitForExecution(ref string, timeout, pollInterval time.Duration) (*spinnaker.Execution, error)
+}
+
+type spinnakerTriggerPipelineUtilsBundle struct {
+	*piperutils.Files
+}
+
+func newSpinnakerTriggerPipelineUtils() spinnakerTriggerPipelineUtils {
+	return &spinnakerTriggerPipelineUtilsBundle{
+		Files: &piperutils.Files{},
+	}
+}
+
+func spinnakerTriggerPipeline(config spinnakerTriggerPipelineOptions, telemetryData *telemetry.CustomData) {
+	utils := newSpinnakerTriggerPipelineUtils()
+
+	httpClient := &piperhttp.Client{}
+	clientOptions, err := spinnakerClientOptions(&config, utils)
+	if err != nil {
+		log.Entry().WithError(err).Fatal("step execution failed")
+	}
+	httpClient.SetOptions(clientOptions)
+
+	client := spinnaker.NewClient(config.GateURL, httpClient)
+
+	reports, err := runSpinnakerTriggerPipeline(&config, client, utils)
+	piperutils.PersistReportsAndLinks("spinnakerTriggerPipeline", "", reports, nil)
+	if err != nil {
+		log.Entry().WithError(err).Fatal("step execution failed")
+	}
+}
+
+func spinnakerClientOptions(config *spinnakerTriggerPipelineOptions, utils spinnakerTriggerPipelineUtils) (piperhttp.ClientOptions, error) {
+	options := piperhttp.ClientOptions{}
+
+	if len(config.ClientCertificate) > 0 || len(config.ClientKey) > 0 {
+		if len(config.ClientCertificate) == 0 || len(config.ClientKey) == 0 {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return options, fmt.Errorf("client certificate authentication requires both 'clientCertificate' and 'clientKey'")
+		}
+		certPEM, err := utils.FileRead(config.ClientCertificate)
+		if err != nil {
+			return options, errors.Wrapf(err, "failed to read client certificate '%s'", config.ClientCertificate)
+		}
+		keyPEM, err := utils.FileRead(config.ClientKey)
+		if err != nil {
+			return options, errors.Wrapf(err, "failed to read client key '%s'", config.ClientKey)
+		}
+		certificate, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return options, errors.Wrap(err, "failed to load client certificate")
+		}
+		options.Certificates = []tls.Certificate{certificate}
+	} else if len(config.Token) > 0 {
+		options.Token = "Bearer " + config.Token
+	} else {
+		log.Entry().Warn("Neither client certificate nor token provided, calling Spinnaker without authentication.")
+	}
+	return options, nil
+}
+
+func runSpinnakerTriggerPipeline(config *spinnakerTriggerPipelineOptions, client spinnakerClient, utils spinnakerTriggerPipelineUtils) ([]piperutils.Path, error) {
+	parameters, err := resolvePipelineParameters(config, os.Getenv)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return nil, err
+	}
+
+	ref, err := client.TriggerPipeline(config.Application, config.PipelineNameOrID, parameters)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorService)
+		return nil, err
+	}
+
+	if config.Timeout == 0 {
+		log.Entry().Infof("Spinnaker pipeline %s triggered, exiting without waiting for the pipeline result.", ref)
+		return nil, nil
+	}
+
+	log.Entry().Infof("Spinnaker pipeline %s triggered, waiting for the pipeline to finish", ref)
+	execution, err := client.WaitForExecution(ref, time.Duration(config.Timeout)*time.Minute, time.Duration(config.PollInterval)*time.Second)
+	if execution == nil {
+		log.SetErrorCategory(log.ErrorService)
+		return nil, err
+	}
+
+	logSpinnakerStages(execution)
+
+	reports := []piperutils.Path{}
+	report, marshalErr := json.MarshalIndent(execution, "", "  ")
+	if marshalErr != nil {
+		return reports, errors.Wrap(marshalErr, "failed to marshal execution report")
+	}
+	if writeErr := utils.FileWrite(spinnakerReportFile, report, 0666); writeErr != nil {
+		return reports, errors.Wrapf(writeErr, "failed to write report '%s'", spinnakerReportFile)
+	}
+	reports = append(reports, piperutils.Path{Target: spinnakerReportFile, Name: "Spinnaker execution"})
+
+	if err != nil {
+		log.SetErrorCategory(log.ErrorService)
+		return reports, err
+	}
+	if !execution.Succeeded() {
+		log.SetErrorCategory(log.ErrorService)
+		return reports, fmt.Errorf("Spinnaker pipeline failed with %s", execution.Status)
+	}
+	return reports, nil
+}
+
+func logSpinnakerStages(execution *spinnaker.Execution) {
+	for _, stage := range execution.Stages {
+		entry := log.Entry().WithField("type", stage.Type)
+		if stage.StartTime > 0 && stage.EndTime > 0 {
+			entry = entry.WithField("duration", time.Duration(stage.EndTime-stage.StartTime)*time.Millisecond)
+		}
+		entry.Infof("Stage '%s': %s", stage.Name, stage.Status)
+	}
+}
+
+// resolvePipelineParameters replaces references like ${config.application} or ${env.BUILD_NUMBER} in the pipeline parameters
+func resolvePipelineParameters(config *spinnakerTriggerPipelineOptions, getenv func(string) string) (map[string]interface{}, error) {
+	// secrets are not available for references
+	configValues := map[string]string{
+		"application":      config.Application,
+		"gateUrl":          config.GateURL,
+		"pipelineNameOrId": config.PipelineNameOrID,
+		"timeout":          fmt.Sprint(config.Timeout),
+	}
+	var resolveErr error
+	resolve := func(value string) string {
+		return spinnakerParameterReference.ReplaceAllStringFunc(value, func(reference string) string {
+			match := spinnakerParameterReference.FindStringSubmatch(reference)
+			if match[1] == "env" {
+				return getenv(match[2])
+			}
+			configValue, ok := configValues[strings.TrimPrefix(match[2], "spinnaker.")]
+			if !ok && resolveErr == nil {
+				resolveErr = fmt.Errorf("pipeline parameter references unknown configuration value '%s'", match[2])
+			}
+			return configValue
+		})
+	}
+
+	parameters, _ := resolveParameterValue(config.PipelineParameters, resolve).(map[string]interface{})
+	return parameters, resolveErr
+}
+
+func resolveParameterValue(value interface{}, resolve func(string) string) interface{} {
+	switch typed := value.(type) {
+	case string:
+		return resolve(typed)
+	case map[string]interface{}:
+		if typed == nil {
+			return typed
+		}
+		resolved := map[string]interface{}{}
+		for key, nested := range typed {
+			resolved[key] = resolveParameterValue(nested, resolve)
+		}
+		return resolved
+	case []interface{}:
+		resolved := []interface{}{}
+		for _, nested := range typed {
+			resolved = append(resolved, resolveParameterValue(nested, resolve))
+		}
+		return resolved
+	}
+	return value
+}
@@ -0,0 +1,230 @@
+// Code generated by piper's step-generator. DO NOT EDIT.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/spf13/cobra"
+)
+
+type spinnakerTriggerPipelineOptions struct {
+	GateURL            string                 `json:"gateUrl,omitempty"`
+	Application        string                 `json:"application,omitempty"`
+	PipelineNameOrID   string                 `json:"pipelineNameOrId,omitempty"`
+	PipelineParameters map[string]interface{} `json:"pipelineParameters,omitempty"`
+	ClientCertificate  string                 `json:"clientCertificate,omitempty"`
+	ClientKey          string                 `json:"clientKey,omitempty"`
+	Token              string                 `json:"token,omitempty"`
+	Timeout            int                    `json:"timeout,omitempty"`
+	PollInterval       int                    `json:"pollInterval,omitempty"`
+}
+
+// SpinnakerTriggerPipelineCommand Triggers a Spinnaker pipeline and waits for its completion.
+func SpinnakerTriggerPipelineCommand() *cobra.Command {
+	const STEP_NAME = "spinnakerTriggerPipeline"
+
+	metadata := spinnakerTriggerPipelineMetadata()
+	var stepConfig spinnakerTriggerPipelineOptions
+	var startTime time.Time
+
+	var createSpinnakerTriggerPipelineCmd = &cobra.Command{
+		Use:   STEP_NAME,
+		Short: "Triggers a Spinnaker pipeline and waits for its completion.",
+		Long: `Triggers a [Spinnaker](https://spinnaker.io) pipeline via the Spinnaker Gate API.
+Spinnaker is for example used for Continuous Deployment scenarios to various Clouds.
+
+Authentication against the Spinnaker Gate is possible either via a client certificate (x509) or via a token.
+
+After the pipeline has been triggered the execution is polled until it is finished or the timeout is reached.
+The outcome of the individual stages is written to the log as well as to the report file ` + "`" + `spinnakerExecution.json` + "`" + `.`,
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			startTime = time.Now()
+			log.SetStepName(STEP_NAME)
+			log.SetVerbose(GeneralConfig.Verbose)
+
+			path, _ := os.Getwd()
+			fatalHook := &log.FatalHook{CorrelationID: GeneralConfig.CorrelationID, Path: path}
+			log.RegisterHook(fatalHook)
+
+			err := PrepareConfig(cmd, &metadata, STEP_NAME, &stepConfig, config.OpenPiperFile)
+			if err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+			log.RegisterSecret(stepConfig.ClientCertificate)
+			log.RegisterSecret(stepConfig.ClientKey)
+			log.RegisterSecret(stepConfig.Token)
+
+			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
+				sentryHook := log.NewSentryHook(GeneralConfig.HookConfig.SentryConfig.Dsn, GeneralConfig.CorrelationID)
+				log.RegisterHook(&sentryHook)
+			}
+
+			return nil
+		},
+		Run: func(_ *cobra.Command, _ []string) {
+			telemetryData := telemetry.CustomData{}
+			telemetryData.ErrorCode = "1"
+			handler := func() {
+				config.RemoveVaultSecretFiles()
+				telemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
+				telemetryData.ErrorCategory = log.GetErrorCategory().String()
+				telemetry.Send(&telemetryData)
+			}
+			log.DeferExitHandler(handler)
+			defer handler()
+			telemetry.Initialize(GeneralConfig.NoTelemetry, STEP_NAME)
+			spinnakerTriggerPipeline(stepConfig, &telemetryData)
+			telemetryData.ErrorCode = "0"
+			log.Entry().Info("SUCCESS")
+		},
+	}
+
+	addSpinnakerTriggerPipelineFlags(createSpinnakerTriggerPipelineCmd, &stepConfig)
+	return createSpinnakerTriggerPipelineCmd
+}
+
+func addSpinnakerTriggerPipelineFlags(cmd *cobra.Command, stepConfig *spinnakerTriggerPipelineOptions) {
+	cmd.Flags().StringVar(&stepConfig.GateURL, "gateUrl", os.Getenv("PIPER_gateUrl"), "Defines the url of the Spinnaker Gateway Service as API endpoint for communication with Spinnaker.")
+	cmd.Flags().StringVar(&stepConfig.Application, "application", os.Getenv("PIPER_application"), "Defines the name of the Spinnaker application.")
+	cmd.Flags().StringVar(&stepConfig.PipelineNameOrID, "pipelineNameOrId", os.Getenv("PIPER_pipelineNameOrId"), "Defines the name/id of the Spinnaker pipeline.")
+
+	cmd.Flags().StringVar(&stepConfig.ClientCertificate, "clientCertificate", os.Getenv("PIPER_clientCertificate"), "Defines the path to the client certificate file (PEM) for Spinnaker authentication.")
+	cmd.Flags().StringVar(&stepConfig.ClientKey, "clientKey", os.Getenv("PIPER_clientKey"), "Defines the path to the private key file (PEM) belonging to the client certificate.")
+	cmd.Flags().StringVar(&stepConfig.Token, "token", os.Getenv("PIPER_token"), "Defines the token used for Spinnaker authentication. The token is sent as bearer token.")
+	cmd.Flags().IntVar(&stepConfig.Timeout, "timeout", 60, "Defines the timeout in minutes for checking the Spinnaker pipeline result. By setting to `0` the check can be de-activated.")
+	cmd.Flags().IntVar(&stepConfig.PollInterval, "pollInterval", 10, "Defines the interval in seconds between two status requests for the pipeline execution.")
+
+	cmd.MarkFlagRequired("gateUrl")
+	cmd.MarkFlagRequired("application")
+	cmd.MarkFlagRequired("pipelineNameOrId")
+}
+
+// retrieve step metadata
+func spinnakerTriggerPipelineMetadata() config.StepData {
+	var theMetaData = config.StepData{
+		Metadata: config.StepMetadata{
+			Name:        "spinnakerTriggerPipeline",
+			Aliases:     []config.Alias{},
+			Description: "Triggers a Spinnaker pipeline and waits for its completion.",
+		},
+		Spec: config.StepSpec{
+			Inputs: config.StepInputs{
+				Parameters: []config.StepParameters{
+					{
+						Name:        "gateUrl",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   true,
+						Aliases:     []config.Alias{{Name: "spinnaker/gateUrl"}, {Name: "spinnakerGateUrl"}},
+					},
+					{
+						Name:        "application",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   true,
+						Aliases:     []config.Alias{{Name: "spinnaker/application"}, {Name: "spinnakerApplication"}},
+					},
+					{
+						Name:        "pipelineNameOrId",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   true,
+						Aliases:     []config.Alias{{Name: "spinnaker/pipelineNameOrId"}, {Name: "spinnakerPipeline"}},
+					},
+					{
+						Name:        "pipelineParameters",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "map[string]interface{}",
+						Mandatory:   false,
+						Aliases:     []config.Alias{{Name: "spinnaker/pipelineParameters"}},
+					},
+					{
+						Name: "clientCertificate",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "certFileCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:  "",
+								Paths: []string{"$(vaultPath)/spinnaker-client-certificate", "$(vaultBasePath)/$(vaultPipelineName)/spinnaker-client-certificate", "$(vaultBasePath)/GROUP-SECRETS/spinnaker-client-certificate"},
+								Type:  "vaultSecretFile",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+					},
+					{
+						Name: "clientKey",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "keyFileCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:  "",
+								Paths: []string{"$(vaultPath)/spinnaker-client-key", "$(vaultBasePath)/$(vaultPipelineName)/spinnaker-client-key", "$(vaultBasePath)/GROUP-SECRETS/spinnaker-client-key"},
+								Type:  "vaultSecretFile",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+					},
+					{
+						Name: "token",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "tokenCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:  "",
+								Paths: []string{"$(vaultPath)/spinnaker", "$(vaultBasePath)/$(vaultPipelineName)/spinnaker", "$(vaultBasePath)/GROUP-SECRETS/spinnaker"},
+								Type:  "vaultSecret",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+					},
+					{
+						Name:        "timeout",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "pollInterval",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+				},
+			},
+		},
+	}
+	return theMetaData
+}
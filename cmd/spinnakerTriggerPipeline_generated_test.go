@@ -0,0 +1,17 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpinnakerTriggerPipelineCommand(t *testing.T) {
+	t.Parallel()
+
+	testCmd := SpinnakerTriggerPipelineCommand()
+
+	// only high level testing performed - details are tested in step generation procedure
+	assert.Equal(t, "spinnakerTriggerPipeline", testCmd.Use, "command name incorrect")
+
+}
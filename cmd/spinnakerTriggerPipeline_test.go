@@ -0,0 +1,231 @@
+package cmd
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/SAP/jenkins-library/pkg/spinnaker"
+	"github.com/stretchr/testify/assert"
+)
+
+type spinnakerClientMock struct {
+	triggeredApplication string
+	triggeredPipeline    string
+	triggeredParameters  map[string]interface{}
+	triggerError         error
+	waitedTimeout        time.Duration
+	waitedPollInterval   time.Duration
+	waitCalled           bool
+	execution            *spinnaker.Execution
+	waitError            error
+}
+
+func (c *spinnakerClientMock) TriggerPipeline(application, pipelineNameOrID string, parameters map[string]interface{}) (string, error) {
+	c.triggeredApplication = application
+	c.triggeredPipeline = pipelineNameOrID
+	c.triggeredParameters = parameters
+	return "/pipelines/01EXAMPLE", c.triggerError
+}
+
+func (c *spinnakerClientMock) WaitForExecution(ref string, timeout, pollInterval time.Duration) (*spinnaker.Execution, error) {
+	c.waitCalled = true
+	c.waitedTimeout = timeout
+	c.waitedPollInterval = pollInterval
+	return c.execution, c.waitError
+}
+
+func TestRunSpinnakerTriggerPipeline(t *testing.T) {
+	t.Parallel()
+
+	config := spinnakerTriggerPipelineOptions{
+		Application:        "myApp",
+		PipelineNameOrID:   "deploy",
+		PipelineParameters: map[string]interface{}{"version": "1.2.3"},
+		Timeout:            5,
+		PollInterval:       10,
+	}
+
+	t.Run("success", func(t *testing.T) {
+		client := &spinnakerClientMock{execution: &spinnaker.Execution{
+			Status: "SUCCEEDED",
+			Stages: []spinnaker.Stage{{Name: "Deploy", Type: "deploy", Status: "SUCCEEDED", StartTime: 1000, EndTime: 5000}},
+		}}
+		utils := &mock.FilesMock{}
+
+		reports, err := runSpinnakerTriggerPipeline(&config, client, utils)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, "myApp", client.triggeredApplication)
+			assert.Equal(t, "deploy", client.triggeredPipeline)
+			assert.Equal(t, map[string]interface{}{"version": "1.2.3"}, client.triggeredParameters)
+			assert.Equal(t, 5*time.Minute, client.waitedTimeout)
+			assert.Equal(t, 10*time.Second, client.waitedPollInterval)
+			assert.Len(t, reports, 1)
+			report, _ := utils.FileRead(spinnakerReportFile)
+			assert.Contains(t, string(report), `"name": "Deploy"`)
+		}
+	})
+
+	t.Run("no waiting", func(t *testing.T) {
+		client := &spinnakerClientMock{}
+		c := config
+		c.Timeout = 0
+
+		reports, err := runSpinnakerTriggerPipeline(&c, client, &mock.FilesMock{})
+
+		assert.NoError(t, err)
+		assert.False(t, client.waitCalled)
+		assert.Empty(t, reports)
+	})
+
+	t.Run("trigger fails", func(t *testing.T) {
+		client := &spinnakerClientMock{triggerError: fmt.Errorf("trigger failed")}
+
+		_, err := runSpinnakerTriggerPipeline(&config, client, &mock.FilesMock{})
+
+		assert.EqualError(t, err, "trigger failed")
+		assert.False(t, client.waitCalled)
+	})
+
+	t.Run("pipeline fails", func(t *testing.T) {
+		client := &spinnakerClientMock{execution: &spinnaker.Execution{Status: "TERMINAL"}}
+		utils := &mock.FilesMock{}
+
+		reports, err := runSpinnakerTriggerPipeline(&config, client, utils)
+
+		assert.EqualError(t, err, "Spinnaker pipeline failed with TERMINAL")
+		assert.Len(t, reports, 1)
+		assert.True(t, utils.HasWrittenFile(spinnakerReportFile))
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		client := &spinnakerClientMock{execution: &spinnaker.Execution{Status: "RUNNING"}, waitError: fmt.Errorf("timeout reached")}
+		utils := &mock.FilesMock{}
+
+		reports, err := runSpinnakerTriggerPipeline(&config, client, utils)
+
+		assert.EqualError(t, err, "timeout reached")
+		assert.Len(t, reports, 1)
+	})
+}
+
+func TestResolvePipelineParameters(t *testing.T) {
+	t.Parallel()
+
+	getenv := func(name string) string {
+		return map[string]string{"BUILD_NUMBER": "42"}[name]
+	}
+
+	t.Run("success", func(t *testing.T) {
+		config := spinnakerTriggerPipelineOptions{
+			Application: "myApp",
+			PipelineParameters: map[string]interface{}{
+				"build":   "${env.BUILD_NUMBER}",
+				"target":  map[string]interface{}{"app": "${config.spinnaker.application}-${ config.application }"},
+				"regions": []interface{}{"eu", "${env.UNKNOWN}"},
+				"replica": 2,
+			},
+		}
+
+		parameters, err := resolvePipelineParameters(&config, getenv)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, map[string]interface{}{
+				"build":   "42",
+				"target":  map[string]interface{}{"app": "myApp-myApp"},
+				"regions": []interface{}{"eu", ""},
+				"replica": 2,
+			}, parameters)
+		}
+	})
+
+	t.Run("no parameters", func(t *testing.T) {
+		parameters, err := resolvePipelineParameters(&spinnakerTriggerPipelineOptions{}, getenv)
+
+		assert.NoError(t, err)
+		assert.Empty(t, parameters)
+	})
+
+	t.Run("unknown configuration value", func(t *testing.T) {
+		config := spinnakerTriggerPipelineOptions{PipelineParameters: map[string]interface{}{"key": "${config.token}"}}
+
+		_, err := resolvePipelineParameters(&config, getenv)
+
+		assert.EqualError(t, err, "pipeline parameter references unknown configuration value 'token'")
+	})
+}
+
+func TestSpinnakerClientOptions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("token", func(t *testing.T) {
+		options, err := spinnakerClientOptions(&spinnakerTriggerPipelineOptions{Token: "secret"}, &mock.FilesMock{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Bearer secret", options.Token)
+		assert.Empty(t, options.Certificates)
+	})
+
+	t.Run("client certificate", func(t *testing.T) {
+		certPEM, keyPEM := generateTestCertificate(t)
+		utils := &mock.FilesMock{}
+		utils.AddFile("cert.pem", certPEM)
+		utils.AddFile("key.pem", keyPEM)
+
+		options, err := spinnakerClientOptions(&spinnakerTriggerPipelineOptions{ClientCertificate: "cert.pem", ClientKey: "key.pem"}, utils)
+
+		assert.NoError(t, err)
+		assert.Len(t, options.Certificates, 1)
+		assert.Empty(t, options.Token)
+	})
+
+	t.Run("key missing", func(t *testing.T) {
+		_, err := spinnakerClientOptions(&spinnakerTriggerPipelineOptions{ClientCertificate: "cert.pem"}, &mock.FilesMock{})
+
+		assert.EqualError(t, err, "client certificate authentication requires both 'clientCertificate' and 'clientKey'")
+	})
+
+	t.Run("invalid certificate", func(t *testing.T) {
+		utils := &mock.FilesMock{}
+		utils.AddFile("cert.pem", []byte("no cert"))
+		utils.AddFile("key.pem", []byte("no key"))
+
+		_, err := spinnakerClientOptions(&spinnakerTriggerPipelineOptions{ClientCertificate: "cert.pem", ClientKey: "key.pem"}, utils)
+
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "failed to load client certificate")
+		}
+	})
+}
+
+func generateTestCertificate(t *testing.T) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "piper"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"github.com/SAP/jenkins-library/pkg/command"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/SAP/jenkins-library/pkg/transportrequest/cts"
+)
+
+type transportRequestCreateCTSUtils interface {
+	cts.Exec
+}
+
+type transportRequestCreateCTSUtilsBundle struct {
+	*command.Command
+}
+
+func newTransportRequestCreateCTSUtils() transportRequestCreateCTSUtils {
+	utils := transportRequestCreateCTSUtilsBundle{
+		Command: &command.Command{},
+	}
+	// Reroute command output to logging framework
+	utils.Stdout(log.Writer())
+	utils.Stderr(log.Writer())
+	return &utils
+}
+
+func transportRequestCreateCTS(config transportRequestCreateCTSOptions,
+	telemetryData *telemetry.CustomData,
+	commonPipelineEnvironment *transportRequestCreateCTSCommonPipelineEnvironment) {
+
+	utils := newTransportRequestCreateCTSUtils()
+
+	err := runTransportRequestCreateCTS(&config, &cts.CreateAction{}, telemetryData, utils, commonPipelineEnvironment)
+	if err != nil {
+		log.Entry().WithError(err).Fatal("step execution failed")
+	}
+}
+
+func runTransportRequestCreateCTS(config *transportRequestCreateCTSOptions,
+	action cts.Create,
+	telemetryData *telemetry.CustomData,
+	utils transportRequestCreateCTSUtils,
+	commonPipelineEnvironment *transportRequestCreateCTSCommonPipelineEnvironment) error {
+
+	action.WithConnection(cts.Connection{
+		Endpoint: config.Endpoint,
+		User:     config.Username,
+		Password: config.Password,
+	})
+	action.WithTransportType(config.TransportType)
+	action.WithTargetSystemID(config.TargetSystem)
+	action.WithDescription(config.Description)
+	action.WithCMOpts(config.CmClientOpts)
+
+	trID, err := action.Perform(utils)
+	if err != nil {
+		return err
+	}
+
+	commonPipelineEnvironment.custom.transportRequestID = trID
+
+	log.Entry().Infof("Transport request '%s' has been created.", trID)
+	return nil
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/SAP/jenkins-library/pkg/transportrequest/cts"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type createCTSActionMock struct {
+	received      cts.CreateAction
+	performCalled bool
+	trID          string
+	failWith      error
+}
+
+func (a *createCTSActionMock) WithConnection(c cts.Connection) {
+	a.received.Connection = c
+}
+func (a *createCTSActionMock) WithTransportType(t string) {
+	a.received.TransportType = t
+}
+func (a *createCTSActionMock) WithTargetSystemID(id string) {
+	a.received.TargetSystemID = id
+}
+func (a *createCTSActionMock) WithDescription(desc string) {
+	a.received.Description = desc
+}
+func (a *createCTSActionMock) WithCMOpts(opts []string) {
+	a.received.CMOpts = opts
+}
+func (a *createCTSActionMock) Perform(command cts.Exec) (string, error) {
+	a.performCalled = true
+	return a.trID, a.failWith
+}
+
+func TestTransportRequestCreateCTS(t *testing.T) {
+
+	config := transportRequestCreateCTSOptions{
+		Endpoint:      "https://example.org/cts",
+		Username:      "me",
+		Password:      "********",
+		TransportType: "W",
+		TargetSystem:  "XYZ",
+		Description:   "Created with Piper",
+	}
+
+	t.Run("straight forward", func(t *testing.T) {
+		t.Parallel()
+
+		action := createCTSActionMock{trID: "XYZK123456"}
+		cpe := &transportRequestCreateCTSCommonPipelineEnvironment{}
+
+		err := runTransportRequestCreateCTS(&config, &action, nil, &mock.ExecMockRunner{}, cpe)
+
+		if assert.NoError(t, err) {
+			assert.True(t, action.performCalled)
+			assert.Equal(t, cts.CreateAction{
+				Connection: cts.Connection{
+					Endpoint: "https://example.org/cts",
+					User:     "me",
+					Password: "********",
+				},
+				TransportType:  "W",
+				TargetSystemID: "XYZ",
+				Description:    "Created with Piper",
+			}, action.received)
+			assert.Equal(t, "XYZK123456", cpe.custom.transportRequestID)
+		}
+	})
+
+	t.Run("create fails", func(t *testing.T) {
+		t.Parallel()
+
+		action := createCTSActionMock{failWith: fmt.Errorf("something went wrong")}
+		cpe := &transportRequestCreateCTSCommonPipelineEnvironment{}
+
+		err := runTransportRequestCreateCTS(&config, &action, nil, &mock.ExecMockRunner{}, cpe)
+
+		assert.EqualError(t, err, "something went wrong")
+		assert.Empty(t, cpe.custom.transportRequestID)
+	})
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"github.com/SAP/jenkins-library/pkg/command"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/SAP/jenkins-library/pkg/transportrequest/solman"
+)
+
+type transportRequestCreateSOLMANUtils interface {
+	solman.Exec
+}
+
+type transportRequestCreateSOLMANUtilsBundle struct {
+	*command.Command
+}
+
+func newTransportRequestCreateSOLMANUtils() transportRequestCreateSOLMANUtils {
+	utils := transportRequestCreateSOLMANUtilsBundle{
+		Command: &command.Command{},
+	}
+	// Reroute command output to logging framework
+	utils.Stdout(log.Writer())
+	utils.Stderr(log.Writer())
+	return &utils
+}
+
+func transportRequestCreateSOLMAN(config transportRequestCreateSOLMANOptions,
+	telemetryData *telemetry.CustomData,
+	commonPipelineEnvironment *transportRequestCreateSOLMANCommonPipelineEnvironment) {
+
+	utils := newTransportRequestCreateSOLMANUtils()
+
+	err := runTransportRequestCreateSOLMAN(&config, &solman.CreateAction{}, telemetryData, utils, &transportRequestUtils{}, commonPipelineEnvironment)
+	if err != nil {
+		log.Entry().WithError(err).Fatal("step execution failed")
+	}
+}
+
+func runTransportRequestCreateSOLMAN(config *transportRequestCreateSOLMANOptions,
+	action solman.Create,
+	telemetryData *telemetry.CustomData,
+	utils transportRequestCreateSOLMANUtils,
+	trUtils iTransportRequestUtils,
+	commonPipelineEnvironment *transportRequestCreateSOLMANCommonPipelineEnvironment) error {
+
+	action.WithConnection(solman.Connection{
+		Endpoint: config.Endpoint,
+		User:     config.Username,
+		Password: config.Password,
+	})
+
+	cdID := config.ChangeDocumentID
+	if len(cdID) == 0 {
+		var err error
+		cdID, err = trUtils.FindIDInRange(config.ChangeDocumentLabel, config.GitFrom, config.GitTo)
+		if err != nil {
+			return err
+		}
+	}
+	action.WithChangeDocumentID(cdID)
+	action.WithDevelopmentSystemID(config.DevelopmentSystemID)
+	action.WithCMOpts(config.CmClientOpts)
+
+	trID, err := action.Perform(utils)
+	if err != nil {
+		return err
+	}
+
+	commonPipelineEnvironment.custom.changeDocumentID = cdID
+	commonPipelineEnvironment.custom.transportRequestID = trID
+
+	log.Entry().Infof("Transport request '%s' has been created in SAP Solution Manager (ChangeDocumentId: '%s').", trID, cdID)
+	return nil
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/SAP/jenkins-library/pkg/transportrequest/solman"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type createSOLMANActionMock struct {
+	received      solman.CreateAction
+	performCalled bool
+	trID          string
+	failWith      error
+}
+
+func (a *createSOLMANActionMock) WithConnection(c solman.Connection) {
+	a.received.Connection = c
+}
+func (a *createSOLMANActionMock) WithChangeDocumentID(id string) {
+	a.received.ChangeDocumentID = id
+}
+func (a *createSOLMANActionMock) WithDevelopmentSystemID(id string) {
+	a.received.DevelopmentSystemID = id
+}
+func (a *createSOLMANActionMock) WithCMOpts(opts []string) {
+	a.received.CMOpts = opts
+}
+func (a *createSOLMANActionMock) Perform(command solman.Exec) (string, error) {
+	a.performCalled = true
+	return a.trID, a.failWith
+}
+
+func TestTransportRequestCreateSOLMAN(t *testing.T) {
+
+	config := transportRequestCreateSOLMANOptions{
+		Endpoint:            "https://example.org/solman",
+		Username:            "me",
+		Password:            "********",
+		DevelopmentSystemID: "XXX~EXT_SRV",
+		CmClientOpts:        []string{"-Dtest=abc123"},
+		ChangeDocumentLabel: "ChangeDocument",
+	}
+
+	t.Run("straight forward", func(t *testing.T) {
+		t.Parallel()
+
+		action := createSOLMANActionMock{trID: "XXXK123456"}
+		cpe := &transportRequestCreateSOLMANCommonPipelineEnvironment{}
+
+		err := runTransportRequestCreateSOLMAN(&config, &action, nil, &mock.ExecMockRunner{},
+			&transportRequestUtilsMock{cdID: "123"}, cpe)
+
+		if assert.NoError(t, err) {
+			assert.True(t, action.performCalled)
+			assert.Equal(t, solman.CreateAction{
+				Connection: solman.Connection{
+					Endpoint: "https://example.org/solman",
+					User:     "me",
+					Password: "********",
+				},
+				ChangeDocumentID:    "123",
+				DevelopmentSystemID: "XXX~EXT_SRV",
+				CMOpts:              []string{"-Dtest=abc123"},
+			}, action.received)
+			assert.Equal(t, "123", cpe.custom.changeDocumentID)
+			assert.Equal(t, "XXXK123456", cpe.custom.transportRequestID)
+		}
+	})
+
+	t.Run("change document id provided via config", func(t *testing.T) {
+		t.Parallel()
+
+		action := createSOLMANActionMock{trID: "XXXK123456"}
+		cpe := &transportRequestCreateSOLMANCommonPipelineEnvironment{}
+		c := config
+		c.ChangeDocumentID = "456"
+
+		err := runTransportRequestCreateSOLMAN(&c, &action, nil, &mock.ExecMockRunner{},
+			&transportRequestUtilsMock{cdID: "123"}, cpe)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, "456", action.received.ChangeDocumentID)
+			assert.Equal(t, "456", cpe.custom.changeDocumentID)
+		}
+	})
+
+	t.Run("create fails", func(t *testing.T) {
+		t.Parallel()
+
+		action := createSOLMANActionMock{failWith: fmt.Errorf("something went wrong")}
+		cpe := &transportRequestCreateSOLMANCommonPipelineEnvironment{}
+
+		err := runTransportRequestCreateSOLMAN(&config, &action, nil, &mock.ExecMockRunner{},
+			&transportRequestUtilsMock{cdID: "123"}, cpe)
+
+		assert.EqualError(t, err, "something went wrong")
+		assert.Empty(t, cpe.custom.transportRequestID)
+	})
+}
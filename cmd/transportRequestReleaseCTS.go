@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"github.com/SAP/jenkins-library/pkg/command"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/SAP/jenkins-library/pkg/transportrequest/cts"
+)
+
+type transportRequestReleaseCTSUtils interface {
+	cts.Exec
+}
+
+type transportRequestReleaseCTSUtilsBundle struct {
+	*command.Command
+}
+
+func newTransportRequestReleaseCTSUtils() transportRequestReleaseCTSUtils {
+	utils := transportRequestReleaseCTSUtilsBundle{
+		Command: &command.Command{},
+	}
+	// Reroute command output to logging framework
+	utils.Stdout(log.Writer())
+	utils.Stderr(log.Writer())
+	return &utils
+}
+
+func transportRequestReleaseCTS(config transportRequestReleaseCTSOptions, telemetryData *telemetry.CustomData) {
+
+	utils := newTransportRequestReleaseCTSUtils()
+
+	err := runTransportRequestReleaseCTS(&config, &cts.ReleaseAction{}, telemetryData, utils)
+	if err != nil {
+		log.Entry().WithError(err).Fatal("step execution failed")
+	}
+}
+
+func runTransportRequestReleaseCTS(config *transportRequestReleaseCTSOptions,
+	action cts.Release,
+	telemetryData *telemetry.CustomData,
+	utils transportRequestReleaseCTSUtils) error {
+
+	action.WithConnection(cts.Connection{
+		Endpoint: config.Endpoint,
+		User:     config.Username,
+		Password: config.Password,
+	})
+	action.WithTransportRequestID(config.TransportRequestID)
+	action.WithCMOpts(config.CmClientOpts)
+
+	err := action.Perform(utils)
+
+	if err == nil {
+		log.Entry().Infof("Transport request '%s' has been released.", config.TransportRequestID)
+	}
+	return err
+}
@@ -0,0 +1,17 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransportRequestReleaseCTSCommand(t *testing.T) {
+	t.Parallel()
+
+	testCmd := TransportRequestReleaseCTSCommand()
+
+	// only high level testing performed - details are tested in step generation procedure
+	assert.Equal(t, "transportRequestReleaseCTS", testCmd.Use, "command name incorrect")
+
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/SAP/jenkins-library/pkg/transportrequest/cts"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type releaseCTSActionMock struct {
+	received      cts.ReleaseAction
+	performCalled bool
+	failWith      error
+}
+
+func (a *releaseCTSActionMock) WithConnection(c cts.Connection) {
+	a.received.Connection = c
+}
+func (a *releaseCTSActionMock) WithTransportRequestID(id string) {
+	a.received.TransportRequestID = id
+}
+func (a *releaseCTSActionMock) WithCMOpts(opts []string) {
+	a.received.CMOpts = opts
+}
+func (a *releaseCTSActionMock) Perform(command cts.Exec) error {
+	a.performCalled = true
+	return a.failWith
+}
+
+func TestTransportRequestReleaseCTS(t *testing.T) {
+
+	config := transportRequestReleaseCTSOptions{
+		Endpoint:           "https://example.org/cts",
+		Username:           "me",
+		Password:           "********",
+		TransportRequestID: "XYZK123456",
+		CmClientOpts:       []string{"-Dtest=abc123"},
+	}
+
+	t.Run("straight forward", func(t *testing.T) {
+		t.Parallel()
+
+		action := releaseCTSActionMock{}
+
+		err := runTransportRequestReleaseCTS(&config, &action, nil, &mock.ExecMockRunner{})
+
+		if assert.NoError(t, err) {
+			assert.True(t, action.performCalled)
+			assert.Equal(t, cts.ReleaseAction{
+				Connection: cts.Connection{
+					Endpoint: "https://example.org/cts",
+					User:     "me",
+					Password: "********",
+				},
+				TransportRequestID: "XYZK123456",
+				CMOpts:             []string{"-Dtest=abc123"},
+			}, action.received)
+		}
+	})
+
+	t.Run("release fails", func(t *testing.T) {
+		t.Parallel()
+
+		action := releaseCTSActionMock{failWith: fmt.Errorf("something went wrong")}
+
+		err := runTransportRequestReleaseCTS(&config, &action, nil, &mock.ExecMockRunner{})
+
+		assert.EqualError(t, err, "something went wrong")
+	})
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"github.com/SAP/jenkins-library/pkg/command"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/SAP/jenkins-library/pkg/transportrequest/solman"
+)
+
+type transportRequestReleaseSOLMANUtils interface {
+	solman.Exec
+}
+
+type transportRequestReleaseSOLMANUtilsBundle struct {
+	*command.Command
+}
+
+func newTransportRequestReleaseSOLMANUtils() transportRequestReleaseSOLMANUtils {
+	utils := transportRequestReleaseSOLMANUtilsBundle{
+		Command: &command.Command{},
+	}
+	// Reroute command output to logging framework
+	utils.Stdout(log.Writer())
+	utils.Stderr(log.Writer())
+	return &utils
+}
+
+func transportRequestReleaseSOLMAN(config transportRequestReleaseSOLMANOptions, telemetryData *telemetry.CustomData) {
+
+	utils := newTransportRequestReleaseSOLMANUtils()
+
+	err := runTransportRequestReleaseSOLMAN(&config, &solman.ReleaseAction{}, telemetryData, utils, &transportRequestUtils{})
+	if err != nil {
+		log.Entry().WithError(err).Fatal("step execution failed")
+	}
+}
+
+func runTransportRequestReleaseSOLMAN(config *transportRequestReleaseSOLMANOptions,
+	action solman.Release,
+	telemetryData *telemetry.CustomData,
+	utils transportRequestReleaseSOLMANUtils,
+	trUtils iTransportRequestUtils) error {
+
+	action.WithConnection(solman.Connection{
+		Endpoint: config.Endpoint,
+		User:     config.Username,
+		Password: config.Password,
+	})
+
+	cdID := config.ChangeDocumentID
+	if len(cdID) == 0 {
+		var err error
+		cdID, err = trUtils.FindIDInRange(config.ChangeDocumentLabel, config.GitFrom, config.GitTo)
+		if err != nil {
+			return err
+		}
+	}
+	action.WithChangeDocumentID(cdID)
+
+	trID := config.TransportRequestID
+	if len(trID) == 0 {
+		var err error
+		trID, err = trUtils.FindIDInRange(config.TransportRequestLabel, config.GitFrom, config.GitTo)
+		if err != nil {
+			return err
+		}
+	}
+	action.WithTransportRequestID(trID)
+	action.WithCMOpts(config.CmClientOpts)
+
+	err := action.Perform(utils)
+
+	if err == nil {
+		log.Entry().Infof("Transport request '%s' has been released in SAP Solution Manager (ChangeDocumentId: '%s').", trID, cdID)
+	}
+	return err
+}
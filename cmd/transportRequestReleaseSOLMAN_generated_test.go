@@ -0,0 +1,17 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransportRequestReleaseSOLMANCommand(t *testing.T) {
+	t.Parallel()
+
+	testCmd := TransportRequestReleaseSOLMANCommand()
+
+	// only high level testing performed - details are tested in step generation procedure
+	assert.Equal(t, "transportRequestReleaseSOLMAN", testCmd.Use, "command name incorrect")
+
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/SAP/jenkins-library/pkg/transportrequest/solman"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type releaseSOLMANActionMock struct {
+	received      solman.ReleaseAction
+	performCalled bool
+	failWith      error
+}
+
+func (a *releaseSOLMANActionMock) WithConnection(c solman.Connection) {
+	a.received.Connection = c
+}
+func (a *releaseSOLMANActionMock) WithChangeDocumentID(id string) {
+	a.received.ChangeDocumentID = id
+}
+func (a *releaseSOLMANActionMock) WithTransportRequestID(id string) {
+	a.received.TransportRequestID = id
+}
+func (a *releaseSOLMANActionMock) WithCMOpts(opts []string) {
+	a.received.CMOpts = opts
+}
+func (a *releaseSOLMANActionMock) Perform(command solman.Exec) error {
+	a.performCalled = true
+	return a.failWith
+}
+
+func TestTransportRequestReleaseSOLMAN(t *testing.T) {
+
+	config := transportRequestReleaseSOLMANOptions{
+		Endpoint:              "https://example.org/solman",
+		Username:              "me",
+		Password:              "********",
+		CmClientOpts:          []string{"-Dtest=abc123"},
+		ChangeDocumentLabel:   "ChangeDocument",
+		TransportRequestLabel: "TransportRequest",
+	}
+
+	t.Run("ids retrieved from git", func(t *testing.T) {
+		t.Parallel()
+
+		action := releaseSOLMANActionMock{}
+
+		err := runTransportRequestReleaseSOLMAN(&config, &action, nil, &mock.ExecMockRunner{},
+			&transportRequestUtilsMock{cdID: "123", trID: "XXXK123456"})
+
+		if assert.NoError(t, err) {
+			assert.True(t, action.performCalled)
+			assert.Equal(t, solman.ReleaseAction{
+				Connection: solman.Connection{
+					Endpoint: "https://example.org/solman",
+					User:     "me",
+					Password: "********",
+				},
+				ChangeDocumentID:   "123",
+				TransportRequestID: "XXXK123456",
+				CMOpts:             []string{"-Dtest=abc123"},
+			}, action.received)
+		}
+	})
+
+	t.Run("ids provided via config", func(t *testing.T) {
+		t.Parallel()
+
+		action := releaseSOLMANActionMock{}
+		c := config
+		c.ChangeDocumentID = "456"
+		c.TransportRequestID = "YYYK654321"
+
+		err := runTransportRequestReleaseSOLMAN(&c, &action, nil, &mock.ExecMockRunner{},
+			&transportRequestUtilsMock{cdID: "123", trID: "XXXK123456"})
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, "456", action.received.ChangeDocumentID)
+			assert.Equal(t, "YYYK654321", action.received.TransportRequestID)
+		}
+	})
+
+	t.Run("release fails", func(t *testing.T) {
+		t.Parallel()
+
+		action := releaseSOLMANActionMock{failWith: fmt.Errorf("something went wrong")}
+
+		err := runTransportRequestReleaseSOLMAN(&config, &action, nil, &mock.ExecMockRunner{},
+			&transportRequestUtilsMock{cdID: "123", trID: "XXXK123456"})
+
+		assert.EqualError(t, err, "something went wrong")
+	})
+}
@@ -586,7 +586,8 @@ func createCustomVulnerabilityReport(config *ScanOptions, scan *ws.Scan, alerts
 	sort.Strings(projectNames)
 
 	scanReport := reporting.ScanReport{
-		Title: "WhiteSource Security Vulnerability Report",
+		StepName: "whitesourceExecuteScan",
+		Title:    "WhiteSource Security Vulnerability Report",
 		Subheaders: []reporting.Subheader{
 			{Description: "WhiteSource product name", Details: config.ProductName},
 			{Description: "Filtered project names", Details: strings.Join(projectNames, ", ")},
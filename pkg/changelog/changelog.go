@@ -0,0 +1,281 @@
+package changelog
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/versioning"
+)
+
+// SectionsTemplate is the Go template used for rendering the sections of a changelog as Markdown
+const SectionsTemplate = `{{range .Sections}}
+### {{.Title}}
+
+{{range .Entries}}* {{.Text}}{{if .Reference}} ([{{.Reference}}]({{.URL}})){{end}}
+{{end}}{{end}}`
+
+// DefaultTemplate is the Go template used for rendering a changelog entry as Markdown
+const DefaultTemplate = "## {{.Version}}{{if .Date}} ({{.Date}}){{end}}\n" + SectionsTemplate + `{{if .CompareURL}}
+**Full changelog**: {{.CompareURL}}
+{{end}}`
+
+const (
+	breakingChangesTitle = "Breaking Changes"
+	otherChangesTitle    = "Other Changes"
+	closedIssuesTitle    = "Closed Issues"
+)
+
+// Group defines which commits (by Conventional Commit type) and pull requests (by label) are listed together
+type Group struct {
+	Title       string
+	CommitTypes []string
+	Labels      []string
+}
+
+// DefaultGroups contains the groups used in case no dedicated groups are provided
+var DefaultGroups = []Group{
+	{Title: "Features", CommitTypes: []string{"feat"}, Labels: []string{"feature", "enhancement"}},
+	{Title: "Bug Fixes", CommitTypes: []string{"fix"}, Labels: []string{"bug", "fix"}},
+	{Title: "Performance Improvements", CommitTypes: []string{"perf"}, Labels: []string{"performance"}},
+}
+
+// Commit describes a git commit, parsed according to https://www.conventionalcommits.org
+type Commit struct {
+	Hash     string
+	Type     string
+	Scope    string
+	Subject  string
+	Breaking bool
+	// Conventional is false for commit messages which do not follow the Conventional Commits format
+	Conventional bool
+	// PullRequest contains the number of the pull request the commit has been merged with (if any)
+	PullRequest int
+}
+
+// Item describes a pull request or an issue
+type Item struct {
+	Number int
+	Title  string
+	URL    string
+	Labels []string
+}
+
+// Entry is a single line of the changelog
+type Entry struct {
+	Text      string
+	Reference string
+	URL       string
+}
+
+// Section groups the changelog entries
+type Section struct {
+	Title   string
+	Entries []Entry
+}
+
+// Changelog contains the changes of a version
+type Changelog struct {
+	Version    string
+	Date       string
+	CompareURL string
+	Sections   []Section
+}
+
+// Options define how the changelog is built
+type Options struct {
+	Groups []Group
+	// CommitURL is used for linking commits, the commit hash is appended
+	CommitURL string
+	// IncludeOtherChanges lists commits and pull requests which do not belong to any group
+	IncludeOtherChanges bool
+}
+
+var pullRequestReference = regexp.MustCompile(`(?:\(#(\d+)\)\s*$|^Merge pull request #(\d+))`)
+
+// ParseCommit parses the commit message according to the Conventional Commits format
+func ParseCommit(hash, message string) Commit {
+	header := strings.TrimSpace(strings.SplitN(message, "\n", 2)[0])
+	commit := Commit{Hash: hash, Subject: header}
+
+	if match := pullRequestReference.FindStringSubmatch(header); match != nil {
+		number := match[1]
+		if len(number) == 0 {
+			number = match[2]
+		}
+		fmt.Sscanf(number, "%d", &commit.PullRequest)
+	}
+
+	conventional, ok := versioning.ParseConventionalCommit(message)
+	if !ok {
+		return commit
+	}
+	commit.Conventional = true
+	commit.Type = conventional.Type
+	commit.Scope = conventional.Scope
+	commit.Subject = conventional.Description
+	commit.Breaking = conventional.Breaking
+	return commit
+}
+
+// Build groups commits, pull requests and closed issues into the sections of a changelog.
+// Commits which have been merged via one of the given pull requests are represented by the pull request.
+func Build(version string, date time.Time, commits []Commit, pullRequests, issues []Item, options Options) Changelog {
+	groups := options.Groups
+	if len(groups) == 0 {
+		groups = DefaultGroups
+	}
+
+	entries := map[string][]Entry{}
+	pullRequestNumbers := map[int]bool{}
+	for _, pr := range pullRequests {
+		pullRequestNumbers[pr.Number] = true
+	}
+
+	for _, pr := range pullRequests {
+		title := otherChangesTitle
+		if containsAny(pr.Labels, []string{"breaking", "breaking-change"}) {
+			title = breakingChangesTitle
+		} else if group := groupForLabels(groups, pr.Labels); group != nil {
+			title = group.Title
+		} else if commit := ParseCommit("", pr.Title); commit.Conventional {
+			// pull request titles often follow the commit conventions as well
+			title = titleForCommit(groups, commit)
+		}
+		entries[title] = append(entries[title], Entry{Text: pr.Title, Reference: fmt.Sprintf("#%d", pr.Number), URL: pr.URL})
+	}
+
+	for _, commit := range commits {
+		if commit.PullRequest > 0 && pullRequestNumbers[commit.PullRequest] {
+			continue
+		}
+		if commit.Conventional && !commit.Breaking && groupForCommitType(groups, commit.Type) == nil {
+			// e.g. 'chore' or 'docs' are not relevant for the changelog
+			continue
+		}
+		entry := Entry{Text: commitText(commit)}
+		if len(commit.Hash) > 0 {
+			entry.Reference = shortHash(commit.Hash)
+			if len(options.CommitURL) > 0 {
+				entry.URL = options.CommitURL + commit.Hash
+			}
+		}
+		if len(entry.URL) == 0 {
+			entry.Reference = ""
+		}
+		title := titleForCommit(groups, commit)
+		entries[title] = append(entries[title], entry)
+	}
+
+	changelog := Changelog{Version: version}
+	if !date.IsZero() {
+		changelog.Date = date.Format("2006-01-02")
+	}
+
+	titles := []string{breakingChangesTitle}
+	for _, group := range groups {
+		titles = append(titles, group.Title)
+	}
+	if options.IncludeOtherChanges {
+		titles = append(titles, otherChangesTitle)
+	}
+	for _, title := range titles {
+		if len(entries[title]) > 0 {
+			changelog.Sections = append(changelog.Sections, Section{Title: title, Entries: entries[title]})
+		}
+	}
+
+	issueEntries := []Entry{}
+	for _, issue := range issues {
+		issueEntries = append(issueEntries, Entry{Text: issue.Title, Reference: fmt.Sprintf("#%d", issue.Number), URL: issue.URL})
+	}
+	if len(issueEntries) > 0 {
+		changelog.Sections = append(changelog.Sections, Section{Title: closedIssuesTitle, Entries: issueEntries})
+	}
+	return changelog
+}
+
+// Render renders the changelog as Markdown using the given Go template (DefaultTemplate in case it is empty)
+func (c Changelog) Render(template string) (string, error) {
+	if len(template) == 0 {
+		template = DefaultTemplate
+	}
+	return piperutils.ExecuteTemplate(template, c)
+}
+
+// UpdateFile adds the rendered changelog entry to the content of a changelog file.
+// The entry is placed below the main heading ('# ...') of the file, existing entries are kept.
+// In case an entry for the version already exists the content is returned unchanged.
+func UpdateFile(content, entry, version string) (string, bool) {
+	if regexp.MustCompile(`(?m)^## ` + regexp.QuoteMeta(version) + `(\s|$)`).MatchString(content) {
+		return content, false
+	}
+	entry = strings.TrimSpace(entry) + "\n"
+
+	if len(strings.TrimSpace(content)) == 0 {
+		return "# Changelog\n\n" + entry, true
+	}
+
+	lines := strings.SplitAfter(content, "\n")
+	if strings.HasPrefix(lines[0], "# ") {
+		rest := strings.TrimLeft(strings.Join(lines[1:], ""), "\n")
+		if len(rest) == 0 {
+			return strings.TrimRight(lines[0], "\n") + "\n\n" + entry, true
+		}
+		return strings.TrimRight(lines[0], "\n") + "\n\n" + entry + "\n" + rest, true
+	}
+	return entry + "\n" + content, true
+}
+
+func titleForCommit(groups []Group, commit Commit) string {
+	if commit.Breaking {
+		return breakingChangesTitle
+	}
+	if group := groupForCommitType(groups, commit.Type); group != nil {
+		return group.Title
+	}
+	return otherChangesTitle
+}
+
+func commitText(commit Commit) string {
+	if len(commit.Scope) > 0 {
+		return fmt.Sprintf("**%s:** %s", commit.Scope, commit.Subject)
+	}
+	return commit.Subject
+}
+
+func groupForCommitType(groups []Group, commitType string) *Group {
+	for i, group := range groups {
+		if piperutils.ContainsString(group.CommitTypes, commitType) {
+			return &groups[i]
+		}
+	}
+	return nil
+}
+
+func groupForLabels(groups []Group, labels []string) *Group {
+	for i, group := range groups {
+		if containsAny(group.Labels, labels) {
+			return &groups[i]
+		}
+	}
+	return nil
+}
+
+func containsAny(values, candidates []string) bool {
+	for _, candidate := range candidates {
+		if piperutils.ContainsString(values, candidate) {
+			return true
+		}
+	}
+	return false
+}
+
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
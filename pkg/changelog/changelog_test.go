@@ -0,0 +1,157 @@
+package changelog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCommit(t *testing.T) {
+	tt := []struct {
+		message  string
+		expected Commit
+	}{
+		{message: "feat: new feature", expected: Commit{Hash: "abc", Type: "feat", Subject: "new feature", Conventional: true}},
+		{message: "fix(parser): handle empty input\n\nbody", expected: Commit{Hash: "abc", Type: "fix", Scope: "parser", Subject: "handle empty input", Conventional: true}},
+		{message: "refactor!: drop old API", expected: Commit{Hash: "abc", Type: "refactor", Subject: "drop old API", Breaking: true, Conventional: true}},
+		{message: "feat: new config\n\nBREAKING CHANGE: removed option", expected: Commit{Hash: "abc", Type: "feat", Subject: "new config", Breaking: true, Conventional: true}},
+		{message: "fix: race condition (#42)", expected: Commit{Hash: "abc", Type: "fix", Subject: "race condition (#42)", Conventional: true, PullRequest: 42}},
+		{message: "Merge pull request #7 from fork/branch", expected: Commit{Hash: "abc", Subject: "Merge pull request #7 from fork/branch", PullRequest: 7}},
+		{message: "Update README", expected: Commit{Hash: "abc", Subject: "Update README"}},
+	}
+
+	for _, test := range tt {
+		t.Run(test.message, func(t *testing.T) {
+			assert.Equal(t, test.expected, ParseCommit("abc", test.message))
+		})
+	}
+}
+
+func TestBuild(t *testing.T) {
+	commits := []Commit{
+		ParseCommit("1111111111", "feat(cli): add flag"),
+		ParseCommit("2222222222", "fix: crash on start (#12)"),
+		ParseCommit("3333333333", "chore: update dependencies"),
+		ParseCommit("4444444444", "refactor!: rename config"),
+		ParseCommit("5555555555", "Update README"),
+	}
+	pullRequests := []Item{
+		{Number: 12, Title: "Fix crash on start", URL: "https://github.com/o/r/pull/12", Labels: []string{"bug"}},
+		{Number: 13, Title: "feat: support proxies", URL: "https://github.com/o/r/pull/13"},
+		{Number: 14, Title: "Improve docs", URL: "https://github.com/o/r/pull/14", Labels: []string{"documentation"}},
+	}
+	issues := []Item{{Number: 10, Title: "Crash on start", URL: "https://github.com/o/r/issues/10"}}
+
+	t.Run("default groups", func(t *testing.T) {
+		changelog := Build("1.2.0", time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC), commits, pullRequests, issues, Options{CommitURL: "https://github.com/o/r/commit/"})
+
+		assert.Equal(t, "1.2.0", changelog.Version)
+		assert.Equal(t, "2021-03-04", changelog.Date)
+		assert.Equal(t, []Section{
+			{Title: "Breaking Changes", Entries: []Entry{{Text: "rename config", Reference: "4444444", URL: "https://github.com/o/r/commit/4444444444"}}},
+			{Title: "Features", Entries: []Entry{
+				{Text: "feat: support proxies", Reference: "#13", URL: "https://github.com/o/r/pull/13"},
+				{Text: "**cli:** add flag", Reference: "1111111", URL: "https://github.com/o/r/commit/1111111111"},
+			}},
+			{Title: "Bug Fixes", Entries: []Entry{{Text: "Fix crash on start", Reference: "#12", URL: "https://github.com/o/r/pull/12"}}},
+			{Title: "Closed Issues", Entries: []Entry{{Text: "Crash on start", Reference: "#10", URL: "https://github.com/o/r/issues/10"}}},
+		}, changelog.Sections)
+	})
+
+	t.Run("other changes", func(t *testing.T) {
+		changelog := Build("1.2.0", time.Time{}, commits, pullRequests, nil, Options{IncludeOtherChanges: true})
+
+		assert.Empty(t, changelog.Date)
+		if assert.Len(t, changelog.Sections, 4) {
+			assert.Equal(t, Section{Title: "Other Changes", Entries: []Entry{
+				{Text: "Improve docs", Reference: "#14", URL: "https://github.com/o/r/pull/14"},
+				{Text: "Update README"},
+			}}, changelog.Sections[3])
+		}
+	})
+
+	t.Run("custom groups", func(t *testing.T) {
+		changelog := Build("1.2.0", time.Time{}, commits, nil, nil, Options{Groups: []Group{{Title: "Maintenance", CommitTypes: []string{"chore"}}}})
+
+		assert.Equal(t, []Section{
+			{Title: "Breaking Changes", Entries: []Entry{{Text: "rename config"}}},
+			{Title: "Maintenance", Entries: []Entry{{Text: "update dependencies"}}},
+		}, changelog.Sections)
+	})
+}
+
+func TestRender(t *testing.T) {
+	changelog := Changelog{
+		Version:    "1.2.0",
+		Date:       "2021-03-04",
+		CompareURL: "https://github.com/o/r/compare/v1.1.0...v1.2.0",
+		Sections: []Section{
+			{Title: "Features", Entries: []Entry{{Text: "add flag", Reference: "1111111", URL: "https://github.com/o/r/commit/1111111"}}},
+			{Title: "Bug Fixes", Entries: []Entry{{Text: "fix crash"}}},
+		},
+	}
+
+	t.Run("default template", func(t *testing.T) {
+		markdown, err := changelog.Render("")
+
+		assert.NoError(t, err)
+		assert.Equal(t, `## 1.2.0 (2021-03-04)
+
+### Features
+
+* add flag ([1111111](https://github.com/o/r/commit/1111111))
+
+### Bug Fixes
+
+* fix crash
+
+**Full changelog**: https://github.com/o/r/compare/v1.1.0...v1.2.0
+`, markdown)
+	})
+
+	t.Run("custom template", func(t *testing.T) {
+		markdown, err := changelog.Render("{{.Version}}:{{range .Sections}} {{.Title}}{{end}}")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "1.2.0: Features Bug Fixes", markdown)
+	})
+
+	t.Run("invalid template", func(t *testing.T) {
+		_, err := changelog.Render("{{.Unknown")
+
+		assert.Error(t, err)
+	})
+}
+
+func TestUpdateFile(t *testing.T) {
+	entry := "## 1.2.0\n\n* change\n"
+
+	t.Run("new file", func(t *testing.T) {
+		content, updated := UpdateFile("", entry, "1.2.0")
+
+		assert.True(t, updated)
+		assert.Equal(t, "# Changelog\n\n## 1.2.0\n\n* change\n", content)
+	})
+
+	t.Run("existing entries", func(t *testing.T) {
+		content, updated := UpdateFile("# Changelog\n\n## 1.1.0\n\n* old change\n", entry, "1.2.0")
+
+		assert.True(t, updated)
+		assert.Equal(t, "# Changelog\n\n## 1.2.0\n\n* change\n\n## 1.1.0\n\n* old change\n", content)
+	})
+
+	t.Run("no heading", func(t *testing.T) {
+		content, updated := UpdateFile("## 1.1.0\n", entry, "1.2.0")
+
+		assert.True(t, updated)
+		assert.Equal(t, "## 1.2.0\n\n* change\n\n## 1.1.0\n", content)
+	})
+
+	t.Run("version already contained", func(t *testing.T) {
+		content, updated := UpdateFile("# Changelog\n\n## 1.2.0 (2021-03-04)\n", entry, "1.2.0")
+
+		assert.False(t, updated)
+		assert.Equal(t, "# Changelog\n\n## 1.2.0 (2021-03-04)\n", content)
+	})
+}
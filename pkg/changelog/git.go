@@ -0,0 +1,80 @@
+package changelog
+
+import (
+	"time"
+
+	gitUtils "github.com/SAP/jenkins-library/pkg/git"
+	"github.com/SAP/jenkins-library/pkg/versioning"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/pkg/errors"
+)
+
+// Tag describes the git tag of a previous version
+type Tag struct {
+	Name string
+	Date time.Time
+}
+
+// Commits returns the parsed commits reachable from 'to', but not reachable by 'from' in chronological order.
+// In case 'from' is empty all commits reachable from 'to' are returned.
+func Commits(repo *git.Repository, from, to string) ([]Commit, error) {
+	var commitIter object.CommitIter
+	if len(from) == 0 {
+		hash, err := repo.ResolveRevision(plumbing.Revision(to))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to resolve '%s'", to)
+		}
+		commitIter, err = repo.Log(&git.LogOptions{From: *hash})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to retrieve commits")
+		}
+	} else {
+		var err error
+		commitIter, err = gitUtils.LogRange(repo, from, to)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	commits := []Commit{}
+	err := commitIter.ForEach(func(c *object.Commit) error {
+		commits = append(commits, ParseCommit(c.Hash.String(), c.Message))
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to retrieve commits")
+	}
+	// the log starts with the latest commit
+	for i, j := 0, len(commits)-1; i < j; i, j = i+1, j-1 {
+		commits[i], commits[j] = commits[j], commits[i]
+	}
+	return commits, nil
+}
+
+// LatestTag returns the tag with the highest semantic version using the given prefix (e.g. 'v').
+// The tag of the version the changelog is created for is ignored. In case no such tag exists nil is returned.
+func LatestTag(repo *git.Repository, tagPrefix, currentVersion string) (*Tag, error) {
+	tags, err := repo.Tags()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to retrieve tags")
+	}
+	latestTag, _, err := versioning.LatestVersionTag(tags, tagPrefix, tagPrefix+currentVersion)
+	if err != nil {
+		return nil, err
+	}
+	if len(latestTag) == 0 {
+		return nil, nil
+	}
+
+	hash, err := repo.ResolveRevision(plumbing.Revision(latestTag))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to resolve tag '%s'", latestTag)
+	}
+	commit, err := repo.CommitObject(*hash)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to retrieve commit of tag '%s'", latestTag)
+	}
+	return &Tag{Name: latestTag, Date: commit.Committer.When}, nil
+}
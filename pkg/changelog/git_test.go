@@ -0,0 +1,87 @@
+package changelog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommitsAndLatestTag(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	date := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	commit := func(message string) plumbing.Hash {
+		date = date.Add(time.Hour)
+		hash, err := worktree.Commit(message, &git.CommitOptions{Author: &object.Signature{Name: "me", Email: "me@example.org", When: date}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return hash
+	}
+
+	first := commit("feat: first")
+
+	t.Run("no tag", func(t *testing.T) {
+		tag, err := LatestTag(repo, "v", "1.0.0")
+
+		assert.NoError(t, err)
+		assert.Nil(t, tag)
+	})
+
+	t.Run("all commits", func(t *testing.T) {
+		commits, err := Commits(repo, "", "HEAD")
+
+		if assert.NoError(t, err) && assert.Len(t, commits, 1) {
+			assert.Equal(t, first.String(), commits[0].Hash)
+			assert.Equal(t, "first", commits[0].Subject)
+		}
+	})
+
+	_, err = repo.CreateTag("v1.0.0", first, nil)
+	assert.NoError(t, err)
+	second := commit("fix: second")
+	_, err = repo.CreateTag("v1.1.0", second, &git.CreateTagOptions{Tagger: &object.Signature{Name: "me", Email: "me@example.org"}, Message: "v1.1.0"})
+	assert.NoError(t, err)
+	_, err = repo.CreateTag("nightly", second, nil)
+	assert.NoError(t, err)
+	commit("feat: third")
+	fourth := commit("docs: fourth")
+	_, err = repo.CreateTag("v1.2.0", fourth, nil)
+	assert.NoError(t, err)
+
+	t.Run("latest tag", func(t *testing.T) {
+		tag, err := LatestTag(repo, "v", "1.2.0")
+
+		if assert.NoError(t, err) && assert.NotNil(t, tag) {
+			assert.Equal(t, "v1.1.0", tag.Name)
+			assert.True(t, time.Date(2021, 3, 4, 7, 6, 7, 0, time.UTC).Equal(tag.Date))
+		}
+	})
+
+	t.Run("commits since tag", func(t *testing.T) {
+		commits, err := Commits(repo, "v1.1.0", "HEAD")
+
+		if assert.NoError(t, err) && assert.Len(t, commits, 2) {
+			assert.Equal(t, "third", commits[0].Subject)
+			assert.Equal(t, "fourth", commits[1].Subject)
+		}
+	})
+
+	t.Run("unknown revision", func(t *testing.T) {
+		_, err := Commits(repo, "", "unknown")
+
+		assert.EqualError(t, err, "failed to resolve 'unknown': reference not found")
+	})
+}
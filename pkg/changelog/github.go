@@ -0,0 +1,107 @@
+package changelog
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/go-github/v32/github"
+	"github.com/pkg/errors"
+)
+
+// PullRequestClient is the part of the GitHub pull request API required for the changelog
+type PullRequestClient interface {
+	List(ctx context.Context, owner string, repo string, opts *github.PullRequestListOptions) ([]*github.PullRequest, *github.Response, error)
+}
+
+// IssueClient is the part of the GitHub issue API required for the changelog
+type IssueClient interface {
+	ListByRepo(ctx context.Context, owner string, repo string, opts *github.IssueListByRepoOptions) ([]*github.Issue, *github.Response, error)
+}
+
+// MergedPullRequests returns the pull requests which have been merged after 'since' ordered by their merge date.
+// Pull requests having one of the excluded labels are skipped.
+func MergedPullRequests(ctx context.Context, client PullRequestClient, owner, repository string, since time.Time, excludeLabels []string) ([]Item, error) {
+	options := github.PullRequestListOptions{
+		State:       "closed",
+		Sort:        "updated",
+		Direction:   "desc",
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	pullRequests := []*github.PullRequest{}
+	for {
+		result, resp, err := client.List(ctx, owner, repository, &options)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to retrieve pull requests of %v/%v", owner, repository)
+		}
+		done := false
+		for _, pr := range result {
+			if pr.GetUpdatedAt().Before(since) {
+				// pull requests are sorted by their last update, all following ones are older
+				done = true
+				break
+			}
+			if pr.MergedAt == nil || !pr.GetMergedAt().After(since) {
+				continue
+			}
+			pullRequests = append(pullRequests, pr)
+		}
+		if done || resp == nil || resp.NextPage == 0 {
+			break
+		}
+		options.Page = resp.NextPage
+	}
+
+	sort.SliceStable(pullRequests, func(i, j int) bool {
+		return pullRequests[i].GetMergedAt().Before(pullRequests[j].GetMergedAt())
+	})
+	items := []Item{}
+	for _, pr := range pullRequests {
+		labels := []string{}
+		for _, label := range pr.Labels {
+			labels = append(labels, label.GetName())
+		}
+		if containsAny(excludeLabels, labels) {
+			continue
+		}
+		items = append(items, Item{Number: pr.GetNumber(), Title: pr.GetTitle(), URL: pr.GetHTMLURL(), Labels: labels})
+	}
+	return items, nil
+}
+
+// ClosedIssues returns the issues (not pull requests) which have been closed after 'since'.
+// Issues having one of the excluded labels are skipped.
+func ClosedIssues(ctx context.Context, client IssueClient, owner, repository string, since time.Time, excludeLabels []string) ([]Item, error) {
+	options := github.IssueListByRepoOptions{
+		State:       "closed",
+		Direction:   "asc",
+		Since:       since,
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	items := []Item{}
+	for {
+		issues, resp, err := client.ListByRepo(ctx, owner, repository, &options)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to retrieve issues of %v/%v", owner, repository)
+		}
+		for _, issue := range issues {
+			// 'since' filters on the last update, an issue might have been closed before
+			if issue.IsPullRequest() || (issue.ClosedAt != nil && !issue.GetClosedAt().After(since)) {
+				continue
+			}
+			labels := []string{}
+			for _, label := range issue.Labels {
+				labels = append(labels, label.GetName())
+			}
+			if containsAny(excludeLabels, labels) {
+				continue
+			}
+			items = append(items, Item{Number: issue.GetNumber(), Title: issue.GetTitle(), URL: issue.GetHTMLURL(), Labels: labels})
+		}
+		if resp == nil || resp.NextPage == 0 {
+			break
+		}
+		options.Page = resp.NextPage
+	}
+	return items, nil
+}
@@ -0,0 +1,114 @@
+package changelog
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v32/github"
+	"github.com/stretchr/testify/assert"
+)
+
+type pullRequestClientMock struct {
+	pages [][]*github.PullRequest
+	err   error
+}
+
+func (p *pullRequestClientMock) List(ctx context.Context, owner string, repo string, opts *github.PullRequestListOptions) ([]*github.PullRequest, *github.Response, error) {
+	if p.err != nil {
+		return nil, nil, p.err
+	}
+	page := opts.Page
+	if page == 0 {
+		page = 1
+	}
+	resp := &github.Response{}
+	if page < len(p.pages) {
+		resp.NextPage = page + 1
+	}
+	return p.pages[page-1], resp, nil
+}
+
+type issueClientMock struct {
+	issues  []*github.Issue
+	options *github.IssueListByRepoOptions
+	err     error
+}
+
+func (i *issueClientMock) ListByRepo(ctx context.Context, owner string, repo string, opts *github.IssueListByRepoOptions) ([]*github.Issue, *github.Response, error) {
+	i.options = opts
+	return i.issues, &github.Response{}, i.err
+}
+
+func TestMergedPullRequests(t *testing.T) {
+	since := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+	day := func(d int) *time.Time {
+		date := since.AddDate(0, 0, d)
+		return &date
+	}
+
+	t.Run("success", func(t *testing.T) {
+		client := &pullRequestClientMock{pages: [][]*github.PullRequest{
+			{
+				{Number: github.Int(3), Title: github.String("third"), HTMLURL: github.String("https://github.com/o/r/pull/3"), UpdatedAt: day(5), MergedAt: day(4), Labels: []*github.Label{{Name: github.String("bug")}}},
+				{Number: github.Int(4), Title: github.String("not merged"), UpdatedAt: day(4)},
+			},
+			{
+				{Number: github.Int(2), Title: github.String("second"), HTMLURL: github.String("https://github.com/o/r/pull/2"), UpdatedAt: day(3), MergedAt: day(2)},
+				{Number: github.Int(5), Title: github.String("excluded"), UpdatedAt: day(2), MergedAt: day(2), Labels: []*github.Label{{Name: github.String("internal")}}},
+				{Number: github.Int(1), Title: github.String("merged before"), UpdatedAt: day(1), MergedAt: day(-1)},
+				{Number: github.Int(0), Title: github.String("old"), UpdatedAt: day(-2), MergedAt: day(-2)},
+			},
+			{
+				{Number: github.Int(6), Title: github.String("not requested"), UpdatedAt: day(-3), MergedAt: day(-3)},
+			},
+		}}
+
+		items, err := MergedPullRequests(context.Background(), client, "o", "r", since, []string{"internal"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []Item{
+			{Number: 2, Title: "second", URL: "https://github.com/o/r/pull/2", Labels: []string{}},
+			{Number: 3, Title: "third", URL: "https://github.com/o/r/pull/3", Labels: []string{"bug"}},
+		}, items)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		client := &pullRequestClientMock{err: fmt.Errorf("unauthorized")}
+
+		_, err := MergedPullRequests(context.Background(), client, "o", "r", since, nil)
+
+		assert.EqualError(t, err, "failed to retrieve pull requests of o/r: unauthorized")
+	})
+}
+
+func TestClosedIssues(t *testing.T) {
+	since := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+	before := since.AddDate(0, 0, -1)
+	after := since.AddDate(0, 0, 1)
+
+	t.Run("success", func(t *testing.T) {
+		client := &issueClientMock{issues: []*github.Issue{
+			{Number: github.Int(1), Title: github.String("closed before"), ClosedAt: &before},
+			{Number: github.Int(2), Title: github.String("issue"), HTMLURL: github.String("https://github.com/o/r/issues/2"), ClosedAt: &after, Labels: []*github.Label{{Name: github.String("bug")}}},
+			{Number: github.Int(3), Title: github.String("pull request"), ClosedAt: &after, PullRequestLinks: &github.PullRequestLinks{}},
+			{Number: github.Int(4), Title: github.String("excluded"), ClosedAt: &after, Labels: []*github.Label{{Name: github.String("wontfix")}}},
+		}}
+
+		items, err := ClosedIssues(context.Background(), client, "o", "r", since, []string{"wontfix"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []Item{{Number: 2, Title: "issue", URL: "https://github.com/o/r/issues/2", Labels: []string{"bug"}}}, items)
+		assert.Equal(t, "closed", client.options.State)
+		assert.Equal(t, since, client.options.Since)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		client := &issueClientMock{err: fmt.Errorf("unauthorized")}
+
+		_, err := ClosedIssues(context.Background(), client, "o", "r", since, nil)
+
+		assert.EqualError(t, err, "failed to retrieve issues of o/r: unauthorized")
+	})
+}
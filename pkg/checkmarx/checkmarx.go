@@ -160,6 +160,7 @@ type DetailedResult struct {
 // Query - Query Structure
 type Query struct {
 	XMLName xml.Name `xml:"Query"`
+	Name    string   `xml:"name,attr"`
 	Results []Result `xml:"Result"`
 }
 
@@ -169,6 +170,9 @@ type Result struct {
 	State         string   `xml:"state,attr"`
 	Severity      string   `xml:"Severity,attr"`
 	FalsePositive string   `xml:"FalsePositive,attr"`
+	FileName      string   `xml:"FileName,attr"`
+	Line          int      `xml:"Line,attr"`
+	DeepLink      string   `xml:"DeepLink,attr"`
 }
 
 // SystemInstance is the client communicating with the Checkmarx backend
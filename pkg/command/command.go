@@ -18,6 +18,7 @@ import (
 type Command struct {
 	ErrorCategoryMapping map[string][]string
 	dir                  string
+	stdin                io.Reader
 	stdout               io.Writer
 	stderr               io.Writer
 	env                  []string
@@ -62,6 +63,11 @@ func (c *Command) AppendEnv(env []string) {
 	c.env = append(c.env, env...)
 }
 
+// Stdin sets the reader providing the input of executables, e.g. for passing secrets without exposing them as parameter
+func (c *Command) Stdin(stdin io.Reader) {
+	c.stdin = stdin
+}
+
 // Stdout ..
 func (c *Command) Stdout(stdout io.Writer) {
 	c.stdout = stdout
@@ -127,6 +133,10 @@ func (c *Command) RunExecutable(executable string, params ...string) error {
 
 	appendEnvironment(cmd, c.env)
 
+	if c.stdin != nil {
+		cmd.Stdin = c.stdin
+	}
+
 	if err := c.runCmd(cmd); err != nil {
 		return errors.Wrapf(err, "running command '%v' failed", executable)
 	}
@@ -150,6 +160,10 @@ func (c *Command) RunExecutableInBackground(executable string, params ...string)
 
 	appendEnvironment(cmd, c.env)
 
+	if c.stdin != nil {
+		cmd.Stdin = c.stdin
+	}
+
 	execution, err := c.startCmd(cmd)
 
 	if err != nil {
@@ -78,6 +78,15 @@ func TestExecutableRun(t *testing.T) {
 			})
 		})
 
+		t.Run("success case - stdin", func(t *testing.T) {
+			out := new(bytes.Buffer)
+			ex := Command{stdout: out, stderr: stderr}
+			ex.Stdin(strings.NewReader("secret"))
+			ex.RunExecutable("/bin/bash")
+
+			assert.Equal(t, "Stdout: command /bin/bash - Stdin: secret\n", out.String())
+		})
+
 		t.Run("success case - log parsing", func(t *testing.T) {
 			log.SetErrorCategory(log.ErrorUndefined)
 			ex := Command{stdout: stdout, stderr: stderr, ErrorCategoryMapping: map[string][]string{"config": {"command echo"}}}
@@ -0,0 +1,301 @@
+package debugreport
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/command"
+	"github.com/pkg/errors"
+)
+
+const redacted = "****"
+
+// DefaultTools contains the tools (and the arguments for retrieving their version) which are reported if available
+var DefaultTools = map[string][]string{
+	"cf":      {"--version"},
+	"docker":  {"--version"},
+	"git":     {"--version"},
+	"helm":    {"version", "--short"},
+	"java":    {"-version"},
+	"kubectl": {"version", "--client", "--short"},
+	"mvn":     {"--version"},
+	"node":    {"--version"},
+	"npm":     {"--version"},
+}
+
+// FileUtils provides the file system access required for collecting the report content
+type FileUtils interface {
+	Glob(pattern string) (matches []string, err error)
+	FileRead(path string) ([]byte, error)
+	DirExists(path string) (bool, error)
+}
+
+// Report contains the information collected for a failed step
+type Report struct {
+	StepName     string
+	PiperVersion string
+	GitCommit    string
+	Timestamp    time.Time
+	OS           string
+	Arch         string
+	GoVersion    string
+	ToolVersions map[string]string
+	Config       map[string]interface{}
+	LogLines     []string
+	// ErrorDetails maps the file name of an errorDetails.json to its content
+	ErrorDetails map[string][]byte
+	// CommonPipelineEnvironment maps the path of a commonPipelineEnvironment value to its content
+	CommonPipelineEnvironment map[string][]byte
+}
+
+// NewReport creates a report for the given step containing the information about the runtime environment
+func NewReport(stepName, piperVersion, gitCommit string) *Report {
+	return &Report{
+		StepName:                  stepName,
+		PiperVersion:              piperVersion,
+		GitCommit:                 gitCommit,
+		Timestamp:                 time.Now().UTC(),
+		OS:                        runtime.GOOS,
+		Arch:                      runtime.GOARCH,
+		GoVersion:                 runtime.Version(),
+		ToolVersions:              map[string]string{},
+		Config:                    map[string]interface{}{},
+		ErrorDetails:              map[string][]byte{},
+		CommonPipelineEnvironment: map[string][]byte{},
+	}
+}
+
+// RedactConfig returns a copy of the configuration in which the values of secret parameters are masked.
+// Besides the provided secret keys all parameters containing 'password', 'token' or 'secret' in their name are masked.
+func RedactConfig(config map[string]interface{}, secretKeys []string) map[string]interface{} {
+	result := map[string]interface{}{}
+	for key, value := range config {
+		if isSecretKey(key, secretKeys) {
+			if value != nil && fmt.Sprint(value) != "" {
+				value = redacted
+			}
+		} else {
+			value = redactValue(value, secretKeys)
+		}
+		result[key] = value
+	}
+	return result
+}
+
+func redactValue(value interface{}, secretKeys []string) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		return RedactConfig(typed, secretKeys)
+	case []interface{}:
+		result := make([]interface{}, len(typed))
+		for i, item := range typed {
+			result[i] = redactValue(item, secretKeys)
+		}
+		return result
+	}
+	return value
+}
+
+func isSecretKey(key string, secretKeys []string) bool {
+	for _, secretKey := range secretKeys {
+		if key == secretKey {
+			return true
+		}
+	}
+	lower := strings.ToLower(key)
+	// credentials ids only reference a secret, they can be shared
+	if strings.HasSuffix(lower, "credentialsid") {
+		return false
+	}
+	for _, part := range []string{"password", "token", "secret"} {
+		if strings.Contains(lower, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// CollectToolVersions retrieves the versions of the tools which are available on the PATH
+func (r *Report) CollectToolVersions(runner command.ExecRunner, lookPath func(file string) (string, error), tools map[string][]string) {
+	for tool, args := range tools {
+		if _, err := lookPath(tool); err != nil {
+			continue
+		}
+		var output bytes.Buffer
+		runner.Stdout(&output)
+		runner.Stderr(&output)
+		if err := runner.RunExecutable(tool, args...); err != nil {
+			r.ToolVersions[tool] = fmt.Sprintf("n/a (%v)", err)
+			continue
+		}
+		r.ToolVersions[tool] = strings.TrimSpace(strings.SplitN(strings.TrimSpace(output.String()), "\n", 2)[0])
+	}
+}
+
+// CollectErrorDetails reads the errorDetails.json files written by log.FatalHook from the given directory
+func (r *Report) CollectErrorDetails(utils FileUtils, path string) error {
+	files, err := utils.Glob(filepath.Join(path, "*errorDetails.json"))
+	if err != nil {
+		return errors.Wrap(err, "failed to search for error details")
+	}
+	for _, file := range files {
+		content, err := utils.FileRead(file)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read error details '%s'", file)
+		}
+		r.ErrorDetails[filepath.Base(file)] = content
+	}
+	return nil
+}
+
+// CollectCommonPipelineEnvironment reads all values of the commonPipelineEnvironment below the given root path
+func (r *Report) CollectCommonPipelineEnvironment(utils FileUtils, rootPath string) error {
+	cpePath := filepath.Join(rootPath, "commonPipelineEnvironment")
+	files, err := utils.Glob(filepath.Join(cpePath, "**"))
+	if err != nil {
+		return errors.Wrap(err, "failed to search for commonPipelineEnvironment values")
+	}
+	for _, file := range files {
+		if isDir, _ := utils.DirExists(file); isDir {
+			continue
+		}
+		content, err := utils.FileRead(file)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read commonPipelineEnvironment value '%s'", file)
+		}
+		relativePath, err := filepath.Rel(cpePath, file)
+		if err != nil {
+			relativePath = file
+		}
+		relativePath = filepath.ToSlash(relativePath)
+		r.CommonPipelineEnvironment[relativePath] = redactCommonPipelineEnvironmentValue(relativePath, content)
+	}
+	return nil
+}
+
+// redactCommonPipelineEnvironmentValue masks values with secret names, JSON values are redacted like the step configuration
+func redactCommonPipelineEnvironmentValue(path string, content []byte) []byte {
+	for _, part := range strings.Split(strings.TrimSuffix(path, ".json"), "/") {
+		if isSecretKey(part, nil) {
+			return []byte(redacted)
+		}
+	}
+	if !strings.HasSuffix(path, ".json") {
+		return content
+	}
+	var value interface{}
+	if err := json.Unmarshal(content, &value); err != nil {
+		// the content cannot be inspected
+		return []byte(redacted)
+	}
+	redactedContent, err := json.Marshal(redactValue(value, nil))
+	if err != nil {
+		return []byte(redacted)
+	}
+	return redactedContent
+}
+
+// ArchiveName returns the name of the archive file for the report
+func (r *Report) ArchiveName() string {
+	return fmt.Sprintf("%s_debugReport_%s.tar.gz", r.StepName, r.Timestamp.Format("2006-01-02-15-04-05"))
+}
+
+// WriteArchive writes the report as gzipped tar archive
+func (r *Report) WriteArchive(w io.Writer) error {
+	gzipWriter := gzip.NewWriter(w)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	configJSON, err := json.MarshalIndent(r.Config, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal step configuration")
+	}
+
+	files := map[string][]byte{
+		"debugReport.md": []byte(r.Markdown()),
+		"config.json":    configJSON,
+		"log.txt":        []byte(strings.Join(r.LogLines, "\n") + "\n"),
+	}
+	for name, content := range r.ErrorDetails {
+		files["errorDetails/"+name] = content
+	}
+	for name, content := range r.CommonPipelineEnvironment {
+		files["commonPipelineEnvironment/"+name] = content
+	}
+
+	for _, name := range sortedKeys(files) {
+		header := &tar.Header{Name: name, Mode: 0644, Size: int64(len(files[name])), ModTime: r.Timestamp}
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return errors.Wrapf(err, "failed to add '%s' to debug report", name)
+		}
+		if _, err := tarWriter.Write(files[name]); err != nil {
+			return errors.Wrapf(err, "failed to add '%s' to debug report", name)
+		}
+	}
+
+	if err := tarWriter.Close(); err != nil {
+		return errors.Wrap(err, "failed to write debug report")
+	}
+	return gzipWriter.Close()
+}
+
+// Markdown returns a summary of the report in Markdown format
+func (r *Report) Markdown() string {
+	var md strings.Builder
+	md.WriteString(fmt.Sprintf("# Debug report for step %s\n\n", r.StepName))
+	md.WriteString(fmt.Sprintf("Generated: %s (UTC)\n\n", r.Timestamp.Format("2006-01-02 15:04")))
+
+	md.WriteString("## Environment\n\n")
+	md.WriteString("| Name | Version |\n|------|---------|\n")
+	md.WriteString(fmt.Sprintf("| piper | %s (commit: %s) |\n", valueOrNA(r.PiperVersion), valueOrNA(r.GitCommit)))
+	md.WriteString(fmt.Sprintf("| OS | %s/%s |\n", r.OS, r.Arch))
+	md.WriteString(fmt.Sprintf("| Go | %s |\n", r.GoVersion))
+	for _, tool := range sortedKeys(r.ToolVersions) {
+		md.WriteString(fmt.Sprintf("| %s | %s |\n", tool, r.ToolVersions[tool]))
+	}
+
+	md.WriteString("\n## Error details\n\n")
+	if len(r.ErrorDetails) == 0 {
+		md.WriteString("No error details available.\n")
+	}
+	for _, name := range sortedKeys(r.ErrorDetails) {
+		md.WriteString(fmt.Sprintf("### %s\n\n```json\n%s\n```\n\n", name, strings.TrimSpace(string(r.ErrorDetails[name]))))
+	}
+
+	md.WriteString("\n## Log (last lines)\n\n```\n")
+	md.WriteString(strings.Join(r.LogLines, "\n"))
+	md.WriteString("\n```\n\n")
+	md.WriteString("The (redacted) step configuration as well as the content of the commonPipelineEnvironment are part of the archive.\n")
+	return md.String()
+}
+
+func valueOrNA(value string) string {
+	if len(value) == 0 {
+		return "n/a"
+	}
+	return value
+}
+
+func sortedKeys(m interface{}) []string {
+	keys := []string{}
+	switch typed := m.(type) {
+	case map[string][]byte:
+		for key := range typed {
+			keys = append(keys, key)
+		}
+	case map[string]string:
+		for key := range typed {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
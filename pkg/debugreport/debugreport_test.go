@@ -0,0 +1,124 @@
+package debugreport
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactConfig(t *testing.T) {
+	config := map[string]interface{}{
+		"username":          "me",
+		"password":          "secret",
+		"githubToken":       "token",
+		"emptySecret":       "",
+		"credentialsId":     "myCredentials",
+		"dockerConfigJSON":  "/path/config.json",
+		"nested":            map[string]interface{}{"apiToken": "token", "url": "https://example.org"},
+		"buildSettingsInfo": "{}",
+		"targets":           []interface{}{map[string]interface{}{"name": "dev", "password": "secret"}, "plain"},
+	}
+
+	result := RedactConfig(config, []string{"username", "dockerConfigJSON"})
+
+	assert.Equal(t, map[string]interface{}{
+		"username":          "****",
+		"password":          "****",
+		"githubToken":       "****",
+		"emptySecret":       "",
+		"credentialsId":     "myCredentials",
+		"dockerConfigJSON":  "****",
+		"nested":            map[string]interface{}{"apiToken": "****", "url": "https://example.org"},
+		"buildSettingsInfo": "{}",
+		"targets":           []interface{}{map[string]interface{}{"name": "dev", "password": "****"}, "plain"},
+	}, result)
+	assert.Equal(t, "secret", config["password"], "original configuration must not be changed")
+}
+
+func TestCollectToolVersions(t *testing.T) {
+	runner := &mock.ExecMockRunner{
+		StdoutReturn:        map[string]string{"git --version": "git version 2.30.0\n"},
+		ShouldFailOnCommand: map[string]error{"mvn --version": fmt.Errorf("exit status 1")},
+	}
+	lookPath := func(file string) (string, error) {
+		if file == "node" {
+			return "", fmt.Errorf("not found")
+		}
+		return "/usr/bin/" + file, nil
+	}
+	report := NewReport("testStep", "v1.0.0", "abcdef")
+
+	report.CollectToolVersions(runner, lookPath, map[string][]string{"git": {"--version"}, "mvn": {"--version"}, "node": {"--version"}})
+
+	assert.Equal(t, map[string]string{"git": "git version 2.30.0", "mvn": "n/a (exit status 1)"}, report.ToolVersions)
+}
+
+func TestCollectFiles(t *testing.T) {
+	utils := &mock.FilesMock{}
+	utils.AddFile("testStep_errorDetails.json", []byte(`{"message":"failed"}`))
+	utils.AddFile(".pipeline/commonPipelineEnvironment/artifactVersion", []byte("1.2.3"))
+	utils.AddFile(".pipeline/commonPipelineEnvironment/git/commitId", []byte("abcdef"))
+	utils.AddFile(".pipeline/commonPipelineEnvironment/custom/repositoryPassword", []byte("secret"))
+	utils.AddFile(".pipeline/commonPipelineEnvironment/custom/deployment.json", []byte(`{"url":"https://example.org","credentials":[{"token":"secret"}]}`))
+	report := NewReport("testStep", "", "")
+
+	assert.NoError(t, report.CollectErrorDetails(utils, "."))
+	assert.NoError(t, report.CollectCommonPipelineEnvironment(utils, ".pipeline"))
+
+	assert.Equal(t, map[string][]byte{"testStep_errorDetails.json": []byte(`{"message":"failed"}`)}, report.ErrorDetails)
+	assert.Equal(t, map[string][]byte{
+		"artifactVersion":           []byte("1.2.3"),
+		"git/commitId":              []byte("abcdef"),
+		"custom/repositoryPassword": []byte("****"),
+		"custom/deployment.json":    []byte(`{"credentials":[{"token":"****"}],"url":"https://example.org"}`),
+	}, report.CommonPipelineEnvironment)
+}
+
+func TestWriteArchive(t *testing.T) {
+	report := NewReport("testStep", "v1.0.0", "abcdef")
+	report.Timestamp = time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	report.ToolVersions["git"] = "git version 2.30.0"
+	report.Config = map[string]interface{}{"password": "****"}
+	report.LogLines = []string{"info  testStep - doing something", "fatal testStep - step execution failed"}
+	report.ErrorDetails["testStep_errorDetails.json"] = []byte(`{"message":"failed"}`)
+	report.CommonPipelineEnvironment["git/commitId"] = []byte("abcdef")
+
+	assert.Equal(t, "testStep_debugReport_2021-03-04-05-06-07.tar.gz", report.ArchiveName())
+
+	var archive bytes.Buffer
+	err := report.WriteArchive(&archive)
+
+	if assert.NoError(t, err) {
+		files := readArchive(t, archive.Bytes())
+		assert.Equal(t, []string{"commonPipelineEnvironment/git/commitId", "config.json", "debugReport.md", "errorDetails/testStep_errorDetails.json", "log.txt"}, sortedKeys(files))
+		assert.Contains(t, string(files["debugReport.md"]), "| piper | v1.0.0 (commit: abcdef) |")
+		assert.Contains(t, string(files["debugReport.md"]), "| git | git version 2.30.0 |")
+		assert.Contains(t, string(files["debugReport.md"]), "fatal testStep - step execution failed")
+		assert.Contains(t, string(files["config.json"]), `"password": "****"`)
+		assert.Equal(t, "abcdef", string(files["commonPipelineEnvironment/git/commitId"]))
+	}
+}
+
+func readArchive(t *testing.T, content []byte) map[string][]byte {
+	gzipReader, err := gzip.NewReader(bytes.NewReader(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tarReader := tar.NewReader(gzipReader)
+	files := map[string][]byte{}
+	for {
+		header, err := tarReader.Next()
+		if err != nil {
+			break
+		}
+		files[header.Name], _ = ioutil.ReadAll(tarReader)
+	}
+	return files
+}
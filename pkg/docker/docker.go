@@ -9,6 +9,9 @@ import (
 	pkgutil "github.com/GoogleContainerTools/container-diff/pkg/util"
 	"github.com/google/go-containerregistry/pkg/legacy/tarball"
 	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	v1tarball "github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/pkg/errors"
 )
 
 // Client defines an docker client object
@@ -99,3 +102,16 @@ func (c *Client) TarImage(writer io.Writer, image pkgutil.Image) error {
 	}
 	return nil
 }
+
+//ImageFileSystem provides the flattened file system of an image saved as tar file as tar stream together with the digest of the image
+func ImageFileSystem(tarFilePath string) (io.ReadCloser, string, error) {
+	image, err := v1tarball.ImageFromPath(tarFilePath, nil)
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "failed to read image from %v", tarFilePath)
+	}
+	digest, err := image.Digest()
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "failed to get digest of image %v", tarFilePath)
+	}
+	return mutate.Extract(image), digest.String(), nil
+}
@@ -1,8 +1,16 @@
 package docker
 
 import (
+	"archive/tar"
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
 	"testing"
 
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	v1tarball "github.com/google/go-containerregistry/pkg/v1/tarball"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -34,3 +42,46 @@ func TestGetImageSource(t *testing.T) {
 		assert.Equal(t, c.want, got)
 	}
 }
+
+func TestImageFileSystem(t *testing.T) {
+	t.Run("success case", func(t *testing.T) {
+		var layer bytes.Buffer
+		writer := tar.NewWriter(&layer)
+		content := []byte("ID=alpine\n")
+		assert.NoError(t, writer.WriteHeader(&tar.Header{Name: "etc/os-release", Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err := writer.Write(content)
+		assert.NoError(t, err)
+		assert.NoError(t, writer.Close())
+
+		imageLayer, err := v1tarball.LayerFromReader(bytes.NewReader(layer.Bytes()))
+		assert.NoError(t, err)
+		image, err := mutate.AppendLayers(empty.Image, imageLayer)
+		assert.NoError(t, err)
+		tag, err := name.NewTag("my.registry/image:1.0")
+		assert.NoError(t, err)
+		tarFilePath := filepath.Join(t.TempDir(), "image.tar")
+		assert.NoError(t, v1tarball.WriteToFile(tarFilePath, tag, image))
+		expectedDigest, err := image.Digest()
+		assert.NoError(t, err)
+
+		filesystem, digest, err := ImageFileSystem(tarFilePath)
+
+		if assert.NoError(t, err) {
+			defer filesystem.Close()
+			assert.Equal(t, expectedDigest.String(), digest)
+			reader := tar.NewReader(filesystem)
+			header, err := reader.Next()
+			if assert.NoError(t, err) {
+				assert.Equal(t, "etc/os-release", header.Name)
+				actual, _ := ioutil.ReadAll(reader)
+				assert.Equal(t, content, actual)
+			}
+		}
+	})
+
+	t.Run("error case", func(t *testing.T) {
+		_, _, err := ImageFileSystem(filepath.Join(t.TempDir(), "missing.tar"))
+
+		assert.Contains(t, err.Error(), "failed to read image from")
+	})
+}
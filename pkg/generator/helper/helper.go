@@ -501,7 +501,7 @@ func setDefaultParameters(stepData *config.StepData) (bool, error) {
 			case "[]string":
 				// ToDo: Check if default should be read from env
 				param.Default = "[]string{}"
-			case "map[string]interface{}":
+			case "map[string]interface{}", "[]map[string]interface{}":
 				// Currently we don't need to set a default here since in this case the default
 				// is never used. Needs to be changed in case we enable cli parameter handling
 				// for that type.
@@ -522,7 +522,7 @@ func setDefaultParameters(stepData *config.StepData) (bool, error) {
 				param.Default = fmt.Sprintf("`%v`", param.Default)
 			case "[]string":
 				param.Default = fmt.Sprintf("[]string{`%v`}", strings.Join(getStringSliceFromInterface(param.Default), "`, `"))
-			case "map[string]interface{}":
+			case "map[string]interface{}", "[]map[string]interface{}":
 				// Currently we don't need to set a default here since in this case the default
 				// is never used. Needs to be changed in case we enable cli parameter handling
 				// for that type.
@@ -658,7 +658,7 @@ func MetadataFiles(sourceDirectory string) ([]string, error) {
 }
 
 func isCLIParam(myType string) bool {
-	return myType != "map[string]interface{}"
+	return myType != "map[string]interface{}" && myType != "[]map[string]interface{}"
 }
 
 func stepTemplate(myStepInfo stepInfo, templateName, goTemplate string) []byte {
@@ -146,6 +146,7 @@ func TestSetDefaultParameters(t *testing.T) {
 						{Name: "param5", Type: "[]string"},
 						{Name: "param6", Type: "int"},
 						{Name: "param7", Type: "int", Default: 1},
+						{Name: "param8", Type: "[]map[string]interface{}"},
 					},
 				},
 			},
@@ -171,6 +172,7 @@ func TestSetDefaultParameters(t *testing.T) {
 		for k, v := range expected {
 			assert.Equal(t, v, stepData.Spec.Inputs.Parameters[k].Default, fmt.Sprintf("default not correct for parameter %v", k))
 		}
+		assert.Nil(t, stepData.Spec.Inputs.Parameters[8].Default, "no default expected for map parameters")
 	})
 
 	t.Run("error case", func(t *testing.T) {
@@ -0,0 +1,96 @@
+package git
+
+import (
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	gitssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+)
+
+// tokenUsername is the user name used together with GitHub App installation tokens
+const tokenUsername = "x-access-token"
+
+// AuthOptions define the credentials used to access a remote repository.
+// For ssh URLs (e.g. git@github.com:org/repo.git) the SSH settings are used, for http(s) URLs the token or username/password.
+type AuthOptions struct {
+	// Username is used for http(s) access only, the ssh user is taken from the URL.
+	Username string
+	Password string
+	// Token is used instead of the password, e.g. a personal access token or a GitHub App installation token.
+	// In case no username is provided, "x-access-token" is used as required for GitHub App installation tokens.
+	Token string
+	// SSHPrivateKey contains the PEM encoded private key. In case it is empty the keys provided by the ssh-agent are used.
+	SSHPrivateKey           string
+	SSHPrivateKeyPassphrase string
+	// SSHKnownHostsFile is used to verify the host key of the server.
+	// In case it is empty the files defined via SSH_KNOWN_HOSTS or ~/.ssh/known_hosts are used.
+	SSHKnownHostsFile string
+	// SSHInsecureIgnoreHostKey disables the verification of the host key
+	SSHInsecureIgnoreHostKey bool
+}
+
+// isSSHURL returns true for URLs which are accessed via ssh, including the scp-like syntax user@host:path
+func isSSHURL(url string) bool {
+	endpoint, err := transport.NewEndpoint(url)
+	if err != nil {
+		return false
+	}
+	return endpoint.Protocol == "ssh"
+}
+
+// authMethod returns the authentication matching the protocol of the url
+func (a AuthOptions) authMethod(url string) (transport.AuthMethod, error) {
+	if isSSHURL(url) {
+		return a.sshAuthMethod(url)
+	}
+	if len(a.Token) > 0 {
+		username := a.Username
+		if len(username) == 0 {
+			username = tokenUsername
+		}
+		return &http.BasicAuth{Username: username, Password: a.Token}, nil
+	}
+	if len(a.Username) == 0 && len(a.Password) == 0 {
+		// e.g. public repositories
+		return nil, nil
+	}
+	return &http.BasicAuth{Username: a.Username, Password: a.Password}, nil
+}
+
+// sshAuthMethod uses the user of the url (e.g. deploy@host:org/repo.git) and "git" in case the url contains none
+func (a AuthOptions) sshAuthMethod(url string) (transport.AuthMethod, error) {
+	endpoint, err := transport.NewEndpoint(url)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse url '%v'", url)
+	}
+	username := endpoint.User
+	if len(username) == 0 {
+		username = gitssh.DefaultUsername
+	}
+
+	var hostKeyCallback ssh.HostKeyCallback
+	if a.SSHInsecureIgnoreHostKey {
+		hostKeyCallback = ssh.InsecureIgnoreHostKey()
+	} else if len(a.SSHKnownHostsFile) > 0 {
+		hostKeyCallback, err = gitssh.NewKnownHostsCallback(a.SSHKnownHostsFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read known hosts file '%v'", a.SSHKnownHostsFile)
+		}
+	}
+
+	if len(a.SSHPrivateKey) == 0 {
+		auth, err := gitssh.NewSSHAgentAuth(username)
+		if err != nil {
+			return nil, errors.Wrap(err, "no ssh key provided and ssh-agent not available")
+		}
+		auth.HostKeyCallback = hostKeyCallback
+		return auth, nil
+	}
+	auth, err := gitssh.NewPublicKeys(username, []byte(a.SSHPrivateKey), a.SSHPrivateKeyPassphrase)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read ssh key")
+	}
+	auth.HostKeyCallback = hostKeyCallback
+	return auth, nil
+}
@@ -1,12 +1,23 @@
 package git
 
 import (
+	"fmt"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/format/diff"
 	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
 	"github.com/go-git/go-git/v5/plumbing/transport/http"
 	"github.com/pkg/errors"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 )
 
@@ -52,16 +63,136 @@ func commitSingleFile(filePath, commitMessage, author string, worktree utilsWork
 	return commit, nil
 }
 
+// CommitFiles commits the files located in the relative file paths with the commitMessage to the given worktree.
+// In contrast to CommitSingleFile only the given files are committed which allows committing in a sparse checkout.
+func CommitFiles(filePaths []string, commitMessage, author string, worktree *git.Worktree) (plumbing.Hash, error) {
+	return commitFiles(filePaths, commitMessage, author, worktree)
+}
+
+func commitFiles(filePaths []string, commitMessage, author string, worktree utilsWorkTree) (plumbing.Hash, error) {
+	for _, filePath := range filePaths {
+		_, err := worktree.Add(filePath)
+		if err != nil {
+			return [20]byte{}, errors.Wrapf(err, "failed to add file '%v' to git", filePath)
+		}
+	}
+
+	commit, err := worktree.Commit(commitMessage, &git.CommitOptions{
+		Author: &object.Signature{Name: author, When: time.Now()},
+	})
+	if err != nil {
+		return [20]byte{}, errors.Wrap(err, "failed to commit files")
+	}
+
+	return commit, nil
+}
+
+// HasChanges returns true if files located in the given paths are modified, added or deleted in the worktree.
+// Only the given paths are considered, since files outside of a sparse checkout are reported as deleted.
+func HasChanges(worktree *git.Worktree, paths []string) (bool, error) {
+	status, err := worktree.Status()
+	if err != nil {
+		return false, errors.Wrap(err, "failed to retrieve status")
+	}
+	for fileName, fileStatus := range status {
+		if !inPaths(fileName, paths) {
+			continue
+		}
+		if fileStatus.Worktree != git.Unmodified || fileStatus.Staging != git.Unmodified {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// IsPushRejected returns true if the push failed since the remote branch contains commits which are not available locally
+func IsPushRejected(err error) bool {
+	if err == nil {
+		return false
+	}
+	message := err.Error()
+	return strings.Contains(message, "non-fast-forward") || strings.Contains(message, "fetch first")
+}
+
 // PushChangesToRepository Pushes all committed changes in the repository to the remote repository
 func PushChangesToRepository(username, password string, repository *git.Repository) error {
 	return pushChangesToRepository(username, password, repository)
 }
 
 func pushChangesToRepository(username, password string, repository utilsRepository) error {
-	pushOptions := &git.PushOptions{
-		Auth: &http.BasicAuth{Username: username, Password: password},
+	return push(&http.BasicAuth{Username: username, Password: password}, nil, repository)
+}
+
+// PushChanges pushes all committed changes in the repository to the remote repository.
+// The authentication method is chosen based on the URL of the remote.
+func PushChanges(repository *git.Repository, auth AuthOptions) error {
+	remote, err := repository.Remote(git.DefaultRemoteName)
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve remote")
 	}
-	err := repository.Push(pushOptions)
+	url := remote.Config().URLs[0]
+	err = pushChanges(url, auth, nil, repository)
+	if errors.Cause(err) == plumbing.ErrObjectNotFound && isRemoteBranchUnknown(repository, remote, url, auth) {
+		// in a shallow clone go-git fails to walk the history up to the updated remote branch while checking for a fast-forward update
+		return errors.Wrap(fmt.Errorf("non-fast-forward update: %v", remoteBranchName(repository)), "failed to push commit")
+	}
+	return err
+}
+
+// isRemoteBranchUnknown returns true if the remote branch of HEAD points to a commit which is not available locally,
+// i.e. the remote branch contains commits which were pushed after the clone
+func isRemoteBranchUnknown(repository *git.Repository, remote *git.Remote, url string, auth AuthOptions) bool {
+	authMethod, err := auth.authMethod(url)
+	if err != nil {
+		return false
+	}
+	refs, err := remote.List(&git.ListOptions{Auth: authMethod})
+	if err != nil {
+		return false
+	}
+	branchName := remoteBranchName(repository)
+	for _, ref := range refs {
+		if ref.Name() == branchName {
+			_, err := repository.CommitObject(ref.Hash())
+			return err == plumbing.ErrObjectNotFound
+		}
+	}
+	return false
+}
+
+func remoteBranchName(repository *git.Repository) plumbing.ReferenceName {
+	head, err := repository.Head()
+	if err != nil {
+		return ""
+	}
+	return head.Name()
+}
+
+// PushChangesToBranch pushes the checked out branch to the given branch of the remote repository.
+// An existing remote branch is overwritten.
+func PushChangesToBranch(repository *git.Repository, branchName string, auth AuthOptions) error {
+	head, err := repository.Head()
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve HEAD")
+	}
+	remote, err := repository.Remote(git.DefaultRemoteName)
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve remote")
+	}
+	refSpec := config.RefSpec(fmt.Sprintf("+%v:%v", head.Name(), plumbing.NewBranchReferenceName(branchName)))
+	return pushChanges(remote.Config().URLs[0], auth, []config.RefSpec{refSpec}, repository)
+}
+
+func pushChanges(url string, auth AuthOptions, refSpecs []config.RefSpec, repository utilsRepository) error {
+	authMethod, err := auth.authMethod(url)
+	if err != nil {
+		return errors.Wrap(err, "failed to prepare authentication")
+	}
+	return push(authMethod, refSpecs, repository)
+}
+
+func push(authMethod transport.AuthMethod, refSpecs []config.RefSpec, repository utilsRepository) error {
+	err := repository.Push(&git.PushOptions{Auth: authMethod, RefSpecs: refSpecs})
 	if err != nil {
 		return errors.Wrap(err, "failed to push commit")
 	}
@@ -86,6 +217,125 @@ func plainClone(username, password, serverURL, directory string, abstractionGit
 	return repository, nil
 }
 
+// CloneOptions define how a repository is cloned
+type CloneOptions struct {
+	Auth AuthOptions
+	// Branch is checked out instead of the default branch of the remote
+	Branch string
+	// SingleBranch only fetches the history of the checked out branch
+	SingleBranch bool
+	// Depth limits the number of fetched commits, 0 fetches the complete history
+	Depth int
+	// SparseCheckoutPaths limits the files written to the worktree to the given directories or files.
+	// The index still contains all files, therefore only changes added explicitly (e.g. via CommitFiles) must be committed.
+	SparseCheckoutPaths []string
+}
+
+// Clone clones a non-bare repository to the provided directory
+func Clone(serverURL, directory string, options CloneOptions) (*git.Repository, error) {
+	abstractedGit := &abstractionGit{}
+	return clone(serverURL, directory, options, abstractedGit)
+}
+
+func clone(serverURL, directory string, options CloneOptions, abstractionGit utilsGit) (*git.Repository, error) {
+	authMethod, err := options.Auth.authMethod(serverURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to prepare authentication")
+	}
+	gitCloneOptions := git.CloneOptions{
+		Auth:         authMethod,
+		URL:          serverURL,
+		SingleBranch: options.SingleBranch,
+		Depth:        options.Depth,
+		NoCheckout:   len(options.SparseCheckoutPaths) > 0,
+	}
+	if len(options.Branch) > 0 {
+		gitCloneOptions.ReferenceName = plumbing.NewBranchReferenceName(options.Branch)
+	}
+	repository, err := abstractionGit.plainClone(directory, false, &gitCloneOptions)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to clone git")
+	}
+	if gitCloneOptions.NoCheckout {
+		err = sparseCheckout(repository, options.SparseCheckoutPaths)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to check out files")
+		}
+	}
+	return repository, nil
+}
+
+// sparseCheckout writes the files of HEAD located in the given paths to the worktree.
+// go-git does not support the skip-worktree flag, therefore the index contains all files of HEAD.
+func sparseCheckout(repository *git.Repository, paths []string) error {
+	head, err := repository.Head()
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve HEAD")
+	}
+	worktree, err := repository.Worktree()
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve worktree")
+	}
+	// only updates the index, the worktree is left untouched
+	err = worktree.Reset(&git.ResetOptions{Commit: head.Hash(), Mode: git.MixedReset})
+	if err != nil {
+		return errors.Wrap(err, "failed to update index")
+	}
+	commit, err := repository.CommitObject(head.Hash())
+	if err != nil {
+		return errors.Wrapf(err, "failed to retrieve commit '%v'", head.Hash())
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve tree")
+	}
+
+	return tree.Files().ForEach(func(file *object.File) error {
+		if !inPaths(file.Name, paths) {
+			return nil
+		}
+		reader, err := file.Reader()
+		if err != nil {
+			return errors.Wrapf(err, "failed to read '%v'", file.Name)
+		}
+		defer reader.Close()
+		content, err := ioutil.ReadAll(reader)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read '%v'", file.Name)
+		}
+
+		fs := worktree.Filesystem
+		if err := fs.MkdirAll(path.Dir(file.Name), 0755); err != nil {
+			return errors.Wrapf(err, "failed to create directory for '%v'", file.Name)
+		}
+		if file.Mode == filemode.Symlink {
+			return fs.Symlink(string(content), file.Name)
+		}
+		perm, err := file.Mode.ToOSFileMode()
+		if err != nil {
+			return errors.Wrapf(err, "invalid file mode of '%v'", file.Name)
+		}
+		target, err := fs.OpenFile(file.Name, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, perm)
+		if err != nil {
+			return errors.Wrapf(err, "failed to write '%v'", file.Name)
+		}
+		defer target.Close()
+		_, err = target.Write(content)
+		return errors.Wrapf(err, "failed to write '%v'", file.Name)
+	})
+}
+
+// inPaths returns true if the file is located in one of the paths (relative to the repository root)
+func inPaths(fileName string, paths []string) bool {
+	for _, p := range paths {
+		p = strings.Trim(path.Clean(filepath.ToSlash(p)), "/")
+		if p == "." || p == "" || fileName == p || strings.HasPrefix(fileName, p+"/") {
+			return true
+		}
+	}
+	return false
+}
+
 // PlainOpen opens a git repository from the given path
 func PlainOpen(path string) (*git.Repository, error) {
 	abstractedGit := &abstractionGit{}
@@ -157,6 +407,91 @@ func LogRange(repo *git.Repository, from, to string) (object.CommitIter, error)
 	return object.NewCommitPreorderIter(cTo, map[plumbing.Hash]bool{}, ignore), nil
 }
 
+// CommitAuthors returns the sorted, distinct e-mail addresses of the authors of all commits
+// reachable from 'to', but not reachable by 'from'. In case 'from' is empty only the author
+// of 'to' is returned.
+func CommitAuthors(repo *git.Repository, from, to string) ([]string, error) {
+	authors := map[string]bool{}
+	if len(from) == 0 {
+		c, err := getCommitObject(to, repo)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Cannot provide commit authors (to: '%s' not found)", to)
+		}
+		authors[c.Author.Email] = true
+	} else {
+		cIter, err := LogRange(repo, from, to)
+		if err != nil {
+			return nil, err
+		}
+		err = cIter.ForEach(func(c *object.Commit) error {
+			authors[c.Author.Email] = true
+			return nil
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "Cannot provide commit authors")
+		}
+	}
+
+	result := []string{}
+	for author := range authors {
+		if len(author) > 0 {
+			result = append(result, author)
+		}
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// ChangedLines returns the line numbers per file which have been added or modified on 'head' since it diverged from 'base',
+// as shown in the diff of a pull request. Deleted and binary files are omitted.
+func ChangedLines(repo *git.Repository, base, head string) (map[string][]int, error) {
+	headCommit, err := getCommitObject(head, repo)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Cannot provide changed lines (head: '%s' not found)", head)
+	}
+	baseCommit, err := getCommitObject(base, repo)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Cannot provide changed lines (base: '%s' not found)", base)
+	}
+	mergeBases, err := baseCommit.MergeBase(headCommit)
+	if err != nil || len(mergeBases) == 0 {
+		return nil, errors.Errorf("Cannot provide changed lines (no common ancestor of '%s' and '%s')", base, head)
+	}
+	patch, err := mergeBases[0].Patch(headCommit)
+	if err != nil {
+		return nil, errors.Wrap(err, "Cannot provide changed lines")
+	}
+
+	changedLines := map[string][]int{}
+	for _, filePatch := range patch.FilePatches() {
+		_, to := filePatch.Files()
+		if to == nil || filePatch.IsBinary() {
+			continue
+		}
+		lines := []int{}
+		line := 1
+		for _, chunk := range filePatch.Chunks() {
+			count := strings.Count(chunk.Content(), "\n")
+			if !strings.HasSuffix(chunk.Content(), "\n") {
+				count++
+			}
+			switch chunk.Type() {
+			case diff.Equal:
+				line += count
+			case diff.Add:
+				for i := 0; i < count; i++ {
+					lines = append(lines, line)
+					line++
+				}
+			}
+		}
+		if len(lines) > 0 {
+			changedLines[to.Path()] = lines
+		}
+	}
+	return changedLines, nil
+}
+
 func getCommitObject(ref string, repo *git.Repository) (*object.Commit, error) {
 	if len(ref) == 0 {
 		// with go-git v5.1.0 we panic otherwise inside ResolveRevision
@@ -5,12 +5,22 @@ import (
 	"fmt"
 	"github.com/go-git/go-billy/v5"
 	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-billy/v5/util"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
+	gitssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
 	"github.com/go-git/go-git/v5/storage/memory"
+	pkgErrors "github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestCommit(t *testing.T) {
@@ -42,6 +52,35 @@ func TestCommit(t *testing.T) {
 	})
 }
 
+func TestCommitFiles(t *testing.T) {
+	t.Parallel()
+	t.Run("successful run", func(t *testing.T) {
+		t.Parallel()
+		worktreeMock := WorktreeMock{}
+		hash, err := commitFiles([]string{"dev/deployment.yaml", "prod/deployment.yaml"}, "message", "user", &worktreeMock)
+		assert.NoError(t, err)
+		assert.Equal(t, plumbing.Hash([20]byte{4, 5, 6}), hash)
+		assert.Equal(t, "user", worktreeMock.author)
+		assert.False(t, worktreeMock.commitAll)
+	})
+
+	t.Run("error adding file", func(t *testing.T) {
+		t.Parallel()
+		_, err := commitFiles([]string{"deployment.yaml"}, "message", "user", WorktreeMockFailing{
+			failingAdd: true,
+		})
+		assert.EqualError(t, err, "failed to add file 'deployment.yaml' to git: failed to add file")
+	})
+
+	t.Run("error committing file", func(t *testing.T) {
+		t.Parallel()
+		_, err := commitFiles([]string{"deployment.yaml"}, "message", "user", WorktreeMockFailing{
+			failingCommit: true,
+		})
+		assert.EqualError(t, err, "failed to commit files: failed to commit file")
+	})
+}
+
 func TestPushChangesToRepository(t *testing.T) {
 	t.Parallel()
 	t.Run("successful push", func(t *testing.T) {
@@ -59,6 +98,65 @@ func TestPushChangesToRepository(t *testing.T) {
 	})
 }
 
+func TestPushChanges(t *testing.T) {
+	t.Parallel()
+	t.Run("successful push", func(t *testing.T) {
+		t.Parallel()
+		err := pushChanges("https://github.com/org/repo", AuthOptions{Username: "user", Token: "token"}, nil, RepositoryMock{
+			test: t,
+		})
+		assert.NoError(t, err)
+	})
+
+	t.Run("error on authentication", func(t *testing.T) {
+		t.Parallel()
+		err := pushChanges("git@github.com:org/repo.git", AuthOptions{SSHPrivateKey: "no key"}, nil, RepositoryMock{})
+		assert.Contains(t, fmt.Sprint(err), "failed to prepare authentication: failed to read ssh key")
+	})
+
+	t.Run("error pushing", func(t *testing.T) {
+		t.Parallel()
+		err := pushChanges("https://github.com/org/repo", AuthOptions{}, nil, RepositoryMockError{})
+		assert.EqualError(t, err, "failed to push commit: error on push commits")
+	})
+}
+
+func TestPushChangesFromShallowClone(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "shallow-push")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	commit := func(repo *git.Repository, name string) {
+		worktree, err := repo.Worktree()
+		require.NoError(t, err)
+		require.NoError(t, util.WriteFile(worktree.Filesystem, name, []byte(name), 0644))
+		_, err = worktree.Add(name)
+		require.NoError(t, err)
+		_, err = worktree.Commit(name, &git.CommitOptions{Author: &object.Signature{Name: "Project Piper", When: time.Now()}})
+		require.NoError(t, err)
+	}
+
+	remoteDir := filepath.Join(dir, "remote")
+	remote, err := git.PlainInit(remoteDir, false)
+	require.NoError(t, err)
+	for _, name := range []string{"first.txt", "second.txt", "third.txt"} {
+		commit(remote, name)
+	}
+	head, err := remote.Head()
+	require.NoError(t, err)
+
+	clone, err := Clone("file://"+remoteDir, filepath.Join(dir, "clone"), CloneOptions{Branch: head.Name().Short(), SingleBranch: true, Depth: 1})
+	require.NoError(t, err)
+	// another pipeline updated the branch meanwhile
+	commit(remote, "concurrent.txt")
+	commit(clone, "update.txt")
+
+	err = PushChanges(clone, AuthOptions{})
+
+	assert.True(t, IsPushRejected(err), "push not rejected: %v", err)
+}
+
 func TestPlainClone(t *testing.T) {
 	t.Parallel()
 	t.Run("successful clone", func(t *testing.T) {
@@ -80,6 +178,214 @@ func TestPlainClone(t *testing.T) {
 	})
 }
 
+func TestClone(t *testing.T) {
+	t.Parallel()
+	t.Run("shallow single branch clone", func(t *testing.T) {
+		t.Parallel()
+		abstractedGit := &UtilsGitMock{}
+		options := CloneOptions{Auth: AuthOptions{Token: "token"}, Branch: "main", SingleBranch: true, Depth: 1}
+		_, err := clone("https://github.com/org/repo", "directory", options, abstractedGit)
+		assert.NoError(t, err)
+		assert.Equal(t, "directory", abstractedGit.path)
+		assert.Equal(t, "http-basic-auth - x-access-token:*******", abstractedGit.authString)
+		assert.Equal(t, plumbing.ReferenceName("refs/heads/main"), abstractedGit.options.ReferenceName)
+		assert.True(t, abstractedGit.options.SingleBranch)
+		assert.Equal(t, 1, abstractedGit.options.Depth)
+		assert.False(t, abstractedGit.options.NoCheckout)
+	})
+
+	t.Run("ssh clone", func(t *testing.T) {
+		t.Parallel()
+		privateKey, _ := testSSHKey(t)
+		abstractedGit := &UtilsGitMock{}
+		options := CloneOptions{Auth: AuthOptions{SSHPrivateKey: privateKey, SSHInsecureIgnoreHostKey: true}}
+		_, err := clone("git@github.com:org/repo.git", "directory", options, abstractedGit)
+		assert.NoError(t, err)
+		assert.Equal(t, "user: git, name: ssh-public-keys", abstractedGit.authString)
+		assert.Equal(t, plumbing.ReferenceName(""), abstractedGit.options.ReferenceName)
+	})
+
+	t.Run("error on authentication", func(t *testing.T) {
+		t.Parallel()
+		options := CloneOptions{Auth: AuthOptions{SSHPrivateKey: "no key"}}
+		_, err := clone("ssh://git@github.com/org/repo.git", "directory", options, &UtilsGitMock{})
+		assert.Contains(t, fmt.Sprint(err), "failed to prepare authentication: failed to read ssh key")
+	})
+
+	t.Run("error on cloning", func(t *testing.T) {
+		t.Parallel()
+		_, err := clone("URL", "directory", CloneOptions{}, UtilsGitMockError{})
+		assert.EqualError(t, err, "failed to clone git: error during clone")
+	})
+}
+
+func TestSparseCheckout(t *testing.T) {
+	t.Parallel()
+	fs := memfs.New()
+	repo, err := git.Init(memory.NewStorage(), fs)
+	require.NoError(t, err)
+	for _, name := range []string{"dev/deployment.yaml", "prod/deployment.yaml", "production.yaml"} {
+		require.NoError(t, util.WriteFile(fs, name, []byte(name), 0644))
+	}
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+	_, err = worktree.Add(".")
+	require.NoError(t, err)
+	_, err = worktree.Commit("initial", &git.CommitOptions{Author: &object.Signature{Name: "Project Piper", When: time.Now()}})
+	require.NoError(t, err)
+	// simulate a clone without checkout
+	require.NoError(t, util.RemoveAll(fs, "dev"))
+	require.NoError(t, util.RemoveAll(fs, "prod"))
+	require.NoError(t, fs.Remove("production.yaml"))
+
+	err = sparseCheckout(repo, []string{"./prod/"})
+
+	if assert.NoError(t, err) {
+		file, err := fs.Open("prod/deployment.yaml")
+		if assert.NoError(t, err) {
+			content, _ := ioutil.ReadAll(file)
+			file.Close()
+			assert.Equal(t, "prod/deployment.yaml", string(content))
+		}
+		_, err = fs.Stat("dev/deployment.yaml")
+		assert.True(t, os.IsNotExist(err))
+		_, err = fs.Stat("production.yaml")
+		assert.True(t, os.IsNotExist(err))
+
+		// files outside the sparse paths stay part of the next commit
+		require.NoError(t, util.WriteFile(fs, "prod/deployment.yaml", []byte("updated"), 0644))
+		hash, err := CommitFiles([]string{"prod/deployment.yaml"}, "update", "Project Piper", worktree)
+		require.NoError(t, err)
+		commit, err := repo.CommitObject(hash)
+		require.NoError(t, err)
+		tree, err := commit.Tree()
+		require.NoError(t, err)
+		treeFile, err := tree.File("dev/deployment.yaml")
+		if assert.NoError(t, err) {
+			content, _ := treeFile.Contents()
+			assert.Equal(t, "dev/deployment.yaml", content)
+		}
+		treeFile, err = tree.File("prod/deployment.yaml")
+		if assert.NoError(t, err) {
+			content, _ := treeFile.Contents()
+			assert.Equal(t, "updated", content)
+		}
+	}
+}
+
+func TestHasChanges(t *testing.T) {
+	t.Parallel()
+	fs := memfs.New()
+	repo, err := git.Init(memory.NewStorage(), fs)
+	require.NoError(t, err)
+	for _, name := range []string{"dev/deployment.yaml", "prod/deployment.yaml"} {
+		require.NoError(t, util.WriteFile(fs, name, []byte(name), 0644))
+	}
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+	_, err = worktree.Add(".")
+	require.NoError(t, err)
+	_, err = worktree.Commit("initial", &git.CommitOptions{Author: &object.Signature{Name: "Project Piper", When: time.Now()}})
+	require.NoError(t, err)
+
+	t.Run("unchanged", func(t *testing.T) {
+		changed, err := HasChanges(worktree, []string{"dev"})
+		assert.NoError(t, err)
+		assert.False(t, changed)
+	})
+
+	t.Run("changes outside of paths", func(t *testing.T) {
+		require.NoError(t, fs.Remove("prod/deployment.yaml"))
+		changed, err := HasChanges(worktree, []string{"dev"})
+		assert.NoError(t, err)
+		assert.False(t, changed)
+	})
+
+	t.Run("changes inside of paths", func(t *testing.T) {
+		require.NoError(t, util.WriteFile(fs, "dev/deployment.yaml", []byte("updated"), 0644))
+		changed, err := HasChanges(worktree, []string{"dev"})
+		assert.NoError(t, err)
+		assert.True(t, changed)
+	})
+}
+
+func TestIsPushRejected(t *testing.T) {
+	t.Parallel()
+	assert.True(t, IsPushRejected(pkgErrors.Wrap(fmt.Errorf("non-fast-forward update: refs/heads/master"), "failed to push commit")))
+	assert.False(t, IsPushRejected(pkgErrors.Wrap(plumbing.ErrObjectNotFound, "failed to push commit")))
+	assert.True(t, IsPushRejected(fmt.Errorf("command error on refs/heads/master: failed to push some refs (fetch first)")))
+	assert.False(t, IsPushRejected(pkgErrors.Wrap(fmt.Errorf("authentication required"), "failed to push commit")))
+	assert.False(t, IsPushRejected(nil))
+}
+
+func TestAuthMethod(t *testing.T) {
+	t.Parallel()
+	t.Run("username and password", func(t *testing.T) {
+		t.Parallel()
+		auth, err := AuthOptions{Username: "user", Password: "password"}.authMethod("https://github.com/org/repo")
+		assert.NoError(t, err)
+		assert.Equal(t, "http-basic-auth - user:*******", auth.String())
+	})
+
+	t.Run("token with username", func(t *testing.T) {
+		t.Parallel()
+		auth, err := AuthOptions{Username: "oauth2", Password: "password", Token: "token"}.authMethod("https://gitlab.com/org/repo")
+		assert.NoError(t, err)
+		assert.Equal(t, "http-basic-auth - oauth2:*******", auth.String())
+	})
+
+	t.Run("anonymous", func(t *testing.T) {
+		t.Parallel()
+		auth, err := AuthOptions{}.authMethod("https://github.com/org/repo")
+		assert.NoError(t, err)
+		assert.Nil(t, auth)
+	})
+
+	t.Run("ssh with known hosts", func(t *testing.T) {
+		t.Parallel()
+		privateKey, publicKey := testSSHKey(t)
+		knownHosts, err := ioutil.TempFile("", "known_hosts")
+		require.NoError(t, err)
+		defer os.Remove(knownHosts.Name())
+		_, err = knownHosts.WriteString("github.com " + string(ssh.MarshalAuthorizedKey(publicKey)))
+		require.NoError(t, err)
+		require.NoError(t, knownHosts.Close())
+
+		auth, err := AuthOptions{Username: "user", SSHPrivateKey: privateKey, SSHKnownHostsFile: knownHosts.Name()}.authMethod("ssh://git@github.com/org/repo.git")
+
+		if assert.NoError(t, err) {
+			publicKeys, ok := auth.(*gitssh.PublicKeys)
+			if assert.True(t, ok) {
+				// the http(s) username is not used for ssh
+				assert.Equal(t, "git", publicKeys.User)
+				assert.NoError(t, publicKeys.HostKeyCallback("github.com:22", &net.TCPAddr{}, publicKey))
+			}
+		}
+	})
+
+	t.Run("ssh user of the url", func(t *testing.T) {
+		t.Parallel()
+		privateKey, _ := testSSHKey(t)
+		for url, user := range map[string]string{
+			"deploy@github.com:org/repo.git":       "deploy",
+			"ssh://deploy@github.com/org/repo.git": "deploy",
+			"ssh://github.com/org/repo.git":        "git",
+		} {
+			auth, err := AuthOptions{SSHPrivateKey: privateKey, SSHInsecureIgnoreHostKey: true}.authMethod(url)
+			if assert.NoError(t, err, url) {
+				assert.Equal(t, "user: "+user+", name: ssh-public-keys", auth.String(), url)
+			}
+		}
+	})
+
+	t.Run("missing known hosts file", func(t *testing.T) {
+		t.Parallel()
+		privateKey, _ := testSSHKey(t)
+		_, err := AuthOptions{SSHPrivateKey: privateKey, SSHKnownHostsFile: "not/existing"}.authMethod("git@github.com:org/repo.git")
+		assert.Contains(t, fmt.Sprint(err), "failed to read known hosts file 'not/existing'")
+	})
+}
+
 func TestPlainOpenMock(t *testing.T) {
 	t.Parallel()
 	t.Run("successful clone", func(t *testing.T) {
@@ -428,6 +734,7 @@ type UtilsGitMock struct {
 	isBare     bool
 	authString string
 	URL        string
+	options    *git.CloneOptions
 }
 
 func (u *UtilsGitMock) plainClone(path string, isBare bool, o *git.CloneOptions) (*git.Repository, error) {
@@ -435,6 +742,7 @@ func (u *UtilsGitMock) plainClone(path string, isBare bool, o *git.CloneOptions)
 	u.isBare = isBare
 	u.authString = o.Auth.String()
 	u.URL = o.URL
+	u.options = o
 	return nil, nil
 }
 
@@ -452,3 +760,99 @@ func (UtilsGitMockError) plainClone(string, bool, *git.CloneOptions) (*git.Repos
 func (UtilsGitMockError) plainOpen(path string) (*git.Repository, error) {
 	return nil, errors.New("error during git plain open")
 }
+
+func TestCommitAuthors(t *testing.T) {
+	fs := memfs.New()
+	r, err := git.Init(memory.NewStorage(), fs)
+	if err != nil {
+		assert.FailNow(t, "failed to init repository", err)
+	}
+	w, err := r.Worktree()
+	if err != nil {
+		assert.FailNow(t, "failed to get worktree", err)
+	}
+
+	commit := func(name, email string) plumbing.Hash {
+		f, _ := fs.Create(name + ".txt")
+		f.Write([]byte(name))
+		f.Close()
+		w.Add(name + ".txt")
+		hash, err := w.Commit(name, &git.CommitOptions{Author: &object.Signature{Name: name, Email: email}})
+		if err != nil {
+			assert.FailNow(t, "failed to commit", err)
+		}
+		return hash
+	}
+
+	hashA := commit("A", "a@example.org")
+	commit("B", "b@example.org")
+	commit("C", "a@example.org")
+	hashD := commit("D", "c@example.org")
+
+	t.Run("range", func(t *testing.T) {
+		authors, err := CommitAuthors(r, hashA.String(), hashD.String())
+		if assert.NoError(t, err) {
+			assert.Equal(t, []string{"a@example.org", "b@example.org", "c@example.org"}, authors)
+		}
+	})
+	t.Run("single commit", func(t *testing.T) {
+		authors, err := CommitAuthors(r, "", hashD.String())
+		if assert.NoError(t, err) {
+			assert.Equal(t, []string{"c@example.org"}, authors)
+		}
+	})
+	t.Run("invalid ref", func(t *testing.T) {
+		_, err := CommitAuthors(r, "", "unknown")
+		assert.EqualError(t, err, "Cannot provide commit authors (to: 'unknown' not found): Trouble resolving 'unknown': reference not found")
+	})
+}
+
+func TestChangedLines(t *testing.T) {
+	fs := memfs.New()
+	r, err := git.Init(memory.NewStorage(), fs)
+	if err != nil {
+		assert.FailNow(t, "failed to init repository", err)
+	}
+	w, err := r.Worktree()
+	if err != nil {
+		assert.FailNow(t, "failed to get worktree", err)
+	}
+
+	commit := func(files map[string]string) plumbing.Hash {
+		for name, content := range files {
+			if len(content) == 0 {
+				w.Remove(name)
+				continue
+			}
+			f, _ := fs.Create(name)
+			f.Write([]byte(content))
+			f.Close()
+			w.Add(name)
+		}
+		hash, err := w.Commit("commit", &git.CommitOptions{Author: &object.Signature{Name: "a", Email: "a@example.org"}})
+		if err != nil {
+			assert.FailNow(t, "failed to commit", err)
+		}
+		return hash
+	}
+
+	base := commit(map[string]string{"main.go": "a\nb\nc\nd\n", "old.go": "x\n"})
+	head := commit(map[string]string{"main.go": "a\nB\nc\nd\ne\n", "new.go": "1\n2", "old.go": ""})
+
+	t.Run("changes", func(t *testing.T) {
+		changedLines, err := ChangedLines(r, base.String(), head.String())
+		if assert.NoError(t, err) {
+			assert.Equal(t, map[string][]int{"main.go": {2, 5}, "new.go": {1, 2}}, changedLines)
+		}
+	})
+	t.Run("no changes", func(t *testing.T) {
+		changedLines, err := ChangedLines(r, head.String(), head.String())
+		if assert.NoError(t, err) {
+			assert.Empty(t, changedLines)
+		}
+	})
+	t.Run("invalid ref", func(t *testing.T) {
+		_, err := ChangedLines(r, "unknown", head.String())
+		assert.EqualError(t, err, "Cannot provide changed lines (base: 'unknown' not found): Trouble resolving 'unknown': reference not found")
+	})
+}
@@ -0,0 +1,229 @@
+package git
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/ssh"
+)
+
+// Signer creates the signature of a commit or tag
+type Signer interface {
+	Sign(content io.Reader) (string, error)
+}
+
+// gpgSigner creates armored OpenPGP signatures like "git commit -S"
+type gpgSigner struct {
+	entity *openpgp.Entity
+}
+
+// NewGPGSigner creates a signer based on an armored OpenPGP private key.
+// In case the key is protected, the passphrase is used to decrypt it.
+func NewGPGSigner(armoredKey, passphrase string) (Signer, error) {
+	entities, err := openpgp.ReadArmoredKeyRing(strings.NewReader(armoredKey))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read gpg key")
+	}
+	if len(entities) == 0 || entities[0].PrivateKey == nil {
+		return nil, fmt.Errorf("no gpg private key available")
+	}
+	entity := entities[0]
+	if entity.PrivateKey.Encrypted {
+		if err := entity.PrivateKey.Decrypt([]byte(passphrase)); err != nil {
+			return nil, errors.Wrap(err, "failed to decrypt gpg key")
+		}
+	}
+	for _, subkey := range entity.Subkeys {
+		if subkey.PrivateKey != nil && subkey.PrivateKey.Encrypted {
+			if err := subkey.PrivateKey.Decrypt([]byte(passphrase)); err != nil {
+				return nil, errors.Wrap(err, "failed to decrypt gpg subkey")
+			}
+		}
+	}
+	return &gpgSigner{entity: entity}, nil
+}
+
+func (g *gpgSigner) Sign(content io.Reader) (string, error) {
+	var signature bytes.Buffer
+	if err := openpgp.ArmoredDetachSign(&signature, g.entity, content, nil); err != nil {
+		return "", errors.Wrap(err, "failed to create gpg signature")
+	}
+	return signature.String(), nil
+}
+
+// sshSigner creates armored SSH signatures like "git commit -S" with gpg.format=ssh
+type sshSigner struct {
+	signer ssh.Signer
+}
+
+const (
+	sshSignatureMagic     = "SSHSIG"
+	sshSignatureNamespace = "git"
+	sshSignatureHash      = "sha512"
+)
+
+// NewSSHSigner creates a signer based on an OpenSSH private key.
+// In case the key is protected, the passphrase is used to decrypt it.
+func NewSSHSigner(privateKey, passphrase string) (Signer, error) {
+	var signer ssh.Signer
+	var err error
+	if len(passphrase) > 0 {
+		signer, err = ssh.ParsePrivateKeyWithPassphrase([]byte(privateKey), []byte(passphrase))
+	} else {
+		signer, err = ssh.ParsePrivateKey([]byte(privateKey))
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read ssh key")
+	}
+	return &sshSigner{signer: signer}, nil
+}
+
+// Sign creates a signature according to https://github.com/openssh/openssh-portable/blob/master/PROTOCOL.sshsig
+func (s *sshSigner) Sign(content io.Reader) (string, error) {
+	message, err := ioutil.ReadAll(content)
+	if err != nil {
+		return "", err
+	}
+	hash := sha512.Sum512(message)
+
+	signedData := append([]byte(sshSignatureMagic), ssh.Marshal(struct {
+		Namespace     string
+		Reserved      string
+		HashAlgorithm string
+		Hash          []byte
+	}{sshSignatureNamespace, "", sshSignatureHash, hash[:]})...)
+
+	var signature *ssh.Signature
+	if algorithmSigner, ok := s.signer.(ssh.AlgorithmSigner); ok && s.signer.PublicKey().Type() == ssh.KeyAlgoRSA {
+		// ssh-rsa (SHA-1) signatures are not accepted by git
+		signature, err = algorithmSigner.SignWithAlgorithm(rand.Reader, signedData, ssh.SigAlgoRSASHA2512)
+	} else {
+		signature, err = s.signer.Sign(rand.Reader, signedData)
+	}
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create ssh signature")
+	}
+
+	blob := append([]byte(sshSignatureMagic), ssh.Marshal(struct {
+		Version       uint32
+		PublicKey     []byte
+		Namespace     string
+		Reserved      string
+		HashAlgorithm string
+		Signature     []byte
+	}{1, s.signer.PublicKey().Marshal(), sshSignatureNamespace, "", sshSignatureHash, ssh.Marshal(signature)})...)
+
+	encoded := base64.StdEncoding.EncodeToString(blob)
+	var armored strings.Builder
+	armored.WriteString("-----BEGIN SSH SIGNATURE-----\n")
+	for len(encoded) > 70 {
+		armored.WriteString(encoded[:70] + "\n")
+		encoded = encoded[70:]
+	}
+	armored.WriteString(encoded + "\n-----END SSH SIGNATURE-----\n")
+	return armored.String(), nil
+}
+
+// SignCommit replaces the commit with a signed copy and moves HEAD to the signed commit.
+// The hash of the signed commit is returned.
+func SignCommit(repo *git.Repository, hash plumbing.Hash, signer Signer) (plumbing.Hash, error) {
+	commit, err := repo.CommitObject(hash)
+	if err != nil {
+		return plumbing.ZeroHash, errors.Wrapf(err, "failed to retrieve commit '%v'", hash)
+	}
+
+	unsigned := &plumbing.MemoryObject{}
+	if err := commit.EncodeWithoutSignature(unsigned); err != nil {
+		return plumbing.ZeroHash, errors.Wrap(err, "failed to encode commit")
+	}
+	reader, err := unsigned.Reader()
+	if err != nil {
+		return plumbing.ZeroHash, errors.Wrap(err, "failed to encode commit")
+	}
+	commit.PGPSignature, err = signer.Sign(reader)
+	if err != nil {
+		return plumbing.ZeroHash, errors.Wrap(err, "failed to sign commit")
+	}
+
+	signed := repo.Storer.NewEncodedObject()
+	if err := commit.Encode(signed); err != nil {
+		return plumbing.ZeroHash, errors.Wrap(err, "failed to encode signed commit")
+	}
+	signedHash, err := repo.Storer.SetEncodedObject(signed)
+	if err != nil {
+		return plumbing.ZeroHash, errors.Wrap(err, "failed to store signed commit")
+	}
+
+	head, err := repo.Storer.Reference(plumbing.HEAD)
+	if err != nil {
+		return plumbing.ZeroHash, errors.Wrap(err, "failed to retrieve HEAD")
+	}
+	name := plumbing.HEAD
+	if head.Type() != plumbing.HashReference {
+		// update the checked out branch
+		name = head.Target()
+	}
+	if err := repo.Storer.SetReference(plumbing.NewHashReference(name, signedHash)); err != nil {
+		return plumbing.ZeroHash, errors.Wrap(err, "failed to update HEAD")
+	}
+	return signedHash, nil
+}
+
+// CreateSignedTag creates a signed annotated tag pointing to the commit
+func CreateSignedTag(repo *git.Repository, name string, hash plumbing.Hash, tagger object.Signature, message string, signer Signer) (*plumbing.Reference, error) {
+	refName := plumbing.NewTagReferenceName(name)
+	if _, err := repo.Storer.Reference(refName); err == nil {
+		return nil, git.ErrTagExists
+	}
+
+	if !strings.HasSuffix(message, "\n") {
+		// the signature is appended to the message
+		message += "\n"
+	}
+	tag := &object.Tag{
+		Name:       name,
+		Tagger:     tagger,
+		Message:    message,
+		TargetType: plumbing.CommitObject,
+		Target:     hash,
+	}
+
+	unsigned := &plumbing.MemoryObject{}
+	if err := tag.EncodeWithoutSignature(unsigned); err != nil {
+		return nil, errors.Wrap(err, "failed to encode tag")
+	}
+	reader, err := unsigned.Reader()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode tag")
+	}
+	tag.PGPSignature, err = signer.Sign(reader)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to sign tag")
+	}
+
+	signed := repo.Storer.NewEncodedObject()
+	if err := tag.Encode(signed); err != nil {
+		return nil, errors.Wrap(err, "failed to encode signed tag")
+	}
+	tagHash, err := repo.Storer.SetEncodedObject(signed)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to store signed tag")
+	}
+
+	ref := plumbing.NewHashReference(refName, tagHash)
+	if err := repo.Storer.SetReference(ref); err != nil {
+		return nil, errors.Wrap(err, "failed to create tag reference")
+	}
+	return ref, nil
+}
@@ -0,0 +1,265 @@
+package git
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/armor"
+	"golang.org/x/crypto/ssh"
+)
+
+func testGPGKey(t *testing.T) (string, string) {
+	entity, err := openpgp.NewEntity("Project Piper", "", "piper@example.com", nil)
+	require.NoError(t, err)
+
+	var private bytes.Buffer
+	w, err := armor.Encode(&private, openpgp.PrivateKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, entity.SerializePrivate(w, nil))
+	require.NoError(t, w.Close())
+
+	var public bytes.Buffer
+	w, err = armor.Encode(&public, openpgp.PublicKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, entity.Serialize(w))
+	require.NoError(t, w.Close())
+
+	return private.String(), public.String()
+}
+
+func testSSHKey(t *testing.T) (string, ssh.PublicKey) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	publicKey, err := ssh.NewPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})), publicKey
+}
+
+// verifySSHSignature verifies a signature according to https://github.com/openssh/openssh-portable/blob/master/PROTOCOL.sshsig
+func verifySSHSignature(armored string, message []byte, publicKey ssh.PublicKey) error {
+	if !strings.HasPrefix(armored, "-----BEGIN SSH SIGNATURE-----\n") || !strings.HasSuffix(armored, "\n-----END SSH SIGNATURE-----\n") {
+		return fmt.Errorf("invalid armor")
+	}
+	encoded := strings.TrimSuffix(strings.TrimPrefix(armored, "-----BEGIN SSH SIGNATURE-----\n"), "\n-----END SSH SIGNATURE-----\n")
+	blob, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(encoded, "\n", ""))
+	if err != nil {
+		return err
+	}
+	if !bytes.HasPrefix(blob, []byte("SSHSIG")) {
+		return fmt.Errorf("invalid magic preamble")
+	}
+	content := struct {
+		Version       uint32
+		PublicKey     []byte
+		Namespace     string
+		Reserved      string
+		HashAlgorithm string
+		Signature     []byte
+	}{}
+	if err := ssh.Unmarshal(blob[6:], &content); err != nil {
+		return err
+	}
+	if content.Version != 1 || content.Namespace != "git" || !bytes.Equal(content.PublicKey, publicKey.Marshal()) {
+		return fmt.Errorf("unexpected signature content")
+	}
+	signature := ssh.Signature{}
+	if err := ssh.Unmarshal(content.Signature, &signature); err != nil {
+		return err
+	}
+	hash := sha512.Sum512(message)
+	signedData := append([]byte("SSHSIG"), ssh.Marshal(struct {
+		Namespace     string
+		Reserved      string
+		HashAlgorithm string
+		Hash          []byte
+	}{"git", "", content.HashAlgorithm, hash[:]})...)
+	return publicKey.Verify(signedData, &signature)
+}
+
+func objectContent(t *testing.T, object *plumbing.MemoryObject) []byte {
+	reader, err := object.Reader()
+	require.NoError(t, err)
+	content, err := ioutil.ReadAll(reader)
+	require.NoError(t, err)
+	return content
+}
+
+func testRepository(t *testing.T) (*git.Repository, plumbing.Hash) {
+	fs := memfs.New()
+	repo, err := git.Init(memory.NewStorage(), fs)
+	require.NoError(t, err)
+	file, err := fs.Create("VERSION")
+	require.NoError(t, err)
+	_, err = file.Write([]byte("1.0.0"))
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+	_, err = worktree.Add("VERSION")
+	require.NoError(t, err)
+	hash, err := worktree.Commit("update version 1.0.0", &git.CommitOptions{Author: &object.Signature{Name: "Project Piper", When: time.Now()}})
+	require.NoError(t, err)
+	return repo, hash
+}
+
+type signerMock struct {
+	err error
+}
+
+func (s *signerMock) Sign(content io.Reader) (string, error) {
+	return "signature\n", s.err
+}
+
+func TestSignCommit(t *testing.T) {
+	t.Parallel()
+	t.Run("gpg", func(t *testing.T) {
+		t.Parallel()
+		privateKey, publicKey := testGPGKey(t)
+		signer, err := NewGPGSigner(privateKey, "")
+		require.NoError(t, err)
+		repo, hash := testRepository(t)
+
+		signedHash, err := SignCommit(repo, hash, signer)
+
+		if assert.NoError(t, err) {
+			assert.NotEqual(t, hash, signedHash)
+			head, err := repo.Head()
+			assert.NoError(t, err)
+			assert.Equal(t, signedHash, head.Hash())
+			commit, err := repo.CommitObject(signedHash)
+			assert.NoError(t, err)
+			assert.Contains(t, commit.PGPSignature, "-----BEGIN PGP SIGNATURE-----")
+			_, err = commit.Verify(publicKey)
+			assert.NoError(t, err, "signature verification failed")
+		}
+	})
+
+	t.Run("ssh", func(t *testing.T) {
+		t.Parallel()
+		privateKey, publicKey := testSSHKey(t)
+		signer, err := NewSSHSigner(privateKey, "")
+		require.NoError(t, err)
+		repo, hash := testRepository(t)
+
+		signedHash, err := SignCommit(repo, hash, signer)
+
+		if assert.NoError(t, err) {
+			commit, err := repo.CommitObject(signedHash)
+			assert.NoError(t, err)
+			unsigned := &plumbing.MemoryObject{}
+			assert.NoError(t, commit.EncodeWithoutSignature(unsigned))
+			assert.NoError(t, verifySSHSignature(commit.PGPSignature, objectContent(t, unsigned), publicKey), "signature verification failed")
+		}
+	})
+
+	t.Run("error signing", func(t *testing.T) {
+		t.Parallel()
+		repo, hash := testRepository(t)
+
+		_, err := SignCommit(repo, hash, &signerMock{err: fmt.Errorf("key expired")})
+
+		assert.EqualError(t, err, "failed to sign commit: key expired")
+		head, _ := repo.Head()
+		assert.Equal(t, hash, head.Hash())
+	})
+}
+
+func TestCreateSignedTag(t *testing.T) {
+	t.Parallel()
+	t.Run("gpg", func(t *testing.T) {
+		t.Parallel()
+		privateKey, publicKey := testGPGKey(t)
+		signer, err := NewGPGSigner(privateKey, "")
+		require.NoError(t, err)
+		repo, hash := testRepository(t)
+
+		ref, err := CreateSignedTag(repo, "v1.0.0", hash, object.Signature{Name: "Project Piper", When: time.Now()}, "version 1.0.0", signer)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, plumbing.ReferenceName("refs/tags/v1.0.0"), ref.Name())
+			tag, err := repo.TagObject(ref.Hash())
+			assert.NoError(t, err)
+			assert.Equal(t, hash, tag.Target)
+			assert.Equal(t, "version 1.0.0\n", tag.Message)
+			_, err = tag.Verify(publicKey)
+			assert.NoError(t, err, "signature verification failed")
+		}
+	})
+
+	t.Run("ssh", func(t *testing.T) {
+		t.Parallel()
+		privateKey, publicKey := testSSHKey(t)
+		signer, err := NewSSHSigner(privateKey, "")
+		require.NoError(t, err)
+		repo, hash := testRepository(t)
+
+		ref, err := CreateSignedTag(repo, "v1.0.0", hash, object.Signature{Name: "Project Piper", When: time.Now()}, "version 1.0.0\n", signer)
+
+		if assert.NoError(t, err) {
+			tag, err := repo.TagObject(ref.Hash())
+			assert.NoError(t, err)
+			// go-git only detects PGP signatures, the ssh signature is part of the message
+			index := strings.Index(tag.Message, "-----BEGIN SSH SIGNATURE-----")
+			if assert.Greater(t, index, 0) {
+				signature := tag.Message[index:]
+				tag.Message = tag.Message[:index]
+				assert.Equal(t, "version 1.0.0\n", tag.Message)
+				unsigned := &plumbing.MemoryObject{}
+				assert.NoError(t, tag.EncodeWithoutSignature(unsigned))
+				assert.NoError(t, verifySSHSignature(signature, objectContent(t, unsigned), publicKey), "signature verification failed")
+			}
+		}
+	})
+
+	t.Run("tag exists", func(t *testing.T) {
+		t.Parallel()
+		repo, hash := testRepository(t)
+		_, err := repo.CreateTag("v1.0.0", hash, nil)
+		require.NoError(t, err)
+
+		_, err = CreateSignedTag(repo, "v1.0.0", hash, object.Signature{Name: "Project Piper"}, "version 1.0.0", &signerMock{})
+
+		assert.Equal(t, git.ErrTagExists, err)
+	})
+}
+
+func TestNewSigner(t *testing.T) {
+	t.Parallel()
+	t.Run("invalid gpg key", func(t *testing.T) {
+		t.Parallel()
+		_, err := NewGPGSigner("no key", "")
+		assert.Contains(t, fmt.Sprint(err), "failed to read gpg key")
+	})
+
+	t.Run("gpg public key only", func(t *testing.T) {
+		t.Parallel()
+		_, publicKey := testGPGKey(t)
+		_, err := NewGPGSigner(publicKey, "")
+		assert.EqualError(t, err, "no gpg private key available")
+	})
+
+	t.Run("invalid ssh key", func(t *testing.T) {
+		t.Parallel()
+		_, err := NewSSHSigner("no key", "")
+		assert.Contains(t, fmt.Sprint(err), "failed to read ssh key")
+	})
+}
@@ -0,0 +1,134 @@
+package github
+
+import (
+	"context"
+	"crypto/rsa"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/google/go-github/v32/github"
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+)
+
+// AppOptions define a GitHub App which is used for authentication instead of a personal access token
+type AppOptions struct {
+	AppID int
+	// InstallationID of the app, in case it is not provided the installation is looked up for Owner and Repository
+	InstallationID int
+	// PrivateKeyFile contains the PEM encoded private key of the app
+	PrivateKeyFile string
+	Owner          string
+	Repository     string
+}
+
+var (
+	installationTokens      = map[string]oauth2.TokenSource{}
+	installationTokensMutex sync.Mutex
+)
+
+// NewClientWithAuth creates a new GitHub client either authenticated as installation of a GitHub App
+// (in case an app ID is provided) or using an OAuth token
+func NewClientWithAuth(token string, app AppOptions, apiURL, uploadURL string) (context.Context, *github.Client, error) {
+	if app.AppID == 0 {
+		if len(token) == 0 {
+			return context.Background(), nil, fmt.Errorf("either a token or a GitHub App is required for authentication")
+		}
+		return NewClient(token, apiURL, uploadURL)
+	}
+	privateKey, err := ioutil.ReadFile(app.PrivateKeyFile)
+	if err != nil {
+		return context.Background(), nil, errors.Wrapf(err, "failed to read private key of GitHub App from '%v'", app.PrivateKeyFile)
+	}
+	return newAppClient(app, privateKey, apiURL, uploadURL)
+}
+
+// newAppClient creates a new GitHub client authenticated as installation of a GitHub App.
+// The installation token is cached and refreshed before it expires.
+func newAppClient(app AppOptions, privateKey []byte, apiURL, uploadURL string) (context.Context, *github.Client, error) {
+	ctx := context.Background()
+	tokenSource, err := installationTokenSource(ctx, app, privateKey, apiURL)
+	if err != nil {
+		return ctx, nil, err
+	}
+	return newClient(ctx, oauth2.NewClient(ctx, tokenSource), apiURL, uploadURL)
+}
+
+func installationTokenSource(ctx context.Context, app AppOptions, privateKey []byte, apiURL string) (oauth2.TokenSource, error) {
+	installationTokensMutex.Lock()
+	defer installationTokensMutex.Unlock()
+
+	cacheKey := fmt.Sprintf("%v|%v|%v|%v/%v", apiURL, app.AppID, app.InstallationID, app.Owner, app.Repository)
+	if tokenSource, ok := installationTokens[cacheKey]; ok {
+		return tokenSource, nil
+	}
+
+	key, err := jwt.ParseRSAPrivateKeyFromPEM(privateKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse private key of GitHub App")
+	}
+	_, appClient, err := newClient(ctx, &http.Client{Transport: &appTransport{appID: app.AppID, key: key, now: time.Now}}, apiURL, "")
+	if err != nil {
+		return nil, err
+	}
+
+	installationID := int64(app.InstallationID)
+	if installationID == 0 {
+		if len(app.Owner) == 0 || len(app.Repository) == 0 {
+			return nil, fmt.Errorf("owner and repository are required to find the installation of the GitHub App")
+		}
+		installation, _, err := appClient.Apps.FindRepositoryInstallation(ctx, app.Owner, app.Repository)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to find installation of GitHub App for '%v/%v'", app.Owner, app.Repository)
+		}
+		installationID = installation.GetID()
+	}
+
+	tokenSource := oauth2.ReuseTokenSource(nil, &appInstallationTokenSource{ctx: ctx, apps: appClient.Apps, installationID: installationID})
+	installationTokens[cacheKey] = tokenSource
+	return tokenSource, nil
+}
+
+// appInstallationTokenSource creates installation access tokens which are valid for one hour
+type appInstallationTokenSource struct {
+	ctx            context.Context
+	apps           *github.AppsService
+	installationID int64
+}
+
+func (s *appInstallationTokenSource) Token() (*oauth2.Token, error) {
+	token, _, err := s.apps.CreateInstallationToken(s.ctx, s.installationID, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create installation token of GitHub App")
+	}
+	// refresh the token before it expires during a request
+	return &oauth2.Token{AccessToken: token.GetToken(), Expiry: token.GetExpiresAt().Add(-time.Minute)}, nil
+}
+
+// appTransport authenticates requests as GitHub App using a JSON Web Token
+type appTransport struct {
+	appID int
+	key   *rsa.PrivateKey
+	now   func() time.Time
+}
+
+func (t *appTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	now := t.now()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.StandardClaims{
+		// allow a clock drift between the GitHub server and the client
+		IssuedAt:  now.Add(-time.Minute).Unix(),
+		ExpiresAt: now.Add(9 * time.Minute).Unix(),
+		Issuer:    strconv.Itoa(t.appID),
+	}).SignedString(t.key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create JSON Web Token for GitHub App")
+	}
+	authenticated := req.Clone(req.Context())
+	authenticated.Header.Set("Authorization", "Bearer "+token)
+	return http.DefaultTransport.RoundTrip(authenticated)
+}
@@ -0,0 +1,151 @@
+package github
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// githubAppServer emulates the endpoints of the GitHub API used by GitHub Apps
+type githubAppServer struct {
+	t             *testing.T
+	key           *rsa.PrivateKey
+	tokenRequests int
+	expiresIn     time.Duration
+}
+
+func (s *githubAppServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	authorization := r.Header.Get("Authorization")
+	switch {
+	case r.URL.Path == "/repos/SAP/jenkins-library/installation" || r.URL.Path == "/app/installations/42/access_tokens":
+		token, err := jwt.ParseWithClaims(strings.TrimPrefix(authorization, "Bearer "), &jwt.StandardClaims{}, func(*jwt.Token) (interface{}, error) {
+			return &s.key.PublicKey, nil
+		})
+		if !assert.NoError(s.t, err, "invalid JSON Web Token") {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		assert.Equal(s.t, "1234", token.Claims.(*jwt.StandardClaims).Issuer)
+		if r.URL.Path == "/repos/SAP/jenkins-library/installation" {
+			fmt.Fprint(w, `{"id": 42}`)
+			return
+		}
+		s.tokenRequests++
+		fmt.Fprintf(w, `{"token": "installation-token-%v", "expires_at": "%v"}`, s.tokenRequests, time.Now().Add(s.expiresIn).UTC().Format(time.RFC3339))
+	case r.URL.Path == "/repos/SAP/jenkins-library":
+		assert.Equal(s.t, fmt.Sprintf("Bearer installation-token-%v", s.tokenRequests), authorization)
+		fmt.Fprint(w, `{"name": "jenkins-library"}`)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func testAppKey(t *testing.T) (*rsa.PrivateKey, []byte) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return key, pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+}
+
+func TestNewAppClient(t *testing.T) {
+	t.Run("installation token is cached", func(t *testing.T) {
+		key, privateKey := testAppKey(t)
+		server := &githubAppServer{t: t, key: key, expiresIn: time.Hour}
+		httpServer := httptest.NewServer(server)
+		defer httpServer.Close()
+
+		for i := 0; i < 2; i++ {
+			ctx, client, err := newAppClient(AppOptions{AppID: 1234, Owner: "SAP", Repository: "jenkins-library"}, privateKey, httpServer.URL, "")
+			require.NoError(t, err)
+			repository, _, err := client.Repositories.Get(ctx, "SAP", "jenkins-library")
+			if assert.NoError(t, err) {
+				assert.Equal(t, "jenkins-library", repository.GetName())
+			}
+		}
+		assert.Equal(t, 1, server.tokenRequests)
+	})
+
+	t.Run("expired installation token is refreshed", func(t *testing.T) {
+		key, privateKey := testAppKey(t)
+		server := &githubAppServer{t: t, key: key, expiresIn: 30 * time.Second}
+		httpServer := httptest.NewServer(server)
+		defer httpServer.Close()
+
+		ctx, client, err := newAppClient(AppOptions{AppID: 1234, InstallationID: 42}, privateKey, httpServer.URL, "")
+		require.NoError(t, err)
+		for i := 0; i < 2; i++ {
+			_, _, err = client.Repositories.Get(ctx, "SAP", "jenkins-library")
+			assert.NoError(t, err)
+		}
+		assert.Equal(t, 2, server.tokenRequests)
+	})
+
+	t.Run("installation not found", func(t *testing.T) {
+		key, privateKey := testAppKey(t)
+		httpServer := httptest.NewServer(&githubAppServer{t: t, key: key})
+		defer httpServer.Close()
+
+		_, _, err := newAppClient(AppOptions{AppID: 1234, Owner: "SAP", Repository: "other"}, privateKey, httpServer.URL, "")
+		assert.Contains(t, fmt.Sprint(err), "failed to find installation of GitHub App for 'SAP/other'")
+	})
+
+	t.Run("missing repository", func(t *testing.T) {
+		_, privateKey := testAppKey(t)
+		_, _, err := newAppClient(AppOptions{AppID: 1234}, privateKey, "https://api.github.com", "")
+		assert.EqualError(t, err, "owner and repository are required to find the installation of the GitHub App")
+	})
+
+	t.Run("invalid private key", func(t *testing.T) {
+		_, _, err := newAppClient(AppOptions{AppID: 1234, InstallationID: 42}, []byte("no key"), "https://api.github.com", "")
+		assert.Contains(t, fmt.Sprint(err), "failed to parse private key of GitHub App")
+	})
+}
+
+func TestNewClientWithAuth(t *testing.T) {
+	t.Run("token", func(t *testing.T) {
+		_, client, err := NewClientWithAuth("token", AppOptions{}, "https://api.github.com", "")
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+	})
+
+	t.Run("neither token nor app", func(t *testing.T) {
+		_, _, err := NewClientWithAuth("", AppOptions{}, "https://api.github.com", "")
+		assert.EqualError(t, err, "either a token or a GitHub App is required for authentication")
+	})
+
+	t.Run("app private key from file", func(t *testing.T) {
+		key, privateKey := testAppKey(t)
+		httpServer := httptest.NewServer(&githubAppServer{t: t, key: key, expiresIn: time.Hour})
+		defer httpServer.Close()
+		keyFile, err := ioutil.TempFile("", "app-key")
+		require.NoError(t, err)
+		defer os.Remove(keyFile.Name())
+		_, err = keyFile.Write(privateKey)
+		require.NoError(t, err)
+		require.NoError(t, keyFile.Close())
+
+		ctx, client, err := NewClientWithAuth("", AppOptions{AppID: 1234, InstallationID: 42, PrivateKeyFile: keyFile.Name()}, httpServer.URL, "")
+
+		if assert.NoError(t, err) {
+			_, _, err = client.Repositories.Get(ctx, "SAP", "jenkins-library")
+			assert.NoError(t, err)
+		}
+	})
+
+	t.Run("missing private key file", func(t *testing.T) {
+		_, _, err := NewClientWithAuth("", AppOptions{AppID: 1234, PrivateKeyFile: "not/existing"}, "https://api.github.com", "")
+		assert.Contains(t, fmt.Sprint(err), "failed to read private key of GitHub App from 'not/existing'")
+	})
+}
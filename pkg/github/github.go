@@ -2,6 +2,7 @@ package github
 
 import (
 	"context"
+	"net/http"
 	"net/url"
 	"strings"
 
@@ -16,7 +17,10 @@ func NewClient(token, apiURL, uploadURL string) (context.Context, *github.Client
 		&oauth2.Token{AccessToken: token},
 	)
 	tc := oauth2.NewClient(ctx, ts)
+	return newClient(ctx, tc, apiURL, uploadURL)
+}
 
+func newClient(ctx context.Context, tc *http.Client, apiURL, uploadURL string) (context.Context, *github.Client, error) {
 	if !strings.HasSuffix(apiURL, "/") {
 		apiURL += "/"
 	}
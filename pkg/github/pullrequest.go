@@ -0,0 +1,122 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/google/go-github/v32/github"
+	"github.com/pkg/errors"
+)
+
+// SummaryMarker identifies the summary comment of a pull request so that it can be updated instead of adding a new one
+const SummaryMarker = "<!-- piper:scan-summary -->"
+
+// ReviewComment is an inline comment on a line of a file which is changed by a pull request
+type ReviewComment struct {
+	Path string
+	Line int
+	Body string
+}
+
+// PullRequestDecoration contains the summary and the inline comments which are posted to a pull request
+type PullRequestDecoration struct {
+	Owner      string
+	Repository string
+	Number     int
+	// CommitID is the head commit the inline comments refer to
+	CommitID string
+	Summary  string
+	Comments []ReviewComment
+}
+
+// DecoratePullRequest creates or updates the summary comment of a pull request and adds a review containing the inline comments.
+// Inline comments which already exist on the same line are not posted again.
+func DecoratePullRequest(ctx context.Context, client *github.Client, decoration PullRequestDecoration) error {
+	if err := upsertSummary(ctx, client, decoration); err != nil {
+		return err
+	}
+	if len(decoration.Comments) == 0 {
+		return nil
+	}
+
+	existing, err := existingReviewComments(ctx, client, decoration)
+	if err != nil {
+		return err
+	}
+	review := github.PullRequestReviewRequest{
+		CommitID: &decoration.CommitID,
+		Event:    github.String("COMMENT"),
+	}
+	for _, comment := range decoration.Comments {
+		if existing[reviewCommentKey(comment.Path, comment.Line, comment.Body)] {
+			log.Entry().Debugf("comment on %v:%v already exists", comment.Path, comment.Line)
+			continue
+		}
+		review.Comments = append(review.Comments, &github.DraftReviewComment{
+			Path: github.String(comment.Path),
+			Line: github.Int(comment.Line),
+			Side: github.String("RIGHT"),
+			Body: github.String(comment.Body),
+		})
+	}
+	if len(review.Comments) == 0 {
+		log.Entry().Info("all findings have already been commented")
+		return nil
+	}
+	if _, _, err := client.PullRequests.CreateReview(ctx, decoration.Owner, decoration.Repository, decoration.Number, &review); err != nil {
+		return errors.Wrapf(err, "failed to add review to pull request #%v", decoration.Number)
+	}
+	log.Entry().Infof("added %v comments to pull request #%v", len(review.Comments), decoration.Number)
+	return nil
+}
+
+func upsertSummary(ctx context.Context, client *github.Client, decoration PullRequestDecoration) error {
+	body := SummaryMarker + "\n" + decoration.Summary
+	options := &github.IssueListCommentsOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		comments, response, err := client.Issues.ListComments(ctx, decoration.Owner, decoration.Repository, decoration.Number, options)
+		if err != nil {
+			return errors.Wrapf(err, "failed to list comments of pull request #%v", decoration.Number)
+		}
+		for _, comment := range comments {
+			if strings.HasPrefix(comment.GetBody(), SummaryMarker) {
+				if _, _, err := client.Issues.EditComment(ctx, decoration.Owner, decoration.Repository, comment.GetID(), &github.IssueComment{Body: &body}); err != nil {
+					return errors.Wrapf(err, "failed to update summary of pull request #%v", decoration.Number)
+				}
+				return nil
+			}
+		}
+		if response.NextPage == 0 {
+			break
+		}
+		options.Page = response.NextPage
+	}
+	if _, _, err := client.Issues.CreateComment(ctx, decoration.Owner, decoration.Repository, decoration.Number, &github.IssueComment{Body: &body}); err != nil {
+		return errors.Wrapf(err, "failed to add summary to pull request #%v", decoration.Number)
+	}
+	return nil
+}
+
+func existingReviewComments(ctx context.Context, client *github.Client, decoration PullRequestDecoration) (map[string]bool, error) {
+	existing := map[string]bool{}
+	options := &github.PullRequestListCommentsOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		comments, response, err := client.PullRequests.ListComments(ctx, decoration.Owner, decoration.Repository, decoration.Number, options)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to list review comments of pull request #%v", decoration.Number)
+		}
+		for _, comment := range comments {
+			existing[reviewCommentKey(comment.GetPath(), comment.GetLine(), comment.GetBody())] = true
+		}
+		if response.NextPage == 0 {
+			return existing, nil
+		}
+		options.Page = response.NextPage
+	}
+}
+
+func reviewCommentKey(path string, line int, body string) string {
+	return fmt.Sprintf("%v:%v:%v", path, line, body)
+}
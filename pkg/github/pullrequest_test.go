@@ -0,0 +1,116 @@
+package github
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pullRequestServer emulates the comment endpoints of a pull request
+type pullRequestServer struct {
+	issueComments  string
+	reviewComments string
+	requests       map[string]string
+}
+
+func (s *pullRequestServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+	s.requests[r.Method+" "+r.URL.Path] = string(body)
+	switch r.Method + " " + r.URL.Path {
+	case "GET /repos/SAP/jenkins-library/issues/7/comments":
+		fmt.Fprint(w, s.issueComments)
+	case "GET /repos/SAP/jenkins-library/pulls/7/comments":
+		fmt.Fprint(w, s.reviewComments)
+	case "POST /repos/SAP/jenkins-library/issues/7/comments", "PATCH /repos/SAP/jenkins-library/issues/comments/11", "POST /repos/SAP/jenkins-library/pulls/7/reviews":
+		fmt.Fprint(w, `{}`)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestDecoratePullRequest(t *testing.T) {
+	decoration := PullRequestDecoration{
+		Owner:      "SAP",
+		Repository: "jenkins-library",
+		Number:     7,
+		CommitID:   "abc123",
+		Summary:    "2 findings",
+		Comments: []ReviewComment{
+			{Path: "main.go", Line: 3, Body: "SQL injection"},
+			{Path: "main.go", Line: 5, Body: "weak hash"},
+		},
+	}
+
+	t.Run("new summary and comments", func(t *testing.T) {
+		server := &pullRequestServer{issueComments: `[{"id": 10, "body": "LGTM"}]`, reviewComments: `[]`, requests: map[string]string{}}
+		httpServer := httptest.NewServer(server)
+		defer httpServer.Close()
+		ctx, client, err := NewClient("token", httpServer.URL, "")
+		require.NoError(t, err)
+
+		err = DecoratePullRequest(ctx, client, decoration)
+
+		if assert.NoError(t, err) {
+			assert.JSONEq(t, `{"body": "<!-- piper:scan-summary -->\n2 findings"}`, server.requests["POST /repos/SAP/jenkins-library/issues/7/comments"])
+			assert.JSONEq(t, `{"commit_id": "abc123", "event": "COMMENT", "comments": [
+				{"path": "main.go", "line": 3, "side": "RIGHT", "body": "SQL injection"},
+				{"path": "main.go", "line": 5, "side": "RIGHT", "body": "weak hash"}]}`,
+				server.requests["POST /repos/SAP/jenkins-library/pulls/7/reviews"])
+		}
+	})
+
+	t.Run("existing summary and comment", func(t *testing.T) {
+		server := &pullRequestServer{
+			issueComments:  `[{"id": 11, "body": "<!-- piper:scan-summary -->\n3 findings"}]`,
+			reviewComments: `[{"path": "main.go", "line": 3, "body": "SQL injection"}]`,
+			requests:       map[string]string{},
+		}
+		httpServer := httptest.NewServer(server)
+		defer httpServer.Close()
+		ctx, client, err := NewClient("token", httpServer.URL, "")
+		require.NoError(t, err)
+
+		err = DecoratePullRequest(ctx, client, decoration)
+
+		if assert.NoError(t, err) {
+			assert.NotContains(t, server.requests, "POST /repos/SAP/jenkins-library/issues/7/comments")
+			assert.JSONEq(t, `{"body": "<!-- piper:scan-summary -->\n2 findings"}`, server.requests["PATCH /repos/SAP/jenkins-library/issues/comments/11"])
+			assert.JSONEq(t, `{"commit_id": "abc123", "event": "COMMENT", "comments": [{"path": "main.go", "line": 5, "side": "RIGHT", "body": "weak hash"}]}`,
+				server.requests["POST /repos/SAP/jenkins-library/pulls/7/reviews"])
+		}
+	})
+
+	t.Run("all comments exist", func(t *testing.T) {
+		server := &pullRequestServer{
+			issueComments:  `[]`,
+			reviewComments: `[{"path": "main.go", "line": 3, "body": "SQL injection"}, {"path": "main.go", "line": 5, "body": "weak hash"}]`,
+			requests:       map[string]string{},
+		}
+		httpServer := httptest.NewServer(server)
+		defer httpServer.Close()
+		ctx, client, err := NewClient("token", httpServer.URL, "")
+		require.NoError(t, err)
+
+		err = DecoratePullRequest(ctx, client, decoration)
+
+		if assert.NoError(t, err) {
+			assert.NotContains(t, server.requests, "POST /repos/SAP/jenkins-library/pulls/7/reviews")
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		httpServer := httptest.NewServer(http.NotFoundHandler())
+		defer httpServer.Close()
+		ctx, client, err := NewClient("token", httpServer.URL, "")
+		require.NoError(t, err)
+
+		err = DecoratePullRequest(ctx, client, decoration)
+
+		assert.Contains(t, err.Error(), "failed to list comments of pull request #7")
+	})
+}
@@ -0,0 +1,32 @@
+package github
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-github/v32/github"
+	"github.com/pkg/errors"
+)
+
+// securityAndAnalysis contains the security settings of a repository which are not yet covered by the GitHub client
+type securityAndAnalysis struct {
+	SecurityAndAnalysis struct {
+		SecretScanning struct {
+			Status string `json:"status"`
+		} `json:"secret_scanning"`
+	} `json:"security_and_analysis"`
+}
+
+// SecretScanningEnabled returns true in case secret scanning is enabled for the repository.
+// The setting is only visible for users with admin permissions on the repository.
+func SecretScanningEnabled(ctx context.Context, client *github.Client, owner, repository string) (bool, error) {
+	request, err := client.NewRequest("GET", fmt.Sprintf("repos/%v/%v", owner, repository), nil)
+	if err != nil {
+		return false, err
+	}
+	settings := securityAndAnalysis{}
+	if _, err := client.Do(ctx, request, &settings); err != nil {
+		return false, errors.Wrapf(err, "failed to read security settings of repository '%v/%v'", owner, repository)
+	}
+	return settings.SecurityAndAnalysis.SecretScanning.Status == "enabled", nil
+}
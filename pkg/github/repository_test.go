@@ -0,0 +1,60 @@
+package github
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecretScanningEnabled(t *testing.T) {
+	handler := func(response string) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/repos/SAP/jenkins-library" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			fmt.Fprint(w, response)
+		})
+	}
+
+	t.Run("enabled", func(t *testing.T) {
+		server := httptest.NewServer(handler(`{"security_and_analysis": {"secret_scanning": {"status": "enabled"}}}`))
+		defer server.Close()
+		ctx, client, err := NewClient("token", server.URL, "")
+		require.NoError(t, err)
+
+		enabled, err := SecretScanningEnabled(ctx, client, "SAP", "jenkins-library")
+
+		if assert.NoError(t, err) {
+			assert.True(t, enabled)
+		}
+	})
+
+	t.Run("not visible", func(t *testing.T) {
+		server := httptest.NewServer(handler(`{"name": "jenkins-library"}`))
+		defer server.Close()
+		ctx, client, err := NewClient("token", server.URL, "")
+		require.NoError(t, err)
+
+		enabled, err := SecretScanningEnabled(ctx, client, "SAP", "jenkins-library")
+
+		if assert.NoError(t, err) {
+			assert.False(t, enabled)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		server := httptest.NewServer(handler(``))
+		defer server.Close()
+		ctx, client, err := NewClient("token", server.URL, "")
+		require.NoError(t, err)
+
+		_, err = SecretScanningEnabled(ctx, client, "SAP", "other")
+
+		assert.Contains(t, err.Error(), "failed to read security settings of repository 'SAP/other'")
+	})
+}
@@ -29,6 +29,7 @@ type Client struct {
 	maxRetries                int
 	transportTimeout          time.Duration
 	transportSkipVerification bool
+	certificates              []tls.Certificate
 	username                  string
 	password                  string
 	token                     string
@@ -59,6 +60,7 @@ type ClientOptions struct {
 	DoLogRequestBodyOnDebug   bool
 	DoLogResponseBodyOnDebug  bool
 	UseDefaultTransport       bool
+	Certificates              []tls.Certificate
 }
 
 // TransportWrapper is a wrapper for central logging capabilities
@@ -197,6 +199,7 @@ func (c *Client) SetOptions(options ClientOptions) {
 	c.useDefaultTransport = options.UseDefaultTransport
 	c.transportTimeout = options.TransportTimeout
 	c.transportSkipVerification = options.TransportSkipVerification
+	c.certificates = options.Certificates
 	c.maxRequestDuration = options.MaxRequestDuration
 	c.username = options.Username
 	c.password = options.Password
@@ -225,6 +228,7 @@ func (c *Client) initialize() *http.Client {
 			TLSHandshakeTimeout:   c.transportTimeout,
 			TLSClientConfig: &tls.Config{
 				InsecureSkipVerify: c.transportSkipVerification,
+				Certificates:       c.certificates,
 			},
 		},
 		doLogRequestBodyOnDebug:  c.doLogRequestBodyOnDebug,
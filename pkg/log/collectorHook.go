@@ -0,0 +1,46 @@
+package log
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// CollectorHook provides a logrus hook which keeps the latest log lines in memory.
+// This is helpful in order to attach the log output preceding an error to a debug report.
+type CollectorHook struct {
+	MaxLines int
+	lines    []string
+	mutex    sync.Mutex
+}
+
+// Levels returns the supported log levels of the hook.
+func (c *CollectorHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire stores the formatted log message, secrets are masked like in the regular log output.
+func (c *CollectorHook) Fire(entry *logrus.Entry) error {
+	formatter := PiperLogFormatter{logFormat: logFormatWithTimestamp}
+	message, err := formatter.Format(entry)
+	if err != nil {
+		// ignore errors, since we don't want to break the logging flow
+		return nil
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.lines = append(c.lines, strings.TrimRight(string(message), "\n"))
+	if c.MaxLines > 0 && len(c.lines) > c.MaxLines {
+		c.lines = c.lines[len(c.lines)-c.MaxLines:]
+	}
+	return nil
+}
+
+// Lines returns the collected log lines.
+func (c *CollectorHook) Lines() []string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return append([]string{}, c.lines...)
+}
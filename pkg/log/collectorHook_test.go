@@ -0,0 +1,42 @@
+package log
+
+import (
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollectorHookLevels(t *testing.T) {
+	hook := CollectorHook{}
+	assert.Equal(t, logrus.AllLevels, hook.Levels())
+}
+
+func TestCollectorHookFire(t *testing.T) {
+	t.Run("keeps latest lines", func(t *testing.T) {
+		hook := CollectorHook{MaxLines: 2}
+		for _, message := range []string{"first", "second", "third"} {
+			err := hook.Fire(&logrus.Entry{Level: logrus.InfoLevel, Data: logrus.Fields{"stepName": "testStep"}, Message: message})
+			assert.NoError(t, err)
+		}
+
+		lines := hook.Lines()
+		if assert.Len(t, lines, 2) {
+			assert.Contains(t, lines[0], "info  testStep second")
+			assert.Contains(t, lines[1], "info  testStep third")
+		}
+	})
+
+	t.Run("masks secrets", func(t *testing.T) {
+		RegisterSecret("collectorHookSecret")
+		hook := CollectorHook{}
+
+		hook.Fire(&logrus.Entry{Level: logrus.ErrorLevel, Data: logrus.Fields{}, Message: "password collectorHookSecret"})
+
+		lines := hook.Lines()
+		if assert.Len(t, lines, 1) {
+			assert.Contains(t, lines[0], "password ****")
+			assert.NotContains(t, lines[0], "collectorHookSecret")
+		}
+	})
+}
@@ -16,6 +16,7 @@ type ExecMockRunner struct {
 	Env                 []string
 	ExitCode            int
 	Calls               []ExecCall
+	stdin               io.Reader
 	stdout              io.Writer
 	stderr              io.Writer
 	StdoutReturn        map[string]string
@@ -27,6 +28,8 @@ type ExecCall struct {
 	Async     bool
 	Exec      string
 	Params    []string
+	// Stdin contains the input provided via Stdin
+	Stdin string
 }
 
 type Execution struct {
@@ -60,6 +63,10 @@ func (m *ExecMockRunner) AppendEnv(e []string) {
 func (m *ExecMockRunner) RunExecutable(e string, p ...string) error {
 
 	exec := ExecCall{Exec: e, Params: p}
+	if m.stdin != nil {
+		in, _ := ioutil.ReadAll(m.stdin)
+		exec.Stdin = string(in)
+	}
 	m.Calls = append(m.Calls, exec)
 
 	c := strings.Join(append([]string{e}, p...), " ")
@@ -86,6 +93,10 @@ func (m *ExecMockRunner) RunExecutableInBackground(e string, p ...string) (comma
 	return &execution, nil
 }
 
+func (m *ExecMockRunner) Stdin(in io.Reader) {
+	m.stdin = in
+}
+
 func (m *ExecMockRunner) Stdout(out io.Writer) {
 	m.stdout = out
 }
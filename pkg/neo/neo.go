@@ -0,0 +1,199 @@
+package neo
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/magiconair/properties"
+	"github.com/pkg/errors"
+)
+
+// DeployMode defines how the deployment to the neo environment is performed
+type DeployMode string
+
+// WarAction defines how a WAR file is brought to the neo environment
+type WarAction string
+
+const (
+	// DeployModeMta deploys an MTA archive
+	DeployModeMta DeployMode = "mta"
+	// DeployModeWarParams deploys a WAR file, the target is described via the step parameters
+	DeployModeWarParams DeployMode = "warParams"
+	// DeployModeWarPropertiesFile deploys a WAR file, the target is described in a properties file
+	DeployModeWarPropertiesFile DeployMode = "warPropertiesFile"
+
+	// WarActionDeploy deploys the application and restarts it afterwards
+	WarActionDeploy WarAction = "deploy"
+	// WarActionRollingUpdate updates a running application without downtime
+	WarActionRollingUpdate WarAction = "rolling-update"
+
+	// StatusStarted is the status of a running application
+	StatusStarted = "STARTED"
+)
+
+// IsWarDeployment returns true in case a WAR file gets deployed
+func (m DeployMode) IsWarDeployment() bool {
+	return m == DeployModeWarParams || m == DeployModeWarPropertiesFile
+}
+
+// Target describes the account/application in the neo environment
+type Target struct {
+	Host           string
+	Account        string
+	Application    string
+	Runtime        string
+	RuntimeVersion string
+	Size           string
+	Environment    map[string]interface{}
+	VMArguments    string
+	// PropertiesFile is only taken into account for deploy mode 'warPropertiesFile'
+	PropertiesFile string
+}
+
+// Credentials used for authenticating against the neo environment
+type Credentials struct {
+	User     string
+	Password string
+}
+
+// CommandBuilder creates the arguments for the different neo.sh calls
+type CommandBuilder struct {
+	Mode        DeployMode
+	Target      Target
+	Credentials Credentials
+	Source      string
+	Extensions  []string
+}
+
+// StatusArgs returns the arguments for retrieving the status of an application
+func (b *CommandBuilder) StatusArgs() ([]string, error) {
+	if b.Mode == DeployModeMta {
+		return nil, fmt.Errorf("status command cannot be executed for MTA applications")
+	}
+	return append([]string{"status"}, b.mainArgs()...), nil
+}
+
+// DeployArgs returns the arguments for deploying a WAR file
+func (b *CommandBuilder) DeployArgs() []string {
+	args := append([]string{"deploy"}, b.mainArgs()...)
+	args = append(args, "--source", b.Source)
+	return append(args, b.additionalArgs()...)
+}
+
+// RollingUpdateArgs returns the arguments for a rolling update of a WAR file
+func (b *CommandBuilder) RollingUpdateArgs() []string {
+	args := append([]string{"rolling-update"}, b.mainArgs()...)
+	args = append(args, "--source", b.Source)
+	return append(args, b.additionalArgs()...)
+}
+
+// RestartArgs returns the arguments for restarting an application
+func (b *CommandBuilder) RestartArgs() []string {
+	return append([]string{"restart", "--synchronous"}, b.mainArgs()...)
+}
+
+// DeployMtaArgs returns the arguments for deploying an MTA archive
+func (b *CommandBuilder) DeployMtaArgs() []string {
+	args := append([]string{"deploy-mta", "--synchronous"}, b.mainArgs()...)
+	if len(b.Extensions) > 0 {
+		args = append(args, "--extensions", strings.Join(b.Extensions, ","))
+	}
+	return append(args, "--source", b.Source)
+}
+
+func (b *CommandBuilder) mainArgs() []string {
+	credentials := []string{"--user", b.Credentials.User, "--password", b.Credentials.Password}
+
+	if b.Mode == DeployModeWarPropertiesFile {
+		return append([]string{b.Target.PropertiesFile}, credentials...)
+	}
+
+	args := []string{"--host", b.Target.Host, "--account", b.Target.Account}
+	if b.Mode == DeployModeWarParams {
+		args = append(args, "--application", b.Target.Application)
+	}
+	return append(args, credentials...)
+}
+
+func (b *CommandBuilder) additionalArgs() []string {
+	if b.Mode != DeployModeWarParams {
+		return []string{}
+	}
+
+	args := []string{"--runtime", b.Target.Runtime, "--runtime-version", b.Target.RuntimeVersion}
+	if len(b.Target.Size) > 0 {
+		args = append(args, "--size", b.Target.Size)
+	}
+
+	// sorted for reproducible command lines
+	keys := []string{}
+	for key := range b.Target.Environment {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		args = append(args, "--ev", fmt.Sprintf("%s=%v", key, b.Target.Environment[key]))
+	}
+
+	if len(b.Target.VMArguments) > 0 {
+		args = append(args, "--vm-arguments", b.Target.VMArguments)
+	}
+	return args
+}
+
+// CloudCockpitLink returns the link to the application (or MTA) dashboard in the SAP BTP cockpit
+func CloudCockpitLink(mode DeployMode, target Target) string {
+	if mode == DeployModeMta {
+		return fmt.Sprintf("https://account.%s/cockpit#/acc/%s/mtas", target.Host, target.Account)
+	}
+	return fmt.Sprintf("https://account.%s/cockpit#/acc/%s/app/%s/dashboard", target.Host, target.Account, target.Application)
+}
+
+// ReadTargetFromProperties reads host, account and application from the content of a neo properties file
+func ReadTargetFromProperties(content []byte) (Target, error) {
+	props, err := properties.Load(content, properties.UTF8)
+	if err != nil {
+		return Target{}, errors.Wrap(err, "failed to parse neo properties file")
+	}
+	target := Target{
+		Host:        props.GetString("host", ""),
+		Account:     props.GetString("account", ""),
+		Application: props.GetString("application", ""),
+	}
+	if len(target.Host) == 0 || len(target.Account) == 0 || len(target.Application) == 0 {
+		return target, fmt.Errorf("configuration for host, account or application is missing in the properties file")
+	}
+	return target, nil
+}
+
+// Status contains the information parsed from the output of neo.sh
+type Status struct {
+	State string
+	URLs  []string
+}
+
+var statusPattern = regexp.MustCompile(`(?m)^\s*Status:\s*([A-Z_]+)`)
+var urlPattern = regexp.MustCompile(`https?://[^\s"'<>]+`)
+
+// ParseOutput extracts the application status and the application URLs from the output of neo.sh
+func ParseOutput(output string) Status {
+	status := Status{URLs: []string{}}
+	if match := statusPattern.FindStringSubmatch(output); match != nil {
+		status.State = match[1]
+	}
+	for _, line := range strings.Split(output, "\n") {
+		lower := strings.ToLower(line)
+		if !strings.Contains(lower, "url") && !strings.Contains(lower, "access point") {
+			continue
+		}
+		for _, url := range urlPattern.FindAllString(line, -1) {
+			if !piperutils.ContainsString(status.URLs, url) {
+				status.URLs = append(status.URLs, url)
+			}
+		}
+	}
+	return status
+}
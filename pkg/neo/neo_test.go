@@ -0,0 +1,127 @@
+package neo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommandBuilder(t *testing.T) {
+	credentials := Credentials{User: "me", Password: "secret"}
+	target := Target{
+		Host:           "hana.example.org",
+		Account:        "myAccount",
+		Application:    "myApp",
+		Runtime:        "neo-javaee7-wp",
+		RuntimeVersion: "2",
+		Size:           "lite",
+		Environment:    map[string]interface{}{"ENV2": "value2", "ENV1": "value1"},
+		VMArguments:    "-Dargument1=value1",
+	}
+
+	t.Run("mta", func(t *testing.T) {
+		b := CommandBuilder{Mode: DeployModeMta, Target: target, Credentials: credentials, Source: "app.mtar", Extensions: []string{"ext1.mtaext", "ext2.mtaext"}}
+
+		assert.Equal(t, []string{
+			"deploy-mta", "--synchronous",
+			"--host", "hana.example.org", "--account", "myAccount",
+			"--user", "me", "--password", "secret",
+			"--extensions", "ext1.mtaext,ext2.mtaext",
+			"--source", "app.mtar",
+		}, b.DeployMtaArgs())
+
+		_, err := b.StatusArgs()
+		assert.EqualError(t, err, "status command cannot be executed for MTA applications")
+	})
+
+	t.Run("war params", func(t *testing.T) {
+		b := CommandBuilder{Mode: DeployModeWarParams, Target: target, Credentials: credentials, Source: "app.war"}
+
+		assert.Equal(t, []string{
+			"deploy",
+			"--host", "hana.example.org", "--account", "myAccount", "--application", "myApp",
+			"--user", "me", "--password", "secret",
+			"--source", "app.war",
+			"--runtime", "neo-javaee7-wp", "--runtime-version", "2", "--size", "lite",
+			"--ev", "ENV1=value1", "--ev", "ENV2=value2",
+			"--vm-arguments", "-Dargument1=value1",
+		}, b.DeployArgs())
+		assert.Equal(t, "rolling-update", b.RollingUpdateArgs()[0])
+		assert.Equal(t, []string{
+			"restart", "--synchronous",
+			"--host", "hana.example.org", "--account", "myAccount", "--application", "myApp",
+			"--user", "me", "--password", "secret",
+		}, b.RestartArgs())
+
+		status, err := b.StatusArgs()
+		if assert.NoError(t, err) {
+			assert.Equal(t, "status", status[0])
+		}
+	})
+
+	t.Run("war properties file", func(t *testing.T) {
+		b := CommandBuilder{Mode: DeployModeWarPropertiesFile, Target: Target{PropertiesFile: "neo.properties"}, Credentials: credentials, Source: "app.war"}
+
+		assert.Equal(t, []string{
+			"rolling-update",
+			"neo.properties",
+			"--user", "me", "--password", "secret",
+			"--source", "app.war",
+		}, b.RollingUpdateArgs())
+	})
+}
+
+func TestCloudCockpitLink(t *testing.T) {
+	target := Target{Host: "hana.example.org", Account: "myAccount", Application: "myApp"}
+	assert.Equal(t, "https://account.hana.example.org/cockpit#/acc/myAccount/mtas", CloudCockpitLink(DeployModeMta, target))
+	assert.Equal(t, "https://account.hana.example.org/cockpit#/acc/myAccount/app/myApp/dashboard", CloudCockpitLink(DeployModeWarParams, target))
+}
+
+func TestReadTargetFromProperties(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		target, err := ReadTargetFromProperties([]byte("host=hana.example.org\naccount=myAccount\napplication=myApp\n"))
+		if assert.NoError(t, err) {
+			assert.Equal(t, Target{Host: "hana.example.org", Account: "myAccount", Application: "myApp"}, target)
+		}
+	})
+	t.Run("incomplete", func(t *testing.T) {
+		_, err := ReadTargetFromProperties([]byte("host=hana.example.org\n"))
+		assert.EqualError(t, err, "configuration for host, account or application is missing in the properties file")
+	})
+}
+
+func TestParseOutput(t *testing.T) {
+	t.Run("status output", func(t *testing.T) {
+		output := `Requesting status for:
+   application: myApp
+   account    : myAccount
+   host       : https://hana.example.org
+
+SDK version    : 3.111.9
+User           : me
+
+Status: STARTED
+
+URL: https://myappmyaccount.hana.example.org
+Access points:
+   https://myappmyaccount.hana.example.org
+   https://myappmyaccount.hana.example.org/other
+
+Runtime: neo-javaee7-wp
+`
+		status := ParseOutput(output)
+		assert.Equal(t, StatusStarted, status.State)
+		assert.Equal(t, []string{"https://myappmyaccount.hana.example.org"}, status.URLs)
+	})
+
+	t.Run("not running", func(t *testing.T) {
+		status := ParseOutput("Status: STOPPED\n")
+		assert.Equal(t, "STOPPED", status.State)
+		assert.Empty(t, status.URLs)
+	})
+
+	t.Run("unknown output", func(t *testing.T) {
+		status := ParseOutput("(!) ERROR: Application does not exist")
+		assert.Empty(t, status.State)
+	})
+}
@@ -0,0 +1,190 @@
+package neo
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DeploymentStateRunning is the state of an MTA deployment which is still in progress
+	DeploymentStateRunning = "RUNNING"
+	// DeploymentStateDone is the state of a successful MTA deployment
+	DeploymentStateDone = "DONE"
+	// DeploymentStateFailed is the state of a failed MTA deployment
+	DeploymentStateFailed = "FAILED"
+)
+
+// OAuthCredentials are the OAuth client credentials used for the deployment of MTA archives
+type OAuthCredentials struct {
+	ClientID     string `json:"oauthClientId"`
+	ClientSecret string `json:"oauthClientSecret"`
+	ServiceURL   string `json:"oauthServiceUrl"`
+}
+
+// ReadOAuthCredentials reads the OAuth client credentials from the content of a JSON credentials file
+func ReadOAuthCredentials(content []byte) (OAuthCredentials, error) {
+	credentials := OAuthCredentials{}
+	if err := json.Unmarshal(content, &credentials); err != nil {
+		return credentials, errors.Wrap(err, "failed to parse OAuth credentials file")
+	}
+	if len(credentials.ClientID) == 0 || len(credentials.ClientSecret) == 0 || len(credentials.ServiceURL) == 0 {
+		return credentials, fmt.Errorf("oauthClientId, oauthClientSecret or oauthServiceUrl is missing in the OAuth credentials file")
+	}
+	return credentials, nil
+}
+
+// MtaDeployer deploys MTA archives via the solution lifecycle management service using an OAuth bearer token
+type MtaDeployer struct {
+	Client       piperhttp.Uploader
+	Host         string
+	Account      string
+	PollInterval time.Duration
+}
+
+type mtaDeployment struct {
+	ID       string `json:"id"`
+	State    string `json:"state"`
+	Progress []struct {
+		Modules []struct {
+			Error struct {
+				InternalMessage string `json:"internalMessage"`
+			} `json:"error"`
+		} `json:"modules"`
+	} `json:"progress"`
+}
+
+// Deploy uploads the MTA archive and waits until the deployment is finished
+func (d *MtaDeployer) Deploy(credentials OAuthCredentials, archive string, content io.Reader) error {
+	log.Entry().Info("Retrieving OAuth token...")
+	token, err := fetchToken(d.Client, credentials.ServiceURL+"/apitoken/v1?grant_type=client_credentials", credentials.ClientID, credentials.ClientSecret)
+	if err != nil {
+		return err
+	}
+	header := http.Header{"Authorization": {"Bearer " + token}}
+	mtarsURL := fmt.Sprintf("https://slservice.%s/slservice/v1/oauth/accounts/%s/mtars", d.Host, d.Account)
+
+	log.Entry().Infof("Deploying '%s' to '%s'...", archive, d.Account)
+	response, err := d.Client.Upload(piperhttp.UploadRequestData{
+		Method:        http.MethodPost,
+		URL:           mtarsURL,
+		File:          archive,
+		FileFieldName: "file",
+		FileContent:   content,
+		Header:        header,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to upload MTA archive '%s'", archive)
+	}
+	deployment := mtaDeployment{}
+	if err := piperhttp.ParseHTTPResponseBodyJSON(response, &deployment); err != nil {
+		return errors.Wrap(err, "failed to read the deployment")
+	}
+	log.Entry().Infof("Deployment Id is '%s'.", deployment.ID)
+
+	statusURL := fmt.Sprintf("%s/%s", mtarsURL, deployment.ID)
+	for {
+		response, err := d.Client.SendRequest(http.MethodGet, statusURL, nil, header, nil)
+		if err != nil {
+			return errors.Wrapf(err, "failed to retrieve the status of deployment '%s'", deployment.ID)
+		}
+		if err := piperhttp.ParseHTTPResponseBodyJSON(response, &deployment); err != nil {
+			return errors.Wrapf(err, "failed to read the status of deployment '%s'", deployment.ID)
+		}
+		if deployment.State != DeploymentStateRunning {
+			break
+		}
+		log.Entry().Info("Deployment is still running...")
+		time.Sleep(d.PollInterval)
+	}
+
+	switch deployment.State {
+	case DeploymentStateDone:
+		log.Entry().Info("Deployment has succeeded.")
+		return nil
+	case DeploymentStateFailed:
+		if len(deployment.Progress) > 0 && len(deployment.Progress[0].Modules) > 0 && len(deployment.Progress[0].Modules[0].Error.InternalMessage) > 0 {
+			return fmt.Errorf("deployment has failed with the message: %s", deployment.Progress[0].Modules[0].Error.InternalMessage)
+		}
+		return fmt.Errorf("deployment has failed")
+	default:
+		return fmt.Errorf("deployment failed with unknown status: '%s'", deployment.State)
+	}
+}
+
+// Portal provides access to the portal service hosting the SAP Fiori launchpad
+type Portal struct {
+	Client    piperhttp.Sender
+	Host      string
+	Account   string
+	Landscape string
+}
+
+// InvalidateCache refreshes the content of the HTML5 applications in the cache of an SAP Fiori launchpad site.
+// In case no site ID is provided, the default site defined in the portal service is used.
+func (p *Portal) InvalidateCache(clientID, clientSecret, siteID string) error {
+	tokenURL := fmt.Sprintf("https://oauthasservices-%s.%s/oauth2/api/v1/token?grant_type=client_credentials&scope=write,read", p.Account, p.Host)
+	token, err := fetchToken(p.Client, tokenURL, clientID, clientSecret)
+	if err != nil {
+		return err
+	}
+	log.Entry().Info("Retrieved bearer token.")
+
+	portalURL := fmt.Sprintf("https://%s-%s.%s/fiori", p.Landscape, p.Account, p.Host)
+	header := http.Header{}
+	header.Set("Authorization", "Bearer "+token)
+	header.Set("X-CSRF-Token", "Fetch")
+	response, err := p.Client.SendRequest(http.MethodGet, portalURL+"/api/v1/csrf", nil, header, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to fetch the CSRF token")
+	}
+	// the CSRF token is only valid within the session established by the cookies of the response
+	cookies := response.Cookies()
+	header = http.Header{}
+	header.Set("Authorization", "Bearer "+token)
+	header.Set("X-CSRF-Token", response.Header.Get("X-CSRF-Token"))
+	header.Set("Content-Type", "application/json")
+
+	if len(siteID) == 0 {
+		log.Entry().Info("Using the default site defined in Portal service and invalidating the cache.")
+	} else {
+		log.Entry().Infof("Invalidating the cache for site with Id: %s.", siteID)
+	}
+	body, _ := json.Marshal(map[string]string{"siteId": siteID})
+	response, err = p.Client.SendRequest(http.MethodPost, portalURL+"/v1/operations/invalidateCache", bytes.NewReader(body), header, cookies)
+	if err != nil {
+		if len(siteID) == 0 && response != nil && response.StatusCode == http.StatusInternalServerError {
+			return fmt.Errorf("invalidating the cache failed. " +
+				"As no siteId is set, the default site defined in the portal UI is used. " +
+				"Please verify a default site is defined in Portal service. " +
+				"Alternatively, configure the siteId parameter for this step to invalidate the cache of that specific site")
+		}
+		return errors.Wrap(err, "invalidating the cache failed")
+	}
+	log.Entry().Info("Successfully invalidated the cache.")
+	return nil
+}
+
+func fetchToken(client piperhttp.Sender, url, clientID, clientSecret string) (string, error) {
+	header := http.Header{"Authorization": {"Basic " + base64.StdEncoding.EncodeToString([]byte(clientID+":"+clientSecret))}}
+	response, err := client.SendRequest(http.MethodPost, url, nil, header, nil)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to retrieve the OAuth token")
+	}
+	token := struct {
+		AccessToken string `json:"access_token"`
+	}{}
+	if err := piperhttp.ParseHTTPResponseBodyJSON(response, &token); err != nil {
+		return "", errors.Wrap(err, "failed to read the OAuth token")
+	}
+	log.RegisterSecret(token.AccessToken)
+	return token.AccessToken, nil
+}
@@ -0,0 +1,166 @@
+package neo
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockResponse struct {
+	statusCode int
+	body       string
+	header     http.Header
+}
+
+type senderMock struct {
+	requests  []string
+	headers   []http.Header
+	bodies    []string
+	cookies   [][]*http.Cookie
+	responses map[string][]mockResponse
+}
+
+func (s *senderMock) SendRequest(method, url string, body io.Reader, header http.Header, cookies []*http.Cookie) (*http.Response, error) {
+	key := method + " " + url
+	s.requests = append(s.requests, key)
+	s.headers = append(s.headers, header)
+	s.cookies = append(s.cookies, cookies)
+	content := []byte{}
+	if body != nil {
+		content, _ = ioutil.ReadAll(body)
+	}
+	s.bodies = append(s.bodies, string(content))
+
+	responses := s.responses[key]
+	if len(responses) == 0 {
+		return &http.Response{StatusCode: http.StatusNotFound, Body: ioutil.NopCloser(strings.NewReader(""))}, fmt.Errorf("unexpected request %s", key)
+	}
+	response := responses[0]
+	if len(responses) > 1 {
+		s.responses[key] = responses[1:]
+	}
+	if response.statusCode == 0 {
+		response.statusCode = http.StatusOK
+	}
+	httpResponse := &http.Response{StatusCode: response.statusCode, Header: response.header, Body: ioutil.NopCloser(strings.NewReader(response.body))}
+	if response.statusCode >= 300 {
+		return httpResponse, fmt.Errorf("request to %s returned with response %d", url, response.statusCode)
+	}
+	return httpResponse, nil
+}
+
+func (s *senderMock) SetOptions(options piperhttp.ClientOptions) {}
+
+func (s *senderMock) Upload(data piperhttp.UploadRequestData) (*http.Response, error) {
+	return s.SendRequest(data.Method, data.URL, data.FileContent, data.Header, data.Cookies)
+}
+
+func (s *senderMock) UploadRequest(method, url, file, fieldName string, header http.Header, cookies []*http.Cookie) (*http.Response, error) {
+	return s.SendRequest(method, url, nil, header, cookies)
+}
+
+func (s *senderMock) UploadFile(url, file, fieldName string, header http.Header, cookies []*http.Cookie) (*http.Response, error) {
+	return s.UploadRequest(http.MethodPost, url, file, fieldName, header, cookies)
+}
+
+func TestReadOAuthCredentials(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		credentials, err := ReadOAuthCredentials([]byte(`{"oauthClientId": "client", "oauthClientSecret": "secret", "oauthServiceUrl": "https://oauth.example.org"}`))
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, OAuthCredentials{ClientID: "client", ClientSecret: "secret", ServiceURL: "https://oauth.example.org"}, credentials)
+		}
+	})
+
+	t.Run("incomplete", func(t *testing.T) {
+		_, err := ReadOAuthCredentials([]byte(`{"oauthClientId": "client"}`))
+
+		assert.EqualError(t, err, "oauthClientId, oauthClientSecret or oauthServiceUrl is missing in the OAuth credentials file")
+	})
+}
+
+func TestMtaDeployer(t *testing.T) {
+	credentials := OAuthCredentials{ClientID: "client", ClientSecret: "secret", ServiceURL: "https://oauth.example.org"}
+	tokenKey := "POST https://oauth.example.org/apitoken/v1?grant_type=client_credentials"
+	mtarsURL := "https://slservice.hana.example.org/slservice/v1/oauth/accounts/myAccount/mtars"
+
+	t.Run("success", func(t *testing.T) {
+		client := &senderMock{responses: map[string][]mockResponse{
+			tokenKey:                   {{body: `{"access_token": "token"}`}},
+			"POST " + mtarsURL:         {{body: `{"id": "123"}`}},
+			"GET " + mtarsURL + "/123": {{body: `{"state": "RUNNING"}`}, {body: `{"state": "DONE"}`}},
+		}}
+		deployer := MtaDeployer{Client: client, Host: "hana.example.org", Account: "myAccount"}
+
+		err := deployer.Deploy(credentials, "app.mtar", strings.NewReader("dummy"))
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, []string{tokenKey, "POST " + mtarsURL, "GET " + mtarsURL + "/123", "GET " + mtarsURL + "/123"}, client.requests)
+			assert.Equal(t, "Basic Y2xpZW50OnNlY3JldA==", client.headers[0].Get("Authorization"))
+			assert.Equal(t, "Bearer token", client.headers[1].Get("Authorization"))
+			assert.Equal(t, "dummy", client.bodies[1])
+		}
+	})
+
+	t.Run("deployment fails", func(t *testing.T) {
+		client := &senderMock{responses: map[string][]mockResponse{
+			tokenKey:                   {{body: `{"access_token": "token"}`}},
+			"POST " + mtarsURL:         {{body: `{"id": "123"}`}},
+			"GET " + mtarsURL + "/123": {{body: `{"state": "FAILED", "progress": [{"modules": [{"error": {"internalMessage": "module failed"}}]}]}`}},
+		}}
+		deployer := MtaDeployer{Client: client, Host: "hana.example.org", Account: "myAccount"}
+
+		err := deployer.Deploy(credentials, "app.mtar", strings.NewReader("dummy"))
+
+		assert.EqualError(t, err, "deployment has failed with the message: module failed")
+	})
+}
+
+func TestInvalidateCache(t *testing.T) {
+	tokenKey := "POST https://oauthasservices-myAccount.hana.example.org/oauth2/api/v1/token?grant_type=client_credentials&scope=write,read"
+	csrfKey := "GET https://cloudnwcportal-myAccount.hana.example.org/fiori/api/v1/csrf"
+	invalidateKey := "POST https://cloudnwcportal-myAccount.hana.example.org/fiori/v1/operations/invalidateCache"
+
+	t.Run("success", func(t *testing.T) {
+		client := &senderMock{responses: map[string][]mockResponse{
+			tokenKey:      {{body: `{"access_token": "token"}`}},
+			csrfKey:       {{header: http.Header{"X-Csrf-Token": {"csrf"}, "Set-Cookie": {"session=abc"}}}},
+			invalidateKey: {{}},
+		}}
+		portal := Portal{Client: client, Host: "hana.example.org", Account: "myAccount", Landscape: "cloudnwcportal"}
+
+		err := portal.InvalidateCache("client", "secret", "mySite")
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, []string{tokenKey, csrfKey, invalidateKey}, client.requests)
+			assert.Equal(t, "Fetch", client.headers[1].Get("X-CSRF-Token"))
+			assert.Equal(t, "csrf", client.headers[2].Get("X-CSRF-Token"))
+			assert.Equal(t, "Bearer token", client.headers[2].Get("Authorization"))
+			assert.Equal(t, `{"siteId":"mySite"}`, client.bodies[2])
+			if assert.Len(t, client.cookies[2], 1) {
+				assert.Equal(t, "session", client.cookies[2][0].Name)
+			}
+		}
+	})
+
+	t.Run("no default site", func(t *testing.T) {
+		client := &senderMock{responses: map[string][]mockResponse{
+			tokenKey:      {{body: `{"access_token": "token"}`}},
+			csrfKey:       {{}},
+			invalidateKey: {{statusCode: http.StatusInternalServerError}},
+		}}
+		portal := Portal{Client: client, Host: "hana.example.org", Account: "myAccount", Landscape: "cloudnwcportal"}
+
+		err := portal.InvalidateCache("client", "secret", "")
+
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "As no siteId is set, the default site defined in the portal UI is used")
+		}
+	})
+}
@@ -0,0 +1,65 @@
+package notification
+
+import (
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// MailServer describes the SMTP server used for sending mails
+type MailServer struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+}
+
+// MailSender sends mails via SMTP
+type MailSender struct {
+	Server MailServer
+	// sendMail is used for sending the mail, can be replaced for testing purposes
+	sendMail func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewMailSender returns a MailSender for the given SMTP server
+func NewMailSender(server MailServer) *MailSender {
+	return &MailSender{Server: server, sendMail: smtp.SendMail}
+}
+
+// Send sends the message as mail to the recipients
+func (s *MailSender) Send(from string, recipients []string, message Message) error {
+	if len(recipients) == 0 {
+		return fmt.Errorf("no mail recipients provided")
+	}
+
+	var auth smtp.Auth
+	if len(s.Server.Username) > 0 {
+		auth = smtp.PlainAuth("", s.Server.Username, s.Server.Password, s.Server.Host)
+	}
+
+	addr := net.JoinHostPort(s.Server.Host, strconv.Itoa(s.Server.Port))
+	if err := s.sendMail(addr, auth, from, recipients, composeMail(from, recipients, message)); err != nil {
+		return errors.Wrapf(err, "failed to send mail via '%s'", addr)
+	}
+	return nil
+}
+
+// headerLineBreaks removes line breaks from header values which would allow to inject additional headers
+var headerLineBreaks = strings.NewReplacer("\r\n", " ", "\r", " ", "\n", " ")
+
+func composeMail(from string, recipients []string, message Message) []byte {
+	var mail strings.Builder
+	mail.WriteString(fmt.Sprintf("From: %s\r\n", headerLineBreaks.Replace(from)))
+	mail.WriteString(fmt.Sprintf("To: %s\r\n", headerLineBreaks.Replace(strings.Join(recipients, ", "))))
+	mail.WriteString(fmt.Sprintf("Subject: %s\r\n", headerLineBreaks.Replace(message.Subject)))
+	mail.WriteString("MIME-Version: 1.0\r\n")
+	mail.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
+	mail.WriteString("\r\n")
+	mail.WriteString(strings.ReplaceAll(message.Text, "\n", "\r\n"))
+	mail.WriteString("\r\n")
+	return []byte(mail.String())
+}
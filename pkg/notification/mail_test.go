@@ -0,0 +1,135 @@
+package notification
+
+import (
+	"bufio"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// smtpStandIn is a minimal SMTP server which records the received mail
+type smtpStandIn struct {
+	listener   net.Listener
+	from       string
+	recipients []string
+	data       string
+	done       chan bool
+}
+
+func startSMTPStandIn(t *testing.T) *smtpStandIn {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &smtpStandIn{listener: listener, done: make(chan bool, 1)}
+	go s.serve()
+	return s
+}
+
+func (s *smtpStandIn) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *smtpStandIn) serve() {
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	defer func() { s.done <- true }()
+
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+	reply("220 localhost ESMTP")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+			reply("250 localhost")
+		case strings.HasPrefix(command, "MAIL FROM:"):
+			s.from = strings.Trim(strings.TrimPrefix(command, "MAIL FROM:"), "<> ")
+			reply("250 OK")
+		case strings.HasPrefix(command, "RCPT TO:"):
+			s.recipients = append(s.recipients, strings.Trim(strings.TrimPrefix(command, "RCPT TO:"), "<> "))
+			reply("250 OK")
+		case command == "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				dataLine, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if dataLine == ".\r\n" {
+					break
+				}
+				data.WriteString(dataLine)
+			}
+			s.data = data.String()
+			reply("250 OK")
+		case command == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func TestMailSender(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		server := startSMTPStandIn(t)
+		defer server.listener.Close()
+
+		sender := NewMailSender(MailServer{Host: "127.0.0.1", Port: server.port()})
+		err := sender.Send("piper@example.org", []string{"a@example.org", "b@example.org"},
+			Message{Subject: "FAILURE: Build myProject", Text: "line 1\nline 2", Result: ResultFailure})
+
+		if assert.NoError(t, err) {
+			<-server.done
+			assert.Equal(t, "piper@example.org", server.from)
+			assert.Equal(t, []string{"a@example.org", "b@example.org"}, server.recipients)
+			assert.Contains(t, server.data, "Subject: FAILURE: Build myProject\r\n")
+			assert.Contains(t, server.data, "To: a@example.org, b@example.org\r\n")
+			assert.Contains(t, server.data, "line 1\r\nline 2\r\n")
+		}
+	})
+
+	t.Run("no recipients", func(t *testing.T) {
+		err := NewMailSender(MailServer{Host: "127.0.0.1", Port: 25}).Send("piper@example.org", []string{}, Message{})
+
+		assert.EqualError(t, err, "no mail recipients provided")
+	})
+
+	t.Run("server not reachable", func(t *testing.T) {
+		listener, _ := net.Listen("tcp", "127.0.0.1:0")
+		port := listener.Addr().(*net.TCPAddr).Port
+		listener.Close()
+
+		err := NewMailSender(MailServer{Host: "127.0.0.1", Port: port}).Send("piper@example.org", []string{"a@example.org"}, Message{})
+
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "failed to send mail via '127.0.0.1:"+strconv.Itoa(port)+"'")
+		}
+	})
+}
+
+func TestComposeMail(t *testing.T) {
+	t.Run("line breaks in headers", func(t *testing.T) {
+		mail := string(composeMail("piper@example.org\r\nBcc: evil@example.org", []string{"a@example.org"},
+			Message{Subject: "FAILURE\r\nBcc: evil@example.org\nX-Injected: true", Text: "text"}))
+
+		assert.Contains(t, mail, "From: piper@example.org Bcc: evil@example.org\r\n")
+		assert.Contains(t, mail, "Subject: FAILURE Bcc: evil@example.org X-Injected: true\r\n")
+		assert.NotContains(t, mail, "\r\nBcc:")
+		assert.NotContains(t, mail, "\nX-Injected:")
+	})
+}
@@ -0,0 +1,113 @@
+package notification
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// Build results which are relevant for notifications
+const (
+	ResultSuccess  = "SUCCESS"
+	ResultUnstable = "UNSTABLE"
+	ResultFailure  = "FAILURE"
+	ResultAborted  = "ABORTED"
+)
+
+// Sender provides an interface to the piper http client
+type Sender interface {
+	SendRequest(method, url string, body io.Reader, header http.Header, cookies []*http.Cookie) (*http.Response, error)
+}
+
+// Message contains the content of a notification
+type Message struct {
+	Subject string
+	Text    string
+	Result  string
+}
+
+type slackAttachment struct {
+	Color string `json:"color"`
+	Title string `json:"title"`
+	Text  string `json:"text"`
+}
+
+type slackMessage struct {
+	Attachments []slackAttachment `json:"attachments"`
+}
+
+type teamsMessage struct {
+	Type       string `json:"@type"`
+	Context    string `json:"@context"`
+	ThemeColor string `json:"themeColor"`
+	Summary    string `json:"summary"`
+	Title      string `json:"title"`
+	Text       string `json:"text"`
+}
+
+// SendSlack posts the message to a Slack incoming webhook
+func SendSlack(client Sender, webhookURL string, message Message) error {
+	payload := slackMessage{Attachments: []slackAttachment{{
+		Color: slackColor(message.Result),
+		Title: message.Subject,
+		Text:  message.Text,
+	}}}
+	return postJSON(client, webhookURL, payload, "Slack")
+}
+
+// SendTeams posts the message to a Microsoft Teams incoming webhook
+func SendTeams(client Sender, webhookURL string, message Message) error {
+	payload := teamsMessage{
+		Type:       "MessageCard",
+		Context:    "https://schema.org/extensions",
+		ThemeColor: teamsColor(message.Result),
+		Summary:    message.Subject,
+		Title:      message.Subject,
+		Text:       message.Text,
+	}
+	return postJSON(client, webhookURL, payload, "Microsoft Teams")
+}
+
+func postJSON(client Sender, url string, payload interface{}, target string) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal %s message", target)
+	}
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	response, err := client.SendRequest(http.MethodPost, url, bytes.NewReader(body), header, nil)
+	if err != nil {
+		return errors.Wrapf(err, "failed to send %s notification", target)
+	}
+	if response != nil && response.Body != nil {
+		response.Body.Close()
+	}
+	return nil
+}
+
+func slackColor(result string) string {
+	switch result {
+	case ResultSuccess:
+		return "good"
+	case ResultUnstable:
+		return "warning"
+	case ResultFailure:
+		return "danger"
+	}
+	return "#808080"
+}
+
+func teamsColor(result string) string {
+	switch result {
+	case ResultSuccess:
+		return "2EB886"
+	case ResultUnstable:
+		return "DAA038"
+	case ResultFailure:
+		return "A30200"
+	}
+	return "808080"
+}
@@ -0,0 +1,65 @@
+package notification
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSendWebhooks(t *testing.T) {
+	var received map[string]interface{}
+	var contentType string
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		contentType = req.Header.Get("Content-Type")
+		body, _ := ioutil.ReadAll(req.Body)
+		received = map[string]interface{}{}
+		json.Unmarshal(body, &received)
+		if req.URL.Path == "/fail" {
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		rw.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	message := Message{Subject: "FAILURE: Build myProject #1", Text: "Step 'npmExecute' failed", Result: ResultFailure}
+
+	t.Run("slack", func(t *testing.T) {
+		err := SendSlack(&piperhttp.Client{}, server.URL+"/slack", message)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, "application/json", contentType)
+			attachments := received["attachments"].([]interface{})
+			if assert.Len(t, attachments, 1) {
+				attachment := attachments[0].(map[string]interface{})
+				assert.Equal(t, "danger", attachment["color"])
+				assert.Equal(t, "FAILURE: Build myProject #1", attachment["title"])
+				assert.Equal(t, "Step 'npmExecute' failed", attachment["text"])
+			}
+		}
+	})
+
+	t.Run("teams", func(t *testing.T) {
+		err := SendTeams(&piperhttp.Client{}, server.URL+"/teams", message)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, "MessageCard", received["@type"])
+			assert.Equal(t, "A30200", received["themeColor"])
+			assert.Equal(t, "FAILURE: Build myProject #1", received["title"])
+			assert.Equal(t, "Step 'npmExecute' failed", received["text"])
+		}
+	})
+
+	t.Run("webhook fails", func(t *testing.T) {
+		err := SendSlack(&piperhttp.Client{}, server.URL+"/fail", message)
+
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "failed to send Slack notification")
+		}
+	})
+}
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 	"text/template"
 	"time"
 
@@ -20,6 +22,21 @@ type ScanReport struct {
 	ReportTime     time.Time       `json:"reportTime"`
 	DetailTable    ScanDetailTable `json:"detailTable"`
 	SuccessfulScan bool            `json:"successfulScan"`
+	// Findings contains the results which are located in the source code, e.g. for decorating pull requests
+	Findings []Finding `json:"findings,omitempty"`
+}
+
+// Finding defines a single result of a scan which is located in a file of the repository
+type Finding struct {
+	// Rule identifies the check which produced the finding, e.g. the query or rule name
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Message  string `json:"message,omitempty"`
+	// File is the path of the file relative to the root of the repository
+	File string `json:"file"`
+	Line int    `json:"line"`
+	// Link points to the details of the finding in the UI of the scan tool
+	Link string `json:"link,omitempty"`
 }
 
 // ScanDetailTable defines a table containing scan result details
@@ -88,6 +105,25 @@ func (s *ScanReport) AddSubHeader(header, details string) {
 //StepReportDirectory specifies the default directory for markdown reports which can later be collected by step pipelineCreateSummary
 const StepReportDirectory = ".pipeline/stepReports"
 
+type reportFileWriter interface {
+	FileWrite(path string, content []byte, perm os.FileMode) error
+	MkdirAll(path string, perm os.FileMode) error
+}
+
+// WriteJSONReport writes the report in JSON format into the StepReportDirectory, e.g. for steps pipelineCreateScanSummary and githubDecoratePullRequest
+func WriteJSONReport(report ScanReport, fileWriter reportFileWriter) error {
+	// ignore JSON errors since structure is in our hands
+	jsonReport, _ := report.ToJSON()
+	if err := fileWriter.MkdirAll(StepReportDirectory, 0777); err != nil {
+		return errors.Wrap(err, "failed to create reporting directory")
+	}
+	reportPath := filepath.Join(StepReportDirectory, fmt.Sprintf("%v_%v.json", report.StepName, report.ReportTime.Format("20060102150405")))
+	if err := fileWriter.FileWrite(reportPath, jsonReport, 0666); err != nil {
+		return errors.Wrapf(err, "failed to write report %v", reportPath)
+	}
+	return nil
+}
+
 // ToJSON returns the report in JSON format
 func (s *ScanReport) ToJSON() ([]byte, error) {
 	return json.Marshal(s)
@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/SAP/jenkins-library/pkg/mock"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -121,3 +122,21 @@ func TestTableColumnCount(t *testing.T) {
 		assert.Equal(t, 3, tableColumnCount(details))
 	})
 }
+
+func TestWriteJSONReport(t *testing.T) {
+	report := ScanReport{
+		StepName:   "checkmarxExecuteScan",
+		Title:      "Checkmarx SAST Report",
+		ReportTime: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+		Findings:   []Finding{{Rule: "SQL_Injection", Severity: "High", File: "src/main.go", Line: 12}},
+	}
+	files := mock.FilesMock{}
+
+	err := WriteJSONReport(report, &files)
+
+	if assert.NoError(t, err) {
+		content, err := files.FileRead(".pipeline/stepReports/checkmarxExecuteScan_20210101000000.json")
+		assert.NoError(t, err)
+		assert.Contains(t, string(content), `"findings":[{"rule":"SQL_Injection","severity":"High","file":"src/main.go","line":12}]`)
+	}
+}
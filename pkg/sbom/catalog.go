@@ -0,0 +1,477 @@
+package sbom
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/pkg/errors"
+)
+
+// maximum size of archives like jar files which are inspected for Maven packages
+const maxArchiveSize = 256 * 1024 * 1024
+
+// Package is a software package found in a container image
+type Package struct {
+	// Type is the package type as used in package URLs, e.g. deb, apk, rpm, npm, pypi or maven
+	Type      string
+	Namespace string
+	Name      string
+	Version   string
+	Arch      string
+	Licenses  []string
+	// Location is the path of the file within the image the package has been found in
+	Location string
+}
+
+// PURL returns the package URL (https://github.com/package-url/purl-spec) of the package
+func (p Package) PURL() string {
+	purl := "pkg:" + p.Type + "/"
+	if len(p.Namespace) > 0 {
+		purl += purlEscape(p.Namespace) + "/"
+	}
+	purl += purlEscape(p.Name)
+	if len(p.Version) > 0 {
+		purl += "@" + purlEscape(p.Version)
+	}
+	if len(p.Arch) > 0 {
+		purl += "?arch=" + url.QueryEscape(p.Arch)
+	}
+	return purl
+}
+
+// purlEscape percent-encodes a component of a package URL, in contrast to path escaping this includes the separators '@', ':' and '+'
+func purlEscape(component string) string {
+	return strings.NewReplacer("@", "%40", ":", "%3A", "+", "%2B").Replace(url.PathEscape(component))
+}
+
+// OS is the operating system of a container image as defined in its os-release file
+type OS struct {
+	ID      string
+	Version string
+	Name    string
+}
+
+// Inventory contains the packages found in the file system of a container image
+type Inventory struct {
+	OS       OS
+	Packages []Package
+	// RpmDatabase contains the files of the rpm database (by file name) in case the image contains one.
+	// The rpm database cannot be read natively, the result of an rpm query has to be added via AddRpmPackages.
+	RpmDatabase map[string][]byte
+}
+
+var rpmDatabaseDirectories = []string{"var/lib/rpm", "usr/lib/sysimage/rpm"}
+
+// Catalog inspects the flattened file system of a container image provided as tar stream.
+// It reads the package databases of dpkg, apk and rpm as well as npm, Python and Maven packages.
+func Catalog(filesystem io.Reader) (*Inventory, error) {
+	inventory := &Inventory{RpmDatabase: map[string][]byte{}}
+	debLicenses := map[string][]string{}
+	osRelease := map[string][]byte{}
+
+	reader := tar.NewReader(filesystem)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read image file system")
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		name := strings.TrimPrefix(path.Clean("/"+header.Name), "/")
+
+		switch {
+		case name == "etc/os-release" || name == "usr/lib/os-release":
+			content, err := ioutil.ReadAll(reader)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to read %v", name)
+			}
+			osRelease[name] = content
+		case name == "var/lib/dpkg/status" || path.Dir(name) == "var/lib/dpkg/status.d":
+			packages, err := parseDpkgStatus(reader, name)
+			if err != nil {
+				return nil, err
+			}
+			inventory.Packages = append(inventory.Packages, packages...)
+		case strings.HasPrefix(name, "usr/share/doc/") && path.Base(name) == "copyright":
+			debLicenses[path.Base(path.Dir(name))] = parseDebianCopyright(reader)
+		case name == "lib/apk/db/installed":
+			packages, err := parseApkInstalled(reader, name)
+			if err != nil {
+				return nil, err
+			}
+			inventory.Packages = append(inventory.Packages, packages...)
+		case isRpmDatabase(name):
+			content, err := ioutil.ReadAll(reader)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to read %v", name)
+			}
+			inventory.RpmDatabase[path.Base(name)] = content
+		case isNpmPackage(name):
+			pkg, err := parseNpmPackage(reader, name)
+			if err != nil {
+				return nil, err
+			}
+			if pkg != nil {
+				inventory.Packages = append(inventory.Packages, *pkg)
+			}
+		case path.Base(name) == "METADATA" && strings.HasSuffix(path.Dir(name), ".dist-info") ||
+			path.Base(name) == "PKG-INFO" && strings.HasSuffix(path.Dir(name), ".egg-info"):
+			if pkg := parsePythonMetadata(reader, name); pkg != nil {
+				inventory.Packages = append(inventory.Packages, *pkg)
+			}
+		case isJavaArchive(name):
+			if header.Size > maxArchiveSize {
+				continue
+			}
+			content, err := ioutil.ReadAll(reader)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to read %v", name)
+			}
+			inventory.Packages = append(inventory.Packages, parseJavaArchive(content, name, 0)...)
+		}
+	}
+
+	inventory.OS = parseOSRelease(osRelease["etc/os-release"])
+	if len(inventory.OS.ID) == 0 {
+		inventory.OS = parseOSRelease(osRelease["usr/lib/os-release"])
+	}
+	for i, pkg := range inventory.Packages {
+		switch pkg.Type {
+		case "deb":
+			inventory.Packages[i].Licenses = debLicenses[pkg.Name]
+			fallthrough
+		case "apk", "rpm":
+			inventory.Packages[i].Namespace = inventory.OS.ID
+		}
+	}
+	if len(inventory.RpmDatabase) == 0 {
+		inventory.RpmDatabase = nil
+	}
+	inventory.Packages = uniquePackages(inventory.Packages)
+	return inventory, nil
+}
+
+// AddRpmPackages adds the packages of an rpm query using the query format
+// '%{NAME}\t%{EPOCH}\t%{VERSION}-%{RELEASE}\t%{ARCH}\t%{LICENSE}\n'
+func (i *Inventory) AddRpmPackages(queryResult string) error {
+	for _, line := range strings.Split(queryResult, "\n") {
+		if len(strings.TrimSpace(line)) == 0 {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) != 5 {
+			return fmt.Errorf("unexpected rpm query result '%v'", line)
+		}
+		pkg := Package{Type: "rpm", Namespace: i.OS.ID, Name: fields[0], Version: fields[2], Arch: fields[3], Location: "var/lib/rpm"}
+		if epoch := fields[1]; len(epoch) > 0 && epoch != "(none)" && epoch != "0" {
+			pkg.Version = epoch + ":" + pkg.Version
+		}
+		if fields[4] != "(none)" && len(fields[4]) > 0 {
+			pkg.Licenses = []string{fields[4]}
+		}
+		if pkg.Arch == "(none)" {
+			pkg.Arch = ""
+		}
+		i.Packages = append(i.Packages, pkg)
+	}
+	i.Packages = uniquePackages(i.Packages)
+	return nil
+}
+
+func isRpmDatabase(name string) bool {
+	for _, dir := range rpmDatabaseDirectories {
+		if path.Dir(name) == dir {
+			switch path.Base(name) {
+			case "Packages", "Packages.db", "rpmdb.sqlite":
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isNpmPackage checks whether the file is the package.json of a package in node_modules, e.g. 'node_modules/@scope/name/package.json'
+func isNpmPackage(name string) bool {
+	if path.Base(name) != "package.json" {
+		return false
+	}
+	parent := path.Dir(path.Dir(name))
+	return path.Base(parent) == "node_modules" || strings.HasPrefix(path.Base(parent), "@") && path.Base(path.Dir(parent)) == "node_modules"
+}
+
+func isJavaArchive(name string) bool {
+	switch path.Ext(name) {
+	case ".jar", ".war", ".ear":
+		return true
+	}
+	return false
+}
+
+func parseOSRelease(content []byte) OS {
+	os := OS{}
+	for _, line := range strings.Split(string(content), "\n") {
+		separator := strings.Index(line, "=")
+		if separator < 0 {
+			continue
+		}
+		value := strings.Trim(strings.TrimSpace(line[separator+1:]), `"'`)
+		switch strings.TrimSpace(line[:separator]) {
+		case "ID":
+			os.ID = value
+		case "VERSION_ID":
+			os.Version = value
+		case "PRETTY_NAME":
+			os.Name = value
+		}
+	}
+	return os
+}
+
+// parseStanzas reads blocks of 'Key: value' lines separated by empty lines as used by dpkg and apk,
+// continuation lines of dpkg start with a space and are ignored
+func parseStanzas(reader io.Reader) ([]map[string]string, error) {
+	stanzas := []map[string]string{}
+	stanza := map[string]string{}
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if len(strings.TrimSpace(line)) == 0 {
+			if len(stanza) > 0 {
+				stanzas = append(stanzas, stanza)
+				stanza = map[string]string{}
+			}
+			continue
+		}
+		if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			continue
+		}
+		if index := strings.Index(line, ":"); index > 0 {
+			stanza[line[:index]] = strings.TrimSpace(line[index+1:])
+		}
+	}
+	if len(stanza) > 0 {
+		stanzas = append(stanzas, stanza)
+	}
+	return stanzas, scanner.Err()
+}
+
+func parseDpkgStatus(reader io.Reader, location string) ([]Package, error) {
+	stanzas, err := parseStanzas(reader)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read dpkg database %v", location)
+	}
+	packages := []Package{}
+	for _, stanza := range stanzas {
+		// the status files of distroless images do not contain a status
+		if status, ok := stanza["Status"]; ok && !strings.HasSuffix(status, " installed") {
+			continue
+		}
+		if len(stanza["Package"]) == 0 {
+			continue
+		}
+		packages = append(packages, Package{Type: "deb", Name: stanza["Package"], Version: stanza["Version"], Arch: stanza["Architecture"], Location: location})
+	}
+	return packages, nil
+}
+
+// parseDebianCopyright returns the licenses of a machine-readable copyright file
+func parseDebianCopyright(reader io.Reader) []string {
+	licenses := []string{}
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "License:") {
+			continue
+		}
+		license := strings.TrimSpace(strings.TrimPrefix(line, "License:"))
+		if len(license) > 0 && !piperutils.ContainsString(licenses, license) {
+			licenses = append(licenses, license)
+		}
+	}
+	if len(licenses) == 0 {
+		return nil
+	}
+	return licenses
+}
+
+func parseApkInstalled(reader io.Reader, location string) ([]Package, error) {
+	stanzas, err := parseStanzas(reader)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read apk database %v", location)
+	}
+	packages := []Package{}
+	for _, stanza := range stanzas {
+		if len(stanza["P"]) == 0 {
+			continue
+		}
+		pkg := Package{Type: "apk", Name: stanza["P"], Version: stanza["V"], Arch: stanza["A"], Location: location}
+		if len(stanza["L"]) > 0 {
+			pkg.Licenses = []string{stanza["L"]}
+		}
+		packages = append(packages, pkg)
+	}
+	return packages, nil
+}
+
+type npmPackage struct {
+	Name     string          `json:"name"`
+	Version  string          `json:"version"`
+	License  json.RawMessage `json:"license"`
+	Licenses []struct {
+		Type string `json:"type"`
+	} `json:"licenses"`
+}
+
+func parseNpmPackage(reader io.Reader, location string) (*Package, error) {
+	content, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read %v", location)
+	}
+	npm := npmPackage{}
+	if err := json.Unmarshal(content, &npm); err != nil || len(npm.Name) == 0 || len(npm.Version) == 0 {
+		// package.json files which are no package descriptors, e.g. of test fixtures, are ignored
+		return nil, nil
+	}
+	pkg := Package{Type: "npm", Name: npm.Name, Version: npm.Version, Location: location}
+	if strings.HasPrefix(npm.Name, "@") && strings.Contains(npm.Name, "/") {
+		pkg.Namespace = npm.Name[:strings.Index(npm.Name, "/")]
+		pkg.Name = npm.Name[strings.Index(npm.Name, "/")+1:]
+	}
+	var license string
+	var licenseObject struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(npm.License, &license); err == nil && len(license) > 0 {
+		pkg.Licenses = []string{license}
+	} else if err := json.Unmarshal(npm.License, &licenseObject); err == nil && len(licenseObject.Type) > 0 {
+		pkg.Licenses = []string{licenseObject.Type}
+	}
+	for _, license := range npm.Licenses {
+		if len(license.Type) > 0 {
+			pkg.Licenses = append(pkg.Licenses, license.Type)
+		}
+	}
+	return &pkg, nil
+}
+
+func parsePythonMetadata(reader io.Reader, location string) *Package {
+	pkg := Package{Type: "pypi", Location: location}
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	// the header ends with the first empty line, the description follows
+	for scanner.Scan() && len(scanner.Text()) > 0 {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "Name:"):
+			pkg.Name = strings.TrimSpace(strings.TrimPrefix(line, "Name:"))
+		case strings.HasPrefix(line, "Version:"):
+			pkg.Version = strings.TrimSpace(strings.TrimPrefix(line, "Version:"))
+		case strings.HasPrefix(line, "License:"):
+			if license := strings.TrimSpace(strings.TrimPrefix(line, "License:")); len(license) > 0 && license != "UNKNOWN" {
+				pkg.Licenses = []string{license}
+			}
+		}
+	}
+	if len(pkg.Name) == 0 || len(pkg.Version) == 0 {
+		return nil
+	}
+	return &pkg
+}
+
+// parseJavaArchive returns the Maven packages of a jar file including the ones of nested jar files, e.g. of Spring Boot applications
+func parseJavaArchive(content []byte, location string, depth int) []Package {
+	archive, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		// invalid archives are not considered as packages
+		return nil
+	}
+	packages := []Package{}
+	for _, file := range archive.File {
+		switch {
+		case strings.HasPrefix(file.Name, "META-INF/maven/") && path.Base(file.Name) == "pom.properties":
+			if pkg := parsePomProperties(file, location); pkg != nil {
+				packages = append(packages, *pkg)
+			}
+		case isJavaArchive(file.Name) && depth < 2 && file.UncompressedSize64 <= maxArchiveSize:
+			nested, err := readZipFile(file)
+			if err != nil {
+				continue
+			}
+			packages = append(packages, parseJavaArchive(nested, location+"!/"+file.Name, depth+1)...)
+		}
+	}
+	return packages
+}
+
+func readZipFile(file *zip.File) ([]byte, error) {
+	reader, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return ioutil.ReadAll(reader)
+}
+
+func parsePomProperties(file *zip.File, location string) *Package {
+	content, err := readZipFile(file)
+	if err != nil {
+		return nil
+	}
+	properties := map[string]string{}
+	for _, line := range strings.Split(string(content), "\n") {
+		if index := strings.Index(line, "="); index > 0 && !strings.HasPrefix(line, "#") {
+			properties[strings.TrimSpace(line[:index])] = strings.TrimSpace(line[index+1:])
+		}
+	}
+	if len(properties["groupId"]) == 0 || len(properties["artifactId"]) == 0 || len(properties["version"]) == 0 {
+		return nil
+	}
+	return &Package{Type: "maven", Namespace: properties["groupId"], Name: properties["artifactId"], Version: properties["version"], Location: location}
+}
+
+// uniquePackages removes duplicates and sorts the packages by type, namespace, name and version
+func uniquePackages(packages []Package) []Package {
+	unique := []Package{}
+	found := map[string]bool{}
+	for _, pkg := range packages {
+		key := pkg.PURL() + "|" + pkg.Location
+		if !found[key] {
+			found[key] = true
+			unique = append(unique, pkg)
+		}
+	}
+	sort.SliceStable(unique, func(i, j int) bool {
+		a, b := unique[i], unique[j]
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		if a.Version != b.Version {
+			return a.Version < b.Version
+		}
+		return a.Location < b.Location
+	})
+	return unique
+}
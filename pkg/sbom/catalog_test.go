@@ -0,0 +1,174 @@
+package sbom
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func tarFileSystem(t *testing.T, files map[string]string) *bytes.Buffer {
+	var buffer bytes.Buffer
+	writer := tar.NewWriter(&buffer)
+	for name, content := range files {
+		err := writer.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg})
+		if assert.NoError(t, err) {
+			_, err = writer.Write([]byte(content))
+			assert.NoError(t, err)
+		}
+	}
+	assert.NoError(t, writer.Close())
+	return &buffer
+}
+
+func zipArchive(t *testing.T, files map[string]string) string {
+	var buffer bytes.Buffer
+	writer := zip.NewWriter(&buffer)
+	for name, content := range files {
+		file, err := writer.Create(name)
+		if assert.NoError(t, err) {
+			_, err = file.Write([]byte(content))
+			assert.NoError(t, err)
+		}
+	}
+	assert.NoError(t, writer.Close())
+	return buffer.String()
+}
+
+func TestCatalog(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Debian packages", func(t *testing.T) {
+		t.Parallel()
+		filesystem := tarFileSystem(t, map[string]string{
+			"etc/os-release": "PRETTY_NAME=\"Debian GNU/Linux 10 (buster)\"\nID=debian\nVERSION_ID=\"10\"\n",
+			"var/lib/dpkg/status": `Package: openssl
+Status: install ok installed
+Architecture: amd64
+Version: 1.1.1d-0+deb10u6
+Description: Secure Sockets Layer toolkit
+ This package contains the openssl binary.
+
+Package: removed
+Status: deinstall ok config-files
+Version: 1.0
+
+Package: libc6
+Status: install ok installed
+Architecture: amd64
+Version: 2.28-10
+`,
+			"usr/share/doc/openssl/copyright": "Files: *\nLicense: OpenSSL\n\nFiles: debian/*\nLicense: OpenSSL\n",
+		})
+
+		inventory, err := Catalog(filesystem)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, OS{ID: "debian", Version: "10", Name: "Debian GNU/Linux 10 (buster)"}, inventory.OS)
+			assert.Equal(t, []Package{
+				{Type: "deb", Namespace: "debian", Name: "libc6", Version: "2.28-10", Arch: "amd64", Location: "var/lib/dpkg/status"},
+				{Type: "deb", Namespace: "debian", Name: "openssl", Version: "1.1.1d-0+deb10u6", Arch: "amd64", Licenses: []string{"OpenSSL"}, Location: "var/lib/dpkg/status"},
+			}, inventory.Packages)
+			assert.Nil(t, inventory.RpmDatabase)
+		}
+	})
+
+	t.Run("distroless packages", func(t *testing.T) {
+		t.Parallel()
+		filesystem := tarFileSystem(t, map[string]string{
+			"./var/lib/dpkg/status.d/tzdata": "Package: tzdata\nVersion: 2021a-0+deb10u1\nArchitecture: all\n",
+		})
+
+		inventory, err := Catalog(filesystem)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, []Package{{Type: "deb", Name: "tzdata", Version: "2021a-0+deb10u1", Arch: "all", Location: "var/lib/dpkg/status.d/tzdata"}}, inventory.Packages)
+		}
+	})
+
+	t.Run("Alpine packages", func(t *testing.T) {
+		t.Parallel()
+		filesystem := tarFileSystem(t, map[string]string{
+			"usr/lib/os-release":   "ID=alpine\nVERSION_ID=3.13.5\n",
+			"lib/apk/db/installed": "C:Q1abc=\nP:musl\nV:1.2.2-r0\nA:x86_64\nL:MIT\n\nP:busybox\nV:1.32.1-r6\nA:x86_64\nL:GPL-2.0-only\n",
+		})
+
+		inventory, err := Catalog(filesystem)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, []Package{
+				{Type: "apk", Namespace: "alpine", Name: "busybox", Version: "1.32.1-r6", Arch: "x86_64", Licenses: []string{"GPL-2.0-only"}, Location: "lib/apk/db/installed"},
+				{Type: "apk", Namespace: "alpine", Name: "musl", Version: "1.2.2-r0", Arch: "x86_64", Licenses: []string{"MIT"}, Location: "lib/apk/db/installed"},
+			}, inventory.Packages)
+		}
+	})
+
+	t.Run("rpm database", func(t *testing.T) {
+		t.Parallel()
+		filesystem := tarFileSystem(t, map[string]string{
+			"etc/os-release":       "ID=\"rhel\"\n",
+			"var/lib/rpm/Packages": "db",
+			"var/lib/rpm/Other":    "other",
+		})
+
+		inventory, err := Catalog(filesystem)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, map[string][]byte{"Packages": []byte("db")}, inventory.RpmDatabase)
+			err = inventory.AddRpmPackages("openssl-libs\t1\t1.1.1g-15.el8_3\tx86_64\tOpenSSL and ASL 2.0\ngpg-pubkey\t(none)\td4082792-5b32db75\t(none)\tpubkey\n")
+			if assert.NoError(t, err) {
+				assert.Equal(t, []Package{
+					{Type: "rpm", Namespace: "rhel", Name: "gpg-pubkey", Version: "d4082792-5b32db75", Licenses: []string{"pubkey"}, Location: "var/lib/rpm"},
+					{Type: "rpm", Namespace: "rhel", Name: "openssl-libs", Version: "1:1.1.1g-15.el8_3", Arch: "x86_64", Licenses: []string{"OpenSSL and ASL 2.0"}, Location: "var/lib/rpm"},
+				}, inventory.Packages)
+			}
+			assert.EqualError(t, inventory.AddRpmPackages("invalid\n"), "unexpected rpm query result 'invalid'")
+		}
+	})
+
+	t.Run("language packages", func(t *testing.T) {
+		t.Parallel()
+		filesystem := tarFileSystem(t, map[string]string{
+			"app/node_modules/express/package.json":               `{"name": "express", "version": "4.17.1", "license": "MIT"}`,
+			"app/node_modules/@babel/core/package.json":           `{"name": "@babel/core", "version": "7.14.0", "license": {"type": "MIT"}}`,
+			"app/node_modules/express/test/fixtures/package.json": `{"name": "fixture"}`,
+			"app/package.json": `{"name": "app", "version": "1.0.0"}`,
+			"usr/lib/python3.8/site-packages/requests-2.25.1.dist-info/METADATA": "Metadata-Version: 2.1\nName: requests\nVersion: 2.25.1\nLicense: Apache 2.0\n\nLicense: not a header\n",
+			"app/lib/app.jar": zipArchive(t, map[string]string{
+				"META-INF/maven/com.example/app/pom.properties": "#Generated by Maven\ngroupId=com.example\nartifactId=app\nversion=1.0.0\n",
+				"BOOT-INF/lib/commons-text-1.9.jar": zipArchive(t, map[string]string{
+					"META-INF/maven/org.apache.commons/commons-text/pom.properties": "groupId=org.apache.commons\nartifactId=commons-text\nversion=1.9\n",
+				}),
+			}),
+			"app/lib/invalid.jar": "no zip",
+		})
+
+		inventory, err := Catalog(filesystem)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, []Package{
+				{Type: "maven", Namespace: "com.example", Name: "app", Version: "1.0.0", Location: "app/lib/app.jar"},
+				{Type: "maven", Namespace: "org.apache.commons", Name: "commons-text", Version: "1.9", Location: "app/lib/app.jar!/BOOT-INF/lib/commons-text-1.9.jar"},
+				{Type: "npm", Name: "express", Version: "4.17.1", Licenses: []string{"MIT"}, Location: "app/node_modules/express/package.json"},
+				{Type: "npm", Namespace: "@babel", Name: "core", Version: "7.14.0", Licenses: []string{"MIT"}, Location: "app/node_modules/@babel/core/package.json"},
+				{Type: "pypi", Name: "requests", Version: "2.25.1", Licenses: []string{"Apache 2.0"}, Location: "usr/lib/python3.8/site-packages/requests-2.25.1.dist-info/METADATA"},
+			}, inventory.Packages)
+		}
+	})
+
+	t.Run("error - invalid file system", func(t *testing.T) {
+		t.Parallel()
+		_, err := Catalog(bytes.NewBufferString("no tar"))
+
+		assert.Contains(t, err.Error(), "failed to read image file system")
+	})
+}
+
+func TestPURL(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "pkg:deb/debian/openssl@1.1.1d-0%2Bdeb10u6?arch=amd64", Package{Type: "deb", Namespace: "debian", Name: "openssl", Version: "1.1.1d-0+deb10u6", Arch: "amd64"}.PURL())
+	assert.Equal(t, "pkg:npm/%40babel/core@7.14.0", Package{Type: "npm", Namespace: "@babel", Name: "core", Version: "7.14.0"}.PURL())
+	assert.Equal(t, "pkg:pypi/requests", Package{Type: "pypi", Name: "requests"}.PURL())
+}
@@ -0,0 +1,102 @@
+package sbom
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// SBOM is the software bill of materials of a container image
+type SBOM struct {
+	ImageName   string
+	ImageDigest string
+	OS          OS
+	Packages    []Package
+	Created     time.Time
+	// UUID identifies the document, it is used as serial number of CycloneDX documents and within the namespace of SPDX documents
+	UUID string
+}
+
+const toolName = "piper"
+const toolVendor = "SAP"
+
+type cycloneDXBom struct {
+	BOMFormat    string               `json:"bomFormat"`
+	SpecVersion  string               `json:"specVersion"`
+	SerialNumber string               `json:"serialNumber"`
+	Version      int                  `json:"version"`
+	Metadata     cycloneDXMetadata    `json:"metadata"`
+	Components   []cycloneDXComponent `json:"components"`
+}
+
+type cycloneDXMetadata struct {
+	Timestamp string             `json:"timestamp"`
+	Tools     []cycloneDXTool    `json:"tools"`
+	Component cycloneDXComponent `json:"component"`
+}
+
+type cycloneDXTool struct {
+	Vendor string `json:"vendor"`
+	Name   string `json:"name"`
+}
+
+type cycloneDXComponent struct {
+	BOMRef      string             `json:"bom-ref,omitempty"`
+	Type        string             `json:"type"`
+	Group       string             `json:"group,omitempty"`
+	Name        string             `json:"name"`
+	Version     string             `json:"version,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Licenses    []cycloneDXLicense `json:"licenses,omitempty"`
+	PURL        string             `json:"purl,omitempty"`
+}
+
+type cycloneDXLicense struct {
+	License cycloneDXLicenseName `json:"license"`
+}
+
+type cycloneDXLicenseName struct {
+	Name string `json:"name"`
+}
+
+// CycloneDX returns the SBOM as CycloneDX 1.2 document in JSON format
+func (s *SBOM) CycloneDX() ([]byte, error) {
+	bom := cycloneDXBom{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  "1.2",
+		SerialNumber: "urn:uuid:" + s.UUID,
+		Version:      1,
+		Metadata: cycloneDXMetadata{
+			Timestamp: s.Created.UTC().Format(time.RFC3339),
+			Tools:     []cycloneDXTool{{Vendor: toolVendor, Name: toolName}},
+			Component: cycloneDXComponent{Type: "container", Name: s.ImageName, Version: s.ImageDigest, Description: s.OS.Name},
+		},
+		Components: []cycloneDXComponent{},
+	}
+	occurrences := map[string]int{}
+	for _, pkg := range s.Packages {
+		occurrences[pkg.PURL()]++
+	}
+	for _, pkg := range s.Packages {
+		// the same package may be found in several locations, but the bom-ref has to be unique
+		bomRef := pkg.PURL()
+		if occurrences[bomRef] > 1 {
+			bomRef += "#" + pkg.Location
+		}
+		component := cycloneDXComponent{
+			BOMRef:  bomRef,
+			Type:    "library",
+			Name:    pkg.Name,
+			Version: pkg.Version,
+			PURL:    pkg.PURL(),
+		}
+		// the namespace of OS packages is the distribution, which is no group
+		if pkg.Type == "maven" || pkg.Type == "npm" {
+			component.Group = pkg.Namespace
+		}
+		for _, license := range pkg.Licenses {
+			component.Licenses = append(component.Licenses, cycloneDXLicense{License: cycloneDXLicenseName{Name: license}})
+		}
+		bom.Components = append(bom.Components, component)
+	}
+	return json.MarshalIndent(bom, "", "  ")
+}
@@ -0,0 +1,85 @@
+package sbom
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testSBOM() *SBOM {
+	return &SBOM{
+		ImageName:   "my.registry/my-service:1.2.3",
+		ImageDigest: "sha256:abc",
+		OS:          OS{ID: "debian", Version: "10", Name: "Debian GNU/Linux 10 (buster)"},
+		Packages: []Package{
+			{Type: "deb", Namespace: "debian", Name: "openssl", Version: "1.1.1d-0+deb10u6", Arch: "amd64", Licenses: []string{"OpenSSL"}, Location: "var/lib/dpkg/status"},
+			{Type: "maven", Namespace: "org.apache.commons", Name: "commons-text", Version: "1.9", Licenses: []string{"Apache License, Version 2.0"}, Location: "app/app.jar"},
+		},
+		Created: time.Date(2021, 5, 4, 10, 0, 0, 0, time.UTC),
+		UUID:    "3e671687-395b-41f5-a30f-a58921a69b79",
+	}
+}
+
+func TestCycloneDX(t *testing.T) {
+	t.Parallel()
+
+	content, err := testSBOM().CycloneDX()
+
+	if assert.NoError(t, err) {
+		bom := map[string]interface{}{}
+		assert.NoError(t, json.Unmarshal(content, &bom))
+		assert.Equal(t, "CycloneDX", bom["bomFormat"])
+		assert.Equal(t, "1.2", bom["specVersion"])
+		assert.Equal(t, "urn:uuid:3e671687-395b-41f5-a30f-a58921a69b79", bom["serialNumber"])
+		assert.Equal(t, map[string]interface{}{
+			"timestamp": "2021-05-04T10:00:00Z",
+			"tools":     []interface{}{map[string]interface{}{"vendor": "SAP", "name": "piper"}},
+			"component": map[string]interface{}{"type": "container", "name": "my.registry/my-service:1.2.3", "version": "sha256:abc", "description": "Debian GNU/Linux 10 (buster)"},
+		}, bom["metadata"])
+		assert.Equal(t, []interface{}{
+			map[string]interface{}{
+				"bom-ref":  "pkg:deb/debian/openssl@1.1.1d-0%2Bdeb10u6?arch=amd64",
+				"type":     "library",
+				"name":     "openssl",
+				"version":  "1.1.1d-0+deb10u6",
+				"licenses": []interface{}{map[string]interface{}{"license": map[string]interface{}{"name": "OpenSSL"}}},
+				"purl":     "pkg:deb/debian/openssl@1.1.1d-0%2Bdeb10u6?arch=amd64",
+			},
+			map[string]interface{}{
+				"bom-ref":  "pkg:maven/org.apache.commons/commons-text@1.9",
+				"type":     "library",
+				"group":    "org.apache.commons",
+				"name":     "commons-text",
+				"version":  "1.9",
+				"licenses": []interface{}{map[string]interface{}{"license": map[string]interface{}{"name": "Apache License, Version 2.0"}}},
+				"purl":     "pkg:maven/org.apache.commons/commons-text@1.9",
+			},
+		}, bom["components"])
+	}
+}
+
+func TestCycloneDXDuplicatePURL(t *testing.T) {
+	t.Parallel()
+	sbom := testSBOM()
+	sbom.Packages = append(sbom.Packages, Package{Type: "maven", Namespace: "org.apache.commons", Name: "commons-text", Version: "1.9", Location: "opt/tool/tool.jar"})
+
+	content, err := sbom.CycloneDX()
+
+	if assert.NoError(t, err) {
+		bom := struct {
+			Components []struct {
+				BOMRef string `json:"bom-ref"`
+				PURL   string `json:"purl"`
+			} `json:"components"`
+		}{}
+		assert.NoError(t, json.Unmarshal(content, &bom))
+		if assert.Equal(t, 3, len(bom.Components)) {
+			assert.Equal(t, "pkg:deb/debian/openssl@1.1.1d-0%2Bdeb10u6?arch=amd64", bom.Components[0].BOMRef)
+			assert.Equal(t, "pkg:maven/org.apache.commons/commons-text@1.9#app/app.jar", bom.Components[1].BOMRef)
+			assert.Equal(t, "pkg:maven/org.apache.commons/commons-text@1.9#opt/tool/tool.jar", bom.Components[2].BOMRef)
+			assert.Equal(t, bom.Components[1].PURL, bom.Components[2].PURL)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package sbom
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const spdxNoAssertion = "NOASSERTION"
+
+// licenses which are no valid SPDX license expressions are only added as comment
+var spdxLicenseExpression = regexp.MustCompile(`^[A-Za-z0-9.+-]+( (AND|OR|WITH) [A-Za-z0-9.+-]+)*$`)
+
+type spdxDocument struct {
+	SPDXID            string             `json:"SPDXID"`
+	SPDXVersion       string             `json:"spdxVersion"`
+	CreationInfo      spdxCreationInfo   `json:"creationInfo"`
+	Name              string             `json:"name"`
+	DataLicense       string             `json:"dataLicense"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	Packages          []spdxPackage      `json:"packages"`
+	Relationships     []spdxRelationship `json:"relationships"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	SPDXID           string            `json:"SPDXID"`
+	Name             string            `json:"name"`
+	VersionInfo      string            `json:"versionInfo,omitempty"`
+	DownloadLocation string            `json:"downloadLocation"`
+	FilesAnalyzed    bool              `json:"filesAnalyzed"`
+	LicenseConcluded string            `json:"licenseConcluded"`
+	LicenseDeclared  string            `json:"licenseDeclared"`
+	LicenseComments  string            `json:"licenseComments,omitempty"`
+	CopyrightText    string            `json:"copyrightText"`
+	SourceInfo       string            `json:"sourceInfo,omitempty"`
+	ExternalRefs     []spdxExternalRef `json:"externalRefs,omitempty"`
+}
+
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type spdxRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+// SPDX returns the SBOM as SPDX 2.2 document in JSON format
+func (s *SBOM) SPDX() ([]byte, error) {
+	const imageID = "SPDXRef-Image"
+	document := spdxDocument{
+		SPDXID:      "SPDXRef-DOCUMENT",
+		SPDXVersion: "SPDX-2.2",
+		CreationInfo: spdxCreationInfo{
+			Created:  s.Created.UTC().Format(time.RFC3339),
+			Creators: []string{fmt.Sprintf("Organization: %v", toolVendor), fmt.Sprintf("Tool: %v", toolName)},
+		},
+		Name:              s.ImageName,
+		DataLicense:       "CC0-1.0",
+		DocumentNamespace: fmt.Sprintf("https://spdx.org/spdxdocs/%v-%v", spdxNamespaceName(s.ImageName), s.UUID),
+		Packages: []spdxPackage{{
+			SPDXID:           imageID,
+			Name:             s.ImageName,
+			VersionInfo:      s.ImageDigest,
+			DownloadLocation: spdxNoAssertion,
+			LicenseConcluded: spdxNoAssertion,
+			LicenseDeclared:  spdxNoAssertion,
+			CopyrightText:    spdxNoAssertion,
+		}},
+		Relationships: []spdxRelationship{{SPDXElementID: "SPDXRef-DOCUMENT", RelationshipType: "DESCRIBES", RelatedSPDXElement: imageID}},
+	}
+	for i, pkg := range s.Packages {
+		id := fmt.Sprintf("SPDXRef-Package-%v", i+1)
+		name := pkg.Name
+		if len(pkg.Namespace) > 0 && (pkg.Type == "maven" || pkg.Type == "npm") {
+			name = pkg.Namespace + "/" + pkg.Name
+			if pkg.Type == "maven" {
+				name = pkg.Namespace + ":" + pkg.Name
+			}
+		}
+		spdxPkg := spdxPackage{
+			SPDXID:           id,
+			Name:             name,
+			VersionInfo:      pkg.Version,
+			DownloadLocation: spdxNoAssertion,
+			LicenseConcluded: spdxNoAssertion,
+			LicenseDeclared:  spdxLicense(pkg.Licenses),
+			CopyrightText:    spdxNoAssertion,
+			SourceInfo:       fmt.Sprintf("found in %v", pkg.Location),
+			ExternalRefs:     []spdxExternalRef{{ReferenceCategory: "PACKAGE-MANAGER", ReferenceType: "purl", ReferenceLocator: pkg.PURL()}},
+		}
+		if spdxPkg.LicenseDeclared == spdxNoAssertion && len(pkg.Licenses) > 0 {
+			spdxPkg.LicenseComments = strings.Join(pkg.Licenses, ", ")
+		}
+		document.Packages = append(document.Packages, spdxPkg)
+		document.Relationships = append(document.Relationships, spdxRelationship{SPDXElementID: imageID, RelationshipType: "CONTAINS", RelatedSPDXElement: id})
+	}
+	return json.MarshalIndent(document, "", "  ")
+}
+
+func spdxLicense(licenses []string) string {
+	if len(licenses) == 0 {
+		return spdxNoAssertion
+	}
+	for _, license := range licenses {
+		if !spdxLicenseExpression.MatchString(license) {
+			return spdxNoAssertion
+		}
+	}
+	if len(licenses) == 1 {
+		return licenses[0]
+	}
+	return "(" + strings.Join(licenses, ") AND (") + ")"
+}
+
+// spdxNamespaceName replaces the characters of an image name which are not allowed within URIs
+func spdxNamespaceName(imageName string) string {
+	return strings.NewReplacer("/", "-", ":", "-", "@", "-").Replace(imageName)
+}
@@ -0,0 +1,53 @@
+package sbom
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSPDX(t *testing.T) {
+	t.Parallel()
+
+	content, err := testSBOM().SPDX()
+
+	if assert.NoError(t, err) {
+		document := spdxDocument{}
+		assert.NoError(t, json.Unmarshal(content, &document))
+		assert.Equal(t, "SPDX-2.2", document.SPDXVersion)
+		assert.Equal(t, "https://spdx.org/spdxdocs/my.registry-my-service-1.2.3-3e671687-395b-41f5-a30f-a58921a69b79", document.DocumentNamespace)
+		assert.Equal(t, spdxCreationInfo{Created: "2021-05-04T10:00:00Z", Creators: []string{"Organization: SAP", "Tool: piper"}}, document.CreationInfo)
+		if assert.Len(t, document.Packages, 3) {
+			assert.Equal(t, "SPDXRef-Image", document.Packages[0].SPDXID)
+			assert.Equal(t, "sha256:abc", document.Packages[0].VersionInfo)
+			assert.Equal(t, spdxPackage{
+				SPDXID:           "SPDXRef-Package-1",
+				Name:             "openssl",
+				VersionInfo:      "1.1.1d-0+deb10u6",
+				DownloadLocation: "NOASSERTION",
+				LicenseConcluded: "NOASSERTION",
+				LicenseDeclared:  "OpenSSL",
+				CopyrightText:    "NOASSERTION",
+				SourceInfo:       "found in var/lib/dpkg/status",
+				ExternalRefs:     []spdxExternalRef{{ReferenceCategory: "PACKAGE-MANAGER", ReferenceType: "purl", ReferenceLocator: "pkg:deb/debian/openssl@1.1.1d-0%2Bdeb10u6?arch=amd64"}},
+			}, document.Packages[1])
+			assert.Equal(t, "org.apache.commons:commons-text", document.Packages[2].Name)
+			assert.Equal(t, "NOASSERTION", document.Packages[2].LicenseDeclared)
+			assert.Equal(t, "Apache License, Version 2.0", document.Packages[2].LicenseComments)
+		}
+		assert.Equal(t, []spdxRelationship{
+			{SPDXElementID: "SPDXRef-DOCUMENT", RelationshipType: "DESCRIBES", RelatedSPDXElement: "SPDXRef-Image"},
+			{SPDXElementID: "SPDXRef-Image", RelationshipType: "CONTAINS", RelatedSPDXElement: "SPDXRef-Package-1"},
+			{SPDXElementID: "SPDXRef-Image", RelationshipType: "CONTAINS", RelatedSPDXElement: "SPDXRef-Package-2"},
+		}, document.Relationships)
+	}
+}
+
+func TestSPDXLicense(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "NOASSERTION", spdxLicense(nil))
+	assert.Equal(t, "MIT", spdxLicense([]string{"MIT"}))
+	assert.Equal(t, "(MIT) AND (GPL-2.0-or-later OR BSD-3-Clause)", spdxLicense([]string{"MIT", "GPL-2.0-or-later OR BSD-3-Clause"}))
+	assert.Equal(t, "NOASSERTION", spdxLicense([]string{"MIT", "Public Domain"}))
+}
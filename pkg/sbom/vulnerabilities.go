@@ -0,0 +1,232 @@
+package sbom
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+	"golang.org/x/mod/semver"
+)
+
+// Severities lists the supported severities of vulnerabilities in ascending order
+var Severities = []string{"low", "medium", "high", "critical"}
+
+// Vulnerability is an entry of a vulnerability database
+type Vulnerability struct {
+	ID          string `json:"id"`
+	Severity    string `json:"severity"`
+	Description string `json:"description,omitempty"`
+	// Type, Namespace and Name identify the affected package like in its package URL, the namespace is optional
+	Type      string `json:"type"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	// Versions lists affected versions explicitly, otherwise all versions from IntroducedVersion up to FixedVersion (excluded) are affected
+	Versions          []string `json:"versions,omitempty"`
+	IntroducedVersion string   `json:"introducedVersion,omitempty"`
+	FixedVersion      string   `json:"fixedVersion,omitempty"`
+}
+
+// VulnerabilityDatabase is a list of known vulnerabilities
+type VulnerabilityDatabase struct {
+	Vulnerabilities []Vulnerability `json:"vulnerabilities"`
+}
+
+// Finding is a vulnerability affecting a package of an image
+type Finding struct {
+	ID           string `json:"id"`
+	Severity     string `json:"severity"`
+	Description  string `json:"description,omitempty"`
+	PURL         string `json:"purl"`
+	Location     string `json:"location"`
+	FixedVersion string `json:"fixedVersion,omitempty"`
+}
+
+// ReadVulnerabilityDatabase parses a vulnerability database in JSON format
+func ReadVulnerabilityDatabase(content []byte) (*VulnerabilityDatabase, error) {
+	database := &VulnerabilityDatabase{}
+	if err := json.Unmarshal(content, database); err != nil {
+		return nil, errors.Wrap(err, "failed to parse vulnerability database")
+	}
+	for i, vulnerability := range database.Vulnerabilities {
+		if len(vulnerability.ID) == 0 || len(vulnerability.Type) == 0 || len(vulnerability.Name) == 0 {
+			return nil, fmt.Errorf("entry %v of vulnerability database requires id, type and name", i+1)
+		}
+		if len(vulnerability.Versions) == 0 && len(vulnerability.FixedVersion) == 0 && len(vulnerability.IntroducedVersion) == 0 {
+			return nil, fmt.Errorf("vulnerability %v requires affected versions, introducedVersion or fixedVersion", vulnerability.ID)
+		}
+	}
+	return database, nil
+}
+
+// Match returns the findings for the given packages
+func (d *VulnerabilityDatabase) Match(packages []Package) []Finding {
+	findings := []Finding{}
+	for _, pkg := range packages {
+		for _, vulnerability := range d.Vulnerabilities {
+			if vulnerability.affects(pkg) {
+				findings = append(findings, Finding{
+					ID:           vulnerability.ID,
+					Severity:     strings.ToLower(vulnerability.Severity),
+					Description:  vulnerability.Description,
+					PURL:         pkg.PURL(),
+					Location:     pkg.Location,
+					FixedVersion: vulnerability.FixedVersion,
+				})
+			}
+		}
+	}
+	return findings
+}
+
+func (v Vulnerability) affects(pkg Package) bool {
+	if !strings.EqualFold(v.Type, pkg.Type) || v.Name != pkg.Name {
+		return false
+	}
+	if len(v.Namespace) > 0 && v.Namespace != pkg.Namespace {
+		return false
+	}
+	if len(v.Versions) > 0 {
+		for _, version := range v.Versions {
+			if version == pkg.Version {
+				return true
+			}
+		}
+		return false
+	}
+	if len(v.IntroducedVersion) > 0 && CompareVersions(pkg.Type, pkg.Version, v.IntroducedVersion) < 0 {
+		return false
+	}
+	return len(v.FixedVersion) == 0 || CompareVersions(pkg.Type, pkg.Version, v.FixedVersion) < 0
+}
+
+// SeverityRank returns the position of the severity in Severities starting with 1, unknown severities are ranked 0
+func SeverityRank(severity string) int {
+	for i, s := range Severities {
+		if strings.EqualFold(s, severity) {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// semanticVersionTypes contains the package types whose versions follow https://semver.org
+var semanticVersionTypes = []string{"npm", "golang", "go", "cargo", "composer"}
+
+// CompareVersions compares two versions of a package of the given type.
+// Semantic versions (e.g. of npm packages) are compared according to https://semver.org, i.e. pre-releases sort before the release.
+// All other versions are compared using the algorithm of dpkg, which works reasonably for the versions of other package types as well.
+// The result is negative if a < b, zero if a == b and positive if a > b.
+func CompareVersions(packageType, a, b string) int {
+	for _, semanticVersionType := range semanticVersionTypes {
+		if strings.EqualFold(packageType, semanticVersionType) {
+			if result, ok := compareSemanticVersions(a, b); ok {
+				return result
+			}
+			break
+		}
+	}
+	return compareDpkgVersions(a, b)
+}
+
+// compareSemanticVersions compares two semantic versions, in case one of them is invalid false is returned
+func compareSemanticVersions(a, b string) (int, bool) {
+	a, b = "v"+strings.TrimPrefix(a, "v"), "v"+strings.TrimPrefix(b, "v")
+	if !semver.IsValid(a) || !semver.IsValid(b) {
+		return 0, false
+	}
+	return semver.Compare(a, b), true
+}
+
+func compareDpkgVersions(a, b string) int {
+	epochA, restA := splitEpoch(a)
+	epochB, restB := splitEpoch(b)
+	if result := compareVersionPart(epochA, epochB); result != 0 {
+		return result
+	}
+	upstreamA, revisionA := splitRevision(restA)
+	upstreamB, revisionB := splitRevision(restB)
+	if result := compareVersionPart(upstreamA, upstreamB); result != 0 {
+		return result
+	}
+	return compareVersionPart(revisionA, revisionB)
+}
+
+func splitEpoch(version string) (string, string) {
+	if index := strings.Index(version, ":"); index > 0 && strings.IndexFunc(version[:index], func(r rune) bool { return !unicode.IsDigit(r) }) < 0 {
+		return version[:index], version[index+1:]
+	}
+	return "0", version
+}
+
+func splitRevision(version string) (string, string) {
+	if index := strings.LastIndex(version, "-"); index >= 0 {
+		return version[:index], version[index+1:]
+	}
+	return version, ""
+}
+
+// compareVersionPart compares alternating non-digit and digit sequences, non-digits are compared lexically
+// with letters sorting before other characters and '~' before everything (even the end of the part)
+func compareVersionPart(a, b string) int {
+	for len(a) > 0 || len(b) > 0 {
+		var nonDigitA, nonDigitB string
+		nonDigitA, a = splitPrefix(a, func(r rune) bool { return !unicode.IsDigit(r) })
+		nonDigitB, b = splitPrefix(b, func(r rune) bool { return !unicode.IsDigit(r) })
+		if result := compareNonDigits(nonDigitA, nonDigitB); result != 0 {
+			return result
+		}
+		var digitA, digitB string
+		digitA, a = splitPrefix(a, unicode.IsDigit)
+		digitB, b = splitPrefix(b, unicode.IsDigit)
+		if result := compareDigits(digitA, digitB); result != 0 {
+			return result
+		}
+	}
+	return 0
+}
+
+func splitPrefix(s string, matches func(r rune) bool) (string, string) {
+	index := strings.IndexFunc(s, func(r rune) bool { return !matches(r) })
+	if index < 0 {
+		return s, ""
+	}
+	return s[:index], s[index:]
+}
+
+func compareNonDigits(a, b string) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var orderA, orderB int
+		if i < len(a) {
+			orderA = characterOrder(a[i])
+		}
+		if i < len(b) {
+			orderB = characterOrder(b[i])
+		}
+		if orderA != orderB {
+			return orderA - orderB
+		}
+	}
+	return 0
+}
+
+func characterOrder(c byte) int {
+	switch {
+	case c == '~':
+		return -1
+	case unicode.IsLetter(rune(c)):
+		return int(c)
+	default:
+		return int(c) + 256
+	}
+}
+
+func compareDigits(a, b string) int {
+	a = strings.TrimLeft(a, "0")
+	b = strings.TrimLeft(b, "0")
+	if len(a) != len(b) {
+		return len(a) - len(b)
+	}
+	return strings.Compare(a, b)
+}
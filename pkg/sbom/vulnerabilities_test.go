@@ -0,0 +1,114 @@
+package sbom
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadVulnerabilityDatabase(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		database, err := ReadVulnerabilityDatabase([]byte(`{"vulnerabilities": [{"id": "CVE-2021-3449", "severity": "medium", "type": "deb", "name": "openssl", "fixedVersion": "1.1.1d-0+deb10u6"}]}`))
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, []Vulnerability{{ID: "CVE-2021-3449", Severity: "medium", Type: "deb", Name: "openssl", FixedVersion: "1.1.1d-0+deb10u6"}}, database.Vulnerabilities)
+		}
+	})
+
+	t.Run("error - invalid JSON", func(t *testing.T) {
+		t.Parallel()
+		_, err := ReadVulnerabilityDatabase([]byte(`[`))
+
+		assert.Contains(t, err.Error(), "failed to parse vulnerability database")
+	})
+
+	t.Run("error - missing name", func(t *testing.T) {
+		t.Parallel()
+		_, err := ReadVulnerabilityDatabase([]byte(`{"vulnerabilities": [{"id": "CVE-2021-3449", "type": "deb", "fixedVersion": "1.0"}]}`))
+
+		assert.EqualError(t, err, "entry 1 of vulnerability database requires id, type and name")
+	})
+
+	t.Run("error - missing versions", func(t *testing.T) {
+		t.Parallel()
+		_, err := ReadVulnerabilityDatabase([]byte(`{"vulnerabilities": [{"id": "CVE-2021-3449", "type": "deb", "name": "openssl"}]}`))
+
+		assert.EqualError(t, err, "vulnerability CVE-2021-3449 requires affected versions, introducedVersion or fixedVersion")
+	})
+}
+
+func TestMatch(t *testing.T) {
+	t.Parallel()
+	database := VulnerabilityDatabase{Vulnerabilities: []Vulnerability{
+		{ID: "CVE-1", Severity: "HIGH", Type: "deb", Name: "openssl", FixedVersion: "1.1.1d-0+deb10u6"},
+		{ID: "CVE-2", Severity: "low", Type: "deb", Namespace: "ubuntu", Name: "openssl", FixedVersion: "2.0"},
+		{ID: "CVE-3", Severity: "critical", Type: "maven", Namespace: "org.apache.logging.log4j", Name: "log4j-core", IntroducedVersion: "2.0-beta9", FixedVersion: "2.15.0"},
+		{ID: "CVE-4", Severity: "medium", Type: "npm", Name: "lodash", Versions: []string{"4.17.20"}},
+		{ID: "CVE-5", Severity: "high", Type: "npm", Name: "minimist", FixedVersion: "1.2.6"},
+	}}
+	packages := []Package{
+		{Type: "deb", Namespace: "debian", Name: "openssl", Version: "1.1.1d-0+deb10u5", Location: "var/lib/dpkg/status"},
+		{Type: "maven", Namespace: "org.apache.logging.log4j", Name: "log4j-core", Version: "2.14.1", Location: "app.jar"},
+		{Type: "maven", Namespace: "org.apache.logging.log4j", Name: "log4j-core", Version: "1.2", Location: "old.jar"},
+		{Type: "npm", Name: "lodash", Version: "4.17.21", Location: "node_modules/lodash/package.json"},
+		{Type: "npm", Name: "minimist", Version: "1.2.6-rc1", Location: "node_modules/minimist/package.json"},
+	}
+
+	findings := database.Match(packages)
+
+	assert.Equal(t, []Finding{
+		{ID: "CVE-1", Severity: "high", PURL: "pkg:deb/debian/openssl@1.1.1d-0%2Bdeb10u5", Location: "var/lib/dpkg/status", FixedVersion: "1.1.1d-0+deb10u6"},
+		{ID: "CVE-3", Severity: "critical", PURL: "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1", Location: "app.jar", FixedVersion: "2.15.0"},
+		{ID: "CVE-5", Severity: "high", PURL: "pkg:npm/minimist@1.2.6-rc1", Location: "node_modules/minimist/package.json", FixedVersion: "1.2.6"},
+	}, findings)
+}
+
+func TestSeverityRank(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, 0, SeverityRank("unknown"))
+	assert.Equal(t, 1, SeverityRank("low"))
+	assert.Equal(t, 4, SeverityRank("Critical"))
+}
+
+func TestCompareVersions(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		packageType, a, b string
+		expected          int
+	}{
+		{"deb", "1.0", "1.0", 0},
+		{"deb", "1.0", "1.0.1", -1},
+		{"deb", "1.10", "1.9", 1},
+		{"deb", "1.01", "1.1", 0},
+		{"deb", "1:1.0", "2.0", 1},
+		{"deb", "1.0~rc1", "1.0", -1},
+		{"deb", "1.0a", "1.0+", -1},
+		{"deb", "1.1.1d-0+deb10u5", "1.1.1d-0+deb10u6", -1},
+		{"deb", "2.28-10", "2.28-9", 1},
+		{"maven", "2.0-beta9", "2.0-rc1", -1},
+		{"apk", "1.2.2-r0", "1.2.2-r1", -1},
+		{"npm", "1.0.0-rc1", "1.0.0", -1},
+		{"npm", "1.0.0", "1.0.0-rc1", 1},
+		{"npm", "1.0.0-alpha.2", "1.0.0-alpha.10", -1},
+		{"npm", "1.0.0+build.1", "1.0.0", 0},
+		{"golang", "v0.0.0-20210114140201-1261216783c6", "v0.1.0", -1},
+		{"go", "v1.2.3-pre", "1.2.3", -1},
+		{"cargo", "1.10.0", "1.9.0", 1},
+		// invalid semantic versions are compared like other versions
+		{"npm", "1.0.0.1", "1.0.0.2", -1},
+	}
+	for _, test := range tests {
+		result := CompareVersions(test.packageType, test.a, test.b)
+		switch {
+		case test.expected < 0:
+			assert.Less(t, result, 0, "%v: %v < %v", test.packageType, test.a, test.b)
+		case test.expected > 0:
+			assert.Greater(t, result, 0, "%v: %v > %v", test.packageType, test.a, test.b)
+		default:
+			assert.Zero(t, result, "%v: %v == %v", test.packageType, test.a, test.b)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package scm
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/pkg/errors"
+)
+
+// bitbucketProvider uses the REST API of Bitbucket Server, Bitbucket does not provide releases and issues
+type bitbucketProvider struct {
+	client     Sender
+	serverURL  string
+	project    string
+	repository string
+}
+
+func newBitbucketProvider(options Options, client Sender) Provider {
+	return &bitbucketProvider{
+		client:     client,
+		serverURL:  trimURL(options.APIURL),
+		project:    url.PathEscape(options.Owner),
+		repository: url.PathEscape(options.Repository),
+	}
+}
+
+// repositoryURL returns the URL of the repository for a REST API, e.g. "api/1.0"
+func (p *bitbucketProvider) repositoryURL(api, path string, args ...interface{}) string {
+	return fmt.Sprintf("%v/rest/%v/projects/%v/repos/%v", p.serverURL, api, p.project, p.repository) + fmt.Sprintf(path, args...)
+}
+
+// PublishRelease creates an annotated tag, the body of the release becomes part of the tag message.
+// Bitbucket neither knows pre-releases nor release assets.
+func (p *bitbucketProvider) PublishRelease(release Release) (string, error) {
+	if len(release.Assets) > 0 {
+		return "", notSupported("release assets", "Bitbucket")
+	}
+	message := release.Name
+	if len(release.Body) > 0 {
+		message += "\n\n" + release.Body
+	}
+	request := map[string]string{"name": release.TagName, "startPoint": release.Commitish, "message": message}
+	if err := sendJSON(p.client, http.MethodPost, p.repositoryURL("git/1.0", "/tags"), request, nil); err != nil {
+		return "", errors.Wrapf(err, "failed to create tag '%v'", release.TagName)
+	}
+	return fmt.Sprintf("%v/projects/%v/repos/%v/browse?at=%v", p.serverURL, p.project, p.repository, url.QueryEscape("refs/tags/"+release.TagName)), nil
+}
+
+type bitbucketRef struct {
+	ID string `json:"id"`
+}
+
+type bitbucketUser struct {
+	Name string `json:"name"`
+}
+
+type bitbucketReviewer struct {
+	User bitbucketUser `json:"user"`
+}
+
+// CreatePullRequest creates a pull request, assignees are added as reviewers since Bitbucket has no assignees.
+func (p *bitbucketProvider) CreatePullRequest(pullRequest PullRequest) (Reference, error) {
+	if len(pullRequest.Labels) > 0 {
+		log.Entry().Warn("Bitbucket does not support labels, labels are not added to the pull request")
+	}
+	request := struct {
+		Title       string              `json:"title"`
+		Description string              `json:"description"`
+		FromRef     bitbucketRef        `json:"fromRef"`
+		ToRef       bitbucketRef        `json:"toRef"`
+		Reviewers   []bitbucketReviewer `json:"reviewers"`
+	}{
+		Title:       pullRequest.Title,
+		Description: pullRequest.Body,
+		FromRef:     bitbucketRef{ID: "refs/heads/" + pullRequest.Head},
+		ToRef:       bitbucketRef{ID: "refs/heads/" + pullRequest.Base},
+		Reviewers:   []bitbucketReviewer{},
+	}
+	for _, assignee := range pullRequest.Assignees {
+		request.Reviewers = append(request.Reviewers, bitbucketReviewer{User: bitbucketUser{Name: assignee}})
+	}
+
+	var created struct {
+		ID    int `json:"id"`
+		Links struct {
+			Self []struct {
+				Href string `json:"href"`
+			} `json:"self"`
+		} `json:"links"`
+	}
+	if err := sendJSON(p.client, http.MethodPost, p.repositoryURL("api/1.0", "/pull-requests"), request, &created); err != nil {
+		return Reference{}, errors.Wrap(err, "failed to create pull request")
+	}
+	reference := Reference{Number: created.ID}
+	if len(created.Links.Self) > 0 {
+		reference.URL = created.Links.Self[0].Href
+	}
+	return reference, nil
+}
+
+func (p *bitbucketProvider) SetCommitStatus(status CommitStatus) error {
+	states := map[string]string{
+		StatusPending: "INPROGRESS",
+		StatusSuccess: "SUCCESSFUL",
+		StatusFailure: "FAILED",
+		StatusError:   "FAILED",
+	}
+	state, ok := states[status.State]
+	if !ok {
+		return fmt.Errorf("status '%v' is not supported", status.State)
+	}
+	request := map[string]string{
+		"state":       state,
+		"key":         status.Context,
+		"name":        status.Context,
+		"description": status.Description,
+		"url":         status.TargetURL,
+	}
+	if err := sendJSON(p.client, http.MethodPost, fmt.Sprintf("%v/rest/build-status/1.0/commits/%v", p.serverURL, status.CommitID), request, nil); err != nil {
+		return errors.Wrapf(err, "failed to set status '%v' on commit '%v'", status.State, status.CommitID)
+	}
+	return nil
+}
+
+func (p *bitbucketProvider) Comment(number int, pullRequest bool, body string) error {
+	if !pullRequest {
+		return notSupported("issues", "Bitbucket")
+	}
+	if err := sendJSON(p.client, http.MethodPost, p.repositoryURL("api/1.0", "/pull-requests/%v/comments", number), map[string]string{"text": body}, nil); err != nil {
+		return errors.Wrapf(err, "failed to comment on pull request #%v", number)
+	}
+	return nil
+}
+
+func (p *bitbucketProvider) CreateIssue(Issue) (Reference, error) {
+	return Reference{}, notSupported("issues", "Bitbucket")
+}
+
+// GetBranchProtection maps the branch permissions and merge checks:
+// administrators cannot bypass the rules in case changes require a pull request without exemptions,
+// required builds are reported as required checks and the required approvers of the repository are used.
+func (p *bitbucketProvider) GetBranchProtection(branch string) (BranchProtection, error) {
+	ref := "refs/heads/" + branch
+	result := BranchProtection{}
+
+	var restrictions struct {
+		Values []struct {
+			Type   string          `json:"type"`
+			Users  []bitbucketUser `json:"users"`
+			Groups []string        `json:"groups"`
+		} `json:"values"`
+	}
+	restrictionsURL := p.repositoryURL("branch-permissions/2.0", "/restrictions?matcherType=BRANCH&matcherId=%v", url.QueryEscape(ref))
+	if err := sendJSON(p.client, http.MethodGet, restrictionsURL, nil, &restrictions); err != nil {
+		return result, errors.Wrapf(err, "failed to read branch permissions of '%v'", branch)
+	}
+	for _, restriction := range restrictions.Values {
+		if (restriction.Type == "pull-request-only" || restriction.Type == "read-only") && len(restriction.Users) == 0 && len(restriction.Groups) == 0 {
+			result.EnforceAdmins = true
+		}
+	}
+
+	var conditions struct {
+		Values []struct {
+			BuildParentKeys []string `json:"buildParentKeys"`
+			RefMatcher      struct {
+				ID string `json:"id"`
+			} `json:"refMatcher"`
+		} `json:"values"`
+	}
+	if err := sendJSON(p.client, http.MethodGet, p.repositoryURL("required-builds/latest", "/conditions"), nil, &conditions); err != nil {
+		return result, errors.Wrap(err, "failed to read required builds")
+	}
+	for _, condition := range conditions.Values {
+		if condition.RefMatcher.ID == ref || condition.RefMatcher.ID == branch {
+			result.RequiredChecks = append(result.RequiredChecks, condition.BuildParentKeys...)
+		}
+	}
+
+	var settings struct {
+		RequiredApprovers int `json:"requiredApprovers"`
+	}
+	if err := sendJSON(p.client, http.MethodGet, p.repositoryURL("api/1.0", "/settings/pull-requests"), nil, &settings); err != nil {
+		return result, errors.Wrap(err, "failed to read pull request settings")
+	}
+	result.RequiredApprovingReviewCount = settings.RequiredApprovers
+	return result, nil
+}
@@ -0,0 +1,115 @@
+package scm
+
+import (
+	"testing"
+
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/stretchr/testify/assert"
+)
+
+const bitbucketRepository = "/rest/api/1.0/projects/SAP/repos/jenkins-library"
+
+func newTestBitbucketProvider(serverURL string) Provider {
+	return newBitbucketProvider(Options{APIURL: serverURL, Owner: "SAP", Repository: "jenkins-library"}, &piperhttp.Client{})
+}
+
+func TestBitbucketPublishRelease(t *testing.T) {
+	t.Run("tag", func(t *testing.T) {
+		standIn, server := newStandInServer(map[string]string{"POST /rest/git/1.0/projects/SAP/repos/jenkins-library/tags": `{}`})
+		defer server.Close()
+
+		releaseURL, err := newTestBitbucketProvider(server.URL).PublishRelease(Release{TagName: "1.0.0", Name: "Release 1.0.0", Body: "notes", Commitish: "master"})
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, server.URL+"/projects/SAP/repos/jenkins-library/browse?at=refs%2Ftags%2F1.0.0", releaseURL)
+			assert.JSONEq(t, `{"name": "1.0.0", "startPoint": "master", "message": "Release 1.0.0\n\nnotes"}`,
+				standIn.requests["POST /rest/git/1.0/projects/SAP/repos/jenkins-library/tags"])
+		}
+	})
+
+	t.Run("assets", func(t *testing.T) {
+		_, err := newTestBitbucketProvider("https://bitbucket.example.org").PublishRelease(Release{TagName: "1.0.0", Assets: []string{"app.zip"}})
+		assert.EqualError(t, err, "release assets is not supported by Bitbucket")
+	})
+}
+
+func TestBitbucketCreatePullRequest(t *testing.T) {
+	standIn, server := newStandInServer(map[string]string{
+		"POST " + bitbucketRepository + "/pull-requests": `{"id": 12, "links": {"self": [{"href": "https://bitbucket.example.org/pull-requests/12"}]}}`,
+	})
+	defer server.Close()
+
+	reference, err := newTestBitbucketProvider(server.URL).CreatePullRequest(PullRequest{Title: "Update", Body: "details", Head: "feature", Base: "master", Assignees: []string{"jdoe"}})
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, Reference{Number: 12, URL: "https://bitbucket.example.org/pull-requests/12"}, reference)
+		assert.JSONEq(t, `{"title": "Update", "description": "details", "fromRef": {"id": "refs/heads/feature"}, "toRef": {"id": "refs/heads/master"}, "reviewers": [{"user": {"name": "jdoe"}}]}`,
+			standIn.requests["POST "+bitbucketRepository+"/pull-requests"])
+	}
+}
+
+func TestBitbucketSetCommitStatus(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		standIn, server := newStandInServer(map[string]string{"POST /rest/build-status/1.0/commits/abc123": ``})
+		defer server.Close()
+
+		err := newTestBitbucketProvider(server.URL).SetCommitStatus(CommitStatus{CommitID: "abc123", Context: "piper/test", Description: "tests", State: StatusPending, TargetURL: "https://jenkins.example.org"})
+
+		if assert.NoError(t, err) {
+			assert.JSONEq(t, `{"state": "INPROGRESS", "key": "piper/test", "name": "piper/test", "description": "tests", "url": "https://jenkins.example.org"}`,
+				standIn.requests["POST /rest/build-status/1.0/commits/abc123"])
+		}
+	})
+
+	t.Run("unknown status", func(t *testing.T) {
+		err := newTestBitbucketProvider("https://bitbucket.example.org").SetCommitStatus(CommitStatus{State: "skipped"})
+		assert.EqualError(t, err, "status 'skipped' is not supported")
+	})
+}
+
+func TestBitbucketComment(t *testing.T) {
+	standIn, server := newStandInServer(map[string]string{"POST " + bitbucketRepository + "/pull-requests/4/comments": `{}`})
+	defer server.Close()
+	provider := newTestBitbucketProvider(server.URL)
+
+	assert.NoError(t, provider.Comment(4, true, "comment"))
+	assert.JSONEq(t, `{"text": "comment"}`, standIn.requests["POST "+bitbucketRepository+"/pull-requests/4/comments"])
+	assert.EqualError(t, provider.Comment(4, false, "comment"), "issues is not supported by Bitbucket")
+}
+
+func TestBitbucketCreateIssue(t *testing.T) {
+	_, err := newTestBitbucketProvider("https://bitbucket.example.org").CreateIssue(Issue{Title: "Bug"})
+	assert.EqualError(t, err, "issues is not supported by Bitbucket")
+}
+
+func TestBitbucketGetBranchProtection(t *testing.T) {
+	t.Run("protected", func(t *testing.T) {
+		_, server := newStandInServer(map[string]string{
+			"GET /rest/branch-permissions/2.0/projects/SAP/repos/jenkins-library/restrictions?matcherType=BRANCH&matcherId=refs%2Fheads%2Fmaster": `{"values": [{"type": "fast-forward-only", "users": [], "groups": []}, {"type": "pull-request-only", "users": [], "groups": []}]}`,
+			"GET /rest/required-builds/latest/projects/SAP/repos/jenkins-library/conditions":                                                      `{"values": [{"buildParentKeys": ["piper/build"], "refMatcher": {"id": "refs/heads/master"}}, {"buildParentKeys": ["other"], "refMatcher": {"id": "refs/heads/release"}}]}`,
+			"GET " + bitbucketRepository + "/settings/pull-requests":                                                                              `{"requiredApprovers": 2}`,
+		})
+		defer server.Close()
+
+		protection, err := newTestBitbucketProvider(server.URL).GetBranchProtection("master")
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, BranchProtection{RequiredChecks: []string{"piper/build"}, EnforceAdmins: true, RequiredApprovingReviewCount: 2}, protection)
+		}
+	})
+
+	t.Run("exemptions", func(t *testing.T) {
+		_, server := newStandInServer(map[string]string{
+			"GET /rest/branch-permissions/2.0/projects/SAP/repos/jenkins-library/restrictions?matcherType=BRANCH&matcherId=refs%2Fheads%2Fmaster": `{"values": [{"type": "pull-request-only", "users": [{"name": "admin"}], "groups": []}]}`,
+			"GET /rest/required-builds/latest/projects/SAP/repos/jenkins-library/conditions":                                                      `{"values": []}`,
+			"GET " + bitbucketRepository + "/settings/pull-requests":                                                                              `{}`,
+		})
+		defer server.Close()
+
+		protection, err := newTestBitbucketProvider(server.URL).GetBranchProtection("master")
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, BranchProtection{}, protection)
+		}
+	})
+}
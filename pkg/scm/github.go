@@ -0,0 +1,143 @@
+package scm
+
+import (
+	"context"
+	"mime"
+	"os"
+	"path/filepath"
+
+	piperGithub "github.com/SAP/jenkins-library/pkg/github"
+	"github.com/google/go-github/v32/github"
+	"github.com/pkg/errors"
+)
+
+type gitHubProvider struct {
+	ctx        context.Context
+	client     *github.Client
+	owner      string
+	repository string
+}
+
+func newGitHubProvider(options Options) (Provider, error) {
+	apiURL := options.APIURL
+	if len(apiURL) == 0 {
+		apiURL = "https://api.github.com"
+	}
+	uploadURL := options.UploadURL
+	if len(uploadURL) == 0 {
+		uploadURL = "https://uploads.github.com"
+	}
+	app := options.GitHubApp
+	app.Owner = options.Owner
+	app.Repository = options.Repository
+	ctx, client, err := piperGithub.NewClientWithAuth(options.Token, app, apiURL, uploadURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create GitHub client")
+	}
+	return &gitHubProvider{ctx: ctx, client: client, owner: options.Owner, repository: options.Repository}, nil
+}
+
+func (p *gitHubProvider) PublishRelease(release Release) (string, error) {
+	created, _, err := p.client.Repositories.CreateRelease(p.ctx, p.owner, p.repository, &github.RepositoryRelease{
+		TagName:         &release.TagName,
+		TargetCommitish: &release.Commitish,
+		Name:            &release.Name,
+		Body:            &release.Body,
+		Prerelease:      &release.PreRelease,
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to create release '%v'", release.TagName)
+	}
+	for _, asset := range release.Assets {
+		if err := p.uploadAsset(created.GetID(), asset); err != nil {
+			return "", err
+		}
+	}
+	return created.GetHTMLURL(), nil
+}
+
+func (p *gitHubProvider) uploadAsset(releaseID int64, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open release asset '%v'", path)
+	}
+	defer file.Close()
+	mediaType := mime.TypeByExtension(filepath.Ext(path))
+	if mediaType == "" {
+		mediaType = "application/octet-stream"
+	}
+	options := github.UploadOptions{Name: filepath.Base(path), MediaType: mediaType}
+	if _, _, err := p.client.Repositories.UploadReleaseAsset(p.ctx, p.owner, p.repository, releaseID, &options, file); err != nil {
+		return errors.Wrapf(err, "failed to upload release asset '%v'", path)
+	}
+	return nil
+}
+
+func (p *gitHubProvider) CreatePullRequest(pullRequest PullRequest) (Reference, error) {
+	created, _, err := p.client.PullRequests.Create(p.ctx, p.owner, p.repository, &github.NewPullRequest{
+		Title: &pullRequest.Title,
+		Head:  &pullRequest.Head,
+		Base:  &pullRequest.Base,
+		Body:  &pullRequest.Body,
+	})
+	if err != nil {
+		return Reference{}, errors.Wrap(err, "failed to create pull request")
+	}
+	if len(pullRequest.Labels) > 0 || len(pullRequest.Assignees) > 0 {
+		_, _, err = p.client.Issues.Edit(p.ctx, p.owner, p.repository, created.GetNumber(), &github.IssueRequest{
+			Labels:    &pullRequest.Labels,
+			Assignees: &pullRequest.Assignees,
+		})
+		if err != nil {
+			return Reference{}, errors.Wrapf(err, "failed to add labels and assignees to pull request #%v", created.GetNumber())
+		}
+	}
+	return Reference{Number: created.GetNumber(), URL: created.GetHTMLURL()}, nil
+}
+
+func (p *gitHubProvider) SetCommitStatus(status CommitStatus) error {
+	_, _, err := p.client.Repositories.CreateStatus(p.ctx, p.owner, p.repository, status.CommitID, &github.RepoStatus{
+		Context:     &status.Context,
+		Description: &status.Description,
+		State:       &status.State,
+		TargetURL:   &status.TargetURL,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to set status '%v' on commit '%v'", status.State, status.CommitID)
+	}
+	return nil
+}
+
+// Comment adds a comment to an issue or a pull request, pull requests are handled like issues by GitHub
+func (p *gitHubProvider) Comment(number int, _ bool, body string) error {
+	if _, _, err := p.client.Issues.CreateComment(p.ctx, p.owner, p.repository, number, &github.IssueComment{Body: &body}); err != nil {
+		return errors.Wrapf(err, "failed to comment on #%v", number)
+	}
+	return nil
+}
+
+func (p *gitHubProvider) CreateIssue(issue Issue) (Reference, error) {
+	created, _, err := p.client.Issues.Create(p.ctx, p.owner, p.repository, &github.IssueRequest{Title: &issue.Title, Body: &issue.Body})
+	if err != nil {
+		return Reference{}, errors.Wrap(err, "failed to create issue")
+	}
+	return Reference{Number: created.GetNumber(), URL: created.GetHTMLURL()}, nil
+}
+
+func (p *gitHubProvider) GetBranchProtection(branch string) (BranchProtection, error) {
+	protection, _, err := p.client.Repositories.GetBranchProtection(p.ctx, p.owner, p.repository, branch)
+	if err != nil {
+		return BranchProtection{}, errors.Wrapf(err, "failed to read branch protection of '%v'", branch)
+	}
+	result := BranchProtection{}
+	if enforceAdmins := protection.GetEnforceAdmins(); enforceAdmins != nil {
+		result.EnforceAdmins = enforceAdmins.Enabled
+	}
+	if reviews := protection.GetRequiredPullRequestReviews(); reviews != nil {
+		result.RequiredApprovingReviewCount = reviews.RequiredApprovingReviewCount
+	}
+	if checks := protection.GetRequiredStatusChecks(); checks != nil {
+		result.RequiredChecks = checks.Contexts
+	}
+	return result, nil
+}
@@ -0,0 +1,105 @@
+package scm
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	piperGithub "github.com/SAP/jenkins-library/pkg/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestGitHubProvider(t *testing.T, serverURL string) Provider {
+	provider, err := newGitHubProvider(Options{APIURL: serverURL, UploadURL: serverURL, Token: "token", Owner: "SAP", Repository: "jenkins-library"})
+	require.NoError(t, err)
+	return provider
+}
+
+func TestGitHubCreatePullRequest(t *testing.T) {
+	standIn, server := newStandInServer(map[string]string{
+		"POST /repos/SAP/jenkins-library/pulls":     `{"number": 1, "html_url": "https://github.com/SAP/jenkins-library/pull/1"}`,
+		"PATCH /repos/SAP/jenkins-library/issues/1": `{"number": 1}`,
+	})
+	defer server.Close()
+
+	reference, err := newTestGitHubProvider(t, server.URL).CreatePullRequest(PullRequest{Title: "Update", Body: "details", Head: "feature", Base: "master", Labels: []string{"a"}, Assignees: []string{"jdoe"}})
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, Reference{Number: 1, URL: "https://github.com/SAP/jenkins-library/pull/1"}, reference)
+		assert.JSONEq(t, `{"title": "Update", "body": "details", "head": "feature", "base": "master"}`, standIn.requests["POST /repos/SAP/jenkins-library/pulls"])
+		assert.JSONEq(t, `{"labels": ["a"], "assignees": ["jdoe"]}`, standIn.requests["PATCH /repos/SAP/jenkins-library/issues/1"])
+	}
+}
+
+func TestGitHubComment(t *testing.T) {
+	standIn, server := newStandInServer(map[string]string{"POST /repos/SAP/jenkins-library/issues/2/comments": `{}`})
+	defer server.Close()
+
+	err := newTestGitHubProvider(t, server.URL).Comment(2, true, "comment")
+
+	if assert.NoError(t, err) {
+		assert.JSONEq(t, `{"body": "comment"}`, standIn.requests["POST /repos/SAP/jenkins-library/issues/2/comments"])
+	}
+}
+
+func TestGitHubGetBranchProtection(t *testing.T) {
+	t.Run("protected", func(t *testing.T) {
+		_, server := newStandInServer(map[string]string{
+			"GET /repos/SAP/jenkins-library/branches/master/protection": `{"required_status_checks": {"contexts": ["piper/build"]}, "enforce_admins": {"enabled": true}, "required_pull_request_reviews": {"required_approving_review_count": 2}}`,
+		})
+		defer server.Close()
+
+		protection, err := newTestGitHubProvider(t, server.URL).GetBranchProtection("master")
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, BranchProtection{RequiredChecks: []string{"piper/build"}, EnforceAdmins: true, RequiredApprovingReviewCount: 2}, protection)
+		}
+	})
+
+	t.Run("no reviews configured", func(t *testing.T) {
+		_, server := newStandInServer(map[string]string{"GET /repos/SAP/jenkins-library/branches/master/protection": `{}`})
+		defer server.Close()
+
+		protection, err := newTestGitHubProvider(t, server.URL).GetBranchProtection("master")
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, BranchProtection{}, protection)
+		}
+	})
+}
+
+func TestGitHubAppAuthentication(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	keyFile := filepath.Join(dir, "app.pem")
+	require.NoError(t, ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}), 0600))
+
+	standIn, server := newStandInServer(map[string]string{
+		"POST /app/installations/4711/access_tokens":        `{"token": "installation-token", "expires_at": "2999-01-01T00:00:00Z"}`,
+		"POST /repos/SAP/jenkins-library/issues/2/comments": `{}`,
+	})
+	defer server.Close()
+	provider, err := newGitHubProvider(Options{
+		APIURL:     server.URL,
+		GitHubApp:  piperGithub.AppOptions{AppID: 1234, InstallationID: 4711, PrivateKeyFile: keyFile},
+		Owner:      "SAP",
+		Repository: "jenkins-library",
+	})
+	require.NoError(t, err)
+
+	err = provider.Comment(2, true, "comment")
+
+	if assert.NoError(t, err) {
+		assert.Contains(t, standIn.authorizations["POST /app/installations/4711/access_tokens"], "Bearer ")
+		assert.Equal(t, "Bearer installation-token", standIn.authorizations["POST /repos/SAP/jenkins-library/issues/2/comments"])
+	}
+}
@@ -0,0 +1,242 @@
+package scm
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/pkg/errors"
+)
+
+// GitLabPipelineCheck is the name of the required check in case merge requests require a successful pipeline on GitLab
+const GitLabPipelineCheck = "pipeline"
+
+// gitLabProvider uses the GitLab REST API v4, pull requests are created as merge requests
+type gitLabProvider struct {
+	client  Sender
+	apiURL  string
+	project string
+}
+
+func newGitLabProvider(options Options, client Sender) Provider {
+	apiURL := options.APIURL
+	if len(apiURL) == 0 {
+		apiURL = "https://gitlab.com/api/v4"
+	}
+	return &gitLabProvider{
+		client:  client,
+		apiURL:  trimURL(apiURL),
+		project: url.PathEscape(options.Owner + "/" + options.Repository),
+	}
+}
+
+func (p *gitLabProvider) projectURL(path string, args ...interface{}) string {
+	return fmt.Sprintf("%v/projects/%v", p.apiURL, p.project) + fmt.Sprintf(path, args...)
+}
+
+type gitLabReleaseLink struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+type gitLabRelease struct {
+	TagName     string `json:"tag_name"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Ref         string `json:"ref,omitempty"`
+	Assets      struct {
+		Links []gitLabReleaseLink `json:"links,omitempty"`
+	} `json:"assets"`
+}
+
+// PublishRelease creates a GitLab release, GitLab does not know pre-releases
+func (p *gitLabProvider) PublishRelease(release Release) (string, error) {
+	request := gitLabRelease{TagName: release.TagName, Name: release.Name, Description: release.Body, Ref: release.Commitish}
+	if len(release.Assets) > 0 {
+		var project struct {
+			WebURL string `json:"web_url"`
+		}
+		if err := sendJSON(p.client, http.MethodGet, p.projectURL(""), nil, &project); err != nil {
+			return "", errors.Wrap(err, "failed to read project")
+		}
+		for _, asset := range release.Assets {
+			link, err := p.uploadFile(asset, project.WebURL)
+			if err != nil {
+				return "", err
+			}
+			request.Assets.Links = append(request.Assets.Links, link)
+		}
+	}
+
+	var created struct {
+		Links struct {
+			Self string `json:"self"`
+		} `json:"_links"`
+	}
+	if err := sendJSON(p.client, http.MethodPost, p.projectURL("/releases"), request, &created); err != nil {
+		return "", errors.Wrapf(err, "failed to create release '%v'", release.TagName)
+	}
+	return created.Links.Self, nil
+}
+
+// uploadFile uploads a file to the project, the returned link refers to the uploaded file
+func (p *gitLabProvider) uploadFile(path, projectURL string) (gitLabReleaseLink, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return gitLabReleaseLink{}, errors.Wrapf(err, "failed to open release asset '%v'", path)
+	}
+	defer file.Close()
+	response, err := p.client.Upload(piperhttp.UploadRequestData{
+		Method:        http.MethodPost,
+		URL:           p.projectURL("/uploads"),
+		File:          filepath.Base(path),
+		FileFieldName: "file",
+		FileContent:   file,
+	})
+	if err != nil {
+		return gitLabReleaseLink{}, errors.Wrapf(err, "failed to upload release asset '%v'", path)
+	}
+	var uploaded struct {
+		URL string `json:"url"`
+	}
+	if err := decode(response, &uploaded); err != nil {
+		return gitLabReleaseLink{}, errors.Wrapf(err, "failed to upload release asset '%v'", path)
+	}
+	return gitLabReleaseLink{Name: filepath.Base(path), URL: trimURL(projectURL) + uploaded.URL}, nil
+}
+
+func (p *gitLabProvider) CreatePullRequest(pullRequest PullRequest) (Reference, error) {
+	assigneeIDs := []int{}
+	for _, assignee := range pullRequest.Assignees {
+		var users []struct {
+			ID int `json:"id"`
+		}
+		if err := sendJSON(p.client, http.MethodGet, fmt.Sprintf("%v/users?username=%v", p.apiURL, url.QueryEscape(assignee)), nil, &users); err != nil {
+			return Reference{}, errors.Wrapf(err, "failed to look up assignee '%v'", assignee)
+		}
+		if len(users) == 0 {
+			return Reference{}, fmt.Errorf("assignee '%v' not found", assignee)
+		}
+		assigneeIDs = append(assigneeIDs, users[0].ID)
+	}
+
+	request := map[string]interface{}{
+		"source_branch": pullRequest.Head,
+		"target_branch": pullRequest.Base,
+		"title":         pullRequest.Title,
+		"description":   pullRequest.Body,
+		"labels":        strings.Join(pullRequest.Labels, ","),
+		"assignee_ids":  assigneeIDs,
+	}
+	var created gitLabReference
+	if err := sendJSON(p.client, http.MethodPost, p.projectURL("/merge_requests"), request, &created); err != nil {
+		return Reference{}, errors.Wrap(err, "failed to create merge request")
+	}
+	return created.reference(), nil
+}
+
+type gitLabReference struct {
+	IID    int    `json:"iid"`
+	WebURL string `json:"web_url"`
+}
+
+func (r gitLabReference) reference() Reference {
+	return Reference{Number: r.IID, URL: r.WebURL}
+}
+
+func (p *gitLabProvider) SetCommitStatus(status CommitStatus) error {
+	state := status.State
+	if state == StatusFailure || state == StatusError {
+		state = "failed"
+	}
+	request := map[string]string{
+		"state":       state,
+		"name":        status.Context,
+		"description": status.Description,
+		"target_url":  status.TargetURL,
+	}
+	if err := sendJSON(p.client, http.MethodPost, p.projectURL("/statuses/%v", status.CommitID), request, nil); err != nil {
+		return errors.Wrapf(err, "failed to set status '%v' on commit '%v'", status.State, status.CommitID)
+	}
+	return nil
+}
+
+func (p *gitLabProvider) Comment(number int, pullRequest bool, body string) error {
+	path := "/issues/%v/notes"
+	if pullRequest {
+		path = "/merge_requests/%v/notes"
+	}
+	if err := sendJSON(p.client, http.MethodPost, p.projectURL(path, number), map[string]string{"body": body}, nil); err != nil {
+		return errors.Wrapf(err, "failed to comment on #%v", number)
+	}
+	return nil
+}
+
+func (p *gitLabProvider) CreateIssue(issue Issue) (Reference, error) {
+	var created gitLabReference
+	request := map[string]string{"title": issue.Title, "description": issue.Body}
+	if err := sendJSON(p.client, http.MethodPost, p.projectURL("/issues"), request, &created); err != nil {
+		return Reference{}, errors.Wrap(err, "failed to create issue")
+	}
+	return created.reference(), nil
+}
+
+// GetBranchProtection maps the settings of a protected branch:
+// administrators cannot bypass the rules in case nobody is allowed to push or force push to the branch,
+// a successful pipeline is reported as required check "pipeline" and approvals are read from the approval rules.
+func (p *gitLabProvider) GetBranchProtection(branch string) (BranchProtection, error) {
+	var protectedBranch struct {
+		PushAccessLevels []struct {
+			AccessLevel int `json:"access_level"`
+		} `json:"push_access_levels"`
+		AllowForcePush bool `json:"allow_force_push"`
+	}
+	if err := sendJSON(p.client, http.MethodGet, p.projectURL("/protected_branches/%v", url.PathEscape(branch)), nil, &protectedBranch); err != nil {
+		return BranchProtection{}, errors.Wrapf(err, "failed to read branch protection of '%v'", branch)
+	}
+
+	result := BranchProtection{EnforceAdmins: !protectedBranch.AllowForcePush}
+	for _, level := range protectedBranch.PushAccessLevels {
+		// 0 is "No one"
+		if level.AccessLevel != 0 {
+			result.EnforceAdmins = false
+		}
+	}
+
+	var project struct {
+		PipelineRequired bool `json:"only_allow_merge_if_pipeline_succeeds"`
+	}
+	if err := sendJSON(p.client, http.MethodGet, p.projectURL(""), nil, &project); err != nil {
+		return BranchProtection{}, errors.Wrap(err, "failed to read project")
+	}
+	if project.PipelineRequired {
+		result.RequiredChecks = []string{GitLabPipelineCheck}
+	}
+
+	var rules []struct {
+		ApprovalsRequired int `json:"approvals_required"`
+		ProtectedBranches []struct {
+			Name string `json:"name"`
+		} `json:"protected_branches"`
+	}
+	if err := sendJSON(p.client, http.MethodGet, p.projectURL("/approval_rules"), nil, &rules); err != nil {
+		// approval rules are not available in all editions of GitLab
+		log.Entry().WithError(err).Warn("Failed to read approval rules, no approvals are considered required")
+		return result, nil
+	}
+	for _, rule := range rules {
+		applies := len(rule.ProtectedBranches) == 0
+		for _, protected := range rule.ProtectedBranches {
+			applies = applies || protected.Name == branch
+		}
+		if applies && rule.ApprovalsRequired > result.RequiredApprovingReviewCount {
+			result.RequiredApprovingReviewCount = rule.ApprovalsRequired
+		}
+	}
+	return result, nil
+}
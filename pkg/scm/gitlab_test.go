@@ -0,0 +1,148 @@
+package scm
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const gitLabProject = "/api/v4/projects/SAP%2Fjenkins-library"
+
+func newTestGitLabProvider(serverURL string) Provider {
+	return newGitLabProvider(Options{APIURL: serverURL + "/api/v4/", Owner: "SAP", Repository: "jenkins-library"}, &piperhttp.Client{})
+}
+
+func TestGitLabPublishRelease(t *testing.T) {
+	t.Run("with assets", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "gitlab")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+		asset := filepath.Join(dir, "app.zip")
+		require.NoError(t, ioutil.WriteFile(asset, []byte("content"), 0644))
+
+		standIn, server := newStandInServer(map[string]string{
+			"GET " + gitLabProject:                `{"web_url": "https://gitlab.example.org/SAP/jenkins-library"}`,
+			"POST " + gitLabProject + "/uploads":  `{"url": "/uploads/1234/app.zip"}`,
+			"POST " + gitLabProject + "/releases": `{"_links": {"self": "https://gitlab.example.org/SAP/jenkins-library/-/releases/1.0.0"}}`,
+		})
+		defer server.Close()
+
+		releaseURL, err := newTestGitLabProvider(server.URL).PublishRelease(Release{TagName: "1.0.0", Name: "Release 1.0.0", Body: "notes", Commitish: "master", Assets: []string{asset}})
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, "https://gitlab.example.org/SAP/jenkins-library/-/releases/1.0.0", releaseURL)
+			assert.Contains(t, standIn.requests["POST "+gitLabProject+"/uploads"], "content")
+			assert.JSONEq(t, `{"tag_name": "1.0.0", "name": "Release 1.0.0", "description": "notes", "ref": "master",
+				"assets": {"links": [{"name": "app.zip", "url": "https://gitlab.example.org/SAP/jenkins-library/uploads/1234/app.zip"}]}}`,
+				standIn.requests["POST "+gitLabProject+"/releases"])
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		_, server := newStandInServer(map[string]string{})
+		defer server.Close()
+
+		_, err := newTestGitLabProvider(server.URL).PublishRelease(Release{TagName: "1.0.0"})
+
+		assert.Contains(t, err.Error(), "failed to create release '1.0.0'")
+	})
+}
+
+func TestGitLabCreatePullRequest(t *testing.T) {
+	standIn, server := newStandInServer(map[string]string{
+		"GET /api/v4/users?username=jdoe":           `[{"id": 42, "username": "jdoe"}]`,
+		"POST " + gitLabProject + "/merge_requests": `{"iid": 7, "web_url": "https://gitlab.example.org/SAP/jenkins-library/-/merge_requests/7"}`,
+	})
+	defer server.Close()
+
+	reference, err := newTestGitLabProvider(server.URL).CreatePullRequest(PullRequest{Title: "Update", Body: "details", Head: "feature", Base: "master", Labels: []string{"a", "b"}, Assignees: []string{"jdoe"}})
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, Reference{Number: 7, URL: "https://gitlab.example.org/SAP/jenkins-library/-/merge_requests/7"}, reference)
+		assert.JSONEq(t, `{"source_branch": "feature", "target_branch": "master", "title": "Update", "description": "details", "labels": "a,b", "assignee_ids": [42]}`,
+			standIn.requests["POST "+gitLabProject+"/merge_requests"])
+	}
+}
+
+func TestGitLabSetCommitStatus(t *testing.T) {
+	standIn, server := newStandInServer(map[string]string{"POST " + gitLabProject + "/statuses/abc123": `{}`})
+	defer server.Close()
+
+	err := newTestGitLabProvider(server.URL).SetCommitStatus(CommitStatus{CommitID: "abc123", Context: "piper/test", Description: "tests", State: StatusFailure, TargetURL: "https://jenkins.example.org"})
+
+	if assert.NoError(t, err) {
+		assert.JSONEq(t, `{"state": "failed", "name": "piper/test", "description": "tests", "target_url": "https://jenkins.example.org"}`,
+			standIn.requests["POST "+gitLabProject+"/statuses/abc123"])
+	}
+}
+
+func TestGitLabComment(t *testing.T) {
+	standIn, server := newStandInServer(map[string]string{
+		"POST " + gitLabProject + "/issues/3/notes":         `{}`,
+		"POST " + gitLabProject + "/merge_requests/4/notes": `{}`,
+	})
+	defer server.Close()
+	provider := newTestGitLabProvider(server.URL)
+
+	assert.NoError(t, provider.Comment(3, false, "issue comment"))
+	assert.NoError(t, provider.Comment(4, true, "merge request comment"))
+	assert.JSONEq(t, `{"body": "issue comment"}`, standIn.requests["POST "+gitLabProject+"/issues/3/notes"])
+	assert.JSONEq(t, `{"body": "merge request comment"}`, standIn.requests["POST "+gitLabProject+"/merge_requests/4/notes"])
+}
+
+func TestGitLabCreateIssue(t *testing.T) {
+	standIn, server := newStandInServer(map[string]string{"POST " + gitLabProject + "/issues": `{"iid": 5, "web_url": "https://gitlab.example.org/issues/5"}`})
+	defer server.Close()
+
+	reference, err := newTestGitLabProvider(server.URL).CreateIssue(Issue{Title: "Bug", Body: "details"})
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, Reference{Number: 5, URL: "https://gitlab.example.org/issues/5"}, reference)
+		assert.JSONEq(t, `{"title": "Bug", "description": "details"}`, standIn.requests["POST "+gitLabProject+"/issues"])
+	}
+}
+
+func TestGitLabGetBranchProtection(t *testing.T) {
+	t.Run("protected", func(t *testing.T) {
+		_, server := newStandInServer(map[string]string{
+			"GET " + gitLabProject + "/protected_branches/master": `{"push_access_levels": [{"access_level": 0}], "allow_force_push": false}`,
+			"GET " + gitLabProject:                                `{"only_allow_merge_if_pipeline_succeeds": true}`,
+			"GET " + gitLabProject + "/approval_rules":            `[{"approvals_required": 1, "protected_branches": []}, {"approvals_required": 2, "protected_branches": [{"name": "master"}]}, {"approvals_required": 3, "protected_branches": [{"name": "release"}]}]`,
+		})
+		defer server.Close()
+
+		protection, err := newTestGitLabProvider(server.URL).GetBranchProtection("master")
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, BranchProtection{RequiredChecks: []string{"pipeline"}, EnforceAdmins: true, RequiredApprovingReviewCount: 2}, protection)
+		}
+	})
+
+	t.Run("maintainers may push, no approval rules", func(t *testing.T) {
+		_, server := newStandInServer(map[string]string{
+			"GET " + gitLabProject + "/protected_branches/master": `{"push_access_levels": [{"access_level": 40}], "allow_force_push": false}`,
+			"GET " + gitLabProject:                                `{"only_allow_merge_if_pipeline_succeeds": false}`,
+		})
+		defer server.Close()
+
+		protection, err := newTestGitLabProvider(server.URL).GetBranchProtection("master")
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, BranchProtection{}, protection)
+		}
+	})
+
+	t.Run("not protected", func(t *testing.T) {
+		_, server := newStandInServer(map[string]string{})
+		defer server.Close()
+
+		_, err := newTestGitLabProvider(server.URL).GetBranchProtection("master")
+
+		assert.Contains(t, err.Error(), "failed to read branch protection of 'master'")
+	})
+}
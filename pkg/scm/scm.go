@@ -0,0 +1,190 @@
+package scm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	piperGithub "github.com/SAP/jenkins-library/pkg/github"
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/pkg/errors"
+)
+
+// Supported source code management systems
+const (
+	GitHub    = "github"
+	GitLab    = "gitlab"
+	Bitbucket = "bitbucket"
+)
+
+// Commit status values, they are mapped to the values of the respective provider
+const (
+	StatusPending = "pending"
+	StatusSuccess = "success"
+	StatusFailure = "failure"
+	StatusError   = "error"
+)
+
+// Sender provides an interface to the piper http client
+type Sender interface {
+	SendRequest(method, url string, body io.Reader, header http.Header, cookies []*http.Cookie) (*http.Response, error)
+	Upload(data piperhttp.UploadRequestData) (*http.Response, error)
+}
+
+// Provider provides access to a repository hosted on a source code management system
+type Provider interface {
+	// PublishRelease creates a release for a tag and returns the URL of the release
+	PublishRelease(release Release) (string, error)
+	// CreatePullRequest creates a pull request (merge request on GitLab)
+	CreatePullRequest(pullRequest PullRequest) (Reference, error)
+	SetCommitStatus(status CommitStatus) error
+	// Comment adds a comment to an issue or a pull request
+	Comment(number int, pullRequest bool, body string) error
+	CreateIssue(issue Issue) (Reference, error)
+	GetBranchProtection(branch string) (BranchProtection, error)
+}
+
+// Options define the repository as well as the access to the source code management system
+type Options struct {
+	// Provider is one of github, gitlab or bitbucket
+	Provider string
+	// APIURL is the base URL of the REST API, e.g. https://gitlab.com/api/v4 or https://bitbucket.example.org
+	APIURL string
+	// UploadURL is only used for GitHub release assets
+	UploadURL string
+	Token     string
+	// GitHubApp is used for authentication to GitHub instead of the token in case an app ID is provided
+	GitHubApp piperGithub.AppOptions
+	// Owner is the GitHub organization, the GitLab namespace or the Bitbucket project key
+	Owner      string
+	Repository string
+}
+
+// Release describes a release which is created for a tag
+type Release struct {
+	TagName string
+	Name    string
+	Body    string
+	// Commitish is used to create the tag in case it does not exist yet
+	Commitish  string
+	PreRelease bool
+	// Assets contain paths to files which are attached to the release
+	Assets []string
+}
+
+// PullRequest describes a pull request which merges Head into Base
+type PullRequest struct {
+	Title     string
+	Body      string
+	Head      string
+	Base      string
+	Labels    []string
+	Assignees []string
+}
+
+// CommitStatus describes a status of a commit, e.g. the result of a build
+type CommitStatus struct {
+	CommitID    string
+	Context     string
+	Description string
+	// State is one of pending, success, failure or error
+	State     string
+	TargetURL string
+}
+
+// Issue describes an issue which is created
+type Issue struct {
+	Title string
+	Body  string
+}
+
+// Reference identifies a created pull request or issue
+type Reference struct {
+	Number int
+	URL    string
+}
+
+// BranchProtection describes the rules which protect a branch
+type BranchProtection struct {
+	// RequiredChecks are the names of the status checks which need to succeed before merging
+	RequiredChecks []string
+	// EnforceAdmins is true in case administrators cannot bypass the rules
+	EnforceAdmins bool
+	// RequiredApprovingReviewCount is the number of approvals which are required before merging
+	RequiredApprovingReviewCount int
+}
+
+// NewProvider creates the provider configured in options
+func NewProvider(options Options) (Provider, error) {
+	switch options.Provider {
+	case GitHub:
+		return newGitHubProvider(options)
+	case GitLab:
+		if len(options.Token) == 0 {
+			return nil, fmt.Errorf("a token is required for authentication to GitLab")
+		}
+		return newGitLabProvider(options, newSender(options.Token)), nil
+	case Bitbucket:
+		if len(options.APIURL) == 0 {
+			return nil, fmt.Errorf("the URL of the Bitbucket server is required")
+		}
+		if len(options.Token) == 0 {
+			return nil, fmt.Errorf("a token is required for authentication to Bitbucket")
+		}
+		return newBitbucketProvider(options, newSender(options.Token)), nil
+	}
+	return nil, fmt.Errorf("provider '%v' is not supported, use one of %v, %v or %v", options.Provider, GitHub, GitLab, Bitbucket)
+}
+
+func newSender(token string) Sender {
+	client := &piperhttp.Client{}
+	client.SetOptions(piperhttp.ClientOptions{Token: "Bearer " + token})
+	return client
+}
+
+func notSupported(operation, provider string) error {
+	return fmt.Errorf("%v is not supported by %v", operation, provider)
+}
+
+// sendJSON sends payload as JSON and decodes the JSON response into result in case result is not nil
+func sendJSON(client Sender, method, url string, payload, result interface{}) error {
+	var body io.Reader
+	header := http.Header{}
+	if payload != nil {
+		content, err := json.Marshal(payload)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal request")
+		}
+		body = bytes.NewReader(content)
+		header.Set("Content-Type", "application/json")
+	}
+	header.Set("Accept", "application/json")
+	response, err := client.SendRequest(method, url, body, header, nil)
+	if err != nil {
+		return err
+	}
+	return decode(response, result)
+}
+
+func decode(response *http.Response, result interface{}) error {
+	defer response.Body.Close()
+	if result == nil {
+		return nil
+	}
+	content, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return errors.Wrap(err, "failed to read response")
+	}
+	if err := json.Unmarshal(content, result); err != nil {
+		return errors.Wrap(err, "failed to parse response")
+	}
+	return nil
+}
+
+func trimURL(url string) string {
+	return strings.TrimSuffix(url, "/")
+}
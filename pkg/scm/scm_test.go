@@ -0,0 +1,74 @@
+package scm
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	piperGithub "github.com/SAP/jenkins-library/pkg/github"
+	"github.com/stretchr/testify/assert"
+)
+
+// standInServer emulates the REST API of a provider, responses are defined per method and request URI
+type standInServer struct {
+	responses map[string]string
+	// requests contains the bodies of the received requests per method and request URI
+	requests map[string]string
+	// authorizations contains the Authorization headers of the received requests per method and request URI
+	authorizations map[string]string
+}
+
+func newStandInServer(responses map[string]string) (*standInServer, *httptest.Server) {
+	standIn := &standInServer{responses: responses, requests: map[string]string{}, authorizations: map[string]string{}}
+	return standIn, httptest.NewServer(standIn)
+}
+
+func (s *standInServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	key := r.Method + " " + r.URL.RequestURI()
+	body, _ := ioutil.ReadAll(r.Body)
+	s.requests[key] = string(body)
+	s.authorizations[key] = r.Header.Get("Authorization")
+	response, ok := s.responses[key]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(response))
+}
+
+func TestNewProvider(t *testing.T) {
+	t.Run("providers", func(t *testing.T) {
+		for _, provider := range []string{GitHub, GitLab, Bitbucket} {
+			p, err := NewProvider(Options{Provider: provider, APIURL: "https://scm.example.org", Token: "token"})
+			assert.NoError(t, err)
+			assert.NotNil(t, p)
+		}
+	})
+
+	t.Run("missing Bitbucket URL", func(t *testing.T) {
+		_, err := NewProvider(Options{Provider: Bitbucket})
+		assert.EqualError(t, err, "the URL of the Bitbucket server is required")
+	})
+
+	t.Run("missing token", func(t *testing.T) {
+		_, err := NewProvider(Options{Provider: GitHub})
+		assert.EqualError(t, err, "failed to create GitHub client: either a token or a GitHub App is required for authentication")
+		_, err = NewProvider(Options{Provider: GitLab})
+		assert.EqualError(t, err, "a token is required for authentication to GitLab")
+		_, err = NewProvider(Options{Provider: Bitbucket, APIURL: "https://scm.example.org"})
+		assert.EqualError(t, err, "a token is required for authentication to Bitbucket")
+	})
+
+	t.Run("GitHub App", func(t *testing.T) {
+		_, err := NewProvider(Options{Provider: GitHub, GitHubApp: piperGithub.AppOptions{AppID: 1, PrivateKeyFile: "not/existing.pem"}})
+		assert.Contains(t, fmt.Sprint(err), "failed to read private key of GitHub App from 'not/existing.pem'")
+	})
+
+	t.Run("unsupported provider", func(t *testing.T) {
+		_, err := NewProvider(Options{Provider: "svn"})
+		assert.EqualError(t, err, "provider 'svn' is not supported, use one of github, gitlab or bitbucket")
+	})
+}
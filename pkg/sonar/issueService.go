@@ -2,6 +2,7 @@ package sonar
 
 import (
 	"net/http"
+	"strconv"
 
 	sonargo "github.com/magicsong/sonargo/sonar"
 	"github.com/pkg/errors"
@@ -44,12 +45,10 @@ func (service *IssueService) SearchIssues(options *IssuesSearchOption) (*sonargo
 	return result, response, nil
 }
 
-func (service *IssueService) getIssueCount(severity issueSeverity) (int, error) {
+func (service *IssueService) searchOptions() *IssuesSearchOption {
 	options := &IssuesSearchOption{
 		ComponentKeys: service.Project,
-		Severities:    severity.ToString(),
 		Resolved:      "false",
-		Ps:            "1",
 	}
 	if len(service.Branch) > 0 {
 		options.Branch = service.Branch
@@ -60,6 +59,13 @@ func (service *IssueService) getIssueCount(severity issueSeverity) (int, error)
 	if len(service.PullRequest) > 0 {
 		options.PullRequest = service.PullRequest
 	}
+	return options
+}
+
+func (service *IssueService) getIssueCount(severity issueSeverity) (int, error) {
+	options := service.searchOptions()
+	options.Severities = severity.ToString()
+	options.Ps = "1"
 	result, _, err := service.SearchIssues(options)
 	if err != nil {
 		return -1, errors.Wrapf(err, "failed to fetch the numer of '%s' issues", severity)
@@ -92,6 +98,25 @@ func (service *IssueService) GetNumberOfInfoIssues() (int, error) {
 	return service.getIssueCount(info)
 }
 
+// GetUnresolvedIssues returns all unresolved issues of the project, e.g. to locate them in the source code.
+func (service *IssueService) GetUnresolvedIssues() ([]*sonargo.Issue, error) {
+	issues := []*sonargo.Issue{}
+	options := service.searchOptions()
+	options.Ps = "500"
+	for page := 1; ; page++ {
+		options.P = strconv.Itoa(page)
+		result, _, err := service.SearchIssues(options)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to fetch the unresolved issues")
+		}
+		issues = append(issues, result.Issues...)
+		// the API does not provide more than 10000 results
+		if len(result.Issues) == 0 || len(issues) >= result.Total || len(issues) >= 10000 {
+			return issues, nil
+		}
+	}
+}
+
 // NewIssuesService returns a new instance of a service for the issues API endpoint.
 func NewIssuesService(host, token, project, organization, branch, pullRequest string, client Sender) *IssueService {
 	return &IssueService{
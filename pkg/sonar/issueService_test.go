@@ -70,6 +70,48 @@ func TestIssueService(t *testing.T) {
 	})
 }
 
+func TestGetUnresolvedIssues(t *testing.T) {
+	testURL := "https://example.org"
+	t.Run("success", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		sender := &piperhttp.Client{}
+		sender.SetOptions(piperhttp.ClientOptions{UseDefaultTransport: true})
+		// add response handler returning one issue per page
+		httpmock.RegisterResponder(http.MethodGet, testURL+"/api/"+EndpointIssuesSearch+"", func(request *http.Request) (*http.Response, error) {
+			page := request.URL.Query().Get("p")
+			return httpmock.NewStringResponse(http.StatusOK, `{"total": 2, "issues": [{"key": "`+page+`", "component": "project:main.go", "line": 3}]}`), nil
+		})
+		// create service instance
+		serviceUnderTest := NewIssuesService(testURL, mock.Anything, "project", mock.Anything, mock.Anything, mock.Anything, sender)
+		// test
+		issues, err := serviceUnderTest.GetUnresolvedIssues()
+		// assert
+		if assert.NoError(t, err) && assert.Len(t, issues, 2) {
+			assert.Equal(t, "1", issues[0].Key)
+			assert.Equal(t, "2", issues[1].Key)
+			assert.Equal(t, 3, issues[1].Line)
+		}
+		assert.Equal(t, 2, httpmock.GetTotalCallCount(), "unexpected number of requests")
+	})
+	t.Run("error", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		sender := &piperhttp.Client{}
+		sender.SetOptions(piperhttp.ClientOptions{UseDefaultTransport: true})
+		// add response handler
+		httpmock.RegisterResponder(http.MethodGet, testURL+"/api/"+EndpointIssuesSearch+"", httpmock.NewStringResponder(http.StatusNotFound, responseIssueSearchError))
+		// create service instance
+		serviceUnderTest := NewIssuesService(testURL, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, sender)
+		// test
+		_, err := serviceUnderTest.GetUnresolvedIssues()
+		// assert
+		assert.Contains(t, err.Error(), "failed to fetch the unresolved issues")
+	})
+}
+
 const responseIssueSearchError = `{
   "errors": [
     {
@@ -0,0 +1,161 @@
+package spinnaker
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/pkg/errors"
+)
+
+// Execution status values as reported by the Spinnaker Gate API
+const (
+	StatusNotStarted = "NOT_STARTED"
+	StatusRunning    = "RUNNING"
+	StatusPaused     = "PAUSED"
+	StatusSuspended  = "SUSPENDED"
+	StatusBuffered   = "BUFFERED"
+	StatusSucceeded  = "SUCCEEDED"
+)
+
+// Sender provides an interface to the piper http client
+type Sender interface {
+	SendRequest(method, url string, body io.Reader, header http.Header, cookies []*http.Cookie) (*http.Response, error)
+}
+
+// Client is a client for the Spinnaker Gate API
+type Client struct {
+	gateURL string
+	client  Sender
+	// sleep is used while polling, can be replaced for testing purposes
+	sleep func(time.Duration)
+}
+
+// Stage describes a single stage of a pipeline execution
+type Stage struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Type      string `json:"type"`
+	Status    string `json:"status"`
+	StartTime int64  `json:"startTime,omitempty"`
+	EndTime   int64  `json:"endTime,omitempty"`
+}
+
+// Execution describes a pipeline execution
+type Execution struct {
+	ID          string  `json:"id"`
+	Name        string  `json:"name"`
+	Application string  `json:"application"`
+	Status      string  `json:"status"`
+	StartTime   int64   `json:"startTime,omitempty"`
+	EndTime     int64   `json:"endTime,omitempty"`
+	Stages      []Stage `json:"stages"`
+}
+
+// Finished returns true in case the execution reached a final state
+func (e *Execution) Finished() bool {
+	switch e.Status {
+	case StatusNotStarted, StatusRunning, StatusPaused, StatusSuspended, StatusBuffered, "":
+		return false
+	}
+	return true
+}
+
+// Succeeded returns true in case the execution finished successfully
+func (e *Execution) Succeeded() bool {
+	return e.Status == StatusSucceeded
+}
+
+// NewClient creates a new client for the Spinnaker Gate API available at gateURL
+func NewClient(gateURL string, client Sender) *Client {
+	return &Client{
+		gateURL: strings.TrimSuffix(gateURL, "/"),
+		client:  client,
+		sleep:   time.Sleep,
+	}
+}
+
+// TriggerPipeline triggers an execution of a pipeline and returns the reference of the execution
+func (c *Client) TriggerPipeline(application, pipelineNameOrID string, parameters map[string]interface{}) (string, error) {
+	body := map[string]interface{}{}
+	if len(parameters) > 0 {
+		body["parameters"] = parameters
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal pipeline parameters")
+	}
+
+	url := fmt.Sprintf("%s/pipelines/%s/%s", c.gateURL, application, pipelineNameOrID)
+	log.Entry().Debugf("Triggering Spinnaker pipeline via '%s' with payload '%s'", url, string(payload))
+
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	response, err := c.client.SendRequest(http.MethodPost, url, bytes.NewReader(payload), header, nil)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to trigger pipeline '%s' of application '%s'", pipelineNameOrID, application)
+	}
+	defer response.Body.Close()
+
+	var result struct {
+		Ref string `json:"ref"`
+	}
+	if err := decode(response, &result); err != nil {
+		return "", errors.Wrap(err, "failed to parse trigger response")
+	}
+	if len(result.Ref) == 0 {
+		return "", fmt.Errorf("failed to trigger pipeline '%s' of application '%s': no execution reference returned", pipelineNameOrID, application)
+	}
+	return result.Ref, nil
+}
+
+// GetExecution retrieves the execution referenced by ref, e.g. "/pipelines/01E..."
+func (c *Client) GetExecution(ref string) (*Execution, error) {
+	url := c.gateURL + "/" + strings.TrimPrefix(ref, "/")
+	response, err := c.client.SendRequest(http.MethodGet, url, nil, nil, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to retrieve execution '%s'", ref)
+	}
+	defer response.Body.Close()
+
+	execution := Execution{}
+	if err := decode(response, &execution); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse execution '%s'", ref)
+	}
+	return &execution, nil
+}
+
+// WaitForExecution polls the execution referenced by ref until it is finished or the timeout is reached.
+// The last known state of the execution is returned in any case it could be retrieved at least once.
+func (c *Client) WaitForExecution(ref string, timeout, pollInterval time.Duration) (*Execution, error) {
+	var waited time.Duration
+	for {
+		execution, err := c.GetExecution(ref)
+		if err != nil {
+			return nil, err
+		}
+		log.Entry().Infof("Spinnaker pipeline execution '%s' status: %s", ref, execution.Status)
+		if execution.Finished() {
+			return execution, nil
+		}
+		if waited+pollInterval > timeout {
+			return execution, fmt.Errorf("timeout of %v reached while waiting for execution '%s' to finish (status: %s)", timeout, ref, execution.Status)
+		}
+		c.sleep(pollInterval)
+		waited += pollInterval
+	}
+}
+
+func decode(response *http.Response, result interface{}) error {
+	content, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(content, result)
+}
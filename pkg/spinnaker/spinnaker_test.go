@@ -0,0 +1,125 @@
+package spinnaker
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTriggerPipeline(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		var requestedURL, requestedMethod, requestedBody, contentType string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestedURL = r.URL.Path
+			requestedMethod = r.Method
+			contentType = r.Header.Get("Content-Type")
+			body, _ := ioutil.ReadAll(r.Body)
+			requestedBody = string(body)
+			w.Write([]byte(`{"ref": "/pipelines/01EXAMPLE"}`))
+		}))
+		defer server.Close()
+
+		client := NewClient(server.URL+"/", &piperhttp.Client{})
+		ref, err := client.TriggerPipeline("myApp", "deploy", map[string]interface{}{"version": "1.2.3"})
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, "/pipelines/01EXAMPLE", ref)
+			assert.Equal(t, "/pipelines/myApp/deploy", requestedURL)
+			assert.Equal(t, http.MethodPost, requestedMethod)
+			assert.Equal(t, "application/json", contentType)
+			assert.JSONEq(t, `{"parameters": {"version": "1.2.3"}}`, requestedBody)
+		}
+	})
+
+	t.Run("no reference returned", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{}`))
+		}))
+		defer server.Close()
+
+		client := NewClient(server.URL, &piperhttp.Client{})
+		_, err := client.TriggerPipeline("myApp", "deploy", nil)
+
+		assert.EqualError(t, err, "failed to trigger pipeline 'deploy' of application 'myApp': no execution reference returned")
+	})
+
+	t.Run("request fails", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+		}))
+		defer server.Close()
+
+		client := NewClient(server.URL, &piperhttp.Client{})
+		_, err := client.TriggerPipeline("myApp", "deploy", nil)
+
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "failed to trigger pipeline 'deploy' of application 'myApp'")
+		}
+	})
+}
+
+func TestWaitForExecution(t *testing.T) {
+	t.Run("polls until finished", func(t *testing.T) {
+		responses := []string{
+			`{"id": "01EXAMPLE", "status": "NOT_STARTED"}`,
+			`{"id": "01EXAMPLE", "status": "RUNNING", "stages": [{"name": "Deploy", "type": "deploy", "status": "RUNNING"}]}`,
+			`{"id": "01EXAMPLE", "status": "SUCCEEDED", "stages": [{"name": "Deploy", "type": "deploy", "status": "SUCCEEDED"}]}`,
+		}
+		calls := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/pipelines/01EXAMPLE", r.URL.Path)
+			w.Write([]byte(responses[calls]))
+			calls++
+		}))
+		defer server.Close()
+
+		sleeps := []time.Duration{}
+		client := NewClient(server.URL, &piperhttp.Client{})
+		client.sleep = func(d time.Duration) { sleeps = append(sleeps, d) }
+
+		execution, err := client.WaitForExecution("/pipelines/01EXAMPLE", time.Minute, 10*time.Second)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, 3, calls)
+			assert.Equal(t, []time.Duration{10 * time.Second, 10 * time.Second}, sleeps)
+			assert.True(t, execution.Succeeded())
+			assert.Equal(t, []Stage{{Name: "Deploy", Type: "deploy", Status: "SUCCEEDED"}}, execution.Stages)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"id": "01EXAMPLE", "status": "RUNNING"}`))
+		}))
+		defer server.Close()
+
+		client := NewClient(server.URL, &piperhttp.Client{})
+		client.sleep = func(time.Duration) {}
+
+		execution, err := client.WaitForExecution("/pipelines/01EXAMPLE", 30*time.Second, 10*time.Second)
+
+		assert.EqualError(t, err, "timeout of 30s reached while waiting for execution '/pipelines/01EXAMPLE' to finish (status: RUNNING)")
+		assert.Equal(t, StatusRunning, execution.Status)
+	})
+}
+
+func TestExecutionFinished(t *testing.T) {
+	for status, finished := range map[string]bool{
+		"NOT_STARTED": false,
+		"RUNNING":     false,
+		"PAUSED":      false,
+		"SUSPENDED":   false,
+		"BUFFERED":    false,
+		"SUCCEEDED":   true,
+		"TERMINAL":    true,
+		"CANCELED":    true,
+	} {
+		e := Execution{Status: status}
+		assert.Equal(t, finished, e.Finished(), status)
+	}
+}
@@ -0,0 +1,167 @@
+package cts
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/SAP/jenkins-library/pkg/command"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/pkg/errors"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Exec interface collecting everything which is execution related
+// and needed in the context of creating and releasing a transport request.
+type Exec interface {
+	command.ExecRunner
+	GetExitCode() int
+}
+
+// CreateAction Collects all the properties we need for creating a transport request
+type CreateAction struct {
+	Connection     Connection
+	TransportType  string
+	TargetSystemID string
+	Description    string
+	CMOpts         []string
+}
+
+// Create collects everything which is needed for creating a transport request
+type Create interface {
+	WithConnection(Connection)
+	WithTransportType(string)
+	WithTargetSystemID(string)
+	WithDescription(string)
+	WithCMOpts([]string)
+	Perform(command Exec) (string, error)
+}
+
+// WithConnection specifies all the connection details which
+// are required in order to connect to CTS
+func (a *CreateAction) WithConnection(c Connection) {
+	a.Connection = c
+}
+
+// WithTransportType specifies the type of the transport request, e.g. "W"
+func (a *CreateAction) WithTransportType(t string) {
+	a.TransportType = t
+}
+
+// WithTargetSystemID specifies the target system of the transport request
+func (a *CreateAction) WithTargetSystemID(id string) {
+	a.TargetSystemID = id
+}
+
+// WithDescription specifies the description of the transport request
+func (a *CreateAction) WithDescription(desc string) {
+	a.Description = desc
+}
+
+// WithCMOpts sets additional options for calling the
+// cm client tool. E.g. -D options. Useful for troubleshooting
+func (a *CreateAction) WithCMOpts(opts []string) {
+	a.CMOpts = opts
+}
+
+// Perform creates a new transport request
+func (a *CreateAction) Perform(command Exec) (string, error) {
+
+	log.Entry().Infof("Creating new transport request via '%s'.", a.Connection.Endpoint)
+
+	err := checkMissingParameters(map[string]string{
+		"Connection.Endpoint": a.Connection.Endpoint,
+		"Connection.User":     a.Connection.User,
+		"Connection.Password": a.Connection.Password,
+		"TransportType":       a.TransportType,
+		"TargetSystemID":      a.TargetSystemID,
+		"Description":         a.Description,
+	})
+
+	var transportRequestID string
+
+	if err == nil {
+		oldStdout := command.GetStdout()
+		defer func() {
+			command.Stdout(oldStdout)
+		}()
+
+		var cmClientStdout bytes.Buffer
+		w := io.MultiWriter(&cmClientStdout, oldStdout)
+		command.Stdout(w)
+
+		err = runCMClient(command, a.Connection, a.CMOpts,
+			"create-transport",
+			"-tt", a.TransportType,
+			"-ts", a.TargetSystemID,
+			"-d", a.Description,
+		)
+
+		if err == nil {
+			transportRequestID = strings.TrimSpace(cmClientStdout.String())
+		}
+	}
+
+	if err == nil {
+		log.Entry().Infof("Created transport request '%s' at '%s'. TransportType: '%s', TargetSystemId: '%s'",
+			transportRequestID,
+			a.Connection.Endpoint,
+			a.TransportType,
+			a.TargetSystemID,
+		)
+	} else {
+		log.Entry().WithError(err).Warnf("Creating transport request at '%s' failed. TransportType: '%s', TargetSystemId: '%s'",
+			a.Connection.Endpoint,
+			a.TransportType,
+			a.TargetSystemID,
+		)
+	}
+
+	return transportRequestID, errors.Wrap(err, "cannot create transport request")
+}
+
+func checkMissingParameters(params map[string]string) error {
+	missingParameters := []string{}
+	for name, value := range params {
+		if len(value) == 0 {
+			missingParameters = append(missingParameters, name)
+		}
+	}
+	if len(missingParameters) != 0 {
+		sort.Strings(missingParameters)
+		return fmt.Errorf("the following parameters are not available %s", missingParameters)
+	}
+	return nil
+}
+
+func runCMClient(command Exec, connection Connection, cmOpts []string, args ...string) error {
+
+	if len(cmOpts) > 0 {
+		command.SetEnv([]string{fmt.Sprintf("CMCLIENT_OPTS=%s", strings.Join(cmOpts, " "))})
+	}
+
+	params := []string{
+		"--endpoint", connection.Endpoint,
+		"--user", connection.User,
+		"--password", connection.Password,
+		"--backend-type", "CTS",
+	}
+	params = append(params, args...)
+
+	err := command.RunExecutable("cmclient", params...)
+
+	exitCode := command.GetExitCode()
+	if exitCode != 0 {
+		message := fmt.Sprintf("%s command returned with exit code '%d'", args[0], exitCode)
+		if err != nil {
+			// Using the wrapping here is to some extend an abuse, since it is not really
+			// error chaining (the other error is not necessaryly a "predecessor" of this one).
+			// But it is a pragmatic approach for not loosing information for trouble shooting. There
+			// is no possibility to have something like suppressed errors.
+			err = errors.Wrap(err, message)
+		} else {
+			err = errors.New(message)
+		}
+	}
+	return err
+}
@@ -0,0 +1,94 @@
+package cts
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCTSCreateTransportRequest(t *testing.T) {
+
+	a := CreateAction{
+		Connection: Connection{
+			Endpoint: "https://example.org/cts",
+			User:     "me",
+			Password: "******",
+		},
+		TransportType:  "W",
+		TargetSystemID: "XYZ",
+		Description:    "my transport",
+		CMOpts:         []string{"-Dprop1=abc", "-Dprop2=123"},
+	}
+
+	t.Run("straight forward", func(t *testing.T) {
+
+		e := getExecMock()
+		e.StdoutReturn = map[string]string{"^cmclient.*": "XYZK123456\n"}
+
+		examinee := a
+		transportRequestID, err := examinee.Perform(e)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, []mock.ExecCall{mock.ExecCall{
+				Exec: "cmclient",
+				Params: []string{
+					"--endpoint", "https://example.org/cts",
+					"--user", "me",
+					"--password", "******",
+					"--backend-type", "CTS",
+					"create-transport",
+					"-tt", "W",
+					"-ts", "XYZ",
+					"-d", "my transport",
+				},
+			}}, e.Calls)
+			assert.Equal(t, "XYZK123456", transportRequestID)
+			assert.Equal(t, []string{"CMCLIENT_OPTS=-Dprop1=abc -Dprop2=123"}, e.Env)
+		}
+	})
+
+	t.Run("fail with error", func(t *testing.T) {
+
+		e := getExecMock()
+		e.ShouldFailOnCommand = map[string]error{"^cmclient.*": fmt.Errorf("creating transport request failed")}
+
+		examinee := a
+		_, err := examinee.Perform(e)
+
+		assert.EqualError(t, err, "cannot create transport request: creating transport request failed")
+	})
+
+	t.Run("fail via return code", func(t *testing.T) {
+
+		e := getExecMock()
+		e.ExitCode = 42
+
+		examinee := a
+		_, err := examinee.Perform(e)
+
+		assert.EqualError(t, err, "cannot create transport request: create-transport command returned with exit code '42'")
+	})
+
+	t.Run("input missing", func(t *testing.T) {
+
+		e := getExecMock()
+
+		examinee := a
+		examinee.Connection = Connection{}
+		examinee.TargetSystemID = ""
+
+		_, err := examinee.Perform(e)
+
+		assert.EqualError(t, err, "cannot create transport request: the following parameters are not available [Connection.Endpoint Connection.Password Connection.User TargetSystemID]")
+		assert.Empty(t, e.Calls)
+	})
+}
+
+func getExecMock() *mock.ExecMockRunner {
+	var out bytes.Buffer
+	e := &mock.ExecMockRunner{}
+	e.Stdout(&out)
+	return e
+}
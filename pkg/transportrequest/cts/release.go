@@ -0,0 +1,67 @@
+package cts
+
+import (
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/pkg/errors"
+)
+
+// ReleaseAction Collects all the properties we need for releasing a transport request
+type ReleaseAction struct {
+	Connection         Connection
+	TransportRequestID string
+	CMOpts             []string
+}
+
+// Release collects everything which is needed for releasing a transport request
+type Release interface {
+	WithConnection(Connection)
+	WithTransportRequestID(string)
+	WithCMOpts([]string)
+	Perform(command Exec) error
+}
+
+// WithConnection specifies all the connection details which
+// are required in order to connect to CTS
+func (a *ReleaseAction) WithConnection(c Connection) {
+	a.Connection = c
+}
+
+// WithTransportRequestID specifies the transport request
+// which gets released.
+func (a *ReleaseAction) WithTransportRequestID(id string) {
+	a.TransportRequestID = id
+}
+
+// WithCMOpts sets additional options for calling the
+// cm client tool. E.g. -D options. Useful for troubleshooting
+func (a *ReleaseAction) WithCMOpts(opts []string) {
+	a.CMOpts = opts
+}
+
+// Perform releases (exports) a transport request
+func (a *ReleaseAction) Perform(command Exec) error {
+
+	log.Entry().Infof("Releasing transport request '%s' via '%s'.", a.TransportRequestID, a.Connection.Endpoint)
+
+	err := checkMissingParameters(map[string]string{
+		"Connection.Endpoint": a.Connection.Endpoint,
+		"Connection.User":     a.Connection.User,
+		"Connection.Password": a.Connection.Password,
+		"TransportRequestID":  a.TransportRequestID,
+	})
+
+	if err == nil {
+		err = runCMClient(command, a.Connection, a.CMOpts,
+			"export-transport",
+			"-tID", a.TransportRequestID,
+		)
+	}
+
+	if err == nil {
+		log.Entry().Infof("Released transport request '%s' at '%s'.", a.TransportRequestID, a.Connection.Endpoint)
+	} else {
+		log.Entry().WithError(err).Warnf("Releasing transport request '%s' at '%s' failed.", a.TransportRequestID, a.Connection.Endpoint)
+	}
+
+	return errors.Wrapf(err, "cannot release transport request '%s'", a.TransportRequestID)
+}
@@ -0,0 +1,78 @@
+package cts
+
+import (
+	"fmt"
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCTSReleaseTransportRequest(t *testing.T) {
+
+	a := ReleaseAction{
+		Connection: Connection{
+			Endpoint: "https://example.org/cts",
+			User:     "me",
+			Password: "******",
+		},
+		TransportRequestID: "XYZK123456",
+	}
+
+	t.Run("straight forward", func(t *testing.T) {
+
+		e := getExecMock()
+
+		examinee := a
+		err := examinee.Perform(e)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, []mock.ExecCall{mock.ExecCall{
+				Exec: "cmclient",
+				Params: []string{
+					"--endpoint", "https://example.org/cts",
+					"--user", "me",
+					"--password", "******",
+					"--backend-type", "CTS",
+					"export-transport",
+					"-tID", "XYZK123456",
+				},
+			}}, e.Calls)
+			assert.Empty(t, e.Env)
+		}
+	})
+
+	t.Run("fail with error", func(t *testing.T) {
+
+		e := getExecMock()
+		e.ShouldFailOnCommand = map[string]error{"^cmclient.*": fmt.Errorf("export failed")}
+
+		examinee := a
+		err := examinee.Perform(e)
+
+		assert.EqualError(t, err, "cannot release transport request 'XYZK123456': export failed")
+	})
+
+	t.Run("fail via return code", func(t *testing.T) {
+
+		e := getExecMock()
+		e.ExitCode = 1
+
+		examinee := a
+		err := examinee.Perform(e)
+
+		assert.EqualError(t, err, "cannot release transport request 'XYZK123456': export-transport command returned with exit code '1'")
+	})
+
+	t.Run("input missing", func(t *testing.T) {
+
+		e := getExecMock()
+
+		examinee := a
+		examinee.TransportRequestID = ""
+
+		err := examinee.Perform(e)
+
+		assert.EqualError(t, err, "cannot release transport request '': the following parameters are not available [TransportRequestID]")
+		assert.Empty(t, e.Calls)
+	})
+}
@@ -0,0 +1,110 @@
+package solman
+
+import (
+	"fmt"
+	"github.com/SAP/jenkins-library/pkg/config/validation"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/pkg/errors"
+	"strings"
+)
+
+// ReleaseAction Collects all the properties we need for releasing a transport request
+type ReleaseAction struct {
+	Connection         Connection
+	ChangeDocumentID   string
+	TransportRequestID string
+	CMOpts             []string
+}
+
+// Release collects everything which is needed for releasing a transport request
+type Release interface {
+	WithConnection(Connection)
+	WithChangeDocumentID(string)
+	WithTransportRequestID(string)
+	WithCMOpts([]string)
+	Perform(command Exec) error
+}
+
+// WithConnection specifies all the connection details which
+// are required in order to connect to SOLMAN
+func (a *ReleaseAction) WithConnection(c Connection) {
+	a.Connection = c
+}
+
+// WithChangeDocumentID specifies the change document which
+// contains the transport request.
+func (a *ReleaseAction) WithChangeDocumentID(id string) {
+	a.ChangeDocumentID = id
+}
+
+// WithTransportRequestID specifies the transport request
+// which gets released.
+func (a *ReleaseAction) WithTransportRequestID(id string) {
+	a.TransportRequestID = id
+}
+
+// WithCMOpts sets additional options for calling the
+// cm client tool. E.g. -D options. Useful for troubleshooting
+func (a *ReleaseAction) WithCMOpts(opts []string) {
+	a.CMOpts = opts
+}
+
+// Perform releases a transport request
+func (a *ReleaseAction) Perform(command Exec) error {
+
+	log.Entry().Infof("Releasing transport request '%s' via '%s'.", a.TransportRequestID, a.Connection.Endpoint)
+
+	missingParameters, err := validation.FindEmptyStringsInConfigStruct(*a)
+
+	if err == nil {
+		if len(missingParameters) != 0 {
+			err = fmt.Errorf("the following parameters are not available %s", missingParameters)
+		}
+	}
+
+	if err == nil {
+		if len(a.CMOpts) > 0 {
+			command.SetEnv([]string{fmt.Sprintf("CMCLIENT_OPTS=%s", strings.Join(a.CMOpts, " "))})
+		}
+
+		err = command.RunExecutable("cmclient",
+			"--endpoint", a.Connection.Endpoint,
+			"--user", a.Connection.User,
+			"--password", a.Connection.Password,
+			"--backend-type", "SOLMAN",
+			"release-transport",
+			"-cID", a.ChangeDocumentID,
+			"-tID", a.TransportRequestID,
+		)
+
+		exitCode := command.GetExitCode()
+		if exitCode != 0 {
+			message := fmt.Sprintf("release transport request command returned with exit code '%d'", exitCode)
+			if err != nil {
+				// Using the wrapping here is to some extend an abuse, since it is not really
+				// error chaining (the other error is not necessaryly a "predecessor" of this one).
+				// But it is a pragmatic approach for not loosing information for trouble shooting. There
+				// is no possibility to have something like suppressed errors.
+				err = errors.Wrap(err, message)
+			} else {
+				err = errors.New(message)
+			}
+		}
+	}
+
+	if err == nil {
+		log.Entry().Infof("Released transport request '%s' at '%s'. ChangeDocumentId: '%s'",
+			a.TransportRequestID,
+			a.Connection.Endpoint,
+			a.ChangeDocumentID,
+		)
+	} else {
+		log.Entry().WithError(err).Warnf("Releasing transport request '%s' at '%s' failed. ChangeDocumentId: '%s'",
+			a.TransportRequestID,
+			a.Connection.Endpoint,
+			a.ChangeDocumentID,
+		)
+	}
+
+	return errors.Wrapf(err, "cannot release transport request '%s'", a.TransportRequestID)
+}
@@ -0,0 +1,88 @@
+package solman
+
+import (
+	"fmt"
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSolmanReleaseTransportRequest(t *testing.T) {
+
+	a := ReleaseAction{
+		Connection: Connection{
+			Endpoint: "https://example.org/solman",
+			User:     "me",
+			Password: "******",
+		},
+		ChangeDocumentID:   "123",
+		TransportRequestID: "XXXK123456",
+		CMOpts:             []string{"-Dprop1=abc", "-Dprop2=123"},
+	}
+
+	t.Run("straight forward", func(t *testing.T) {
+
+		e := getExecMock()
+
+		examinee := a
+		err := examinee.Perform(e)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, []mock.ExecCall{mock.ExecCall{
+				Exec: "cmclient",
+				Params: []string{
+					"--endpoint", "https://example.org/solman",
+					"--user", "me",
+					"--password", "******",
+					"--backend-type", "SOLMAN",
+					"release-transport",
+					"-cID", "123",
+					"-tID", "XXXK123456",
+				},
+			}}, e.Calls)
+			assert.Equal(t, []string{"CMCLIENT_OPTS=-Dprop1=abc -Dprop2=123"}, e.Env)
+		}
+	})
+
+	t.Run("fail with error", func(t *testing.T) {
+
+		e := getExecMock()
+		e.ShouldFailOnCommand = map[string]error{"^cmclient.*": fmt.Errorf("releasing transport request failed")}
+
+		examinee := a
+		err := examinee.Perform(e)
+
+		assert.EqualError(t, err, "cannot release transport request 'XXXK123456': releasing transport request failed")
+	})
+
+	t.Run("fail via return code", func(t *testing.T) {
+
+		e := getExecMock()
+		e.ExitCode = 42
+
+		examinee := a
+		err := examinee.Perform(e)
+
+		assert.EqualError(t, err, "cannot release transport request 'XXXK123456': release transport request command returned with exit code '42'")
+	})
+
+	t.Run("input missing", func(t *testing.T) {
+
+		e := getExecMock()
+
+		examinee := a
+		examinee.Connection = Connection{}
+		examinee.TransportRequestID = ""
+
+		err := examinee.Perform(e)
+
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "the following parameters are not available")
+			assert.Contains(t, err.Error(), "Connection.Endpoint")
+			assert.Contains(t, err.Error(), "Connection.User")
+			assert.Contains(t, err.Error(), "Connection.Password")
+			assert.Contains(t, err.Error(), "TransportRequestID")
+			assert.Empty(t, e.Calls)
+		}
+	})
+}
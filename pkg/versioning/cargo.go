@@ -0,0 +1,115 @@
+package versioning
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	cargoSectionRegex = regexp.MustCompile(`^\s*\[([^\[\]]+)\]\s*(#.*)?$`)
+	cargoFieldRegex   = regexp.MustCompile(`^(\s*([A-Za-z0-9_.-]+)\s*=\s*")([^"]*)(".*)$`)
+)
+
+// Cargo defines an artifact using the Cargo.toml of a Rust crate for versioning
+type Cargo struct {
+	path      string
+	content   []string
+	readFile  func(string) ([]byte, error)
+	writeFile func(string, []byte, os.FileMode) error
+}
+
+func (c *Cargo) init() error {
+	if len(c.path) == 0 {
+		c.path = "Cargo.toml"
+	}
+	if c.readFile == nil {
+		c.readFile = ioutil.ReadFile
+	}
+	if c.writeFile == nil {
+		c.writeFile = ioutil.WriteFile
+	}
+	if c.content == nil {
+		content, err := c.readFile(c.path)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read file '%v'", c.path)
+		}
+		c.content = strings.Split(string(content), "\n")
+	}
+	return nil
+}
+
+// field returns the line index and value of a field of the [package] section.
+// For workspaces the [workspace.package] section is considered.
+func (c *Cargo) field(name string) (int, string, error) {
+	if err := c.init(); err != nil {
+		return -1, "", err
+	}
+	for _, section := range []string{"package", "workspace.package"} {
+		current := ""
+		for i, line := range c.content {
+			if match := cargoSectionRegex.FindStringSubmatch(line); match != nil {
+				current = strings.TrimSpace(match[1])
+				continue
+			}
+			if current != section {
+				continue
+			}
+			if match := cargoFieldRegex.FindStringSubmatch(line); match != nil && match[2] == name {
+				return i, match[3], nil
+			}
+			if strings.HasPrefix(strings.TrimSpace(line), name+".workspace") {
+				return -1, "", fmt.Errorf("%v of '%v' is inherited from the workspace", name, c.path)
+			}
+		}
+	}
+	return -1, "", fmt.Errorf("no %v available in '%v'", name, c.path)
+}
+
+// VersioningScheme returns the relevant versioning scheme
+func (c *Cargo) VersioningScheme() string {
+	// Cargo requires versions to follow SemVer 2
+	return "semver2"
+}
+
+// GetVersion returns the current version of the crate
+func (c *Cargo) GetVersion() (string, error) {
+	_, version, err := c.field("version")
+	if err != nil {
+		return "", errors.Wrap(err, "failed to retrieve version")
+	}
+	return version, nil
+}
+
+// SetVersion updates the version of the crate.
+// The Cargo.toml is updated in place in order to keep formatting and comments.
+func (c *Cargo) SetVersion(version string) error {
+	index, _, err := c.field("version")
+	if err != nil {
+		return errors.Wrap(err, "failed to set version")
+	}
+	c.content[index] = cargoFieldRegex.ReplaceAllString(c.content[index], "${1}"+version+"${4}")
+
+	if err := c.writeFile(c.path, []byte(strings.Join(c.content, "\n")), 0644); err != nil {
+		return errors.Wrapf(err, "failed to write file '%v'", c.path)
+	}
+	return nil
+}
+
+// GetCoordinates returns the coordinates
+func (c *Cargo) GetCoordinates() (Coordinates, error) {
+	result := Coordinates{}
+	var err error
+	result.Version, err = c.GetVersion()
+	if err != nil {
+		return result, err
+	}
+	// a workspace does not necessarily have a name
+	_, result.ArtifactID, _ = c.field("name")
+	result.Packaging = "crate"
+	return result, nil
+}
@@ -0,0 +1,105 @@
+package versioning
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testCargo = `[package]
+name = "my-crate"
+version = "0.3.1" # released version
+edition = "2018"
+
+[dependencies]
+serde = { version = "1.0", features = ["derive"] }
+log = "0.4"
+`
+
+func TestCargoGetVersion(t *testing.T) {
+	t.Run("success case", func(t *testing.T) {
+		cargo := Cargo{
+			path:     "Cargo.toml",
+			readFile: func(filename string) ([]byte, error) { return []byte(testCargo), nil },
+		}
+		version, err := cargo.GetVersion()
+		assert.NoError(t, err)
+		assert.Equal(t, "0.3.1", version)
+	})
+
+	t.Run("success case - workspace", func(t *testing.T) {
+		cargo := Cargo{
+			path: "Cargo.toml",
+			readFile: func(filename string) ([]byte, error) {
+				return []byte("[workspace]\nmembers = [\"a\"]\n\n[workspace.package]\nversion = \"1.0.0\"\n"), nil
+			},
+		}
+		version, err := cargo.GetVersion()
+		assert.NoError(t, err)
+		assert.Equal(t, "1.0.0", version)
+	})
+
+	t.Run("error case - inherited version", func(t *testing.T) {
+		cargo := Cargo{
+			path: "a/Cargo.toml",
+			readFile: func(filename string) ([]byte, error) {
+				return []byte("[package]\nname = \"a\"\nversion.workspace = true\n"), nil
+			},
+		}
+		_, err := cargo.GetVersion()
+		assert.EqualError(t, err, "failed to retrieve version: version of 'a/Cargo.toml' is inherited from the workspace")
+	})
+
+	t.Run("error case - read error", func(t *testing.T) {
+		cargo := Cargo{
+			path:     "Cargo.toml",
+			readFile: func(filename string) ([]byte, error) { return nil, fmt.Errorf("read error") },
+		}
+		_, err := cargo.GetVersion()
+		assert.EqualError(t, err, "failed to retrieve version: failed to read file 'Cargo.toml': read error")
+	})
+}
+
+func TestCargoSetVersion(t *testing.T) {
+	t.Run("success case", func(t *testing.T) {
+		var content []byte
+		cargo := Cargo{
+			path:      "Cargo.toml",
+			readFile:  func(filename string) ([]byte, error) { return []byte(testCargo), nil },
+			writeFile: func(filename string, filecontent []byte, mode os.FileMode) error { content = filecontent; return nil },
+		}
+		err := cargo.SetVersion("0.4.0")
+		assert.NoError(t, err)
+		assert.Equal(t, `[package]
+name = "my-crate"
+version = "0.4.0" # released version
+edition = "2018"
+
+[dependencies]
+serde = { version = "1.0", features = ["derive"] }
+log = "0.4"
+`, string(content))
+	})
+
+	t.Run("error case", func(t *testing.T) {
+		cargo := Cargo{
+			path:      "Cargo.toml",
+			readFile:  func(filename string) ([]byte, error) { return []byte(testCargo), nil },
+			writeFile: func(filename string, filecontent []byte, mode os.FileMode) error { return fmt.Errorf("write error") },
+		}
+		err := cargo.SetVersion("0.4.0")
+		assert.EqualError(t, err, "failed to write file 'Cargo.toml': write error")
+	})
+}
+
+func TestCargoGetCoordinates(t *testing.T) {
+	cargo := Cargo{
+		path:     "Cargo.toml",
+		readFile: func(filename string) ([]byte, error) { return []byte(testCargo), nil },
+	}
+	coordinates, err := cargo.GetCoordinates()
+	assert.NoError(t, err)
+	assert.Equal(t, Coordinates{ArtifactID: "my-crate", Version: "0.3.1", Packaging: "crate"}, coordinates)
+}
@@ -0,0 +1,43 @@
+package versioning
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Composer defines an artifact using the composer.json of a PHP package for versioning
+type Composer struct {
+	JSONfile
+}
+
+// GetVersion returns the current version of the package
+func (c *Composer) GetVersion() (string, error) {
+	version, err := c.JSONfile.GetVersion()
+	if err != nil {
+		return "", err
+	}
+	if _, ok := c.content[c.versionField]; !ok {
+		// Packagist recommends to omit the version and to derive it from tags
+		return "", fmt.Errorf("no version available in '%v'", c.path)
+	}
+	return version, nil
+}
+
+// GetCoordinates returns the coordinates
+func (c *Composer) GetCoordinates() (Coordinates, error) {
+	result := Coordinates{}
+	var err error
+	result.Version, err = c.GetVersion()
+	if err != nil {
+		return result, err
+	}
+	// package names consist of vendor name and project name
+	name := fmt.Sprint(c.content["name"])
+	if parts := strings.SplitN(name, "/", 2); len(parts) == 2 {
+		result.GroupID, result.ArtifactID = parts[0], parts[1]
+	} else {
+		result.ArtifactID = name
+	}
+	result.Packaging = "composer"
+	return result, nil
+}
@@ -0,0 +1,38 @@
+package versioning
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComposerGetVersion(t *testing.T) {
+	t.Run("success case", func(t *testing.T) {
+		composer := Composer{JSONfile: JSONfile{
+			path:     "composer.json",
+			readFile: func(filename string) ([]byte, error) { return []byte(`{"name": "acme/tool", "version": "1.2.3"}`), nil },
+		}}
+		version, err := composer.GetVersion()
+		assert.NoError(t, err)
+		assert.Equal(t, "1.2.3", version)
+	})
+
+	t.Run("error case - no version", func(t *testing.T) {
+		composer := Composer{JSONfile: JSONfile{
+			path:     "composer.json",
+			readFile: func(filename string) ([]byte, error) { return []byte(`{"name": "acme/tool"}`), nil },
+		}}
+		_, err := composer.GetVersion()
+		assert.EqualError(t, err, "no version available in 'composer.json'")
+	})
+}
+
+func TestComposerGetCoordinates(t *testing.T) {
+	composer := Composer{JSONfile: JSONfile{
+		path:     "composer.json",
+		readFile: func(filename string) ([]byte, error) { return []byte(`{"name": "acme/tool", "version": "1.2.3"}`), nil },
+	}}
+	coordinates, err := composer.GetCoordinates()
+	assert.NoError(t, err)
+	assert.Equal(t, Coordinates{GroupID: "acme", ArtifactID: "tool", Version: "1.2.3", Packaging: "composer"}, coordinates)
+}
@@ -0,0 +1,140 @@
+package versioning
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// VersionIncrement defines which part of a semantic version needs to be incremented
+type VersionIncrement int
+
+const (
+	// IncrementNone keeps the version as it is
+	IncrementNone VersionIncrement = iota
+	// IncrementPatch increments the patch version, e.g. for bug fixes
+	IncrementPatch
+	// IncrementMinor increments the minor version, e.g. for new features
+	IncrementMinor
+	// IncrementMajor increments the major version, e.g. for breaking changes
+	IncrementMajor
+)
+
+func (i VersionIncrement) String() string {
+	switch i {
+	case IncrementPatch:
+		return "patch"
+	case IncrementMinor:
+		return "minor"
+	case IncrementMajor:
+		return "major"
+	}
+	return "none"
+}
+
+// SemanticVersion represents the <major>.<minor>.<patch> part of a version according to https://semver.org
+type SemanticVersion struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+var semanticVersionPattern = regexp.MustCompile(`^v?(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(?:[-+.].*)?$`)
+
+// ParseSemanticVersion parses the <major>.<minor>.<patch> part of a version, pre-release and build information are ignored
+func ParseSemanticVersion(version string) (SemanticVersion, error) {
+	match := semanticVersionPattern.FindStringSubmatch(strings.TrimSpace(version))
+	if match == nil {
+		return SemanticVersion{}, fmt.Errorf("version '%s' is not a semantic version", version)
+	}
+	// the pattern makes sure that the conversion does not fail
+	major, _ := strconv.Atoi(match[1])
+	minor, _ := strconv.Atoi(match[2])
+	patch, _ := strconv.Atoi(match[3])
+	return SemanticVersion{Major: major, Minor: minor, Patch: patch}, nil
+}
+
+// Less returns true in case the version is lower than the other version
+func (v SemanticVersion) Less(other SemanticVersion) bool {
+	if v.Major != other.Major {
+		return v.Major < other.Major
+	}
+	if v.Minor != other.Minor {
+		return v.Minor < other.Minor
+	}
+	return v.Patch < other.Patch
+}
+
+// Increment returns the version incremented according to the given increment
+func (v SemanticVersion) Increment(increment VersionIncrement) SemanticVersion {
+	switch increment {
+	case IncrementMajor:
+		return SemanticVersion{Major: v.Major + 1}
+	case IncrementMinor:
+		return SemanticVersion{Major: v.Major, Minor: v.Minor + 1}
+	case IncrementPatch:
+		return SemanticVersion{Major: v.Major, Minor: v.Minor, Patch: v.Patch + 1}
+	}
+	return v
+}
+
+func (v SemanticVersion) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// ConventionalCommit is a commit message following the format of https://www.conventionalcommits.org
+type ConventionalCommit struct {
+	// Type is lower case, e.g. feat or fix
+	Type        string
+	Scope       string
+	Description string
+	Breaking    bool
+}
+
+var conventionalCommitHeader = regexp.MustCompile(`^(\w+)(?:\(([^)]*)\))?(!)?:\s+(.+)$`)
+var breakingChangeFooter = regexp.MustCompile(`(?m)^BREAKING[ -]CHANGE:\s`)
+
+// ParseConventionalCommit parses the commit message, false is returned in case it does not follow the Conventional Commits format
+func ParseConventionalCommit(message string) (ConventionalCommit, bool) {
+	header := strings.TrimSpace(strings.SplitN(message, "\n", 2)[0])
+	match := conventionalCommitHeader.FindStringSubmatch(header)
+	if match == nil {
+		return ConventionalCommit{}, false
+	}
+	return ConventionalCommit{
+		Type:        strings.ToLower(match[1]),
+		Scope:       match[2],
+		Description: strings.TrimSpace(match[4]),
+		Breaking:    match[3] == "!" || breakingChangeFooter.MatchString(message),
+	}, true
+}
+
+// ConventionalCommitIncrement classifies a commit message according to https://www.conventionalcommits.org
+func ConventionalCommitIncrement(message string) VersionIncrement {
+	commit, ok := ParseConventionalCommit(message)
+	if !ok {
+		return IncrementNone
+	}
+	if commit.Breaking {
+		return IncrementMajor
+	}
+	switch commit.Type {
+	case "feat":
+		return IncrementMinor
+	case "fix":
+		return IncrementPatch
+	}
+	return IncrementNone
+}
+
+// ConventionalCommitsIncrement returns the highest increment required by the given commit messages
+func ConventionalCommitsIncrement(messages []string) VersionIncrement {
+	increment := IncrementNone
+	for _, message := range messages {
+		if commitIncrement := ConventionalCommitIncrement(message); commitIncrement > increment {
+			increment = commitIncrement
+		}
+	}
+	return increment
+}
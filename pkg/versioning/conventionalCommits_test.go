@@ -0,0 +1,97 @@
+package versioning
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSemanticVersion(t *testing.T) {
+	tt := []struct {
+		version  string
+		expected SemanticVersion
+		err      string
+	}{
+		{version: "1.2.3", expected: SemanticVersion{1, 2, 3}},
+		{version: "v10.0.1", expected: SemanticVersion{10, 0, 1}},
+		{version: "1.2.3-SNAPSHOT", expected: SemanticVersion{1, 2, 3}},
+		{version: "1.2.3+build.4", expected: SemanticVersion{1, 2, 3}},
+		{version: "1.2", err: "version '1.2' is not a semantic version"},
+		{version: "01.2.3", err: "version '01.2.3' is not a semantic version"},
+	}
+
+	for _, test := range tt {
+		version, err := ParseSemanticVersion(test.version)
+		if len(test.err) > 0 {
+			assert.EqualError(t, err, test.err)
+		} else if assert.NoError(t, err, test.version) {
+			assert.Equal(t, test.expected, version)
+		}
+	}
+}
+
+func TestSemanticVersion(t *testing.T) {
+	v := SemanticVersion{1, 2, 3}
+
+	assert.Equal(t, "2.0.0", v.Increment(IncrementMajor).String())
+	assert.Equal(t, "1.3.0", v.Increment(IncrementMinor).String())
+	assert.Equal(t, "1.2.4", v.Increment(IncrementPatch).String())
+	assert.Equal(t, "1.2.3", v.Increment(IncrementNone).String())
+
+	assert.True(t, v.Less(SemanticVersion{1, 10, 0}))
+	assert.True(t, v.Less(SemanticVersion{2, 0, 0}))
+	assert.False(t, v.Less(SemanticVersion{1, 2, 3}))
+	assert.False(t, v.Less(SemanticVersion{1, 2, 2}))
+}
+
+func TestConventionalCommitIncrement(t *testing.T) {
+	tt := []struct {
+		message  string
+		expected VersionIncrement
+	}{
+		{message: "feat: add semantic versioning", expected: IncrementMinor},
+		{message: "feat(versioning): add semantic versioning", expected: IncrementMinor},
+		{message: "fix: correct tag prefix", expected: IncrementPatch},
+		{message: "Fix(git): correct tag prefix", expected: IncrementPatch},
+		{message: "feat!: drop support for cloud_noTag", expected: IncrementMajor},
+		{message: "refactor(api)!: rename parameters", expected: IncrementMajor},
+		{message: "fix: correct tag prefix\n\nBREAKING CHANGE: tags are now prefixed with v", expected: IncrementMajor},
+		{message: "fix: correct tag prefix\n\nBREAKING-CHANGE: tags are now prefixed with v", expected: IncrementMajor},
+		{message: "docs: describe semantic versioning", expected: IncrementNone},
+		{message: "chore: update dependencies", expected: IncrementNone},
+		{message: "Update README.md", expected: IncrementNone},
+		{message: "feature: no conventional type", expected: IncrementNone},
+	}
+
+	for _, test := range tt {
+		assert.Equal(t, test.expected, ConventionalCommitIncrement(test.message), test.message)
+	}
+}
+
+func TestConventionalCommitsIncrement(t *testing.T) {
+	assert.Equal(t, IncrementNone, ConventionalCommitsIncrement([]string{}))
+	assert.Equal(t, IncrementPatch, ConventionalCommitsIncrement([]string{"docs: readme", "fix: bug"}))
+	assert.Equal(t, IncrementMinor, ConventionalCommitsIncrement([]string{"fix: bug", "feat: feature", "chore: deps"}))
+	assert.Equal(t, IncrementMajor, ConventionalCommitsIncrement([]string{"feat!: feature", "fix: bug"}))
+}
+
+func TestParseConventionalCommit(t *testing.T) {
+	tt := []struct {
+		message  string
+		expected ConventionalCommit
+		ok       bool
+	}{
+		{message: "feat: new feature", expected: ConventionalCommit{Type: "feat", Description: "new feature"}, ok: true},
+		{message: "Fix(parser): handle empty input\n\nbody", expected: ConventionalCommit{Type: "fix", Scope: "parser", Description: "handle empty input"}, ok: true},
+		{message: "refactor!: drop old API", expected: ConventionalCommit{Type: "refactor", Description: "drop old API", Breaking: true}, ok: true},
+		{message: "feat: new config\n\nBREAKING CHANGE: removed option", expected: ConventionalCommit{Type: "feat", Description: "new config", Breaking: true}, ok: true},
+		{message: "Update README", ok: false},
+		{message: "Merge branch 'feature: x'", ok: false},
+	}
+
+	for _, test := range tt {
+		commit, ok := ParseConventionalCommit(test.message)
+		assert.Equal(t, test.ok, ok, test.message)
+		assert.Equal(t, test.expected, commit, test.message)
+	}
+}
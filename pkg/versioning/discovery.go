@@ -0,0 +1,106 @@
+package versioning
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Descriptor references the build descriptor of an artifact
+type Descriptor struct {
+	BuildTool string
+	FilePath  string
+}
+
+// discoverableDescriptors maps the file names of build descriptors to the build tool they belong to
+var discoverableDescriptors = map[string]string{
+	"pom.xml":       "maven",
+	"package.json":  "npm",
+	"mta.yaml":      "mta",
+	"setup.py":      "pip",
+	"dub.json":      "dub",
+	"go.mod":        "golang",
+	"Chart.yaml":    "helm",
+	"Cargo.toml":    "cargo",
+	"composer.json": "composer",
+}
+
+// nestedDescriptorDirectories maps the build tools whose nested descriptors are versioned together with their parent
+// to the directory (relative to the parent descriptor) containing them, e.g. maven modules and helm subcharts.
+// Nested descriptors of other build tools (e.g. npm workspaces) describe artifacts of their own.
+var nestedDescriptorDirectories = map[string]string{
+	"maven": "",
+	"helm":  "charts/",
+}
+
+// directories which contain dependencies or build results and thus no descriptors of own artifacts
+var ignoredDirectories = []string{"node_modules", "target", "vendor", ".git", ".pipeline"}
+
+// DiscoverDescriptors searches for build descriptors in the current directory and all its subdirectories.
+// Descriptors of maven modules and helm subcharts are not returned since they are versioned together with their parent.
+func DiscoverDescriptors(glob func(pattern string) ([]string, error)) ([]Descriptor, error) {
+	paths := []string{}
+	for name := range discoverableDescriptors {
+		matches, err := glob(filepath.Join("**", name))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to search for '%v'", name)
+		}
+		paths = append(paths, matches...)
+	}
+	// parent directories need to be processed before their subdirectories
+	sort.Slice(paths, func(i, j int) bool {
+		depthI, depthJ := strings.Count(filepath.ToSlash(paths[i]), "/"), strings.Count(filepath.ToSlash(paths[j]), "/")
+		if depthI != depthJ {
+			return depthI < depthJ
+		}
+		return paths[i] < paths[j]
+	})
+
+	descriptors := []Descriptor{}
+	directories := map[string][]string{}
+	for _, path := range paths {
+		path = filepath.ToSlash(filepath.Clean(path))
+		if isIgnored(path) {
+			continue
+		}
+		buildTool := discoverableDescriptors[filepath.Base(path)]
+		nestedDirectory, versionedWithParent := nestedDescriptorDirectories[buildTool]
+		if versionedWithParent {
+			if hasParentDescriptor(path, directories[buildTool], nestedDirectory) {
+				continue
+			}
+			directories[buildTool] = append(directories[buildTool], filepath.ToSlash(filepath.Dir(path)))
+		}
+		descriptors = append(descriptors, Descriptor{BuildTool: buildTool, FilePath: path})
+	}
+	sort.Slice(descriptors, func(i, j int) bool { return descriptors[i].FilePath < descriptors[j].FilePath })
+	return descriptors, nil
+}
+
+func isIgnored(path string) bool {
+	for _, part := range strings.Split(filepath.ToSlash(filepath.Dir(path)), "/") {
+		for _, ignored := range ignoredDirectories {
+			if part == ignored {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasParentDescriptor checks whether the path is located in the nested directory of one of the parent directories
+func hasParentDescriptor(path string, directories []string, nestedDirectory string) bool {
+	for _, dir := range directories {
+		prefix := dir + "/" + nestedDirectory
+		if dir == "." {
+			// the descriptor in the root directory is the parent of all descriptors in its nested directory
+			prefix = nestedDirectory
+		}
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package versioning
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiscoverDescriptors(t *testing.T) {
+	t.Run("monorepo", func(t *testing.T) {
+		files := mock.FilesMock{}
+		files.AddFile("backend/pom.xml", []byte{})
+		files.AddFile("backend/module/pom.xml", []byte{})
+		files.AddFile("backend/target/classes/pom.xml", []byte{})
+		files.AddFile("ui/package.json", []byte{})
+		files.AddFile("ui/node_modules/lib/package.json", []byte{})
+		files.AddFile("tools/go.mod", []byte{})
+		files.AddFile("deploy/chart/Chart.yaml", []byte{})
+		files.AddFile("deploy/chart/charts/db/Chart.yaml", []byte{})
+		files.AddFile("README.md", []byte{})
+
+		descriptors, err := DiscoverDescriptors(files.Glob)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []Descriptor{
+			{BuildTool: "maven", FilePath: "backend/pom.xml"},
+			{BuildTool: "helm", FilePath: "deploy/chart/Chart.yaml"},
+			{BuildTool: "golang", FilePath: "tools/go.mod"},
+			{BuildTool: "npm", FilePath: "ui/package.json"},
+		}, descriptors)
+	})
+
+	t.Run("descriptor in root directory", func(t *testing.T) {
+		files := mock.FilesMock{}
+		files.AddFile("mta.yaml", []byte{})
+		files.AddFile("pom.xml", []byte{})
+		files.AddFile("srv/pom.xml", []byte{})
+		files.AddFile("app/package.json", []byte{})
+
+		descriptors, err := DiscoverDescriptors(files.Glob)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []Descriptor{
+			{BuildTool: "npm", FilePath: "app/package.json"},
+			{BuildTool: "mta", FilePath: "mta.yaml"},
+			{BuildTool: "maven", FilePath: "pom.xml"},
+		}, descriptors)
+	})
+
+	t.Run("nested descriptors with own version", func(t *testing.T) {
+		files := mock.FilesMock{}
+		files.AddFile("package.json", []byte{})
+		files.AddFile("packages/core/package.json", []byte{})
+		files.AddFile("packages/ui/package.json", []byte{})
+		files.AddFile("go.mod", []byte{})
+		files.AddFile("svc/go.mod", []byte{})
+		files.AddFile("Chart.yaml", []byte{})
+		files.AddFile("charts/db/Chart.yaml", []byte{})
+		files.AddFile("deploy/Chart.yaml", []byte{})
+
+		descriptors, err := DiscoverDescriptors(files.Glob)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []Descriptor{
+			{BuildTool: "helm", FilePath: "Chart.yaml"},
+			{BuildTool: "helm", FilePath: "deploy/Chart.yaml"},
+			{BuildTool: "golang", FilePath: "go.mod"},
+			{BuildTool: "npm", FilePath: "package.json"},
+			{BuildTool: "npm", FilePath: "packages/core/package.json"},
+			{BuildTool: "npm", FilePath: "packages/ui/package.json"},
+			{BuildTool: "golang", FilePath: "svc/go.mod"},
+		}, descriptors)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		_, err := DiscoverDescriptors(func(pattern string) ([]string, error) { return nil, fmt.Errorf("glob error") })
+
+		assert.Contains(t, fmt.Sprint(err), "glob error")
+	})
+}
@@ -0,0 +1,114 @@
+package versioning
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// DotNet defines an artifact using an MSBuild project file (e.g. *.csproj or Directory.Build.props) for versioning
+type DotNet struct {
+	path      string
+	content   string
+	readFile  func(string) ([]byte, error)
+	writeFile func(string, []byte, os.FileMode) error
+}
+
+func (d *DotNet) init() error {
+	if d.readFile == nil {
+		d.readFile = ioutil.ReadFile
+	}
+	if d.writeFile == nil {
+		d.writeFile = ioutil.WriteFile
+	}
+	if len(d.content) == 0 {
+		content, err := d.readFile(d.path)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read file '%v'", d.path)
+		}
+		d.content = string(content)
+	}
+	return nil
+}
+
+func dotNetPropertyRegex(property string) *regexp.Regexp {
+	return regexp.MustCompile(`(<` + property + `>)\s*([^<]*?)\s*(</` + property + `>)`)
+}
+
+// versionProperty returns the property carrying the version.
+// Besides <Version> also <VersionPrefix> is supported which is combined with a <VersionSuffix> by MSBuild.
+func (d *DotNet) versionProperty() (string, string, error) {
+	if err := d.init(); err != nil {
+		return "", "", err
+	}
+	for _, property := range []string{"Version", "VersionPrefix"} {
+		if match := dotNetPropertyRegex(property).FindStringSubmatch(d.content); match != nil {
+			return property, match[2], nil
+		}
+	}
+	return "", "", fmt.Errorf("no <Version> or <VersionPrefix> available in '%v'", d.path)
+}
+
+// VersioningScheme returns the relevant versioning scheme
+func (d *DotNet) VersioningScheme() string {
+	// NuGet supports SemVer 2 since NuGet 4.3
+	return "semver2"
+}
+
+// GetVersion returns the current version of the project
+func (d *DotNet) GetVersion() (string, error) {
+	_, version, err := d.versionProperty()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to retrieve version")
+	}
+	return version, nil
+}
+
+// SetVersion updates the version of the project
+func (d *DotNet) SetVersion(version string) error {
+	property, _, err := d.versionProperty()
+	if err != nil {
+		return errors.Wrap(err, "failed to set version")
+	}
+	replaced := false
+	d.content = dotNetPropertyRegex(property).ReplaceAllStringFunc(d.content, func(match string) string {
+		// only the first occurrence carries the version, later ones are usually conditional
+		if replaced {
+			return match
+		}
+		replaced = true
+		return fmt.Sprintf("<%v>%v</%v>", property, version, property)
+	})
+
+	if err := d.writeFile(d.path, []byte(d.content), 0644); err != nil {
+		return errors.Wrapf(err, "failed to write file '%v'", d.path)
+	}
+	return nil
+}
+
+// GetCoordinates returns the coordinates
+func (d *DotNet) GetCoordinates() (Coordinates, error) {
+	result := Coordinates{}
+	var err error
+	result.Version, err = d.GetVersion()
+	if err != nil {
+		return result, err
+	}
+	for _, property := range []string{"PackageId", "AssemblyName"} {
+		if match := dotNetPropertyRegex(property).FindStringSubmatch(d.content); match != nil {
+			result.ArtifactID = match[2]
+			break
+		}
+	}
+	if len(result.ArtifactID) == 0 && strings.HasSuffix(d.path, "proj") {
+		// the package id defaults to the name of the project
+		result.ArtifactID = strings.TrimSuffix(filepath.Base(d.path), filepath.Ext(d.path))
+	}
+	result.Packaging = "nupkg"
+	return result, nil
+}
@@ -0,0 +1,104 @@
+package versioning
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testCsproj = `<Project Sdk="Microsoft.NET.Sdk">
+  <PropertyGroup>
+    <TargetFramework>net5.0</TargetFramework>
+    <Version>1.4.0</Version>
+  </PropertyGroup>
+</Project>
+`
+
+func TestDotNetGetVersion(t *testing.T) {
+	t.Run("success case - Version", func(t *testing.T) {
+		dotnet := DotNet{
+			path:     "MyApp.csproj",
+			readFile: func(filename string) ([]byte, error) { return []byte(testCsproj), nil },
+		}
+		version, err := dotnet.GetVersion()
+		assert.NoError(t, err)
+		assert.Equal(t, "1.4.0", version)
+	})
+
+	t.Run("success case - VersionPrefix", func(t *testing.T) {
+		dotnet := DotNet{
+			path: "Directory.Build.props",
+			readFile: func(filename string) ([]byte, error) {
+				return []byte("<Project><PropertyGroup><VersionPrefix>2.0.0</VersionPrefix></PropertyGroup></Project>"), nil
+			},
+		}
+		version, err := dotnet.GetVersion()
+		assert.NoError(t, err)
+		assert.Equal(t, "2.0.0", version)
+	})
+
+	t.Run("error case", func(t *testing.T) {
+		dotnet := DotNet{
+			path:     "MyApp.csproj",
+			readFile: func(filename string) ([]byte, error) { return []byte("<Project></Project>"), nil },
+		}
+		_, err := dotnet.GetVersion()
+		assert.EqualError(t, err, "failed to retrieve version: no <Version> or <VersionPrefix> available in 'MyApp.csproj'")
+	})
+}
+
+func TestDotNetSetVersion(t *testing.T) {
+	t.Run("success case", func(t *testing.T) {
+		var content []byte
+		dotnet := DotNet{
+			path:      "MyApp.csproj",
+			readFile:  func(filename string) ([]byte, error) { return []byte(testCsproj), nil },
+			writeFile: func(filename string, filecontent []byte, mode os.FileMode) error { content = filecontent; return nil },
+		}
+		err := dotnet.SetVersion("1.5.0-20210301")
+		assert.NoError(t, err)
+		assert.Equal(t, `<Project Sdk="Microsoft.NET.Sdk">
+  <PropertyGroup>
+    <TargetFramework>net5.0</TargetFramework>
+    <Version>1.5.0-20210301</Version>
+  </PropertyGroup>
+</Project>
+`, string(content))
+	})
+
+	t.Run("error case", func(t *testing.T) {
+		dotnet := DotNet{
+			path:      "MyApp.csproj",
+			readFile:  func(filename string) ([]byte, error) { return []byte(testCsproj), nil },
+			writeFile: func(filename string, filecontent []byte, mode os.FileMode) error { return fmt.Errorf("write error") },
+		}
+		err := dotnet.SetVersion("1.5.0")
+		assert.EqualError(t, err, "failed to write file 'MyApp.csproj': write error")
+	})
+}
+
+func TestDotNetGetCoordinates(t *testing.T) {
+	t.Run("project name", func(t *testing.T) {
+		dotnet := DotNet{
+			path:     "src/MyApp.csproj",
+			readFile: func(filename string) ([]byte, error) { return []byte(testCsproj), nil },
+		}
+		coordinates, err := dotnet.GetCoordinates()
+		assert.NoError(t, err)
+		assert.Equal(t, Coordinates{ArtifactID: "MyApp", Version: "1.4.0", Packaging: "nupkg"}, coordinates)
+	})
+
+	t.Run("package id", func(t *testing.T) {
+		dotnet := DotNet{
+			path: "src/MyApp.csproj",
+			readFile: func(filename string) ([]byte, error) {
+				return []byte("<Project><PackageId>Company.MyApp</PackageId><Version>1.0.0</Version></Project>"), nil
+			},
+		}
+		coordinates, err := dotnet.GetCoordinates()
+		assert.NoError(t, err)
+		assert.Equal(t, "Company.MyApp", coordinates.ArtifactID)
+	})
+}
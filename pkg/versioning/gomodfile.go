@@ -3,6 +3,7 @@ package versioning
 import (
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"golang.org/x/mod/modfile"
@@ -41,7 +42,9 @@ func (m *GoMod) GetVersion() (string, error) {
 	buildDescriptorFilePath := m.path
 	var err error
 	if strings.Contains(m.path, "go.mod") {
-		buildDescriptorFilePath, err = searchDescriptor([]string{"version.txt", "VERSION"}, m.fileExists)
+		// a version file is expected next to the go.mod file
+		dir := filepath.Dir(m.path)
+		buildDescriptorFilePath, err = searchDescriptor([]string{filepath.Join(dir, "version.txt"), filepath.Join(dir, "VERSION")}, m.fileExists)
 		if err != nil {
 			err = m.init()
 			if err != nil {
@@ -0,0 +1,150 @@
+package versioning
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+)
+
+// HelmChart defines an artifact using the Chart.yaml of a Helm chart for versioning
+type HelmChart struct {
+	path             string
+	content          []byte
+	updateAppVersion bool
+	readFile         func(string) ([]byte, error)
+	writeFile        func(string, []byte, os.FileMode) error
+}
+
+// helmChartMetadata contains the relevant fields of a Chart.yaml
+type helmChartMetadata struct {
+	Name       string `json:"name"`
+	Version    string `json:"version"`
+	AppVersion string `json:"appVersion"`
+}
+
+func (h *HelmChart) init() error {
+	if len(h.path) == 0 {
+		h.path = "Chart.yaml"
+	}
+	if h.readFile == nil {
+		h.readFile = ioutil.ReadFile
+	}
+	if h.writeFile == nil {
+		h.writeFile = ioutil.WriteFile
+	}
+	if h.content == nil {
+		content, err := h.readFile(h.path)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read file '%v'", h.path)
+		}
+		h.content = content
+	}
+	return nil
+}
+
+func (h *HelmChart) metadata() (helmChartMetadata, error) {
+	metadata := helmChartMetadata{}
+	if err := h.init(); err != nil {
+		return metadata, err
+	}
+	if err := yaml.Unmarshal(h.content, &metadata); err != nil {
+		return metadata, errors.Wrapf(err, "failed to read yaml content of file '%v'", h.path)
+	}
+	return metadata, nil
+}
+
+// VersioningScheme returns the relevant versioning scheme
+func (h *HelmChart) VersioningScheme() string {
+	// Helm requires chart versions to follow SemVer 2
+	return "semver2"
+}
+
+// GetVersion returns the current version of the chart
+func (h *HelmChart) GetVersion() (string, error) {
+	metadata, err := h.metadata()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to retrieve version")
+	}
+	if len(metadata.Version) == 0 {
+		return "", fmt.Errorf("no version available in '%v'", h.path)
+	}
+	return metadata.Version, nil
+}
+
+// GetAppVersion returns the version of the application contained in the chart
+func (h *HelmChart) GetAppVersion() (string, error) {
+	metadata, err := h.metadata()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to retrieve appVersion")
+	}
+	return metadata.AppVersion, nil
+}
+
+// SetVersion updates the version of the chart.
+// In case the appVersion should be updated as well it is set to the same version.
+func (h *HelmChart) SetVersion(version string) error {
+	if err := h.setField("version", version); err != nil {
+		return errors.Wrap(err, "failed to set version")
+	}
+	if h.updateAppVersion {
+		if err := h.setField("appVersion", version); err != nil {
+			return errors.Wrap(err, "failed to set appVersion")
+		}
+	}
+	return h.write()
+}
+
+// SetAppVersion updates the version of the application contained in the chart
+func (h *HelmChart) SetAppVersion(version string) error {
+	if err := h.setField("appVersion", version); err != nil {
+		return errors.Wrap(err, "failed to set appVersion")
+	}
+	return h.write()
+}
+
+// setField replaces the value of a top-level field in place in order to keep formatting and comments of the Chart.yaml.
+// Missing fields are appended.
+func (h *HelmChart) setField(field, value string) error {
+	if err := h.init(); err != nil {
+		return err
+	}
+	fieldRegex := regexp.MustCompile(`(?m)^(` + regexp.QuoteMeta(field) + `:[ \t]*)(["']?)[^"'\s#]*(["']?)`)
+	if fieldRegex.Match(h.content) {
+		h.content = fieldRegex.ReplaceAll(h.content, []byte("${1}${2}"+value+"${3}"))
+		return nil
+	}
+	content := string(h.content)
+	if len(content) > 0 && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	if field == "appVersion" {
+		// appVersion is usually not a semantic version, quoting prevents interpretation as number
+		value = fmt.Sprintf("%q", value)
+	}
+	h.content = []byte(fmt.Sprintf("%v%v: %v\n", content, field, value))
+	return nil
+}
+
+func (h *HelmChart) write() error {
+	if err := h.writeFile(h.path, h.content, 0644); err != nil {
+		return errors.Wrapf(err, "failed to write file '%v'", h.path)
+	}
+	return nil
+}
+
+// GetCoordinates returns the coordinates
+func (h *HelmChart) GetCoordinates() (Coordinates, error) {
+	result := Coordinates{}
+	metadata, err := h.metadata()
+	if err != nil {
+		return result, err
+	}
+	result.ArtifactID = metadata.Name
+	result.Version = metadata.Version
+	return result, nil
+}
@@ -0,0 +1,123 @@
+package versioning
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testChart = `apiVersion: v2
+name: my-app
+# chart version
+version: 1.2.3
+appVersion: "1.0.0"
+dependencies:
+  - name: db
+    version: 2.0.0
+`
+
+func TestHelmChartGetVersion(t *testing.T) {
+	t.Run("success case", func(t *testing.T) {
+		chart := HelmChart{
+			path:     "Chart.yaml",
+			readFile: func(filename string) ([]byte, error) { return []byte(testChart), nil },
+		}
+
+		version, err := chart.GetVersion()
+		assert.NoError(t, err)
+		assert.Equal(t, "1.2.3", version)
+
+		appVersion, err := chart.GetAppVersion()
+		assert.NoError(t, err)
+		assert.Equal(t, "1.0.0", appVersion)
+	})
+
+	t.Run("error case - no version", func(t *testing.T) {
+		chart := HelmChart{
+			path:     "Chart.yaml",
+			readFile: func(filename string) ([]byte, error) { return []byte("name: my-app"), nil },
+		}
+		_, err := chart.GetVersion()
+		assert.EqualError(t, err, "no version available in 'Chart.yaml'")
+	})
+
+	t.Run("error case - read error", func(t *testing.T) {
+		chart := HelmChart{
+			path:     "Chart.yaml",
+			readFile: func(filename string) ([]byte, error) { return []byte{}, fmt.Errorf("read error") },
+		}
+		_, err := chart.GetVersion()
+		assert.EqualError(t, err, "failed to retrieve version: failed to read file 'Chart.yaml': read error")
+	})
+}
+
+func TestHelmChartSetVersion(t *testing.T) {
+	t.Run("version only", func(t *testing.T) {
+		var content []byte
+		chart := HelmChart{
+			path:      "Chart.yaml",
+			readFile:  func(filename string) ([]byte, error) { return []byte(testChart), nil },
+			writeFile: func(filename string, filecontent []byte, mode os.FileMode) error { content = filecontent; return nil },
+		}
+		err := chart.SetVersion("1.3.0")
+		assert.NoError(t, err)
+		assert.Equal(t, `apiVersion: v2
+name: my-app
+# chart version
+version: 1.3.0
+appVersion: "1.0.0"
+dependencies:
+  - name: db
+    version: 2.0.0
+`, string(content))
+	})
+
+	t.Run("version and appVersion", func(t *testing.T) {
+		var content []byte
+		chart := HelmChart{
+			path:             "Chart.yaml",
+			updateAppVersion: true,
+			readFile:         func(filename string) ([]byte, error) { return []byte(testChart), nil },
+			writeFile:        func(filename string, filecontent []byte, mode os.FileMode) error { content = filecontent; return nil },
+		}
+		err := chart.SetVersion("1.3.0")
+		assert.NoError(t, err)
+		assert.Contains(t, string(content), "\nversion: 1.3.0\n")
+		assert.Contains(t, string(content), "\nappVersion: \"1.3.0\"\n")
+		assert.Contains(t, string(content), "\n    version: 2.0.0\n")
+	})
+
+	t.Run("appVersion added", func(t *testing.T) {
+		var content []byte
+		chart := HelmChart{
+			path:      "Chart.yaml",
+			readFile:  func(filename string) ([]byte, error) { return []byte("name: my-app\nversion: 1.2.3"), nil },
+			writeFile: func(filename string, filecontent []byte, mode os.FileMode) error { content = filecontent; return nil },
+		}
+		err := chart.SetAppVersion("2.0.0-20210301")
+		assert.NoError(t, err)
+		assert.Equal(t, "name: my-app\nversion: 1.2.3\nappVersion: \"2.0.0-20210301\"\n", string(content))
+	})
+
+	t.Run("error case", func(t *testing.T) {
+		chart := HelmChart{
+			path:      "Chart.yaml",
+			readFile:  func(filename string) ([]byte, error) { return []byte(testChart), nil },
+			writeFile: func(filename string, filecontent []byte, mode os.FileMode) error { return fmt.Errorf("write error") },
+		}
+		err := chart.SetVersion("1.3.0")
+		assert.EqualError(t, err, "failed to write file 'Chart.yaml': write error")
+	})
+}
+
+func TestHelmChartGetCoordinates(t *testing.T) {
+	chart := HelmChart{
+		path:     "Chart.yaml",
+		readFile: func(filename string) ([]byte, error) { return []byte(testChart), nil },
+	}
+	coordinates, err := chart.GetCoordinates()
+	assert.NoError(t, err)
+	assert.Equal(t, Coordinates{ArtifactID: "my-app", Version: "1.2.3"}, coordinates)
+}
@@ -0,0 +1,188 @@
+package versioning
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+)
+
+// Kustomization defines an artifact using the tag of an image maintained in a kustomization.yaml for versioning
+type Kustomization struct {
+	path      string
+	imageName string
+	content   []byte
+	readFile  func(string) ([]byte, error)
+	writeFile func(string, []byte, os.FileMode) error
+}
+
+// kustomizationImage describes an entry of the images section of a kustomization.yaml
+type kustomizationImage struct {
+	Name    string `json:"name"`
+	NewName string `json:"newName"`
+	NewTag  string `json:"newTag"`
+}
+
+var kustomizationListItemRegex = regexp.MustCompile(`^(\s*-\s+)`)
+
+func (k *Kustomization) init() error {
+	if len(k.path) == 0 {
+		k.path = "kustomization.yaml"
+	}
+	if k.readFile == nil {
+		k.readFile = ioutil.ReadFile
+	}
+	if k.writeFile == nil {
+		k.writeFile = ioutil.WriteFile
+	}
+	if k.content == nil {
+		content, err := k.readFile(k.path)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read file '%v'", k.path)
+		}
+		k.content = content
+	}
+	return nil
+}
+
+// image returns the image entry which carries the version.
+// In case no image name is defined the kustomization needs to contain exactly one image.
+func (k *Kustomization) image() (kustomizationImage, error) {
+	if err := k.init(); err != nil {
+		return kustomizationImage{}, err
+	}
+	kustomization := struct {
+		Images []kustomizationImage `json:"images"`
+	}{}
+	if err := yaml.Unmarshal(k.content, &kustomization); err != nil {
+		return kustomizationImage{}, errors.Wrapf(err, "failed to read yaml content of file '%v'", k.path)
+	}
+	if len(k.imageName) == 0 {
+		if len(kustomization.Images) != 1 {
+			return kustomizationImage{}, fmt.Errorf("'%v' contains %v images, please define the image which carries the version", k.path, len(kustomization.Images))
+		}
+		return kustomization.Images[0], nil
+	}
+	for _, image := range kustomization.Images {
+		if image.Name == k.imageName {
+			return image, nil
+		}
+	}
+	return kustomizationImage{}, fmt.Errorf("image '%v' not contained in '%v'", k.imageName, k.path)
+}
+
+// VersioningScheme returns the relevant versioning scheme
+func (k *Kustomization) VersioningScheme() string {
+	return "docker"
+}
+
+// GetVersion returns the current tag of the image
+func (k *Kustomization) GetVersion() (string, error) {
+	image, err := k.image()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to retrieve version")
+	}
+	if len(image.NewTag) == 0 {
+		return "", fmt.Errorf("no tag available for image '%v' in '%v'", image.Name, k.path)
+	}
+	return image.NewTag, nil
+}
+
+// kustomizationItem references the lines of an entry of the images section
+type kustomizationItem struct {
+	start  int
+	end    int
+	indent string
+}
+
+// imageItem returns the lines of the images entry with the given name
+func (k *Kustomization) imageItem(lines []string, name string) kustomizationItem {
+	inImages := false
+	current, result := kustomizationItem{start: -1}, kustomizationItem{start: -1}
+	matches := false
+	finish := func(end int) {
+		if matches && result.start < 0 {
+			result = current
+			result.end = end
+		}
+		matches = false
+	}
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if len(trimmed) == 0 || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "-") {
+			// top-level key
+			finish(i)
+			inImages = strings.HasPrefix(line, "images:")
+			continue
+		}
+		if !inImages {
+			continue
+		}
+		if prefix := kustomizationListItemRegex.FindString(line); len(prefix) > 0 {
+			// a new entry starts
+			finish(i)
+			current = kustomizationItem{start: i, indent: strings.Repeat(" ", len(prefix))}
+		}
+		field := strings.TrimLeft(line, " -")
+		if field == "name: "+name || field == fmt.Sprintf("name: %q", name) || field == fmt.Sprintf("name: '%v'", name) {
+			matches = true
+		}
+	}
+	finish(len(lines))
+	return result
+}
+
+// SetVersion updates the tag of the image.
+// The kustomization.yaml is updated in place in order to keep formatting and comments.
+func (k *Kustomization) SetVersion(version string) error {
+	image, err := k.image()
+	if err != nil {
+		return errors.Wrap(err, "failed to set version")
+	}
+
+	lines := strings.Split(string(k.content), "\n")
+	tagLine := fmt.Sprintf("newTag: %q", version)
+	item := k.imageItem(lines, image.Name)
+	if item.start < 0 {
+		return fmt.Errorf("failed to set version: image '%v' not found in '%v'", image.Name, k.path)
+	}
+	updated := false
+	for i := item.start; i < item.end; i++ {
+		if strings.HasPrefix(strings.TrimLeft(lines[i], " -"), "newTag:") {
+			lines[i] = lines[i][:len(lines[i])-len(strings.TrimLeft(lines[i], " -"))] + tagLine
+			updated = true
+		}
+	}
+	if !updated {
+		lines = append(lines[:item.start+1], append([]string{item.indent + tagLine}, lines[item.start+1:]...)...)
+	}
+
+	k.content = []byte(strings.Join(lines, "\n"))
+	if err := k.writeFile(k.path, k.content, 0644); err != nil {
+		return errors.Wrapf(err, "failed to write file '%v'", k.path)
+	}
+	return nil
+}
+
+// GetCoordinates returns the coordinates
+func (k *Kustomization) GetCoordinates() (Coordinates, error) {
+	result := Coordinates{}
+	image, err := k.image()
+	if err != nil {
+		return result, err
+	}
+	result.ArtifactID = image.NewName
+	if len(result.ArtifactID) == 0 {
+		result.ArtifactID = image.Name
+	}
+	result.Version = image.NewTag
+	result.Packaging = "docker"
+	return result, nil
+}
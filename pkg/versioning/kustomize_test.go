@@ -0,0 +1,129 @@
+package versioning
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testKustomization = `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+  - deployment.yaml
+images:
+  - name: my-app
+    newName: registry.example.com/my-app
+    newTag: 1.2.3 # managed by pipeline
+  - newName: registry.example.com/my-db
+    name: my-db
+`
+
+func TestKustomizationGetVersion(t *testing.T) {
+	t.Run("success case", func(t *testing.T) {
+		kustomization := Kustomization{
+			path:      "kustomization.yaml",
+			imageName: "my-app",
+			readFile:  func(filename string) ([]byte, error) { return []byte(testKustomization), nil },
+		}
+		version, err := kustomization.GetVersion()
+		assert.NoError(t, err)
+		assert.Equal(t, "1.2.3", version)
+	})
+
+	t.Run("success case - single image", func(t *testing.T) {
+		kustomization := Kustomization{
+			path: "kustomization.yaml",
+			readFile: func(filename string) ([]byte, error) {
+				return []byte("images:\n- name: my-app\n  newTag: \"2.0.0\"\n"), nil
+			},
+		}
+		version, err := kustomization.GetVersion()
+		assert.NoError(t, err)
+		assert.Equal(t, "2.0.0", version)
+	})
+
+	t.Run("error case - image ambiguous", func(t *testing.T) {
+		kustomization := Kustomization{
+			path:     "kustomization.yaml",
+			readFile: func(filename string) ([]byte, error) { return []byte(testKustomization), nil },
+		}
+		_, err := kustomization.GetVersion()
+		assert.EqualError(t, err, "failed to retrieve version: 'kustomization.yaml' contains 2 images, please define the image which carries the version")
+	})
+
+	t.Run("error case - image not contained", func(t *testing.T) {
+		kustomization := Kustomization{
+			path:      "kustomization.yaml",
+			imageName: "other",
+			readFile:  func(filename string) ([]byte, error) { return []byte(testKustomization), nil },
+		}
+		_, err := kustomization.GetVersion()
+		assert.EqualError(t, err, "failed to retrieve version: image 'other' not contained in 'kustomization.yaml'")
+	})
+
+	t.Run("error case - no tag", func(t *testing.T) {
+		kustomization := Kustomization{
+			path:      "kustomization.yaml",
+			imageName: "my-db",
+			readFile:  func(filename string) ([]byte, error) { return []byte(testKustomization), nil },
+		}
+		_, err := kustomization.GetVersion()
+		assert.EqualError(t, err, "no tag available for image 'my-db' in 'kustomization.yaml'")
+	})
+}
+
+func TestKustomizationSetVersion(t *testing.T) {
+	t.Run("update existing tag", func(t *testing.T) {
+		var content []byte
+		kustomization := Kustomization{
+			path:      "kustomization.yaml",
+			imageName: "my-app",
+			readFile:  func(filename string) ([]byte, error) { return []byte(testKustomization), nil },
+			writeFile: func(filename string, filecontent []byte, mode os.FileMode) error { content = filecontent; return nil },
+		}
+		err := kustomization.SetVersion("1.3.0-20210301")
+		assert.NoError(t, err)
+		assert.Equal(t, `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+  - deployment.yaml
+images:
+  - name: my-app
+    newName: registry.example.com/my-app
+    newTag: "1.3.0-20210301"
+  - newName: registry.example.com/my-db
+    name: my-db
+`, string(content))
+	})
+
+	t.Run("add tag", func(t *testing.T) {
+		var content []byte
+		kustomization := Kustomization{
+			path:      "kustomization.yaml",
+			imageName: "my-db",
+			readFile:  func(filename string) ([]byte, error) { return []byte(testKustomization), nil },
+			writeFile: func(filename string, filecontent []byte, mode os.FileMode) error { content = filecontent; return nil },
+		}
+		err := kustomization.SetVersion("3.0.0")
+		assert.NoError(t, err)
+		assert.Contains(t, string(content), `
+    newTag: 1.2.3 # managed by pipeline
+  - newName: registry.example.com/my-db
+    newTag: "3.0.0"
+    name: my-db
+`)
+	})
+
+	t.Run("error case", func(t *testing.T) {
+		kustomization := Kustomization{
+			path:      "kustomization.yaml",
+			imageName: "my-app",
+			readFile:  func(filename string) ([]byte, error) { return []byte(testKustomization), nil },
+			writeFile: func(filename string, filecontent []byte, mode os.FileMode) error { return fmt.Errorf("write error") },
+		}
+		err := kustomization.SetVersion("1.3.0")
+		assert.EqualError(t, err, "failed to write file 'kustomization.yaml': write error")
+	})
+}
@@ -0,0 +1,130 @@
+package versioning
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	gemspecVersionRegex = regexp.MustCompile(`(\.version\s*=\s*["'])([^"']+)(["'])`)
+	gemspecNameRegex    = regexp.MustCompile(`\.name\s*=\s*["']([^"']+)["']`)
+	versionRbRegex      = regexp.MustCompile(`(VERSION\s*=\s*["'])([^"']+)(["'])`)
+)
+
+// RubyGem defines an artifact using a *.gemspec for versioning.
+// In case the gemspec references a constant (e.g. spec.version = MyGem::VERSION) the version is maintained in the version.rb of the gem.
+type RubyGem struct {
+	path        string
+	versionFile string
+	readFile    func(string) ([]byte, error)
+	writeFile   func(string, []byte, os.FileMode) error
+	glob        func(string) ([]string, error)
+}
+
+func (r *RubyGem) init() {
+	if r.readFile == nil {
+		r.readFile = ioutil.ReadFile
+	}
+	if r.writeFile == nil {
+		r.writeFile = ioutil.WriteFile
+	}
+}
+
+// versionSource returns the file containing the version as well as the regular expression to find the version
+func (r *RubyGem) versionSource() (string, *regexp.Regexp, error) {
+	r.init()
+	if filepath.Base(r.path) == "version.rb" {
+		return r.path, versionRbRegex, nil
+	}
+	if len(r.versionFile) > 0 {
+		return r.versionFile, versionRbRegex, nil
+	}
+
+	content, err := r.readFile(r.path)
+	if err != nil {
+		return "", nil, errors.Wrapf(err, "failed to read file '%v'", r.path)
+	}
+	if gemspecVersionRegex.Match(content) {
+		return r.path, gemspecVersionRegex, nil
+	}
+
+	if r.glob == nil {
+		return "", nil, fmt.Errorf("no version available in '%v'", r.path)
+	}
+	versionFiles, err := r.glob(filepath.Join(filepath.Dir(r.path), "lib", "**", "version.rb"))
+	if err != nil {
+		return "", nil, errors.Wrap(err, "failed to search for version.rb")
+	}
+	if len(versionFiles) != 1 {
+		return "", nil, fmt.Errorf("no version available in '%v' and %v version.rb files found, please define the version.rb as filePath", r.path, len(versionFiles))
+	}
+	r.versionFile = versionFiles[0]
+	return r.versionFile, versionRbRegex, nil
+}
+
+// VersioningScheme returns the relevant versioning scheme
+func (r *RubyGem) VersioningScheme() string {
+	return "rubygems"
+}
+
+// GetVersion returns the current version of the gem
+func (r *RubyGem) GetVersion() (string, error) {
+	path, versionRegex, err := r.versionSource()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to retrieve version")
+	}
+	content, err := r.readFile(path)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read file '%v'", path)
+	}
+	match := versionRegex.FindStringSubmatch(string(content))
+	if match == nil {
+		return "", fmt.Errorf("no version available in '%v'", path)
+	}
+	return match[2], nil
+}
+
+// SetVersion updates the version of the gem
+func (r *RubyGem) SetVersion(version string) error {
+	path, versionRegex, err := r.versionSource()
+	if err != nil {
+		return errors.Wrap(err, "failed to set version")
+	}
+	content, err := r.readFile(path)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read file '%v'", path)
+	}
+	// ruby strings may contain the version in single or double quotes, the quotes are kept
+	updated := versionRegex.ReplaceAllString(string(content), "${1}"+version+"${3}")
+	if err := r.writeFile(path, []byte(updated), 0644); err != nil {
+		return errors.Wrapf(err, "failed to write file '%v'", path)
+	}
+	return nil
+}
+
+// GetCoordinates returns the coordinates
+func (r *RubyGem) GetCoordinates() (Coordinates, error) {
+	result := Coordinates{}
+	var err error
+	result.Version, err = r.GetVersion()
+	if err != nil {
+		return result, err
+	}
+	if strings.HasSuffix(r.path, ".gemspec") {
+		content, err := r.readFile(r.path)
+		if err != nil {
+			return result, errors.Wrapf(err, "failed to read file '%v'", r.path)
+		}
+		if match := gemspecNameRegex.FindStringSubmatch(string(content)); match != nil {
+			result.ArtifactID = match[1]
+		}
+	}
+	result.Packaging = "gem"
+	return result, nil
+}
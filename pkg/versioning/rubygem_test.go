@@ -0,0 +1,95 @@
+package versioning
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRubyGemGetVersion(t *testing.T) {
+	t.Run("success case - gemspec", func(t *testing.T) {
+		gem := RubyGem{
+			path: "my_gem.gemspec",
+			readFile: func(filename string) ([]byte, error) {
+				return []byte("Gem::Specification.new do |spec|\n  spec.name = 'my_gem'\n  spec.version = '1.2.3'\n  spec.required_ruby_version = '>= 2.5'\nend\n"), nil
+			},
+		}
+		version, err := gem.GetVersion()
+		assert.NoError(t, err)
+		assert.Equal(t, "1.2.3", version)
+	})
+
+	t.Run("success case - version.rb", func(t *testing.T) {
+		files := map[string]string{
+			"gem/my_gem.gemspec":        "spec.name = \"my_gem\"\nspec.version = MyGem::VERSION\n",
+			"gem/lib/my_gem/version.rb": "module MyGem\n  VERSION = \"0.9.0\"\nend\n",
+		}
+		gem := RubyGem{
+			path:     "gem/my_gem.gemspec",
+			readFile: func(filename string) ([]byte, error) { return []byte(files[filename]), nil },
+			glob: func(pattern string) ([]string, error) {
+				assert.Equal(t, "gem/lib/**/version.rb", pattern)
+				return []string{"gem/lib/my_gem/version.rb"}, nil
+			},
+		}
+		version, err := gem.GetVersion()
+		assert.NoError(t, err)
+		assert.Equal(t, "0.9.0", version)
+	})
+
+	t.Run("error case - no version.rb", func(t *testing.T) {
+		gem := RubyGem{
+			path:     "my_gem.gemspec",
+			readFile: func(filename string) ([]byte, error) { return []byte("spec.version = MyGem::VERSION\n"), nil },
+			glob:     func(pattern string) ([]string, error) { return []string{}, nil },
+		}
+		_, err := gem.GetVersion()
+		assert.EqualError(t, err, "failed to retrieve version: no version available in 'my_gem.gemspec' and 0 version.rb files found, please define the version.rb as filePath")
+	})
+}
+
+func TestRubyGemSetVersion(t *testing.T) {
+	t.Run("success case", func(t *testing.T) {
+		var content []byte
+		var path string
+		gem := RubyGem{
+			path: "lib/my_gem/version.rb",
+			readFile: func(filename string) ([]byte, error) {
+				return []byte("module MyGem\n  VERSION = '0.9.0'.freeze\nend\n"), nil
+			},
+			writeFile: func(filename string, filecontent []byte, mode os.FileMode) error {
+				path = filename
+				content = filecontent
+				return nil
+			},
+		}
+		err := gem.SetVersion("0.9.1.20210301")
+		assert.NoError(t, err)
+		assert.Equal(t, "lib/my_gem/version.rb", path)
+		assert.Equal(t, "module MyGem\n  VERSION = '0.9.1.20210301'.freeze\nend\n", string(content))
+	})
+
+	t.Run("error case", func(t *testing.T) {
+		gem := RubyGem{
+			path:      "my_gem.gemspec",
+			readFile:  func(filename string) ([]byte, error) { return []byte("spec.version = '1.0.0'"), nil },
+			writeFile: func(filename string, filecontent []byte, mode os.FileMode) error { return fmt.Errorf("write error") },
+		}
+		err := gem.SetVersion("1.0.1")
+		assert.EqualError(t, err, "failed to write file 'my_gem.gemspec': write error")
+	})
+}
+
+func TestRubyGemGetCoordinates(t *testing.T) {
+	gem := RubyGem{
+		path: "my_gem.gemspec",
+		readFile: func(filename string) ([]byte, error) {
+			return []byte("spec.name = \"my_gem\"\nspec.version = \"1.0.0\"\n"), nil
+		},
+	}
+	coordinates, err := gem.GetCoordinates()
+	assert.NoError(t, err)
+	assert.Equal(t, Coordinates{ArtifactID: "my_gem", Version: "1.0.0", Packaging: "gem"}, coordinates)
+}
@@ -0,0 +1,38 @@
+package versioning
+
+import (
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+	"github.com/pkg/errors"
+)
+
+// LatestVersionTag returns the name and the version of the tag with the highest semantic version using the given prefix (e.g. 'v').
+// Tags which do not describe a version as well as the ignored tags are skipped. In case no such tag exists the name is empty.
+func LatestVersionTag(tags storer.ReferenceIter, tagPrefix string, ignoredTags ...string) (string, SemanticVersion, error) {
+	ignored := map[string]bool{}
+	for _, tag := range ignoredTags {
+		ignored[tag] = true
+	}
+	latestTag, latestVersion := "", SemanticVersion{}
+	err := tags.ForEach(func(ref *plumbing.Reference) error {
+		name := ref.Name().Short()
+		if !strings.HasPrefix(name, tagPrefix) || ignored[name] {
+			return nil
+		}
+		tagVersion, err := ParseSemanticVersion(strings.TrimPrefix(name, tagPrefix))
+		if err != nil {
+			// tags which do not describe a version are ignored
+			return nil
+		}
+		if len(latestTag) == 0 || latestVersion.Less(tagVersion) {
+			latestTag, latestVersion = name, tagVersion
+		}
+		return nil
+	})
+	if err != nil {
+		return "", SemanticVersion{}, errors.Wrap(err, "failed to retrieve tags")
+	}
+	return latestTag, latestVersion, nil
+}
@@ -0,0 +1,41 @@
+package versioning
+
+import (
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLatestVersionTag(t *testing.T) {
+	tags := func(names ...string) storer.ReferenceIter {
+		refs := []*plumbing.Reference{}
+		for _, name := range names {
+			refs = append(refs, plumbing.NewHashReference(plumbing.NewTagReferenceName(name), plumbing.ZeroHash))
+		}
+		return storer.NewReferenceSliceIter(refs)
+	}
+
+	t.Run("highest version", func(t *testing.T) {
+		name, version, err := LatestVersionTag(tags("v1.9.0", "v1.10.0", "build_2.0.0", "v1.10", "latest"), "v")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "v1.10.0", name)
+		assert.Equal(t, SemanticVersion{Major: 1, Minor: 10}, version)
+	})
+
+	t.Run("ignored tag", func(t *testing.T) {
+		name, _, err := LatestVersionTag(tags("1.0.0", "1.1.0"), "", "1.1.0")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "1.0.0", name)
+	})
+
+	t.Run("no version tag", func(t *testing.T) {
+		name, _, err := LatestVersionTag(tags("latest"), "v")
+
+		assert.NoError(t, err)
+		assert.Empty(t, name)
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 
 	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/bmatcuk/doublestar"
 
 	"github.com/SAP/jenkins-library/pkg/maven"
 )
@@ -27,14 +28,16 @@ type Artifact interface {
 
 // Options define build tool specific settings in order to properly retrieve e.g. the version / coordinates of an artifact
 type Options struct {
-	ProjectSettingsFile string
-	DockerImage         string
-	GlobalSettingsFile  string
-	M2Path              string
-	VersionSource       string
-	VersionSection      string
-	VersionField        string
-	VersioningScheme    string
+	ProjectSettingsFile  string
+	DockerImage          string
+	GlobalSettingsFile   string
+	M2Path               string
+	VersionSource        string
+	VersionSection       string
+	VersionField         string
+	VersioningScheme     string
+	HelmUpdateAppVersion bool
+	KustomizeImageName   string
 }
 
 // Utils defines the versioning operations for various build tools
@@ -53,6 +56,8 @@ func (m *mvnRunner) Evaluate(options *maven.EvaluateOptions, expression string,
 
 var fileExists func(string) (bool, error)
 
+var glob = doublestar.Glob
+
 // GetArtifact returns the build tool specific implementation for retrieving version, etc. of an artifact
 func GetArtifact(buildTool, buildDescriptorFilePath string, opts *Options, utils Utils) (Artifact, error) {
 	var artifact Artifact
@@ -60,6 +65,23 @@ func GetArtifact(buildTool, buildDescriptorFilePath string, opts *Options, utils
 		fileExists = piperutils.FileExists
 	}
 	switch buildTool {
+	case "cargo":
+		if len(buildDescriptorFilePath) == 0 {
+			buildDescriptorFilePath = "Cargo.toml"
+		}
+		artifact = &Cargo{
+			path: buildDescriptorFilePath,
+		}
+	case "composer":
+		if len(buildDescriptorFilePath) == 0 {
+			buildDescriptorFilePath = "composer.json"
+		}
+		artifact = &Composer{
+			JSONfile: JSONfile{
+				path:         buildDescriptorFilePath,
+				versionField: "version",
+			},
+		}
 	case "custom":
 		var err error
 		artifact, err = customArtifact(buildDescriptorFilePath, opts.VersionField, opts.VersionSection, opts.VersioningScheme)
@@ -74,6 +96,17 @@ func GetArtifact(buildTool, buildDescriptorFilePath string, opts *Options, utils
 			versionSource:    opts.VersionSource,
 			versioningScheme: opts.VersioningScheme,
 		}
+	case "dotnet":
+		if len(buildDescriptorFilePath) == 0 {
+			var err error
+			buildDescriptorFilePath, err = searchDescriptorPattern([]string{"Directory.Build.props", "*.csproj"})
+			if err != nil {
+				return artifact, err
+			}
+		}
+		artifact = &DotNet{
+			path: buildDescriptorFilePath,
+		}
 	case "dub":
 		if len(buildDescriptorFilePath) == 0 {
 			buildDescriptorFilePath = "dub.json"
@@ -82,6 +115,18 @@ func GetArtifact(buildTool, buildDescriptorFilePath string, opts *Options, utils
 			path:         buildDescriptorFilePath,
 			versionField: "version",
 		}
+	case "gem":
+		if len(buildDescriptorFilePath) == 0 {
+			var err error
+			buildDescriptorFilePath, err = searchDescriptorPattern([]string{"*.gemspec"})
+			if err != nil {
+				return artifact, err
+			}
+		}
+		artifact = &RubyGem{
+			path: buildDescriptorFilePath,
+			glob: glob,
+		}
 	case "gradle":
 		if len(buildDescriptorFilePath) == 0 {
 			buildDescriptorFilePath = "gradle.properties"
@@ -99,13 +144,30 @@ func GetArtifact(buildTool, buildDescriptorFilePath string, opts *Options, utils
 			}
 		}
 
-		switch buildDescriptorFilePath {
+		// go.mod files can also be located in subdirectories (e.g. discovered artifacts)
+		switch filepath.Base(buildDescriptorFilePath) {
 		case "go.mod":
 			artifact = &GoMod{path: buildDescriptorFilePath, fileExists: fileExists}
 			break
 		default:
 			artifact = &Versionfile{path: buildDescriptorFilePath}
 		}
+	case "helm":
+		if len(buildDescriptorFilePath) == 0 {
+			buildDescriptorFilePath = "Chart.yaml"
+		}
+		artifact = &HelmChart{
+			path:             buildDescriptorFilePath,
+			updateAppVersion: opts.HelmUpdateAppVersion,
+		}
+	case "kustomize":
+		if len(buildDescriptorFilePath) == 0 {
+			buildDescriptorFilePath = "kustomization.yaml"
+		}
+		artifact = &Kustomization{
+			path:      buildDescriptorFilePath,
+			imageName: opts.KustomizeImageName,
+		}
 	case "maven":
 		if len(buildDescriptorFilePath) == 0 {
 			buildDescriptorFilePath = "pom.xml"
@@ -183,6 +245,23 @@ func searchDescriptor(supported []string, existsFunc func(string) (bool, error))
 	return descriptor, nil
 }
 
+// searchDescriptorPattern returns the descriptor matching the first pattern which matches exactly one file
+func searchDescriptorPattern(patterns []string) (string, error) {
+	for _, pattern := range patterns {
+		matches, err := glob(pattern)
+		if err != nil {
+			return "", err
+		}
+		if len(matches) == 1 {
+			return matches[0], nil
+		}
+		if len(matches) > 1 {
+			return "", fmt.Errorf("multiple build descriptors found (%v), please define filePath", matches)
+		}
+	}
+	return "", fmt.Errorf("no build descriptor available, supported: %v", patterns)
+}
+
 func customArtifact(buildDescriptorFilePath, field, section, scheme string) (Artifact, error) {
 	switch filepath.Ext(buildDescriptorFilePath) {
 	case ".cfg", ".ini":
@@ -3,10 +3,34 @@ package versioning
 import (
 	"testing"
 
+	"github.com/bmatcuk/doublestar"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestGetArtifact(t *testing.T) {
+	t.Run("cargo", func(t *testing.T) {
+		cargo, err := GetArtifact("cargo", "", &Options{}, nil)
+
+		assert.NoError(t, err)
+
+		theType, ok := cargo.(*Cargo)
+		assert.True(t, ok)
+		assert.Equal(t, "Cargo.toml", theType.path)
+		assert.Equal(t, "semver2", cargo.VersioningScheme())
+	})
+
+	t.Run("composer", func(t *testing.T) {
+		composer, err := GetArtifact("composer", "", &Options{}, nil)
+
+		assert.NoError(t, err)
+
+		theType, ok := composer.(*Composer)
+		assert.True(t, ok)
+		assert.Equal(t, "composer.json", theType.path)
+		assert.Equal(t, "version", theType.versionField)
+		assert.Equal(t, "semver2", composer.VersioningScheme())
+	})
+
 	t.Run("custom", func(t *testing.T) {
 		custom, err := GetArtifact("custom", "test.ini", &Options{VersionField: "theversion", VersionSection: "test"}, nil)
 
@@ -33,6 +57,37 @@ func TestGetArtifact(t *testing.T) {
 		assert.Equal(t, "docker", docker.VersioningScheme())
 	})
 
+	t.Run("dotnet", func(t *testing.T) {
+		glob = func(pattern string) ([]string, error) {
+			if pattern == "*.csproj" {
+				return []string{"MyApp.csproj"}, nil
+			}
+			return []string{}, nil
+		}
+		defer func() { glob = doublestar.Glob }()
+		dotnet, err := GetArtifact("dotnet", "", &Options{}, nil)
+
+		assert.NoError(t, err)
+
+		theType, ok := dotnet.(*DotNet)
+		assert.True(t, ok)
+		assert.Equal(t, "MyApp.csproj", theType.path)
+		assert.Equal(t, "semver2", dotnet.VersioningScheme())
+	})
+
+	t.Run("dotnet - multiple projects", func(t *testing.T) {
+		glob = func(pattern string) ([]string, error) {
+			if pattern == "*.csproj" {
+				return []string{"A.csproj", "B.csproj"}, nil
+			}
+			return []string{}, nil
+		}
+		defer func() { glob = doublestar.Glob }()
+		_, err := GetArtifact("dotnet", "", &Options{}, nil)
+
+		assert.EqualError(t, err, "multiple build descriptors found ([A.csproj B.csproj]), please define filePath")
+	})
+
 	t.Run("dub", func(t *testing.T) {
 		dub, err := GetArtifact("dub", "", &Options{VersionField: "theversion"}, nil)
 
@@ -57,6 +112,16 @@ func TestGetArtifact(t *testing.T) {
 		assert.Equal(t, "semver2", golang.VersioningScheme())
 	})
 
+	t.Run("golang - nested go.mod", func(t *testing.T) {
+		golang, err := GetArtifact("golang", "svc/go.mod", &Options{}, nil)
+
+		assert.NoError(t, err)
+
+		theType, ok := golang.(*GoMod)
+		assert.True(t, ok)
+		assert.Equal(t, "svc/go.mod", theType.path)
+	})
+
 	t.Run("golang - error", func(t *testing.T) {
 		fileExists = func(string) (bool, error) { return false, nil }
 		_, err := GetArtifact("golang", "", &Options{}, nil)
@@ -76,6 +141,42 @@ func TestGetArtifact(t *testing.T) {
 		assert.Equal(t, "semver2", gradle.VersioningScheme())
 	})
 
+	t.Run("gem", func(t *testing.T) {
+		gem, err := GetArtifact("gem", "my_gem.gemspec", &Options{}, nil)
+
+		assert.NoError(t, err)
+
+		theType, ok := gem.(*RubyGem)
+		assert.True(t, ok)
+		assert.Equal(t, "my_gem.gemspec", theType.path)
+		assert.NotNil(t, theType.glob)
+		assert.Equal(t, "rubygems", gem.VersioningScheme())
+	})
+
+	t.Run("helm", func(t *testing.T) {
+		helm, err := GetArtifact("helm", "", &Options{HelmUpdateAppVersion: true}, nil)
+
+		assert.NoError(t, err)
+
+		theType, ok := helm.(*HelmChart)
+		assert.True(t, ok)
+		assert.Equal(t, "Chart.yaml", theType.path)
+		assert.True(t, theType.updateAppVersion)
+		assert.Equal(t, "semver2", helm.VersioningScheme())
+	})
+
+	t.Run("kustomize", func(t *testing.T) {
+		kustomize, err := GetArtifact("kustomize", "", &Options{KustomizeImageName: "my-app"}, nil)
+
+		assert.NoError(t, err)
+
+		theType, ok := kustomize.(*Kustomization)
+		assert.True(t, ok)
+		assert.Equal(t, "kustomization.yaml", theType.path)
+		assert.Equal(t, "my-app", theType.imageName)
+		assert.Equal(t, "docker", kustomize.VersioningScheme())
+	})
+
 	t.Run("maven", func(t *testing.T) {
 		opts := Options{
 			ProjectSettingsFile: "projectsettings.xml",
@@ -0,0 +1,52 @@
+import org.junit.Rule
+import org.junit.Test
+import org.junit.rules.RuleChain
+
+import util.BasePiperTest
+import util.JenkinsReadYamlRule
+import util.JenkinsStepRule
+import util.Rules
+
+public class ChangelogCreateTest extends BasePiperTest {
+
+    private JenkinsStepRule stepRule = new JenkinsStepRule(this)
+    private JenkinsReadYamlRule readYamlRule = new JenkinsReadYamlRule(this)
+
+    @Rule
+    public RuleChain ruleChain = Rules
+        .getCommonRules(this)
+        .around(stepRule)
+        .around(readYamlRule)
+
+    @Test
+    void testCallGoWrapper() {
+
+        def calledWithParameters,
+            calledWithStepName,
+            calledWithMetadata,
+            calledWithCredentials
+
+        helper.registerAllowedMethod(
+            'piperExecuteBin',
+            [Map, String, String, List],
+            {
+                params, stepName, metaData, creds ->
+                calledWithParameters = params
+                calledWithStepName = stepName
+                calledWithMetadata = metaData
+                calledWithCredentials = creds
+            }
+        )
+
+        stepRule.step.changelogCreate(script: nullScript, version: '1.2.0')
+
+        assert calledWithParameters.size() == 2
+        assert calledWithParameters.script == nullScript
+        assert calledWithParameters.version == '1.2.0'
+
+        assert calledWithStepName == 'changelogCreate'
+        assert calledWithMetadata == 'metadata/changelogCreate.yaml'
+        assert calledWithCredentials.size() == 1
+        assert calledWithCredentials[0] == [type: 'token', id: 'githubTokenCredentialsId', env: ['PIPER_token']]
+    }
+}
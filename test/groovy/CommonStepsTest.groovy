@@ -144,7 +144,11 @@ public class CommonStepsTest extends BasePiperTest{
         'githubPublishRelease', //implementing new golang pattern without fields
         'githubCheckBranchProtection', //implementing new golang pattern without fields
         'githubCommentIssue', //implementing new golang pattern without fields
+        'githubDecoratePullRequest', //implementing new golang pattern without fields
         'githubSetCommitStatus', //implementing new golang pattern without fields
+        'helmExecute', //implementing new golang pattern without fields
+        'containerScanImage', //implementing new golang pattern without fields
+        'containerSignImage', //implementing new golang pattern without fields
         'kubernetesDeploy', //implementing new golang pattern without fields
         'piperExecuteBin', //implementing new golang pattern without fields
         'protecodeExecuteScan', //implementing new golang pattern without fields
@@ -157,6 +161,7 @@ public class CommonStepsTest extends BasePiperTest{
         'mavenExecuteIntegration', //implementing new golang pattern without fields
         'mavenExecuteStaticCodeChecks', //implementing new golang pattern without fields
         'mtaBuild', //implementing new golang pattern without fields
+        'neoDeploy', //implementing new golang pattern without fields
         'nexusUpload', //implementing new golang pattern without fields
         'piperPipelineStageArtifactDeployment', //stage without step flags
         'sonarExecuteScan', //implementing new golang pattern without fields
@@ -183,6 +188,19 @@ public class CommonStepsTest extends BasePiperTest{
         'integrationArtifactUpload', //implementing new golang pattern without fields
         'containerExecuteStructureTests', //implementing new golang pattern without fields
         'transportRequestUploadSOLMAN', //implementing new golang pattern without fields
+        'transportRequestCreateCTS', //implementing new golang pattern without fields
+        'transportRequestCreateSOLMAN', //implementing new golang pattern without fields
+        'transportRequestReleaseCTS', //implementing new golang pattern without fields
+        'transportRequestReleaseSOLMAN', //implementing new golang pattern without fields
+        'spinnakerTriggerPipeline', //implementing new golang pattern without fields
+        'notificationSend', //implementing new golang pattern without fields
+        'changelogCreate', //implementing new golang pattern without fields
+        'scmCheckBranchProtection', //implementing new golang pattern without fields
+        'scmCommentIssue', //implementing new golang pattern without fields
+        'scmCreateIssue', //implementing new golang pattern without fields
+        'scmCreatePullRequest', //implementing new golang pattern without fields
+        'scmPublishRelease', //implementing new golang pattern without fields
+        'scmSetCommitStatus', //implementing new golang pattern without fields
     ]
 
     @Test
@@ -1,4 +1,3 @@
-import util.CommandLineMatcher
 import util.JenkinsLockRule
 import util.JenkinsWithEnvRule
 import util.JenkinsWriteFileRule
@@ -12,7 +11,6 @@ import static org.hamcrest.Matchers.subString
 import static org.junit.Assert.assertThat
 
 import org.hamcrest.Matchers
-import org.junit.Assert
 import org.junit.After
 import org.junit.Before
 import org.junit.Rule
@@ -142,6 +140,13 @@ class FioriOnCloudPlatformPipelineTest extends BasePiperTest {
                                     ]
                                 ]
 
+        def neoDeployStepName, neoDeployParameters
+        helper.registerAllowedMethod('piperExecuteBin', [Map, String, String, List], {
+            params, stepName, metadata, credentials ->
+                neoDeployStepName = stepName
+                neoDeployParameters = params
+        })
+
         stepRule.step.fioriOnCloudPlatformPipeline(script: nullScript)
 
         //
@@ -151,16 +156,9 @@ class FioriOnCloudPlatformPipelineTest extends BasePiperTest {
         assertThat(nullScript.commonPipelineEnvironment.getMtarFilePath(), is(equalTo('test.mtar')))
 
         //
-        // the neo deploy call:
-        Assert.assertThat(shellRule.shell,
-            new CommandLineMatcher()
-                .hasProlog("neo.sh deploy-mta")
-                .hasSingleQuotedOption('host', 'hana\\.example\\.com')
-                .hasSingleQuotedOption('account', 'myTestAccount')
-                .hasSingleQuotedOption('password', 'terceSpot')
-                .hasSingleQuotedOption('user', 'foo')
-                .hasSingleQuotedOption('source', 'test.mtar')
-                .hasArgument('synchronous'))
+        // the neo deploy call, the deployable is taken from the commonPipelineEnvironment by the go step:
+        assertThat(neoDeployStepName, is(equalTo('neoDeploy')))
+        assertThat(neoDeployParameters.script, is(nullScript))
     }
     
     @Test
@@ -0,0 +1,54 @@
+import org.junit.Rule
+import org.junit.Test
+import org.junit.rules.RuleChain
+
+import util.BasePiperTest
+import util.JenkinsReadYamlRule
+import util.JenkinsStepRule
+import util.Rules
+
+public class NotificationSendTest extends BasePiperTest {
+
+    private JenkinsStepRule stepRule = new JenkinsStepRule(this)
+    private JenkinsReadYamlRule readYamlRule = new JenkinsReadYamlRule(this)
+
+    @Rule
+    public RuleChain ruleChain = Rules
+        .getCommonRules(this)
+        .around(stepRule)
+        .around(readYamlRule)
+
+    @Test
+    void testCallGoWrapper() {
+
+        def calledWithParameters,
+            calledWithStepName,
+            calledWithMetadata,
+            calledWithCredentials
+
+        helper.registerAllowedMethod(
+            'piperExecuteBin',
+            [Map, String, String, List],
+            {
+                params, stepName, metaData, creds ->
+                calledWithParameters = params
+                calledWithStepName = stepName
+                calledWithMetadata = metaData
+                calledWithCredentials = creds
+            }
+        )
+
+        stepRule.step.notificationSend(script: nullScript, buildResult: 'FAILURE')
+
+        assert calledWithParameters.size() == 2
+        assert calledWithParameters.script == nullScript
+        assert calledWithParameters.buildResult == 'FAILURE'
+
+        assert calledWithStepName == 'notificationSend'
+        assert calledWithMetadata == 'metadata/notificationSend.yaml'
+        assert calledWithCredentials.size() == 3
+        assert calledWithCredentials[0] == [type: 'token', id: 'slackCredentialsId', env: ['PIPER_slackWebhookUrl']]
+        assert calledWithCredentials[1] == [type: 'token', id: 'teamsCredentialsId', env: ['PIPER_teamsWebhookUrl']]
+        assert calledWithCredentials[2] == [type: 'usernamePassword', id: 'smtpCredentialsId', env: ['PIPER_smtpUsername', 'PIPER_smtpPassword']]
+    }
+}
@@ -13,6 +13,8 @@ void call(Map parameters = [:]) {
     List credentials = [
         [type: 'ssh', id: 'gitSshKeyCredentialsId'],
         [type: 'usernamePassword', id: 'gitHttpsCredentialsId', env: ['PIPER_username', 'PIPER_password']],
+        [type: 'file', id: 'signingKeyCredentialsId', env: ['PIPER_signingKey']],
+        [type: 'token', id: 'signingKeyPassphraseCredentialsId', env: ['PIPER_signingKeyPassphrase']],
     ]
 
     // Tell dockerExecuteOnKubernetes (if used) to stash also .-folders
@@ -5,7 +5,8 @@ import groovy.transform.Field
 
 void call(Map parameters = [:]) {
     List credentials = [
-        [type: 'token', id: 'githubTokenCredentialsId', env: ['PIPER_token']]
+        [type: 'token', id: 'githubTokenCredentialsId', env: ['PIPER_token']],
+        [type: 'file', id: 'githubAppPrivateKeyCredentialsId', env: ['PIPER_githubAppPrivateKey']]
     ]
     piperExecuteBin(parameters, STEP_NAME, METADATA_FILE, credentials)
 }
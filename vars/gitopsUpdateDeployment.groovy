@@ -6,6 +6,8 @@ import groovy.transform.Field
 void call(Map parameters = [:]) {
     List credentials = [
         [type: 'usernamePassword', id: 'gitHttpsCredentialsId', env: ['PIPER_username', 'PIPER_password']],
+        [type: 'ssh', id: 'gitSshKeyCredentialsId'],
+        [type: 'token', id: 'githubTokenCredentialsId', env: ['PIPER_githubToken']],
     ]
     piperExecuteBin(parameters, STEP_NAME, METADATA_FILE, credentials)
 }
@@ -0,0 +1,13 @@
+import groovy.transform.Field
+
+@Field String STEP_NAME = getClass().getName()
+@Field String METADATA_FILE = 'metadata/notificationSend.yaml'
+
+void call(Map parameters = [:]) {
+    List credentials = [
+        [type: 'token', id: 'slackCredentialsId', env: ['PIPER_slackWebhookUrl']],
+        [type: 'token', id: 'teamsCredentialsId', env: ['PIPER_teamsWebhookUrl']],
+        [type: 'usernamePassword', id: 'smtpCredentialsId', env: ['PIPER_smtpUsername', 'PIPER_smtpPassword']],
+    ]
+    piperExecuteBin(parameters, STEP_NAME, METADATA_FILE, credentials)
+}